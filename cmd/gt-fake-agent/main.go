@@ -0,0 +1,13 @@
+// gt-fake-agent is the "scripted" agent runtime: it replays a TOML script of
+// actions in place of an LLM CLI, for deterministic end-to-end tests.
+package main
+
+import (
+	"os"
+
+	"github.com/steveyegge/gastown/internal/fakeagent"
+)
+
+func main() {
+	os.Exit(fakeagent.Main(os.Args[1:]))
+}
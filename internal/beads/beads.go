@@ -127,6 +127,7 @@ type ListOptions struct {
 	Status     string // "open", "closed", "all"
 	Type       string // Deprecated: use Label instead. "task", "bug", "feature", "epic"
 	Label      string // Label filter (e.g., "gt:agent", "gt:merge-request")
+	IssueType  string // bd's own issue type (e.g., "convoy"); not mapped to a label
 	Priority   int    // 0-4, -1 for no filter
 	Parent     string // filter by parent ID
 	Assignee   string // filter by assignee (e.g., "gastown/Toast")
@@ -141,8 +142,9 @@ type CreateOptions struct {
 	Priority    int    // 0-4
 	Description string
 	Parent      string
-	Actor       string // Who is creating this issue (populates created_by)
-	Ephemeral   bool   // Create as ephemeral (wisp) - not exported to JSONL
+	Actor       string   // Who is creating this issue (populates created_by)
+	Ephemeral   bool     // Create as ephemeral (wisp) - not exported to JSONL
+	Labels      []string // Labels to add at creation, after gt:<type>
 }
 
 // UpdateOptions specifies options for updating an issue.
@@ -395,6 +397,9 @@ func (b *Beads) List(opts ListOptions) ([]*Issue, error) {
 		// Deprecated: convert type to label for backward compatibility
 		args = append(args, "--label=gt:"+opts.Type)
 	}
+	if opts.IssueType != "" {
+		args = append(args, "--type="+opts.IssueType)
+	}
 	if opts.Priority >= 0 {
 		args = append(args, fmt.Sprintf("--priority=%d", opts.Priority))
 	}
@@ -530,13 +535,44 @@ func (b *Beads) ReadyWithType(issueType string) ([]*Issue, error) {
 	return issues, nil
 }
 
+// routed returns the wrapper for the database that owns id, following
+// routes.jsonl so that a rig-level ID (e.g., "gt-abc123") or a town-level
+// one ("hq-abc") reaches its own database. run pins BEADS_DIR, which turns
+// off bd's own prefix routing, so every single-issue operation goes through
+// here. Returns b when id belongs to b's database.
+func (b *Beads) routed(id string) *Beads {
+	local := b.getResolvedBeadsDir()
+	targetDir := ResolveRoutingTarget(b.getTownRoot(), id, local)
+	if targetDir == local {
+		return b
+	}
+	return NewWithBeadsDir(filepath.Dir(targetDir), targetDir)
+}
+
+// routedGroups splits ids by the database that owns them, keeping the
+// order of first appearance.
+func (b *Beads) routedGroups(ids []string) ([]*Beads, [][]string) {
+	var targets []*Beads
+	var groups [][]string
+	index := make(map[string]int)
+	for _, id := range ids {
+		target := b.routed(id)
+		key := target.getResolvedBeadsDir()
+		i, ok := index[key]
+		if !ok {
+			i = len(targets)
+			index[key] = i
+			targets = append(targets, target)
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], id)
+	}
+	return targets, groups
+}
+
 // Show returns detailed information about an issue.
 func (b *Beads) Show(id string) (*Issue, error) {
-	// Route cross-rig queries via routes.jsonl so that rig-level bead IDs
-	// (e.g., "gt-abc123") resolve to the correct rig database.
-	targetDir := ResolveRoutingTarget(b.getTownRoot(), id, b.getResolvedBeadsDir())
-	if targetDir != b.getResolvedBeadsDir() {
-		target := NewWithBeadsDir(filepath.Dir(targetDir), targetDir)
+	if target := b.routed(id); target != b {
 		return target.Show(id)
 	}
 
@@ -615,8 +651,13 @@ func (b *Beads) Create(opts CreateOptions) (*Issue, error) {
 		args = append(args, "--title="+opts.Title)
 	}
 	// Type is deprecated: convert to gt:<type> label
+	var labels []string
 	if opts.Type != "" {
-		args = append(args, "--labels=gt:"+opts.Type)
+		labels = append(labels, "gt:"+opts.Type)
+	}
+	labels = append(labels, opts.Labels...)
+	if len(labels) > 0 {
+		args = append(args, "--labels="+strings.Join(labels, ","))
 	}
 	if opts.Priority >= 0 {
 		args = append(args, fmt.Sprintf("--priority=%d", opts.Priority))
@@ -671,8 +712,13 @@ func (b *Beads) CreateWithID(id string, opts CreateOptions) (*Issue, error) {
 		args = append(args, "--title="+opts.Title)
 	}
 	// Type is deprecated: convert to gt:<type> label
+	var labels []string
 	if opts.Type != "" {
-		args = append(args, "--labels=gt:"+opts.Type)
+		labels = append(labels, "gt:"+opts.Type)
+	}
+	labels = append(labels, opts.Labels...)
+	if len(labels) > 0 {
+		args = append(args, "--labels="+strings.Join(labels, ","))
 	}
 	if opts.Priority >= 0 {
 		args = append(args, fmt.Sprintf("--priority=%d", opts.Priority))
@@ -706,8 +752,12 @@ func (b *Beads) CreateWithID(id string, opts CreateOptions) (*Issue, error) {
 	return &issue, nil
 }
 
-// Update updates an existing issue.
+// Update updates an existing issue in the database that owns it.
 func (b *Beads) Update(id string, opts UpdateOptions) error {
+	if target := b.routed(id); target != b {
+		return target.Update(id, opts)
+	}
+
 	args := []string{"update", id}
 
 	if opts.Title != nil {
@@ -743,63 +793,48 @@ func (b *Beads) Update(id string, opts UpdateOptions) error {
 	return err
 }
 
-// Close closes one or more issues.
+// Close closes one or more issues, each in the database that owns it.
 // If a runtime session ID is set in the environment, it is passed to bd close
 // for work attribution tracking (see decision 009-session-events-architecture.md).
 func (b *Beads) Close(ids ...string) error {
-	if len(ids) == 0 {
-		return nil
-	}
-
-	args := append([]string{"close"}, ids...)
-
-	// Pass session ID for work attribution if available
-	if sessionID := runtime.SessionIDFromEnv(); sessionID != "" {
-		args = append(args, "--session="+sessionID)
-	}
-
-	_, err := b.run(args...)
-	return err
+	return b.close(ids)
 }
 
 // CloseWithReason closes one or more issues with a reason.
 // If a runtime session ID is set in the environment, it is passed to bd close
 // for work attribution tracking (see decision 009-session-events-architecture.md).
 func (b *Beads) CloseWithReason(reason string, ids ...string) error {
-	if len(ids) == 0 {
-		return nil
-	}
-
-	args := append([]string{"close"}, ids...)
-	args = append(args, "--reason="+reason)
-
-	// Pass session ID for work attribution if available
-	if sessionID := runtime.SessionIDFromEnv(); sessionID != "" {
-		args = append(args, "--session="+sessionID)
-	}
-
-	_, err := b.run(args...)
-	return err
+	return b.close(ids, "--reason="+reason)
 }
 
 // ForceCloseWithReason closes one or more issues with --force, bypassing
 // dependency checks. Used by gt done where the polecat is about to be nuked
 // and open molecule wisps should not block issue closure.
 func (b *Beads) ForceCloseWithReason(reason string, ids ...string) error {
+	return b.close(ids, "--reason="+reason, "--force")
+}
+
+// close runs bd close once per database owning some of ids.
+func (b *Beads) close(ids []string, flags ...string) error {
 	if len(ids) == 0 {
 		return nil
 	}
 
-	args := append([]string{"close"}, ids...)
-	args = append(args, "--reason="+reason, "--force")
-
 	// Pass session ID for work attribution if available
 	if sessionID := runtime.SessionIDFromEnv(); sessionID != "" {
-		args = append(args, "--session="+sessionID)
+		flags = append(flags, "--session="+sessionID)
 	}
 
-	_, err := b.run(args...)
-	return err
+	targets, groups := b.routedGroups(ids)
+	var errs []error
+	for i, target := range targets {
+		args := append([]string{"close"}, groups[i]...)
+		args = append(args, flags...)
+		if _, err := target.run(args...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
 }
 
 // Release moves an in_progress issue back to open status.
@@ -812,6 +847,10 @@ func (b *Beads) Release(id string) error {
 // ReleaseWithReason moves an in_progress issue back to open status with a reason.
 // The reason is added as a note to the issue for tracking purposes.
 func (b *Beads) ReleaseWithReason(id, reason string) error {
+	if target := b.routed(id); target != b {
+		return target.ReleaseWithReason(id, reason)
+	}
+
 	args := []string{"update", id, "--status=open", "--assignee="}
 
 	// Add reason as a note if provided
@@ -835,6 +874,42 @@ func (b *Beads) RemoveDependency(issue, dependsOn string) error {
 	return err
 }
 
+// Track records that convoyID tracks issueID. Tracking does not block.
+func (b *Beads) Track(convoyID, issueID string) error {
+	_, err := b.run("dep", "add", convoyID, issueID, "--type=tracks")
+	return err
+}
+
+// TrackedIssues returns the issues a convoy tracks. Statuses are read from
+// each issue's own database, since the convoy's copy of a cross-rig issue
+// goes stale. An issue that can't be looked up is returned with an empty
+// Status.
+func (b *Beads) TrackedIssues(convoyID string) ([]*Issue, error) {
+	out, err := b.run("dep", "list", convoyID, "--direction=down", "--type=tracks", "--json")
+	if err != nil {
+		return nil, err
+	}
+	var deps []IssueDep
+	if err := json.Unmarshal(out, &deps); err != nil {
+		return nil, fmt.Errorf("parsing bd dep list output: %w", err)
+	}
+
+	tracked := make([]*Issue, 0, len(deps))
+	for _, dep := range deps {
+		id := dep.ID
+		// Cross-rig references are stored as external:<prefix>:<id>.
+		if parts := strings.SplitN(id, ":", 3); len(parts) == 3 && parts[0] == "external" {
+			id = parts[2]
+		}
+		issue, err := b.Show(id)
+		if err != nil {
+			issue = &Issue{ID: id, Title: dep.Title}
+		}
+		tracked = append(tracked, issue)
+	}
+	return tracked, nil
+}
+
 // Sync syncs beads with remote.
 func (b *Beads) Sync() error {
 	_, err := b.run("sync")
@@ -472,12 +472,8 @@ type AgentFieldUpdates struct {
 // condition where concurrent callers updating different fields overwrite each
 // other because the entire description is replaced.
 func (b *Beads) UpdateAgentDescriptionFields(id string, updates AgentFieldUpdates) error {
-	// Validate notification level if provided
-	if updates.NotificationLevel != nil {
-		level := *updates.NotificationLevel
-		if level != "" && level != NotifyVerbose && level != NotifyNormal && level != NotifyMuted {
-			return fmt.Errorf("invalid notification level %q: must be verbose, normal, or muted", level)
-		}
+	if err := validateAgentFieldUpdates(updates); err != nil {
+		return err
 	}
 
 	// Lock the agent bead to prevent concurrent read-modify-write races.
@@ -494,6 +490,22 @@ func (b *Beads) UpdateAgentDescriptionFields(id string, updates AgentFieldUpdate
 		return err
 	}
 
+	description := applyAgentFieldUpdates(issue, updates)
+	return b.Update(id, UpdateOptions{Description: &description})
+}
+
+func validateAgentFieldUpdates(updates AgentFieldUpdates) error {
+	if updates.NotificationLevel != nil {
+		level := *updates.NotificationLevel
+		if level != "" && level != NotifyVerbose && level != NotifyNormal && level != NotifyMuted {
+			return fmt.Errorf("invalid notification level %q: must be verbose, normal, or muted", level)
+		}
+	}
+	return nil
+}
+
+// applyAgentFieldUpdates returns issue's description with updates applied.
+func applyAgentFieldUpdates(issue *Issue, updates AgentFieldUpdates) string {
 	fields := ParseAgentFields(issue.Description)
 
 	if updates.CleanupStatus != nil {
@@ -509,8 +521,7 @@ func (b *Beads) UpdateAgentDescriptionFields(id string, updates AgentFieldUpdate
 		fields.Mode = *updates.Mode
 	}
 
-	description := FormatAgentDescription(issue.Title, fields)
-	return b.Update(id, UpdateOptions{Description: &description})
+	return FormatAgentDescription(issue.Title, fields)
 }
 
 // UpdateAgentCleanupStatus updates the cleanup_status field in an agent bead.
@@ -13,23 +13,23 @@ import (
 // EscalationFields holds structured fields for escalation beads.
 // These are stored as "key: value" lines in the description.
 type EscalationFields struct {
-	Severity           string // critical, high, medium, low
-	Reason             string // Why this was escalated
-	Source             string // Source identifier (e.g., plugin:rebuild-gt, patrol:deacon)
-	EscalatedBy        string // Agent address that escalated (e.g., "gastown/Toast")
-	EscalatedAt        string // ISO 8601 timestamp
-	AckedBy            string // Agent that acknowledged (empty if not acked)
-	AckedAt            string // When acknowledged (empty if not acked)
-	ClosedBy           string // Agent that closed (empty if not closed)
-	ClosedReason       string // Resolution reason (empty if not closed)
-	RelatedBead        string // Optional: related bead ID (task, bug, etc.)
-	OriginalSeverity   string // Original severity before any re-escalation
-	ReescalationCount  int    // Number of times this has been re-escalated
-	LastReescalatedAt  string // When last re-escalated (empty if never)
-	LastReescalatedBy  string // Who last re-escalated (empty if never)
+	Severity          string   // critical, high, medium, low
+	Reason            string   // Why this was escalated
+	Source            string   // Source identifier (e.g., plugin:rebuild-gt, patrol:deacon)
+	EscalatedBy       string   // Agent address that escalated (e.g., "gastown/Toast")
+	EscalatedAt       string   // ISO 8601 timestamp
+	AckedBy           string   // Agent that acknowledged (empty if not acked)
+	AckedAt           string   // When acknowledged (empty if not acked)
+	ClosedBy          string   // Agent that closed (empty if not closed)
+	ClosedReason      string   // Resolution reason (empty if not closed)
+	RelatedBead       string   // Optional: related bead ID (task, bug, etc.)
+	OriginalSeverity  string   // Original severity before any re-escalation
+	ReescalationCount int      // Number of times this has been re-escalated
+	LastReescalatedAt string   // When last re-escalated (empty if never)
+	LastReescalatedBy string   // Who last re-escalated (empty if never)
+	Deliveries        []string // External delivery outcomes, one "delivery:" line each
 }
 
-
 // FormatEscalationDescription creates a description string from escalation fields.
 func FormatEscalationDescription(title string, fields *EscalationFields) string {
 	if fields == nil {
@@ -97,6 +97,10 @@ func FormatEscalationDescription(title string, fields *EscalationFields) string
 		lines = append(lines, "last_reescalated_by: null")
 	}
 
+	for _, d := range fields.Deliveries {
+		lines = append(lines, fmt.Sprintf("delivery: %s", d))
+	}
+
 	return strings.Join(lines, "\n")
 }
 
@@ -152,6 +156,10 @@ func ParseEscalationFields(description string) *EscalationFields {
 			fields.LastReescalatedAt = value
 		case "last_reescalated_by":
 			fields.LastReescalatedBy = value
+		case "delivery":
+			if value != "" {
+				fields.Deliveries = append(fields.Deliveries, value)
+			}
 		}
 	}
 
@@ -227,6 +235,33 @@ func (b *Beads) AckEscalation(id, ackedBy string) error {
 	})
 }
 
+// RecordEscalationDeliveries appends external delivery outcomes (email, sms,
+// slack) to an escalation bead. If failed is true the bead also gets the
+// "delivery-failed" label so undelivered escalations can be found with bd list.
+func (b *Beads) RecordEscalationDeliveries(id string, entries []string, failed bool) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	issue, err := b.Show(id)
+	if err != nil {
+		return err
+	}
+	if !HasLabel(issue, "gt:escalation") {
+		return fmt.Errorf("issue %s is not an escalation bead (missing gt:escalation label)", id)
+	}
+
+	fields := ParseEscalationFields(issue.Description)
+	fields.Deliveries = append(fields.Deliveries, entries...)
+	description := FormatEscalationDescription(issue.Title, fields)
+
+	opts := UpdateOptions{Description: &description}
+	if failed {
+		opts.AddLabels = []string{"delivery-failed"}
+	}
+	return b.Update(id, opts)
+}
+
 // CloseEscalation closes an escalation bead with a resolution reason.
 // Sets closed_by and closed_reason fields, closes the issue.
 func (b *Beads) CloseEscalation(id, closedBy, reason string) error {
@@ -322,3 +322,25 @@ func TestBumpSeverity(t *testing.T) {
 		})
 	}
 }
+
+func TestEscalationFieldsRoundTrip_Deliveries(t *testing.T) {
+	original := &EscalationFields{
+		Severity:    "critical",
+		EscalatedBy: "gastown/witness",
+		Deliveries: []string{
+			"email:human sent attempts=1 at=2024-06-15T12:00:01Z",
+			"slack failed attempts=3 at=2024-06-15T12:00:09Z error=webhook returned 500: upstream: down",
+		},
+	}
+
+	parsed := ParseEscalationFields(FormatEscalationDescription("Escalation: disk full", original))
+
+	if len(parsed.Deliveries) != len(original.Deliveries) {
+		t.Fatalf("Deliveries: got %d entries, want %d", len(parsed.Deliveries), len(original.Deliveries))
+	}
+	for i := range original.Deliveries {
+		if parsed.Deliveries[i] != original.Deliveries[i] {
+			t.Errorf("Deliveries[%d]: got %q, want %q", i, parsed.Deliveries[i], original.Deliveries[i])
+		}
+	}
+}
@@ -468,6 +468,21 @@ worker: Toast`,
 			want: `merge_commit: deadbeef
 close_reason: rejected`,
 		},
+		{
+			name: "pull request fields",
+			fields: &MRFields{
+				Branch:         "polecat/Nux/gt-xyz",
+				MergeStrategy:  "pr",
+				PRNumber:       42,
+				PRURL:          "https://github.com/acme/widgets/pull/42",
+				PRReviewSeenAt: "2026-01-02T15:04:05Z",
+			},
+			want: `branch: polecat/Nux/gt-xyz
+merge_strategy: pr
+pr_number: 42
+pr_url: https://github.com/acme/widgets/pull/42
+pr_review_seen_at: 2026-01-02T15:04:05Z`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -25,7 +25,7 @@ type AttachmentFields struct {
 	NoMerge          bool   // If true, gt done skips merge queue (for upstream PRs/human review)
 	Mode             string // Execution mode: "" (normal) or "ralph" (Ralph Wiggum loop)
 	ConvoyID         string // Convoy bead ID tracking this issue (e.g., "hq-cv-abc")
-	MergeStrategy    string // Convoy merge strategy: "direct", "mr", "local", or "" (default = mr)
+	MergeStrategy    string // Convoy merge strategy: "direct", "mr", "local", "pr", or "" (default = mr)
 	ConvoyOwned      bool   // If true, convoy has gt:owned label (caller-managed lifecycle)
 }
 
@@ -237,6 +237,13 @@ type MRFields struct {
 	// Convoy tracking (for priority scoring - convoy starvation prevention)
 	ConvoyID        string // Parent convoy ID if part of a convoy
 	ConvoyCreatedAt string // Convoy creation time (ISO 8601) for starvation prevention
+
+	// Pull request tracking (merge_strategy "pr": the refinery lands the MR
+	// through a forge pull request instead of merging locally)
+	MergeStrategy  string // "pr", or empty for a local refinery merge
+	PRNumber       int    // Pull request number on the forge (0 = not opened yet)
+	PRURL          string // Pull request web URL
+	PRReviewSeenAt string // Newest review already turned into rework (RFC 3339)
 }
 
 // ParseMRFields extracts structured merge-request fields from an issue's description.
@@ -311,6 +318,20 @@ func ParseMRFields(issue *Issue) *MRFields {
 		case "convoy_created_at", "convoy-created-at", "convoycreatedat":
 			fields.ConvoyCreatedAt = value
 			hasFields = true
+		case "merge_strategy", "merge-strategy", "mergestrategy":
+			fields.MergeStrategy = value
+			hasFields = true
+		case "pr_number", "pr-number", "prnumber":
+			if n, err := parseIntField(value); err == nil {
+				fields.PRNumber = n
+				hasFields = true
+			}
+		case "pr_url", "pr-url", "prurl":
+			fields.PRURL = value
+			hasFields = true
+		case "pr_review_seen_at", "pr-review-seen-at", "prreviewseenat":
+			fields.PRReviewSeenAt = value
+			hasFields = true
 		}
 	}
 
@@ -375,6 +396,18 @@ func FormatMRFields(fields *MRFields) string {
 	if fields.ConvoyCreatedAt != "" {
 		lines = append(lines, "convoy_created_at: "+fields.ConvoyCreatedAt)
 	}
+	if fields.MergeStrategy != "" {
+		lines = append(lines, "merge_strategy: "+fields.MergeStrategy)
+	}
+	if fields.PRNumber > 0 {
+		lines = append(lines, fmt.Sprintf("pr_number: %d", fields.PRNumber))
+	}
+	if fields.PRURL != "" {
+		lines = append(lines, "pr_url: "+fields.PRURL)
+	}
+	if fields.PRReviewSeenAt != "" {
+		lines = append(lines, "pr_review_seen_at: "+fields.PRReviewSeenAt)
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -421,6 +454,18 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 		"convoy_created_at":  true,
 		"convoy-created-at":  true,
 		"convoycreatedat":    true,
+		"merge_strategy":     true,
+		"merge-strategy":     true,
+		"mergestrategy":      true,
+		"pr_number":          true,
+		"pr-number":          true,
+		"prnumber":           true,
+		"pr_url":             true,
+		"pr-url":             true,
+		"prurl":              true,
+		"pr_review_seen_at":  true,
+		"pr-review-seen-at":  true,
+		"prreviewseenat":     true,
 	}
 
 	// Collect non-MR lines from existing description
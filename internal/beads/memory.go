@@ -0,0 +1,617 @@
+package beads
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-memory Store for tests. It keeps bd's semantics for
+// what callers observe: dependencies block until the blocker closes, ready
+// queries skip blocked and ephemeral issues, types become gt:<type> labels,
+// and IDs route across prefixes (see Routed).
+//
+// Show and List fill the computed fields bd reports: DependsOn, BlockedBy
+// (open blockers only), Blocks, Children and the matching counts. Show also
+// fills Dependencies and Dependents.
+type MemoryStore struct {
+	db     *memoryDB
+	prefix string // e.g. "gt-"
+}
+
+// memoryDB holds the issues of every routed MemoryStore in a town.
+type memoryDB struct {
+	mu     sync.Mutex
+	issues map[string]*memoryIssue
+	order  []string       // IDs in creation order
+	seq    map[string]int // last generated ID number per prefix
+	now    func() time.Time
+}
+
+type memoryIssue struct {
+	issue  Issue    // Stored fields; computed fields are filled on read
+	owner  string   // Prefix of the store the issue was created in
+	deps   []string // IDs this issue depends on
+	tracks []string // IDs this convoy tracks (may be unknown IDs)
+}
+
+// NewMemoryStore returns an empty in-memory store that creates IDs with the
+// given prefix ("gt" or "gt-").
+func NewMemoryStore(prefix string) *MemoryStore {
+	return &MemoryStore{
+		db: &memoryDB{
+			issues: make(map[string]*memoryIssue),
+			seq:    make(map[string]int),
+			now:    time.Now,
+		},
+		prefix: normalizeMemoryPrefix(prefix),
+	}
+}
+
+// Routed returns the store for another prefix in the same town, the way
+// routes.jsonl maps prefixes to rig databases. Show, Update, Close and
+// dependencies resolve any ID across all routed stores; List, Ready and
+// Blocked only see issues created in their own store.
+func (m *MemoryStore) Routed(prefix string) *MemoryStore {
+	return &MemoryStore{db: m.db, prefix: normalizeMemoryPrefix(prefix)}
+}
+
+// Prefix returns the ID prefix of the store, including the trailing dash.
+func (m *MemoryStore) Prefix() string {
+	return m.prefix
+}
+
+// SetClock sets the time source for created/updated/closed timestamps.
+// It applies to all routed stores.
+func (m *MemoryStore) SetClock(now func() time.Time) {
+	m.db.mu.Lock()
+	defer m.db.mu.Unlock()
+	m.db.now = now
+}
+
+func normalizeMemoryPrefix(prefix string) string {
+	return strings.TrimSuffix(prefix, "-") + "-"
+}
+
+// List returns issues matching the given options, in creation order.
+func (m *MemoryStore) List(opts ListOptions) ([]*Issue, error) {
+	m.db.mu.Lock()
+	defer m.db.mu.Unlock()
+
+	label := opts.Label
+	if label == "" && opts.Type != "" {
+		label = "gt:" + opts.Type
+	}
+	// Types are stored as gt:<type> labels, so bd's own types match those.
+	typeLabel := ""
+	if opts.IssueType != "" {
+		typeLabel = "gt:" + opts.IssueType
+	}
+
+	var issues []*Issue
+	for _, id := range m.db.order {
+		mi := m.db.issues[id]
+		if mi.owner != m.prefix || !matchesStatus(mi.issue.Status, opts.Status) {
+			continue
+		}
+		if label != "" && !hasAllLabels(&mi.issue, label) {
+			continue
+		}
+		if typeLabel != "" && !HasLabel(&mi.issue, typeLabel) {
+			continue
+		}
+		if opts.Priority >= 0 && mi.issue.Priority != opts.Priority {
+			continue
+		}
+		if opts.Parent != "" && mi.issue.Parent != opts.Parent {
+			continue
+		}
+		if opts.Assignee != "" && mi.issue.Assignee != opts.Assignee {
+			continue
+		}
+		if opts.NoAssignee && mi.issue.Assignee != "" {
+			continue
+		}
+		issues = append(issues, m.db.view(mi, false))
+		if opts.Limit > 0 && len(issues) == opts.Limit {
+			break
+		}
+	}
+	return issues, nil
+}
+
+// hasAllLabels reports whether issue has every label in a comma-separated
+// filter, as bd list --label does.
+func hasAllLabels(issue *Issue, filter string) bool {
+	for _, label := range strings.Split(filter, ",") {
+		if !HasLabel(issue, strings.TrimSpace(label)) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesStatus(status, filter string) bool {
+	switch filter {
+	case "":
+		return status != "closed"
+	case "all":
+		return true
+	default:
+		return status == filter
+	}
+}
+
+// Show returns an issue with its dependencies and dependents.
+func (m *MemoryStore) Show(id string) (*Issue, error) {
+	m.db.mu.Lock()
+	defer m.db.mu.Unlock()
+
+	mi, ok := m.db.issues[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return m.db.view(mi, true), nil
+}
+
+// ShowMultiple returns the issues that exist among ids, keyed by ID.
+func (m *MemoryStore) ShowMultiple(ids []string) (map[string]*Issue, error) {
+	m.db.mu.Lock()
+	defer m.db.mu.Unlock()
+
+	result := make(map[string]*Issue, len(ids))
+	for _, id := range ids {
+		if mi, ok := m.db.issues[id]; ok {
+			result[id] = m.db.view(mi, true)
+		}
+	}
+	return result, nil
+}
+
+// Ready returns open, non-ephemeral issues with no open blockers,
+// highest priority first.
+func (m *MemoryStore) Ready() ([]*Issue, error) {
+	m.db.mu.Lock()
+	defer m.db.mu.Unlock()
+
+	return m.db.ready(func(mi *memoryIssue) bool {
+		return mi.owner == m.prefix && !mi.issue.Ephemeral
+	}), nil
+}
+
+// ReadyForMol returns the ready steps under a molecule: its open descendants
+// with no open blockers. Ephemeral steps are included.
+func (m *MemoryStore) ReadyForMol(moleculeID string) ([]*Issue, error) {
+	m.db.mu.Lock()
+	defer m.db.mu.Unlock()
+
+	if _, ok := m.db.issues[moleculeID]; !ok {
+		return nil, ErrNotFound
+	}
+	return m.db.ready(func(mi *memoryIssue) bool {
+		return m.db.isDescendant(mi, moleculeID)
+	}), nil
+}
+
+// ReadyWithType returns ready issues labeled gt:<issueType>.
+func (m *MemoryStore) ReadyWithType(issueType string) ([]*Issue, error) {
+	m.db.mu.Lock()
+	defer m.db.mu.Unlock()
+
+	label := "gt:" + issueType
+	return m.db.ready(func(mi *memoryIssue) bool {
+		return mi.owner == m.prefix && !mi.issue.Ephemeral && HasLabel(&mi.issue, label)
+	}), nil
+}
+
+// Blocked returns non-closed issues with at least one open blocker.
+func (m *MemoryStore) Blocked() ([]*Issue, error) {
+	m.db.mu.Lock()
+	defer m.db.mu.Unlock()
+
+	var issues []*Issue
+	for _, id := range m.db.order {
+		mi := m.db.issues[id]
+		if mi.owner == m.prefix && mi.issue.Status != "closed" && len(m.db.openBlockers(mi)) > 0 {
+			issues = append(issues, m.db.view(mi, false))
+		}
+	}
+	return issues, nil
+}
+
+// Create creates a new issue with the next ID for the store's prefix.
+func (m *MemoryStore) Create(opts CreateOptions) (*Issue, error) {
+	m.db.mu.Lock()
+	defer m.db.mu.Unlock()
+
+	var id string
+	for id == "" || m.db.issues[id] != nil {
+		m.db.seq[m.prefix]++
+		id = m.prefix + strconv.Itoa(m.db.seq[m.prefix])
+	}
+	return m.db.create(id, m.prefix, opts)
+}
+
+// CreateWithID creates an issue with a specific ID.
+func (m *MemoryStore) CreateWithID(id string, opts CreateOptions) (*Issue, error) {
+	m.db.mu.Lock()
+	defer m.db.mu.Unlock()
+
+	if _, exists := m.db.issues[id]; exists {
+		return nil, fmt.Errorf("creating %s: issue already exists", id)
+	}
+	return m.db.create(id, m.prefix, opts)
+}
+
+// Update updates an existing issue.
+func (m *MemoryStore) Update(id string, opts UpdateOptions) error {
+	m.db.mu.Lock()
+	defer m.db.mu.Unlock()
+
+	mi, ok := m.db.issues[id]
+	if !ok {
+		return ErrNotFound
+	}
+	issue := &mi.issue
+	if opts.Title != nil {
+		issue.Title = *opts.Title
+	}
+	if opts.Status != nil {
+		m.db.setStatus(issue, *opts.Status)
+	}
+	if opts.Priority != nil {
+		issue.Priority = *opts.Priority
+	}
+	if opts.Description != nil {
+		issue.Description = *opts.Description
+	}
+	if opts.Assignee != nil {
+		issue.Assignee = *opts.Assignee
+	}
+	if len(opts.SetLabels) > 0 {
+		issue.Labels = nil
+		for _, label := range opts.SetLabels {
+			issue.Labels = addLabel(issue.Labels, label)
+		}
+	} else {
+		for _, label := range opts.AddLabels {
+			issue.Labels = addLabel(issue.Labels, label)
+		}
+		for _, label := range opts.RemoveLabels {
+			issue.Labels = removeLabel(issue.Labels, label)
+		}
+	}
+	issue.UpdatedAt = m.db.timestamp()
+	return nil
+}
+
+// Close closes one or more issues. Nothing is closed if any ID is unknown.
+func (m *MemoryStore) Close(ids ...string) error {
+	return m.CloseWithReason("", ids...)
+}
+
+// CloseWithReason closes one or more issues. The reason is accepted for
+// interface compatibility; bd records it outside the Issue fields.
+func (m *MemoryStore) CloseWithReason(reason string, ids ...string) error {
+	m.db.mu.Lock()
+	defer m.db.mu.Unlock()
+
+	for _, id := range ids {
+		if _, ok := m.db.issues[id]; !ok {
+			return fmt.Errorf("closing %s: %w", id, ErrNotFound)
+		}
+	}
+	for _, id := range ids {
+		issue := &m.db.issues[id].issue
+		m.db.setStatus(issue, "closed")
+		issue.UpdatedAt = issue.ClosedAt
+	}
+	return nil
+}
+
+// AddDependency adds a dependency: issue depends on dependsOn.
+// Adding an existing dependency is a no-op; cycles are rejected.
+func (m *MemoryStore) AddDependency(issue, dependsOn string) error {
+	m.db.mu.Lock()
+	defer m.db.mu.Unlock()
+
+	mi, ok := m.db.issues[issue]
+	if !ok {
+		return fmt.Errorf("adding dependency to %s: %w", issue, ErrNotFound)
+	}
+	if _, ok := m.db.issues[dependsOn]; !ok {
+		return fmt.Errorf("adding dependency on %s: %w", dependsOn, ErrNotFound)
+	}
+	if issue == dependsOn || m.db.dependsOn(dependsOn, issue) {
+		return fmt.Errorf("adding dependency %s → %s would create a cycle", issue, dependsOn)
+	}
+	for _, dep := range mi.deps {
+		if dep == dependsOn {
+			return nil
+		}
+	}
+	mi.deps = append(mi.deps, dependsOn)
+	return nil
+}
+
+// RemoveDependency removes a dependency.
+func (m *MemoryStore) RemoveDependency(issue, dependsOn string) error {
+	m.db.mu.Lock()
+	defer m.db.mu.Unlock()
+
+	mi, ok := m.db.issues[issue]
+	if !ok {
+		return fmt.Errorf("removing dependency from %s: %w", issue, ErrNotFound)
+	}
+	for i, dep := range mi.deps {
+		if dep == dependsOn {
+			mi.deps = append(mi.deps[:i], mi.deps[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// Track records that convoyID tracks issueID. The tracked ID need not exist,
+// as with bd's external references.
+func (m *MemoryStore) Track(convoyID, issueID string) error {
+	m.db.mu.Lock()
+	defer m.db.mu.Unlock()
+
+	mi, ok := m.db.issues[convoyID]
+	if !ok {
+		return fmt.Errorf("tracking from %s: %w", convoyID, ErrNotFound)
+	}
+	for _, id := range mi.tracks {
+		if id == issueID {
+			return nil
+		}
+	}
+	mi.tracks = append(mi.tracks, issueID)
+	return nil
+}
+
+// TrackedIssues returns the issues convoyID tracks, in the order they were
+// tracked.
+func (m *MemoryStore) TrackedIssues(convoyID string) ([]*Issue, error) {
+	m.db.mu.Lock()
+	defer m.db.mu.Unlock()
+
+	mi, ok := m.db.issues[convoyID]
+	if !ok {
+		return nil, fmt.Errorf("listing tracked issues of %s: %w", convoyID, ErrNotFound)
+	}
+	tracked := make([]*Issue, 0, len(mi.tracks))
+	for _, id := range mi.tracks {
+		if other, ok := m.db.issues[id]; ok {
+			tracked = append(tracked, m.db.view(other, false))
+		} else {
+			tracked = append(tracked, &Issue{ID: id})
+		}
+	}
+	return tracked, nil
+}
+
+// UpdateAgentDescriptionFields updates agent description fields like
+// (*Beads).UpdateAgentDescriptionFields, in a single locked step.
+func (m *MemoryStore) UpdateAgentDescriptionFields(id string, updates AgentFieldUpdates) error {
+	if err := validateAgentFieldUpdates(updates); err != nil {
+		return err
+	}
+
+	m.db.mu.Lock()
+	defer m.db.mu.Unlock()
+
+	mi, ok := m.db.issues[id]
+	if !ok {
+		return ErrNotFound
+	}
+	mi.issue.Description = applyAgentFieldUpdates(&mi.issue, updates)
+	mi.issue.UpdatedAt = m.db.timestamp()
+	return nil
+}
+
+// UpdateAgentActiveMR updates the active_mr field in an agent bead.
+func (m *MemoryStore) UpdateAgentActiveMR(id string, activeMR string) error {
+	return m.UpdateAgentDescriptionFields(id, AgentFieldUpdates{ActiveMR: &activeMR})
+}
+
+// The methods below require db.mu to be held.
+
+func (db *memoryDB) timestamp() string {
+	return db.now().UTC().Format(time.RFC3339)
+}
+
+func (db *memoryDB) create(id, owner string, opts CreateOptions) (*Issue, error) {
+	// Guard against flag-like titles (gt-e0kx5: --help garbage beads)
+	if IsFlagLikeTitle(opts.Title) {
+		return nil, fmt.Errorf("refusing to create bead: %w (got %q)", ErrFlagTitle, opts.Title)
+	}
+	if opts.Title == "" {
+		return nil, fmt.Errorf("creating %s: title is required", id)
+	}
+	if opts.Parent != "" {
+		if _, ok := db.issues[opts.Parent]; !ok {
+			return nil, fmt.Errorf("creating %s: parent %s: %w", id, opts.Parent, ErrNotFound)
+		}
+	}
+
+	priority := opts.Priority
+	if priority < 0 {
+		priority = 2 // bd's default
+	}
+	now := db.timestamp()
+	mi := &memoryIssue{
+		owner: owner,
+		issue: Issue{
+			ID:          id,
+			Title:       opts.Title,
+			Description: opts.Description,
+			Status:      "open",
+			Priority:    priority,
+			Type:        "task",
+			CreatedAt:   now,
+			CreatedBy:   opts.Actor,
+			UpdatedAt:   now,
+			Parent:      opts.Parent,
+			Ephemeral:   opts.Ephemeral,
+		},
+	}
+	if opts.Type != "" {
+		mi.issue.Labels = []string{"gt:" + opts.Type}
+	}
+	for _, label := range opts.Labels {
+		mi.issue.Labels = addLabel(mi.issue.Labels, label)
+	}
+	db.issues[id] = mi
+	db.order = append(db.order, id)
+	return db.view(mi, true), nil
+}
+
+func (db *memoryDB) setStatus(issue *Issue, status string) {
+	if status == "closed" && issue.Status != "closed" {
+		issue.ClosedAt = db.timestamp()
+	} else if status != "closed" {
+		issue.ClosedAt = ""
+	}
+	issue.Status = status
+}
+
+// openBlockers returns the IDs of mi's dependencies that are not closed.
+// Dependencies on unknown IDs do not block.
+func (db *memoryDB) openBlockers(mi *memoryIssue) []string {
+	var open []string
+	for _, dep := range mi.deps {
+		if blocker, ok := db.issues[dep]; ok && blocker.issue.Status != "closed" {
+			open = append(open, dep)
+		}
+	}
+	return open
+}
+
+// dependsOn reports whether from depends on to, directly or transitively.
+func (db *memoryDB) dependsOn(from, to string) bool {
+	seen := make(map[string]bool)
+	stack := []string{from}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == to {
+			return true
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if mi, ok := db.issues[id]; ok {
+			stack = append(stack, mi.deps...)
+		}
+	}
+	return false
+}
+
+func (db *memoryDB) isDescendant(mi *memoryIssue, ancestor string) bool {
+	seen := make(map[string]bool)
+	for parent := mi.issue.Parent; parent != "" && !seen[parent]; {
+		if parent == ancestor {
+			return true
+		}
+		seen[parent] = true
+		p, ok := db.issues[parent]
+		if !ok {
+			return false
+		}
+		parent = p.issue.Parent
+	}
+	return false
+}
+
+// ready returns the open, unblocked issues accepted by include, ordered by
+// priority and then creation order.
+func (db *memoryDB) ready(include func(*memoryIssue) bool) []*Issue {
+	var issues []*Issue
+	for _, id := range db.order {
+		mi := db.issues[id]
+		if mi.issue.Status == "open" && include(mi) && len(db.openBlockers(mi)) == 0 {
+			issues = append(issues, db.view(mi, false))
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Priority < issues[j].Priority
+	})
+	return issues
+}
+
+// view returns a copy of mi's issue with the computed fields filled in.
+// detailed adds the Dependencies and Dependents that bd show reports.
+func (db *memoryDB) view(mi *memoryIssue, detailed bool) *Issue {
+	issue := mi.issue
+	issue.Labels = append([]string(nil), mi.issue.Labels...)
+	issue.DependsOn = append([]string(nil), mi.deps...)
+	issue.BlockedBy = db.openBlockers(mi)
+	issue.DependencyCount = len(issue.DependsOn)
+	issue.BlockedByCount = len(issue.BlockedBy)
+	issue.Blocks = nil
+	issue.Children = nil
+
+	for _, id := range db.order {
+		other := db.issues[id]
+		if other.issue.Parent == mi.issue.ID {
+			issue.Children = append(issue.Children, id)
+		}
+		for _, dep := range other.deps {
+			if dep == mi.issue.ID {
+				issue.Blocks = append(issue.Blocks, id)
+				break
+			}
+		}
+	}
+	issue.DependentCount = len(issue.Blocks)
+
+	if detailed {
+		issue.Dependencies = db.issueDeps(issue.DependsOn)
+		issue.Dependents = db.issueDeps(issue.Blocks)
+	}
+	return &issue
+}
+
+func (db *memoryDB) issueDeps(ids []string) []IssueDep {
+	var deps []IssueDep
+	for _, id := range ids {
+		other, ok := db.issues[id]
+		if !ok {
+			continue
+		}
+		deps = append(deps, IssueDep{
+			ID:             id,
+			Title:          other.issue.Title,
+			Status:         other.issue.Status,
+			Priority:       other.issue.Priority,
+			Type:           other.issue.Type,
+			DependencyType: "blocks",
+		})
+	}
+	return deps
+}
+
+func addLabel(labels []string, label string) []string {
+	for _, l := range labels {
+		if l == label {
+			return labels
+		}
+	}
+	return append(labels, label)
+}
+
+func removeLabel(labels []string, label string) []string {
+	out := labels[:0]
+	for _, l := range labels {
+		if l != label {
+			out = append(out, l)
+		}
+	}
+	return out
+}
@@ -0,0 +1,263 @@
+package beads
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func issueIDs(issues []*Issue) []string {
+	var ids []string
+	for _, issue := range issues {
+		ids = append(ids, issue.ID)
+	}
+	return ids
+}
+
+func TestMemoryStore_CreateAndShow(t *testing.T) {
+	clock := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	m := NewMemoryStore("gt")
+	m.SetClock(func() time.Time { return clock })
+
+	issue, err := m.Create(CreateOptions{Title: "Fix it", Type: "task", Priority: -1, Actor: "gastown/Toast"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue.ID != "gt-1" || issue.Status != "open" || issue.Priority != 2 {
+		t.Errorf("created %+v, want gt-1 open P2", issue)
+	}
+	if !HasLabel(issue, "gt:task") || issue.CreatedBy != "gastown/Toast" || issue.CreatedAt != "2026-03-01T12:00:00Z" {
+		t.Errorf("created %+v", issue)
+	}
+
+	if _, err := m.Create(CreateOptions{Title: "--help"}); !errors.Is(err, ErrFlagTitle) {
+		t.Errorf("flag-like title: err = %v, want ErrFlagTitle", err)
+	}
+	if _, err := m.CreateWithID("gt-1", CreateOptions{Title: "dup"}); err == nil {
+		t.Error("CreateWithID on existing ID should fail")
+	}
+	if _, err := m.Show("gt-nope"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Show missing: err = %v, want ErrNotFound", err)
+	}
+
+	// Returned issues are copies.
+	issue.Title = "changed"
+	if got, _ := m.Show("gt-1"); got.Title != "Fix it" {
+		t.Errorf("store mutated through returned issue: %q", got.Title)
+	}
+}
+
+func TestMemoryStore_DependenciesAndReady(t *testing.T) {
+	m := NewMemoryStore("gt-")
+	mr, _ := m.Create(CreateOptions{Title: "MR", Type: "merge-request", Priority: 1})
+	task, _ := m.Create(CreateOptions{Title: "Resolve conflicts", Type: "task", Priority: 0})
+	_, _ = m.Create(CreateOptions{Title: "wisp", Ephemeral: true})
+
+	if err := m.AddDependency(mr.ID, task.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddDependency(task.ID, mr.ID); err == nil {
+		t.Error("cyclic dependency should be rejected")
+	}
+
+	ready, _ := m.Ready()
+	if got := issueIDs(ready); !reflect.DeepEqual(got, []string{task.ID}) {
+		t.Errorf("Ready = %v, want only %s (MR blocked, wisp ephemeral)", got, task.ID)
+	}
+	blocked, _ := m.Blocked()
+	if got := issueIDs(blocked); !reflect.DeepEqual(got, []string{mr.ID}) {
+		t.Errorf("Blocked = %v, want %s", got, mr.ID)
+	}
+
+	shown, _ := m.Show(mr.ID)
+	if !reflect.DeepEqual(shown.BlockedBy, []string{task.ID}) || shown.BlockedByCount != 1 || len(shown.Dependencies) != 1 {
+		t.Errorf("Show(MR) deps = %+v", shown)
+	}
+	shown, _ = m.Show(task.ID)
+	if !reflect.DeepEqual(shown.Blocks, []string{mr.ID}) || len(shown.Dependents) != 1 {
+		t.Errorf("Show(task) dependents = %+v", shown)
+	}
+
+	// Closing the blocker unblocks the MR.
+	if err := m.CloseWithReason("resolved", task.ID); err != nil {
+		t.Fatal(err)
+	}
+	ready, _ = m.ReadyWithType("merge-request")
+	if got := issueIDs(ready); !reflect.DeepEqual(got, []string{mr.ID}) {
+		t.Errorf("ReadyWithType after close = %v, want %s", got, mr.ID)
+	}
+	if shown, _ := m.Show(mr.ID); len(shown.BlockedBy) != 0 || !reflect.DeepEqual(shown.DependsOn, []string{task.ID}) {
+		t.Errorf("closed blocker still blocks: %+v", shown)
+	}
+
+	if err := m.Close("gt-missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Close missing: err = %v", err)
+	}
+}
+
+func TestMemoryStore_ReadyForMol(t *testing.T) {
+	m := NewMemoryStore("gt")
+	mol, _ := m.Create(CreateOptions{Title: "molecule", Type: "molecule"})
+	step1, _ := m.Create(CreateOptions{Title: "step 1", Parent: mol.ID, Ephemeral: true})
+	step2, _ := m.Create(CreateOptions{Title: "step 2", Parent: mol.ID, Ephemeral: true})
+	_, _ = m.Create(CreateOptions{Title: "unrelated"})
+	if err := m.AddDependency(step2.ID, step1.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	ready, err := m.ReadyForMol(mol.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := issueIDs(ready); !reflect.DeepEqual(got, []string{step1.ID}) {
+		t.Errorf("ReadyForMol = %v, want [%s]", got, step1.ID)
+	}
+	if shown, _ := m.Show(mol.ID); !reflect.DeepEqual(shown.Children, []string{step1.ID, step2.ID}) {
+		t.Errorf("Children = %v", shown.Children)
+	}
+	if _, err := m.Create(CreateOptions{Title: "orphan", Parent: "gt-missing"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing parent: err = %v", err)
+	}
+}
+
+func TestMemoryStore_ListAndUpdate(t *testing.T) {
+	m := NewMemoryStore("gt")
+	a, _ := m.Create(CreateOptions{Title: "a", Type: "merge-request", Priority: 1})
+	b, _ := m.Create(CreateOptions{Title: "b", Type: "merge-request", Priority: 2, Labels: []string{"queue:main"}})
+	c, _ := m.Create(CreateOptions{Title: "c", Type: "task", Priority: 1})
+
+	assignee := "gastown/refinery"
+	if err := m.Update(b.ID, UpdateOptions{Assignee: &assignee, AddLabels: []string{"gt:expedite"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Close(c.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opts ListOptions
+		want []string
+	}{
+		{"default hides closed", ListOptions{Priority: -1}, []string{a.ID, b.ID}},
+		{"all", ListOptions{Status: "all", Priority: -1}, []string{a.ID, b.ID, c.ID}},
+		{"closed", ListOptions{Status: "closed", Priority: -1}, []string{c.ID}},
+		{"label", ListOptions{Label: "gt:expedite", Priority: -1}, []string{b.ID}},
+		{"all labels", ListOptions{Label: "gt:merge-request,queue:main", Priority: -1}, []string{b.ID}},
+		{"deprecated type", ListOptions{Type: "merge-request", Priority: -1}, []string{a.ID, b.ID}},
+		{"priority", ListOptions{Status: "all", Priority: 1}, []string{a.ID, c.ID}},
+		{"assignee", ListOptions{Assignee: assignee, Priority: -1}, []string{b.ID}},
+		{"no assignee", ListOptions{NoAssignee: true, Priority: -1}, []string{a.ID}},
+		{"limit", ListOptions{Status: "all", Priority: -1, Limit: 1}, []string{a.ID}},
+	}
+	for _, tt := range tests {
+		got, err := m.List(tt.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ids := issueIDs(got); !reflect.DeepEqual(ids, tt.want) {
+			t.Errorf("%s: List = %v, want %v", tt.name, ids, tt.want)
+		}
+	}
+
+	if err := m.Update(a.ID, UpdateOptions{SetLabels: []string{"x", "y"}, RemoveLabels: []string{"x"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := m.Show(a.ID); !reflect.DeepEqual(got.Labels, []string{"x", "y"}) {
+		t.Errorf("SetLabels should win over RemoveLabels: %v", got.Labels)
+	}
+	open := "open"
+	if err := m.Update(c.ID, UpdateOptions{Status: &open}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := m.Show(c.ID); got.ClosedAt != "" {
+		t.Errorf("reopened issue kept ClosedAt %q", got.ClosedAt)
+	}
+}
+
+func TestMemoryStore_Routing(t *testing.T) {
+	rig := NewMemoryStore("gt")
+	town := rig.Routed("hq")
+
+	convoy, _ := town.Create(CreateOptions{Title: "convoy", Type: "convoy"})
+	task, _ := rig.Create(CreateOptions{Title: "task"})
+	if convoy.ID != "hq-1" || task.ID != "gt-1" {
+		t.Fatalf("IDs = %s, %s", convoy.ID, task.ID)
+	}
+
+	// Cross-prefix lookups and dependencies resolve; lists stay per-database.
+	if _, err := rig.Show(convoy.ID); err != nil {
+		t.Errorf("rig.Show(hq-1): %v", err)
+	}
+	if err := town.AddDependency(convoy.ID, task.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := town.Show(convoy.ID); !reflect.DeepEqual(got.BlockedBy, []string{task.ID}) {
+		t.Errorf("convoy BlockedBy = %v", got.BlockedBy)
+	}
+	if got, _ := rig.List(ListOptions{Priority: -1}); !reflect.DeepEqual(issueIDs(got), []string{task.ID}) {
+		t.Errorf("rig.List = %v", issueIDs(got))
+	}
+	if err := rig.Close(task.ID); err != nil {
+		t.Fatal(err)
+	}
+	if ready, _ := town.ReadyWithType("convoy"); !reflect.DeepEqual(issueIDs(ready), []string{convoy.ID}) {
+		t.Errorf("town ready convoys = %v", issueIDs(ready))
+	}
+}
+
+func TestMemoryStore_Tracking(t *testing.T) {
+	rig := NewMemoryStore("gt")
+	town := rig.Routed("hq")
+
+	convoy, _ := town.Create(CreateOptions{Title: "convoy", Type: "convoy"})
+	_, _ = town.Create(CreateOptions{Title: "not a convoy", Type: "task"})
+	task, _ := rig.Create(CreateOptions{Title: "task"})
+	for _, id := range []string{task.ID, "gt-elsewhere", task.ID} {
+		if err := town.Track(convoy.ID, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := town.Track("hq-nope", task.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Track from missing convoy: err = %v, want ErrNotFound", err)
+	}
+
+	if got, _ := town.List(ListOptions{IssueType: "convoy", Priority: -1}); !reflect.DeepEqual(issueIDs(got), []string{convoy.ID}) {
+		t.Errorf("convoys = %v", issueIDs(got))
+	}
+	// Tracking doesn't block, and unknown IDs come back without a status.
+	if got, _ := town.Show(convoy.ID); len(got.BlockedBy) != 0 {
+		t.Errorf("convoy BlockedBy = %v, want none", got.BlockedBy)
+	}
+	if err := rig.Close(task.ID); err != nil {
+		t.Fatal(err)
+	}
+	tracked, err := town.TrackedIssues(convoy.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(issueIDs(tracked), []string{task.ID, "gt-elsewhere"}) || tracked[0].Status != "closed" || tracked[1].Status != "" {
+		t.Errorf("tracked = %+v, %+v", tracked[0], tracked[1])
+	}
+}
+
+func TestMemoryStore_UpdateAgentActiveMR(t *testing.T) {
+	m := NewMemoryStore("gt")
+	desc := FormatAgentDescription("polecat Toast", &AgentFields{RoleType: "polecat", Rig: "gastown", AgentState: "working"})
+	if _, err := m.CreateWithID("gt-gastown-polecat-Toast", CreateOptions{Title: "polecat Toast", Description: desc}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.UpdateAgentActiveMR("gt-gastown-polecat-Toast", "gt-mr-1"); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := m.Show("gt-gastown-polecat-Toast")
+	fields := ParseAgentFields(got.Description)
+	if fields.ActiveMR != "gt-mr-1" || fields.AgentState != "working" {
+		t.Errorf("agent fields = %+v", fields)
+	}
+	level := "loud"
+	if err := m.UpdateAgentDescriptionFields("gt-gastown-polecat-Toast", AgentFieldUpdates{NotificationLevel: &level}); err == nil {
+		t.Error("invalid notification level should be rejected")
+	}
+}
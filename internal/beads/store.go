@@ -0,0 +1,47 @@
+package beads
+
+// Store is the issue storage used by Gas Town components. *Beads implements
+// it by shelling out to bd; *MemoryStore implements it in memory for tests
+// that need real dependency, ready and routing semantics without bd or Dolt.
+//
+// Components that only need lookups should keep taking the narrower
+// IssueShower.
+type Store interface {
+	IssueShower
+
+	// List returns issues matching opts. An empty Status lists non-closed
+	// issues; "all" lists everything.
+	List(opts ListOptions) ([]*Issue, error)
+	// ShowMultiple returns the issues that exist among ids, keyed by ID.
+	ShowMultiple(ids []string) (map[string]*Issue, error)
+
+	// Ready returns open, non-ephemeral issues with no open blockers.
+	Ready() ([]*Issue, error)
+	// ReadyForMol returns the ready steps under a molecule.
+	ReadyForMol(moleculeID string) ([]*Issue, error)
+	// ReadyWithType returns ready issues labeled gt:<issueType>.
+	ReadyWithType(issueType string) ([]*Issue, error)
+	// Blocked returns non-closed issues with at least one open blocker.
+	Blocked() ([]*Issue, error)
+
+	Create(opts CreateOptions) (*Issue, error)
+	CreateWithID(id string, opts CreateOptions) (*Issue, error)
+	Update(id string, opts UpdateOptions) error
+	Close(ids ...string) error
+	CloseWithReason(reason string, ids ...string) error
+
+	// AddDependency records that issue depends on (is blocked by) dependsOn.
+	AddDependency(issue, dependsOn string) error
+	RemoveDependency(issue, dependsOn string) error
+
+	// Track records that a convoy tracks an issue, without blocking either.
+	Track(convoyID, issueID string) error
+	// TrackedIssues returns the issues a convoy tracks, with their current
+	// status. Issues that can't be looked up have an empty Status.
+	TrackedIssues(convoyID string) ([]*Issue, error)
+}
+
+var (
+	_ Store = (*Beads)(nil)
+	_ Store = (*MemoryStore)(nil)
+)
@@ -15,7 +15,6 @@ import (
 	"github.com/gofrs/flock"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/session"
-	"github.com/steveyegge/gastown/internal/tmux"
 )
 
 // MarkerFileName is the lock file for Boot startup coordination.
@@ -39,7 +38,7 @@ type Boot struct {
 	townRoot   string
 	bootDir    string // ~/gt/deacon/dogs/boot/
 	deaconDir  string // ~/gt/deacon/
-	tmux       *tmux.Tmux
+	tmux       session.Backend
 	degraded   bool
 	lockHandle *flock.Flock // held during triage execution
 }
@@ -50,7 +49,7 @@ func New(townRoot string) *Boot {
 		townRoot:  townRoot,
 		bootDir:   filepath.Join(townRoot, "deacon", "dogs", "boot"),
 		deaconDir: filepath.Join(townRoot, "deacon"),
-		tmux:      tmux.NewTmux(),
+		tmux:      session.NewBackend(townRoot),
 		degraded:  os.Getenv("GT_DEGRADED") == "true",
 	}
 }
@@ -230,7 +229,7 @@ func (b *Boot) DeaconDir() string {
 	return b.deaconDir
 }
 
-// Tmux returns the tmux manager.
-func (b *Boot) Tmux() *tmux.Tmux {
+// Tmux returns the session backend.
+func (b *Boot) Tmux() session.Backend {
 	return b.tmux
 }
@@ -31,7 +31,7 @@ var activityCmd = &cobra.Command{
 	Short:   "Emit and view activity events",
 	Long: `Emit and view activity events for the Gas Town activity feed.
 
-Events are written to ~/gt/.events.jsonl and can be viewed with 'gt feed'.
+Events are written to the event store (~/gt/.events/) and can be viewed with 'gt feed'.
 
 Subcommands:
   emit    Emit an activity event`,
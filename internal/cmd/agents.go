@@ -14,7 +14,6 @@ import (
 	"github.com/steveyegge/gastown/internal/lock"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -160,7 +159,7 @@ func categorizeSession(name string) *AgentSession {
 
 // getAgentSessions returns all categorized Gas Town sessions.
 func getAgentSessions(includePolecats bool) ([]*AgentSession, error) {
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	sessions, err := t.ListSessions()
 	if err != nil {
 		return nil, err
@@ -466,7 +465,7 @@ func buildCollisionReport(townRoot string) (*CollisionReport, error) {
 	}
 
 	// Get all tmux sessions
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	sessions, err := t.ListSessions()
 	if err != nil {
 		sessions = []string{} // Continue even if tmux not running
@@ -403,42 +403,21 @@ func formatTownlogSummary(e townlog.Event) string {
 func collectFeedEvents(townRoot, actor string, since time.Time) ([]AuditEntry, error) {
 	var entries []AuditEntry
 
-	eventsPath := filepath.Join(townRoot, events.EventsFile)
-	file, err := os.Open(eventsPath)
+	recs, err := events.OpenStore(townRoot).Query(events.Query{
+		Since: since,
+		Match: func(e events.Event) bool { return actor == "" || matchesActor(e.Actor, actor) },
+	})
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil // No events file yet
-		}
 		return nil, err
 	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		var e events.Event
-		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
-			continue // Skip malformed lines
-		}
-
-		// Apply actor filter
-		if actor != "" && !matchesActor(e.Actor, actor) {
-			continue
-		}
-
-		// Parse timestamp
-		ts, _ := time.Parse(time.RFC3339, e.Timestamp)
-
-		// Apply since filter
-		if !since.IsZero() && ts.Before(since) {
-			continue
-		}
 
+	for _, rec := range recs {
 		entries = append(entries, AuditEntry{
-			Timestamp: ts,
+			Timestamp: rec.Time(),
 			Source:    "events",
-			Type:      e.Type,
-			Actor:     e.Actor,
-			Summary:   formatFeedSummary(e),
+			Type:      rec.Type,
+			Actor:     rec.Actor,
+			Summary:   formatFeedSummary(rec.Event),
 		})
 	}
 
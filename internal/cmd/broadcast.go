@@ -7,7 +7,6 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -102,7 +101,7 @@ func runBroadcast(cmd *cobra.Command, args []string) error {
 	}
 
 	// Send nudges
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	townRoot, _ := workspace.FindFromCwd()
 	var succeeded, failed, skipped int
 	var failures []string
@@ -11,23 +11,19 @@ import (
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/townlog"
+	"github.com/steveyegge/gastown/internal/witness"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
-// Callback message subject patterns for routing.
+// Callback message subject patterns for routing. POLECAT_DONE and HELP are
+// witness protocol messages; see witness.ClassifyMail.
 var (
-	// POLECAT_DONE <name> - polecat signaled completion
-	patternPolecatDone = regexp.MustCompile(`^POLECAT_DONE\s+(\S+)`)
-
 	// Merge Request Rejected: <branch> - refinery rejected MR
 	patternMergeRejected = regexp.MustCompile(`^Merge Request Rejected:\s+(.+)`)
 
 	// Merge Request Completed: <branch> - refinery completed MR
 	patternMergeCompleted = regexp.MustCompile(`^Merge Request Completed:\s+(.+)`)
 
-	// HELP: <topic> - polecat requesting help
-	patternHelp = regexp.MustCompile(`^HELP:\s+(.+)`)
-
 	// ESCALATION: <topic> - witness escalating issue
 	patternEscalation = regexp.MustCompile(`^ESCALATION:\s+(.+)`)
 
@@ -208,7 +204,7 @@ func processCallback(townRoot string, msg *mail.Message, dryRun bool) CallbackRe
 	}
 
 	// Classify the callback
-	result.CallbackType = classifyCallback(msg.Subject)
+	result.CallbackType = classifyCallback(msg)
 
 	// Handle based on type
 	switch result.CallbackType {
@@ -252,17 +248,22 @@ func processCallback(townRoot string, msg *mail.Message, dryRun bool) CallbackRe
 	return result
 }
 
-// classifyCallback determines the type of callback from the subject line.
-func classifyCallback(subject string) CallbackType {
-	switch {
-	case patternPolecatDone.MatchString(subject):
+// classifyCallback determines the type of callback. Witness protocol mail
+// (POLECAT_DONE, HELP) is classified by its envelope, falling back to the
+// subject line; the remaining callbacks are subject conventions.
+func classifyCallback(msg *mail.Message) CallbackType {
+	switch witness.ClassifyMail(msg) {
+	case witness.ProtoPolecatDone:
 		return CallbackPolecatDone
+	case witness.ProtoHelp:
+		return CallbackHelp
+	}
+
+	switch subject := msg.Subject; {
 	case patternMergeRejected.MatchString(subject):
 		return CallbackMergeRejected
 	case patternMergeCompleted.MatchString(subject):
 		return CallbackMergeCompleted
-	case patternHelp.MatchString(subject):
-		return CallbackHelp
 	case patternEscalation.MatchString(subject):
 		return CallbackEscalation
 	case patternSling.MatchString(subject):
@@ -275,23 +276,11 @@ func classifyCallback(subject string) CallbackType {
 // handlePolecatDone processes a POLECAT_DONE callback.
 // These come from Witnesses forwarding polecat completion notices.
 func handlePolecatDone(townRoot string, msg *mail.Message, dryRun bool) (string, error) {
-	matches := patternPolecatDone.FindStringSubmatch(msg.Subject)
-	if len(matches) < 2 {
-		return "", fmt.Errorf("could not parse polecat name from subject: %q", msg.Subject)
-	}
-	polecatName := matches[1]
-
-	// Extract info from body
-	var exitType, issueID string
-	for _, line := range strings.Split(msg.Body, "\n") {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "Exit:") {
-			exitType = strings.TrimSpace(strings.TrimPrefix(line, "Exit:"))
-		}
-		if strings.HasPrefix(line, "Issue:") {
-			issueID = strings.TrimSpace(strings.TrimPrefix(line, "Issue:"))
-		}
+	payload, err := witness.ParsePolecatDone(msg.Subject, msg.Body)
+	if err != nil {
+		return "", err
 	}
+	polecatName, exitType, issueID := payload.PolecatName, payload.Exit, payload.IssueID
 
 	if dryRun {
 		return fmt.Sprintf("would log completion for %s (exit=%s, issue=%s)",
@@ -385,11 +374,11 @@ func handleMergeRejected(townRoot string, msg *mail.Message, dryRun bool) (strin
 
 // handleHelp processes a HELP: request from a polecat.
 func handleHelp(townRoot string, msg *mail.Message, dryRun bool) (string, error) {
-	matches := patternHelp.FindStringSubmatch(msg.Subject)
-	if len(matches) < 2 {
-		return "", fmt.Errorf("could not parse topic from subject: %q", msg.Subject)
+	payload, err := witness.ParseHelp(msg.Subject, msg.Body)
+	if err != nil {
+		return "", err
 	}
-	topic := matches[1]
+	topic := payload.Topic
 
 	if dryRun {
 		return fmt.Sprintf("would forward help request to overseer: %s", topic), nil
@@ -10,6 +10,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -603,11 +604,14 @@ Supported keys:
                               completion (true/false, default: false)
   cli_theme                   CLI color scheme ("dark", "light", "auto")
   default_agent               Default agent preset name
+  session_backend             Where agent sessions run ("tmux", "pty");
+                              restart the daemon after changing it
 
 Examples:
   gt config set convoy.notify_on_complete true
   gt config set cli_theme dark
-  gt config set default_agent claude`,
+  gt config set default_agent claude
+  gt config set session_backend pty`,
 	Args: cobra.ExactArgs(2),
 	RunE: runConfigSet,
 }
@@ -623,6 +627,7 @@ Supported keys:
                               completion (true/false, default: false)
   cli_theme                   CLI color scheme
   default_agent               Default agent preset name
+  session_backend             Where agent sessions run
 
 Examples:
   gt config get convoy.notify_on_complete
@@ -668,8 +673,16 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 	case "default_agent":
 		townSettings.DefaultAgent = value
 
+	case "session_backend":
+		switch value {
+		case session.BackendTmux, session.BackendPTY:
+			townSettings.SessionBackend = value
+		default:
+			return fmt.Errorf("invalid session_backend: %q (expected tmux or pty)", value)
+		}
+
 	default:
-		return fmt.Errorf("unknown config key: %q\n\nSupported keys:\n  convoy.notify_on_complete\n  cli_theme\n  default_agent", key)
+		return fmt.Errorf("unknown config key: %q\n\nSupported keys:\n  convoy.notify_on_complete\n  cli_theme\n  default_agent\n  session_backend", key)
 	}
 
 	if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
@@ -715,8 +728,14 @@ func runConfigGet(cmd *cobra.Command, args []string) error {
 			value = "claude"
 		}
 
+	case "session_backend":
+		value = townSettings.SessionBackend
+		if value == "" {
+			value = session.BackendTmux
+		}
+
 	default:
-		return fmt.Errorf("unknown config key: %q\n\nSupported keys:\n  convoy.notify_on_complete\n  cli_theme\n  default_agent", key)
+		return fmt.Errorf("unknown config key: %q\n\nSupported keys:\n  convoy.notify_on_complete\n  cli_theme\n  default_agent\n  session_backend", key)
 	}
 
 	fmt.Println(value)
@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/session"
 )
 
 // setupTestTown creates a minimal Gas Town workspace for testing.
@@ -677,6 +678,32 @@ func TestConfigSetGet(t *testing.T) {
 		}
 	})
 
+	t.Run("set session_backend selects the pty backend", func(t *testing.T) {
+		townRoot := setupTestTownForConfig(t)
+
+		originalWd, _ := os.Getwd()
+		defer os.Chdir(originalWd)
+		if err := os.Chdir(townRoot); err != nil {
+			t.Fatalf("chdir: %v", err)
+		}
+
+		if session.UsePTY(townRoot) {
+			t.Fatal("UsePTY should be false before session_backend is set")
+		}
+		cmd := &cobra.Command{}
+		if err := runConfigSet(cmd, []string{"session_backend", "pty"}); err != nil {
+			t.Fatalf("runConfigSet failed: %v", err)
+		}
+		if !session.UsePTY(townRoot) {
+			t.Error("UsePTY should be true after setting session_backend to pty")
+		}
+
+		err := runConfigSet(cmd, []string{"session_backend", "screen"})
+		if err == nil || !strings.Contains(err.Error(), "invalid session_backend") {
+			t.Errorf("error = %v, want 'invalid session_backend'", err)
+		}
+	})
+
 	t.Run("set rejects unknown key", func(t *testing.T) {
 		townRoot := setupTestTownForConfig(t)
 
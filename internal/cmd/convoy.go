@@ -18,6 +18,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tui/convoy"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -186,6 +187,8 @@ The --merge flag sets the merge strategy for all work in the convoy:
   direct  Push branch directly to main (no MR, no refinery)
   mr      Create merge-request bead, refinery processes (default)
   local   Keep on feature branch (for upstream PRs, human review)
+  pr      Refinery opens a forge pull request and merges it once checks
+          pass and reviews allow (see 'gt mq pr')
 
 Examples:
   gt convoy create "Deploy v2.0" gt-abc bd-xyz
@@ -330,7 +333,7 @@ func init() {
 	convoyCreateCmd.Flags().StringVar(&convoyNotify, "notify", "", "Additional address to notify on completion (default: mayor/ if flag used without value)")
 	convoyCreateCmd.Flags().Lookup("notify").NoOptDefVal = "mayor/"
 	convoyCreateCmd.Flags().BoolVar(&convoyOwned, "owned", false, "Mark convoy as caller-managed lifecycle (no automatic witness/refinery registration)")
-	convoyCreateCmd.Flags().StringVar(&convoyMerge, "merge", "", "Merge strategy: direct (push to main), mr (merge queue, default), local (keep on branch)")
+	convoyCreateCmd.Flags().StringVar(&convoyMerge, "merge", "", "Merge strategy: direct (push to main), mr (merge queue, default), local (keep on branch), pr (forge pull request)")
 
 	// Status flags
 	convoyStatusCmd.Flags().BoolVar(&convoyStatusJSON, "json", false, "Output as JSON")
@@ -389,10 +392,10 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	// Validate --merge flag if provided
 	if convoyMerge != "" {
 		switch convoyMerge {
-		case "direct", "mr", "local":
+		case "direct", "mr", "local", "pr":
 			// Valid
 		default:
-			return fmt.Errorf("invalid --merge value %q: must be direct, mr, or local", convoyMerge)
+			return fmt.Errorf("invalid --merge value %q: must be direct, mr, local, or pr", convoyMerge)
 		}
 	}
 
@@ -744,6 +747,7 @@ func checkSingleConvoy(townBeads, convoyID string, dryRun bool) error {
 	}
 
 	fmt.Printf("%s Auto-closed convoy 🚚 %s: %s\n", style.Bold.Render("✓"), convoyID, convoy.Title)
+	logConvoyClosed(convoyID, convoy.Title, reason)
 
 	// Send completion notification
 	notifyConvoyCompletion(townBeads, convoyID, convoy.Title)
@@ -856,6 +860,7 @@ func runConvoyClose(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("%s Closed convoy 🚚 %s: %s\n", style.Bold.Render("✓"), convoyID, convoy.Title)
+	logConvoyClosed(convoyID, convoy.Title, reason)
 	if convoyCloseReason != "" {
 		fmt.Printf("  Reason: %s\n", convoyCloseReason)
 	}
@@ -1040,6 +1045,7 @@ func runConvoyLand(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("\n%s Landed convoy 🚚 %s: %s\n", style.Bold.Render("✓"), convoyID, convoy.Title)
+	logConvoyClosed(convoyID, convoy.Title, reason)
 	fmt.Printf("  Reason: %s\n", reason)
 	if len(tracked) > 0 {
 		closedCount := len(tracked) - len(openIssues)
@@ -1419,6 +1425,7 @@ func checkAndCloseCompletedConvoys(townBeads string, dryRun bool) ([]struct{ ID,
 			}
 
 			closed = append(closed, struct{ ID, Title string }{convoy.ID, convoy.Title})
+			logConvoyClosed(convoy.ID, convoy.Title, reason)
 
 			// Check if convoy has notify address and send notification
 			notifyConvoyCompletion(townBeads, convoy.ID, convoy.Title)
@@ -1428,6 +1435,12 @@ func checkAndCloseCompletedConvoys(townBeads string, dryRun bool) ([]struct{ ID,
 	return closed, nil
 }
 
+// logConvoyClosed records a convoy_closed event on the feed. Plugins with
+// an event gate on convoy_closed are dispatched by the daemon in response.
+func logConvoyClosed(convoyID, title, reason string) {
+	_ = events.LogFeed(events.TypeConvoyClosed, "gt", events.ConvoyClosedPayload(convoyID, title, reason))
+}
+
 // notifyConvoyCompletion sends notifications to owner and any notify addresses.
 func notifyConvoyCompletion(townBeads, convoyID, title string) {
 	// Get convoy description to find owner and notify addresses
@@ -1883,7 +1896,7 @@ func hasLabel(labels []string, target string) bool { //nolint:unparam // target
 }
 
 // parseConvoyMergeStrategy extracts the merge strategy from a convoy description.
-// Returns the strategy string ("direct", "mr", "local") or empty string if not set.
+// Returns the strategy string ("direct", "mr", "local", "pr") or empty string if not set.
 func parseConvoyMergeStrategy(description string) string {
 	for _, line := range strings.Split(description, "\n") {
 		line = strings.TrimSpace(line)
@@ -82,6 +82,7 @@ esac
 }
 
 func TestCheckSingleConvoy_EmptyConvoyAutoCloses(t *testing.T) {
+	t.Chdir(t.TempDir()) // keep events out of any enclosing town
 	_, townBeads, closeLogPath := mockBdForConvoyTest(t, "hq-empty1", "Empty test convoy")
 
 	err := checkSingleConvoy(townBeads, "hq-empty1", false)
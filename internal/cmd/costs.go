@@ -2,10 +2,8 @@
 package cmd
 
 import (
-	"bufio"
 	"encoding/json"
 	"fmt"
-	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -17,9 +15,10 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/costs"
+	"github.com/steveyegge/gastown/internal/quota"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -29,6 +28,8 @@ var (
 	costsWeek    bool
 	costsByRole  bool
 	costsByRig   bool
+	costsByAgent bool
+	costsByModel bool
 	costsVerbose bool
 
 	// Record subcommand flags
@@ -47,11 +48,22 @@ var (
 var costsCmd = &cobra.Command{
 	Use:     "costs",
 	GroupID: GroupDiag,
-	Short:   "Show costs for running Claude sessions",
-	Long: `Display costs for Claude Code sessions in Gas Town.
+	Short:   "Show costs for running agent sessions",
+	Long: `Display costs for agent sessions in Gas Town.
 
-Costs are calculated from Claude Code transcript files at ~/.claude/projects/
-by summing token usage from assistant messages and applying model-specific pricing.
+Costs are calculated from each agent's own transcript files by summing token
+usage per model and applying model-specific pricing. Transcripts are read for
+the claude, gemini, codex and opencode agent presets; the agent is taken from
+GT_AGENT or the role's configured agent (role_agents).
+
+Prices come from a built-in table that the town settings "pricing" list can
+extend or override. Each entry may carry an effective_from date so older
+sessions keep the price that applied when they ended:
+
+  "pricing": [
+    {"model": "gemini-2.5-pro*", "input": 1.25, "output": 10, "cache_read": 0.31},
+    {"model": "claude-sonnet-4*", "effective_from": "2026-11-01", "input": 2.5, "output": 12}
+  ]
 
 Examples:
   gt costs              # Live costs from running sessions
@@ -59,12 +71,16 @@ Examples:
   gt costs --week       # This week's costs from digest beads + today's log
   gt costs --by-role    # Breakdown by role (polecat, witness, etc.)
   gt costs --by-rig     # Breakdown by rig
+  gt costs --by-agent   # Breakdown by agent preset (claude, gemini, ...)
+  gt costs --by-model   # Breakdown by model
   gt costs --json       # Output as JSON
   gt costs -v           # Show debug output for failures
 
 Subcommands:
   gt costs record       # Record session cost to local log file (Stop hook)
-  gt costs digest       # Aggregate log entries into daily digest bead (Deacon patrol)`,
+  gt costs digest       # Aggregate log entries into daily digest bead (Deacon patrol)
+  gt costs pricing      # Show the effective model price table
+  gt costs budget       # Show daily budgets and today's spend against them`,
 	RunE: runCosts,
 }
 
@@ -73,9 +89,9 @@ var costsRecordCmd = &cobra.Command{
 	Short: "Record session cost to local log file (called by Stop hook)",
 	Long: `Record the final cost of a session to a local log file.
 
-This command is intended to be called from a Claude Code Stop hook.
-It reads token usage from the Claude Code transcript file (~/.claude/projects/...)
-and calculates the cost based on model pricing, then appends it to
+This command is intended to be called from an agent Stop hook.
+It reads token usage from the session's agent transcript (Claude Code, Gemini CLI,
+Codex or OpenCode) and calculates the cost based on model pricing, then appends it to
 ~/.gt/costs.jsonl. This is a simple append operation that never fails
 due to database availability.
 
@@ -107,6 +123,21 @@ Examples:
 	RunE: runCostsDigest,
 }
 
+var costsPricingCmd = &cobra.Command{
+	Use:   "pricing",
+	Short: "Show the effective model price table",
+	Long: `Show the model prices used by gt costs, in USD per million tokens.
+
+The table is the built-in prices merged with the "pricing" list in town
+settings. Model names ending in "*" match any model with that prefix;
+"*" alone prices models with no other match.
+
+Examples:
+  gt costs pricing
+  gt costs pricing --json`,
+	RunE: runCostsPricing,
+}
+
 var costsMigrateCmd = &cobra.Command{
 	Use:   "migrate",
 	Short: "Migrate legacy session.ended beads to the new log-file architecture",
@@ -135,6 +166,8 @@ func init() {
 	costsCmd.Flags().BoolVar(&costsWeek, "week", false, "Show this week's total from session events")
 	costsCmd.Flags().BoolVar(&costsByRole, "by-role", false, "Show breakdown by role")
 	costsCmd.Flags().BoolVar(&costsByRig, "by-rig", false, "Show breakdown by rig")
+	costsCmd.Flags().BoolVar(&costsByAgent, "by-agent", false, "Show breakdown by agent preset")
+	costsCmd.Flags().BoolVar(&costsByModel, "by-model", false, "Show breakdown by model")
 	costsCmd.Flags().BoolVarP(&costsVerbose, "verbose", "v", false, "Show debug output for failures")
 
 	// Add record subcommand
@@ -148,6 +181,10 @@ func init() {
 	costsDigestCmd.Flags().StringVar(&digestDate, "date", "", "Digest a specific date (YYYY-MM-DD)")
 	costsDigestCmd.Flags().BoolVar(&digestDryRun, "dry-run", false, "Preview what would be done without making changes")
 
+	// Add pricing subcommand
+	costsCmd.AddCommand(costsPricingCmd)
+	costsPricingCmd.Flags().BoolVar(&costsJSON, "json", false, "Output as JSON")
+
 	// Add migrate subcommand
 	costsCmd.AddCommand(costsMigrateCmd)
 	costsMigrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Preview what would be migrated without making changes")
@@ -159,20 +196,26 @@ type SessionCost struct {
 	Role    string  `json:"role"`
 	Rig     string  `json:"rig,omitempty"`
 	Worker  string  `json:"worker,omitempty"`
+	Agent   string  `json:"agent,omitempty"`
+	Model   string  `json:"model,omitempty"`
 	Cost    float64 `json:"cost_usd"`
 	Running bool    `json:"running"`
 }
 
 // CostEntry is a ledger entry for historical cost tracking.
 type CostEntry struct {
-	SessionID string    `json:"session_id"`
-	Role      string    `json:"role"`
-	Rig       string    `json:"rig,omitempty"`
-	Worker    string    `json:"worker,omitempty"`
-	CostUSD   float64   `json:"cost_usd"`
-	StartedAt time.Time `json:"started_at"`
-	EndedAt   time.Time `json:"ended_at"`
-	WorkItem  string    `json:"work_item,omitempty"`
+	SessionID string             `json:"session_id"`
+	Role      string             `json:"role"`
+	Rig       string             `json:"rig,omitempty"`
+	Worker    string             `json:"worker,omitempty"`
+	Agent     string             `json:"agent,omitempty"`
+	Model     string             `json:"model,omitempty"`
+	ByModel   map[string]float64 `json:"by_model,omitempty"`
+	CostUSD   float64            `json:"cost_usd"`
+	StartedAt time.Time          `json:"started_at"`
+	EndedAt   time.Time          `json:"ended_at"`
+	WorkItem  string             `json:"work_item,omitempty"`
+	Convoy    string             `json:"convoy,omitempty"`
 }
 
 // CostsOutput is the JSON output structure.
@@ -181,65 +224,17 @@ type CostsOutput struct {
 	Total    float64            `json:"total_usd"`
 	ByRole   map[string]float64 `json:"by_role,omitempty"`
 	ByRig    map[string]float64 `json:"by_rig,omitempty"`
+	ByAgent  map[string]float64 `json:"by_agent,omitempty"`
+	ByModel  map[string]float64 `json:"by_model,omitempty"`
 	Period   string             `json:"period,omitempty"`
 }
 
 // costRegex matches cost patterns like "$1.23" or "$12.34"
 var costRegex = regexp.MustCompile(`\$(\d+\.\d{2})`)
 
-// TranscriptMessage represents a message from a Claude Code transcript file.
-type TranscriptMessage struct {
-	Type      string                 `json:"type"`
-	SessionID string                 `json:"sessionId"`
-	CWD       string                 `json:"cwd"`
-	Message   *TranscriptMessageBody `json:"message,omitempty"`
-}
-
-// TranscriptMessageBody contains the message content and usage info.
-type TranscriptMessageBody struct {
-	Model string          `json:"model"`
-	Role  string          `json:"role"`
-	Usage *TranscriptUsage `json:"usage,omitempty"`
-}
-
-// TranscriptUsage contains token usage information.
-type TranscriptUsage struct {
-	InputTokens              int `json:"input_tokens"`
-	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
-	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
-	OutputTokens             int `json:"output_tokens"`
-}
-
-// TokenUsage aggregates token usage across a session.
-type TokenUsage struct {
-	Model                    string
-	InputTokens              int
-	CacheCreationInputTokens int
-	CacheReadInputTokens     int
-	OutputTokens             int
-}
-
-// Model pricing per million tokens (as of Jan 2025).
-// See: https://www.anthropic.com/pricing
-var modelPricing = map[string]struct {
-	InputPerMillion       float64
-	OutputPerMillion      float64
-	CacheReadPerMillion   float64 // 90% discount on input price
-	CacheCreatePerMillion float64 // 25% premium on input price
-}{
-	// Claude Opus 4.5
-	"claude-opus-4-5-20251101": {15.0, 75.0, 1.5, 18.75},
-	// Claude Sonnet 4
-	"claude-sonnet-4-20250514": {3.0, 15.0, 0.3, 3.75},
-	// Claude Haiku 3.5
-	"claude-3-5-haiku-20241022": {1.0, 5.0, 0.1, 1.25},
-	// Fallback for unknown models (use Sonnet pricing)
-	"default": {3.0, 15.0, 0.3, 3.75},
-}
-
 func runCosts(cmd *cobra.Command, args []string) error {
 	// If querying ledger, use ledger functions
-	if costsToday || costsWeek || costsByRole || costsByRig {
+	if costsToday || costsWeek || costsByRole || costsByRig || costsByAgent || costsByModel {
 		return runCostsFromLedger()
 	}
 
@@ -248,7 +243,7 @@ func runCosts(cmd *cobra.Command, args []string) error {
 }
 
 func runLiveCosts() error {
-	t := tmux.NewTmux()
+	t := sessionBackend()
 
 	// Get all tmux sessions
 	sessions, err := t.ListSessions()
@@ -256,7 +251,10 @@ func runLiveCosts() error {
 		return fmt.Errorf("listing sessions: %w", err)
 	}
 
-	var costs []SessionCost
+	townRoot, _ := workspace.FindFromCwd()
+	cc := newCostContext(townRoot)
+
+	var sessionCosts []SessionCost
 	var total float64
 
 	for _, sess := range sessions {
@@ -277,48 +275,52 @@ func runLiveCosts() error {
 			continue
 		}
 
-		// Extract cost from Claude transcript
-		cost, err := extractCostFromWorkDir(workDir)
+		// Extract cost from the agent's transcript
+		agentName, _ := t.GetEnvironment(sess, "GT_AGENT")
+		agent := cc.agentFor(agentName, role, rig)
+		sc, err := cc.sessionCost(agent, workDir, time.Now())
 		if err != nil {
 			if costsVerbose {
 				fmt.Fprintf(os.Stderr, "[costs] could not extract cost for %s: %v\n", sess, err)
 			}
 			// Still include the session with zero cost
-			cost = 0.0
 		}
 
 		// Check if an agent appears to be running
 		running := t.IsAgentRunning(sess)
 
-		costs = append(costs, SessionCost{
+		sessionCosts = append(sessionCosts, SessionCost{
 			Session: sess,
 			Role:    role,
 			Rig:     rig,
 			Worker:  worker,
-			Cost:    cost,
+			Agent:   agent,
+			Model:   sc.model,
+			Cost:    sc.total,
 			Running: running,
 		})
-		total += cost
+		total += sc.total
 	}
 
 	// Sort by session name
-	sort.Slice(costs, func(i, j int) bool {
-		return costs[i].Session < costs[j].Session
+	sort.Slice(sessionCosts, func(i, j int) bool {
+		return sessionCosts[i].Session < sessionCosts[j].Session
 	})
 
 	if costsJSON {
 		return outputCostsJSON(CostsOutput{
-			Sessions: costs,
+			Sessions: sessionCosts,
 			Total:    total,
 		})
 	}
 
-	return outputCostsHuman(costs, total)
+	return outputCostsHuman(sessionCosts, total)
 }
 
 func runCostsFromLedger() error {
 	now := time.Now()
 	var entries []CostEntry
+	var digests []CostDigest
 	var err error
 
 	if costsToday {
@@ -331,7 +333,7 @@ func runCostsFromLedger() error {
 	} else if costsWeek {
 		// For week: query digest beads (costs.digest events)
 		// These are the aggregated daily reports
-		entries, err = queryDigestBeads(7)
+		entries, digests, err = queryDigestBeads(7)
 		if err != nil {
 			return fmt.Errorf("querying digest beads: %w", err)
 		}
@@ -339,8 +341,8 @@ func runCostsFromLedger() error {
 		// Also include today's wisps (not yet digested)
 		todayEntries, _ := querySessionCostEntries(now)
 		entries = append(entries, todayEntries...)
-	} else if costsByRole || costsByRig {
-		// When using --by-role or --by-rig without time filter, default to today
+	} else if costsByRole || costsByRig || costsByAgent || costsByModel {
+		// When using a breakdown flag without time filter, default to today
 		// (querying all historical events would be expensive and likely empty)
 		entries, err = querySessionCostEntries(now)
 		if err != nil {
@@ -352,34 +354,26 @@ func runCostsFromLedger() error {
 		entries = querySessionEvents()
 	}
 
-	if len(entries) == 0 {
+	if len(entries) == 0 && len(digests) == 0 {
 		fmt.Println(style.Dim.Render("No cost data found. Costs are recorded when sessions end."))
 		return nil
 	}
 
-	// Calculate totals
-	var total float64
-	byRole := make(map[string]float64)
-	byRig := make(map[string]float64)
-
-	for _, entry := range entries {
-		total += entry.CostUSD
-		byRole[entry.Role] += entry.CostUSD
-		if entry.Rig != "" {
-			byRig[entry.Rig] += entry.CostUSD
-		}
-	}
-
-	// Build output
+	full := aggregateCosts(entries, digests)
 	output := CostsOutput{
-		Total: total,
+		Total: full.Total,
 	}
-
 	if costsByRole {
-		output.ByRole = byRole
+		output.ByRole = full.ByRole
 	}
 	if costsByRig {
-		output.ByRig = byRig
+		output.ByRig = full.ByRig
+	}
+	if costsByAgent {
+		output.ByAgent = full.ByAgent
+	}
+	if costsByModel {
+		output.ByModel = full.ByModel
 	}
 
 	// Set period label
@@ -393,7 +387,69 @@ func runCostsFromLedger() error {
 		return outputCostsJSON(output)
 	}
 
-	return outputLedgerHuman(output, entries)
+	sessionCount := len(entries)
+	for _, d := range digests {
+		sessionCount += d.SessionCount
+	}
+	return outputLedgerHuman(output, sessionCount)
+}
+
+// aggregateCosts totals ledger entries and aggregate-only digests and splits
+// them by role, rig, agent and model. Entries recorded before agents and models
+// were tracked count as agent "claude" and model "unknown".
+func aggregateCosts(entries []CostEntry, digests []CostDigest) CostsOutput {
+	out := CostsOutput{
+		ByRole:  make(map[string]float64),
+		ByRig:   make(map[string]float64),
+		ByAgent: make(map[string]float64),
+		ByModel: make(map[string]float64),
+	}
+	for _, e := range entries {
+		out.Total += e.CostUSD
+		out.ByRole[e.Role] += e.CostUSD
+		if e.Rig != "" {
+			out.ByRig[e.Rig] += e.CostUSD
+		}
+		agent := e.Agent
+		if agent == "" {
+			agent = string(config.AgentClaude)
+		}
+		out.ByAgent[agent] += e.CostUSD
+		switch {
+		case len(e.ByModel) > 0:
+			for model, cost := range e.ByModel {
+				out.ByModel[model] += cost
+			}
+		case e.Model != "":
+			out.ByModel[e.Model] += e.CostUSD
+		default:
+			out.ByModel["unknown"] += e.CostUSD
+		}
+	}
+	for _, d := range digests {
+		out.Total += d.TotalUSD
+		for k, v := range d.ByRole {
+			out.ByRole[k] += v
+		}
+		for k, v := range d.ByRig {
+			out.ByRig[k] += v
+		}
+		addSplit(out.ByAgent, d.ByAgent, d.TotalUSD, string(config.AgentClaude))
+		addSplit(out.ByModel, d.ByModel, d.TotalUSD, "unknown")
+	}
+	return out
+}
+
+// addSplit adds a digest's breakdown to dst. Digests written before the
+// breakdown existed put their whole total under fallback.
+func addSplit(dst, split map[string]float64, total float64, fallback string) {
+	if len(split) == 0 {
+		dst[fallback] += total
+		return
+	}
+	for k, v := range split {
+		dst[k] += v
+	}
 }
 
 // SessionEvent represents a session.ended event from beads.
@@ -562,8 +618,10 @@ func querySessionEventsFromLocation(location string) ([]CostEntry, error) {
 	return entries, nil
 }
 
-// queryDigestBeads queries costs.digest events from the past N days and extracts session entries.
-func queryDigestBeads(days int) ([]CostEntry, error) {
+// queryDigestBeads queries costs.digest events from the past N days. Digests that
+// carry per-session data (old format) are returned as entries; aggregate-only
+// digests are returned as is.
+func queryDigestBeads(days int) ([]CostEntry, []CostDigest, error) {
 	// Get list of event IDs
 	listArgs := []string{
 		"list",
@@ -576,16 +634,16 @@ func queryDigestBeads(days int) ([]CostEntry, error) {
 	listCmd := exec.Command("bd", listArgs...)
 	listOutput, err := listCmd.Output()
 	if err != nil {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	var listItems []EventListItem
 	if err := json.Unmarshal(listOutput, &listItems); err != nil {
-		return nil, fmt.Errorf("parsing event list: %w", err)
+		return nil, nil, fmt.Errorf("parsing event list: %w", err)
 	}
 
 	if len(listItems) == 0 {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	// Get full details for all events
@@ -597,12 +655,12 @@ func queryDigestBeads(days int) ([]CostEntry, error) {
 	showCmd := exec.Command("bd", showArgs...)
 	showOutput, err := showCmd.Output()
 	if err != nil {
-		return nil, fmt.Errorf("showing events: %w", err)
+		return nil, nil, fmt.Errorf("showing events: %w", err)
 	}
 
 	var events []SessionEvent
 	if err := json.Unmarshal(showOutput, &events); err != nil {
-		return nil, fmt.Errorf("parsing event details: %w", err)
+		return nil, nil, fmt.Errorf("parsing event details: %w", err)
 	}
 
 	// Calculate date range
@@ -610,6 +668,7 @@ func queryDigestBeads(days int) ([]CostEntry, error) {
 	cutoff := now.AddDate(0, 0, -days)
 
 	var entries []CostEntry
+	var digests []CostDigest
 	for _, event := range events {
 		// Filter for costs.digest events only
 		if event.EventKind != "costs.digest" {
@@ -634,22 +693,14 @@ func queryDigestBeads(days int) ([]CostEntry, error) {
 		}
 
 		// If the digest has per-session data (old format), use it directly.
-		// Otherwise, synthesize entries from the aggregate ByRole data.
 		if len(digest.Sessions) > 0 {
 			entries = append(entries, digest.Sessions...)
 		} else {
-			for role, cost := range digest.ByRole {
-				entries = append(entries, CostEntry{
-					SessionID: fmt.Sprintf("digest-%s-%s", digest.Date, role),
-					Role:      role,
-					CostUSD:   cost,
-					EndedAt:   digestDate,
-				})
-			}
+			digests = append(digests, digest)
 		}
 	}
 
-	return entries, nil
+	return entries, digests, nil
 }
 
 // parseSessionName extracts role, rig, and worker from a session name.
@@ -699,144 +750,65 @@ func extractCost(content string) float64 {
 	return cost
 }
 
-// getClaudeProjectDir returns the Claude Code project directory for a working directory.
-// Claude Code stores transcripts in ~/.claude/projects/<path-with-dashes-instead-of-slashes>/
-func getClaudeProjectDir(workDir string) (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-
-	// Convert path to Claude's directory naming: replace / with -
-	// Keep leading slash - it becomes a leading dash in Claude's encoding
-	projectName := strings.ReplaceAll(workDir, "/", "-")
-	return filepath.Join(home, ".claude", "projects", projectName), nil
+// costContext holds the town settings used to price sessions.
+type costContext struct {
+	townRoot string
+	settings *config.TownSettings
+	prices   *costs.PriceTable
 }
 
-// findLatestTranscript finds the most recently modified .jsonl file in a directory.
-func findLatestTranscript(projectDir string) (string, error) {
-	var latestPath string
-	var latestTime time.Time
-
-	err := filepath.WalkDir(projectDir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-		if d.IsDir() && path != projectDir {
-			return fs.SkipDir // Don't recurse into subdirectories
-		}
-		if !d.IsDir() && strings.HasSuffix(path, ".jsonl") {
-			info, err := d.Info()
-			if err != nil {
-				return nil // Skip files we can't stat
-			}
-			if info.ModTime().After(latestTime) {
-				latestTime = info.ModTime()
-				latestPath = path
-			}
+func newCostContext(townRoot string) *costContext {
+	cc := &costContext{townRoot: townRoot, settings: config.NewTownSettings()}
+	if townRoot != "" {
+		if ts, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+			cc.settings = ts
 		}
-		return nil
-	})
-
-	if err != nil {
-		return "", err
-	}
-	if latestPath == "" {
-		return "", fmt.Errorf("no transcript files found in %s", projectDir)
 	}
-	return latestPath, nil
+	cc.prices = costs.NewPriceTable(cc.settings.Pricing)
+	return cc
 }
 
-// parseTranscriptUsage reads a transcript file and sums token usage from assistant messages.
-func parseTranscriptUsage(transcriptPath string) (*TokenUsage, error) {
-	file, err := os.Open(transcriptPath)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
-	usage := &TokenUsage{}
-	scanner := bufio.NewScanner(file)
-	// Increase buffer for potentially large JSON lines
-	buf := make([]byte, 0, 256*1024)
-	scanner.Buffer(buf, 1024*1024)
-
-	for scanner.Scan() {
-		line := scanner.Bytes()
-		if len(line) == 0 {
-			continue
-		}
-
-		var msg TranscriptMessage
-		if err := json.Unmarshal(line, &msg); err != nil {
-			continue // Skip malformed lines
+// agentFor returns the agent preset whose transcripts a session writes.
+// name is the session's GT_AGENT; when empty, the role's configured agent
+// is used. Custom agents resolve through their provider or command.
+func (cc *costContext) agentFor(name, role, rig string) string {
+	if name == "" && cc.townRoot != "" && role != "" && role != "unknown" {
+		rigPath := ""
+		if rig != "" {
+			rigPath = filepath.Join(cc.townRoot, rig)
 		}
-
-		// Only process assistant messages with usage info
-		if msg.Type != "assistant" || msg.Message == nil || msg.Message.Usage == nil {
-			continue
+		name, _ = config.ResolveRoleAgentName(role, cc.townRoot, rigPath)
+	}
+	if name == "" {
+		return string(config.AgentClaude)
+	}
+	if rc, ok := cc.settings.Agents[name]; ok && rc != nil {
+		if rc.Provider != "" {
+			return rc.Provider
 		}
-
-		// Capture the model (use first one found, they should all be the same)
-		if usage.Model == "" && msg.Message.Model != "" {
-			usage.Model = msg.Message.Model
+		if rc.Command != "" {
+			return filepath.Base(rc.Command)
 		}
-
-		// Sum token usage
-		u := msg.Message.Usage
-		usage.InputTokens += u.InputTokens
-		usage.CacheCreationInputTokens += u.CacheCreationInputTokens
-		usage.CacheReadInputTokens += u.CacheReadInputTokens
-		usage.OutputTokens += u.OutputTokens
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, err
 	}
-
-	return usage, nil
+	return name
 }
 
-// calculateCost converts token usage to USD cost based on model pricing.
-func calculateCost(usage *TokenUsage) float64 {
-	if usage == nil {
-		return 0.0
-	}
-
-	// Look up pricing for the model
-	pricing, ok := modelPricing[usage.Model]
-	if !ok {
-		pricing = modelPricing["default"]
-	}
-
-	// Calculate cost (prices are per million tokens)
-	inputCost := float64(usage.InputTokens) / 1_000_000 * pricing.InputPerMillion
-	cacheReadCost := float64(usage.CacheReadInputTokens) / 1_000_000 * pricing.CacheReadPerMillion
-	cacheCreateCost := float64(usage.CacheCreationInputTokens) / 1_000_000 * pricing.CacheCreatePerMillion
-	outputCost := float64(usage.OutputTokens) / 1_000_000 * pricing.OutputPerMillion
-
-	return inputCost + cacheReadCost + cacheCreateCost + outputCost
+// pricedSession is a session's cost split by model.
+type pricedSession struct {
+	total   float64
+	model   string
+	byModel map[string]float64
 }
 
-// extractCostFromWorkDir extracts cost from Claude Code transcript for a working directory.
-// This reads the most recent transcript file and sums all token usage.
-func extractCostFromWorkDir(workDir string) (float64, error) {
-	projectDir, err := getClaudeProjectDir(workDir)
+// sessionCost reads the latest transcript the agent wrote for workDir and
+// prices it as of at.
+func (cc *costContext) sessionCost(agent, workDir string, at time.Time) (pricedSession, error) {
+	usage, err := costs.SessionUsage(agent, workDir)
 	if err != nil {
-		return 0, fmt.Errorf("getting project dir: %w", err)
+		return pricedSession{}, err
 	}
-
-	transcriptPath, err := findLatestTranscript(projectDir)
-	if err != nil {
-		return 0, fmt.Errorf("finding transcript: %w", err)
-	}
-
-	usage, err := parseTranscriptUsage(transcriptPath)
-	if err != nil {
-		return 0, fmt.Errorf("parsing transcript: %w", err)
-	}
-
-	return calculateCost(usage), nil
+	total, byModel := cc.prices.Cost(usage, at)
+	return pricedSession{total: total, model: usage.Model(), byModel: byModel}, nil
 }
 
 // getTmuxSessionWorkDir gets the current working directory of a tmux session.
@@ -899,7 +871,7 @@ func outputCostsHuman(costs []SessionCost, total float64) error {
 	return nil
 }
 
-func outputLedgerHuman(output CostsOutput, entries []CostEntry) error {
+func outputLedgerHuman(output CostsOutput, sessionCount int) error {
 	periodStr := ""
 	if output.Period != "" {
 		periodStr = fmt.Sprintf(" (%s)", output.Period)
@@ -927,34 +899,47 @@ func outputLedgerHuman(output CostsOutput, entries []CostEntry) error {
 		}
 	}
 
+	// By agent and model breakdowns
+	printCostSplit("By Agent:", output.ByAgent)
+	printCostSplit("By Model:", output.ByModel)
+
 	// Session count
-	fmt.Printf("\n%s %d sessions\n", style.Dim.Render("Entries:"), len(entries))
+	fmt.Printf("\n%s %d sessions\n", style.Dim.Render("Entries:"), sessionCount)
 
 	return nil
 }
 
-// CostLogEntry represents a single entry in the costs.jsonl log file.
-type CostLogEntry struct {
-	SessionID string    `json:"session_id"`
-	Role      string    `json:"role"`
-	Rig       string    `json:"rig,omitempty"`
-	Worker    string    `json:"worker,omitempty"`
-	CostUSD   float64   `json:"cost_usd"`
-	EndedAt   time.Time `json:"ended_at"`
-	WorkItem  string    `json:"work_item,omitempty"`
+// printCostSplit prints a breakdown, most expensive first.
+func printCostSplit(title string, split map[string]float64) {
+	if len(split) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(split))
+	for k := range split {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if split[keys[i]] != split[keys[j]] {
+			return split[keys[i]] > split[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	fmt.Printf("\n%s\n", style.Bold.Render(title))
+	for _, k := range keys {
+		fmt.Printf("  %-28s $%.2f\n", k, split[k])
+	}
 }
 
+// CostLogEntry represents a single entry in the costs.jsonl log file.
+type CostLogEntry = costs.LedgerEntry
+
 // getCostsLogPath returns the path to the costs log file (~/.gt/costs.jsonl).
 func getCostsLogPath() string {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "/tmp/gt-costs.jsonl" // Fallback
-	}
-	return filepath.Join(home, ".gt", "costs.jsonl")
+	return costs.LedgerPath()
 }
 
 // runCostsRecord captures the final cost from a session and appends it to a local log file.
-// This is called by the Claude Code Stop hook. It's designed to never fail due to
+// This is called by the agent's Stop hook. It's designed to never fail due to
 // database availability - it's a simple file append operation.
 func runCostsRecord(cmd *cobra.Command, args []string) error {
 	// Get session from flag or try to detect from environment
@@ -987,21 +972,48 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Extract cost from Claude transcript
-	var cost float64
+	// Parse session name
+	role, rig, worker := parseSessionName(session)
+
+	// Extract cost from the agent's transcript
+	townRoot, _ := workspace.FindFromCwd()
+	cc := newCostContext(townRoot)
+	agent := cc.agentFor(os.Getenv("GT_AGENT"), role, rig)
+	now := time.Now()
+	var sc pricedSession
 	if workDir != "" {
 		var err error
-		cost, err = extractCostFromWorkDir(workDir)
+		sc, err = cc.sessionCost(agent, workDir, now)
 		if err != nil {
 			if costsVerbose {
 				fmt.Fprintf(os.Stderr, "[costs] could not extract cost from transcript: %v\n", err)
 			}
-			cost = 0.0
 		}
 	}
+	cost := sc.total
 
-	// Parse session name
-	role, rig, worker := parseSessionName(session)
+	// Attribute the session to its convoy when convoy budgets need it.
+	// Looking up the hooked bead costs a few bd calls, so skip it otherwise.
+	workItem := recordWorkItem
+	var convoy string
+	if townRoot != "" && hasConvoyBudgets(townRoot) {
+		if workItem == "" && role == constants.RolePolecat && workDir != "" {
+			if info, err := GetRoleWithContext(workDir, townRoot); err == nil {
+				workItem = detectHookedBead(workDir, info)
+			}
+		}
+		if workItem != "" {
+			convoy = isTrackedByConvoy(workItem)
+		}
+	}
+
+	// Attribute the session to its quota account for usage forecasts.
+	var account string
+	if townRoot != "" {
+		if acctCfg, err := config.LoadAccountsConfig(constants.MayorAccountsPath(townRoot)); err == nil {
+			account = quota.AccountForConfigDir(acctCfg, os.Getenv("CLAUDE_CONFIG_DIR"))
+		}
+	}
 
 	// Build log entry
 	entry := CostLogEntry{
@@ -1009,9 +1021,14 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 		Role:      role,
 		Rig:       rig,
 		Worker:    worker,
+		Agent:     agent,
+		Model:     sc.model,
+		ByModel:   sc.byModel,
 		CostUSD:   cost,
-		EndedAt:   time.Now(),
-		WorkItem:  recordWorkItem,
+		EndedAt:   now,
+		WorkItem:  workItem,
+		Convoy:    convoy,
+		Account:   account,
 	}
 
 	// Marshal to JSON
@@ -1055,6 +1072,17 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// hasConvoyBudgets reports whether any budget rule is scoped to convoys.
+func hasConvoyBudgets(townRoot string) bool {
+	rules, _ := costs.LoadBudgetRules(townRoot)
+	for _, r := range rules {
+		if r.EffectiveScope() == config.BudgetScopeConvoy {
+			return true
+		}
+	}
+	return false
+}
+
 // deriveSessionName derives the tmux session name from GT_* environment variables.
 // Uses session.* helpers for canonical naming. Parses GT_ROLE via parseRoleString
 // so compound forms (e.g. "gastown/witness") resolve to their canonical session names.
@@ -1133,6 +1161,8 @@ type CostDigest struct {
 	Sessions     []CostEntry        `json:"sessions,omitempty"`
 	ByRole       map[string]float64 `json:"by_role"`
 	ByRig        map[string]float64 `json:"by_rig,omitempty"`
+	ByAgent      map[string]float64 `json:"by_agent,omitempty"`
+	ByModel      map[string]float64 `json:"by_model,omitempty"`
 }
 
 // CostDigestPayload is the compact payload stored in the bead.
@@ -1143,6 +1173,8 @@ type CostDigestPayload struct {
 	SessionCount int                `json:"session_count"`
 	ByRole       map[string]float64 `json:"by_role"`
 	ByRig        map[string]float64 `json:"by_rig,omitempty"`
+	ByAgent      map[string]float64 `json:"by_agent,omitempty"`
+	ByModel      map[string]float64 `json:"by_model,omitempty"`
 }
 
 // runCostsDigest aggregates session cost entries into a daily digest bead.
@@ -1176,20 +1208,16 @@ func runCostsDigest(cmd *cobra.Command, args []string) error {
 	}
 
 	// Build digest
+	totals := aggregateCosts(costEntries, nil)
 	digest := CostDigest{
-		Date:     dateStr,
-		Sessions: costEntries,
-		ByRole:   make(map[string]float64),
-		ByRig:    make(map[string]float64),
-	}
-
-	for _, e := range costEntries {
-		digest.TotalUSD += e.CostUSD
-		digest.SessionCount++
-		digest.ByRole[e.Role] += e.CostUSD
-		if e.Rig != "" {
-			digest.ByRig[e.Rig] += e.CostUSD
-		}
+		Date:         dateStr,
+		TotalUSD:     totals.Total,
+		SessionCount: len(costEntries),
+		Sessions:     costEntries,
+		ByRole:       totals.ByRole,
+		ByRig:        totals.ByRig,
+		ByAgent:      totals.ByAgent,
+		ByModel:      totals.ByModel,
 	}
 
 	if digestDryRun {
@@ -1206,6 +1234,14 @@ func runCostsDigest(cmd *cobra.Command, args []string) error {
 				fmt.Printf("    %s: $%.2f\n", rig, cost)
 			}
 		}
+		fmt.Printf("  By Agent:\n")
+		for agent, cost := range digest.ByAgent {
+			fmt.Printf("    %s: $%.2f\n", agent, cost)
+		}
+		fmt.Printf("  By Model:\n")
+		for model, cost := range digest.ByModel {
+			fmt.Printf("    %s: $%.2f\n", model, cost)
+		}
 		return nil
 	}
 
@@ -1272,9 +1308,13 @@ func querySessionCostEntries(targetDate time.Time) ([]CostEntry, error) {
 			Role:      logEntry.Role,
 			Rig:       logEntry.Rig,
 			Worker:    logEntry.Worker,
+			Agent:     logEntry.Agent,
+			Model:     logEntry.Model,
+			ByModel:   logEntry.ByModel,
 			CostUSD:   logEntry.CostUSD,
 			EndedAt:   logEntry.EndedAt,
 			WorkItem:  logEntry.WorkItem,
+			Convoy:    logEntry.Convoy,
 		})
 	}
 
@@ -1315,6 +1355,25 @@ func createCostDigestBead(digest CostDigest) (string, error) {
 		desc.WriteString("\n")
 	}
 
+	for _, split := range []struct {
+		title string
+		costs map[string]float64
+	}{{"By Agent", digest.ByAgent}, {"By Model", digest.ByModel}} {
+		if len(split.costs) == 0 {
+			continue
+		}
+		desc.WriteString("## " + split.title + "\n")
+		keys := make([]string, 0, len(split.costs))
+		for k := range split.costs {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			desc.WriteString(fmt.Sprintf("- %s: $%.2f\n", k, split.costs[k]))
+		}
+		desc.WriteString("\n")
+	}
+
 	// Build compact payload (aggregate only, no per-session details).
 	// Per-session details can be thousands of records and exceed Dolt column limits.
 	compactPayload := CostDigestPayload{
@@ -1323,6 +1382,8 @@ func createCostDigestBead(digest CostDigest) (string, error) {
 		SessionCount: digest.SessionCount,
 		ByRole:       digest.ByRole,
 		ByRig:        digest.ByRig,
+		ByAgent:      digest.ByAgent,
+		ByModel:      digest.ByModel,
 	}
 	payloadJSON, err := json.Marshal(compactPayload)
 	if err != nil {
@@ -1508,3 +1569,27 @@ func runCostsMigrate(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// runCostsPricing prints the merged model price table.
+func runCostsPricing(cmd *cobra.Command, args []string) error {
+	townRoot, _ := workspace.FindFromCwd()
+	prices := newCostContext(townRoot).prices.Prices()
+
+	if costsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(prices)
+	}
+
+	fmt.Printf("%-28s %-12s %9s %9s %9s %9s\n", "Model", "Effective", "Input", "Output", "CacheRd", "CacheWr")
+	fmt.Println(strings.Repeat("─", 82))
+	for _, p := range prices {
+		from := p.EffectiveFrom
+		if from == "" {
+			from = "-"
+		}
+		fmt.Printf("%-28s %-12s %9.3f %9.3f %9.3f %9.3f\n", p.Model, from, p.Input, p.Output, p.CacheRead, p.CacheWrite)
+	}
+	fmt.Println(style.Dim.Render("USD per million tokens. Override with \"pricing\" in settings/config.json."))
+	return nil
+}
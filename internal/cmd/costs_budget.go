@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/costs"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var costsBudgetCmd = &cobra.Command{
+	Use:   "budget",
+	Short: "Show cost budgets and today's spend against them",
+	Long: `Show the daily cost budgets configured in town and rig settings.
+
+Budgets are rules in the "budgets" list of settings/config.json (town) or
+<rig>/settings/config.json (rig). The daemon evaluates them every heartbeat
+against today's ~/.gt/costs.jsonl ledger and acts once when a limit is
+crossed:
+
+  "budgets": [
+    {"scope": "rig", "limit_usd": 50, "warn_at": 0.8,
+     "actions": ["escalate", "stop_sling"]},
+    {"scope": "session", "role": "polecat", "limit_usd": 10,
+     "actions": ["pause_polecats"]},
+    {"scope": "town", "limit_usd": 200,
+     "actions": ["escalate", "cost_tier"], "cost_tier": "economy"}
+  ]
+
+Scopes: town, rig (default), convoy, session.
+Actions: escalate (default), stop_sling, cost_tier, pause_polecats.
+
+Holds from stop_sling and pause_polecats last until the day ends or the
+breach is cleared. So does cost_tier: the daemon saves the role_agents it
+replaces and puts them back on its next heartbeat after that.
+
+Examples:
+  gt costs budget
+  gt costs budget --json
+  gt costs budget clear rig-gastown-50:gastown`,
+	RunE: runCostsBudget,
+}
+
+var costsBudgetClearCmd = &cobra.Command{
+	Use:   "clear <key>",
+	Short: "Lift the holds of a budget breach for the rest of the day",
+	Long: `Lift the holds of a budget breach for the rest of the day.
+
+The key is shown by 'gt costs budget'. A cleared breach no longer stops
+gt sling or pauses polecats, and the daemon does not act on it again today.
+Role_agents it switched with cost_tier are restored on the daemon's next
+heartbeat.
+
+Examples:
+  gt costs budget clear rig-gastown-50:gastown`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCostsBudgetClear,
+}
+
+func init() {
+	costsCmd.AddCommand(costsBudgetCmd)
+	costsBudgetCmd.Flags().BoolVar(&costsJSON, "json", false, "Output as JSON")
+	costsBudgetCmd.AddCommand(costsBudgetClearCmd)
+}
+
+// BudgetStatus is one budget rule's spend for the JSON output of
+// 'gt costs budget'.
+type BudgetStatus struct {
+	Rule     string   `json:"rule"`
+	Scope    string   `json:"scope"`
+	Rig      string   `json:"rig,omitempty"`
+	Role     string   `json:"role,omitempty"`
+	Subject  string   `json:"subject,omitempty"`
+	SpentUSD float64  `json:"spent_usd"`
+	LimitUSD float64  `json:"limit_usd"`
+	Actions  []string `json:"actions,omitempty"`
+}
+
+// BudgetOutput is the JSON output of 'gt costs budget'.
+type BudgetOutput struct {
+	Budgets  []BudgetStatus `json:"budgets"`
+	Breaches []costs.Breach `json:"breaches,omitempty"`
+	Cleared  []string       `json:"cleared,omitempty"`
+}
+
+func runCostsBudget(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+	rules, errs := costs.LoadBudgetRules(townRoot)
+	entries, err := costs.ReadLedger(costs.LedgerPath())
+	if err != nil {
+		return fmt.Errorf("reading costs ledger: %w", err)
+	}
+	now := time.Now()
+	state, err := costs.LoadBudgetState(townRoot, now)
+	if err != nil {
+		return fmt.Errorf("reading budget state: %w", err)
+	}
+
+	// Rules with no spend yet still get a row.
+	spends := costs.EvaluateBudgets(rules, entries, now)
+	output := BudgetOutput{Breaches: state.Breaches, Cleared: state.Cleared}
+	for _, rule := range rules {
+		found := false
+		for _, s := range spends {
+			if s.Rule.RuleName() == rule.RuleName() && s.Rule.Rig == rule.Rig {
+				output.Budgets = append(output.Budgets, budgetStatus(rule, s.Subject, s.SpentUSD))
+				found = true
+			}
+		}
+		if !found {
+			output.Budgets = append(output.Budgets, budgetStatus(rule, "", 0))
+		}
+	}
+
+	if costsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(output)
+	}
+
+	for _, err := range errs {
+		fmt.Printf("%s %v\n", style.Warning.Render("⚠"), err)
+	}
+	if len(rules) == 0 {
+		fmt.Println(style.Dim.Render("No budgets configured. Add a \"budgets\" list to settings/config.json."))
+		return nil
+	}
+
+	fmt.Printf("\n%s Cost budgets for %s\n\n", style.Bold.Render("💰"), now.Format("2006-01-02"))
+	fmt.Printf("%-28s %-8s %-20s %10s %10s  %s\n", "Rule", "Scope", "Subject", "Spent", "Limit", "Actions")
+	fmt.Println(strings.Repeat("─", 96))
+	for _, b := range output.Budgets {
+		subject := b.Subject
+		if subject == "" {
+			subject = "-"
+		}
+		line := fmt.Sprintf("%-28s %-8s %-20s %10s %10s  %s", b.Rule, b.Scope, subject,
+			fmt.Sprintf("$%.2f", b.SpentUSD), fmt.Sprintf("$%.2f", b.LimitUSD), strings.Join(b.Actions, ","))
+		if b.SpentUSD >= b.LimitUSD {
+			line = style.Error.Render(line)
+		}
+		fmt.Println(line)
+	}
+
+	if len(state.Breaches) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Breaches today"))
+		for _, b := range state.Breaches {
+			status := style.Error.Render("over")
+			switch {
+			case state.IsCleared(b.Key):
+				status = style.Dim.Render("cleared")
+			case b.Warning:
+				status = style.Warning.Render("warning")
+			}
+			fmt.Printf("  %-36s %-8s $%.2f of $%.2f since %s\n", b.Key, status, b.SpentUSD, b.LimitUSD, b.At.Local().Format("15:04"))
+		}
+		fmt.Println(style.Dim.Render("\nLift a hold with 'gt costs budget clear <key>'."))
+	}
+	return nil
+}
+
+// budgetStatus builds the status row for a rule and subject.
+func budgetStatus(rule config.BudgetRule, subject string, spent float64) BudgetStatus {
+	actions := rule.Actions
+	if len(actions) == 0 {
+		actions = []string{config.BudgetActionEscalate}
+	}
+	return BudgetStatus{
+		Rule:     rule.RuleName(),
+		Scope:    rule.EffectiveScope(),
+		Rig:      rule.Rig,
+		Role:     rule.Role,
+		Subject:  subject,
+		SpentUSD: spent,
+		LimitUSD: rule.LimitUSD,
+		Actions:  actions,
+	}
+}
+
+func runCostsBudgetClear(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+	key := args[0]
+	state, err := costs.LoadBudgetState(townRoot, time.Now())
+	if err != nil {
+		return fmt.Errorf("reading budget state: %w", err)
+	}
+	if state.IsCleared(key) {
+		fmt.Printf("%s Budget %s is already cleared for today\n", style.Dim.Render("○"), key)
+		return nil
+	}
+	known := false
+	for _, b := range state.Breaches {
+		if b.Key == key {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return fmt.Errorf("no budget breach %q today (see 'gt costs budget')", key)
+	}
+	state.Cleared = append(state.Cleared, key)
+	if err := costs.SaveBudgetState(townRoot, state); err != nil {
+		return fmt.Errorf("saving budget state: %w", err)
+	}
+	fmt.Printf("%s Cleared budget %s for the rest of today\n", style.Success.Render("✓"), key)
+	return nil
+}
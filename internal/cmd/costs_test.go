@@ -264,3 +264,37 @@ func TestCostDigestPayload_ExcludesSessions(t *testing.T) {
 		t.Errorf("by_role should have 3 entries, got %d", len(asDigest.ByRole))
 	}
 }
+
+func TestAggregateCosts_ByAgentAndModel(t *testing.T) {
+	entries := []CostEntry{
+		{Role: "polecat", Rig: "gastown", Agent: "gemini", Model: "gemini-2.5-pro", CostUSD: 2},
+		{Role: "polecat", Rig: "gastown", Agent: "codex", Model: "gpt-5-codex", CostUSD: 3,
+			ByModel: map[string]float64{"gpt-5-codex": 2.5, "gpt-5-mini": 0.5}},
+		{Role: "mayor", CostUSD: 1}, // recorded before agents were tracked
+	}
+	digests := []CostDigest{
+		{TotalUSD: 4, SessionCount: 9, ByRole: map[string]float64{"witness": 4},
+			ByAgent: map[string]float64{"claude": 4}, ByModel: map[string]float64{"claude-haiku-4-5": 4}},
+		{TotalUSD: 5, SessionCount: 2, ByRole: map[string]float64{"crew": 5}}, // old digest, no split
+	}
+
+	out := aggregateCosts(entries, digests)
+	if out.Total != 15 {
+		t.Errorf("Total = %v, want 15", out.Total)
+	}
+	wantAgent := map[string]float64{"gemini": 2, "codex": 3, "claude": 10}
+	for k, v := range wantAgent {
+		if out.ByAgent[k] != v {
+			t.Errorf("ByAgent[%s] = %v, want %v (all: %v)", k, out.ByAgent[k], v, out.ByAgent)
+		}
+	}
+	wantModel := map[string]float64{"gemini-2.5-pro": 2, "gpt-5-codex": 2.5, "gpt-5-mini": 0.5, "claude-haiku-4-5": 4, "unknown": 6}
+	for k, v := range wantModel {
+		if out.ByModel[k] != v {
+			t.Errorf("ByModel[%s] = %v, want %v (all: %v)", k, out.ByModel[k], v, out.ByModel)
+		}
+	}
+	if out.ByRole["polecat"] != 5 || out.ByRole["crew"] != 5 || out.ByRig["gastown"] != 5 {
+		t.Errorf("ByRole = %v, ByRig = %v", out.ByRole, out.ByRig)
+	}
+}
@@ -18,8 +18,8 @@ import (
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/townlog"
+	"github.com/steveyegge/gastown/internal/witness"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -50,7 +50,7 @@ func runCrewRemove(cmd *cobra.Command, args []string) error {
 
 		// Check for running session (unless forced)
 		if !forceRemove {
-			t := tmux.NewTmux()
+			t := sessionBackend()
 			sessionID := crewSessionName(r.Name, name)
 			hasSession, _ := t.HasSession(sessionID)
 			if hasSession {
@@ -61,7 +61,7 @@ func runCrewRemove(cmd *cobra.Command, args []string) error {
 		}
 
 		// Kill session if it exists (with proper process cleanup to avoid orphans)
-		t := tmux.NewTmux()
+		t := sessionBackend()
 		sessionID := crewSessionName(r.Name, name)
 		if hasSession, _ := t.HasSession(sessionID); hasSession {
 			if err := t.KillSessionWithProcesses(sessionID); err != nil {
@@ -227,11 +227,13 @@ func runCrewRefresh(cmd *cobra.Command, args []string) error {
 
 	// Create and send mail
 	mailbox := mail.NewMailbox(mailDir)
-	msg := &mail.Message{
-		From:    fmt.Sprintf("%s/%s", r.Name, name),
-		To:      fmt.Sprintf("%s/%s", r.Name, name),
-		Subject: "🤝 HANDOFF: Context Refresh",
-		Body:    handoffMsg,
+	addr := fmt.Sprintf("%s/%s", r.Name, name)
+	msg, err := witness.NewHandoffMessage(addr, addr, &witness.HandoffPayload{
+		Topic: "Context Refresh",
+		Notes: handoffMsg,
+	})
+	if err != nil {
+		return fmt.Errorf("building handoff mail: %w", err)
 	}
 	if err := mailbox.Append(msg); err != nil {
 		return fmt.Errorf("sending handoff mail: %w", err)
@@ -572,7 +574,7 @@ func runCrewStop(cmd *cobra.Command, args []string) error {
 	}
 
 	var lastErr error
-	t := tmux.NewTmux()
+	t := sessionBackend()
 
 	for _, arg := range args {
 		name := arg
@@ -695,7 +697,7 @@ func runCrewStopAll() error {
 	fmt.Printf("%s Stopping %d crew session(s)...\n\n",
 		style.Bold.Render("🛑"), len(targets))
 
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	var succeeded, failed int
 	var failures []string
 
@@ -10,7 +10,6 @@ import (
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 )
 
 // CrewListItem represents a crew worker in list output.
@@ -52,7 +51,7 @@ func runCrewList(cmd *cobra.Command, args []string) error {
 	}
 
 	// Check session and git status for each worker
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	var items []CrewListItem
 
 	for _, r := range rigs {
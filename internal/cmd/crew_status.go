@@ -12,7 +12,6 @@ import (
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 )
 
 // CrewStatusItem represents detailed status for a crew worker.
@@ -80,7 +79,7 @@ func runCrewStatus(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	var items []CrewStatusItem
 
 	for _, w := range workers {
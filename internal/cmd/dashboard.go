@@ -2,23 +2,30 @@ package cmd
 
 import (
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/exec"
 	"runtime"
+	"strconv"
 	"time"
 
 	"golang.org/x/term"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/web"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 var (
-	dashboardPort int
-	dashboardOpen bool
+	dashboardPort     int
+	dashboardOpen     bool
+	dashboardBind     string
+	dashboardUserRole string
+	dashboardUserJSON bool
 )
 
 var dashboardCmd = &cobra.Command{
@@ -33,16 +40,67 @@ The dashboard shows real-time convoy status with:
 - Last activity indicator (green/yellow/red)
 - Auto-refresh every 30 seconds via htmx
 
+The dashboard listens on 127.0.0.1 unless dashboard.bind in
+settings/config.json or --bind says otherwise. Listening on any other
+address requires dashboard users: add one with 'gt dashboard user add',
+then log in with the printed token (or send it as a Bearer token).
+Viewers can read and run safe commands, but not read mail; operators can
+run every allowed command. Cross-origin callers must be listed in dashboard.allowed_origins.
+
 Example:
   gt dashboard              # Start on default port 8080
   gt dashboard --port 3000  # Start on port 3000
-  gt dashboard --open       # Start and open browser`,
+  gt dashboard --open       # Start and open browser
+  gt dashboard --bind 0.0.0.0  # Serve the LAN (requires users)`,
 	RunE: runDashboard,
 }
 
+var dashboardUserCmd = &cobra.Command{
+	Use:   "user",
+	Short: "Manage dashboard login accounts",
+	RunE:  requireSubcommand,
+}
+
+var dashboardUserAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add a dashboard user and print their token",
+	Long: `Add a dashboard user with a newly generated token.
+
+The token is printed once; only its hash is stored in settings/config.json.
+Adding an existing name replaces that user's token and role.
+
+Examples:
+  gt dashboard user add alice --role operator
+  gt dashboard user add ci-bot              # viewer`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDashboardUserAdd,
+}
+
+var dashboardUserListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List dashboard users",
+	Args:  cobra.NoArgs,
+	RunE:  runDashboardUserList,
+}
+
+var dashboardUserRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a dashboard user",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDashboardUserRemove,
+}
+
 func init() {
 	dashboardCmd.Flags().IntVar(&dashboardPort, "port", 8080, "HTTP port to listen on")
 	dashboardCmd.Flags().BoolVar(&dashboardOpen, "open", false, "Open browser automatically")
+	dashboardCmd.Flags().StringVar(&dashboardBind, "bind", "", "Address to listen on (default: dashboard.bind or 127.0.0.1)")
+	dashboardUserAddCmd.Flags().StringVar(&dashboardUserRole, "role", config.DashboardRoleViewer, "Role: viewer or operator")
+	dashboardUserListCmd.Flags().BoolVar(&dashboardUserJSON, "json", false, "Output as JSON")
+
+	dashboardUserCmd.AddCommand(dashboardUserAddCmd)
+	dashboardUserCmd.AddCommand(dashboardUserListCmd)
+	dashboardUserCmd.AddCommand(dashboardUserRemoveCmd)
+	dashboardCmd.AddCommand(dashboardUserCmd)
 	rootCmd.AddCommand(dashboardCmd)
 }
 
@@ -50,6 +108,7 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 	// Check if we're in a workspace - if not, run in setup mode
 	var handler http.Handler
 	var err error
+	dashCfg := config.DefaultDashboardConfig()
 
 	townRoot, wsErr := workspace.FindFromCwdOrError()
 	if wsErr != nil {
@@ -69,16 +128,30 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 		var webCfg *config.WebTimeoutsConfig
 		if ts, loadErr := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); loadErr == nil {
 			webCfg = ts.WebTimeouts
+			if ts.Dashboard != nil {
+				dashCfg = ts.Dashboard
+			}
 		} else {
 			fmt.Fprintf(cmd.ErrOrStderr(), "warning: loading town settings: %v (using defaults)\n", loadErr)
 		}
 
-		handler, err = web.NewDashboardMux(fetcher, webCfg)
+		handler, err = web.NewDashboardMux(fetcher, webCfg, web.NewAuth(dashCfg, events.LogAudit))
 		if err != nil {
 			return fmt.Errorf("creating dashboard handler: %w", err)
 		}
 	}
 
+	bind := dashCfg.Bind
+	if bind == "" {
+		bind = config.DefaultDashboardConfig().Bind
+	}
+	if cmd.Flags().Changed("bind") {
+		bind = dashboardBind
+	}
+	if !web.IsLoopbackBind(bind) && len(dashCfg.Users) == 0 {
+		return fmt.Errorf("refusing to listen on %q without dashboard users: run 'gt dashboard user add <name>' first, or use --bind 127.0.0.1", bind)
+	}
+
 	// Build the URL
 	url := fmt.Sprintf("http://localhost:%d", dashboardPort)
 
@@ -119,7 +192,7 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  launching dashboard at %s  •  api: %s/api/  •  ctrl+c to stop\n", url, url)
 
 	server := &http.Server{
-		Addr:              fmt.Sprintf(":%d", dashboardPort),
+		Addr:              net.JoinHostPort(bind, strconv.Itoa(dashboardPort)),
 		Handler:           handler,
 		ReadHeaderTimeout: 10 * time.Second,
 		ReadTimeout:       30 * time.Second,
@@ -144,3 +217,108 @@ func openBrowser(url string) {
 	}
 	_ = cmd.Start()
 }
+
+// loadDashboardSettings loads town settings for dashboard user management.
+func loadDashboardSettings() (string, *config.TownSettings, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", nil, err
+	}
+	path := config.TownSettingsPath(townRoot)
+	ts, err := config.LoadOrCreateTownSettings(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	if ts.Dashboard == nil {
+		ts.Dashboard = config.DefaultDashboardConfig()
+	}
+	return path, ts, nil
+}
+
+func runDashboardUserAdd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if dashboardUserRole != config.DashboardRoleViewer && dashboardUserRole != config.DashboardRoleOperator {
+		return fmt.Errorf("invalid role %q: must be %s or %s", dashboardUserRole, config.DashboardRoleViewer, config.DashboardRoleOperator)
+	}
+	path, ts, err := loadDashboardSettings()
+	if err != nil {
+		return err
+	}
+
+	token := web.GenerateToken()
+	hash, err := web.HashToken(token)
+	if err != nil {
+		return fmt.Errorf("hashing token: %w", err)
+	}
+	user := config.DashboardUser{Name: name, Role: dashboardUserRole, TokenHash: hash}
+	replaced := false
+	for i := range ts.Dashboard.Users {
+		if ts.Dashboard.Users[i].Name == name {
+			ts.Dashboard.Users[i] = user
+			replaced = true
+		}
+	}
+	if !replaced {
+		ts.Dashboard.Users = append(ts.Dashboard.Users, user)
+	}
+	if err := config.SaveTownSettings(path, ts); err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+
+	verb := "Added"
+	if replaced {
+		verb = "Updated"
+	}
+	fmt.Printf("%s %s dashboard user %s (%s)\n", style.Success.Render("✓"), verb, style.Bold.Render(name), dashboardUserRole)
+	fmt.Printf("  Token: %s\n", token)
+	fmt.Println(style.Dim.Render("  Store it now; it is not shown again. Restart gt dashboard to apply."))
+	return nil
+}
+
+func runDashboardUserList(cmd *cobra.Command, args []string) error {
+	_, ts, err := loadDashboardSettings()
+	if err != nil {
+		return err
+	}
+	type userInfo struct {
+		Name string `json:"name"`
+		Role string `json:"role"`
+	}
+	users := make([]userInfo, 0, len(ts.Dashboard.Users))
+	for _, u := range ts.Dashboard.Users {
+		users = append(users, userInfo{Name: u.Name, Role: u.Role})
+	}
+	if dashboardUserJSON {
+		return outputJSON(users)
+	}
+	if len(users) == 0 {
+		fmt.Println(style.Dim.Render("No dashboard users (authentication is off; dashboard is local-only)"))
+		return nil
+	}
+	for _, u := range users {
+		fmt.Printf("%s  %s\n", style.Bold.Render(u.Name), u.Role)
+	}
+	return nil
+}
+
+func runDashboardUserRemove(cmd *cobra.Command, args []string) error {
+	path, ts, err := loadDashboardSettings()
+	if err != nil {
+		return err
+	}
+	kept := ts.Dashboard.Users[:0]
+	for _, u := range ts.Dashboard.Users {
+		if u.Name != args[0] {
+			kept = append(kept, u)
+		}
+	}
+	if len(kept) == len(ts.Dashboard.Users) {
+		return fmt.Errorf("no dashboard user %q", args[0])
+	}
+	ts.Dashboard.Users = kept
+	if err := config.SaveTownSettings(path, ts); err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+	fmt.Printf("%s Removed dashboard user %s\n", style.Success.Render("✓"), style.Bold.Render(args[0]))
+	return nil
+}
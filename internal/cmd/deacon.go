@@ -492,7 +492,7 @@ func init() {
 }
 
 func runDeaconStart(cmd *cobra.Command, args []string) error {
-	t := tmux.NewTmux()
+	t := sessionBackend()
 
 	sessionName := getDeaconSessionName()
 
@@ -517,7 +517,7 @@ func runDeaconStart(cmd *cobra.Command, args []string) error {
 }
 
 // startDeaconSession creates and initializes the Deacon tmux session.
-func startDeaconSession(t *tmux.Tmux, sessionName, agentOverride string) error {
+func startDeaconSession(t session.Backend, sessionName, agentOverride string) error {
 	// Find workspace root
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -590,7 +590,7 @@ func startDeaconSession(t *tmux.Tmux, sessionName, agentOverride string) error {
 }
 
 func runDeaconStop(cmd *cobra.Command, args []string) error {
-	t := tmux.NewTmux()
+	t := sessionBackend()
 
 	sessionName := getDeaconSessionName()
 
@@ -620,7 +620,7 @@ func runDeaconStop(cmd *cobra.Command, args []string) error {
 }
 
 func runDeaconAttach(cmd *cobra.Command, args []string) error {
-	t := tmux.NewTmux()
+	t := sessionBackend()
 
 	sessionName := getDeaconSessionName()
 
@@ -662,7 +662,7 @@ type HeartbeatStatus struct {
 }
 
 func runDeaconStatus(cmd *cobra.Command, args []string) error {
-	t := tmux.NewTmux()
+	t := sessionBackend()
 
 	sessionName := getDeaconSessionName()
 	townRoot, _ := workspace.FindFromCwdOrError()
@@ -779,7 +779,7 @@ func runDeaconStatus(cmd *cobra.Command, args []string) error {
 }
 
 func runDeaconRestart(cmd *cobra.Command, args []string) error {
-	t := tmux.NewTmux()
+	t := sessionBackend()
 
 	sessionName := getDeaconSessionName()
 
@@ -934,7 +934,7 @@ func runDeaconHealthCheck(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid agent address: %w", err)
 	}
 
-	t := tmux.NewTmux()
+	t := sessionBackend()
 
 	// Check if session exists
 	exists, err := t.HasSession(sessionName)
@@ -1061,7 +1061,7 @@ func runDeaconForceKill(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid agent address: %w", err)
 	}
 
-	t := tmux.NewTmux()
+	t := sessionBackend()
 
 	// Check if session exists
 	exists, err := t.HasSession(sessionName)
@@ -15,7 +15,6 @@ import (
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/plugin"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -569,7 +568,7 @@ func runDogClear(cmd *cobra.Command, args []string) error {
 	// Check for live tmux session
 	if !dogForce {
 		sessionName := fmt.Sprintf("hq-dog-%s", name)
-		tm := tmux.NewTmux()
+		tm := sessionBackend()
 		if has, _ := tm.HasSession(sessionName); has {
 			return fmt.Errorf("dog %s has an active session (%s)\nUse --force to clear anyway", name, sessionName)
 		}
@@ -699,7 +698,7 @@ func showDogStatus(mgr *dog.Manager, name string) error {
 
 	// Check for tmux session
 	sessionName := fmt.Sprintf("hq-dog-%s", name)
-	tm := tmux.NewTmux()
+	tm := sessionBackend()
 	if has, _ := tm.HasSession(sessionName); has {
 		fmt.Printf("\nSession: %s (running)\n", sessionName)
 	}
@@ -948,6 +947,12 @@ func runDogDispatch(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("sending plugin mail to dog: %w", err)
 	}
 
+	// Record the dispatch so the daemon's gate evaluator doesn't dispatch
+	// the same plugin again before the dog records its run.
+	if err := plugin.MarkDispatched(townRoot, p.Name, time.Now()); err != nil && !dogDispatchJSON {
+		fmt.Printf("  Warning: could not record dispatch: %v\n", err)
+	}
+
 	// Success - output result
 	if dogDispatchJSON {
 		return json.NewEncoder(os.Stdout).Encode(result)
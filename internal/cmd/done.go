@@ -19,11 +19,12 @@ import (
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/townlog"
+	"github.com/steveyegge/gastown/internal/witness"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -764,6 +765,10 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 			if agentBeadID != "" {
 				description += fmt.Sprintf("\nagent_bead: %s", agentBeadID)
 			}
+			if convoyInfo != nil && convoyInfo.MergeStrategy == refinery.MergeStrategyPR {
+				// Refinery lands this MR through a forge pull request (gt mq pr)
+				description += fmt.Sprintf("\nmerge_strategy: %s", refinery.MergeStrategyPR)
+			}
 
 			// Add conflict resolution tracking fields (initialized, updated by Refinery)
 			description += "\nretry_count: 0"
@@ -930,11 +935,24 @@ afterDoltMerge:
 		bodyLines = append(bodyLines, fmt.Sprintf("Errors: %s", strings.Join(doneErrors, "; ")))
 	}
 
-	doneNotification := &mail.Message{
-		To:      witnessAddr,
-		From:    sender,
-		Subject: fmt.Sprintf("POLECAT_DONE %s", polecatName),
-		Body:    strings.Join(bodyLines, "\n"),
+	donePayload := &witness.PolecatDonePayload{
+		PolecatName: polecatName,
+		Exit:        exitType,
+		IssueID:     issueID,
+		MRID:        mrID,
+		Branch:      branch,
+		Errors:      strings.Join(doneErrors, "; "),
+	}
+	if convoyInfo != nil {
+		donePayload.ConvoyID = convoyInfo.ID
+		donePayload.ConvoyOwned = convoyInfo.Owned
+		donePayload.MergeStrategy = convoyInfo.MergeStrategy
+	}
+	doneNotification, err := witness.NewPolecatDoneMessage(sender, witnessAddr, donePayload)
+	if err != nil {
+		// The payload always marshals; keep the legacy body if it somehow doesn't.
+		style.PrintWarning("could not attach protocol envelope: %v", err)
+		doneNotification = mail.NewMessage(sender, witnessAddr, fmt.Sprintf("POLECAT_DONE %s", polecatName), strings.Join(bodyLines, "\n"))
 	}
 
 	fmt.Printf("\nNotifying Witness...\n")
@@ -1470,7 +1488,7 @@ func selfKillSession(townRoot string, roleInfo RoleInfo) error {
 	// We use KillSessionWithProcessesExcluding to ensure no orphaned processes are left behind,
 	// while excluding our own PID to avoid killing ourselves before cleanup completes.
 	// The tmux kill-session at the end will terminate us along with the session.
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	myPID := strconv.Itoa(os.Getpid())
 	if err := t.KillSessionWithProcessesExcluding(sessionName, []string{myPID}); err != nil {
 		return fmt.Errorf("killing session %s: %w", sessionName, err)
@@ -91,8 +91,10 @@ func runDown(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
-	t := tmux.NewTmux()
-	if !t.IsAvailable() {
+	// An unreachable PTY supervisor just means its sessions are already
+	// gone, so only tmux has to be present to shut down.
+	t := session.NewBackend(townRoot)
+	if !session.UsePTY(townRoot) && !t.IsAvailable() {
 		return fmt.Errorf("tmux not available (is tmux installed and on PATH?)")
 	}
 
@@ -115,7 +117,7 @@ func runDown(cmd *cobra.Command, args []string) error {
 		// By default, tmux exits when there are no sessions (exit-empty on).
 		// This ensures the server stays running for subsequent `gt up`.
 		// Ignore errors - if there's no server, nothing to configure.
-		_ = t.SetExitEmpty(false)
+		_ = tmux.NewTmux().SetExitEmpty(false)
 	}
 	allOK := true
 
@@ -304,7 +306,7 @@ func runDown(cmd *cobra.Command, args []string) error {
 			fmt.Printf("To proceed, run with: %s\n", style.Bold.Render("GT_NUKE_ACKNOWLEDGED=1 gt down --nuke"))
 			allOK = false
 		} else {
-			if err := t.KillServer(); err != nil {
+			if err := tmux.NewTmux().KillServer(); err != nil {
 				printDownStatus("Tmux server", false, err.Error())
 				allOK = false
 			} else {
@@ -347,7 +349,7 @@ func runDown(cmd *cobra.Command, args []string) error {
 
 // stopAllPolecats stops all polecat sessions across all rigs.
 // Returns the number of polecats stopped (or would be stopped in dry-run).
-func stopAllPolecats(t *tmux.Tmux, townRoot string, rigNames []string, force bool, dryRun bool) int {
+func stopAllPolecats(t session.Backend, townRoot string, rigNames []string, force bool, dryRun bool) int {
 	stopped := 0
 
 	// Load rigs config
@@ -404,7 +406,7 @@ func printDownStatus(name string, ok bool, detail string) {
 
 // stopSession gracefully stops a tmux session.
 // Returns (wasRunning, error) - wasRunning is true if session existed and was stopped.
-func stopSession(t *tmux.Tmux, sessionName string) (bool, error) {
+func stopSession(t session.Backend, sessionName string) (bool, error) {
 	running, err := t.HasSession(sessionName)
 	if err != nil {
 		return false, err
@@ -453,7 +455,7 @@ func acquireShutdownLock(townRoot string) (*flock.Flock, error) {
 
 // verifyShutdown checks for respawned processes after shutdown.
 // Returns list of things that are still running or respawned.
-func verifyShutdown(t *tmux.Tmux, townRoot string) []string {
+func verifyShutdown(t session.Backend, townRoot string) []string {
 	var respawned []string
 
 	sessions, err := t.ListSessions()
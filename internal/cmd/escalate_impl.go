@@ -6,11 +6,13 @@ import (
 	"io"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/escalation"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
@@ -129,7 +131,13 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Process external notification actions (email:, sms:, slack)
-	executeExternalActions(actions, escalationConfig, issue.ID, severity, description)
+	outcomes := executeExternalActions(actions, escalationConfig, escalation.Notice{
+		BeadID:   issue.ID,
+		Severity: severity,
+		Title:    description,
+		Body:     formatEscalationMailBody(issue.ID, severity, escalateReason, agentID, escalateRelatedBead),
+	})
+	recordDeliveries(bd, issue.ID, outcomes)
 
 	// Log to activity feed
 	payload := events.EscalationPayload(issue.ID, agentID, strings.Join(targets, ","), description)
@@ -394,6 +402,16 @@ func runEscalateStale(cmd *cobra.Command, args []string) error {
 				}
 			}
 
+			// A bump to a severity with external routes (e.g. high → critical)
+			// should page humans the same way a fresh escalation would.
+			outcomes := executeExternalActions(actions, escalationConfig, escalation.Notice{
+				BeadID:   result.ID,
+				Severity: result.NewSeverity,
+				Title:    "Re-escalated: " + result.Title,
+				Body:     formatReescalationMailBody(result, reescalatedBy),
+			})
+			recordDeliveries(bd, result.ID, outcomes)
+
 			// Log to activity feed
 			_ = events.LogFeed(events.TypeEscalationSent, reescalatedBy, map[string]interface{}{
 				"escalation_id":    result.ID,
@@ -505,6 +523,7 @@ func runEscalateShow(cmd *cobra.Command, args []string) error {
 			"closedBy":    fields.ClosedBy,
 			"closedReason": fields.ClosedReason,
 			"relatedBead": fields.RelatedBead,
+			"deliveries":  fields.Deliveries,
 		}
 		out, _ := json.MarshalIndent(data, "", "  ")
 		fmt.Println(string(out))
@@ -531,6 +550,12 @@ func runEscalateShow(cmd *cobra.Command, args []string) error {
 	if fields.RelatedBead != "" {
 		fmt.Printf("  Related: %s\n", fields.RelatedBead)
 	}
+	if len(fields.Deliveries) > 0 {
+		fmt.Printf("  Deliveries:\n")
+		for _, d := range fields.Deliveries {
+			fmt.Printf("    %s\n", d)
+		}
+	}
 
 	return nil
 }
@@ -553,41 +578,79 @@ func extractMailTargetsFromActions(actions []string) []string {
 	return targets
 }
 
-// executeExternalActions processes external notification actions (email:, sms:, slack).
-// For now, this logs warnings if contacts aren't configured - actual sending is future work.
-func executeExternalActions(actions []string, cfg *config.EscalationConfig, _, _, _ string) {
+// executeExternalActions delivers the external notification actions
+// (email:, sms:, slack) in a route concurrently and reports each outcome.
+// Each action is bounded by the delivery deadline. Delivery failures are
+// warnings: the escalation bead and gt mail have already been created.
+func executeExternalActions(actions []string, cfg *config.EscalationConfig, notice escalation.Notice) []escalation.Outcome {
+	var external []string
 	for _, action := range actions {
-		switch {
-		case strings.HasPrefix(action, "email:"):
-			if cfg.Contacts.HumanEmail == "" {
-				style.PrintWarning("email action '%s' skipped: contacts.human_email not configured in settings/escalation.json", action)
-			} else {
-				// TODO: Implement actual email sending
-				fmt.Printf("  📧 Would send email to %s (not yet implemented)\n", cfg.Contacts.HumanEmail)
-			}
-
-		case strings.HasPrefix(action, "sms:"):
-			if cfg.Contacts.HumanSMS == "" {
-				style.PrintWarning("sms action '%s' skipped: contacts.human_sms not configured in settings/escalation.json", action)
-			} else {
-				// TODO: Implement actual SMS sending
-				fmt.Printf("  📱 Would send SMS to %s (not yet implemented)\n", cfg.Contacts.HumanSMS)
-			}
-
-		case action == "slack":
-			if cfg.Contacts.SlackWebhook == "" {
-				style.PrintWarning("slack action skipped: contacts.slack_webhook not configured in settings/escalation.json")
-			} else {
-				// TODO: Implement actual Slack webhook posting
-				fmt.Printf("  💬 Would post to Slack (not yet implemented)\n")
-			}
-
-		case action == "log":
+		if action == "log" {
 			// Log action always succeeds - writes to escalation log file
 			// TODO: Implement actual log file writing
 			fmt.Printf("  📝 Logged to escalation log\n")
+			continue
+		}
+		if escalation.IsExternal(action) {
+			external = append(external, action)
 		}
 	}
+	if len(external) == 0 {
+		return nil
+	}
+
+	deliverer := escalation.NewDeliverer(cfg)
+	outcomes := make([]escalation.Outcome, len(external))
+	var wg sync.WaitGroup
+	for i, action := range external {
+		wg.Add(1)
+		go func(i int, action string) {
+			defer wg.Done()
+			outcomes[i] = deliverer.Deliver(action, notice)
+		}(i, action)
+	}
+	wg.Wait()
+
+	for _, o := range outcomes {
+		switch o.Status {
+		case escalation.StatusSent:
+			fmt.Printf("  %s Delivered %s\n", externalActionEmoji(o.Action), o.Action)
+		case escalation.StatusSkipped:
+			style.PrintWarning("%s action skipped: %v in settings/escalation.json", o.Action, o.Err)
+		default:
+			style.PrintWarning("%s delivery failed after %d attempt(s): %v", o.Action, o.Attempts, o.Err)
+		}
+	}
+	return outcomes
+}
+
+// recordDeliveries stores external delivery outcomes on the escalation bead.
+func recordDeliveries(bd *beads.Beads, beadID string, outcomes []escalation.Outcome) {
+	if len(outcomes) == 0 {
+		return
+	}
+	entries := make([]string, 0, len(outcomes))
+	failed := false
+	for _, o := range outcomes {
+		entries = append(entries, o.String())
+		if o.Status == escalation.StatusFailed {
+			failed = true
+		}
+	}
+	if err := bd.RecordEscalationDeliveries(beadID, entries, failed); err != nil {
+		style.PrintWarning("could not record delivery outcome on %s: %v", beadID, err)
+	}
+}
+
+func externalActionEmoji(action string) string {
+	switch {
+	case strings.HasPrefix(action, "email:"):
+		return "📧"
+	case strings.HasPrefix(action, "sms:"):
+		return "📱"
+	default:
+		return "💬"
+	}
 }
 
 func formatEscalationMailBody(beadID, severity, reason, from, related string) string {
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -8,6 +10,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/escalation"
 )
 
 func TestGetNextSeverity(t *testing.T) {
@@ -332,23 +335,29 @@ func TestDetectSenderFallback(t *testing.T) {
 }
 
 func TestExecuteExternalActions(t *testing.T) {
-	// executeExternalActions prints warnings/info but doesn't return errors.
-	// We test that it doesn't panic with various configurations.
+	// Unconfigured channels are skipped; configured ones are delivered.
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer slack.Close()
 
 	tests := []struct {
 		name    string
 		actions []string
 		cfg     *config.EscalationConfig
+		want    []escalation.Status
 	}{
 		{
 			name:    "no external actions",
 			actions: []string{"bead", "mail:mayor"},
 			cfg:     &config.EscalationConfig{},
+			want:    nil,
 		},
 		{
 			name:    "email action without contact",
 			actions: []string{"email:human"},
 			cfg:     &config.EscalationConfig{},
+			want:    []escalation.Status{escalation.StatusSkipped},
 		},
 		{
 			name:    "email action with contact",
@@ -358,11 +367,13 @@ func TestExecuteExternalActions(t *testing.T) {
 					HumanEmail: "test@example.com",
 				},
 			},
+			want:    []escalation.Status{escalation.StatusSkipped},
 		},
 		{
 			name:    "sms action without contact",
 			actions: []string{"sms:human"},
 			cfg:     &config.EscalationConfig{},
+			want:    []escalation.Status{escalation.StatusSkipped},
 		},
 		{
 			name:    "sms action with contact",
@@ -372,25 +383,29 @@ func TestExecuteExternalActions(t *testing.T) {
 					HumanSMS: "+15551234567",
 				},
 			},
+			want:    []escalation.Status{escalation.StatusSkipped},
 		},
 		{
 			name:    "slack action without webhook",
 			actions: []string{"slack"},
 			cfg:     &config.EscalationConfig{},
+			want:    []escalation.Status{escalation.StatusSkipped},
 		},
 		{
 			name:    "slack action with webhook",
 			actions: []string{"slack"},
 			cfg: &config.EscalationConfig{
 				Contacts: config.EscalationContacts{
-					SlackWebhook: "https://hooks.slack.com/test",
+					SlackWebhook: slack.URL,
 				},
 			},
+			want:    []escalation.Status{escalation.StatusSent},
 		},
 		{
 			name:    "log action",
 			actions: []string{"log"},
 			cfg:     &config.EscalationConfig{},
+			want:    nil,
 		},
 		{
 			name:    "all external actions combined",
@@ -399,21 +414,34 @@ func TestExecuteExternalActions(t *testing.T) {
 				Contacts: config.EscalationContacts{
 					HumanEmail:   "test@example.com",
 					HumanSMS:     "+15551234567",
-					SlackWebhook: "https://hooks.slack.com/test",
+					SlackWebhook: slack.URL,
 				},
 			},
+			want:    []escalation.Status{escalation.StatusSkipped, escalation.StatusSkipped, escalation.StatusSent},
 		},
 		{
 			name:    "empty actions",
 			actions: []string{},
 			cfg:     &config.EscalationConfig{},
+			want:    nil,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Should not panic
-			executeExternalActions(tt.actions, tt.cfg, "hq-test", "high", "Test escalation")
+			outcomes := executeExternalActions(tt.actions, tt.cfg, escalation.Notice{
+				BeadID:   "hq-test",
+				Severity: "high",
+				Title:    "Test escalation",
+			})
+			if len(outcomes) != len(tt.want) {
+				t.Fatalf("got %d outcomes, want %d: %+v", len(outcomes), len(tt.want), outcomes)
+			}
+			for i, o := range outcomes {
+				if o.Status != tt.want[i] {
+					t.Errorf("outcome[%d] (%s) = %s (%v), want %s", i, o.Action, o.Status, o.Err, tt.want[i])
+				}
+			}
 		})
 	}
 }
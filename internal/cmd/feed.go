@@ -63,11 +63,11 @@ Problems View (--problems/-p):
   - Press 'p' to toggle between activity and problems view
 
 The feed combines multiple event sources:
-  - GT events: Agent activity like patrol, sling, handoff (from .events.jsonl)
+  - GT events: Agent activity like patrol, sling, handoff (from the .events/ store)
   - Beads activity: Issue creates, updates, completions (from bd activity, when available)
   - Convoy status: In-progress and recently-landed convoys (refreshes every 10s)
 
-Use --plain for simple text output (reads .events.jsonl directly).
+Use --plain for simple text output (reads the event store directly).
 
 Tmux Integration:
   Use --window to open the feed in a dedicated tmux window named 'feed'.
@@ -204,7 +204,7 @@ func buildFeedArgs() []string {
 	return args
 }
 
-// runFeedDirect prints events from .events.jsonl to stdout.
+// runFeedDirect prints events from the event store to stdout.
 // Supports --follow for tailing, and --since/--mol/--type for filtering.
 // townRoot is the resolved workspace root (incorporates --rig if set).
 func runFeedDirect(townRoot string) error {
@@ -259,7 +259,7 @@ func runFeedTUI(workDir string, problemsView bool) error {
 	}
 
 	if len(sources) == 0 {
-		return fmt.Errorf("no event sources available (check that .events.jsonl exists in %s)", townRoot)
+		return fmt.Errorf("no event sources available (check that the event store exists in %s)", townRoot)
 	}
 
 	// Combine all sources
@@ -30,6 +30,7 @@ var (
 	formulaRunPR      int
 	formulaRunRig     string
 	formulaRunDryRun  bool
+	formulaRunVars    []string
 	formulaCreateType string
 )
 
@@ -51,31 +52,44 @@ Commands:
   run     Execute a formula (pour and dispatch)
   create  Create a new formula template
 
-Search paths (in order):
-  1. .beads/formulas/ (project)
-  2. ~/.beads/formulas/ (user)
-  3. $GT_ROOT/.beads/formulas/ (orchestrator)
+Registry (see 'gt formula install --help'):
+  install    Install a formula from the registry
+  upgrade    Upgrade installed formulas
+  pin        Pin an installed formula to a version
+  uninstall  Remove an installed formula
+
+Formulas resolve from three tiers (first match wins):
+  1. project  <rig>/.beads/formulas/
+  2. town     <town>/.beads/formulas/
+  3. system   formulas embedded in gt
 
 Examples:
   gt formula list                    # List all formulas
   gt formula show shiny              # Show formula details
   gt formula run shiny --pr=123      # Run formula on PR #123
-  gt formula create my-workflow      # Create new formula template`,
+  gt formula create my-workflow      # Create new formula template
+  gt formula install mol-code-review # Install from the registry`,
 }
 
 var formulaListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List available formulas",
-	Long: `List available formulas from all search paths.
+	Long: `List available formulas with the tier and version each resolves to.
+
+Formula files (.formula.toml, .formula.json) are searched for in:
+  1. project  <rig>/.beads/formulas/
+  2. town     <town>/.beads/formulas/
+  3. system   formulas embedded in gt
 
-Searches for formula files (.formula.toml, .formula.json) in:
-  1. .beads/formulas/ (project)
-  2. ~/.beads/formulas/ (user)
-  3. $GT_ROOT/.beads/formulas/ (orchestrator)
+A formula in a higher tier overrides the same name below it. The notes
+column shows what each one overrides, and warns when the overridden copy
+has a newer version than the override.
 
 Examples:
-  gt formula list            # List all formulas
-  gt formula list --json     # JSON output`,
+  gt formula list              # List all formulas
+  gt formula list --tier town  # Only the town's formulas
+  gt formula list --json       # JSON output
+  gt formula list --installed  # Formulas installed from a registry`,
 	RunE: runFormulaList,
 }
 
@@ -90,9 +104,20 @@ Shows:
   - Steps with dependencies
   - Composition rules (extends, aspects)
 
+With --resolve, prints the search path walked (project, town, system) and
+which file the name resolves to instead.
+
+With --expanded, prints the formula as TOML with its composition flattened:
+extended formulas merged, includes prefixed, removals, expansions and
+aspects applied. gt sling hands this flattened form to bd cook, so it is the
+workflow agents get (step control keys in it are advisory).
+
 Examples:
   gt formula show shiny
-  gt formula show rule-of-five --json`,
+  gt formula show rule-of-five --json
+  gt formula show mol-polecat-work --resolve
+  gt formula show mol-polecat-work --resolve --tier system
+  gt formula show shiny-secure --expanded`,
 	Args: cobra.ExactArgs(1),
 	RunE: runFormulaShow,
 }
@@ -115,6 +140,8 @@ the rig's settings/config.json under workflow.default_formula.
 Options:
   --pr=N      Run formula on GitHub PR #N
   --rig=NAME  Target specific rig (default: current or gastown)
+  --tier=TIER Resolve the formula from one tier (project, town, system)
+  --var=K=V   Set a formula variable (validated against its declared type)
   --dry-run   Show what would happen without executing
 
 Examples:
@@ -122,7 +149,8 @@ Examples:
   gt formula run                          # Run default formula from rig config
   gt formula run shiny --pr=123           # Run on PR #123
   gt formula run security-audit --rig=beads  # Run in specific rig
-  gt formula run release --dry-run        # Preview execution`,
+  gt formula run release --dry-run        # Preview execution
+  gt formula run digest --var period=weekly  # Set a typed variable`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runFormulaRun,
 }
@@ -151,14 +179,21 @@ Examples:
 func init() {
 	// List flags
 	formulaListCmd.Flags().BoolVar(&formulaListJSON, "json", false, "Output as JSON")
+	formulaListCmd.Flags().StringVar(&formulaTier, "tier", "", "Only list formulas from this tier: project, town, or system")
 
 	// Show flags
 	formulaShowCmd.Flags().BoolVar(&formulaShowJSON, "json", false, "Output as JSON")
+	formulaShowCmd.Flags().BoolVar(&formulaShowResolve, "resolve", false, "Show the search path and which tier the formula resolves from")
+	formulaShowCmd.Flags().BoolVar(&formulaShowExpanded, "expanded", false, "Print the formula with extends, include and compose flattened")
+	formulaShowCmd.Flags().StringVar(&formulaTier, "tier", "", "Resolve from this tier only: project, town, or system")
 
 	// Run flags
 	formulaRunCmd.Flags().IntVar(&formulaRunPR, "pr", 0, "GitHub PR number to run formula on")
 	formulaRunCmd.Flags().StringVar(&formulaRunRig, "rig", "", "Target rig (default: current or gastown)")
 	formulaRunCmd.Flags().BoolVar(&formulaRunDryRun, "dry-run", false, "Preview execution without running")
+	formulaRunCmd.Flags().StringArrayVar(&formulaRunVars, "var", nil, "Formula variable (key=value), checked against the formula's var types; can be repeated")
+	_ = formulaRunCmd.RegisterFlagCompletionFunc("var", completeFormulaVars)
+	formulaRunCmd.Flags().StringVar(&formulaTier, "tier", "", "Resolve the formula from this tier only: project, town, or system")
 
 	// Create flags
 	formulaCreateCmd.Flags().StringVar(&formulaCreateType, "type", "task", "Formula type: task, workflow, or patrol")
@@ -172,22 +207,23 @@ func init() {
 	rootCmd.AddCommand(formulaCmd)
 }
 
-// runFormulaList delegates to bd formula list
+// runFormulaList lists formulas across the resolution tiers
 func runFormulaList(cmd *cobra.Command, args []string) error {
-	bdArgs := []string{"formula", "list"}
-	if formulaListJSON {
-		bdArgs = append(bdArgs, "--json")
+	if formulaListInstalled {
+		return runFormulaListInstalled()
 	}
-
-	bdCmd := exec.Command("bd", bdArgs...)
-	bdCmd.Stdout = os.Stdout
-	bdCmd.Stderr = os.Stderr
-	return bdCmd.Run()
+	return runFormulaListTiers()
 }
 
 // runFormulaShow delegates to bd formula show
 func runFormulaShow(cmd *cobra.Command, args []string) error {
 	formulaName := args[0]
+	if formulaShowExpanded {
+		return runFormulaShowExpanded(formulaName)
+	}
+	if formulaShowResolve || formulaTier != "" {
+		return runFormulaShowResolve(formulaName)
+	}
 	bdArgs := []string{"formula", "show", formulaName}
 	if formulaShowJSON {
 		bdArgs = append(bdArgs, "--json")
@@ -249,20 +285,32 @@ func runFormulaRun(cmd *cobra.Command, args []string) error {
 		fmt.Printf("%s Using default formula: %s\n", style.Dim.Render("Note:"), formulaName)
 	}
 
-	// Find the formula file
-	formulaPath, err := findFormulaFile(formulaName)
+	// Find the formula (project, town, then system tier)
+	resolved, err := resolveFormula(formulaName, rigPath)
 	if err != nil {
 		return fmt.Errorf("finding formula: %w", err)
 	}
 
 	// Parse the formula
-	f, err := parseFormulaFile(formulaPath)
+	f, err := resolved.Parse()
 	if err != nil {
 		return fmt.Errorf("parsing formula: %w", err)
 	}
 
+	// Check --var values against the formula's declared var types
+	vars, err := parseFormulaVars(formulaRunVars)
+	if err != nil {
+		return err
+	}
+	townRoot, _ := workspace.FindFromCwd()
+	if err := f.ValidateVars(vars, formulaVarLookup{townRoot: townRoot}); err != nil {
+		return err
+	}
+
 	// Handle dry-run mode
 	if formulaRunDryRun {
+		fmt.Printf("%s Formula %s resolved from %s tier (v%d): %s\n",
+			style.Dim.Render("[dry-run]"), formulaName, resolved.Tier, resolved.Version, resolved.Path)
 		return dryRunFormula(f, formulaName, targetRig)
 	}
 
@@ -274,7 +322,11 @@ func runFormulaRun(cmd *cobra.Command, args []string) error {
 		fmt.Printf("\nTo run '%s' manually:\n", formulaName)
 		fmt.Printf("  1. View formula:   gt formula show %s\n", formulaName)
 		fmt.Printf("  2. Cook to proto:  bd cook %s\n", formulaName)
-		fmt.Printf("  3. Pour molecule:  bd pour %s\n", formulaName)
+		pourVars := ""
+		for _, v := range formulaRunVars {
+			pourVars += fmt.Sprintf(" --var %q", v)
+		}
+		fmt.Printf("  3. Pour molecule:  bd pour %s%s\n", formulaName, pourVars)
 		fmt.Printf("  4. Sling to rig:   gt sling <mol-id> %s\n", targetRig)
 		return nil
 	}
@@ -346,6 +398,7 @@ func dryRunFormula(f *formula.Formula, formulaName, targetRig string) error {
 						"description": leg.Description,
 					},
 					"changed_files": changedFiles,
+					"vars":          formulaRunVarValues(f),
 				}
 				legPattern := renderTemplateOrDefault(f.Output.LegPattern, legCtx, leg.ID+"-findings.md")
 				outputPath := filepath.Join(outputDir, legPattern)
@@ -479,6 +532,7 @@ func executeConvoyFormula(f *formula.Formula, formulaName, targetRig string) err
 						"description": leg.Description,
 					},
 					"changed_files": changedFiles,
+					"vars":          formulaRunVarValues(f),
 					"files":         []string{}, // TODO: support --files flag
 				}
 
@@ -634,45 +688,6 @@ func executeConvoyFormula(f *formula.Formula, formulaName, targetRig string) err
 	return nil
 }
 
-// findFormulaFile searches for a formula file by name
-func findFormulaFile(name string) (string, error) {
-	// Search paths in order
-	searchPaths := []string{}
-
-	// 1. Project .beads/formulas/
-	if cwd, err := os.Getwd(); err == nil {
-		searchPaths = append(searchPaths, filepath.Join(cwd, ".beads", "formulas"))
-	}
-
-	// 2. Town .beads/formulas/
-	if townRoot, err := workspace.FindFromCwd(); err == nil {
-		searchPaths = append(searchPaths, filepath.Join(townRoot, ".beads", "formulas"))
-	}
-
-	// 3. User ~/.beads/formulas/
-	if home, err := os.UserHomeDir(); err == nil {
-		searchPaths = append(searchPaths, filepath.Join(home, ".beads", "formulas"))
-	}
-
-	// Try each path with common extensions
-	extensions := []string{".formula.toml", ".formula.json"}
-	for _, basePath := range searchPaths {
-		for _, ext := range extensions {
-			path := filepath.Join(basePath, name+ext)
-			if _, err := os.Stat(path); err == nil {
-				return path, nil
-			}
-		}
-	}
-
-	return "", fmt.Errorf("formula '%s' not found in search paths", name)
-}
-
-// parseFormulaFile parses a formula file using the formula package's TOML parser.
-func parseFormulaFile(path string) (*formula.Formula, error) {
-	return formula.ParseFile(path)
-}
-
 // renderTemplate renders a Go text/template with the given context map
 func renderTemplate(tmplText string, ctx map[string]interface{}) (string, error) {
 	tmpl, err := template.New("prompt").Parse(tmplText)
@@ -0,0 +1,288 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Formula package manager flags
+var (
+	formulaRegistry       string
+	formulaInstallForce   bool
+	formulaInstallJSON    bool
+	formulaUpgradeForce   bool
+	formulaUpgradeJSON    bool
+	formulaPinUnpin       bool
+	formulaUninstallForce bool
+	formulaListInstalled  bool
+)
+
+var formulaInstallCmd = &cobra.Command{
+	Use:   "install <name>[@version]",
+	Short: "Install a formula from the registry",
+	Long: `Install a formula into the town from a formula registry.
+
+The registry is a static index.json plus release tarballs, served from a
+directory or plain HTTP. It is set with formula_registry in the town's
+settings/config.json, or --registry.
+
+The formula is written to <town>/.beads/formulas/<name>.formula.toml after
+its tarball's SHA-256 is checked against the index. The installed version
+and checksums are recorded in <town>/.beads/formulas/.lock.json.
+
+Version specs:
+  name          Newest release
+  name@2        Newest 2.x.x
+  name@2.1      Newest 2.1.x
+  name@2.1.0    Exactly 2.1.0, pinned (upgrade leaves it alone)
+
+A formula file that was not installed from the registry, or was edited since,
+is only replaced with --force.
+
+Examples:
+  gt formula install mol-code-review
+  gt formula install mol-code-review@1.2.0
+  gt formula install mol-deploy --registry https://formulas.example.com`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFormulaInstall,
+}
+
+var formulaUpgradeCmd = &cobra.Command{
+	Use:   "upgrade [name...]",
+	Short: "Upgrade installed formulas to their newest release",
+	Long: `Upgrade registry-installed formulas to the newest release.
+
+With no names, every installed formula is checked. Pinned formulas and
+formulas edited since they were installed are skipped unless --force.
+
+Examples:
+  gt formula upgrade
+  gt formula upgrade mol-code-review
+  gt formula upgrade --force mol-code-review`,
+	RunE: runFormulaUpgrade,
+}
+
+var formulaPinCmd = &cobra.Command{
+	Use:   "pin <name>[@version]",
+	Short: "Pin an installed formula to a version",
+	Long: `Pin a registry-installed formula so upgrade leaves it alone.
+
+Without a version the installed version is pinned. With one, the matching
+release is installed and pinned.
+
+Examples:
+  gt formula pin mol-code-review
+  gt formula pin mol-code-review@1.2.0
+  gt formula pin --unpin mol-code-review`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFormulaPin,
+}
+
+var formulaUninstallCmd = &cobra.Command{
+	Use:   "uninstall <name>",
+	Short: "Remove a registry-installed formula",
+	Long: `Remove a formula installed with 'gt formula install' and its lockfile entry.
+
+A formula edited since it was installed is only removed with --force.
+
+Examples:
+  gt formula uninstall mol-code-review`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFormulaUninstall,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{formulaInstallCmd, formulaUpgradeCmd, formulaPinCmd} {
+		c.Flags().StringVar(&formulaRegistry, "registry", "", "Registry URL or directory (default: formula_registry in town settings)")
+	}
+	formulaInstallCmd.Flags().BoolVar(&formulaInstallForce, "force", false, "Replace a formula not installed from the registry or edited locally")
+	formulaInstallCmd.Flags().BoolVar(&formulaInstallJSON, "json", false, "Output as JSON")
+	formulaUpgradeCmd.Flags().BoolVar(&formulaUpgradeForce, "force", false, "Upgrade pinned and locally edited formulas too")
+	formulaUpgradeCmd.Flags().BoolVar(&formulaUpgradeJSON, "json", false, "Output as JSON")
+	formulaPinCmd.Flags().BoolVar(&formulaPinUnpin, "unpin", false, "Remove the pin instead")
+	formulaUninstallCmd.Flags().BoolVar(&formulaUninstallForce, "force", false, "Remove even if edited locally")
+	formulaListCmd.Flags().BoolVar(&formulaListInstalled, "installed", false, "List formulas installed from a registry")
+
+	formulaCmd.AddCommand(formulaInstallCmd)
+	formulaCmd.AddCommand(formulaUpgradeCmd)
+	formulaCmd.AddCommand(formulaPinCmd)
+	formulaCmd.AddCommand(formulaUninstallCmd)
+}
+
+// formulaInstaller returns an installer for the current town. The registry
+// is opened only if one is configured, so commands that don't need it
+// (uninstall, list --installed, pin without a version) work without one.
+func formulaInstaller(needRegistry bool) (*formula.Installer, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	location := formulaRegistry
+	if location == "" {
+		settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+		if err != nil {
+			return nil, fmt.Errorf("loading town settings: %w", err)
+		}
+		location = settings.FormulaRegistry
+	}
+	var reg *formula.Registry
+	if location != "" || needRegistry {
+		if reg, err = formula.OpenRegistry(location); err != nil {
+			return nil, err
+		}
+	}
+	return formula.NewInstaller(townRoot, reg), nil
+}
+
+func runFormulaInstall(cmd *cobra.Command, args []string) error {
+	name, spec := formula.ParseFormulaRef(args[0])
+	inst, err := formulaInstaller(true)
+	if err != nil {
+		return err
+	}
+	result, err := inst.Install(context.Background(), name, spec, formulaInstallForce)
+	if err != nil {
+		return err
+	}
+	if formulaInstallJSON {
+		return outputJSON(result)
+	}
+	printFormulaInstallResult(result)
+	return nil
+}
+
+func runFormulaUpgrade(cmd *cobra.Command, args []string) error {
+	inst, err := formulaInstaller(true)
+	if err != nil {
+		return err
+	}
+	results, err := inst.Upgrade(context.Background(), args, formulaUpgradeForce)
+	if err != nil {
+		return err
+	}
+	if formulaUpgradeJSON {
+		return outputJSON(results)
+	}
+	if len(results) == 0 {
+		fmt.Printf("%s No formulas installed from a registry\n", style.Dim.Render("ℹ"))
+		return nil
+	}
+	for _, r := range results {
+		printFormulaInstallResult(r)
+	}
+	return nil
+}
+
+func runFormulaPin(cmd *cobra.Command, args []string) error {
+	name, spec := formula.ParseFormulaRef(args[0])
+	if formulaPinUnpin {
+		if spec != "" {
+			return fmt.Errorf("--unpin takes a name without a version")
+		}
+		inst, err := formulaInstaller(false)
+		if err != nil {
+			return err
+		}
+		if err := inst.Unpin(name); err != nil {
+			return err
+		}
+		fmt.Printf("%s Unpinned %s\n", style.Success.Render("✓"), name)
+		return nil
+	}
+	if spec != "" && !formula.IsExactVersion(spec) {
+		return fmt.Errorf("pin needs an exact version (e.g. %s@1.2.0), got %q", name, spec)
+	}
+
+	inst, err := formulaInstaller(spec != "")
+	if err != nil {
+		return err
+	}
+	result, err := inst.Pin(context.Background(), name, spec)
+	if err != nil {
+		return err
+	}
+	printFormulaInstallResult(result)
+	return nil
+}
+
+func runFormulaUninstall(cmd *cobra.Command, args []string) error {
+	inst, err := formulaInstaller(false)
+	if err != nil {
+		return err
+	}
+	if err := inst.Uninstall(args[0], formulaUninstallForce); err != nil {
+		return err
+	}
+	fmt.Printf("%s Uninstalled %s\n", style.Success.Render("✓"), args[0])
+	return nil
+}
+
+// runFormulaListInstalled lists registry-installed formulas from the lockfile.
+func runFormulaListInstalled() error {
+	inst, err := formulaInstaller(false)
+	if err != nil {
+		return err
+	}
+	installed, err := inst.Installed()
+	if err != nil {
+		return err
+	}
+	if formulaListJSON {
+		return outputJSON(installed)
+	}
+	if len(installed) == 0 {
+		fmt.Printf("%s No formulas installed from a registry\n", style.Dim.Render("ℹ"))
+		return nil
+	}
+
+	width := 0
+	for _, f := range installed {
+		width = max(width, len(f.Name))
+	}
+	for _, f := range installed {
+		var tags []string
+		if f.Pinned {
+			tags = append(tags, "pinned")
+		}
+		switch {
+		case f.Missing:
+			tags = append(tags, style.Error.Render("missing"))
+		case f.Modified:
+			tags = append(tags, style.Warning.Render("modified"))
+		}
+		tag := ""
+		if len(tags) > 0 {
+			tag = "[" + strings.Join(tags, ", ") + "]"
+		}
+		fmt.Printf("  %-*s  %-10s %s\n", width, f.Name, f.Version, tag)
+	}
+	return nil
+}
+
+func printFormulaInstallResult(r *formula.InstallResult) {
+	pinned := ""
+	if r.Pinned {
+		pinned = style.Dim.Render(" (pinned)")
+	}
+	switch {
+	case r.Skipped != "":
+		fmt.Printf("  %s %s@%s %s\n", style.Dim.Render("○"), r.Name, r.Version, style.Dim.Render("skipped: "+r.Skipped))
+	case r.Unchanged:
+		fmt.Printf("  %s %s@%s already installed%s\n", style.Dim.Render("✓"), r.Name, r.Version, pinned)
+	case r.Previous != "" && r.Previous != r.Version:
+		fmt.Printf("  %s %s %s → %s%s\n", style.Success.Render("✓"), r.Name, r.Previous, r.Version, pinned)
+	default:
+		fmt.Printf("  %s Installed %s@%s%s\n", style.Success.Render("✓"), r.Name, r.Version, pinned)
+		if r.Checksum != "" {
+			fmt.Printf("    %s\n", style.Dim.Render("checksum "+r.Checksum))
+		}
+		fmt.Printf("    %s\n", style.Dim.Render(r.Path))
+	}
+}
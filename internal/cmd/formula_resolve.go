@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Formula resolution flags
+var (
+	formulaTier         string
+	formulaShowResolve  bool
+	formulaShowExpanded bool
+)
+
+// newFormulaResolver returns a resolver for the current town. rigPath selects
+// the project tier; when empty, the rig containing the cwd is used.
+func newFormulaResolver(rigPath string) *formula.Resolver {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return formula.NewResolver("", rigPath)
+	}
+	if rigPath == "" {
+		if _, r, err := findCurrentRig(townRoot); err == nil && r != nil {
+			rigPath = r.Path
+		}
+	}
+	return formula.NewResolver(townRoot, rigPath)
+}
+
+// formulaTierFlag parses --tier ("" means search every tier).
+func formulaTierFlag() (formula.Tier, error) {
+	if formulaTier == "" {
+		return "", nil
+	}
+	return formula.ParseTier(formulaTier)
+}
+
+// resolveFormula finds name for the rig at rigPath, honoring --tier.
+func resolveFormula(name, rigPath string) (*formula.Resolved, error) {
+	tier, err := formulaTierFlag()
+	if err != nil {
+		return nil, err
+	}
+	return newFormulaResolver(rigPath).Resolve(name, tier)
+}
+
+// formulaProvenance describes where a formula resolved from, e.g.
+// "project v6", for sling output and events. Empty if it doesn't resolve.
+func formulaProvenance(name, townRoot, rigName string) (*formula.Resolved, string) {
+	res, err := resolveSlungFormula(name, townRoot, rigName)
+	if err != nil {
+		return nil, ""
+	}
+	return res, fmt.Sprintf("%s v%d", res.Tier, res.Version)
+}
+
+// addFormulaProvenance records which tier a slung formula came from in an
+// event payload.
+func addFormulaProvenance(payload map[string]interface{}, name, townRoot, rigName string) {
+	payload["formula"] = name
+	if res, _ := formulaProvenance(name, townRoot, rigName); res != nil {
+		payload["formula_tier"] = string(res.Tier)
+		payload["formula_version"] = res.Version
+		payload["formula_path"] = res.Path
+	}
+}
+
+// cookSource returns what to hand bd cook for a slung formula: the file gt
+// resolved it to, so bd cooks the same formula the provenance records. A
+// composed formula is flattened (as gt formula show --expanded prints it),
+// since bd doesn't understand gt's composition keys; it and an embedded
+// (system tier) formula are written to a temporary file. A formula gt can't
+// resolve is passed by name for bd to resolve or report, but one using step
+// control is refused, since nothing would run it. cleanup removes any
+// temporary file.
+func cookSource(name, townRoot, rigName string) (source string, cleanup func(), err error) {
+	res, err := resolveSlungFormula(name, townRoot, rigName)
+	if err != nil {
+		return name, func() {}, nil
+	}
+	f, parseErr := res.Parse()
+	// bd would pour step control settings without running them.
+	if errors.Is(parseErr, formula.ErrStepControlUnsupported) {
+		return "", nil, fmt.Errorf("formula %s: %w", name, parseErr)
+	}
+	file, data := path.Base(res.Path), res.Content()
+	if res.Composes() {
+		if parseErr != nil {
+			return "", nil, parseErr
+		}
+		if data, err = f.EncodeTOML(); err != nil {
+			return "", nil, err
+		}
+		file = res.Name + ".formula.toml"
+	} else if res.Tier != formula.TierSystem {
+		return res.Path, func() {}, nil
+	}
+
+	dir, err := os.MkdirTemp("", "gt-formula-")
+	if err != nil {
+		return "", nil, fmt.Errorf("staging formula %s: %w", name, err)
+	}
+	cleanup = func() { _ = os.RemoveAll(dir) }
+	source = filepath.Join(dir, file)
+	if err := os.WriteFile(source, data, 0644); err != nil { //nolint:gosec // G306: formula files are not secret
+		cleanup()
+		return "", nil, fmt.Errorf("staging formula %s: %w", name, err)
+	}
+	return source, cleanup, nil
+}
+
+// runFormulaListTiers lists formulas with the tier each resolves from.
+func runFormulaListTiers() error {
+	tier, err := formulaTierFlag()
+	if err != nil {
+		return err
+	}
+	list, err := newFormulaResolver("").List(tier)
+	if err != nil {
+		return err
+	}
+	if formulaListJSON {
+		return outputJSON(list)
+	}
+	if len(list) == 0 {
+		fmt.Printf("%s No formulas found\n", style.Dim.Render("ℹ"))
+		return nil
+	}
+
+	width := 0
+	for _, f := range list {
+		width = max(width, len(f.Name))
+	}
+	fmt.Printf("  %-*s  %-8s %-4s %s\n", width, "NAME", "TIER", "VER", "NOTES")
+	for _, f := range list {
+		fmt.Printf("  %-*s  %-8s v%-3d %s\n", width, f.Name, f.Tier, f.Version, formulaListNotes(f))
+	}
+	return nil
+}
+
+func formulaListNotes(f *formula.Resolved) string {
+	var notes []string
+	if f.Installed != "" {
+		notes = append(notes, "registry "+f.Installed)
+	}
+	if len(f.Shadows) > 0 {
+		var over []string
+		for _, s := range f.Shadows {
+			over = append(over, fmt.Sprintf("%s v%d", s.Tier, s.Version))
+		}
+		notes = append(notes, "overrides "+strings.Join(over, ", "))
+	}
+	note := strings.Join(notes, "; ")
+	if stale := f.Stale(); stale != nil {
+		note += " " + style.Warning.Render(fmt.Sprintf("(%s has newer v%d)", stale.Tier, stale.Version))
+	}
+	return style.Dim.Render(note)
+}
+
+// runFormulaShowResolve prints the search path walked for a formula and
+// what it resolved to.
+func runFormulaShowResolve(name string) error {
+	tier, err := formulaTierFlag()
+	if err != nil {
+		return err
+	}
+	res, steps, err := newFormulaResolver("").Trace(name, tier)
+	if formulaShowJSON {
+		out := map[string]interface{}{"name": name, "search_path": steps, "resolved": res}
+		if err != nil {
+			out["error"] = err.Error()
+		}
+		if jsonErr := outputJSON(out); jsonErr != nil {
+			return jsonErr
+		}
+		return err
+	}
+
+	fmt.Printf("%s %s\n\n", style.Bold.Render("Resolving"), name)
+	for i, s := range steps {
+		mark := style.Dim.Render("·")
+		switch {
+		case s.Found:
+			mark = style.Success.Render("✓")
+		case s.Skipped:
+			mark = style.Dim.Render("-")
+		}
+		note := ""
+		if s.Skipped {
+			note = style.Dim.Render(" (skipped: --tier)")
+		}
+		fmt.Printf("  %d. %s %-8s %s%s\n", i+1, mark, s.Tier, s.Path, note)
+	}
+	fmt.Println()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Resolved: %s %s v%d\n", style.Bold.Render(string(res.Tier)), res.Path, res.Version)
+	if res.Installed != "" {
+		fmt.Printf("  Installed from registry at %s\n", res.Installed)
+	}
+	for _, s := range res.Shadows {
+		fmt.Printf("  Overrides %s v%d %s\n", s.Tier, s.Version, style.Dim.Render(s.Path))
+	}
+	if stale := res.Stale(); stale != nil {
+		fmt.Printf("  %s %s v%d is newer than this override (v%d)\n",
+			style.Warning.Render("⚠"), stale.Tier, stale.Version, res.Version)
+	}
+	return nil
+}
+
+// runFormulaShowExpanded prints a formula with its composition (extends,
+// include, compose) flattened, as TOML.
+func runFormulaShowExpanded(name string) error {
+	tier, err := formulaTierFlag()
+	if err != nil {
+		return err
+	}
+	res, err := newFormulaResolver("").Resolve(name, tier)
+	if err != nil {
+		return err
+	}
+	f, err := res.Parse()
+	if err != nil {
+		return err
+	}
+
+	data, err := f.EncodeTOML()
+	if err != nil {
+		return err
+	}
+	if formulaShowJSON {
+		// Round-trip through TOML so the JSON keys match the formula file.
+		var doc map[string]interface{}
+		if _, err := toml.Decode(string(data), &doc); err != nil {
+			return fmt.Errorf("encoding formula: %w", err)
+		}
+		return outputJSON(map[string]interface{}{
+			"name":          name,
+			"tier":          res.Tier,
+			"path":          res.Path,
+			"composed_from": f.ComposedFrom(),
+			"formula":       doc,
+		})
+	}
+
+	fmt.Printf("# %s, expanded (%s tier, %s)\n", name, res.Tier, res.Path)
+	if from := f.ComposedFrom(); len(from) > 0 {
+		fmt.Printf("# composed from: %s\n", strings.Join(from, ", "))
+	}
+	fmt.Println()
+	fmt.Print(string(data))
+	return nil
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// parseFormulaVars splits key=value --var flags. A repeated key keeps the
+// last value, as bd does.
+func parseFormulaVars(vars []string) (map[string]string, error) {
+	values := make(map[string]string, len(vars))
+	for _, v := range vars {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --var %q (want key=value)", v)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// formulaRunVarValues returns f's var defaults overridden by gt formula
+// run --var flags, for prompt templates ({{.vars.name}}).
+func formulaRunVarValues(f *formula.Formula) map[string]string {
+	values := make(map[string]string, len(f.Vars))
+	for name, v := range f.Vars {
+		values[name] = v.Default
+	}
+	set, _ := parseFormulaVars(formulaRunVars) // validated by runFormulaRun
+	for name, value := range set {
+		values[name] = value
+	}
+	return values
+}
+
+// formulaVarLookup checks bead-id vars against beads and rig-name vars
+// against the town's rig registry.
+type formulaVarLookup struct {
+	townRoot string
+}
+
+func (l formulaVarLookup) BeadExists(id string) error {
+	return verifyBeadExists(id)
+}
+
+func (l formulaVarLookup) RigExists(name string) error {
+	if l.townRoot == "" {
+		return nil
+	}
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(l.townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		return fmt.Errorf("loading rig registry: %w", err)
+	}
+	if _, ok := rigsConfig.Rigs[name]; !ok {
+		return fmt.Errorf("rig %q is not registered (see gt rig list)", name)
+	}
+	return nil
+}
+
+// resolveSlungFormula resolves a formula named on the sling command line
+// for rigName, trying the mol- prefix bd adds when the name doesn't resolve.
+func resolveSlungFormula(name, townRoot, rigName string) (*formula.Resolved, error) {
+	rigPath := ""
+	if townRoot != "" && rigName != "" {
+		rigPath = filepath.Join(townRoot, rigName)
+	}
+	r := formula.NewResolver(townRoot, rigPath)
+	res, err := r.Resolve(name, "")
+	if err != nil {
+		if res, err = r.Resolve("mol-"+name, ""); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// beadFormulaVars are the vars InstantiateFormulaOnBead fills in from the
+// bead, so they needn't be passed with --var.
+var beadFormulaVars = []string{"feature", "issue"}
+
+// validateFormulaVars checks --var values against the types formulaName
+// declares, and that its required vars are set or in provided, before
+// anything is cooked or spawned. Formulas gt can't resolve or parse are left
+// for bd to check.
+func validateFormulaVars(formulaName string, vars []string, townRoot, rigName string, provided ...string) error {
+	values, err := parseFormulaVars(vars)
+	if err != nil {
+		return err
+	}
+	res, err := resolveSlungFormula(formulaName, townRoot, rigName)
+	if err != nil {
+		return nil
+	}
+	f, err := res.Parse()
+	if err != nil {
+		return nil
+	}
+	return f.ValidateVars(values, formulaVarLookup{townRoot: townRoot}, provided...)
+}
+
+// validateSlingVars checks the user's --var flags for a sling to target.
+// Without a named formula, vars go to the mol-polecat-work formula applied
+// to polecat targets. provided names the vars the sling fills in itself.
+func validateSlingVars(formulaName, target, townRoot string, provided ...string) error {
+	if formulaName == "" {
+		_, isRig := IsRigName(target)
+		if slingHookRawBead || (!isRig && !strings.Contains(target, "/polecats/")) {
+			return nil
+		}
+		formulaName = "mol-polecat-work"
+	}
+	return validateFormulaVars(formulaName, slingVars, townRoot, budgetTargetRig(target), provided...)
+}
+
+// completeFormulaVars completes --var for the formula named by the first
+// argument: var names, then enum, bool and rig-name values.
+func completeFormulaVars(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	townRoot, _ := workspace.FindFromCwd()
+	res, err := resolveSlungFormula(args[0], townRoot, "")
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	f, err := res.Parse()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	name, _, hasValue := strings.Cut(toComplete, "=")
+	if !hasValue {
+		var names []string
+		for n, v := range f.Vars {
+			names = append(names, n+"=\t"+v.Description)
+		}
+		sort.Strings(names)
+		return names, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+	}
+
+	v, ok := f.Vars[name]
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	values := v.Completions()
+	if v.Type == formula.VarRigName && townRoot != "" {
+		if rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json")); err == nil {
+			for rigName := range rigsConfig.Rigs {
+				values = append(values, rigName)
+			}
+			sort.Strings(values)
+		}
+	}
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		out = append(out, name+"="+value)
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
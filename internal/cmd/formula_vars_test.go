@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFormulaVars(t *testing.T) {
+	got, err := parseFormulaVars([]string{"a=1", "b=x=y", "a=2", "empty="})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"a": "2", "b": "x=y", "empty": ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseFormulaVars = %v, want %v", got, want)
+	}
+	for _, bad := range []string{"novalue", "=x"} {
+		if _, err := parseFormulaVars([]string{bad}); err == nil {
+			t.Errorf("parseFormulaVars(%q) should fail", bad)
+		}
+	}
+}
+
+func TestValidateFormulaVars(t *testing.T) {
+	err := validateFormulaVars("mol-digest-generate", []string{"period=monthly"}, "", "")
+	if err == nil || !strings.Contains(err.Error(), `"monthly" is not one of daily, weekly, custom`) {
+		t.Errorf("bad enum: err = %v", err)
+	}
+	// The mol- prefix is optional, as with bd.
+	if err := validateFormulaVars("digest-generate", []string{"period=weekly"}, "", ""); err != nil {
+		t.Errorf("valid enum: %v", err)
+	}
+	// Formulas gt can't resolve are left to bd.
+	if err := validateFormulaVars("no-such-formula", []string{"x=1"}, "", ""); err != nil {
+		t.Errorf("unknown formula: %v", err)
+	}
+}
+
+func TestValidateFormulaVars_MissingRequired(t *testing.T) {
+	// Required vars are checked even with no --var flags at all.
+	err := validateFormulaVars("mol-polecat-work", nil, "", "")
+	if err == nil || !strings.Contains(err.Error(), `missing required var "issue"`) {
+		t.Errorf("no vars: err = %v, want missing issue", err)
+	}
+	// Slinging onto a bead fills issue in.
+	if err := validateFormulaVars("mol-polecat-work", nil, "", "", beadFormulaVars...); err != nil {
+		t.Errorf("bead sling: %v", err)
+	}
+}
+
+func TestCompleteFormulaVars(t *testing.T) {
+	got, _ := completeFormulaVars(nil, []string{"mol-digest-generate"}, "period=")
+	want := []string{"period=daily", "period=weekly", "period=custom"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("value completions = %v, want %v", got, want)
+	}
+
+	names, _ := completeFormulaVars(nil, []string{"mol-digest-generate"}, "")
+	found := false
+	for _, n := range names {
+		if strings.HasPrefix(n, "period=\t") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("name completions = %v, want period=", names)
+	}
+}
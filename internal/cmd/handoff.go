@@ -17,6 +17,7 @@ import (
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/witness"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -704,7 +705,7 @@ func buildRestartCommand(sessionName string) (string, error) {
 	// since exec env vars may not propagate through all agent runtimes.
 	currentAgent := os.Getenv("GT_AGENT")
 	if currentAgent == "" {
-		t := tmux.NewTmux()
+		t := sessionBackend()
 		if val, err := t.GetEnvironment(sessionName, "GT_AGENT"); err == nil && val != "" {
 			currentAgent = val
 		}
@@ -1061,6 +1062,14 @@ func sendHandoffMail(subject, message string) (string, error) {
 		return "", fmt.Errorf("cannot detect town root")
 	}
 
+	// Carry the topic and notes in a typed envelope so readers don't have to
+	// re-parse the subject. The legacy subject and notes stay as written.
+	if payload, err := witness.ParseHandoff(subject, message); err == nil {
+		if msg, err := witness.NewHandoffMessage(agentID, agentID, payload); err == nil {
+			message = msg.Body
+		}
+	}
+
 	// Build labels for mail metadata (matches mail router format)
 	labels := fmt.Sprintf("from:%s", agentID)
 
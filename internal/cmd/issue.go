@@ -6,7 +6,6 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/session"
-	"github.com/steveyegge/gastown/internal/tmux"
 )
 
 var issueCmd = &cobra.Command{
@@ -69,7 +68,7 @@ func runIssueSet(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	if err := t.SetEnvironment(session, "GT_ISSUE", issueID); err != nil {
 		return fmt.Errorf("setting issue: %w", err)
 	}
@@ -87,7 +86,7 @@ func runIssueClear(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	// Set to empty string to clear
 	if err := t.SetEnvironment(session, "GT_ISSUE", ""); err != nil {
 		return fmt.Errorf("clearing issue: %w", err)
@@ -106,7 +105,7 @@ func runIssueShow(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	issue, err := t.GetEnvironment(session, "GT_ISSUE")
 	if err != nil {
 		return fmt.Errorf("getting issue: %w", err)
@@ -54,8 +54,9 @@ var krcPruneCmd = &cobra.Command{
 	Short: "Remove expired events",
 	Long: `Prune events that have exceeded their TTL.
 
-Events are removed from both .events.jsonl and .feed.jsonl.
-The operation is atomic (uses temp files and rename).
+Events are removed from both the event store (.events/) and .feed.jsonl.
+Event store segments that have fully expired are deleted outright; others
+are rewritten atomically (temp file and rename).
 
 Use --dry-run to preview what would be pruned without making changes.`,
 	RunE: runKrcPrune,
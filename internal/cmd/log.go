@@ -1,13 +1,17 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/townlog"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -20,6 +24,7 @@ var (
 	logAgent  string
 	logSince  string
 	logFollow bool
+	logEvents bool
 
 	// log crash flags
 	crashAgent    string
@@ -48,7 +53,14 @@ Examples:
   gt log --type spawn        # Show only spawn events
   gt log --agent greenplace/    # Show events for gastown rig
   gt log --since 1h          # Show events from last hour
-  gt log -f                  # Follow log (like tail -f)`,
+  gt log -f                  # Follow log (like tail -f)
+
+Use --events to query the activity event store (~/gt/.events/) instead of
+the lifecycle log. The same filters apply; segments that cannot match a
+--type, --agent or --since filter are skipped using the store index.
+
+  gt log --events --type sling --since 24h
+  gt log --events --agent gastown/polecats/ -f`,
 	RunE: runLog,
 }
 
@@ -75,6 +87,7 @@ func init() {
 	logCmd.Flags().StringVarP(&logAgent, "agent", "a", "", "Filter by agent prefix (e.g., gastown/, greenplace/crew/max)")
 	logCmd.Flags().StringVar(&logSince, "since", "", "Show events since duration (e.g., 1h, 30m, 24h)")
 	logCmd.Flags().BoolVarP(&logFollow, "follow", "f", false, "Follow log output (like tail -f)")
+	logCmd.Flags().BoolVar(&logEvents, "events", false, "Query the activity event store instead of the lifecycle log")
 
 	// crash subcommand flags
 	logCrashCmd.Flags().StringVar(&crashAgent, "agent", "", "Agent ID (e.g., greenplace/Toast)")
@@ -92,6 +105,10 @@ func runLog(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	if logEvents {
+		return runLogEvents(townRoot)
+	}
+
 	logPath := fmt.Sprintf("%s/logs/town.log", townRoot)
 
 	// If following, use tail -f
@@ -156,6 +173,64 @@ func runLog(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runLogEvents queries the event store with the gt log filters.
+func runLogEvents(townRoot string) error {
+	q := events.Query{}
+	if logType != "" {
+		q.Types = strings.Split(logType, ",")
+	}
+	if logAgent != "" {
+		q.Match = func(e events.Event) bool { return strings.HasPrefix(e.Actor, logAgent) }
+	}
+	if logSince != "" {
+		duration, err := time.ParseDuration(logSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since duration: %w", err)
+		}
+		q.Since = time.Now().Add(-duration)
+	}
+
+	store := events.OpenStore(townRoot)
+	if logFollow {
+		end, err := store.End()
+		if err != nil {
+			return fmt.Errorf("opening event store: %w", err)
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		fmt.Printf("%s Following %s (Ctrl+C to stop)\n\n", style.Dim.Render("○"), store.Dir())
+		q.Since = time.Time{}
+		for rec := range store.Subscribe(ctx, end, q) {
+			printStoreEvent(rec.Event)
+		}
+		return nil
+	}
+
+	q.Limit = logTail
+	recs, err := store.Query(q)
+	if err != nil {
+		return fmt.Errorf("querying event store: %w", err)
+	}
+	if len(recs) == 0 {
+		fmt.Printf("%s No events match filter\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, rec := range recs {
+		printStoreEvent(rec.Event)
+	}
+	return nil
+}
+
+// printStoreEvent prints a single event store entry with styling.
+func printStoreEvent(e events.Event) {
+	ts := e.Timestamp
+	if t := e.Time(); !t.IsZero() {
+		ts = t.Local().Format("2006-01-02 15:04:05")
+	}
+	fmt.Printf("%s %s %s %s\n", style.Dim.Render(ts), fmt.Sprintf("[%s]", e.Type), e.Actor, formatFeedSummary(e))
+}
+
 // followLog uses tail -f to follow the log file.
 func followLog(logPath string) error {
 	// Check if log file exists, create empty if not
@@ -0,0 +1,379 @@
+//go:build e2e
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// loopScripts are the scripted agent's parts in the loop test. The polecat
+// does the work and submits it; the witness relays POLECAT_DONE to the
+// refinery as MERGE_READY; the refinery works the queue one MR at a time
+// through the Engineer (gt mq process). Every other role idles at the prompt.
+var loopScripts = map[string]string{
+	"polecat.toml": `
+# Sit at the prompt long enough for gt sling to see the session start.
+[[step]]
+action = "idle"
+timeout = "3s"
+
+[[step]]
+action = "run"
+command = "gt hook"
+
+[[step]]
+action = "write"
+path = "fix.txt"
+content = "fixed\n"
+
+[[step]]
+action = "commit"
+message = "Fix the thing"
+
+[[step]]
+action = "done"
+`,
+	"witness.toml": `
+[[step]]
+action = "run"
+command = "until gt mail inbox | grep -q POLECAT_DONE; do sleep 1; done"
+
+[[step]]
+action = "run"
+command = "gt mail send \"$GT_RIG/refinery\" -s MERGE_READY -m 'Polecat work is in the merge queue'"
+`,
+	"refinery.toml": `
+[[step]]
+action = "run"
+command = "until gt mail inbox | grep -q MERGE_READY; do sleep 1; done"
+
+[[step]]
+action = "run"
+command = "gt mq process \"$GT_RIG\""
+`,
+	"default.toml": `
+[[step]]
+action = "idle"
+`,
+}
+
+// TestSlingToConvoyLoop runs the whole work loop with the scripted agent and
+// no network: gt sling spawns a polecat that commits a fix and runs gt done,
+// the witness hands it to the refinery, the refinery merges it, and the
+// source issue and its convoy close. The rig's origin is served by a local
+// git daemon, since gt rig add only takes remote URLs.
+func TestSlingToConvoyLoop(t *testing.T) {
+	for _, tool := range []string{"tmux", "bd", "dolt"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			if loopRequired() {
+				t.Fatalf("%s not installed", tool)
+			}
+			t.Skipf("%s not installed", tool)
+		}
+	}
+
+	tmpDir := t.TempDir()
+	hqPath := filepath.Join(tmpDir, "hq")
+	rigPath := filepath.Join(hqPath, "app")
+	binDir := filepath.Join(tmpDir, "bin")
+	scriptDir := filepath.Join(tmpDir, "scripts")
+
+	// Agents call gt and gt-fake-agent from PATH.
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(buildGT(t), filepath.Join(binDir, "gt")); err != nil {
+		t.Fatal(err)
+	}
+	build := exec.Command("go", "build", "-o", filepath.Join(binDir, "gt-fake-agent"), "./cmd/gt-fake-agent")
+	build.Dir = filepath.Dir(filepath.Dir(mustGetwd(t)))
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("building gt-fake-agent: %v\n%s", err, out)
+	}
+	gtBinary := filepath.Join(binDir, "gt")
+
+	// A private HOME and tmux server, so nothing leaks in from (or out to)
+	// the machine running the test.
+	var env []string
+	for _, kv := range cleanE2EEnv() {
+		if !strings.HasPrefix(kv, "TMUX") && !strings.HasPrefix(kv, "PATH=") {
+			env = append(env, kv)
+		}
+	}
+	env = append(env,
+		"HOME="+tmpDir,
+		"TMUX_TMPDIR="+tmpDir,
+		"PATH="+binDir+string(os.PathListSeparator)+os.Getenv("PATH"),
+	)
+	configureGitIdentity(t, env)
+	t.Cleanup(func() {
+		cmd := exec.Command("tmux", "kill-server")
+		cmd.Env = env
+		_ = cmd.Run()
+	})
+
+	// The rig's origin: a bare repo served over git://. Its .gitignore
+	// covers the files gt keeps in worktrees, as a project set up for Gas
+	// Town would.
+	originDir := filepath.Join(tmpDir, "origin")
+	runLoopCmd(t, tmpDir, env, "git", "init", "-q", "--bare", filepath.Join(originDir, "app.git"))
+	seed := filepath.Join(tmpDir, "seed")
+	runLoopCmd(t, tmpDir, env, "git", "clone", "-q", filepath.Join(originDir, "app.git"), seed)
+	writeLoopFile(t, filepath.Join(seed, ".gitignore"), ".beads/\n.runtime/\n.claude/commands/\n.logs/\n")
+	writeLoopFile(t, filepath.Join(seed, "README.md"), "hello\n")
+	runLoopCmd(t, seed, env, "git", "add", ".")
+	runLoopCmd(t, seed, env, "git", "commit", "-q", "-m", "init")
+	runLoopCmd(t, seed, env, "git", "push", "-q", "origin", "HEAD:main")
+	originURL := serveGitDaemon(t, originDir, env) + "/app.git"
+
+	// Kill any stale dolt from a previous test to avoid a port 3307 conflict.
+	_ = exec.Command("pkill", "-f", "dolt sql-server").Run()
+
+	runGTCmd(t, gtBinary, tmpDir, env, "install", hqPath, "--name", "loop-town", "--git")
+	t.Cleanup(func() {
+		cmd := exec.Command(gtBinary, "dolt", "stop")
+		cmd.Dir = hqPath
+		cmd.Env = env
+		_ = cmd.Run()
+	})
+	runGTCmd(t, gtBinary, hqPath, env, "rig", "add", "app", originURL, "--prefix", "ap")
+
+	// Every agent in the town is the scripted agent.
+	if err := os.MkdirAll(scriptDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, script := range loopScripts {
+		writeLoopFile(t, filepath.Join(scriptDir, name), script)
+	}
+	settingsPath := config.TownSettingsPath(hqPath)
+	settings, err := config.LoadOrCreateTownSettings(settingsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings.DefaultAgent = "fake"
+	settings.Agents = map[string]*config.RuntimeConfig{
+		"fake": {Provider: string(config.AgentScripted), Env: map[string]string{"GT_FAKE_AGENT_SCRIPT": scriptDir}},
+	}
+	if err := config.SaveTownSettings(settingsPath, settings); err != nil {
+		t.Fatal(err)
+	}
+
+	// The refinery holds the merge slot to push to main. Find out up front
+	// whether this bd can hold it, before the refinery tries.
+	slotErr := probeMergeSlot(rigPath, env)
+
+	runGTCmd(t, gtBinary, hqPath, env, "daemon", "start")
+	t.Cleanup(func() {
+		cmd := exec.Command(gtBinary, "daemon", "stop")
+		cmd.Dir = hqPath
+		cmd.Env = env
+		_ = cmd.Run()
+	})
+
+	issueID := strings.TrimSpace(runLoopCmd(t, rigPath, env, "bd", "create", "Fix the thing",
+		"--type", "task", "--description", "Write fix.txt", "--silent"))
+	runGTCmd(t, gtBinary, hqPath, env, "sling", issueID, "app")
+	t.Cleanup(func() {
+		if t.Failed() {
+			dumpLoopState(t, hqPath, rigPath, issueID, env)
+		}
+	})
+
+	// Polecat → witness → refinery: the witness relays the polecat's
+	// POLECAT_DONE once gt done has pushed the branch and queued the MR.
+	waitForLoop(t, 2*time.Minute, "refinery to get MERGE_READY", func() bool {
+		out, err := loopOutput(hqPath, env, "gt", "mail", "inbox", "app/refinery", "--json")
+		return err == nil && strings.Contains(out, "MERGE_READY")
+	})
+
+	if slotErr != nil {
+		// CI pins a bd whose merge slot works, so there the merge half
+		// must run; a local bd that can't hold the slot only skips it.
+		if loopRequired() {
+			t.Fatalf("bd cannot hold the merge slot the refinery needs to push to main: %v", slotErr)
+		}
+		t.Skipf("stopping before the merge: bd cannot hold the merge slot the refinery needs to push to main: %v", slotErr)
+	}
+
+	// Refinery → convoy: the merge closes the source issue, which closes
+	// the convoy sling created for it.
+	waitForLoop(t, 2*time.Minute, "convoy to close", func() bool {
+		out, err := loopOutput(hqPath, env, "gt", "convoy", "list", "--all", "--json")
+		if err != nil {
+			return false
+		}
+		var convoys []struct {
+			Status  string `json:"status"`
+			Tracked []struct {
+				ID     string `json:"id"`
+				Status string `json:"status"`
+			} `json:"tracked"`
+		}
+		if json.Unmarshal([]byte(out), &convoys) != nil {
+			return false
+		}
+		for _, c := range convoys {
+			for _, tr := range c.Tracked {
+				if tr.ID == issueID {
+					return c.Status == "closed" && tr.Status == "closed"
+				}
+			}
+		}
+		return false
+	})
+
+	log := runLoopCmd(t, tmpDir, env, "git", "--git-dir", filepath.Join(originDir, "app.git"), "log", "--format=%s", "main")
+	if !strings.Contains(log, "("+issueID+")") {
+		t.Errorf("origin/main has no merge for %s:\n%s", issueID, log)
+	}
+	if show := runLoopCmd(t, tmpDir, env, "git", "--git-dir", filepath.Join(originDir, "app.git"), "show", "main:fix.txt"); show != "fixed\n" {
+		t.Errorf("fix.txt on main = %q", show)
+	}
+	if out, _ := loopOutput(hqPath, env, "gt", "polecat", "list", "app", "--json"); strings.TrimSpace(out) != "[]" {
+		t.Errorf("polecat should be gone after gt done, got %s", out)
+	}
+}
+
+// loopRequired reports whether the whole loop must run rather than skip
+// what the machine can't do. The Work Loop CI job sets GT_LOOP_REQUIRED.
+func loopRequired() bool {
+	return os.Getenv("GT_LOOP_REQUIRED") != ""
+}
+
+// dumpLoopState logs what each agent's pane shows and where the issue and
+// convoy stand, so a CI failure says which hop of the loop stalled.
+func dumpLoopState(t *testing.T, hqPath, rigPath, issueID string, env []string) {
+	t.Helper()
+	sessions, _ := loopOutput(hqPath, env, "tmux", "list-sessions", "-F", "#{session_name}")
+	for _, s := range strings.Fields(sessions) {
+		pane, _ := loopOutput(hqPath, env, "tmux", "capture-pane", "-p", "-S", "-40", "-t", s)
+		t.Logf("--- session %s ---\n%s", s, pane)
+	}
+	for _, args := range [][]string{
+		{"bd", "show", issueID},
+		{"bd", "list", "--all"},
+		{"gt", "convoy", "list", "--all"},
+		{"gt", "mq", "list", "app"},
+	} {
+		out, _ := loopOutput(rigPath, env, args[0], args[1:]...)
+		t.Logf("--- %s ---\n%s", strings.Join(args, " "), out)
+	}
+}
+
+// probeMergeSlot acquires and releases the rig's merge slot, and checks the
+// slot is free again afterwards.
+func probeMergeSlot(rigPath string, env []string) error {
+	_, _ = loopOutput(rigPath, env, "bd", "merge-slot", "create", "--json")
+	for _, args := range [][]string{
+		{"acquire", "--holder=loop-probe", "--json"},
+		{"release", "--json"},
+	} {
+		if out, err := loopOutput(rigPath, env, "bd", append([]string{"merge-slot"}, args...)...); err != nil {
+			return fmt.Errorf("bd merge-slot %s: %v: %s", args[0], err, strings.TrimSpace(out))
+		}
+	}
+	out, err := loopOutput(rigPath, env, "bd", "merge-slot", "check", "--json")
+	if err != nil {
+		return fmt.Errorf("bd merge-slot check: %v: %s", err, strings.TrimSpace(out))
+	}
+	var status struct {
+		Available bool   `json:"available"`
+		Holder    string `json:"holder"`
+	}
+	if err := json.Unmarshal([]byte(out), &status); err != nil {
+		return fmt.Errorf("bd merge-slot check: %v: %s", err, strings.TrimSpace(out))
+	}
+	if !status.Available {
+		return fmt.Errorf("slot still held by %q after release", status.Holder)
+	}
+	return nil
+}
+
+// serveGitDaemon serves dir over git:// (push enabled) on a free local port
+// and returns the base URL.
+func serveGitDaemon(t *testing.T, dir string, env []string) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	_ = l.Close()
+
+	cmd := exec.Command("git", "daemon", "--reuseaddr", "--export-all", "--enable=receive-pack",
+		"--listen=127.0.0.1", fmt.Sprintf("--port=%d", port), "--base-path="+dir, dir)
+	cmd.Env = env
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("starting git daemon: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+	waitForLoop(t, 10*time.Second, "git daemon", func() bool {
+		c, err := net.Dial("tcp", addr)
+		if err == nil {
+			_ = c.Close()
+		}
+		return err == nil
+	})
+	return "git://" + addr
+}
+
+func waitForLoop(t *testing.T, timeout time.Duration, what string, done func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !done() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out after %v waiting for %s", timeout, what)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+func runLoopCmd(t *testing.T, dir string, env []string, name string, args ...string) string {
+	t.Helper()
+	out, err := loopOutput(dir, env, name, args...)
+	if err != nil {
+		t.Fatalf("%s %v failed: %v\n%s", name, args, err, out)
+	}
+	return out
+}
+
+func loopOutput(dir string, env []string, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	cmd.Env = env
+	out, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		out = append(out, exitErr.Stderr...)
+	}
+	return string(out), err
+}
+
+func writeLoopFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustGetwd(t *testing.T) string {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return wd
+}
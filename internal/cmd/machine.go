@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/connection"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	machineListJSON bool
+	machineHost     string
+	machineKeyPath  string
+	machineTownPath string
+)
+
+var machineCmd = &cobra.Command{
+	Use:     "machine",
+	GroupID: GroupWorkspace,
+	Short:   "Manage machines that can host polecats",
+	Long: `Manage the town's machine registry (mayor/machines.json).
+
+Registered machines can host polecats: 'gt sling <bead> <rig> --machine <name>'
+creates the polecat's worktree and tmux session on that machine over ssh.
+The remote machine needs a town at --town-path with the rig already added,
+sharing this town's Dolt server.
+
+Examples:
+  gt machine list
+  gt machine add gpu1 --host me@gpu1.lan --town-path /home/me/gt
+  gt machine test gpu1
+  gt machine remove gpu1`,
+	RunE: requireSubcommand,
+}
+
+var machineListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered machines",
+	Args:  cobra.NoArgs,
+	RunE:  runMachineList,
+}
+
+var machineAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Register an ssh machine",
+	Long: `Register an ssh machine that can host polecats.
+
+Re-adding an existing name updates its settings.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMachineAdd,
+}
+
+var machineRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Unregister a machine",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runMachineRemove,
+}
+
+var machineTestCmd = &cobra.Command{
+	Use:   "test <name>",
+	Short: "Check that a machine is reachable and has a town",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runMachineTest,
+}
+
+func init() {
+	machineListCmd.Flags().BoolVar(&machineListJSON, "json", false, "Output as JSON")
+	machineAddCmd.Flags().StringVar(&machineHost, "host", "", "SSH destination (user@host)")
+	machineAddCmd.Flags().StringVar(&machineKeyPath, "key", "", "SSH private key path")
+	machineAddCmd.Flags().StringVar(&machineTownPath, "town-path", "", "Town root on the remote machine")
+	_ = machineAddCmd.MarkFlagRequired("host")
+	_ = machineAddCmd.MarkFlagRequired("town-path")
+
+	machineCmd.AddCommand(machineListCmd)
+	machineCmd.AddCommand(machineAddCmd)
+	machineCmd.AddCommand(machineRemoveCmd)
+	machineCmd.AddCommand(machineTestCmd)
+	rootCmd.AddCommand(machineCmd)
+}
+
+func loadMachineRegistry() (*connection.MachineRegistry, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	return connection.LoadTownRegistry(townRoot)
+}
+
+func runMachineList(cmd *cobra.Command, args []string) error {
+	reg, err := loadMachineRegistry()
+	if err != nil {
+		return err
+	}
+	machines := reg.List()
+	sort.Slice(machines, func(i, j int) bool { return machines[i].Name < machines[j].Name })
+
+	if machineListJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(machines)
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render("Machines"))
+	for _, m := range machines {
+		if m.Type == "local" {
+			fmt.Printf("  %s  %s\n", m.Name, style.Dim.Render("(this machine)"))
+			continue
+		}
+		fmt.Printf("  %s  %s  %s\n", m.Name, m.Host, style.Dim.Render(m.TownPath))
+	}
+	return nil
+}
+
+func runMachineAdd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if name == "local" {
+		return fmt.Errorf("'local' is reserved for this machine")
+	}
+	reg, err := loadMachineRegistry()
+	if err != nil {
+		return err
+	}
+	if err := reg.Add(&connection.Machine{
+		Name:     name,
+		Type:     "ssh",
+		Host:     machineHost,
+		KeyPath:  machineKeyPath,
+		TownPath: machineTownPath,
+	}); err != nil {
+		return err
+	}
+	fmt.Printf("%s Registered machine %s (%s)\n", style.Success.Render("✓"), name, machineHost)
+	fmt.Printf("  Check it with: gt machine test %s\n", name)
+	return nil
+}
+
+func runMachineRemove(cmd *cobra.Command, args []string) error {
+	reg, err := loadMachineRegistry()
+	if err != nil {
+		return err
+	}
+	if err := reg.Remove(args[0]); err != nil {
+		return err
+	}
+	fmt.Printf("%s Removed machine %s\n", style.Success.Render("✓"), args[0])
+	return nil
+}
+
+func runMachineTest(cmd *cobra.Command, args []string) error {
+	reg, err := loadMachineRegistry()
+	if err != nil {
+		return err
+	}
+	m, err := reg.Get(args[0])
+	if err != nil {
+		return err
+	}
+	conn, err := reg.Connection(m.Name)
+	if err != nil {
+		return err
+	}
+
+	if _, err := conn.Exec("true"); err != nil {
+		return fmt.Errorf("%s is not reachable: %w", m.Name, err)
+	}
+	fmt.Printf("%s Connected to %s\n", style.Success.Render("✓"), m.Name)
+
+	if m.TownPath != "" {
+		ok, err := conn.Exists(m.TownPath)
+		if err != nil {
+			return fmt.Errorf("checking town path: %w", err)
+		}
+		if !ok {
+			return fmt.Errorf("town path %s does not exist on %s", m.TownPath, m.Name)
+		}
+		fmt.Printf("%s Town found at %s\n", style.Success.Render("✓"), m.TownPath)
+	}
+
+	if _, err := conn.TmuxListSessions(); err != nil {
+		style.PrintWarning("tmux not usable on %s: %v", m.Name, err)
+	} else {
+		fmt.Printf("%s tmux available\n", style.Success.Render("✓"))
+	}
+	return nil
+}
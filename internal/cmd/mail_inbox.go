@@ -387,7 +387,7 @@ func runMailArchiveStale(mailbox *mail.Mailbox, address string) error {
 		return fmt.Errorf("could not determine session name for %s", address)
 	}
 
-	sessionStart, err := session.SessionCreatedAt(sessionName)
+	sessionStart, err := session.SessionCreatedAt(sessionBackend(), sessionName)
 	if err != nil {
 		return fmt.Errorf("getting session start time for %s: %w", sessionName, err)
 	}
@@ -12,6 +12,7 @@ import (
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -86,6 +87,12 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	// Set message type
 	msg.Type = mail.ParseMessageType(mailType)
 
+	// Hand-written protocol mail (HELP, MERGED, ...) gets the same typed
+	// envelope as mail sent by gt itself, so receivers decode one format.
+	if err := witness.AttachLegacyEnvelope(msg); err != nil {
+		style.PrintWarning("could not attach protocol envelope: %v", err)
+	}
+
 	// Set pinned flag
 	msg.Pinned = mailPinned
 
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var metricsJSON bool
+
+var metricsCmd = &cobra.Command{
+	Use:     "metrics",
+	GroupID: GroupDiag,
+	Short:   "Print town metrics in Prometheus text format",
+	Long: `Print the town's metrics in Prometheus text format.
+
+Metrics are collected when telemetry is enabled in settings/config.json:
+
+  "telemetry": {
+    "enabled": true,
+    "listen": "127.0.0.1:9464",
+    "traces_file": ".runtime/telemetry/traces.jsonl"
+  }
+
+With listen set, the daemon serves the same output at http://<listen>/metrics
+for Prometheus to scrape. With traces_file set, merges, quality gates and
+polecat spawns are written there as OTLP JSON spans.
+
+Metrics:
+  gt_polecat_spawn_seconds         polecat allocation to session start
+  gt_polecat_first_hook_seconds    spawn to first pickup of hooked work
+  gt_mq_depth                      merge requests ready for the refinery
+  gt_mq_wait_seconds               MR creation to merge
+  gt_gate_duration_seconds         quality gate run time
+  gt_merges_total                  merges by result and failure type
+  gt_session_deaths_total          sessions found dead by the daemon
+  gt_session_restarts_total        sessions restarted by the daemon
+  gt_dolt_query_latency_seconds    Dolt SELECT 1 round trip
+  gt_mail_messages_total           mail sent, by recipient address
+
+Examples:
+  gt metrics
+  gt metrics --json`,
+	RunE: runMetrics,
+}
+
+func init() {
+	metricsCmd.Flags().BoolVar(&metricsJSON, "json", false, "Output the raw metric snapshot as JSON")
+	rootCmd.AddCommand(metricsCmd)
+}
+
+func runMetrics(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if !telemetry.Enabled(townRoot) {
+		fmt.Fprintf(os.Stderr, "%s Telemetry is disabled; set \"telemetry\": {\"enabled\": true} in settings/config.json\n",
+			style.Dim.Render("○"))
+	}
+
+	snap, err := telemetry.Load(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading metrics: %w", err)
+	}
+
+	if metricsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(snap)
+	}
+	return telemetry.WritePrometheus(os.Stdout, snap)
+}
@@ -1,15 +1,11 @@
 package cmd
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 	"os/exec"
-	"path/filepath"
-	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -34,7 +30,7 @@ var moleculeAwaitSignalCmd = &cobra.Command{
 	Long: `Wait for any activity on the events feed, with optional backoff.
 
 This command is the primary wake mechanism for patrol agents. It tails
-~/gt/.events.jsonl and returns immediately when a new event is appended
+the event store (~/gt/.events/) and returns immediately when a new event is appended
 (indicating Gas Town activity such as slings, nudges, mail, spawns, etc.).
 
 If no activity occurs within the timeout, the command returns with exit code 0
@@ -105,7 +101,7 @@ func runMoleculeAwaitSignal(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a beads workspace: %w", err)
 	}
 
-	// Find town root for events file (events are always at <townRoot>/.events.jsonl)
+	// Find town root for the event store (events are always at <townRoot>/.events/)
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
@@ -302,33 +298,24 @@ func calculateEffectiveTimeout(idleCycles int) (time.Duration, error) {
 	return time.ParseDuration(awaitSignalTimeout)
 }
 
-// waitForActivitySignal tails the events file for new activity.
-// townRoot is the Gas Town workspace root; the events file is at
-// <townRoot>/.events.jsonl. Returns immediately when a new event line is
-// appended, or when context is canceled.
+// waitForActivitySignal tails the town's event store for new activity.
+// Returns immediately when a new event is appended, or when context is
+// canceled.
 func waitForActivitySignal(ctx context.Context, townRoot string) (*AwaitSignalResult, error) {
-	return waitForEventsFile(ctx, filepath.Join(townRoot, events.EventsFile))
+	return waitForStoreEvent(ctx, events.OpenStore(townRoot))
 }
 
-// waitForEventsFile tails the events file for new lines.
+// waitForStoreEvent polls the event store for events appended after the
+// current end of the store.
 // This replaces the former bd activity --follow subprocess approach.
-func waitForEventsFile(ctx context.Context, eventsPath string) (*AwaitSignalResult, error) {
-
-	f, err := os.OpenFile(eventsPath, os.O_RDONLY|os.O_CREATE, 0644)
+func waitForStoreEvent(ctx context.Context, store *events.Store) (*AwaitSignalResult, error) {
+	// Start at the end — we only want new events, not historical ones
+	end, err := store.End()
 	if err != nil {
-		return nil, fmt.Errorf("opening events file %s: %w", eventsPath, err)
-	}
-	defer f.Close()
-
-	// Seek to end — we only want new events, not historical ones
-	if _, err := f.Seek(0, 2); err != nil {
-		return nil, fmt.Errorf("seeking to end of events file: %w", err)
+		return nil, fmt.Errorf("opening event store %s: %w", store.Dir(), err)
 	}
+	tail := store.Tail(end, events.Query{})
 
-	// Poll for new lines using bufio.Reader (not Scanner, which doesn't
-	// resume after EOF). Reader.ReadString properly retries the underlying
-	// file reader, picking up appended data between polls.
-	reader := bufio.NewReader(f)
 	ticker := time.NewTicker(200 * time.Millisecond)
 	defer ticker.Stop()
 
@@ -339,16 +326,15 @@ func waitForEventsFile(ctx context.Context, eventsPath string) (*AwaitSignalResu
 				Reason: "timeout",
 			}, nil
 		case <-ticker.C:
-			line, err := reader.ReadString('\n')
-			if err == nil && line != "" {
+			recs, err := tail.Next()
+			if len(recs) > 0 {
 				return &AwaitSignalResult{
 					Reason: "signal",
-					Signal: strings.TrimRight(line, "\n"),
+					Signal: recs[0].Line,
 				}, nil
 			}
-			// io.EOF means no new data yet — keep polling
-			if err != nil && err != io.EOF {
-				return nil, fmt.Errorf("reading events file: %w", err)
+			if err != nil {
+				return nil, fmt.Errorf("reading event store: %w", err)
 			}
 		}
 	}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
 )
 
 func TestCalculateEffectiveTimeout(t *testing.T) {
@@ -127,13 +130,13 @@ func TestAwaitSignalResult(t *testing.T) {
 	}
 }
 
-func TestWaitForEventsFile_MissingFile(t *testing.T) {
-	// When the events file doesn't exist, waitForEventsFile creates it and
-	// waits for new events. With no events, it should return timeout.
+func TestWaitForStoreEvent_EmptyStore(t *testing.T) {
+	// With no store on disk yet, waitForStoreEvent waits for new events.
+	// With no events, it should return timeout.
 	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
 	defer cancel()
 
-	result, err := waitForEventsFile(ctx, filepath.Join(t.TempDir(), "nonexistent.jsonl"))
+	result, err := waitForStoreEvent(ctx, events.OpenStore(t.TempDir()))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -142,17 +145,17 @@ func TestWaitForEventsFile_MissingFile(t *testing.T) {
 	}
 }
 
-func TestWaitForEventsFile_Timeout(t *testing.T) {
-	// When no new events are appended, waitForEventsFile should return timeout.
-	eventsPath := filepath.Join(t.TempDir(), ".events.jsonl")
-	if err := os.WriteFile(eventsPath, []byte(`{"ts":"2024-01-01","type":"test"}`+"\n"), 0644); err != nil {
+func TestWaitForStoreEvent_Timeout(t *testing.T) {
+	// When no new events are appended, waitForStoreEvent should return timeout.
+	store := events.OpenStore(t.TempDir())
+	if err := store.Append(events.Event{Timestamp: "2024-01-01T00:00:00Z", Type: "test"}); err != nil {
 		t.Fatal(err)
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
 	defer cancel()
 
-	result, err := waitForEventsFile(ctx, eventsPath)
+	result, err := waitForStoreEvent(ctx, store)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -161,61 +164,51 @@ func TestWaitForEventsFile_Timeout(t *testing.T) {
 	}
 }
 
-func TestWaitForEventsFile_Signal(t *testing.T) {
-	// When a new event is appended, waitForEventsFile should return signal.
-	eventsPath := filepath.Join(t.TempDir(), ".events.jsonl")
-	// Write initial content (will be skipped — we seek to end)
-	if err := os.WriteFile(eventsPath, []byte(`{"ts":"old","type":"ignore"}`+"\n"), 0644); err != nil {
+func TestWaitForStoreEvent_Signal(t *testing.T) {
+	// When a new event is appended, waitForStoreEvent should return signal.
+	store := events.OpenStore(t.TempDir())
+	// Existing events are skipped — we start at the end
+	if err := store.Append(events.Event{Timestamp: "2024-01-01T00:00:00Z", Type: "ignore"}); err != nil {
 		t.Fatal(err)
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	// Append a new line after a short delay
+	// Append a new event after a short delay
 	go func() {
 		time.Sleep(300 * time.Millisecond)
-		f, err := os.OpenFile(eventsPath, os.O_APPEND|os.O_WRONLY, 0644)
-		if err != nil {
-			return
-		}
-		defer f.Close()
-		_, _ = f.WriteString(`{"ts":"new","type":"sling","actor":"test"}` + "\n")
+		_ = store.Append(events.Event{Timestamp: time.Now().Format(time.RFC3339), Type: "sling", Actor: "test"})
 	}()
 
-	result, err := waitForEventsFile(ctx, eventsPath)
+	result, err := waitForStoreEvent(ctx, store)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if result.Reason != "signal" {
 		t.Errorf("expected reason 'signal', got %q", result.Reason)
 	}
-	if result.Signal == "" {
-		t.Error("expected signal line to be set")
+	if !strings.Contains(result.Signal, `"type":"sling"`) {
+		t.Errorf("expected signal line for the sling event, got %q", result.Signal)
 	}
 }
 
-func TestWaitForActivitySignal_PathWiring(t *testing.T) {
-	// Verify waitForActivitySignal constructs the correct events path from
-	// townRoot. The events file should be at <townRoot>/.events.jsonl.
+func TestWaitForActivitySignal_LegacyFile(t *testing.T) {
+	// A legacy <townRoot>/.events.jsonl is migrated into the store, and
+	// events appended afterwards still wake the waiter.
 	townRoot := t.TempDir()
-	eventsPath := filepath.Join(townRoot, ".events.jsonl")
-	if err := os.WriteFile(eventsPath, []byte(`{"ts":"old","type":"ignore"}`+"\n"), 0644); err != nil {
+	eventsPath := filepath.Join(townRoot, events.EventsFile)
+	if err := os.WriteFile(eventsPath, []byte(`{"ts":"2024-01-01T00:00:00Z","type":"ignore"}`+"\n"), 0644); err != nil {
 		t.Fatal(err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	// Append a new event after a short delay
 	go func() {
 		time.Sleep(200 * time.Millisecond)
-		f, err := os.OpenFile(eventsPath, os.O_APPEND|os.O_WRONLY, 0644)
-		if err != nil {
-			return
-		}
-		defer f.Close()
-		_, _ = f.WriteString(`{"ts":"new","type":"sling"}` + "\n")
+		_ = events.OpenStore(townRoot).Append(events.Event{Timestamp: time.Now().Format(time.RFC3339), Type: "sling"})
 	}()
 
 	result, err := waitForActivitySignal(ctx, townRoot)
@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/formula"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -18,6 +19,7 @@ type DAGNode struct {
 	Title        string     `json:"title"`
 	Status       string     `json:"status"`
 	Parallel     bool       `json:"parallel,omitempty"`
+	Control      []string   `json:"control,omitempty"` // when/retry/foreach/timeout lines from the description, e.g. "retry 3 from fix"
 	Dependencies []string   `json:"dependencies,omitempty"`
 	Dependents   []string   `json:"dependents,omitempty"`
 	Tier         int        `json:"tier"` // Execution tier (0 = root, higher = later)
@@ -46,11 +48,19 @@ Shows the dependency structure with execution tiers and status:
   ○ ready       - Step ready to execute (all deps met)
   ◌ blocked     - Step waiting on dependencies
 
+Control lines in a step's description (when:, retry:, foreach:, timeout:)
+are shown after the step, e.g. [retry 3 from fix]. They are instructions
+for the agent working the step; nothing enforces them.
+
+With --formula, the argument is a formula name and its workflow is drawn
+from the definition, before anything is poured.
+
 Examples:
   gt mol dag gs-wisp-abc     # Show DAG for molecule
   gt mol dag gs-wisp-abc --json  # JSON output
   gt mol dag gs-wisp-abc --tree  # Tree view (default)
-  gt mol dag gs-wisp-abc --tiers # Group by execution tier`,
+  gt mol dag gs-wisp-abc --tiers # Group by execution tier
+  gt mol dag gastown-release --formula`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMoleculeDag,
 }
@@ -58,17 +68,27 @@ Examples:
 var (
 	dagShowTiers bool
 	dagTreeView  bool
+	dagFormula   bool
 )
 
 func init() {
 	moleculeDagCmd.Flags().BoolVar(&dagShowTiers, "tiers", false, "Group output by execution tier")
 	moleculeDagCmd.Flags().BoolVar(&dagTreeView, "tree", true, "Show tree view (default)")
 	moleculeDagCmd.Flags().BoolVar(&moleculeJSON, "json", false, "Output as JSON")
+	moleculeDagCmd.Flags().BoolVar(&dagFormula, "formula", false, "Draw a formula's workflow instead of a poured molecule")
 }
 
 func runMoleculeDag(cmd *cobra.Command, args []string) error {
 	rootID := args[0]
 
+	if dagFormula {
+		dag, err := buildFormulaDAG(rootID)
+		if err != nil {
+			return err
+		}
+		return outputDAG(dag)
+	}
+
 	workDir, err := findLocalBeadsDir()
 	if err != nil {
 		return fmt.Errorf("not in a beads workspace: %w", err)
@@ -102,6 +122,11 @@ func runMoleculeDag(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("building DAG: %w", err)
 	}
 
+	return outputDAG(dag)
+}
+
+// outputDAG writes the DAG as JSON, tiers, or a tree, per the flags.
+func outputDAG(dag *DAGInfo) error {
 	// JSON output
 	if moleculeJSON {
 		enc := json.NewEncoder(os.Stdout)
@@ -116,6 +141,84 @@ func runMoleculeDag(cmd *cobra.Command, args []string) error {
 	return outputDAGTree(dag)
 }
 
+// buildFormulaDAG draws a workflow formula's steps. Nothing has run, so
+// entry steps are ready and the rest blocked.
+func buildFormulaDAG(name string) (*DAGInfo, error) {
+	resolved, err := resolveFormula(name, "")
+	if err != nil {
+		return nil, err
+	}
+	f, err := resolved.Parse()
+	if err != nil {
+		return nil, err
+	}
+	if f.Type != formula.TypeWorkflow {
+		return nil, fmt.Errorf("%s is a %s formula; only workflow formulas have a step DAG", name, f.Type)
+	}
+
+	dag := &DAGInfo{
+		RootID:    fmt.Sprintf("%s (%s v%d)", name, resolved.Tier, resolved.Version),
+		RootTitle: f.Description,
+		Nodes:     make(map[string]*DAGNode),
+	}
+	if dag.RootTitle == "" {
+		dag.RootTitle = name
+	}
+	for i := range f.Steps {
+		step := &f.Steps[i]
+		node := &DAGNode{
+			ID:           step.ID,
+			Title:        step.Title,
+			Status:       "blocked",
+			Parallel:     step.Parallel,
+			Dependencies: step.Needs,
+			Control:      stepControlMarkers(step.Description),
+		}
+		if len(step.Needs) == 0 {
+			node.Status = "ready"
+		}
+		dag.Nodes[step.ID] = node
+		dag.TotalNodes++
+	}
+	for _, step := range f.Steps {
+		for _, dep := range step.Needs {
+			if depNode, ok := dag.Nodes[dep]; ok {
+				depNode.Dependents = append(depNode.Dependents, step.ID)
+			}
+		}
+	}
+	computeTiers(dag)
+	dag.CriticalPath = findCriticalPath(dag)
+	return dag, nil
+}
+
+// stepControlMarkers returns the when/retry/foreach/timeout lines a step
+// description carries, e.g. "retry: 3 from fix" -> "retry 3 from fix".
+func stepControlMarkers(description string) []string {
+	var markers []string
+	for _, line := range strings.Split(description, "\n") {
+		line = strings.TrimSpace(line)
+		for _, key := range []string{"when", "retry", "foreach", "timeout"} {
+			if value, ok := strings.CutPrefix(line, key+":"); ok && strings.TrimSpace(value) != "" {
+				sep := " "
+				if key == "when" {
+					sep = ": "
+				}
+				markers = append(markers, key+sep+strings.TrimSpace(value))
+			}
+		}
+	}
+	return markers
+}
+
+// controlSuffix renders a node's control settings for display.
+func controlSuffix(node *DAGNode) string {
+	if len(node.Control) == 0 {
+		return ""
+	}
+	return " " + style.Dim.Render("["+strings.Join(node.Control, ", ")+"]")
+}
+
 // buildDAG constructs the DAG from molecule children.
 func buildDAG(b *beads.Beads, root *beads.Issue, children []*beads.Issue) (*DAGInfo, error) {
 	dag := &DAGInfo{
@@ -169,6 +272,7 @@ func buildDAG(b *beads.Beads, root *beads.Issue, children []*beads.Issue) (*DAGI
 			strings.Contains(step.Description, "parallel=true") {
 			node.Parallel = true
 		}
+		node.Control = stepControlMarkers(step.Description)
 
 		// Compute ready status for open steps
 		if child.Status == "open" {
@@ -373,7 +477,7 @@ func printNode(dag *DAGInfo, id, prefix string, isLast bool, visited map[string]
 	}
 
 	// Print node
-	fmt.Printf("%s%s %s %s%s\n", prefix, connector, icon, node.ID, parallelMark)
+	fmt.Printf("%s%s %s %s%s%s\n", prefix, connector, icon, node.ID, parallelMark, controlSuffix(node))
 
 	// Child prefix
 	childPrefix := prefix
@@ -437,7 +541,7 @@ func outputDAGTiers(dag *DAGInfo) error {
 				depStr = fmt.Sprintf(" ← %s", strings.Join(node.Dependencies, ", "))
 			}
 
-			fmt.Printf("       %s %s%s%s\n", icon, id, parallelMark, depStr)
+			fmt.Printf("       %s %s%s%s%s\n", icon, id, parallelMark, depStr, controlSuffix(node))
 		}
 		fmt.Println()
 	}
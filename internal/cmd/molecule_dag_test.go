@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStepControlMarkers(t *testing.T) {
+	desc := "Run the suite.\n\nwhen: vars.full == true\nretry: 3 from fix\n  timeout: 30m\nNot a marker: retry later\n"
+	got := stepControlMarkers(desc)
+	want := []string{"when: vars.full == true", "retry 3 from fix", "timeout 30m"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("stepControlMarkers = %q, want %q", got, want)
+	}
+}
+
+func TestBuildFormulaDAG(t *testing.T) {
+	dag, err := buildFormulaDAG("gastown-release")
+	if err != nil {
+		t.Fatalf("buildFormulaDAG: %v", err)
+	}
+	if dag.TotalNodes == 0 || dag.Tiers != dag.TotalNodes {
+		t.Errorf("release formula should be a linear chain: %d nodes, %d tiers", dag.TotalNodes, dag.Tiers)
+	}
+	if n := dag.Nodes["restart-daemons"]; n == nil || !reflect.DeepEqual(n.Control, []string{"retry 2, 10s apart"}) {
+		t.Errorf("restart-daemons = %+v", n)
+	}
+	if n := dag.Nodes["preflight-workspaces"]; n == nil || n.Status != "ready" {
+		t.Errorf("entry step = %+v, want ready", n)
+	}
+
+	if _, err := buildFormulaDAG("code-review"); err == nil {
+		t.Error("convoy formulas have no step DAG")
+	}
+}
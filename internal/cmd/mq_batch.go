@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ batch command flags
+var (
+	mqBatchSize   int
+	mqBatchDryRun bool
+	mqBatchJSON   bool
+)
+
+var mqBatchCmd = &cobra.Command{
+	Use:   "batch <rig>",
+	Short: "Merge the top of the queue as a speculative batch",
+	Long: `Merge the highest-scoring ready MRs as one speculative batch.
+
+The top N MRs (by the same score as 'gt mq next') that share a target branch
+are landed with the rig's merge strategy onto the target, one after another,
+in a scratch worktree.
+The quality gates run on the resulting stack. If the stack is green it lands
+in a single push.
+
+If the stack fails, its prefixes are bisected to find the first MR that breaks
+the gates. The MRs before it land, it gets a MERGE_FAILED like any other
+failure, and the MRs after it go back to the queue for the next batch. Up to
+merge_queue.max_concurrent prefixes are gated at once, each in its own scratch
+worktree.
+
+N defaults to merge_queue.batch_size in the rig config (1 = one MR at a time).
+
+Examples:
+  gt mq batch gastown                # Merge the next batch
+  gt mq batch gastown --size 8       # Override the batch size
+  gt mq batch gastown --dry-run      # Show which MRs would be batched`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQBatch,
+}
+
+func init() {
+	mqBatchCmd.Flags().IntVar(&mqBatchSize, "size", 0, "Number of MRs to stack (default: merge_queue.batch_size)")
+	mqBatchCmd.Flags().BoolVarP(&mqBatchDryRun, "dry-run", "n", false, "Show the batch without merging")
+	mqBatchCmd.Flags().BoolVar(&mqBatchJSON, "json", false, "Output as JSON")
+
+	mqCmd.AddCommand(mqBatchCmd)
+}
+
+// mqBatchItem is the JSON form of one MR's batch outcome.
+type mqBatchItem struct {
+	ID          string `json:"id"`
+	Branch      string `json:"branch"`
+	Outcome     string `json:"outcome"`
+	MergeCommit string `json:"merge_commit,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+func runMQBatch(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+
+	_, r, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+
+	size := eng.BatchSize()
+	if mqBatchSize > 0 {
+		size = mqBatchSize
+	}
+
+	ready, err := eng.ListReadyMRs()
+	if err != nil {
+		return fmt.Errorf("listing ready MRs: %w", err)
+	}
+	scorer := eng.NewScorer(ready, time.Now())
+	batch := refinery.SelectBatch(ready, size, scorer)
+	if len(batch) == 0 {
+		if mqBatchJSON {
+			return outputJSON([]mqBatchItem{})
+		}
+		fmt.Printf("%s No ready merge requests in queue\n", style.Dim.Render("ℹ"))
+		return nil
+	}
+
+	if mqBatchDryRun {
+		if mqBatchJSON {
+			items := make([]mqBatchItem, len(batch))
+			for i, mr := range batch {
+				items[i] = mqBatchItem{ID: mr.ID, Branch: mr.Branch, Outcome: "selected"}
+			}
+			return outputJSON(items)
+		}
+		fmt.Printf("%s Next batch for '%s' (%d of %d ready, target %s):\n\n",
+			style.Bold.Render("📦"), rigName, len(batch), len(ready), batch[0].Target)
+		for i, mr := range batch {
+			fmt.Printf("  %d. %s  %s  %s\n", i+1, mr.ID, mr.Branch, style.Dim.Render(fmt.Sprintf("score %.1f", scorer.Score(mr))))
+		}
+		return nil
+	}
+
+	// Claim the batch so a concurrent patrol doesn't pick the same MRs.
+	workerID := rigName + "/refinery"
+	var claimed []*refinery.MRInfo
+	for _, mr := range batch {
+		if err := eng.ClaimMR(mr.ID, workerID); err != nil {
+			style.PrintWarning("could not claim %s: %v", mr.ID, err)
+			continue
+		}
+		claimed = append(claimed, mr)
+	}
+	if len(claimed) == 0 {
+		return fmt.Errorf("could not claim any MR in the batch")
+	}
+
+	if mqBatchJSON {
+		eng.SetOutput(cmd.ErrOrStderr())
+	}
+	result := eng.ProcessBatch(context.Background(), claimed)
+
+	items := make([]mqBatchItem, 0, len(result.Items))
+	for _, item := range result.Items {
+		switch item.Outcome {
+		case refinery.BatchMerged:
+			eng.HandleMRInfoSuccess(item.MR, item.Result)
+		case refinery.BatchFailed:
+			eng.HandleMRInfoFailure(item.MR, item.Result)
+			if err := eng.ReleaseMR(item.MR.ID); err != nil {
+				style.PrintWarning("could not release %s: %v", item.MR.ID, err)
+			}
+		default:
+			if err := eng.ReleaseMR(item.MR.ID); err != nil {
+				style.PrintWarning("could not release %s: %v", item.MR.ID, err)
+			}
+		}
+		items = append(items, mqBatchItem{
+			ID:          item.MR.ID,
+			Branch:      item.MR.Branch,
+			Outcome:     string(item.Outcome),
+			MergeCommit: item.Result.MergeCommit,
+			Error:       item.Result.Error,
+		})
+	}
+
+	if mqBatchJSON {
+		return outputJSON(items)
+	}
+
+	fmt.Printf("\n%s Batch into %s: %d merged, %d failed, %d deferred (%d gate run(s))\n",
+		style.Bold.Render("📦"), result.Target,
+		result.Count(refinery.BatchMerged), result.Count(refinery.BatchFailed),
+		result.Count(refinery.BatchDeferred), result.GateRuns)
+	for _, item := range items {
+		switch refinery.BatchOutcome(item.Outcome) {
+		case refinery.BatchMerged:
+			sha := item.MergeCommit
+			if len(sha) > 8 {
+				sha = sha[:8]
+			}
+			fmt.Printf("  %s %s  %s\n", style.Success.Render("✓"), item.ID, style.Dim.Render(sha))
+		case refinery.BatchFailed:
+			fmt.Printf("  %s %s  %s\n", style.Error.Render("✗"), item.ID, item.Error)
+		default:
+			fmt.Printf("  %s %s  %s\n", style.Dim.Render("↺"), item.ID, style.Dim.Render(item.Error))
+		}
+	}
+	return nil
+}
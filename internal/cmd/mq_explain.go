@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ explain command flags
+var (
+	mqExplainRig  string
+	mqExplainJSON bool
+)
+
+var mqExplainCmd = &cobra.Command{
+	Use:   "explain <mr-id>",
+	Short: "Show how an MR's priority score is made up",
+	Long: `Show the per-factor priority score breakdown for a queued merge request,
+and its position in the queue.
+
+Each factor's points are listed with the input that produced them:
+  base          Constant starting score
+  convoy_age    Older convoys rise (starvation prevention)
+  priority      P0 > P1 > P2 > P3 > P4
+  retry         Repeated conflicts sink (capped)
+  mr_age        FIFO tiebreaker
+  diff_size     Large diffs sink (capped)
+  overlap       Sharing files with other queued MRs sinks (capped)
+  failure_rate  Authors whose recent MRs failed sink
+  expedite      gt:expedite / expedite label jumps the queue
+
+Weights come from merge_queue.scoring in the rig settings
+(settings/config.json); unset weights use the defaults.
+
+Examples:
+  gt mq explain gt-mr-abc123              # Rig inferred from cwd
+  gt mq explain gt-mr-abc123 --rig gastown
+  gt mq explain gt-mr-abc123 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQExplain,
+}
+
+func init() {
+	mqExplainCmd.Flags().StringVar(&mqExplainRig, "rig", "", "Rig whose queue the MR is in (default: inferred from cwd)")
+	mqExplainCmd.Flags().BoolVar(&mqExplainJSON, "json", false, "Output as JSON")
+
+	mqCmd.AddCommand(mqExplainCmd)
+}
+
+// mqExplainOutput is the JSON form of gt mq explain.
+type mqExplainOutput struct {
+	ID         string                 `json:"id"`
+	Branch     string                 `json:"branch,omitempty"`
+	Target     string                 `json:"target,omitempty"`
+	Position   int                    `json:"position"`
+	QueueDepth int                    `json:"queue_depth"`
+	Score      float64                `json:"score"`
+	Factors    []refinery.ScoreFactor `json:"factors"`
+}
+
+func runMQExplain(cmd *cobra.Command, args []string) error {
+	mrID := args[0]
+
+	_, r, _, err := getRefineryManager(mqExplainRig)
+	if err != nil {
+		return err
+	}
+
+	b := beads.New(r.BeadsPath())
+	issues, err := b.List(beads.ListOptions{
+		Label:    "gt:merge-request",
+		Status:   "open",
+		Priority: -1, // No priority filter
+	})
+	if err != nil {
+		return fmt.Errorf("querying merge queue: %w", err)
+	}
+
+	var queue []*refinery.MRInfo
+	var target *refinery.MRInfo
+	for _, issue := range issues {
+		// Skip closed MRs (workaround for bd list not respecting --status filter)
+		if issue.Status != "open" {
+			continue
+		}
+		mr := refinery.MRInfoFromIssue(issue, beads.ParseMRFields(issue))
+		queue = append(queue, mr)
+		if issue.ID == mrID {
+			target = mr
+		}
+	}
+	if target == nil {
+		return fmt.Errorf("merge request '%s' is not in the %s queue", mrID, r.Name)
+	}
+
+	scorer := refinery.NewEngineer(r).NewScorer(queue, time.Now())
+	position := 0
+	for i, mr := range scorer.Rank(queue) {
+		if mr == target {
+			position = i + 1
+			break
+		}
+	}
+	breakdown := scorer.Explain(target)
+
+	if mqExplainJSON {
+		return outputJSON(mqExplainOutput{
+			ID:         target.ID,
+			Branch:     target.Branch,
+			Target:     target.Target,
+			Position:   position,
+			QueueDepth: len(queue),
+			Score:      breakdown.Total,
+			Factors:    breakdown.Factors,
+		})
+	}
+
+	fmt.Printf("%s %s  %s\n", style.Bold.Render("🔎"), target.ID, style.Dim.Render(target.Branch+" → "+target.Target))
+	fmt.Printf("  Position: %d of %d\n", position, len(queue))
+	fmt.Printf("  Score:    %.1f\n\n", breakdown.Total)
+	for _, f := range breakdown.Factors {
+		points := fmt.Sprintf("%+9.1f", f.Points)
+		switch {
+		case f.Points > 0:
+			points = style.Success.Render(points)
+		case f.Points < 0:
+			points = style.Error.Render(points)
+		default:
+			points = style.Dim.Render(points)
+		}
+		fmt.Printf("  %-13s %s  %s\n", f.Name, points, style.Dim.Render(f.Detail))
+	}
+	return nil
+}
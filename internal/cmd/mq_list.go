@@ -13,6 +13,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -75,7 +76,7 @@ func runMQList(cmd *cobra.Command, args []string) error {
 	}
 
 	// Apply additional filters and calculate scores
-	now := time.Now()
+	scorer := newMQScorer(r, issues, time.Now())
 	type scoredIssue struct {
 		issue          *beads.Issue
 		fields         *beads.MRFields
@@ -132,7 +133,7 @@ func runMQList(cmd *cobra.Command, args []string) error {
 		branchMissing, branchVerifyErr := verifyBranch(mqListVerify, gitClient, fields)
 
 		// Calculate priority score
-		score := calculateMRScore(issue, fields, now)
+		score := calculateMRScore(scorer, issue, fields)
 		scored = append(scored, scoredIssue{issue: issue, fields: fields, score: score, branchMissing: branchMissing, branchVerifyErr: branchVerifyErr})
 	}
 
@@ -353,38 +354,22 @@ func outputJSON(data interface{}) error {
 	return enc.Encode(data)
 }
 
-// calculateMRScore computes the priority score for an MR using the refinery scoring function.
-// Higher scores mean higher priority (process first).
-func calculateMRScore(issue *beads.Issue, fields *beads.MRFields, now time.Time) float64 {
-	// Parse MR creation time
-	mrCreatedAt, err := time.Parse(time.RFC3339, issue.CreatedAt)
-	if err != nil {
-		mrCreatedAt, err = time.Parse("2006-01-02T15:04:05Z", issue.CreatedAt)
-		if err != nil {
-			mrCreatedAt = now // Fallback to now if parsing fails
-		}
-	}
-
-	// Build score input
-	input := refinery.ScoreInput{
-		Priority:    issue.Priority,
-		MRCreatedAt: mrCreatedAt,
-		Now:         now,
-	}
-
-	// Add fields from MR metadata if available
-	if fields != nil {
-		input.RetryCount = fields.RetryCount
-
-		// Parse convoy created at if available
-		if fields.ConvoyCreatedAt != "" {
-			if convoyTime, err := time.Parse(time.RFC3339, fields.ConvoyCreatedAt); err == nil {
-				input.ConvoyCreatedAt = &convoyTime
-			}
+// newMQScorer returns a refinery scorer for the rig's open MRs among issues,
+// using the rig's scoring weights.
+func newMQScorer(r *rig.Rig, issues []*beads.Issue, now time.Time) *refinery.Scorer {
+	var queue []*refinery.MRInfo
+	for _, issue := range issues {
+		if issue.Status == "open" {
+			queue = append(queue, refinery.MRInfoFromIssue(issue, beads.ParseMRFields(issue)))
 		}
 	}
+	return refinery.NewEngineer(r).NewScorer(queue, now)
+}
 
-	return refinery.ScoreMRWithDefaults(input)
+// calculateMRScore computes the priority score for an MR using the refinery scoring function.
+// Higher scores mean higher priority (process first).
+func calculateMRScore(scorer *refinery.Scorer, issue *beads.Issue, fields *beads.MRFields) float64 {
+	return scorer.Score(refinery.MRInfoFromIssue(issue, fields))
 }
 
 // branchVerifier abstracts git branch existence checks for testability.
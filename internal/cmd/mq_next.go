@@ -27,6 +27,12 @@ The priority scoring function considers:
   - Issue priority: P0 > P1 > P2 > P3 > P4
   - Retry count: MRs that fail repeatedly get deprioritized
   - MR age: FIFO tiebreaker for same priority/convoy
+  - Diff size and file overlap: small, independent MRs go first
+  - Author failure rate: MRs from authors whose recent MRs failed go later
+  - Expedite label: gt:expedite jumps the queue
+
+Weights come from merge_queue.scoring in the rig settings. Use
+'gt mq explain' to see how an MR's score is made up.
 
 Use --strategy=fifo for first-in-first-out ordering instead.
 
@@ -90,7 +96,7 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	now := time.Now()
+	scorer := newMQScorer(r, ready, time.Now())
 
 	// Sort based on strategy
 	if mqNextStrategy == "fifo" {
@@ -109,7 +115,7 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 		scored := make([]scoredIssue, len(ready))
 		for i, issue := range ready {
 			fields := beads.ParseMRFields(issue)
-			score := calculateMRScore(issue, fields, now)
+			score := calculateMRScore(scorer, issue, fields)
 			scored[i] = scoredIssue{issue: issue, score: score}
 		}
 
@@ -140,7 +146,7 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 	// Human-readable output
 	fmt.Printf("%s Next MR to process:\n\n", style.Bold.Render("🎯"))
 
-	score := calculateMRScore(next, fields, now)
+	score := calculateMRScore(scorer, next, fields)
 
 	fmt.Printf("  ID:       %s\n", next.ID)
 	fmt.Printf("  Score:    %.1f\n", score)
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ pr command flags
+var mqPRJSON bool
+
+var mqPRCmd = &cobra.Command{
+	Use:   "pr <rig>",
+	Short: "Advance merge requests that land through pull requests",
+	Long: `Advance every ready MR with merge_strategy "pr" by one step.
+
+Work slung with --merge pr is not merged by the refinery itself. Instead, for
+each such MR this command:
+
+  1. Pushes the branch if needed and opens a pull request on the rig's forge
+  2. Waits for the forge's checks (merge_queue gates are not run)
+  3. Merges through the forge API once checks pass and branch protection
+     allows it
+  4. Turns new "changes requested" reviews and inline comments into a rework
+     task that the MR is blocked on until the worker addresses them
+
+MRs still waiting on checks or review stay in the queue. Run this every patrol
+cycle. Failing checks and conflicts are handled like local merge failures.
+
+The forge is configured in the rig's settings/config.json:
+
+  "forge": {
+    "type": "github",                 # or "gitea" (Forgejo too)
+    "url": "https://codeberg.org",    # gitea server; github defaults to api.github.com
+    "repo": "owner/name",             # default: from the rig's git_url
+    "token_env": "GITHUB_TOKEN",
+    "merge_method": "squash",         # merge, squash or rebase
+    "required_checks": ["ci/test"]    # default: every reported check
+  }
+
+Examples:
+  gt mq pr gastown
+  gt mq pr gastown --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQPR,
+}
+
+func init() {
+	mqPRCmd.Flags().BoolVar(&mqPRJSON, "json", false, "Output as JSON")
+
+	mqCmd.AddCommand(mqPRCmd)
+}
+
+// mqPRItem is the JSON form of one PR-strategy MR's step.
+type mqPRItem struct {
+	ID          string `json:"id"`
+	Branch      string `json:"branch"`
+	PR          int    `json:"pr,omitempty"`
+	Outcome     string `json:"outcome"` // merged, failed, pending
+	MergeCommit string `json:"merge_commit,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+func runMQPR(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+
+	_, r, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+	if mqPRJSON {
+		eng.SetOutput(cmd.ErrOrStderr())
+	}
+
+	ready, err := eng.ListReadyMRs()
+	if err != nil {
+		return fmt.Errorf("listing ready MRs: %w", err)
+	}
+
+	workerID := rigName + "/refinery"
+	items := []mqPRItem{}
+	for _, mr := range ready {
+		if mr.MergeStrategy != refinery.MergeStrategyPR {
+			continue
+		}
+		if err := eng.ClaimMR(mr.ID, workerID); err != nil {
+			style.PrintWarning("could not claim %s: %v", mr.ID, err)
+			continue
+		}
+
+		result := eng.ProcessMRInfo(context.Background(), mr)
+		item := mqPRItem{ID: mr.ID, Branch: mr.Branch, MergeCommit: result.MergeCommit, Error: result.Error}
+		switch {
+		case result.Success:
+			item.Outcome = "merged"
+			eng.HandleMRInfoSuccess(mr, result)
+		case result.Pending:
+			item.Outcome = "pending"
+		default:
+			item.Outcome = "failed"
+			eng.HandleMRInfoFailure(mr, result)
+		}
+		if !result.Success {
+			if err := eng.ReleaseMR(mr.ID); err != nil {
+				style.PrintWarning("could not release %s: %v", mr.ID, err)
+			}
+		}
+		item.PR = mr.PRNumber
+		items = append(items, item)
+	}
+
+	if mqPRJSON {
+		return outputJSON(items)
+	}
+	if len(items) == 0 {
+		fmt.Printf("%s No ready pull-request MRs in queue\n", style.Dim.Render("ℹ"))
+		return nil
+	}
+
+	fmt.Printf("\n%s Pull-request MRs for '%s':\n", style.Bold.Render("🔀"), rigName)
+	for _, item := range items {
+		pr := ""
+		if item.PR > 0 {
+			pr = fmt.Sprintf("PR #%d  ", item.PR)
+		}
+		switch item.Outcome {
+		case "merged":
+			sha := item.MergeCommit
+			if len(sha) > 8 {
+				sha = sha[:8]
+			}
+			fmt.Printf("  %s %s  %s%s\n", style.Success.Render("✓"), item.ID, pr, style.Dim.Render(sha))
+		case "failed":
+			fmt.Printf("  %s %s  %s%s\n", style.Error.Render("✗"), item.ID, pr, item.Error)
+		default:
+			fmt.Printf("  %s %s  %s%s\n", style.Dim.Render("…"), item.ID, pr, style.Dim.Render(item.Error))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ process command flags
+var (
+	mqProcessOnce bool
+	mqProcessJSON bool
+)
+
+var mqProcessCmd = &cobra.Command{
+	Use:   "process <rig>",
+	Short: "Merge ready MRs one at a time",
+	Long: `Work the merge queue one MR at a time, as the refinery's Engineer does.
+
+Each round takes the highest-scoring ready MR (the one 'gt mq next' shows),
+claims it, rebases or squashes it onto its target, runs the quality gates and
+pushes. A merged MR closes its source issue and attached molecule and
+notifies the witness; a failed MR gets a MERGE_FAILED or a conflict task and
+goes back to the queue. Rounds continue until no ready MR is left that this
+run has not already tried.
+
+Pull-request MRs (merge_strategy: pr) are left to 'gt mq pr'.
+
+Examples:
+  gt mq process gastown           # Drain the ready queue
+  gt mq process gastown --once    # Merge just the next MR`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQProcess,
+}
+
+func init() {
+	mqProcessCmd.Flags().BoolVar(&mqProcessOnce, "once", false, "Process a single MR and stop")
+	mqProcessCmd.Flags().BoolVar(&mqProcessJSON, "json", false, "Output as JSON")
+
+	mqCmd.AddCommand(mqProcessCmd)
+}
+
+// mqProcessItem is the JSON form of one MR's outcome.
+type mqProcessItem struct {
+	ID          string `json:"id"`
+	Branch      string `json:"branch"`
+	Outcome     string `json:"outcome"` // merged, failed, pending
+	MergeCommit string `json:"merge_commit,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+func runMQProcess(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+
+	_, r, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+	if mqProcessJSON {
+		eng.SetOutput(cmd.ErrOrStderr())
+	}
+
+	workerID := rigName + "/refinery"
+	tried := make(map[string]bool)
+	items := []mqProcessItem{}
+	for {
+		ready, err := eng.ListReadyMRs()
+		if err != nil {
+			return fmt.Errorf("listing ready MRs: %w", err)
+		}
+		var fresh []*refinery.MRInfo
+		for _, mr := range ready {
+			if !tried[mr.ID] {
+				fresh = append(fresh, mr)
+			}
+		}
+		next := refinery.SelectBatch(fresh, 1, eng.NewScorer(fresh, time.Now()))
+		if len(next) == 0 {
+			break
+		}
+		mr := next[0]
+		tried[mr.ID] = true
+
+		if err := eng.ClaimMR(mr.ID, workerID); err != nil {
+			style.PrintWarning("could not claim %s: %v", mr.ID, err)
+			continue
+		}
+		result := eng.ProcessMRInfo(context.Background(), mr)
+		item := mqProcessItem{ID: mr.ID, Branch: mr.Branch, MergeCommit: result.MergeCommit, Error: result.Error}
+		switch {
+		case result.Success:
+			item.Outcome = "merged"
+			eng.HandleMRInfoSuccess(mr, result)
+		case result.Pending:
+			item.Outcome = "pending"
+		default:
+			item.Outcome = "failed"
+			eng.HandleMRInfoFailure(mr, result)
+		}
+		if !result.Success {
+			if err := eng.ReleaseMR(mr.ID); err != nil {
+				style.PrintWarning("could not release %s: %v", mr.ID, err)
+			}
+		}
+		items = append(items, item)
+		if mqProcessOnce {
+			break
+		}
+	}
+
+	if mqProcessJSON {
+		return outputJSON(items)
+	}
+	if len(items) == 0 {
+		fmt.Printf("%s No ready merge requests in queue\n", style.Dim.Render("ℹ"))
+		return nil
+	}
+
+	fmt.Printf("\n%s Processed %d MR(s) for '%s':\n", style.Bold.Render("⚙"), len(items), rigName)
+	for _, item := range items {
+		switch item.Outcome {
+		case "merged":
+			sha := item.MergeCommit
+			if len(sha) > 8 {
+				sha = sha[:8]
+			}
+			fmt.Printf("  %s %s  %s\n", style.Success.Render("✓"), item.ID, style.Dim.Render(sha))
+		case "failed":
+			fmt.Printf("  %s %s  %s\n", style.Error.Render("✗"), item.ID, item.Error)
+		default:
+			fmt.Printf("  %s %s  %s\n", style.Dim.Render("↺"), item.ID, style.Dim.Render(item.Error))
+		}
+	}
+	return nil
+}
@@ -119,11 +119,16 @@ const ifFreshMaxAge = 60 * time.Second
 // This is a var (not const) so tests can override it to avoid 15s waits.
 var waitIdleTimeout = 15 * time.Second
 
+// nudgeSessions returns the backend nudges are delivered through.
+func nudgeSessions(townRoot string) session.Backend {
+	return session.NewBackend(townRoot)
+}
+
 // deliverNudge routes a nudge based on the --mode flag.
 // For "immediate" mode: sends directly via tmux (current behavior).
 // For "queue" mode: writes to the nudge queue for cooperative delivery.
 // For "wait-idle" mode: waits for idle, then delivers or falls back to queue.
-func deliverNudge(t *tmux.Tmux, sessionName, message, sender string) error {
+func deliverNudge(t session.Backend, sessionName, message, sender string) error {
 	townRoot, _ := workspace.FindFromCwd()
 
 	// For direct tmux delivery, prefix with sender attribution.
@@ -204,7 +209,7 @@ func runNudge(cmd *cobra.Command, args []string) error {
 	if nudgeIfFreshFlag {
 		sessionName := tmux.CurrentSessionName()
 		if sessionName != "" {
-			t := tmux.NewTmux()
+			t := sessionBackend()
 			created, err := t.GetSessionCreatedUnix(sessionName)
 			if err == nil && created > 0 {
 				age := time.Since(time.Unix(created, 0))
@@ -278,7 +283,7 @@ func runNudge(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	t := tmux.NewTmux()
+	t := nudgeSessions(townRoot)
 
 	// Expand role shortcuts to session names
 	// These shortcuts let users type "mayor" instead of "gt-mayor"
@@ -464,7 +469,7 @@ func runNudgeChannel(channelName, message, sender string) error {
 	}
 
 	// Send nudges via deliverNudge (respects --mode flag)
-	t := tmux.NewTmux()
+	t := nudgeSessions(townRoot)
 	var succeeded, failed, skipped int
 	var failures []string
 
@@ -23,6 +23,7 @@ var (
 	pluginRunDryRun   bool
 	pluginHistoryJSON bool
 	pluginHistoryLimit int
+	pluginGatesJSON   bool
 )
 
 var pluginCmd = &cobra.Command{
@@ -44,9 +45,14 @@ GATE TYPES:
   event       Run on events (e.g., startup)
   manual      Never auto-run, trigger explicitly
 
+The daemon evaluates gates on every heartbeat and dispatches plugins whose
+gate is open to a dog (see gt dog dispatch). Disable this with
+patrols.plugins.enabled=false in mayor/daemon.json.
+
 Examples:
   gt plugin list                    # List all discovered plugins
   gt plugin show <name>             # Show plugin details
+  gt plugin gates                   # Show which gates are open now
   gt plugin list --json             # JSON output`,
 	RunE: requireSubcommand,
 }
@@ -113,6 +119,20 @@ Examples:
 	RunE: runPluginHistory,
 }
 
+var pluginGatesCmd = &cobra.Command{
+	Use:   "gates",
+	Short: "Evaluate plugin gates without dispatching",
+	Long: `Evaluate every plugin's gate the way the daemon does, without dispatching.
+
+Condition gates run their check command. Event gates are shown as waiting,
+since events are only consumed by the daemon.
+
+Examples:
+  gt plugin gates
+  gt plugin gates --json`,
+	RunE: runPluginGates,
+}
+
 func init() {
 	// List subcommand flags
 	pluginListCmd.Flags().BoolVar(&pluginListJSON, "json", false, "Output as JSON")
@@ -128,11 +148,15 @@ func init() {
 	pluginHistoryCmd.Flags().BoolVar(&pluginHistoryJSON, "json", false, "Output as JSON")
 	pluginHistoryCmd.Flags().IntVar(&pluginHistoryLimit, "limit", 10, "Maximum number of runs to show")
 
+	// Gates subcommand flags
+	pluginGatesCmd.Flags().BoolVar(&pluginGatesJSON, "json", false, "Output as JSON")
+
 	// Add subcommands
 	pluginCmd.AddCommand(pluginListCmd)
 	pluginCmd.AddCommand(pluginShowCmd)
 	pluginCmd.AddCommand(pluginRunCmd)
 	pluginCmd.AddCommand(pluginHistoryCmd)
+	pluginCmd.AddCommand(pluginGatesCmd)
 
 	rootCmd.AddCommand(pluginCmd)
 }
@@ -450,6 +474,43 @@ func runPluginRun(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runPluginGates(cmd *cobra.Command, args []string) error {
+	scanner, townRoot, err := getPluginScanner()
+	if err != nil {
+		return err
+	}
+
+	plugins, err := scanner.DiscoverAll()
+	if err != nil {
+		return fmt.Errorf("discovering plugins: %w", err)
+	}
+
+	state, err := plugin.LoadGateState(townRoot)
+	if err != nil {
+		return err
+	}
+	decisions := plugin.NewGateEvaluator(townRoot, plugin.NewRecorder(townRoot), state).Evaluate(plugins, nil)
+
+	if pluginGatesJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(decisions)
+	}
+
+	if len(decisions) == 0 {
+		fmt.Println("No plugins discovered.")
+		return nil
+	}
+	for _, d := range decisions {
+		mark := style.Dim.Render("○")
+		if d.Open {
+			mark = style.Success.Render("●")
+		}
+		fmt.Printf("%s %-24s %-10s %s\n", mark, d.Name, d.Gate, style.Dim.Render(d.Reason))
+	}
+	return nil
+}
+
 func runPluginHistory(cmd *cobra.Command, args []string) error {
 	name := args[0]
 
@@ -17,7 +17,6 @@ import (
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/util"
 )
 
@@ -346,6 +345,7 @@ type PolecatListItem struct {
 	Name           string        `json:"name"`
 	State          polecat.State `json:"state"`
 	Issue          string        `json:"issue,omitempty"`
+	Machine        string        `json:"machine,omitempty"`
 	SessionRunning bool          `json:"session_running"`
 	Zombie         bool          `json:"zombie,omitempty"`
 	SessionName    string        `json:"session_name,omitempty"`
@@ -359,7 +359,7 @@ func getPolecatManager(rigName string) (*polecat.Manager, *rig.Rig, error) {
 	}
 
 	polecatGit := git.NewGit(r.Path)
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	mgr := polecat.NewManager(r, polecatGit, t)
 
 	return mgr, r, nil
@@ -388,7 +388,7 @@ func runPolecatList(cmd *cobra.Command, args []string) error {
 	}
 
 	// Collect polecats from all rigs
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	allPolecats := make([]PolecatListItem, 0)
 
 	for _, r := range rigs {
@@ -411,6 +411,7 @@ func runPolecatList(cmd *cobra.Command, args []string) error {
 				Name:           p.Name,
 				State:          p.State,
 				Issue:          p.Issue,
+				Machine:        p.Machine,
 				SessionRunning: running,
 			})
 			knownNames[p.Name] = true
@@ -484,7 +485,11 @@ func runPolecatList(cmd *cobra.Command, args []string) error {
 			stateStr = style.Dim.Render(stateStr)
 		}
 
-		fmt.Printf("  %s %s/%s  %s\n", sessionStatus, p.Rig, p.Name, stateStr)
+		if p.Machine != "" {
+			fmt.Printf("  %s %s/%s  %s  %s\n", sessionStatus, p.Rig, p.Name, stateStr, style.Dim.Render("@"+p.Machine))
+		} else {
+			fmt.Printf("  %s %s/%s  %s\n", sessionStatus, p.Rig, p.Name, stateStr)
+		}
 		if p.Issue != "" {
 			fmt.Printf("    %s\n", style.Dim.Render(p.Issue))
 		}
@@ -536,7 +541,7 @@ func runPolecatRemove(cmd *cobra.Command, args []string) error {
 	}
 
 	// Remove each polecat
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	var removeErrors []string
 	removed := 0
 
@@ -592,6 +597,7 @@ type PolecatStatus struct {
 	State          polecat.State `json:"state"`
 	Issue          string        `json:"issue,omitempty"`
 	ClonePath      string        `json:"clone_path"`
+	Machine        string        `json:"machine,omitempty"`
 	Branch         string        `json:"branch"`
 	SessionRunning bool          `json:"session_running"`
 	SessionID      string        `json:"session_id,omitempty"`
@@ -619,7 +625,7 @@ func runPolecatStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get session info
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	polecatMgr := polecat.NewSessionManager(t, r)
 	sessInfo, err := polecatMgr.Status(polecatName)
 	if err != nil {
@@ -638,6 +644,7 @@ func runPolecatStatus(cmd *cobra.Command, args []string) error {
 			State:          p.State,
 			Issue:          p.Issue,
 			ClonePath:      p.ClonePath,
+			Machine:        p.Machine,
 			Branch:         p.Branch,
 			SessionRunning: sessInfo.Running,
 			SessionID:      sessInfo.SessionID,
@@ -680,6 +687,9 @@ func runPolecatStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	// Clone path and branch
+	if p.Machine != "" {
+		fmt.Printf("  Machine:       %s\n", p.Machine)
+	}
 	fmt.Printf("  Clone:         %s\n", style.Dim.Render(p.ClonePath))
 	fmt.Printf("  Branch:        %s\n", style.Dim.Render(p.Branch))
 
@@ -1180,7 +1190,7 @@ func runPolecatNuke(cmd *cobra.Command, args []string) error {
 // 4. Close agent bead
 // This is the canonical cleanup path used by both `polecat nuke` and `polecat stale --cleanup`.
 func nukePolecatFull(polecatName, rigName string, mgr *polecat.Manager, r *rig.Rig) error {
-	t := tmux.NewTmux()
+	t := sessionBackend()
 
 	// Step 1: Kill tmux session unconditionally to prevent ghost sessions
 	// when IsRunning fails to detect the session.
@@ -10,14 +10,16 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/connection"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -30,10 +32,13 @@ type SpawnedPolecatInfo struct {
 	Pane        string // Tmux pane ID (empty until StartSession is called)
 	DoltBranch  string // Dolt branch for write isolation (empty if not created)
 	BaseBranch  string // Effective base branch (e.g., "main", "integration/epic-id")
+	Machine     string // Registered machine hosting the polecat (empty = local)
 
 	// Internal fields for deferred session start
+	rigPath string
 	account string
 	agent   string
+	span    *telemetry.Span // spawn trace span, ended when the session starts
 }
 
 // AgentID returns the agent identifier (e.g., "gastown/polecats/Toast")
@@ -41,6 +46,21 @@ func (s *SpawnedPolecatInfo) AgentID() string {
 	return fmt.Sprintf("%s/polecats/%s", s.RigName, s.PolecatName)
 }
 
+// IsRemote returns true if the polecat was placed on another machine.
+func (s *SpawnedPolecatInfo) IsRemote() bool {
+	return s.Machine != "" && s.Machine != "local"
+}
+
+// LocalWorkDir returns a local directory for running bd/gt commands on
+// behalf of the polecat. Remote worktrees aren't visible locally, so remote
+// polecats use the rig directory instead (beads are shared town-wide).
+func (s *SpawnedPolecatInfo) LocalWorkDir() string {
+	if s.IsRemote() {
+		return s.rigPath
+	}
+	return s.ClonePath
+}
+
 // SessionStarted returns true if the tmux session has been started.
 func (s *SpawnedPolecatInfo) SessionStarted() bool {
 	return s.Pane != ""
@@ -54,6 +74,7 @@ type SlingSpawnOptions struct {
 	HookBead   string // Bead ID to set as hook_bead at spawn time (atomic assignment)
 	Agent      string // Agent override for this spawn (e.g., "gemini", "codex", "claude-haiku")
 	BaseBranch string // Override base branch for polecat worktree (e.g., "develop", "release/v2")
+	Machine    string // Registered machine to run the polecat on (empty = local)
 }
 
 // SpawnPolecatForSling creates a fresh polecat and optionally starts its session.
@@ -65,6 +86,7 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	if err != nil {
 		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
+	span := telemetry.StartSpan(townRoot, "polecat.spawn", telemetry.Labels{"rig": rigName})
 
 	// Load rig config
 	rigsConfigPath := filepath.Join(townRoot, "mayor", "rigs.json")
@@ -82,7 +104,7 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 
 	// Get polecat manager (with tmux for session-aware allocation)
 	polecatGit := git.NewGit(r.Path)
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	polecatMgr := polecat.NewManager(r, polecatGit, t)
 
 	// Pre-spawn Dolt health check (gt-94llt7): verify Dolt is reachable before
@@ -97,6 +119,17 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		return nil, fmt.Errorf("admission control: %w", err)
 	}
 
+	isRemote := opts.Machine != "" && opts.Machine != "local"
+	if isRemote {
+		reg, err := connection.LoadTownRegistry(townRoot)
+		if err != nil {
+			return nil, fmt.Errorf("loading machine registry: %w", err)
+		}
+		if _, err := reg.Get(opts.Machine); err != nil {
+			return nil, fmt.Errorf("%w\nRegister it with: gt machine add %s --host user@host --town-path <path>", err, opts.Machine)
+		}
+	}
+
 	// Allocate a new polecat name
 	polecatName, err := polecatMgr.AllocateName()
 	if err != nil {
@@ -136,9 +169,14 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	addOpts := polecat.AddOptions{
 		HookBead:   opts.HookBead,
 		BaseBranch: baseBranch,
+		Machine:    opts.Machine,
 	}
 
-	if err == nil {
+	if err == nil && isRemote {
+		// Worktree repair is local-only; a stale remote placement must be nuked first.
+		return nil, fmt.Errorf("polecat '%s' already exists with stale state\nClean it up with: gt polecat nuke --force %s/%s",
+			polecatName, rigName, polecatName)
+	} else if err == nil {
 		// Stale state: polecat exists despite fresh name allocation - repair it
 		// Check for uncommitted work first
 		if !opts.Force {
@@ -183,8 +221,11 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	}
 
 	// Verify worktree was actually created (fixes #1070)
-	// The identity bead may exist but worktree creation can fail silently
-	if err := verifyWorktreeExists(polecatObj.ClonePath); err != nil {
+	// The identity bead may exist but worktree creation can fail silently.
+	// Remote worktrees were verified by git on the remote during creation.
+	if isRemote {
+		fmt.Printf("  Worktree on %s: %s\n", opts.Machine, polecatObj.ClonePath)
+	} else if err := verifyWorktreeExists(polecatObj.ClonePath); err != nil {
 		// Clean up the partial state before returning error
 		_ = polecatMgr.Remove(polecatName, true) // force=true to clean up partial state
 		return nil, fmt.Errorf("worktree verification failed for %s: %w\nHint: try 'gt polecat nuke %s/%s --force' to clean up",
@@ -220,8 +261,11 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		Pane:        "", // Empty until StartSession is called
 		DoltBranch:  doltBranch,
 		BaseBranch:  effectiveBranch,
+		Machine:     polecatObj.Machine,
+		rigPath:     r.Path,
 		account:     opts.Account,
 		agent:       opts.Agent,
+		span:        span,
 	}, nil
 }
 
@@ -261,7 +305,7 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 	}
 
 	// Start session
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	polecatSessMgr := polecat.NewSessionManager(t, r)
 
 	fmt.Printf("Starting session for %s/%s...\n", s.RigName, s.PolecatName)
@@ -281,6 +325,10 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 		return "", fmt.Errorf("starting session: %w", err)
 	}
 
+	if s.IsRemote() {
+		return s.startedRemote(r, t)
+	}
+
 	// Wait for runtime to be fully ready before returning.
 	spawnTownRoot := filepath.Dir(r.Path)
 	runtimeConfig := config.ResolveRoleAgentConfig("polecat", spawnTownRoot, r.Path)
@@ -316,9 +364,41 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 	}
 
 	s.Pane = pane
+	s.recordStarted(townRoot)
 	return pane, nil
 }
 
+// recordStarted records the polecat's spawn latency and remembers its spawn
+// time for the time-to-first-hook metric.
+func (s *SpawnedPolecatInfo) recordStarted(townRoot string) {
+	if s.span == nil {
+		return
+	}
+	s.span.SetAttr("polecat", s.PolecatName)
+	_ = s.span.End(nil)
+	_ = telemetry.ObserveDuration(townRoot, telemetry.PolecatSpawnSeconds,
+		telemetry.Labels{"rig": s.RigName}, time.Since(s.span.Start()))
+	_ = telemetry.MarkSpawned(townRoot, s.AgentID(), s.span.Start())
+	s.span = nil
+}
+
+// startedRemote finishes StartSession for a polecat on another machine.
+// There is no local pane to return, so the session is identified as
+// machine:session for display; nudges go through the SessionManager.
+func (s *SpawnedPolecatInfo) startedRemote(r *rig.Rig, t session.Backend) (string, error) {
+	polecatMgr := polecat.NewManager(r, git.NewGit(r.Path), t)
+	if err := polecatMgr.SetAgentStateWithRetry(s.PolecatName, "working"); err != nil {
+		style.PrintWarning("could not update agent state after retries: %v", err)
+	}
+	if err := polecatMgr.SetState(s.PolecatName, polecat.StateWorking); err != nil {
+		style.PrintWarning("could not update issue status to in_progress: %v", err)
+	}
+	s.Pane = s.Machine + ":" + s.SessionName
+	s.recordStarted(filepath.Dir(r.Path))
+	fmt.Printf("%s Session %s running on %s\n", style.Bold.Render("✓"), s.SessionName, s.Machine)
+	return s.Pane, nil
+}
+
 // CreateDoltBranch flushes the main working set to HEAD and creates the polecat's
 // Dolt branch. Must be called AFTER all sling writes (hook, formula, fields) so the
 // branch fork includes everything. This fixes the visibility gap where DOLT_BRANCH
@@ -15,6 +15,7 @@ import (
 	"github.com/steveyegge/gastown/internal/lock"
 	"github.com/steveyegge/gastown/internal/state"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -386,6 +387,10 @@ func checkSlungWork(ctx RoleContext) bool {
 	if hookedBead == nil {
 		return false
 	}
+	if ctx.Role == RolePolecat {
+		// First pickup after spawn completes the time-to-first-hook metric.
+		_ = telemetry.MarkHooked(ctx.TownRoot, getAgentIdentity(ctx), ctx.Rig)
+	}
 
 	attachment := beads.ParseAttachmentFields(hookedBead)
 	hasMolecule := attachment != nil && attachment.AttachedMolecule != ""
@@ -151,7 +151,7 @@ func resolveSessionIDForPrime(actor string) string {
 }
 
 // emitSessionEvent emits a session_start event for seance discovery.
-// The event is written to ~/gt/.events.jsonl and can be queried via gt seance.
+// The event is written to the event store (~/gt/.events/) and can be queried via gt seance.
 // Session ID resolution order: GT_SESSION_ID, CLAUDE_SESSION_ID, persisted file, fallback.
 func emitSessionEvent(ctx RoleContext) {
 	if ctx.Role == RoleUnknown {
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/pty"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// PTY command flags
+var (
+	ptyListJSON     bool
+	ptyCaptureLines int
+)
+
+var ptyCmd = &cobra.Command{
+	Use:     "pty",
+	GroupID: GroupServices,
+	Short:   "Inspect headless PTY sessions",
+	Long: `Inspect agent sessions run by the daemon's PTY supervisor.
+
+With session_backend set to "pty", agents run as child processes of the daemon on
+pseudo-terminals instead of inside tmux. Each session keeps a scrollback
+buffer that can be captured, and you can attach to watch or type into it.
+
+The supervisor lives in the daemon, so run 'gt config set session_backend pty'
+and restart the daemon before starting agents.
+
+Examples:
+  gt pty list
+  gt pty capture gt-gastown-witness -n 50
+  gt pty attach hq-deacon           # Ctrl-] detaches`,
+	RunE: requireSubcommand,
+}
+
+var ptyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List PTY sessions",
+	Args:  cobra.NoArgs,
+	RunE:  runPtyList,
+}
+
+var ptyAttachCmd = &cobra.Command{
+	Use:   "attach <session>",
+	Short: "Attach the terminal to a PTY session (Ctrl-] detaches)",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPtyAttach,
+}
+
+var ptyCaptureCmd = &cobra.Command{
+	Use:   "capture <session>",
+	Short: "Print recent output from a PTY session",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPtyCapture,
+}
+
+func init() {
+	ptyListCmd.Flags().BoolVar(&ptyListJSON, "json", false, "Output as JSON")
+	ptyCaptureCmd.Flags().IntVarP(&ptyCaptureLines, "lines", "n", 100, "Number of lines to capture (0 for all)")
+
+	ptyCmd.AddCommand(ptyListCmd)
+	ptyCmd.AddCommand(ptyAttachCmd)
+	ptyCmd.AddCommand(ptyCaptureCmd)
+	rootCmd.AddCommand(ptyCmd)
+}
+
+func ptyClient() (*pty.Client, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, err
+	}
+	return pty.NewClient(pty.SocketPath(townRoot)), nil
+}
+
+func runPtyList(cmd *cobra.Command, args []string) error {
+	c, err := ptyClient()
+	if err != nil {
+		return err
+	}
+	if !c.IsAvailable() {
+		return fmt.Errorf("%w: set session_backend to pty and restart the daemon", pty.ErrNoServer)
+	}
+	names, err := c.ListSessions()
+	if err != nil {
+		return err
+	}
+	infos := make([]*pty.Info, 0, len(names))
+	for _, name := range names {
+		info, err := c.Info(name)
+		if err != nil {
+			continue // exited between list and info
+		}
+		infos = append(infos, info)
+	}
+
+	if ptyListJSON {
+		return outputJSON(infos)
+	}
+	if len(infos) == 0 {
+		fmt.Println(style.Dim.Render("No PTY sessions"))
+		return nil
+	}
+	for _, info := range infos {
+		state := style.Success.Render("running")
+		if info.Exited {
+			state = style.Warning.Render(fmt.Sprintf("exited (%d)", info.ExitCode))
+		}
+		fmt.Printf("%s  %s  pid %d  up %s  idle %s", style.Bold.Render(info.Name), state, info.PID,
+			time.Since(info.Created).Round(time.Second), time.Since(info.Activity).Round(time.Second))
+		if info.Restarts > 0 {
+			fmt.Printf("  restarts %d", info.Restarts)
+		}
+		if info.Attached > 0 {
+			fmt.Printf("  %s", style.Dim.Render(fmt.Sprintf("(%d attached)", info.Attached)))
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+func runPtyAttach(cmd *cobra.Command, args []string) error {
+	c, err := ptyClient()
+	if err != nil {
+		return err
+	}
+	return c.Attach(args[0])
+}
+
+func runPtyCapture(cmd *cobra.Command, args []string) error {
+	c, err := ptyClient()
+	if err != nil {
+		return err
+	}
+	out, err := c.CapturePane(args[0], ptyCaptureLines)
+	if err != nil {
+		return err
+	}
+	fmt.Println(out)
+	return nil
+}
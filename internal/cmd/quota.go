@@ -6,11 +6,13 @@ import (
 	"maps"
 	"os"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/costs"
 	"github.com/steveyegge/gastown/internal/quota"
 	"github.com/steveyegge/gastown/internal/style"
 	ttmux "github.com/steveyegge/gastown/internal/tmux"
@@ -54,6 +56,14 @@ var quotaStatusCmd = &cobra.Command{
 Displays which accounts are available, rate-limited, or in cooldown,
 along with timestamps for limit detection and estimated reset times.
 
+Each account also gets a forecast of its current usage window, built from
+the Claude Code transcripts in its config dir: tokens and requests used,
+the recent burn rate, and when the window resets. Once the account has been
+seen rate-limited, the window length and token capacity are learned from
+the reset times the provider reported, and the forecast predicts when the
+account will run out. The daemon uses the same forecast to put accounts in
+cooldown and rotate idle sessions off them before they hit the limit.
+
 Examples:
   gt quota status           # Text output
   gt quota status --json    # JSON output`,
@@ -69,6 +79,10 @@ type QuotaStatusItem struct {
 	ResetsAt  string `json:"resets_at,omitempty"`
 	LastUsed  string `json:"last_used,omitempty"`
 	IsDefault bool   `json:"is_default"`
+
+	CooldownUntil string          `json:"cooldown_until,omitempty"`
+	Forecast      *quota.Forecast `json:"forecast,omitempty"`
+	WindowCostUSD float64         `json:"window_cost_usd,omitempty"`
 }
 
 func runQuotaStatus(cmd *cobra.Command, args []string) error {
@@ -102,13 +116,45 @@ func runQuotaStatus(cmd *cobra.Command, args []string) error {
 	// Ensure all accounts are tracked
 	mgr.EnsureAccountsTracked(state, acctCfg.Accounts)
 
+	forecasts := quotaForecasts(mgr, acctCfg, state)
+
 	if quotaJSON {
-		return printQuotaStatusJSON(acctCfg, state)
+		return printQuotaStatusJSON(acctCfg, state, forecasts)
 	}
-	return printQuotaStatusText(acctCfg, state)
+	return printQuotaStatusText(acctCfg, state, forecasts)
+}
+
+// quotaForecast is an account's usage forecast with the window's spend.
+type quotaForecast struct {
+	quota.Forecast
+	costUSD float64
 }
 
-func printQuotaStatusJSON(acctCfg *config.AccountsConfig, state *config.QuotaState) error {
+// quotaForecasts forecasts each account's current usage window. The usage
+// state is refreshed from transcripts in memory only; the daemon owns it.
+func quotaForecasts(mgr *quota.Manager, acctCfg *config.AccountsConfig, state *config.QuotaState) map[string]quotaForecast {
+	now := time.Now()
+	usage, err := mgr.LoadUsage()
+	if err != nil {
+		style.PrintWarning("%v", err)
+	}
+	for _, err := range quota.RefreshUsage(usage, acctCfg, state, now) {
+		style.PrintWarning("%v", err)
+	}
+	entries, _ := costs.ReadLedger(costs.LedgerPath())
+
+	forecasts := make(map[string]quotaForecast, len(acctCfg.Accounts))
+	for handle := range acctCfg.Accounts {
+		f := quotaForecast{Forecast: quota.ForecastUsage(handle, usage.Accounts[handle], now)}
+		if !f.WindowStart.IsZero() {
+			f.costUSD = quota.WindowCost(entries, handle, f.WindowStart)
+		}
+		forecasts[handle] = f
+	}
+	return forecasts
+}
+
+func printQuotaStatusJSON(acctCfg *config.AccountsConfig, state *config.QuotaState, forecasts map[string]quotaForecast) error {
 	var items []QuotaStatusItem
 	for _, handle := range slices.Sorted(maps.Keys(acctCfg.Accounts)) {
 		acct := acctCfg.Accounts[handle]
@@ -125,14 +171,20 @@ func printQuotaStatusJSON(acctCfg *config.AccountsConfig, state *config.QuotaSta
 			ResetsAt:  qs.ResetsAt,
 			LastUsed:  qs.LastUsed,
 			IsDefault: handle == acctCfg.Default,
+
+			CooldownUntil: qs.CooldownUntil,
 		})
+		if f, ok := forecasts[handle]; ok {
+			items[len(items)-1].Forecast = &f.Forecast
+			items[len(items)-1].WindowCostUSD = f.costUSD
+		}
 	}
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
 	return enc.Encode(items)
 }
 
-func printQuotaStatusText(acctCfg *config.AccountsConfig, state *config.QuotaState) error {
+func printQuotaStatusText(acctCfg *config.AccountsConfig, state *config.QuotaState, forecasts map[string]quotaForecast) error {
 	available := 0
 	limited := 0
 
@@ -168,6 +220,9 @@ func printQuotaStatusText(acctCfg *config.AccountsConfig, state *config.QuotaSta
 		case config.QuotaStatusCooldown:
 			badge = style.Warning.Render("cooldown")
 			limited++
+			if until, err := time.Parse(time.RFC3339, qs.CooldownUntil); err == nil {
+				badge += style.Dim.Render(" (until " + until.Local().Format("15:04") + ")")
+			}
 		default:
 			badge = style.Dim.Render("unknown")
 		}
@@ -178,6 +233,9 @@ func printQuotaStatusText(acctCfg *config.AccountsConfig, state *config.QuotaSta
 		}
 
 		fmt.Printf(" %s %-12s %s%s\n", marker, handle, badge, email)
+		if f, ok := forecasts[handle]; ok {
+			fmt.Printf("   %-12s %s\n", "", formatQuotaForecast(f))
+		}
 	}
 
 	fmt.Println()
@@ -187,6 +245,50 @@ func printQuotaStatusText(acctCfg *config.AccountsConfig, state *config.QuotaSta
 	return nil
 }
 
+// formatQuotaForecast renders a one-line usage forecast.
+func formatQuotaForecast(f quotaForecast) string {
+	window := "default " + f.Window.String()
+	if f.Learned {
+		window = fmt.Sprintf("learned %s from %d reset(s)", f.Window, f.Observations)
+	}
+	if f.WindowStart.IsZero() {
+		return style.Dim.Render("no usage in the current window (" + window + ")")
+	}
+
+	used := formatTokenCount(f.UsedTokens)
+	if f.CapacityTokens > 0 {
+		used += fmt.Sprintf(" of ~%s (%.0f%%)", formatTokenCount(f.CapacityTokens), 100*f.UsedFraction())
+	}
+	parts := []string{
+		fmt.Sprintf("window %s–%s (%s)", f.WindowStart.Local().Format("15:04"), f.WindowEnd.Local().Format("15:04"), window),
+		fmt.Sprintf("%s tokens, %d requests", used, f.Requests),
+		fmt.Sprintf("%s/h", formatTokenCount(int64(f.BurnPerHour))),
+	}
+	if f.costUSD > 0 {
+		parts = append(parts, fmt.Sprintf("$%.2f", f.costUSD))
+	}
+	line := style.Dim.Render(strings.Join(parts, " · "))
+	switch {
+	case !f.ExhaustsAt.IsZero():
+		line += " " + style.Warning.Render("exhausts ~"+f.ExhaustsAt.Local().Format("15:04"))
+	case f.CapacityTokens == 0:
+		line += " " + style.Dim.Render("· capacity not learned yet")
+	}
+	return line
+}
+
+// formatTokenCount abbreviates a token count (e.g. 1.2M, 340k).
+func formatTokenCount(n int64) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1_000:
+		return fmt.Sprintf("%.0fk", float64(n)/1_000)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
+
 // Scan command flags
 var (
 	scanUpdate bool
@@ -221,7 +323,7 @@ func runQuotaScan(cmd *cobra.Command, args []string) error {
 	// acctCfg can be nil if no accounts configured — scan still works
 
 	// Create scanner
-	t := ttmux.NewTmux()
+	t := sessionBackend()
 	scanner, err := quota.NewScanner(t, nil, acctCfg)
 	if err != nil {
 		return fmt.Errorf("creating scanner: %w", err)
@@ -315,7 +417,8 @@ func printScanText(results []quota.ScanResult) error {
 
 // Rotate command flags
 var (
-	rotateDryRun bool
+	rotateDryRun    bool
+	rotateProactive bool
 )
 
 var quotaRotateCmd = &cobra.Command{
@@ -332,8 +435,14 @@ The rotation process:
   3. Updates tmux session environment with new CLAUDE_CONFIG_DIR
   4. Restarts blocked sessions via respawn-pane
 
+With --proactive, sessions are moved off accounts in forecast cooldown
+(predicted to hit their limit before the window resets) instead of off
+blocked accounts. Only sessions idle at their prompt are rotated, so no
+in-flight turn is lost. The daemon runs this every heartbeat.
+
 Examples:
   gt quota rotate              # Rotate all blocked sessions
+  gt quota rotate --proactive  # Move idle sessions off accounts about to run out
   gt quota rotate --dry-run    # Show plan without executing
   gt quota rotate --json       # JSON output`,
 	RunE: runQuotaRotate,
@@ -363,13 +472,23 @@ func runQuotaRotate(cmd *cobra.Command, args []string) error {
 	}
 
 	mgr := quota.NewManager(townRoot)
-	plan, err := quota.PlanRotation(scanner, mgr, acctCfg)
+	var plan *quota.RotatePlan
+	if rotateProactive {
+		isIdle := func(session string) bool { return t.WaitForIdle(session, time.Second) == nil }
+		plan, err = quota.PlanProactiveRotation(scanner, mgr, acctCfg, isIdle)
+	} else {
+		plan, err = quota.PlanRotation(scanner, mgr, acctCfg)
+	}
 	if err != nil {
 		return fmt.Errorf("planning rotation: %w", err)
 	}
 
 	if len(plan.LimitedSessions) == 0 {
-		fmt.Printf(" %s No rate-limited sessions detected\n", style.SuccessPrefix)
+		if rotateProactive {
+			fmt.Printf(" %s No idle sessions on accounts in cooldown\n", style.SuccessPrefix)
+		} else {
+			fmt.Printf(" %s No rate-limited sessions detected\n", style.SuccessPrefix)
+		}
 		return nil
 	}
 
@@ -514,6 +633,7 @@ func init() {
 	quotaScanCmd.Flags().BoolVar(&scanUpdate, "update", false, "Update quota state with detected limits")
 
 	quotaRotateCmd.Flags().BoolVar(&rotateDryRun, "dry-run", false, "Show plan without executing")
+	quotaRotateCmd.Flags().BoolVar(&rotateProactive, "proactive", false, "Rotate idle sessions off accounts in forecast cooldown")
 	quotaRotateCmd.Flags().BoolVar(&quotaJSON, "json", false, "Output as JSON")
 
 	quotaCmd.AddCommand(quotaStatusCmd)
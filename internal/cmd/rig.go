@@ -24,7 +24,6 @@ import (
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/wisp"
 	"github.com/steveyegge/gastown/internal/witness"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -676,7 +675,7 @@ func runRigList(cmd *cobra.Command, args []string) error {
 	// Create rig manager to get details
 	g := git.NewGit(townRoot)
 	mgr := rig.NewManager(townRoot, rigsConfig, g)
-	t := tmux.NewTmux()
+	t := sessionBackend()
 
 	type rigInfo struct {
 		Name     string `json:"name"`
@@ -801,7 +800,7 @@ func runRigRemove(cmd *cobra.Command, args []string) error {
 	mgr := rig.NewManager(townRoot, rigsConfig, g)
 
 	// Check for running tmux sessions before removing
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	sessions, sessErr := findRigSessions(t, name)
 	if sessErr != nil {
 		if !rigRemoveForce {
@@ -1154,7 +1153,7 @@ func runRigReset(cmd *cobra.Command, args []string) error {
 
 // runResetStale resets in_progress issues whose assigned agent no longer has a session.
 func runResetStale(bd *beads.Beads, dryRun bool) error {
-	t := tmux.NewTmux()
+	t := sessionBackend()
 
 	// Get all in_progress issues
 	issues, err := bd.List(beads.ListOptions{
@@ -1314,7 +1313,7 @@ func runRigBoot(cmd *cobra.Command, args []string) error {
 	var started []string
 	var skipped []string
 
-	t := tmux.NewTmux()
+	t := sessionBackend()
 
 	// 1. Start the witness
 	// Check actual tmux session, not state file (may be stale)
@@ -1378,7 +1377,7 @@ func runRigStart(cmd *cobra.Command, args []string) error {
 
 	g := git.NewGit(townRoot)
 	rigMgr := rig.NewManager(townRoot, rigsConfig, g)
-	t := tmux.NewTmux()
+	t := sessionBackend()
 
 	var successRigs []string
 	var failedRigs []string
@@ -1503,7 +1502,7 @@ func runRigShutdown(cmd *cobra.Command, args []string) error {
 	var errors []string
 
 	// 1. Stop all polecat sessions
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	polecatMgr := polecat.NewSessionManager(t, r)
 	infos, err := polecatMgr.ListPolecats()
 	if err == nil && len(infos) > 0 {
@@ -1592,7 +1591,7 @@ func runRigStatus(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	t := tmux.NewTmux()
+	t := sessionBackend()
 
 	// Header
 	fmt.Printf("%s\n", style.Bold.Render(rigName))
@@ -1754,7 +1753,7 @@ func runRigStop(cmd *cobra.Command, args []string) error {
 		var errors []string
 
 		// 1. Stop all polecat sessions
-		t := tmux.NewTmux()
+		t := sessionBackend()
 		polecatMgr := polecat.NewSessionManager(t, r)
 		infos, err := polecatMgr.ListPolecats()
 		if err == nil && len(infos) > 0 {
@@ -1827,7 +1826,7 @@ func runRigRestart(cmd *cobra.Command, args []string) error {
 
 	g := git.NewGit(townRoot)
 	rigMgr := rig.NewManager(townRoot, rigsConfig, g)
-	t := tmux.NewTmux()
+	t := sessionBackend()
 
 	// Track results
 	var succeeded []string
@@ -2043,7 +2042,7 @@ func syncRigHooks(townRoot, rigName string) error {
 // findRigSessions returns all tmux sessions belonging to the given rig.
 // All rig sessions share the "<rigPrefix>-" prefix, so this catches witness,
 // refinery, polecat, and crew sessions in one pass.
-func findRigSessions(t *tmux.Tmux, rigName string) ([]string, error) {
+func findRigSessions(t session.Backend, rigName string) ([]string, error) {
 	prefix := session.PrefixFor(rigName) + "-"
 	all, err := t.ListSessions()
 	if err != nil {
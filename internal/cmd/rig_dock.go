@@ -13,7 +13,6 @@ import (
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/witness"
 )
 
@@ -119,7 +118,7 @@ func runRigDock(cmd *cobra.Command, args []string) error {
 
 	var stoppedAgents []string
 
-	t := tmux.NewTmux()
+	t := sessionBackend()
 
 	// Stop witness if running
 	witnessSession := session.WitnessSessionName(session.PrefixFor(rigName))
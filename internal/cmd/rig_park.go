@@ -7,7 +7,6 @@ import (
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/wisp"
 	"github.com/steveyegge/gastown/internal/witness"
 )
@@ -93,7 +92,7 @@ func parkOneRig(rigName string) error {
 
 	var stoppedAgents []string
 
-	t := tmux.NewTmux()
+	t := sessionBackend()
 
 	// Stop witness if running
 	witnessSession := session.WitnessSessionName(session.PrefixFor(rigName))
@@ -65,6 +65,9 @@ var beadsExemptCommands = map[string]bool{
 	"krc":           true, // KRC doesn't require beads
 	"run-migration":       true, // Migration orchestrator handles its own beads checks
 	"migrate-bead-labels": true, // Label migration handles its own beads access
+	"pty":                 true, // Talks only to the daemon's PTY supervisor
+	"metrics":             true, // Reads the telemetry snapshot only
+	"webhooks":            true, // Reads settings and the delivery queue only
 }
 
 // Commands exempt from the town root branch warning.
@@ -1,14 +1,12 @@
 package cmd
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"sort"
 	"strings"
 	"time"
 
@@ -58,7 +56,7 @@ The --talk flag spawns: claude --fork-session --resume <id>
 This loads the predecessor's full context without modifying their session.
 
 Sessions are discovered from:
-  1. Events emitted by SessionStart hooks (~/gt/.events.jsonl)
+  1. Events emitted by SessionStart hooks (the ~/gt/.events/ event store)
   2. The [GAS TOWN] beacon makes sessions searchable in /resume`,
 	RunE: runSeance,
 }
@@ -135,7 +133,7 @@ func runSeanceList() error {
 
 	if len(filtered) == 0 {
 		fmt.Println("No session events found.")
-		fmt.Println(style.Dim.Render("Sessions are discovered from ~/gt/.events.jsonl"))
+		fmt.Println(style.Dim.Render("Sessions are discovered from the ~/gt/.events/ event store"))
 		fmt.Println(style.Dim.Render("Ensure SessionStart hooks emit session_start events"))
 		return nil
 	}
@@ -271,41 +269,23 @@ func runSeanceTalk(sessionID, prompt string) error {
 
 // discoverSessions reads session_start events from our event stream.
 func discoverSessions(townRoot string) ([]sessionEvent, error) {
-	eventsPath := filepath.Join(townRoot, events.EventsFile)
-
-	file, err := os.Open(eventsPath)
+	recs, err := events.OpenStore(townRoot).Query(events.Query{Types: []string{events.TypeSessionStart}})
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
-		}
 		return nil, err
 	}
-	defer file.Close()
 
-	var sessions []sessionEvent
-	scanner := bufio.NewScanner(file)
-
-	// Increase buffer for large lines
-	buf := make([]byte, 0, 64*1024)
-	scanner.Buffer(buf, 1024*1024)
-
-	for scanner.Scan() {
-		var event sessionEvent
-		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
-			continue
-		}
-
-		if event.Type == events.TypeSessionStart {
-			sessions = append(sessions, event)
-		}
+	// Most recent first
+	sessions := make([]sessionEvent, 0, len(recs))
+	for i := len(recs) - 1; i >= 0; i-- {
+		rec := recs[i]
+		sessions = append(sessions, sessionEvent{
+			Timestamp: rec.Timestamp,
+			Type:      rec.Type,
+			Actor:     rec.Actor,
+			Payload:   rec.Payload,
+		})
 	}
-
-	// Sort by timestamp descending (most recent first)
-	sort.Slice(sessions, func(i, j int) bool {
-		return sessions[i].Timestamp > sessions[j].Timestamp
-	})
-
-	return sessions, scanner.Err()
+	return sessions, nil
 }
 
 func getPayloadString(payload map[string]interface{}, key string) string {
@@ -17,11 +17,17 @@ import (
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/suggest"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/townlog"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
+// sessionBackend returns the session backend of the town containing the
+// current directory, or tmux outside a workspace.
+func sessionBackend() session.Backend {
+	townRoot, _ := workspace.FindFromCwd()
+	return session.NewBackend(townRoot)
+}
+
 // Session command flags
 var (
 	sessionIssue     string
@@ -231,7 +237,7 @@ func getSessionManager(rigName string) (*polecat.SessionManager, *rig.Rig, error
 		return nil, nil, err
 	}
 
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	polecatMgr := polecat.NewSessionManager(t, r)
 
 	return polecatMgr, r, nil
@@ -378,7 +384,7 @@ func runSessionList(cmd *cobra.Command, args []string) error {
 	}
 
 	// Collect sessions from all rigs
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	var allSessions []SessionListItem
 
 	for _, r := range rigs {
@@ -645,7 +651,7 @@ func runSessionCheck(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("%s Session Health Check\n\n", style.Bold.Render("🔍"))
 
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	totalChecked := 0
 	totalHealthy := 0
 	totalCrashed := 0
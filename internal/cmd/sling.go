@@ -14,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -43,6 +44,7 @@ Merge Strategy (--merge):
   gt sling gt-abc gastown --merge=direct  # Push branch directly to main
   gt sling gt-abc gastown --merge=mr      # Merge queue (default)
   gt sling gt-abc gastown --merge=local   # Keep on feature branch
+  gt sling gt-abc gastown --merge=pr      # Refinery lands it via a forge pull request
 
 Target Resolution:
   gt sling gt-abc                       # Self (current agent)
@@ -57,6 +59,8 @@ Spawning Options (when target is a rig):
   gt sling gp-abc greenplace --create               # Create polecat if missing
   gt sling gp-abc greenplace --force                # Ignore unread mail
   gt sling gp-abc greenplace --account work         # Use specific Claude account
+  gt sling gp-abc greenplace --machine build2       # Spawn polecat on a registered machine
+  gt sling gp-abc build2:greenplace                 # Same, using a machine:rig address
 
 Natural Language Args:
   gt sling gt-abc --args "patch release"
@@ -120,10 +124,11 @@ var (
 	slingNoConvoy      bool   // --no-convoy: skip auto-convoy creation
 	slingOwned         bool   // --owned: mark auto-convoy as caller-managed lifecycle
 	slingNoMerge       bool   // --no-merge: skip merge queue on completion (for upstream PRs/human review)
-	slingMerge         string // --merge: merge strategy for convoy (direct/mr/local)
+	slingMerge         string // --merge: merge strategy for convoy (direct/mr/local/pr)
 	slingNoBoot        bool   // --no-boot: skip wakeRigAgents (avoid witness/refinery boot and lock contention)
 	slingMaxConcurrent int    // --max-concurrent: limit concurrent spawns in batch mode
 	slingBaseBranch    string // --base-branch: override base branch for polecat worktree
+	slingMachine       string // --machine: registered machine to run new polecats on
 )
 
 func init() {
@@ -132,6 +137,7 @@ func init() {
 	slingCmd.Flags().BoolVarP(&slingDryRun, "dry-run", "n", false, "Show what would be done")
 	slingCmd.Flags().StringVar(&slingOnTarget, "on", "", "Apply formula to existing bead (implies wisp scaffolding)")
 	slingCmd.Flags().StringArrayVar(&slingVars, "var", nil, "Formula variable (key=value), can be repeated")
+	_ = slingCmd.RegisterFlagCompletionFunc("var", completeFormulaVars)
 	slingCmd.Flags().StringVarP(&slingArgs, "args", "a", "", "Natural language instructions for the executor (e.g., 'patch release')")
 	slingCmd.Flags().BoolVar(&slingStdin, "stdin", false, "Read --message and/or --args from stdin (avoids shell quoting issues)")
 
@@ -144,10 +150,11 @@ func init() {
 	slingCmd.Flags().BoolVar(&slingOwned, "owned", false, "Mark auto-convoy as caller-managed lifecycle (no automatic witness/refinery registration)")
 	slingCmd.Flags().BoolVar(&slingHookRawBead, "hook-raw-bead", false, "Hook raw bead without default formula (expert mode)")
 	slingCmd.Flags().BoolVar(&slingNoMerge, "no-merge", false, "Skip merge queue on completion (keep work on feature branch for review)")
-	slingCmd.Flags().StringVar(&slingMerge, "merge", "", "Merge strategy: direct (push to main), mr (merge queue, default), local (keep on branch)")
+	slingCmd.Flags().StringVar(&slingMerge, "merge", "", "Merge strategy: direct (push to main), mr (merge queue, default), local (keep on branch), pr (forge pull request)")
 	slingCmd.Flags().BoolVar(&slingNoBoot, "no-boot", false, "Skip rig boot after polecat spawn (avoids witness/refinery lock contention)")
 	slingCmd.Flags().IntVar(&slingMaxConcurrent, "max-concurrent", 0, "Limit concurrent polecat spawns in batch mode (0 = no limit)")
 	slingCmd.Flags().StringVar(&slingBaseBranch, "base-branch", "", "Override base branch for polecat worktree (e.g., 'develop', 'release/v2')")
+	slingCmd.Flags().StringVar(&slingMachine, "machine", "", "Run the spawned polecat on a registered machine (see gt machine list)")
 
 	rootCmd.AddCommand(slingCmd)
 }
@@ -170,10 +177,10 @@ func runSling(cmd *cobra.Command, args []string) error {
 	// Validate --merge flag if provided
 	if slingMerge != "" {
 		switch slingMerge {
-		case "direct", "mr", "local":
+		case "direct", "mr", "local", "pr":
 			// Valid
 		default:
-			return fmt.Errorf("invalid --merge value %q: must be direct, mr, or local", slingMerge)
+			return fmt.Errorf("invalid --merge value %q: must be direct, mr, local, or pr", slingMerge)
 		}
 	}
 
@@ -371,6 +378,17 @@ func runSling(cmd *cobra.Command, args []string) error {
 	if len(args) > 1 {
 		target = args[1]
 	}
+
+	// Check --var values against the formula's declared types before
+	// anything is spawned or cooked.
+	if err := validateSlingVars(formulaName, target, townRoot, beadFormulaVars...); err != nil {
+		return err
+	}
+
+	// Cost budgets with stop_sling hold new work for the rig or convoy.
+	if err := checkBudgetHold(townRoot, target, beadID); err != nil {
+		return err
+	}
 	resolved, err := resolveTarget(target, ResolveTargetOptions{
 		DryRun:     slingDryRun,
 		Force:      force,
@@ -382,6 +400,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 		BeadID:     beadID,
 		TownRoot:   townRoot,
 		BaseBranch: slingBaseBranch,
+		Machine:    slingMachine,
 	})
 	if err != nil {
 		return err
@@ -438,15 +457,17 @@ func runSling(cmd *cobra.Command, args []string) error {
 			if townRoot != "" {
 				router := mail.NewRouter(townRoot)
 				defer router.WaitPendingNotifications()
-				shutdownMsg := &mail.Message{
-					From:     "gt-sling",
-					To:       fmt.Sprintf("%s/witness", oldRigName),
-					Subject:  fmt.Sprintf("LIFECYCLE:Shutdown %s", oldPolecatName),
-					Body:     fmt.Sprintf("Reason: work_reassigned\nRequestedBy: %s\nBead: %s\nNewAssignee: %s", requester, beadID, targetAgent),
-					Type:     mail.TypeTask,
-					Priority: mail.PriorityHigh,
+				shutdownMsg, err := witness.NewLifecycleShutdownMessage("gt-sling", fmt.Sprintf("%s/witness", oldRigName), &witness.LifecycleShutdownPayload{
+					PolecatName: oldPolecatName,
+					Reason:      "work_reassigned",
+					RequestedBy: requester,
+					BeadID:      beadID,
+					NewAssignee: targetAgent,
+				})
+				if err == nil {
+					err = router.Send(shutdownMsg)
 				}
-				if err := router.Send(shutdownMsg); err != nil {
+				if err != nil {
 					fmt.Printf("%s Could not send shutdown to witness: %v\n", style.Dim.Render("Warning:"), err)
 				} else {
 					fmt.Printf("%s Sent LIFECYCLE:Shutdown to %s/witness for %s\n", style.Bold.Render("→"), oldRigName, oldPolecatName)
@@ -559,7 +580,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 			slingVars = append(rigCmdVars, slingVars...)
 		}
 
-		result, err := InstantiateFormulaOnBead(formulaName, beadID, info.Title, hookWorkDir, townRoot, false, slingVars)
+		result, err := InstantiateFormulaOnBead(formulaName, beadID, info.Title, hookWorkDir, townRoot, budgetTargetRig(targetAgent), false, slingVars)
 		if err != nil {
 			// If we spawned a fresh polecat (rig target), rollback the partial artifacts.
 			// Otherwise, a wisp creation failure (e.g., missing required vars) leaves an orphaned polecat.
@@ -578,6 +599,9 @@ func runSling(cmd *cobra.Command, args []string) error {
 
 		fmt.Printf("%s Formula wisp created: %s\n", style.Bold.Render("✓"), result.WispRootID)
 		fmt.Printf("%s Formula bonded to %s\n", style.Bold.Render("✓"), beadID)
+		if _, prov := formulaProvenance(formulaName, townRoot, budgetTargetRig(targetAgent)); prov != "" {
+			fmt.Printf("  Formula %s resolved from %s\n", formulaName, prov)
+		}
 
 		// Record attached molecule - will be stored in BASE bead (not wisp).
 		// The base bead is hooked, and its attached_molecule points to the wisp.
@@ -608,7 +632,11 @@ func runSling(cmd *cobra.Command, args []string) error {
 
 	// Log sling event to activity feed
 	actor := detectActor()
-	_ = events.LogFeed(events.TypeSling, actor, events.SlingPayload(beadID, targetAgent))
+	slingPayload := events.SlingPayload(beadID, targetAgent)
+	if formulaName != "" {
+		addFormulaProvenance(slingPayload, formulaName, townRoot, budgetTargetRig(targetAgent))
+	}
+	_ = events.LogFeed(events.TypeSling, actor, slingPayload)
 
 	// Update agent bead's hook_bead field (ZFC: agents track their current work)
 	// Skip if hook was already set atomically during polecat spawn - avoids "agent bead not found"
@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/formula"
 )
 
 // TestInstantiateFormulaOnBead verifies the helper function works correctly.
@@ -114,7 +118,7 @@ exit /b 0
 
 	// Test the helper function directly
 	extraVars := []string{"branch=polecat/furiosa/gt-abc123"}
-	result, err := InstantiateFormulaOnBead("mol-polecat-work", "gt-abc123", "Test Bug Fix", "", townRoot, false, extraVars)
+	result, err := InstantiateFormulaOnBead("mol-polecat-work", "gt-abc123", "Test Bug Fix", "", townRoot, "", false, extraVars)
 	if err != nil {
 		t.Fatalf("InstantiateFormulaOnBead failed: %v", err)
 	}
@@ -133,8 +137,8 @@ exit /b 0
 	}
 	logContent := string(logBytes)
 
-	if !strings.Contains(logContent, "cook mol-polecat-work") {
-		t.Errorf("cook command not found in log:\n%s", logContent)
+	if !regexp.MustCompile(`cook \S+/mol-polecat-work\.formula\.toml`).MatchString(logContent) {
+		t.Errorf("cook of the resolved formula file not found in log:\n%s", logContent)
 	}
 	if !strings.Contains(logContent, "mol wisp mol-polecat-work") {
 		t.Errorf("mol wisp command not found in log:\n%s", logContent)
@@ -211,7 +215,7 @@ exit /b 0
 	_ = os.Chdir(townRoot)
 
 	// Test with skipCook=true
-	_, err := InstantiateFormulaOnBead("mol-polecat-work", "gt-test", "Test", "", townRoot, true, nil)
+	_, err := InstantiateFormulaOnBead("mol-polecat-work", "gt-test", "Test", "", townRoot, "", true, nil)
 	if err != nil {
 		t.Fatalf("InstantiateFormulaOnBead failed: %v", err)
 	}
@@ -255,14 +259,120 @@ exit /b 0
 	t.Setenv("BD_LOG", logPath)
 	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
 
-	err := CookFormula("mol-polecat-work", townRoot, townRoot)
+	err := CookFormula("mol-polecat-work", townRoot, townRoot, "")
 	if err != nil {
 		t.Fatalf("CookFormula failed: %v", err)
 	}
 
+	// The embedded formula is staged in a temporary file for bd.
 	logBytes, _ := os.ReadFile(logPath)
-	if !strings.Contains(string(logBytes), "cook mol-polecat-work") {
-		t.Errorf("cook command not found in log")
+	staged := regexp.MustCompile(`cook (\S+/mol-polecat-work\.formula\.toml)`).FindStringSubmatch(string(logBytes))
+	if staged == nil {
+		t.Fatalf("cook of the embedded formula not found in log:\n%s", logBytes)
+	}
+	if _, err := os.Stat(staged[1]); !os.IsNotExist(err) {
+		t.Errorf("staged formula %s was not removed", staged[1])
+	}
+
+	// A rig override is cooked from the file gt resolved, as provenance records.
+	rigFormulas := filepath.Join(townRoot, "gastown", ".beads", "formulas")
+	if err := os.MkdirAll(rigFormulas, 0755); err != nil {
+		t.Fatal(err)
+	}
+	override := filepath.Join(rigFormulas, "mol-polecat-work.formula.toml")
+	if err := os.WriteFile(override, []byte("formula = \"mol-polecat-work\"\nversion = 99\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(logPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := CookFormula("mol-polecat-work", townRoot, townRoot, "gastown"); err != nil {
+		t.Fatalf("CookFormula failed: %v", err)
+	}
+	logBytes, _ = os.ReadFile(logPath)
+	if !strings.Contains(string(logBytes), "cook "+override) {
+		t.Errorf("cook of the rig override %s not found in log:\n%s", override, logBytes)
+	}
+}
+
+// TestCookFormula_Composed verifies a composed formula reaches bd flattened,
+// since bd doesn't understand gt's composition keys.
+func TestCookFormula_Composed(t *testing.T) {
+	townRoot := t.TempDir()
+
+	binDir := filepath.Join(townRoot, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatalf("mkdir binDir: %v", err)
+	}
+	cooked := filepath.Join(townRoot, "cooked.toml")
+	bdScript := `#!/bin/sh
+cp "$2" "${BD_COOKED}"
+exit 0
+`
+	bdScriptWindows := `@echo off
+copy "%2" "%BD_COOKED%" >nul
+exit /b 0
+`
+	_ = writeBDStub(t, binDir, bdScript, bdScriptWindows)
+
+	t.Setenv("BD_COOKED", cooked)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	if err := CookFormula("shiny-secure", townRoot, townRoot, ""); err != nil {
+		t.Fatalf("CookFormula failed: %v", err)
+	}
+
+	data, err := os.ReadFile(cooked)
+	if err != nil {
+		t.Fatalf("bd did not get a formula file: %v", err)
+	}
+	f, err := formula.Parse(data)
+	if err != nil {
+		t.Fatalf("cooked formula is not standalone: %v\n%s", err, data)
+	}
+	if f.Name != "shiny-secure" || f.GetStep("threat-model") == nil || f.GetStep("implement-security-prescan") == nil {
+		t.Errorf("cooked formula is not the flattened shiny-secure:\n%s", data)
+	}
+}
+
+// TestCookFormula_StepControlRefused verifies a formula using step control
+// never reaches bd, which would pour it without running it.
+func TestCookFormula_StepControlRefused(t *testing.T) {
+	townRoot := t.TempDir()
+
+	binDir := filepath.Join(townRoot, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatalf("mkdir binDir: %v", err)
+	}
+	logPath := filepath.Join(townRoot, "bd.log")
+	bdScript := `#!/bin/sh
+echo "CMD:$*" >> "${BD_LOG}"
+exit 0
+`
+	bdScriptWindows := `@echo off
+echo CMD:%*>>"%BD_LOG%"
+exit /b 0
+`
+	_ = writeBDStub(t, binDir, bdScript, bdScriptWindows)
+
+	t.Setenv("BD_LOG", logPath)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	rigFormulas := filepath.Join(townRoot, "gastown", ".beads", "formulas")
+	if err := os.MkdirAll(rigFormulas, 0755); err != nil {
+		t.Fatal(err)
+	}
+	src := "formula = \"build-all\"\n[vars.targets]\ndefault = \"linux,darwin\"\n[[steps]]\nid = \"build\"\ntitle = \"Build {{item}}\"\nforeach = \"targets\"\n"
+	if err := os.WriteFile(filepath.Join(rigFormulas, "build-all.formula.toml"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := CookFormula("build-all", townRoot, townRoot, "gastown")
+	if !errors.Is(err, formula.ErrStepControlUnsupported) {
+		t.Fatalf("CookFormula err = %v, want ErrStepControlUnsupported", err)
+	}
+	if _, err := os.Stat(logPath); !os.IsNotExist(err) {
+		t.Error("bd was called for a formula using step control")
 	}
 }
 
@@ -403,7 +513,7 @@ exit /b 0
 	t.Cleanup(func() { _ = os.Chdir(cwd) })
 	_ = os.Chdir(townRoot)
 
-	_, err := InstantiateFormulaOnBead("mol-polecat-work", "gt-abc123", "My Cool Feature", "", townRoot, false, nil)
+	_, err := InstantiateFormulaOnBead("mol-polecat-work", "gt-abc123", "My Cool Feature", "", townRoot, "", false, nil)
 	if err != nil {
 		t.Fatalf("InstantiateFormulaOnBead: %v", err)
 	}
@@ -13,7 +13,6 @@ import (
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -27,6 +26,13 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 		}
 	}
 
+	// Cost budgets with stop_sling hold new work for the rig or convoy.
+	for _, beadID := range beadIDs {
+		if err := checkBudgetHold(filepath.Dir(townBeadsDir), rigName, beadID); err != nil {
+			return err
+		}
+	}
+
 	// Cross-rig guard: check all beads match the target rig before spawning (gt-myecw)
 	if !slingForce {
 		townRoot := filepath.Dir(townBeadsDir)
@@ -37,6 +43,11 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 		}
 	}
 
+	// Check --var values against mol-polecat-work before spawning anything.
+	if err := validateFormulaVars("mol-polecat-work", slingVars, filepath.Dir(townBeadsDir), rigName, beadFormulaVars...); err != nil {
+		return err
+	}
+
 	if slingDryRun {
 		fmt.Printf("%s Batch slinging %d beads to rig '%s':\n", style.Bold.Render("🎯"), len(beadIDs), rigName)
 		fmt.Printf("  Would cook mol-polecat-work formula once\n")
@@ -140,6 +151,7 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			HookBead:   beadID, // Set atomically at spawn time
 			Agent:      slingAgent,
 			BaseBranch: slingBaseBranch,
+			Machine:    slingMachine,
 		}
 		spawnInfo, err := spawnPolecatForSling(rigName, spawnOpts)
 		if err != nil {
@@ -149,7 +161,7 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 		}
 
 		targetAgent := spawnInfo.AgentID()
-		hookWorkDir := spawnInfo.ClonePath
+		hookWorkDir := spawnInfo.LocalWorkDir()
 
 		// Auto-convoy: check if issue is already tracked
 		if !slingNoConvoy {
@@ -170,7 +182,7 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 		// Cook once (lazy), then instantiate for each bead
 		if !formulaCooked {
 			workDir := beads.ResolveHookDir(townRoot, beadID, hookWorkDir)
-			if err := CookFormula(formulaName, workDir, townRoot); err != nil {
+			if err := CookFormula(formulaName, workDir, townRoot, rigName); err != nil {
 				fmt.Printf("  %s Could not cook formula %s: %v\n", style.Dim.Render("Warning:"), formulaName, err)
 				// Fall back to raw hook if formula cook fails
 			} else {
@@ -188,7 +200,7 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			if spawnInfo.BaseBranch != "" && spawnInfo.BaseBranch != "main" {
 				batchVars = append(batchVars, fmt.Sprintf("base_branch=%s", spawnInfo.BaseBranch))
 			}
-			result, err := InstantiateFormulaOnBead(formulaName, beadID, info.Title, hookWorkDir, townRoot, true, batchVars)
+			result, err := InstantiateFormulaOnBead(formulaName, beadID, info.Title, hookWorkDir, townRoot, rigName, true, batchVars)
 			if err != nil {
 				// Best-effort: in batch mode, a formula instantiation failure should not abort or rollback the
 				// spawned polecat. We still hook the raw bead so work can proceed (e.g., missing required vars).
@@ -214,7 +226,11 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 
 		// Log sling event
 		actor := detectActor()
-		_ = events.LogFeed(events.TypeSling, actor, events.SlingPayload(beadToHook, targetAgent))
+		slingPayload := events.SlingPayload(beadToHook, targetAgent)
+		if attachedMoleculeID != "" {
+			addFormulaProvenance(slingPayload, formulaName, townRoot, rigName)
+		}
+		_ = events.LogFeed(events.TypeSling, actor, slingPayload)
 
 		// Update agent bead state
 		updateAgentHookBead(targetAgent, beadToHook, hookWorkDir, townBeadsDir)
@@ -314,7 +330,7 @@ func cleanupSpawnedPolecat(spawnInfo *SpawnedPolecatInfo, rigName string) {
 		return
 	}
 	polecatGit := git.NewGit(r.Path)
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	polecatMgr := polecat.NewManager(r, polecatGit, t)
 	if err := polecatMgr.Remove(spawnInfo.PolecatName, true); err != nil {
 		fmt.Printf("  %s Could not clean up orphaned polecat %s: %v\n",
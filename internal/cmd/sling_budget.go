@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/costs"
+)
+
+// budgetTargetRig returns the rig a sling target puts work in, or "" for
+// town-level and self targets.
+func budgetTargetRig(target string) string {
+	if rigName, ok := IsRigName(target); ok {
+		return rigName
+	}
+	if i := strings.Index(target, "/"); i > 0 {
+		if rigName, ok := IsRigName(target[:i]); ok {
+			return rigName
+		}
+	}
+	return ""
+}
+
+// checkBudgetHold refuses new work when a cost budget with the stop_sling
+// action is over its limit for the target rig or the bead's convoy.
+func checkBudgetHold(townRoot, target, beadID string) error {
+	if townRoot == "" {
+		return nil
+	}
+	state, err := costs.LoadBudgetState(townRoot, time.Now())
+	if err != nil || len(state.Breaches) == 0 {
+		return nil
+	}
+	convoy := ""
+	if beadID != "" && state.HasConvoySlingHolds() {
+		convoy = isTrackedByConvoy(beadID)
+	}
+	b := state.SlingHold(budgetTargetRig(target), convoy)
+	if b == nil {
+		return nil
+	}
+	return fmt.Errorf("budget %s is over its limit ($%.2f of $%.2f today) and stops new work\nReview with 'gt costs budget'; lift the hold with 'gt costs budget clear %s'",
+		b.Rule, b.SpentUSD, b.LimitUSD, b.Key)
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/dog"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -166,7 +165,7 @@ func DispatchToDog(dogName string, opts DogDispatchOptions) (*DogDispatchInfo, e
 	}
 
 	// Ensure dog session is running (start if needed)
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	sessMgr := dog.NewSessionManager(t, townRoot, mgr)
 
 	sessOpts := dog.SessionStartOptions{
@@ -195,7 +194,7 @@ func (d *DogDispatchInfo) StartDelayedSession() (string, error) {
 		return d.Pane, nil // Session was already started
 	}
 
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	mgr := dog.NewManager(d.townRoot, d.rigsConfig)
 	sessMgr := dog.NewSessionManager(t, d.townRoot, mgr)
 
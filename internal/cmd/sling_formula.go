@@ -12,7 +12,6 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -87,6 +86,9 @@ func runSlingFormula(args []string) error {
 	if len(args) > 1 {
 		target = args[1]
 	}
+	if err := validateSlingVars(formulaName, target, townRoot); err != nil {
+		return err
+	}
 	resolved, err := resolveTarget(target, ResolveTargetOptions{
 		DryRun:   slingDryRun,
 		Force:    slingForce,
@@ -134,11 +136,7 @@ func runSlingFormula(args []string) error {
 
 	// Step 1: Cook the formula (ensures proto exists)
 	fmt.Printf("  Cooking formula...\n")
-	cookArgs := []string{"cook", formulaName}
-	cookCmd := exec.Command("bd", cookArgs...)
-	cookCmd.Dir = formulaWorkDir
-	cookCmd.Stderr = os.Stderr
-	if err := cookCmd.Run(); err != nil {
+	if err := CookFormula(formulaName, formulaWorkDir, townRoot, budgetTargetRig(targetAgent)); err != nil {
 		rollbackSpawned("")
 		return fmt.Errorf("cooking formula: %w", err)
 	}
@@ -180,7 +178,7 @@ func runSlingFormula(args []string) error {
 	// Log sling event to activity feed (formula slinging)
 	actor := detectActor()
 	payload := events.SlingPayload(wispRootID, targetAgent)
-	payload["formula"] = formulaName
+	addFormulaProvenance(payload, formulaName, townRoot, budgetTargetRig(targetAgent))
 	_ = events.LogFeed(events.TypeSling, actor, payload)
 
 	// Update agent bead's hook_bead field (ZFC: agents track their current work)
@@ -258,7 +256,7 @@ func runSlingFormula(args []string) error {
 	} else {
 		prompt = fmt.Sprintf("Formula %s slung. Run `"+cli.Name()+" hook` to see your hook, then execute the steps.", formulaName)
 	}
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	if err := t.NudgePane(targetPane, prompt); err != nil {
 		// Graceful fallback for no-tmux mode
 		fmt.Printf("%s Could not nudge (no tmux?): %v\n", style.Dim.Render("○"), err)
@@ -18,7 +18,6 @@ import (
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -234,7 +233,7 @@ type beadFieldUpdates struct {
 	NoMerge          bool   // Skip merge queue on completion
 	Mode             string // Execution mode: "" (normal) or "ralph"
 	ConvoyID         string // Convoy bead ID (e.g., "hq-cv-abc")
-	MergeStrategy    string // Convoy merge strategy: "direct", "mr", "local"
+	MergeStrategy    string // Convoy merge strategy: "direct", "mr", "local", "pr"
 	ConvoyOwned      bool   // Convoy has gt:owned label (caller-managed lifecycle)
 }
 
@@ -348,7 +347,7 @@ func injectStartPrompt(pane, beadID, subject, args string) error {
 	}
 
 	// Use the reliable nudge pattern (same as gt nudge / tmux.NudgeSession)
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	return t.NudgePane(pane, prompt)
 }
 
@@ -377,7 +376,7 @@ func getSessionFromPane(pane string) string {
 // Uses a pragmatic approach: wait for the pane to leave a shell, then (Claude-only)
 // accept the bypass permissions warning and give it a moment to finish initializing.
 func ensureAgentReady(sessionName string) error {
-	t := tmux.NewTmux()
+	t := sessionBackend()
 
 	if t.IsAgentRunning(sessionName) {
 		// Agent process is detected, but it may have just started (fresh spawn).
@@ -592,7 +591,7 @@ func wakeRigAgents(rigName string) {
 	// nudges would be stuck forever. Direct delivery is safe: if the
 	// agent is busy, text buffers in tmux and is processed at next prompt.
 	witnessSession := session.WitnessSessionName(session.PrefixFor(rigName))
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	if err := t.NudgeSession(witnessSession, "Polecat dispatched - check for work"); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to nudge witness %s: %v\n", witnessSession, err)
 	}
@@ -617,7 +616,7 @@ func nudgeRefinery(rigName, message string) {
 		return // Don't actually nudge tmux in tests
 	}
 
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	if err := t.NudgeSession(refinerySession, message); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to nudge refinery %s: %v\n", refinerySession, err)
 	}
@@ -647,21 +646,18 @@ type FormulaOnBeadResult struct {
 //   - title: the bead title (used for --var feature=<title>)
 //   - hookWorkDir: working directory for bd commands (polecat's worktree)
 //   - townRoot: the town root directory
+//   - rigName: the target rig, whose project tier the formula resolves from
 //   - skipCook: if true, skip cooking (for batch mode optimization where cook happens once)
 //   - extraVars: additional --var values supplied by the user
 //
 // Returns the wisp root ID which should be hooked.
-func InstantiateFormulaOnBead(formulaName, beadID, title, hookWorkDir, townRoot string, skipCook bool, extraVars []string) (*FormulaOnBeadResult, error) {
+func InstantiateFormulaOnBead(formulaName, beadID, title, hookWorkDir, townRoot, rigName string, skipCook bool, extraVars []string) (*FormulaOnBeadResult, error) {
 	// Route bd mutations (wisp/bond) to the correct beads context for the target bead.
 	formulaWorkDir := beads.ResolveHookDir(townRoot, beadID, hookWorkDir)
 
 	// Step 1: Cook the formula (ensures proto exists)
 	if !skipCook {
-		cookCmd := exec.Command("bd", "cook", formulaName)
-		cookCmd.Dir = formulaWorkDir
-		cookCmd.Env = append(os.Environ(), "GT_ROOT="+townRoot)
-		cookCmd.Stderr = os.Stderr
-		if err := cookCmd.Run(); err != nil {
+		if err := CookFormula(formulaName, formulaWorkDir, townRoot, rigName); err != nil {
 			return nil, fmt.Errorf("cooking formula %s: %w", formulaName, err)
 		}
 	}
@@ -715,9 +711,16 @@ func InstantiateFormulaOnBead(formulaName, beadID, title, hookWorkDir, townRoot
 
 // CookFormula cooks a formula to ensure its proto exists.
 // This is useful for batch mode where we cook once before processing multiple beads.
+// bd is given the file gt resolves formulaName to for rigName (see cookSource),
+// so the formula cooked is the one recorded in the sling event's provenance.
 // townRoot is required for GT_ROOT so bd can find town-level formulas.
-func CookFormula(formulaName, workDir, townRoot string) error {
-	cookCmd := exec.Command("bd", "cook", formulaName)
+func CookFormula(formulaName, workDir, townRoot, rigName string) error {
+	source, cleanup, err := cookSource(formulaName, townRoot, rigName)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	cookCmd := exec.Command("bd", "cook", source)
 	cookCmd.Dir = workDir
 	cookCmd.Env = append(os.Environ(), "GT_ROOT="+townRoot)
 	cookCmd.Stderr = os.Stderr
@@ -736,7 +739,7 @@ func isHookedAgentDead(assignee string) bool {
 	if sessionName == "" {
 		return false // Unknown format, can't determine
 	}
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	alive, err := t.HasSession(sessionName)
 	if err != nil {
 		return false // tmux not available or error, be conservative
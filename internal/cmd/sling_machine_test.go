@@ -0,0 +1,29 @@
+package cmd
+
+import "testing"
+
+func TestSplitMachineTarget(t *testing.T) {
+	tests := []struct {
+		target      string
+		wantMachine string
+		wantRig     string
+		wantOK      bool
+	}{
+		{"gpu1:gastown", "gpu1", "gastown", true},
+		{"gpu1:gastown/", "gpu1", "gastown", true},
+		{"local:gastown", "", "gastown", true},
+		{"gastown", "", "", false},
+		{"gpu1:gastown/toast", "", "", false},
+		{":gastown", "", "", false},
+		{"gpu1:", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			machine, rigName, ok := splitMachineTarget(tt.target)
+			if ok != tt.wantOK || machine != tt.wantMachine || rigName != tt.wantRig {
+				t.Errorf("splitMachineTarget(%q) = (%q, %q, %v), want (%q, %q, %v)",
+					tt.target, machine, rigName, ok, tt.wantMachine, tt.wantRig, tt.wantOK)
+			}
+		})
+	}
+}
@@ -5,8 +5,8 @@ import (
 	"os"
 	"strings"
 
+	"github.com/steveyegge/gastown/internal/connection"
 	"github.com/steveyegge/gastown/internal/session"
-	"github.com/steveyegge/gastown/internal/tmux"
 )
 
 // spawnPolecatForSling is a seam for tests. Production uses SpawnPolecatForSling.
@@ -33,7 +33,7 @@ func resolveTargetAgent(target string) (agentID string, pane string, hookRoot st
 	}
 
 	// Get the target's working directory for hook storage
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	hookRoot, err = t.GetPaneWorkDir(sessionName)
 	if err != nil {
 		return "", "", "", fmt.Errorf("getting working dir for %s: %w", sessionName, err)
@@ -107,6 +107,7 @@ type ResolveTargetOptions struct {
 	TownRoot   string
 	WorkDesc   string // Description for dog dispatch (defaults to HookBead if empty)
 	BaseBranch string // Override base branch for polecat worktree
+	Machine    string // Registered machine for newly spawned polecats (empty = local)
 }
 
 // ResolvedTarget holds the results of target resolution.
@@ -175,6 +176,17 @@ func resolveTarget(target string, opts ResolveTargetOptions) (*ResolvedTarget, e
 		return result, nil
 	}
 
+	// machine:rig targets place the new polecat on a registered machine.
+	if machine, rigName, ok := splitMachineTarget(target); ok {
+		if machine != "" && opts.Machine != "" && opts.Machine != machine {
+			return nil, fmt.Errorf("target machine %q conflicts with --machine %q", machine, opts.Machine)
+		}
+		if machine != "" {
+			opts.Machine = machine
+		}
+		target = rigName
+	}
+
 	// Rig target (auto-spawn polecat)
 	if rigName, isRig := IsRigName(target); isRig {
 		if opts.BeadID != "" && !opts.Force {
@@ -183,6 +195,12 @@ func resolveTarget(target string, opts ResolveTargetOptions) (*ResolvedTarget, e
 			}
 		}
 		if opts.DryRun {
+			if opts.Machine != "" {
+				fmt.Printf("Would spawn fresh polecat in rig '%s' on machine '%s'\n", rigName, opts.Machine)
+				result.Agent = fmt.Sprintf("%s/polecats/<new>", rigName)
+				result.Pane = "<new-pane>"
+				return result, nil
+			}
 			fmt.Printf("Would spawn fresh polecat in rig '%s'\n", rigName)
 			result.Agent = fmt.Sprintf("%s/polecats/<new>", rigName)
 			result.Pane = "<new-pane>"
@@ -196,6 +214,7 @@ func resolveTarget(target string, opts ResolveTargetOptions) (*ResolvedTarget, e
 			HookBead:   opts.HookBead,
 			Agent:      opts.Agent,
 			BaseBranch: opts.BaseBranch,
+			Machine:    opts.Machine,
 		}
 		spawnInfo, err := spawnPolecatForSling(rigName, spawnOpts)
 		if err != nil {
@@ -203,7 +222,7 @@ func resolveTarget(target string, opts ResolveTargetOptions) (*ResolvedTarget, e
 		}
 		result.Agent = spawnInfo.AgentID()
 		result.NewPolecatInfo = spawnInfo
-		result.WorkDir = spawnInfo.ClonePath
+		result.WorkDir = spawnInfo.LocalWorkDir()
 		result.HookSetAtomically = opts.HookBead != ""
 		if !opts.NoBoot {
 			wakeRigAgents(rigName)
@@ -233,6 +252,7 @@ func resolveTarget(target string, opts ResolveTargetOptions) (*ResolvedTarget, e
 					HookBead:   opts.HookBead,
 					Agent:      opts.Agent,
 					BaseBranch: opts.BaseBranch,
+					Machine:    opts.Machine,
 				}
 				spawnInfo, spawnErr := spawnPolecatForSling(rigName, spawnOpts)
 				if spawnErr != nil {
@@ -240,7 +260,7 @@ func resolveTarget(target string, opts ResolveTargetOptions) (*ResolvedTarget, e
 				}
 				result.Agent = spawnInfo.AgentID()
 				result.NewPolecatInfo = spawnInfo
-				result.WorkDir = spawnInfo.ClonePath
+				result.WorkDir = spawnInfo.LocalWorkDir()
 				result.HookSetAtomically = opts.HookBead != ""
 				if !opts.NoBoot {
 					wakeRigAgents(rigName)
@@ -255,3 +275,20 @@ func resolveTarget(target string, opts ResolveTargetOptions) (*ResolvedTarget, e
 	result.WorkDir = workDir
 	return result, nil
 }
+
+// splitMachineTarget splits a "machine:rig" sling target (see connection.Address).
+// Returns ok=false for targets without a machine prefix or with a polecat part.
+// The local machine ("local:rig") yields an empty machine name.
+func splitMachineTarget(target string) (machine, rigName string, ok bool) {
+	if !strings.Contains(target, ":") {
+		return "", "", false
+	}
+	addr, err := connection.ParseAddress(strings.TrimSuffix(target, "/") + "/")
+	if err != nil || !addr.IsBroadcast() {
+		return "", "", false
+	}
+	if !addr.IsLocal() {
+		machine = addr.Machine
+	}
+	return machine, addr.Rig, true
+}
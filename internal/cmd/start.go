@@ -25,7 +25,6 @@ import (
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/witness"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -195,7 +194,7 @@ func runStart(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  %s Could not ensure daemon config: %v\n", style.Dim.Render("○"), err)
 	}
 
-	t := tmux.NewTmux()
+	t := sessionBackend()
 
 	// Clean up orphaned tmux sessions before starting new agents.
 	// This prevents session name conflicts and resource accumulation from
@@ -422,7 +421,7 @@ func startRefineryForRig(r *rig.Rig) string {
 }
 
 // startConfiguredCrew starts crew members configured in rig settings in parallel.
-func startConfiguredCrew(t *tmux.Tmux, rigs []*rig.Rig, townRoot string, mu *sync.Mutex) {
+func startConfiguredCrew(t session.Backend, rigs []*rig.Rig, townRoot string, mu *sync.Mutex) {
 	var wg sync.WaitGroup
 	var startedAny int32 // Use atomic for thread-safe flag
 
@@ -456,7 +455,7 @@ func startConfiguredCrew(t *tmux.Tmux, rigs []*rig.Rig, townRoot string, mu *syn
 // Uses IsAgentAlive for robust zombie detection (checks pane command + descendant processes),
 // and delegates zombie cleanup to crewMgr.Start() which kills the zombie session and recreates
 // it with fresh env vars and runtime settings.
-func startOrRestartCrewMember(t *tmux.Tmux, r *rig.Rig, crewName, townRoot string) (msg string, started bool) {
+func startOrRestartCrewMember(t session.Backend, r *rig.Rig, crewName, townRoot string) (msg string, started bool) {
 	sessionID := crewSessionName(r.Name, crewName)
 	if running, _ := t.HasSession(sessionID); running {
 		// Session exists - check if agent is still alive
@@ -502,7 +501,7 @@ func discoverAllRigs(townRoot string) ([]*rig.Rig, error) {
 }
 
 func runShutdown(cmd *cobra.Command, args []string) error {
-	t := tmux.NewTmux()
+	t := sessionBackend()
 
 	// Find workspace root for polecat cleanup
 	townRoot, _ := workspace.FindFromCwd()
@@ -603,7 +602,7 @@ func categorizeSessions(sessions []string) (toStop, preserved []string) {
 	return
 }
 
-func runGracefulShutdown(t *tmux.Tmux, gtSessions []string, townRoot string) error {
+func runGracefulShutdown(t session.Backend, gtSessions []string, townRoot string) error {
 	fmt.Printf("Graceful shutdown of Gas Town (waiting up to %ds)...\n\n", shutdownWait)
 
 	// Phase 1: Send ESC to all agents to interrupt them
@@ -676,7 +675,7 @@ func runGracefulShutdown(t *tmux.Tmux, gtSessions []string, townRoot string) err
 	return nil
 }
 
-func runImmediateShutdown(t *tmux.Tmux, gtSessions []string, townRoot string) error {
+func runImmediateShutdown(t session.Backend, gtSessions []string, townRoot string) error {
 	fmt.Println("Shutting down Gas Town...")
 
 	mayorSession := getMayorSessionName()
@@ -731,7 +730,7 @@ func runImmediateShutdown(t *tmux.Tmux, gtSessions []string, townRoot string) er
 //
 // Returns the count of sessions that were successfully stopped (verified by checking
 // if the session no longer exists after the kill attempt).
-func killSessionsInOrder(t *tmux.Tmux, sessions []string, mayorSession, deaconSession string) int {
+func killSessionsInOrder(t session.Backend, sessions []string, mayorSession, deaconSession string) int {
 	stopped := 0
 	bootSession := session.BootSessionName()
 
@@ -23,7 +23,6 @@ import (
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 	"golang.org/x/term"
 )
@@ -180,7 +179,7 @@ func resolveAgentDisplay(townSettings *config.TownSettings, role string, session
 // to determine what agent runtime and model are in use.
 func detectRuntimeFromSession(sessionName string) string {
 	// Get the PID of the shell process in the tmux pane
-	t := tmux.NewTmux()
+	t := sessionBackend()
 	pid, err := t.GetPanePID(sessionName)
 	if err != nil || pid == "" {
 		return ""
@@ -588,7 +587,7 @@ func gatherStatus() (TownStatus, error) {
 	mgr := rig.NewManager(townRoot, rigsConfig, g)
 
 	// Create tmux instance for runtime checks
-	t := tmux.NewTmux()
+	t := sessionBackend()
 
 	// Pre-fetch all tmux sessions and verify agent liveness for O(1) lookup.
 	// A Gas Town session is only considered "running" if the agent process is
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -355,7 +354,7 @@ func runTrailHooks(cmd *cobra.Command, args []string) error {
 		since = time.Now().Add(-duration)
 	}
 
-	entries, err := readHookTrailEntries(filepath.Join(townRoot, events.EventsFile), since, trailLimit)
+	entries, err := readHookTrailEntries(townRoot, since, trailLimit)
 	if err != nil {
 		return err
 	}
@@ -396,51 +395,26 @@ func runTrailHooks(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func readHookTrailEntries(eventsPath string, since time.Time, limit int) ([]HookEntry, error) {
+func readHookTrailEntries(townRoot string, since time.Time, limit int) ([]HookEntry, error) {
 	if limit <= 0 {
 		return []HookEntry{}, nil
 	}
 
-	data, err := os.ReadFile(eventsPath)
+	recs, err := events.OpenStore(townRoot).Query(events.Query{
+		Types: []string{events.TypeHook, events.TypeUnhook},
+		Since: since,
+		Match: func(e events.Event) bool { return !e.Time().IsZero() },
+		Limit: limit,
+	})
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("reading events file: %w", err)
-	}
-
-	trimmed := strings.TrimSpace(string(data))
-	if trimmed == "" {
-		return nil, nil
-	}
-
-	lines := strings.Split(trimmed, "\n")
-	entryCap := len(lines)
-	if limit < entryCap {
-		entryCap = limit
+		return nil, fmt.Errorf("reading events: %w", err)
 	}
-	entries := make([]HookEntry, 0, entryCap)
-	for i := len(lines) - 1; i >= 0; i-- {
-		line := strings.TrimSpace(lines[i])
-		if line == "" {
-			continue
-		}
-
-		var event events.Event
-		if err := json.Unmarshal([]byte(line), &event); err != nil {
-			continue
-		}
-		if event.Type != events.TypeHook && event.Type != events.TypeUnhook {
-			continue
-		}
 
-		ts, err := time.Parse(time.RFC3339, event.Timestamp)
-		if err != nil {
-			continue
-		}
-		if !since.IsZero() && ts.Before(since) {
-			continue
-		}
+	// Most recent first
+	entries := make([]HookEntry, 0, len(recs))
+	for i := len(recs) - 1; i >= 0; i-- {
+		event := recs[i]
+		ts := event.Time()
 
 		bead := ""
 		if rawBead, ok := event.Payload["bead"]; ok && rawBead != nil {
@@ -459,9 +433,6 @@ func readHookTrailEntries(eventsPath string, since time.Time, limit int) ([]Hook
 			Timestamp: ts,
 			TimeRel:   relativeTime(ts),
 		})
-		if len(entries) >= limit {
-			break
-		}
 	}
 
 	return entries, nil
@@ -1,40 +1,24 @@
 package cmd
 
 import (
-	"encoding/json"
-	"os"
-	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/events"
 )
 
-func writeTrailEventsFile(t *testing.T, path string, entries []events.Event) {
+func writeTrailEvents(t *testing.T, townRoot string, entries []events.Event) {
 	t.Helper()
 
-	f, err := os.Create(path)
-	if err != nil {
-		t.Fatalf("creating events file: %v", err)
-	}
-	defer f.Close()
-
-	for _, entry := range entries {
-		b, err := json.Marshal(entry)
-		if err != nil {
-			t.Fatalf("marshaling event: %v", err)
-		}
-		if _, err := f.Write(append(b, '\n')); err != nil {
-			t.Fatalf("writing event: %v", err)
-		}
+	if err := events.OpenStore(townRoot).Append(entries...); err != nil {
+		t.Fatalf("appending events: %v", err)
 	}
 }
 
-func TestReadHookTrailEntriesMissingFile(t *testing.T) {
+func TestReadHookTrailEntriesNoEvents(t *testing.T) {
 	tmp := t.TempDir()
-	path := filepath.Join(tmp, ".events.jsonl")
 
-	got, err := readHookTrailEntries(path, time.Time{}, 20)
+	got, err := readHookTrailEntries(tmp, time.Time{}, 20)
 	if err != nil {
 		t.Fatalf("readHookTrailEntries() error = %v", err)
 	}
@@ -45,10 +29,9 @@ func TestReadHookTrailEntriesMissingFile(t *testing.T) {
 
 func TestReadHookTrailEntriesFiltersAndOrders(t *testing.T) {
 	tmp := t.TempDir()
-	path := filepath.Join(tmp, ".events.jsonl")
 	base := time.Date(2026, time.January, 2, 12, 0, 0, 0, time.UTC)
 
-	writeTrailEventsFile(t, path, []events.Event{
+	writeTrailEvents(t, tmp, []events.Event{
 		{
 			Timestamp: base.Add(-4 * time.Hour).Format(time.RFC3339),
 			Type:      events.TypeSling,
@@ -75,7 +58,7 @@ func TestReadHookTrailEntriesFiltersAndOrders(t *testing.T) {
 		},
 	})
 
-	got, err := readHookTrailEntries(path, time.Time{}, 10)
+	got, err := readHookTrailEntries(tmp, time.Time{}, 10)
 	if err != nil {
 		t.Fatalf("readHookTrailEntries() error = %v", err)
 	}
@@ -96,10 +79,9 @@ func TestReadHookTrailEntriesFiltersAndOrders(t *testing.T) {
 
 func TestReadHookTrailEntriesSinceAndLimit(t *testing.T) {
 	tmp := t.TempDir()
-	path := filepath.Join(tmp, ".events.jsonl")
 	base := time.Date(2026, time.January, 3, 12, 0, 0, 0, time.UTC)
 
-	writeTrailEventsFile(t, path, []events.Event{
+	writeTrailEvents(t, tmp, []events.Event{
 		{
 			Timestamp: base.Add(-3 * time.Hour).Format(time.RFC3339),
 			Type:      events.TypeHook,
@@ -121,7 +103,7 @@ func TestReadHookTrailEntriesSinceAndLimit(t *testing.T) {
 	})
 
 	since := base.Add(-90 * time.Minute)
-	got, err := readHookTrailEntries(path, since, 1)
+	got, err := readHookTrailEntries(tmp, since, 1)
 	if err != nil {
 		t.Fatalf("readHookTrailEntries() error = %v", err)
 	}
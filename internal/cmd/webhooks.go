@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/webhooks"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	webhooksJSON            bool
+	webhookDeliveriesStatus string
+	webhookDeliveriesName   string
+	webhookDeliveriesLimit  int
+	webhookDeliveriesJSON   bool
+)
+
+var webhooksCmd = &cobra.Command{
+	Use:     "webhooks",
+	GroupID: GroupConfig,
+	Short:   "List outbound webhooks and their deliveries",
+	Long: `List the webhooks configured in settings/config.json.
+
+The daemon POSTs matching town events to each webhook's URL:
+
+  "webhooks": [
+    {
+      "name": "ci",
+      "url": "https://ci.example.com/gastown",
+      "events": ["merged", "merge_failed", "convoy_closed"],
+      "actors": ["gastown/*"],
+      "secret_env": "GT_WEBHOOK_SECRET"
+    }
+  ]
+
+events and actors filter what is delivered (empty means everything; actors
+are glob patterns). With a secret, each request carries
+X-Gastown-Signature: sha256=HMAC-SHA256(secret, "<timestamp>.<body>"), where
+the timestamp is the X-Gastown-Timestamp header.
+
+Failed deliveries are retried with exponential backoff (30s doubling to 1h,
+up to 10 attempts). Client errors other than 408 and 429 are not retried.
+
+Examples:
+  gt webhooks
+  gt webhooks deliveries --status failed
+  gt webhooks redeliver wh-3f2a9c1e0b7d4a65`,
+	RunE: runWebhooks,
+}
+
+var webhookDeliveriesCmd = &cobra.Command{
+	Use:   "deliveries",
+	Short: "Show queued and recent webhook deliveries",
+	Long: `Show webhook deliveries, newest first.
+
+Pending deliveries are waiting for their next attempt. Delivered and failed
+deliveries are kept for 7 days.
+
+Examples:
+  gt webhooks deliveries
+  gt webhooks deliveries --status pending
+  gt webhooks deliveries --webhook ci -n 50
+  gt webhooks deliveries --json`,
+	Args: cobra.NoArgs,
+	RunE: runWebhookDeliveries,
+}
+
+var webhookRedeliverCmd = &cobra.Command{
+	Use:   "redeliver <delivery-id>",
+	Short: "Send a delivery again",
+	Long: `Reset a delivery to pending so the daemon sends it on its next poll.
+
+Works for failed and delivered deliveries alike; the attempt count restarts.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWebhookRedeliver,
+}
+
+func init() {
+	webhooksCmd.Flags().BoolVar(&webhooksJSON, "json", false, "Output as JSON")
+
+	webhookDeliveriesCmd.Flags().StringVar(&webhookDeliveriesStatus, "status", "", "Filter by status (pending, delivered, failed)")
+	webhookDeliveriesCmd.Flags().StringVar(&webhookDeliveriesName, "webhook", "", "Filter by webhook name")
+	webhookDeliveriesCmd.Flags().IntVarP(&webhookDeliveriesLimit, "limit", "n", 20, "Maximum deliveries to show (0 for all)")
+	webhookDeliveriesCmd.Flags().BoolVar(&webhookDeliveriesJSON, "json", false, "Output as JSON")
+
+	webhooksCmd.AddCommand(webhookDeliveriesCmd)
+	webhooksCmd.AddCommand(webhookRedeliverCmd)
+	rootCmd.AddCommand(webhooksCmd)
+}
+
+func runWebhooks(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+
+	if webhooksJSON {
+		// Never print secrets.
+		rules := make([]config.WebhookRule, len(settings.Webhooks))
+		for i, r := range settings.Webhooks {
+			if r.Secret != "" {
+				r.Secret = "********"
+			}
+			rules[i] = r
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rules)
+	}
+
+	if len(settings.Webhooks) == 0 {
+		fmt.Printf("%s No webhooks configured; add a \"webhooks\" list to settings/config.json\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	for _, r := range settings.Webhooks {
+		name := style.Bold.Render(r.Name)
+		if r.Disabled {
+			name += " " + style.Dim.Render("(disabled)")
+		}
+		fmt.Printf("%s  %s\n", name, r.URL)
+		fmt.Printf("  events: %s\n", listOrAll(r.Events))
+		fmt.Printf("  actors: %s\n", listOrAll(r.Actors))
+		if err := r.Validate(); err != nil {
+			fmt.Printf("  %s %v\n", style.Warning.Render("⚠"), err)
+		}
+		switch {
+		case r.SecretEnv != "" && os.Getenv(r.SecretEnv) == "" && r.Secret == "":
+			fmt.Printf("  %s %s is not set in this environment; the daemon needs it to sign\n",
+				style.Warning.Render("⚠"), r.SecretEnv)
+		case r.SecretEnv == "" && r.Secret == "":
+			fmt.Printf("  %s\n", style.Dim.Render("unsigned (no secret)"))
+		}
+	}
+	return nil
+}
+
+func listOrAll(items []string) string {
+	if len(items) == 0 {
+		return style.Dim.Render("all")
+	}
+	return strings.Join(items, ", ")
+}
+
+func runWebhookDeliveries(cmd *cobra.Command, args []string) error {
+	switch webhookDeliveriesStatus {
+	case "", webhooks.StatusPending, webhooks.StatusDelivered, webhooks.StatusFailed:
+	default:
+		return fmt.Errorf("invalid --status %q: want pending, delivered or failed", webhookDeliveriesStatus)
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	q, err := webhooks.Load(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading webhook deliveries: %w", err)
+	}
+
+	var shown []*webhooks.Delivery
+	for _, d := range q.Sorted() {
+		if webhookDeliveriesStatus != "" && d.Status != webhookDeliveriesStatus {
+			continue
+		}
+		if webhookDeliveriesName != "" && d.Webhook != webhookDeliveriesName {
+			continue
+		}
+		shown = append(shown, d)
+		if webhookDeliveriesLimit > 0 && len(shown) == webhookDeliveriesLimit {
+			break
+		}
+	}
+
+	if webhookDeliveriesJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if shown == nil {
+			shown = []*webhooks.Delivery{}
+		}
+		return enc.Encode(shown)
+	}
+
+	if len(shown) == 0 {
+		fmt.Printf("%s No webhook deliveries\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, d := range shown {
+		fmt.Printf("%s %s  %s → %s  %s\n",
+			deliveryStatusIcon(d.Status), d.ID,
+			style.Bold.Render(d.Event.Type), d.Webhook,
+			style.Dim.Render(formatRelativeTime(d.CreatedAt.Format(time.RFC3339))))
+		detail := fmt.Sprintf("actor %s, %d attempt(s)", d.Event.Actor, d.Attempts)
+		if d.LastError != "" {
+			detail += ", last error: " + d.LastError
+		}
+		if d.Status == webhooks.StatusPending && !d.NextAttempt.IsZero() {
+			detail += ", next attempt " + d.NextAttempt.Local().Format("15:04:05")
+		}
+		fmt.Printf("  %s\n", style.Dim.Render(detail))
+	}
+	return nil
+}
+
+func deliveryStatusIcon(status string) string {
+	switch status {
+	case webhooks.StatusDelivered:
+		return style.Success.Render("✓")
+	case webhooks.StatusFailed:
+		return style.Error.Render("✗")
+	default:
+		return style.Warning.Render("…")
+	}
+}
+
+func runWebhookRedeliver(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	d, err := webhooks.Redeliver(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Queued %s (%s → %s) for redelivery\n", style.Success.Render("✓"), d.ID, d.Event.Type, d.Webhook)
+	return nil
+}
@@ -33,6 +33,8 @@ const (
 	AgentCopilot AgentPreset = "copilot"
 	// AgentPi is Pi Coding Agent (extension-based lifecycle).
 	AgentPi AgentPreset = "pi"
+	// AgentScripted is the gt-fake-agent test runtime (replays a script, no LLM).
+	AgentScripted AgentPreset = "scripted"
 )
 
 // AgentPresetInfo contains the configuration details for an agent preset.
@@ -340,6 +342,26 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 			OutputFlag: "--no-session",
 		},
 	},
+	AgentScripted: {
+		Name:                AgentScripted,
+		Command:             "gt-fake-agent",
+		Args:                []string{}, // Script comes from GT_FAKE_AGENT_SCRIPT
+		ProcessNames:        []string{"gt-fake-agent"},
+		SessionIDEnv:        "GT_FAKE_AGENT_SESSION_ID",
+		ResumeFlag:          "", // Scripts always start from the top
+		ResumeStyle:         "",
+		SupportsHooks:       true, // Runs Claude-format hooks from .fake-agent/settings.json
+		SupportsForkSession: false,
+		NonInteractive:      nil,
+		// Runtime defaults
+		PromptMode:        "arg",
+		HooksProvider:     "scripted",
+		HooksDir:          ".fake-agent",
+		HooksSettingsFile: "settings.json",
+		ReadyPromptPrefix: "❯ ",
+		ReadyDelayMs:      1000,
+		InstructionsFile:  "AGENTS.md",
+	},
 }
 
 // Registry state with proper synchronization.
@@ -163,6 +163,42 @@ func ApplyCostTier(settings *TownSettings, tier CostTier) error {
 	return nil
 }
 
+// rigTierRoles are the tier-managed roles that run inside a rig, and so can
+// be overridden in rig settings.
+var rigTierRoles = []string{"witness", "refinery", "polecat", "crew"}
+
+// ApplyCostTierToRig writes the tier's role_agents for rig roles (witness,
+// refinery, polecat, crew) to one rig's settings, which take precedence over
+// town role_agents for that rig. Town-level roles are left alone. The
+// tier's agent presets are added to the rig's agents so the rig resolves
+// them whatever the town's tier.
+func ApplyCostTierToRig(settings *RigSettings, tier CostTier) error {
+	roleAgents := CostTierRoleAgents(tier)
+	if roleAgents == nil {
+		return fmt.Errorf("invalid cost tier: %q (valid: %s)", tier, strings.Join(ValidCostTiers(), ", "))
+	}
+
+	if settings.RoleAgents == nil {
+		settings.RoleAgents = make(map[string]string)
+	}
+	for _, role := range rigTierRoles {
+		if agentName := roleAgents[role]; agentName == "" {
+			delete(settings.RoleAgents, role)
+		} else {
+			settings.RoleAgents[role] = agentName
+		}
+	}
+
+	agents := CostTierAgents(tier)
+	if len(agents) > 0 && settings.Agents == nil {
+		settings.Agents = make(map[string]*RuntimeConfig)
+	}
+	for name, rc := range agents {
+		settings.Agents[name] = rc
+	}
+	return nil
+}
+
 // GetCurrentTier infers the current cost tier from the settings' RoleAgents.
 // Returns the tier name if it matches a known tier exactly, or empty string for custom configs.
 // Only tier-managed roles are compared — non-tier custom entries are ignored.
@@ -234,3 +270,73 @@ func FormatTierRoleTable(tier CostTier) string {
 	}
 	return strings.Join(lines, "\n")
 }
+
+// tierAgentNames are the agent presets cost tiers add.
+var tierAgentNames = []string{"claude-sonnet", "claude-haiku"}
+
+// CostTierSnapshot holds what applying a cost tier overwrites: the
+// tier-managed role_agents entries, the tier agent presets and, for town
+// settings, the recorded tier. An entry missing from the snapshot was unset.
+type CostTierSnapshot struct {
+	CostTier   string                    `json:"cost_tier,omitempty"`
+	RoleAgents map[string]string         `json:"role_agents,omitempty"`
+	Agents     map[string]*RuntimeConfig `json:"agents,omitempty"`
+}
+
+// SnapshotCostTier records the town settings ApplyCostTier would change.
+func SnapshotCostTier(settings *TownSettings) *CostTierSnapshot {
+	snap := snapshotTier(settings.RoleAgents, settings.Agents, TierManagedRoles)
+	snap.CostTier = settings.CostTier
+	return snap
+}
+
+// RestoreCostTier puts back town settings recorded by SnapshotCostTier.
+// Entries a tier doesn't manage are left as they are now.
+func RestoreCostTier(settings *TownSettings, snap *CostTierSnapshot) {
+	settings.RoleAgents = restoreTier(settings.RoleAgents, TierManagedRoles, snap.RoleAgents)
+	settings.Agents = restoreTier(settings.Agents, tierAgentNames, snap.Agents)
+	settings.CostTier = snap.CostTier
+}
+
+// SnapshotRigCostTier records the rig settings ApplyCostTierToRig would
+// change.
+func SnapshotRigCostTier(settings *RigSettings) *CostTierSnapshot {
+	return snapshotTier(settings.RoleAgents, settings.Agents, rigTierRoles)
+}
+
+// RestoreRigCostTier puts back rig settings recorded by SnapshotRigCostTier.
+func RestoreRigCostTier(settings *RigSettings, snap *CostTierSnapshot) {
+	settings.RoleAgents = restoreTier(settings.RoleAgents, rigTierRoles, snap.RoleAgents)
+	settings.Agents = restoreTier(settings.Agents, tierAgentNames, snap.Agents)
+}
+
+func snapshotTier(roleAgents map[string]string, agents map[string]*RuntimeConfig, roles []string) *CostTierSnapshot {
+	snap := &CostTierSnapshot{RoleAgents: map[string]string{}, Agents: map[string]*RuntimeConfig{}}
+	for _, role := range roles {
+		if agent, ok := roleAgents[role]; ok {
+			snap.RoleAgents[role] = agent
+		}
+	}
+	for _, name := range tierAgentNames {
+		if rc, ok := agents[name]; ok {
+			snap.Agents[name] = rc
+		}
+	}
+	return snap
+}
+
+// restoreTier sets each of keys in m to its value in saved, deleting keys
+// saved doesn't have.
+func restoreTier[V any](m map[string]V, keys []string, saved map[string]V) map[string]V {
+	for _, key := range keys {
+		if v, ok := saved[key]; ok {
+			if m == nil {
+				m = make(map[string]V)
+			}
+			m[key] = v
+		} else {
+			delete(m, key)
+		}
+	}
+	return m
+}
@@ -498,3 +498,34 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestCostTierSnapshot_Restore(t *testing.T) {
+	ts := NewTownSettings()
+	ts.RoleAgents = map[string]string{"witness": "gemini", "dog": "codex"}
+	snap := SnapshotCostTier(ts)
+	if err := ApplyCostTier(ts, TierBudget); err != nil {
+		t.Fatal(err)
+	}
+	ts.RoleAgents["dog"] = "aider" // changed since; not the tier's to undo
+
+	RestoreCostTier(ts, snap)
+	if ts.CostTier != "" || ts.RoleAgents["witness"] != "gemini" || ts.RoleAgents["dog"] != "aider" {
+		t.Errorf("restored town = tier %q, role_agents %v", ts.CostTier, ts.RoleAgents)
+	}
+	if _, ok := ts.RoleAgents["polecat"]; ok {
+		t.Error("restore should remove role_agents the tier added")
+	}
+	if ts.Agents["claude-sonnet"] != nil || ts.Agents["claude-haiku"] != nil {
+		t.Errorf("restore should remove the tier's agent presets: %v", ts.Agents)
+	}
+
+	rs := NewRigSettings()
+	rigSnap := SnapshotRigCostTier(rs)
+	if err := ApplyCostTierToRig(rs, TierEconomy); err != nil {
+		t.Fatal(err)
+	}
+	RestoreRigCostTier(rs, rigSnap)
+	if len(rs.RoleAgents) != 0 || len(rs.Agents) != 0 {
+		t.Errorf("restored rig = role_agents %v, agents %v, want none", rs.RoleAgents, rs.Agents)
+	}
+}
@@ -225,6 +225,9 @@ func validateRigSettings(c *RigSettings) error {
 // ErrInvalidOnConflict indicates an invalid on_conflict strategy.
 var ErrInvalidOnConflict = errors.New("invalid on_conflict strategy")
 
+// ErrInvalidMergeStrategy indicates an invalid merge_strategy.
+var ErrInvalidMergeStrategy = errors.New("invalid merge_strategy")
+
 // validateMergeQueueConfig validates a MergeQueueConfig.
 func validateMergeQueueConfig(c *MergeQueueConfig) error {
 	// Validate on_conflict strategy
@@ -233,6 +236,12 @@ func validateMergeQueueConfig(c *MergeQueueConfig) error {
 			ErrInvalidOnConflict, c.OnConflict, OnConflictAssignBack, OnConflictAutoRebase)
 	}
 
+	if !IsValidMergeStrategy(c.MergeStrategy) {
+		return fmt.Errorf("%w: got '%s', want '%s', '%s', '%s' or '%s'",
+			ErrInvalidMergeStrategy, c.MergeStrategy, MergeStrategyMergeCommit,
+			MergeStrategySquash, MergeStrategyRebaseFF, MergeStrategyFFOnly)
+	}
+
 	// Validate poll_interval if specified
 	if c.PollInterval != "" {
 		if _, err := time.ParseDuration(c.PollInterval); err != nil {
@@ -258,10 +267,45 @@ func validateMergeQueueConfig(c *MergeQueueConfig) error {
 	if c.MaxConcurrent < 0 {
 		return fmt.Errorf("%w: max_concurrent must be non-negative", ErrMissingField)
 	}
+	if c.BatchSize < 0 {
+		return fmt.Errorf("%w: batch_size must be non-negative", ErrMissingField)
+	}
+	if c.Scoring != nil {
+		if err := validateMergeQueueScoring(c.Scoring); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
+// validateMergeQueueScoring rejects negative scoring weights. Penalties are
+// subtracted by the scorer, so a negative value would silently invert them.
+func validateMergeQueueScoring(s *MergeQueueScoringConfig) error {
+	weights := []struct {
+		name  string
+		value *float64
+	}{
+		{"convoy_age_weight", s.ConvoyAgeWeight},
+		{"priority_weight", s.PriorityWeight},
+		{"retry_penalty", s.RetryPenalty},
+		{"max_retry_penalty", s.MaxRetryPenalty},
+		{"mr_age_weight", s.MRAgeWeight},
+		{"diff_size_weight", s.DiffSizeWeight},
+		{"max_diff_size_penalty", s.MaxDiffSizePenalty},
+		{"overlap_penalty", s.OverlapPenalty},
+		{"max_overlap_penalty", s.MaxOverlapPenalty},
+		{"failure_rate_weight", s.FailureRateWeight},
+		{"expedite_bonus", s.ExpediteBonus},
+	}
+	for _, w := range weights {
+		if w.value != nil && *w.value < 0 {
+			return fmt.Errorf("%w: scoring.%s must be non-negative", ErrMissingField, w.name)
+		}
+	}
+	return nil
+}
+
 // NewRigConfig creates a new RigConfig (identity only).
 func NewRigConfig(name, gitURL string) *RigConfig {
 	return &RigConfig{
@@ -2234,6 +2278,32 @@ func validateEscalationConfig(c *EscalationConfig) error {
 		return fmt.Errorf("%w: max_reescalations must be non-negative", ErrMissingField)
 	}
 
+	if d := c.Delivery; d != nil {
+		if d.MaxAttempts < 0 {
+			return fmt.Errorf("%w: delivery.max_attempts must be non-negative", ErrMissingField)
+		}
+		for name, v := range map[string]string{
+			"initial_backoff": d.InitialBackoff,
+			"max_backoff":     d.MaxBackoff,
+			"timeout":         d.Timeout,
+		} {
+			if v == "" {
+				continue
+			}
+			if _, err := time.ParseDuration(v); err != nil {
+				return fmt.Errorf("invalid delivery.%s: %w", name, err)
+			}
+		}
+	}
+
+	if smtp := c.Contacts.SMTP; smtp != nil {
+		switch smtp.TLS {
+		case "", "starttls", "tls", "none":
+		default:
+			return fmt.Errorf("invalid contacts.smtp.tls %q (valid: starttls, tls, none)", smtp.TLS)
+		}
+	}
+
 	return nil
 }
 
@@ -2260,6 +2330,40 @@ func (c *EscalationConfig) GetRouteForSeverity(severity string) []string {
 	return []string{"bead", "mail:mayor"}
 }
 
+// GetDeliveryPolicy returns the retry policy for external escalation actions,
+// filling in defaults for anything not configured.
+func (c *EscalationConfig) GetDeliveryPolicy() (maxAttempts int, initialBackoff, maxBackoff, timeout time.Duration) {
+	maxAttempts, initialBackoff, maxBackoff, timeout = 3, 2*time.Second, 30*time.Second, 15*time.Second
+	d := c.Delivery
+	if d == nil {
+		return
+	}
+	if d.MaxAttempts > 0 {
+		maxAttempts = d.MaxAttempts
+	}
+	if v, err := time.ParseDuration(d.InitialBackoff); err == nil && v >= 0 {
+		initialBackoff = v
+	}
+	if v, err := time.ParseDuration(d.MaxBackoff); err == nil && v > 0 {
+		maxBackoff = v
+	}
+	if v, err := time.ParseDuration(d.Timeout); err == nil && v > 0 {
+		timeout = v
+	}
+	return
+}
+
+// GetDeliveryDeadline returns the time allowed for all attempts of one
+// external escalation action. Default: 20s.
+func (c *EscalationConfig) GetDeliveryDeadline() time.Duration {
+	if c.Delivery != nil {
+		if v, err := time.ParseDuration(c.Delivery.Deadline); err == nil && v > 0 {
+			return v
+		}
+	}
+	return 20 * time.Second
+}
+
 // GetMaxReescalations returns the maximum number of re-escalations allowed.
 // Returns 2 if not configured (nil). Explicit 0 means "never re-escalate".
 func (c *EscalationConfig) GetMaxReescalations() int {
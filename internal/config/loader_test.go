@@ -328,6 +328,28 @@ func TestRigSettingsValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid merge_strategy",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					MergeStrategy: "octopus",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rebase-ff merge_strategy",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					MergeStrategy: MergeStrategyRebaseFF,
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "invalid poll_interval",
 			settings: &RigSettings{
@@ -4545,3 +4567,23 @@ func TestResolveRoleAgentConfig_EphemeralDefaultPreservesNonClaudeOverride(t *te
 		t.Errorf("expected gemini for polecat (non-Claude rig override with tier default), got Command=%q", rc.Command)
 	}
 }
+
+func TestMergeQueueScoringValidation(t *testing.T) {
+	t.Parallel()
+	neg, zero, pos := -1.0, 0.0, 5.0
+
+	valid := &MergeQueueConfig{Scoring: &MergeQueueScoringConfig{
+		BaseScore:      &neg, // base score may be negative
+		DiffSizeWeight: &zero,
+		ExpediteBonus:  &pos,
+	}}
+	if err := validateMergeQueueConfig(valid); err != nil {
+		t.Errorf("valid scoring config rejected: %v", err)
+	}
+
+	invalid := &MergeQueueConfig{Scoring: &MergeQueueScoringConfig{OverlapPenalty: &neg}}
+	err := validateMergeQueueConfig(invalid)
+	if err == nil || !strings.Contains(err.Error(), "scoring.overlap_penalty") {
+		t.Errorf("negative overlap_penalty: err = %v, want scoring.overlap_penalty error", err)
+	}
+}
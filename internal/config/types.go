@@ -3,8 +3,10 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
@@ -73,6 +75,9 @@ type TownSettings struct {
 	// WebTimeouts configures command execution timeouts for the web dashboard.
 	WebTimeouts *WebTimeoutsConfig `json:"web_timeouts,omitempty"`
 
+	// Dashboard configures the web dashboard's listen address and access control.
+	Dashboard *DashboardConfig `json:"dashboard,omitempty"`
+
 	// WorkerStatus configures activity-age thresholds for worker status classification.
 	WorkerStatus *WorkerStatusConfig `json:"worker_status,omitempty"`
 
@@ -86,6 +91,33 @@ type TownSettings struct {
 	// Actual model assignments live in RoleAgents and Agents.
 	// Values: "standard", "economy", "budget", or empty for custom configs.
 	CostTier string `json:"cost_tier,omitempty"`
+
+	// Pricing adds or overrides model prices used by 'gt costs'.
+	// Entries are merged over the built-in table; the entry with the latest
+	// EffectiveFrom on or before a session's end date wins.
+	Pricing []ModelPrice `json:"pricing,omitempty"`
+
+	// Budgets caps daily spend across the town, per rig, per convoy or per
+	// session. The daemon evaluates them against the costs ledger.
+	Budgets []BudgetRule `json:"budgets,omitempty"`
+
+	// Telemetry turns on metric collection, the daemon's Prometheus endpoint
+	// and the trace span file.
+	Telemetry *TelemetryConfig `json:"telemetry,omitempty"`
+
+	// Webhooks post matching town events to external URLs. The daemon
+	// delivers them with HMAC signatures and retries failures.
+	Webhooks []WebhookRule `json:"webhooks,omitempty"`
+
+	// SessionBackend selects where agent sessions run: "tmux" (default) or
+	// "pty" for the daemon's headless PTY supervisor, for containers and CI
+	// without tmux. Restart the daemon after changing it.
+	SessionBackend string `json:"session_backend,omitempty"`
+
+	// FormulaRegistry is where 'gt formula install' finds formulas: an
+	// http(s) URL or a local directory holding index.json, or the index
+	// file itself.
+	FormulaRegistry string `json:"formula_registry,omitempty"`
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -127,6 +159,222 @@ func DefaultWebTimeoutsConfig() *WebTimeoutsConfig {
 	}
 }
 
+// Dashboard user roles.
+const (
+	// DashboardRoleViewer may read dashboard data and run Safe commands only.
+	DashboardRoleViewer = "viewer"
+	// DashboardRoleOperator may also run action commands and use mutating endpoints.
+	DashboardRoleOperator = "operator"
+)
+
+// DashboardConfig configures how gt dashboard listens and who may use it.
+type DashboardConfig struct {
+	// Bind is the listen address (host only). Default: "127.0.0.1".
+	// Binding a non-loopback address requires at least one user.
+	Bind string `json:"bind,omitempty"`
+	// AllowedOrigins lists cross-origin callers allowed to use the API
+	// (e.g. "https://ops.example.com"). Same-origin requests are always allowed.
+	AllowedOrigins []string `json:"allowed_origins,omitempty"`
+	// Users are the accounts that may log in. With no users, authentication
+	// is off and every caller acts as an operator.
+	Users []DashboardUser `json:"users,omitempty"`
+	// SessionTTL is how long a login lasts. Default: "12h".
+	SessionTTL string `json:"session_ttl,omitempty"`
+}
+
+// DashboardUser is a dashboard account. Tokens are stored hashed; the
+// plaintext is shown once by 'gt dashboard user add'.
+type DashboardUser struct {
+	Name      string `json:"name"`
+	Role      string `json:"role"`       // DashboardRoleViewer or DashboardRoleOperator
+	TokenHash string `json:"token_hash"` // pbkdf2-sha256$<iterations>$<salt>$<key>
+}
+
+// DefaultDashboardConfig returns a DashboardConfig with sensible defaults.
+func DefaultDashboardConfig() *DashboardConfig {
+	return &DashboardConfig{
+		Bind:       "127.0.0.1",
+		SessionTTL: "12h",
+	}
+}
+
+// ModelPrice is the price of one model, in USD per million tokens.
+type ModelPrice struct {
+	// Model is the model ID as reported in transcripts (e.g. "claude-sonnet-4-20250514").
+	// A trailing "*" matches any model with that prefix (e.g. "gemini-2.5-pro*").
+	Model string `json:"model"`
+	// EffectiveFrom is the first day (YYYY-MM-DD) this price applies.
+	// Empty means it has always applied.
+	EffectiveFrom string `json:"effective_from,omitempty"`
+
+	Input      float64 `json:"input"`
+	Output     float64 `json:"output"`
+	CacheRead  float64 `json:"cache_read,omitempty"`
+	CacheWrite float64 `json:"cache_write,omitempty"`
+}
+
+// Budget scopes: what a BudgetRule's limit applies to.
+const (
+	BudgetScopeTown    = "town"    // all spend in the town
+	BudgetScopeRig     = "rig"     // each rig separately
+	BudgetScopeConvoy  = "convoy"  // each convoy separately
+	BudgetScopeSession = "session" // each agent session separately
+)
+
+// Budget actions, taken once when a limit is crossed.
+const (
+	// BudgetActionEscalate files an escalation (the default action).
+	BudgetActionEscalate = "escalate"
+	// BudgetActionStopSling refuses new 'gt sling' work for the rig or convoy
+	// over budget until the day ends or the breach is cleared.
+	BudgetActionStopSling = "stop_sling"
+	// BudgetActionCostTier applies the rule's CostTier to town role_agents.
+	BudgetActionCostTier = "cost_tier"
+	// BudgetActionPausePolecats stops the affected polecat sessions and keeps
+	// the daemon from restarting them until the hold lifts. Hooked work stays.
+	BudgetActionPausePolecats = "pause_polecats"
+)
+
+// BudgetRule is one spend limit. Limits are per calendar day, except for the
+// session scope where the limit applies to a single session's cost.
+type BudgetRule struct {
+	// Name identifies the rule in escalations and 'gt costs budget'.
+	// Default: "<scope>[-<rig>][-<role>]-<limit>", e.g. "rig-gastown-50".
+	Name string `json:"name,omitempty"`
+	// Scope is BudgetScopeTown, BudgetScopeRig, BudgetScopeConvoy or
+	// BudgetScopeSession. Default: BudgetScopeRig.
+	Scope string `json:"scope,omitempty"`
+	// Rig limits the rule to one rig's sessions. Set implicitly for rules in
+	// rig settings.
+	Rig string `json:"rig,omitempty"`
+	// Role limits the rule to sessions of one role (e.g. "polecat").
+	Role string `json:"role,omitempty"`
+	// LimitUSD is the spend limit.
+	LimitUSD float64 `json:"limit_usd"`
+	// WarnAt is a fraction of LimitUSD (e.g. 0.8) at which an early warning
+	// escalation is filed. Zero disables the warning.
+	WarnAt float64 `json:"warn_at,omitempty"`
+	// Actions to take when the limit is crossed. Default: ["escalate"].
+	Actions []string `json:"actions,omitempty"`
+	// CostTier is the tier applied by the cost_tier action.
+	CostTier string `json:"cost_tier,omitempty"`
+}
+
+// EffectiveScope returns the rule's scope, defaulting to BudgetScopeRig.
+func (r BudgetRule) EffectiveScope() string {
+	if r.Scope == "" {
+		return BudgetScopeRig
+	}
+	return r.Scope
+}
+
+// RuleName returns the rule's name, or a generated one if unset.
+func (r BudgetRule) RuleName() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	name := r.EffectiveScope()
+	if r.Rig != "" {
+		name += "-" + r.Rig
+	}
+	if r.Role != "" {
+		name += "-" + r.Role
+	}
+	return fmt.Sprintf("%s-%g", name, r.LimitUSD)
+}
+
+// Validate checks the rule's scope, limit and actions.
+func (r BudgetRule) Validate() error {
+	switch r.EffectiveScope() {
+	case BudgetScopeTown, BudgetScopeRig, BudgetScopeConvoy, BudgetScopeSession:
+	default:
+		return fmt.Errorf("budget %q: invalid scope %q (want town, rig, convoy or session)", r.RuleName(), r.Scope)
+	}
+	if r.LimitUSD <= 0 {
+		return fmt.Errorf("budget %q: limit_usd must be positive", r.RuleName())
+	}
+	if r.WarnAt < 0 || r.WarnAt >= 1 {
+		return fmt.Errorf("budget %q: warn_at must be between 0 and 1", r.RuleName())
+	}
+	for _, a := range r.Actions {
+		switch a {
+		case BudgetActionEscalate, BudgetActionStopSling, BudgetActionPausePolecats:
+		case BudgetActionCostTier:
+			if !IsValidTier(r.CostTier) {
+				return fmt.Errorf("budget %q: cost_tier action needs a valid cost_tier (%s)", r.RuleName(), strings.Join(ValidCostTiers(), ", "))
+			}
+		default:
+			return fmt.Errorf("budget %q: unknown action %q", r.RuleName(), a)
+		}
+	}
+	return nil
+}
+
+// TelemetryConfig configures town-wide metrics and traces.
+type TelemetryConfig struct {
+	// Enabled turns on metric collection. Default: false.
+	Enabled bool `json:"enabled"`
+	// Listen is the address the daemon serves /metrics on in Prometheus
+	// text format (e.g. "127.0.0.1:9464"). Empty disables the endpoint;
+	// 'gt metrics' still prints the current values.
+	Listen string `json:"listen,omitempty"`
+	// TracesFile receives trace spans as OTLP JSON lines. Relative paths
+	// are resolved against the town root. Empty disables tracing.
+	TracesFile string `json:"traces_file,omitempty"`
+}
+
+// WebhookRule posts town events that match its filters to a URL.
+type WebhookRule struct {
+	// Name identifies the webhook in 'gt webhooks deliveries'. Required.
+	Name string `json:"name"`
+	// URL receives a POST with the event as JSON. Must be http or https.
+	URL string `json:"url"`
+	// Events lists event types to deliver (e.g. "merged", "session_death").
+	// Empty means all types.
+	Events []string `json:"events,omitempty"`
+	// Actors lists actor patterns to deliver, matched with path.Match
+	// (e.g. "gastown/polecats/*", "deacon"). Empty means all actors.
+	Actors []string `json:"actors,omitempty"`
+	// SecretEnv names the environment variable holding the HMAC signing key.
+	// The daemon's environment must have it set.
+	SecretEnv string `json:"secret_env,omitempty"`
+	// Secret is the HMAC signing key, for towns that keep it in settings.
+	// SecretEnv takes precedence when both are set.
+	Secret string `json:"secret,omitempty"`
+	// Headers are added to every request (e.g. an Authorization token).
+	Headers map[string]string `json:"headers,omitempty"`
+	// Disabled stops new deliveries without removing the rule.
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// Validate checks the rule's name, URL and actor patterns.
+func (w WebhookRule) Validate() error {
+	if w.Name == "" {
+		return fmt.Errorf("webhook %q: name is required", w.URL)
+	}
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("webhook %q: url must be an http or https URL", w.Name)
+	}
+	for _, a := range w.Actors {
+		if _, err := path.Match(a, ""); err != nil {
+			return fmt.Errorf("webhook %q: invalid actor pattern %q", w.Name, a)
+		}
+	}
+	return nil
+}
+
+// SigningKey returns the HMAC key: the SecretEnv variable if set,
+// otherwise Secret. Empty means deliveries are unsigned.
+func (w WebhookRule) SigningKey() string {
+	if w.SecretEnv != "" {
+		if v := os.Getenv(w.SecretEnv); v != "" {
+			return v
+		}
+	}
+	return w.Secret
+}
+
 // WorkerStatusConfig configures activity-age thresholds for worker status classification.
 type WorkerStatusConfig struct {
 	// StaleThreshold is the activity age after which a worker is considered "stale".
@@ -352,6 +600,39 @@ type RigSettings struct {
 	// Overrides TownSettings.RoleAgents for this specific rig.
 	// Example: {"witness": "claude-haiku", "polecat": "claude-sonnet"}
 	RoleAgents map[string]string `json:"role_agents,omitempty"`
+
+	// Budgets caps spend for this rig. Rules count only this rig's sessions
+	// and are evaluated alongside the town's budgets.
+	Budgets []BudgetRule `json:"budgets,omitempty"`
+
+	// Forge connects the rig to its code host. Required by the "pr" merge
+	// strategy, where the refinery lands work through pull requests.
+	Forge *ForgeConfig `json:"forge,omitempty"`
+}
+
+// ForgeConfig describes the code host a rig's pull requests go to.
+type ForgeConfig struct {
+	// Type is "github" or "gitea" (also used for Forgejo).
+	Type string `json:"type"`
+
+	// URL is the server base URL, e.g. "https://codeberg.org". Defaults to
+	// https://api.github.com for GitHub; required for Gitea. For GitHub
+	// Enterprise use the API root ("https://ghe.example.com/api/v3").
+	URL string `json:"url,omitempty"`
+
+	// Repo is "owner/name". Defaults to the repository in the rig's git_url.
+	Repo string `json:"repo,omitempty"`
+
+	// TokenEnv names the environment variable holding the API token.
+	// Defaults to GITHUB_TOKEN (falling back to GH_TOKEN) or GITEA_TOKEN.
+	TokenEnv string `json:"token_env,omitempty"`
+
+	// MergeMethod is "merge", "squash" or "rebase". Default: "squash".
+	MergeMethod string `json:"merge_method,omitempty"`
+
+	// RequiredChecks lists the check names that must pass before merging.
+	// Empty means every check reported on the PR head must pass.
+	RequiredChecks []string `json:"required_checks,omitempty"`
 }
 
 // CrewConfig represents crew workspace settings for a rig.
@@ -849,6 +1130,10 @@ type MergeQueueConfig struct {
 	// OnConflict specifies conflict resolution strategy: "assign_back" or "auto_rebase".
 	OnConflict string `json:"on_conflict"`
 
+	// MergeStrategy is how the refinery lands a branch on its target:
+	// "merge-commit", "squash" (default), "rebase-ff" or "ff-only".
+	MergeStrategy string `json:"merge_strategy,omitempty"`
+
 	// RunTests controls whether to run tests before merging.
 	// Nil defaults to true (tests are run).
 	RunTests *bool `json:"run_tests,omitempty"`
@@ -879,11 +1164,39 @@ type MergeQueueConfig struct {
 	PollInterval string `json:"poll_interval"`
 
 	// MaxConcurrent is the maximum number of concurrent merges.
+	// In batch mode it bounds how many speculative gate runs execute at once.
 	MaxConcurrent int `json:"max_concurrent"`
 
+	// BatchSize enables speculative batch merging when greater than 1: the
+	// refinery stacks the top BatchSize MRs, gates the stack and bisects on
+	// failure. 0 or 1 merges one MR at a time.
+	BatchSize int `json:"batch_size,omitempty"`
+
 	// StaleClaimTimeout is how long a claimed MR can go without updates before
 	// being considered abandoned and eligible for re-claim (e.g., "30m").
 	StaleClaimTimeout string `json:"stale_claim_timeout,omitempty"`
+
+	// Scoring overrides the weights used to order the merge queue.
+	// Nil keeps the built-in defaults.
+	Scoring *MergeQueueScoringConfig `json:"scoring,omitempty"`
+}
+
+// MergeQueueScoringConfig overrides the refinery's MR priority scoring weights
+// (see refinery.ScoreConfig for what each weight does). Unset fields keep the
+// built-in default; setting a weight to 0 disables its factor.
+type MergeQueueScoringConfig struct {
+	BaseScore          *float64 `json:"base_score,omitempty"`
+	ConvoyAgeWeight    *float64 `json:"convoy_age_weight,omitempty"`
+	PriorityWeight     *float64 `json:"priority_weight,omitempty"`
+	RetryPenalty       *float64 `json:"retry_penalty,omitempty"`
+	MaxRetryPenalty    *float64 `json:"max_retry_penalty,omitempty"`
+	MRAgeWeight        *float64 `json:"mr_age_weight,omitempty"`
+	DiffSizeWeight     *float64 `json:"diff_size_weight,omitempty"`
+	MaxDiffSizePenalty *float64 `json:"max_diff_size_penalty,omitempty"`
+	OverlapPenalty     *float64 `json:"overlap_penalty,omitempty"`
+	MaxOverlapPenalty  *float64 `json:"max_overlap_penalty,omitempty"`
+	FailureRateWeight  *float64 `json:"failure_rate_weight,omitempty"`
+	ExpediteBonus      *float64 `json:"expedite_bonus,omitempty"`
 }
 
 // OnConflict strategy constants.
@@ -892,6 +1205,32 @@ const (
 	OnConflictAutoRebase = "auto_rebase"
 )
 
+// Merge strategy constants for MergeQueueConfig.MergeStrategy.
+const (
+	// MergeStrategyMergeCommit merges with --no-ff, keeping the branch's
+	// commits under a merge commit.
+	MergeStrategyMergeCommit = "merge-commit"
+	// MergeStrategySquash lands the branch as one commit whose message is
+	// generated from the source issue.
+	MergeStrategySquash = "squash"
+	// MergeStrategyRebaseFF rebases the branch's commits onto the target
+	// and fast-forwards, for linear history that keeps each commit.
+	MergeStrategyRebaseFF = "rebase-ff"
+	// MergeStrategyFFOnly lands the branch only if it already fast-forwards
+	// the target; otherwise the MR goes back to the worker to rebase.
+	MergeStrategyFFOnly = "ff-only"
+)
+
+// IsValidMergeStrategy reports whether s is a known merge strategy.
+// The empty string is valid and means the default (squash).
+func IsValidMergeStrategy(s string) bool {
+	switch s {
+	case "", MergeStrategyMergeCommit, MergeStrategySquash, MergeStrategyRebaseFF, MergeStrategyFFOnly:
+		return true
+	}
+	return false
+}
+
 // IsPolecatIntegrationEnabled returns whether polecat integration branch
 // sourcing is enabled. Nil-safe, defaults to true.
 func (c *MergeQueueConfig) IsPolecatIntegrationEnabled() bool {
@@ -1037,6 +1376,11 @@ type AccountQuotaState struct {
 	LimitedAt string             `json:"limited_at,omitempty"` // RFC3339 when limit was detected
 	ResetsAt  string             `json:"resets_at,omitempty"`  // Human-readable reset time from provider (e.g. "7pm (America/Los_Angeles)")
 	LastUsed  string             `json:"last_used,omitempty"`  // RFC3339 when account was last assigned to a session
+
+	// CooldownUntil is when a forecast cooldown ends (RFC3339). Set by the
+	// daemon when an account is predicted to hit its limit before its window
+	// resets; the account is not a rotation target until then.
+	CooldownUntil string `json:"cooldown_until,omitempty"`
 }
 
 // CurrentQuotaVersion is the current schema version for QuotaState.
@@ -1116,7 +1460,9 @@ type EscalationConfig struct {
 	//   - "bead"        → Create escalation bead (always first, implicit)
 	//   - "mail:<target>" → Send gt mail to target (e.g., "mail:mayor")
 	//   - "email:human" → Send email to contacts.human_email
+	//   - "email:<addr>" → Send email to addr
 	//   - "sms:human"   → Send SMS to contacts.human_sms
+	//   - "sms:<number>" → Send SMS to number
 	//   - "slack"       → Post to contacts.slack_webhook
 	//   - "log"         → Write to escalation log file
 	Routes map[string][]string `json:"routes"`
@@ -1134,6 +1480,9 @@ type EscalationConfig struct {
 	// re-escalated. Default: 2 (low→medium→high, then stops)
 	// Pointer type to distinguish "not configured" (nil) from explicit 0.
 	MaxReescalations *int `json:"max_reescalations,omitempty"`
+
+	// Delivery controls retry and timeouts for email, sms and slack actions.
+	Delivery *EscalationDelivery `json:"delivery,omitempty"`
 }
 
 // EscalationContacts contains contact information for external notification channels.
@@ -1141,6 +1490,64 @@ type EscalationContacts struct {
 	HumanEmail   string `json:"human_email,omitempty"`   // email address for email:human action
 	HumanSMS     string `json:"human_sms,omitempty"`     // phone number for sms:human action
 	SlackWebhook string `json:"slack_webhook,omitempty"` // webhook URL for slack action
+
+	// SMTP configures the mail server used by email: actions.
+	SMTP *EscalationSMTP `json:"smtp,omitempty"`
+
+	// SMSGateway configures the HTTP gateway used by sms: actions.
+	SMSGateway *EscalationSMSGateway `json:"sms_gateway,omitempty"`
+}
+
+// EscalationSMTP configures SMTP delivery for email escalations.
+type EscalationSMTP struct {
+	Host string `json:"host"`
+	Port int    `json:"port,omitempty"` // default: 587 (465 when tls is "tls")
+	From string `json:"from"`
+
+	// Username enables PLAIN auth. The password comes from Password or,
+	// preferably, from the environment variable named by PasswordEnv so the
+	// secret stays out of settings/escalation.json.
+	Username    string `json:"username,omitempty"`
+	Password    string `json:"password,omitempty"`
+	PasswordEnv string `json:"password_env,omitempty"`
+
+	// TLS selects the transport: "starttls" (default), "tls" (implicit TLS,
+	// usually port 465) or "none" (plaintext, for local relays only).
+	TLS string `json:"tls,omitempty"`
+
+	// InsecureSkipVerify disables certificate verification (self-signed relays).
+	InsecureSkipVerify bool `json:"insecure_skip_verify,omitempty"`
+}
+
+// EscalationSMSGateway describes a generic HTTP SMS gateway.
+// URL and Body are Go text/templates rendered with .To, .Message,
+// .Severity and .BeadID, so most providers (Twilio, Vonage, ntfy, a
+// self-hosted relay) can be driven without provider-specific code.
+type EscalationSMSGateway struct {
+	URL         string            `json:"url"`
+	Method      string            `json:"method,omitempty"`       // default: POST
+	ContentType string            `json:"content_type,omitempty"` // default: application/json
+	Headers     map[string]string `json:"headers,omitempty"`      // values may use $ENV_VAR expansion
+	Body        string            `json:"body,omitempty"`         // default: {"to": ..., "message": ...}
+}
+
+// EscalationDelivery controls retries for external escalation actions.
+type EscalationDelivery struct {
+	// MaxAttempts is the number of tries per action, including the first.
+	// Default: 3.
+	MaxAttempts int `json:"max_attempts,omitempty"`
+
+	// InitialBackoff is the wait before the first retry; it doubles after
+	// each failed attempt up to MaxBackoff. Defaults: "2s" and "30s".
+	InitialBackoff string `json:"initial_backoff,omitempty"`
+	MaxBackoff     string `json:"max_backoff,omitempty"`
+
+	// Timeout bounds a single attempt. Default: "15s".
+	Timeout string `json:"timeout,omitempty"`
+
+	// Deadline bounds all attempts of one action, backoff included, so a
+	// dead endpoint can't hold up gt escalate. Default: "20s".
+	Deadline string `json:"deadline,omitempty"`
 }
 
 // CurrentEscalationVersion is the current schema version for EscalationConfig.
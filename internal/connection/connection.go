@@ -68,6 +68,10 @@ type Connection interface {
 	// TmuxSendKeys sends keys to the named tmux session.
 	TmuxSendKeys(session, keys string) error
 
+	// TmuxSendKeysRaw sends tmux key names (e.g. "C-c") to the named
+	// session, without literal mode or a trailing Enter.
+	TmuxSendKeysRaw(session, keys string) error
+
 	// TmuxCapturePane captures the last N lines from a tmux pane.
 	TmuxCapturePane(session string, lines int) (string, error)
 
@@ -171,6 +171,11 @@ func (c *LocalConnection) TmuxSendKeys(session, keys string) error {
 	return c.tmux.SendKeys(session, keys)
 }
 
+// TmuxSendKeysRaw sends tmux key names to a tmux session.
+func (c *LocalConnection) TmuxSendKeysRaw(session, keys string) error {
+	return c.tmux.SendKeysRaw(session, keys)
+}
+
 // TmuxCapturePane captures the last N lines from a tmux pane.
 func (c *LocalConnection) TmuxCapturePane(session string, lines int) (string, error) {
 	return c.tmux.CapturePane(session, lines)
@@ -31,6 +31,17 @@ type MachineRegistry struct {
 	mu       sync.RWMutex
 }
 
+// TownRegistryPath returns the path of the machine registry for a town.
+func TownRegistryPath(townRoot string) string {
+	return filepath.Join(townRoot, "mayor", "machines.json")
+}
+
+// LoadTownRegistry loads the machine registry for a town.
+// A town without mayor/machines.json gets a registry containing only "local".
+func LoadTownRegistry(townRoot string) (*MachineRegistry, error) {
+	return NewMachineRegistry(TownRegistryPath(townRoot))
+}
+
 // NewMachineRegistry creates a registry from the given config file path.
 // If the file doesn't exist, an empty registry is created.
 func NewMachineRegistry(configPath string) (*MachineRegistry, error) {
@@ -176,8 +187,10 @@ func (r *MachineRegistry) Connection(name string) (Connection, error) {
 	case "local":
 		return NewLocalConnection(), nil
 	case "ssh":
-		// SSH connection not yet implemented
-		return nil, fmt.Errorf("ssh connections not yet implemented")
+		if m.Host == "" {
+			return nil, fmt.Errorf("ssh machine %s has no host", m.Name)
+		}
+		return NewSSHConnection(m), nil
 	default:
 		return nil, fmt.Errorf("unknown machine type: %s", m.Type)
 	}
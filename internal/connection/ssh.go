@@ -0,0 +1,503 @@
+package connection
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Exit codes used by the small shell scripts SSHConnection runs remotely
+// to report file errors without having to parse localized stderr text.
+const (
+	sshExitNotFound   = 3
+	sshExitPermission = 4
+)
+
+// sshExitConnection is the exit status ssh(1) uses for its own failures
+// (unreachable host, auth failure), as opposed to the remote command's.
+const sshExitConnection = 255
+
+// SSHConnection implements Connection for a remote machine reached over SSH.
+// It shells out to the system ssh binary (like the tmux and git wrappers do)
+// so that the user's ssh config, agent and known_hosts are honored.
+// Relative paths are rooted at the machine's TownPath.
+type SSHConnection struct {
+	machine *Machine
+
+	// sshBin is the ssh executable. Overridable for tests.
+	sshBin string
+
+	// extraOpts are additional ssh -o options appended after the defaults.
+	extraOpts []string
+}
+
+// NewSSHConnection creates a connection to the given ssh machine.
+func NewSSHConnection(m *Machine) *SSHConnection {
+	return &SSHConnection{
+		machine: m,
+		sshBin:  "ssh",
+	}
+}
+
+// Name returns the machine name.
+func (c *SSHConnection) Name() string {
+	return c.machine.Name
+}
+
+// IsLocal returns false for SSH connections.
+func (c *SSHConnection) IsLocal() bool {
+	return false
+}
+
+// Machine returns the machine this connection targets.
+func (c *SSHConnection) Machine() *Machine {
+	return c.machine
+}
+
+// ResolvePath returns the remote absolute path for p.
+// Absolute paths are returned cleaned; relative paths are joined to TownPath.
+func (c *SSHConnection) ResolvePath(p string) string {
+	if path.IsAbs(p) || c.machine.TownPath == "" {
+		return path.Clean(p)
+	}
+	return path.Join(c.machine.TownPath, p)
+}
+
+// sshArgs builds the ssh argument list that runs script on the remote host.
+func (c *SSHConnection) sshArgs(script string) []string {
+	args := []string{
+		"-o", "BatchMode=yes",
+		"-o", "ConnectTimeout=10",
+		// Reuse one TCP connection across the many short commands a
+		// patrol cycle issues (has-session, capture-pane, ...).
+		"-o", "ControlMaster=auto",
+		"-o", "ControlPath=" + filepath.Join(os.TempDir(), "gt-ssh-%C"),
+		"-o", "ControlPersist=60",
+	}
+	for _, opt := range c.extraOpts {
+		args = append(args, "-o", opt)
+	}
+	if c.machine.KeyPath != "" {
+		args = append(args, "-i", expandHome(c.machine.KeyPath))
+	}
+	// Always run under sh so behavior doesn't depend on the remote login shell.
+	args = append(args, c.machine.Host, "--", "sh -c "+shellQuote(script))
+	return args
+}
+
+// run executes script on the remote host via sh and returns stdout.
+// stdin, if non-nil, is fed to the remote command.
+func (c *SSHConnection) run(op string, stdin []byte, script string) ([]byte, []byte, error) {
+	cmd := exec.Command(c.sshBin, c.sshArgs(script)...) //nolint:gosec // G204: args built from registry config
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+
+	err := cmd.Run()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() == sshExitConnection {
+			msg := strings.TrimSpace(stderr.String())
+			if msg == "" {
+				msg = err.Error()
+			}
+			return stdout.Bytes(), stderr.Bytes(), &ConnectionError{
+				Op:      op,
+				Machine: c.machine.Name,
+				Err:     errors.New(msg),
+			}
+		}
+	}
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
+// fileOp runs a file-oriented script and maps the sentinel exit codes
+// to NotFoundError / PermissionError.
+func (c *SSHConnection) fileOp(op, p string, stdin []byte, script string) ([]byte, error) {
+	out, stderr, err := c.run(op, stdin, script)
+	if err == nil {
+		return out, nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		switch exitErr.ExitCode() {
+		case sshExitNotFound:
+			return nil, &NotFoundError{Path: p}
+		case sshExitPermission:
+			return nil, &PermissionError{Path: p, Op: op}
+		}
+		if msg := strings.TrimSpace(string(stderr)); msg != "" {
+			return nil, fmt.Errorf("%s %s on %s: %s", op, p, c.machine.Name, msg)
+		}
+	}
+	return nil, err
+}
+
+// ReadFile reads the named remote file.
+func (c *SSHConnection) ReadFile(p string) ([]byte, error) {
+	rp := c.ResolvePath(p)
+	q := shellQuote(rp)
+	script := fmt.Sprintf(
+		"[ -e %[1]s ] || exit %[2]d; [ -r %[1]s ] || exit %[3]d; cat -- %[1]s",
+		q, sshExitNotFound, sshExitPermission)
+	return c.fileOp("read", rp, nil, script)
+}
+
+// WriteFile writes data to the named remote file, streaming it over stdin.
+func (c *SSHConnection) WriteFile(p string, data []byte, perm fs.FileMode) error {
+	rp := c.ResolvePath(p)
+	q := shellQuote(rp)
+	// Only access checks map to the permission sentinel; anything else
+	// (missing parent, full disk) surfaces with the remote stderr.
+	script := fmt.Sprintf(
+		"if [ -e %[1]s ]; then [ -w %[1]s ] || exit %[2]d; elif [ -d %[4]s ]; then [ -w %[4]s ] || exit %[2]d; fi; cat > %[1]s && chmod %04[3]o %[1]s",
+		q, sshExitPermission, perm.Perm(), shellQuote(path.Dir(rp)))
+	if data == nil {
+		data = []byte{}
+	}
+	_, err := c.fileOp("write", rp, data, script)
+	return err
+}
+
+// MkdirAll creates a remote directory and all parent directories.
+func (c *SSHConnection) MkdirAll(p string, perm fs.FileMode) error {
+	rp := c.ResolvePath(p)
+	script := fmt.Sprintf("mkdir -p -m %04o -- %s 2>/dev/null || exit %d",
+		perm.Perm(), shellQuote(rp), sshExitPermission)
+	_, err := c.fileOp("mkdir", rp, nil, script)
+	return err
+}
+
+// Remove removes the named remote file or empty directory.
+// Like LocalConnection, removing a missing path is not an error.
+func (c *SSHConnection) Remove(p string) error {
+	rp := c.ResolvePath(p)
+	q := shellQuote(rp)
+	script := fmt.Sprintf(
+		"[ -e %[1]s ] || [ -L %[1]s ] || exit 0; if [ -d %[1]s ] && [ ! -L %[1]s ]; then rmdir -- %[1]s; else rm -f -- %[1]s; fi",
+		q)
+	_, err := c.fileOp("remove", rp, nil, script)
+	return err
+}
+
+// RemoveAll removes the named remote file or directory and any children.
+func (c *SSHConnection) RemoveAll(p string) error {
+	rp := c.ResolvePath(p)
+	if rp == "/" || rp == "." || rp == "" {
+		return fmt.Errorf("refusing to remove %q on %s", rp, c.machine.Name)
+	}
+	script := fmt.Sprintf("rm -rf -- %s 2>/dev/null || exit %d", shellQuote(rp), sshExitPermission)
+	_, err := c.fileOp("remove", rp, nil, script)
+	return err
+}
+
+// Stat returns file info for the named remote file.
+// Uses GNU stat format flags; the remote is expected to be Linux.
+func (c *SSHConnection) Stat(p string) (FileInfo, error) {
+	rp := c.ResolvePath(p)
+	q := shellQuote(rp)
+	script := fmt.Sprintf("[ -e %[1]s ] || exit %[2]d; stat -L -c '%%s %%f %%Y' -- %[1]s",
+		q, sshExitNotFound)
+	out, err := c.fileOp("stat", rp, nil, script)
+	if err != nil {
+		return nil, err
+	}
+	return parseStatOutput(path.Base(rp), string(out))
+}
+
+// parseStatOutput parses the output of `stat -c '%s %f %Y'`:
+// size in bytes, raw mode in hex, and mtime in epoch seconds.
+func parseStatOutput(name, out string) (BasicFileInfo, error) {
+	fields := strings.Fields(out)
+	if len(fields) != 3 {
+		return BasicFileInfo{}, fmt.Errorf("unexpected stat output: %q", strings.TrimSpace(out))
+	}
+	size, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return BasicFileInfo{}, fmt.Errorf("parsing stat size: %w", err)
+	}
+	raw, err := strconv.ParseUint(fields[1], 16, 32)
+	if err != nil {
+		return BasicFileInfo{}, fmt.Errorf("parsing stat mode: %w", err)
+	}
+	mtime, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return BasicFileInfo{}, fmt.Errorf("parsing stat mtime: %w", err)
+	}
+
+	const sIFMT, sIFDIR, sIFLNK = 0o170000, 0o040000, 0o120000
+	mode := fs.FileMode(raw & 0o777)
+	switch raw & sIFMT {
+	case sIFDIR:
+		mode |= fs.ModeDir
+	case sIFLNK:
+		mode |= fs.ModeSymlink
+	}
+
+	return BasicFileInfo{
+		FileName:    name,
+		FileSize:    size,
+		FileMode:    mode,
+		FileModTime: time.Unix(mtime, 0),
+		FileIsDir:   mode.IsDir(),
+	}, nil
+}
+
+// Glob returns the names of all remote files matching the pattern.
+// The pattern is expanded by the remote shell with field splitting disabled,
+// so it is subject to pathname expansion only (no command substitution).
+func (c *SSHConnection) Glob(pattern string) ([]string, error) {
+	rp := c.ResolvePath(pattern)
+	script := fmt.Sprintf(
+		`sh -c 'IFS=; for f in $1; do [ -e "$f" ] && printf "%%s\n" "$f"; done; exit 0' sh %s`,
+		shellQuote(rp))
+	out, _, err := c.run("glob", nil, script)
+	if err != nil {
+		return nil, err
+	}
+	var matches []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line != "" {
+			matches = append(matches, line)
+		}
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// Exists returns true if the remote path exists.
+func (c *SSHConnection) Exists(p string) (bool, error) {
+	rp := c.ResolvePath(p)
+	_, err := c.fileOp("stat", rp, nil, fmt.Sprintf("[ -e %s ] || exit %d", shellQuote(rp), sshExitNotFound))
+	if err != nil {
+		var nf *NotFoundError
+		if errors.As(err, &nf) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Exec runs a command on the remote host and returns its combined output.
+// The command runs from TownPath when one is configured.
+func (c *SSHConnection) Exec(cmd string, args ...string) ([]byte, error) {
+	return c.ExecDir(c.machine.TownPath, cmd, args...)
+}
+
+// ExecDir runs a command in the specified remote directory.
+func (c *SSHConnection) ExecDir(dir, cmd string, args ...string) ([]byte, error) {
+	script := joinCommand(cmd, args) + " 2>&1"
+	if dir != "" {
+		script = "cd " + shellQuote(c.ResolvePath(dir)) + " && " + script
+	}
+	out, _, err := c.run("exec", nil, script)
+	return out, err
+}
+
+// ExecEnv runs a command with additional environment variables.
+func (c *SSHConnection) ExecEnv(env map[string]string, cmd string, args ...string) ([]byte, error) {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	if c.machine.TownPath != "" {
+		sb.WriteString("cd " + shellQuote(c.machine.TownPath) + " && ")
+	}
+	sb.WriteString("env")
+	for _, k := range keys {
+		sb.WriteString(" " + shellQuote(k+"="+env[k]))
+	}
+	sb.WriteString(" " + joinCommand(cmd, args) + " 2>&1")
+
+	out, _, err := c.run("exec", nil, sb.String())
+	return out, err
+}
+
+// tmux runs a tmux subcommand on the remote host.
+// Errors are mapped onto the same sentinel meanings the local tmux wrapper uses.
+func (c *SSHConnection) tmux(args ...string) (string, error) {
+	out, stderr, err := c.run("tmux", nil, joinCommand("tmux", append([]string{"-u"}, args...)))
+	if err != nil {
+		var connErr *ConnectionError
+		if errors.As(err, &connErr) {
+			return "", err
+		}
+		msg := strings.TrimSpace(string(stderr))
+		switch {
+		case isTmuxNoServer(msg):
+			return "", errRemoteNoServer
+		case strings.Contains(msg, "session not found"), strings.Contains(msg, "can't find session"):
+			return "", errRemoteSessionNotFound
+		case msg != "":
+			return "", fmt.Errorf("tmux %s on %s: %s", args[0], c.machine.Name, msg)
+		}
+		return "", fmt.Errorf("tmux %s on %s: %w", args[0], c.machine.Name, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+var (
+	errRemoteNoServer        = errors.New("no tmux server running")
+	errRemoteSessionNotFound = errors.New("session not found")
+)
+
+func isTmuxNoServer(stderr string) bool {
+	return strings.Contains(stderr, "no server running") ||
+		strings.Contains(stderr, "error connecting to") ||
+		strings.Contains(stderr, "no current target") ||
+		strings.Contains(stderr, "server exited unexpectedly")
+}
+
+// TmuxNewSession creates a new detached tmux session on the remote host.
+func (c *SSHConnection) TmuxNewSession(name, dir string) error {
+	args := []string{"new-session", "-d", "-s", name}
+	if dir != "" {
+		args = append(args, "-c", c.ResolvePath(dir))
+	}
+	_, err := c.tmux(args...)
+	return err
+}
+
+// TmuxKillSession terminates a remote tmux session.
+// Like the local implementation, descendant processes of the pane are
+// signalled first so agents don't survive as orphans.
+func (c *SSHConnection) TmuxKillSession(name string) error {
+	q := shellQuote("=" + name)
+	script := fmt.Sprintf(
+		"for p in $(tmux -u list-panes -s -t %[1]s -F '#{pane_pid}' 2>/dev/null); do pkill -TERM -P \"$p\" 2>/dev/null; kill -TERM \"$p\" 2>/dev/null; done; tmux -u kill-session -t %[1]s",
+		q)
+	_, stderr, err := c.run("tmux", nil, script)
+	if err != nil {
+		var connErr *ConnectionError
+		if errors.As(err, &connErr) {
+			return err
+		}
+		msg := strings.TrimSpace(string(stderr))
+		if isTmuxNoServer(msg) || strings.Contains(msg, "can't find session") || strings.Contains(msg, "session not found") {
+			return nil
+		}
+		return fmt.Errorf("tmux kill-session on %s: %s", c.machine.Name, msg)
+	}
+	return nil
+}
+
+// TmuxSendKeys sends keys to a remote tmux session followed by Enter.
+// Enter is sent separately after a debounce, matching tmux.SendKeys.
+func (c *SSHConnection) TmuxSendKeys(session, keys string) error {
+	target := exactPaneTarget(session)
+	if _, err := c.tmux("send-keys", "-t", target, "-l", keys); err != nil {
+		return err
+	}
+	time.Sleep(time.Duration(constants.DefaultDebounceMs) * time.Millisecond)
+	_, err := c.tmux("send-keys", "-t", target, "Enter")
+	return err
+}
+
+// TmuxSendKeysRaw sends tmux key names (e.g. "C-c") to exactly the named
+// remote session.
+func (c *SSHConnection) TmuxSendKeysRaw(session, keys string) error {
+	_, err := c.tmux("send-keys", "-t", exactPaneTarget(session), keys)
+	return err
+}
+
+// TmuxCapturePane captures the last N lines from a remote tmux pane.
+func (c *SSHConnection) TmuxCapturePane(session string, lines int) (string, error) {
+	return c.tmux("capture-pane", "-p", "-t", exactPaneTarget(session), "-S", fmt.Sprintf("-%d", lines))
+}
+
+// exactPaneTarget returns a pane target for the named session that only
+// matches that exact session name. Without the "=" prefix tmux falls back
+// to prefix matching, so "gt-foo" could hit "gt-foo-2". Pane targets need
+// the trailing colon: tmux rejects a bare "=name" as a pane.
+func exactPaneTarget(session string) string {
+	return "=" + session + ":"
+}
+
+// TmuxHasSession returns true if the remote session exists (exact match).
+func (c *SSHConnection) TmuxHasSession(name string) (bool, error) {
+	_, err := c.tmux("has-session", "-t", "="+name)
+	if err != nil {
+		if errors.Is(err, errRemoteSessionNotFound) || errors.Is(err, errRemoteNoServer) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// TmuxListSessions returns all remote tmux session names.
+func (c *SSHConnection) TmuxListSessions() ([]string, error) {
+	out, err := c.tmux("list-sessions", "-F", "#{session_name}")
+	if err != nil {
+		if errors.Is(err, errRemoteNoServer) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// joinCommand shell-quotes a command and its arguments into one string
+// suitable for evaluation by the remote login shell.
+func joinCommand(cmd string, args []string) string {
+	parts := make([]string, 0, len(args)+1)
+	parts = append(parts, shellQuote(cmd))
+	for _, a := range args {
+		parts = append(parts, shellQuote(a))
+	}
+	return strings.Join(parts, " ")
+}
+
+// shellQuote quotes s for a POSIX shell. Unlike config.ShellQuote it always
+// produces a single word, including for the empty string.
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	safe := true
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+			strings.ContainsRune("-_./=:,+@%", r)) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// expandHome expands a leading ~/ in a local path.
+func expandHome(p string) string {
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(p, "~"))
+		}
+	}
+	return p
+}
+
+// Verify SSHConnection implements Connection.
+var _ Connection = (*SSHConnection)(nil)
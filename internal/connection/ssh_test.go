@@ -0,0 +1,376 @@
+package connection
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// newLoopbackSSH returns an SSHConnection whose ssh binary is a stub that
+// runs the remote command with the local sh. This exercises the full
+// command-building and error-mapping path without needing an sshd.
+//
+// Set GT_TEST_SSH_HOST (e.g. "user@localhost") to run against a real sshd
+// instead; GT_TEST_SSH_KEY optionally selects the identity file.
+func newLoopbackSSH(t *testing.T, townPath string) *SSHConnection {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("ssh connection tests require a POSIX shell")
+	}
+
+	if host := os.Getenv("GT_TEST_SSH_HOST"); host != "" {
+		return NewSSHConnection(&Machine{
+			Name:     "test",
+			Type:     "ssh",
+			Host:     host,
+			KeyPath:  os.Getenv("GT_TEST_SSH_KEY"),
+			TownPath: townPath,
+		})
+	}
+
+	stub := filepath.Join(t.TempDir(), "ssh")
+	script := "#!/bin/sh\nfor last; do :; done\nexec sh -c \"$last\"\n"
+	if err := os.WriteFile(stub, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	c := NewSSHConnection(&Machine{Name: "test", Type: "ssh", Host: "user@test", TownPath: townPath})
+	c.sshBin = stub
+	return c
+}
+
+func TestSSHConnection_FileOps(t *testing.T) {
+	town := t.TempDir()
+	c := newLoopbackSSH(t, town)
+
+	if c.IsLocal() {
+		t.Error("IsLocal() = true, want false")
+	}
+
+	// Relative paths are rooted at TownPath.
+	if err := c.MkdirAll("rigs/gastown", 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	content := []byte("hello 'quoted' $world\n")
+	if err := c.WriteFile("rigs/gastown/notes.txt", content, 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	onDisk, err := os.ReadFile(filepath.Join(town, "rigs", "gastown", "notes.txt"))
+	if err != nil {
+		t.Fatalf("file not written under TownPath: %v", err)
+	}
+	if string(onDisk) != string(content) {
+		t.Errorf("on-disk content = %q, want %q", onDisk, content)
+	}
+
+	got, err := c.ReadFile(filepath.Join(town, "rigs/gastown/notes.txt"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(got) != string(content) {
+		t.Errorf("ReadFile = %q, want %q", got, content)
+	}
+
+	fi, err := c.Stat("rigs/gastown/notes.txt")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if fi.Name() != "notes.txt" || fi.Size() != int64(len(content)) || fi.IsDir() {
+		t.Errorf("Stat = %+v, unexpected", fi)
+	}
+	if fi.Mode().Perm() != 0600 {
+		t.Errorf("Stat mode = %v, want 0600", fi.Mode().Perm())
+	}
+
+	dirInfo, err := c.Stat("rigs")
+	if err != nil {
+		t.Fatalf("Stat dir: %v", err)
+	}
+	if !dirInfo.IsDir() {
+		t.Error("Stat(rigs).IsDir() = false, want true")
+	}
+
+	if err := c.WriteFile("rigs/gastown/other.txt", nil, 0644); err != nil {
+		t.Fatalf("WriteFile empty: %v", err)
+	}
+	matches, err := c.Glob("rigs/gastown/*.txt")
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	if len(matches) != 2 || !strings.HasSuffix(matches[0], "notes.txt") || !strings.HasSuffix(matches[1], "other.txt") {
+		t.Errorf("Glob = %v, want notes.txt and other.txt", matches)
+	}
+	none, err := c.Glob("rigs/gastown/*.none")
+	if err != nil || len(none) != 0 {
+		t.Errorf("Glob(no match) = %v, %v; want empty", none, err)
+	}
+
+	if ok, err := c.Exists("rigs/gastown/notes.txt"); err != nil || !ok {
+		t.Errorf("Exists = %v, %v; want true", ok, err)
+	}
+	if err := c.Remove("rigs/gastown/notes.txt"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if ok, err := c.Exists("rigs/gastown/notes.txt"); err != nil || ok {
+		t.Errorf("Exists after Remove = %v, %v; want false", ok, err)
+	}
+	if err := c.Remove("rigs/gastown/notes.txt"); err != nil {
+		t.Errorf("Remove of missing file = %v, want nil", err)
+	}
+
+	if err := c.RemoveAll("rigs"); err != nil {
+		t.Fatalf("RemoveAll: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(town, "rigs")); !os.IsNotExist(err) {
+		t.Errorf("rigs still exists after RemoveAll: %v", err)
+	}
+}
+
+func TestSSHConnection_NotFound(t *testing.T) {
+	c := newLoopbackSSH(t, t.TempDir())
+
+	_, err := c.ReadFile("missing.txt")
+	var nf *NotFoundError
+	if !errors.As(err, &nf) {
+		t.Fatalf("ReadFile(missing) error = %v, want NotFoundError", err)
+	}
+	if _, err := c.Stat("missing.txt"); !errors.As(err, &nf) {
+		t.Errorf("Stat(missing) error = %v, want NotFoundError", err)
+	}
+
+	// A missing parent directory is not an access failure.
+	err = c.WriteFile("no/such/dir/file.txt", []byte("x"), 0644)
+	var pe *PermissionError
+	if err == nil || errors.As(err, &pe) {
+		t.Errorf("WriteFile(missing parent) error = %v, want non-permission error", err)
+	}
+}
+
+func TestSSHConnection_Exec(t *testing.T) {
+	town := t.TempDir()
+	c := newLoopbackSSH(t, town)
+
+	out, err := c.Exec("echo", "a b", "it's")
+	if err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	if strings.TrimSpace(string(out)) != "a b it's" {
+		t.Errorf("Exec output = %q", out)
+	}
+
+	// Exec runs from TownPath by default.
+	out, err = c.Exec("pwd")
+	if err != nil {
+		t.Fatalf("Exec pwd: %v", err)
+	}
+	if got, _ := filepath.EvalSymlinks(strings.TrimSpace(string(out))); got != mustEvalSymlinks(t, town) {
+		t.Errorf("Exec pwd = %q, want %q", got, town)
+	}
+
+	sub := filepath.Join(town, "sub")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	out, err = c.ExecDir("sub", "pwd")
+	if err != nil {
+		t.Fatalf("ExecDir: %v", err)
+	}
+	if got, _ := filepath.EvalSymlinks(strings.TrimSpace(string(out))); got != mustEvalSymlinks(t, sub) {
+		t.Errorf("ExecDir pwd = %q, want %q", got, sub)
+	}
+
+	out, err = c.ExecEnv(map[string]string{"GT_TEST_VALUE": "x y"}, "sh", "-c", "echo \"$GT_TEST_VALUE\"")
+	if err != nil {
+		t.Fatalf("ExecEnv: %v", err)
+	}
+	if strings.TrimSpace(string(out)) != "x y" {
+		t.Errorf("ExecEnv output = %q, want %q", out, "x y")
+	}
+
+	// Remote command failures surface as exit errors, not connection errors.
+	_, err = c.Exec("false")
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Errorf("Exec(false) error = %v, want *exec.ExitError", err)
+	}
+}
+
+func TestSSHConnection_ConnectionFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	stub := filepath.Join(t.TempDir(), "ssh")
+	script := "#!/bin/sh\necho 'ssh: connect to host test port 22: Connection refused' >&2\nexit 255\n"
+	if err := os.WriteFile(stub, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	c := NewSSHConnection(&Machine{Name: "box2", Type: "ssh", Host: "user@test"})
+	c.sshBin = stub
+
+	_, err := c.ReadFile("/etc/hostname")
+	var connErr *ConnectionError
+	if !errors.As(err, &connErr) {
+		t.Fatalf("error = %v, want ConnectionError", err)
+	}
+	if connErr.Machine != "box2" || !strings.Contains(connErr.Error(), "Connection refused") {
+		t.Errorf("ConnectionError = %v", connErr)
+	}
+
+	if _, err := c.TmuxHasSession("gt-x"); !errors.As(err, &connErr) {
+		t.Errorf("TmuxHasSession error = %v, want ConnectionError", err)
+	}
+}
+
+func TestSSHConnection_Tmux(t *testing.T) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not installed")
+	}
+	c := newLoopbackSSH(t, t.TempDir())
+
+	// Use an isolated tmux server so the test doesn't touch user sessions.
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
+
+	name := "gt-ssh-test-" + strings.ReplaceAll(filepath.Base(t.TempDir()), ".", "")
+	if ok, err := c.TmuxHasSession(name); err != nil || ok {
+		t.Fatalf("TmuxHasSession before create = %v, %v", ok, err)
+	}
+	if err := c.TmuxNewSession(name, ""); err != nil {
+		t.Fatalf("TmuxNewSession: %v", err)
+	}
+	defer func() { _ = c.TmuxKillSession(name) }()
+
+	if ok, err := c.TmuxHasSession(name); err != nil || !ok {
+		t.Fatalf("TmuxHasSession after create = %v, %v", ok, err)
+	}
+	sessions, err := c.TmuxListSessions()
+	if err != nil {
+		t.Fatalf("TmuxListSessions: %v", err)
+	}
+	found := false
+	for _, s := range sessions {
+		if s == name {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("TmuxListSessions = %v, missing %s", sessions, name)
+	}
+	if err := c.TmuxSendKeys(name, "echo gt-ssh-marker"); err != nil {
+		t.Fatalf("TmuxSendKeys: %v", err)
+	}
+	if _, err := c.TmuxCapturePane(name, 20); err != nil {
+		t.Errorf("TmuxCapturePane: %v", err)
+	}
+	if err := c.TmuxKillSession(name); err != nil {
+		t.Fatalf("TmuxKillSession: %v", err)
+	}
+	if ok, _ := c.TmuxHasSession(name); ok {
+		t.Error("session still exists after TmuxKillSession")
+	}
+}
+
+func TestSSHConnection_TmuxSendKeysRawIsExact(t *testing.T) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not installed")
+	}
+	c := newLoopbackSSH(t, t.TempDir())
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
+
+	// Only name+"2" exists; a prefix-matched target would interrupt it.
+	name := "gt-ssh-raw-" + strings.ReplaceAll(filepath.Base(t.TempDir()), ".", "")
+	if err := c.TmuxNewSession(name+"2", ""); err != nil {
+		t.Fatalf("TmuxNewSession: %v", err)
+	}
+	defer func() { _ = c.TmuxKillSession(name + "2") }()
+
+	if err := c.TmuxSendKeysRaw(name, "C-c"); err == nil {
+		t.Errorf("TmuxSendKeysRaw(%q) reached session %q", name, name+"2")
+	}
+	if err := c.TmuxSendKeysRaw(name+"2", "C-c"); err != nil {
+		t.Errorf("TmuxSendKeysRaw on the exact session: %v", err)
+	}
+}
+
+func TestParseStatOutput(t *testing.T) {
+	fi, err := parseStatOutput("rigs", "4096 41ed 1700000000\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fi.IsDir() || fi.Mode()&fs.ModeDir == 0 || fi.Mode().Perm() != 0755 {
+		t.Errorf("dir parse = %+v", fi)
+	}
+	if fi.ModTime().Unix() != 1700000000 {
+		t.Errorf("ModTime = %v", fi.ModTime())
+	}
+
+	fi, err = parseStatOutput("f", "12 81a4 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.IsDir() || fi.Size() != 12 || fi.Mode().Perm() != 0644 {
+		t.Errorf("file parse = %+v", fi)
+	}
+
+	if _, err := parseStatOutput("x", "garbage"); err == nil {
+		t.Error("expected error for malformed stat output")
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"":           "''",
+		"plain":      "plain",
+		"/a/b-c.txt": "/a/b-c.txt",
+		"a b":        "'a b'",
+		"it's":       `'it'\''s'`,
+		"$(rm -rf)":  "'$(rm -rf)'",
+		"~/x":        "'~/x'",
+	}
+	for in, want := range tests {
+		if got := shellQuote(in); got != want {
+			t.Errorf("shellQuote(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSSHConnection_ResolvePath(t *testing.T) {
+	c := NewSSHConnection(&Machine{Name: "vm", Type: "ssh", Host: "h", TownPath: "/home/gt"})
+	if got := c.ResolvePath("gastown/polecats"); got != "/home/gt/gastown/polecats" {
+		t.Errorf("relative = %q", got)
+	}
+	if got := c.ResolvePath("/tmp/x/../y"); got != "/tmp/y" {
+		t.Errorf("absolute = %q", got)
+	}
+}
+
+func TestMachineRegistry_SSHConnection(t *testing.T) {
+	r, err := NewMachineRegistry(filepath.Join(t.TempDir(), "machines.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add(&Machine{Name: "vm", Type: "ssh", Host: "gt@build2", TownPath: "/home/gt/gt"}); err != nil {
+		t.Fatal(err)
+	}
+	conn, err := r.Connection("vm")
+	if err != nil {
+		t.Fatalf("Connection(vm): %v", err)
+	}
+	if conn.IsLocal() || conn.Name() != "vm" {
+		t.Errorf("Connection(vm) = %s local=%v", conn.Name(), conn.IsLocal())
+	}
+}
+
+func mustEvalSymlinks(t *testing.T, p string) string {
+	t.Helper()
+	resolved, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resolved
+}
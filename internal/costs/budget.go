@@ -0,0 +1,382 @@
+package costs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// LoadBudgetRules returns the town's budget rules followed by each rig's.
+// Rig rules are limited to their rig. Invalid rules are skipped and
+// reported in errs.
+func LoadBudgetRules(townRoot string) (rules []config.BudgetRule, errs []error) {
+	if ts, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err != nil {
+		errs = append(errs, fmt.Errorf("loading town settings: %w", err))
+	} else {
+		for _, r := range ts.Budgets {
+			if err := r.Validate(); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			rules = append(rules, r)
+		}
+	}
+
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return rules, errs
+	}
+	rigNames := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		rigNames = append(rigNames, name)
+	}
+	sort.Strings(rigNames)
+	for _, name := range rigNames {
+		rs, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, name)))
+		if err != nil {
+			if !errors.Is(err, config.ErrNotFound) {
+				errs = append(errs, fmt.Errorf("loading %s settings: %w", name, err))
+			}
+			continue
+		}
+		for _, r := range rs.Budgets {
+			r.Rig = name
+			if err := r.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+				continue
+			}
+			rules = append(rules, r)
+		}
+	}
+	return rules, errs
+}
+
+// Spend is what one subject spent today under a budget rule.
+type Spend struct {
+	Rule     config.BudgetRule
+	Subject  string   // rig, convoy ID or session; empty for the town scope
+	Rigs     []string // rigs whose sessions contributed
+	SpentUSD float64
+}
+
+// Over reports whether the spend crossed the rule's limit.
+func (s Spend) Over() bool { return s.SpentUSD >= s.Rule.LimitUSD }
+
+// Warn reports whether the spend crossed the rule's warning threshold.
+func (s Spend) Warn() bool {
+	return s.Rule.WarnAt > 0 && s.SpentUSD >= s.Rule.WarnAt*s.Rule.LimitUSD
+}
+
+// EvaluateBudgets totals today's ledger entries for each rule and subject.
+// Each ledger record holds a session's cumulative cost so far, so a session
+// counts once, at its highest recorded cost.
+func EvaluateBudgets(rules []config.BudgetRule, entries []LedgerEntry, now time.Time) []Spend {
+	today := now.Format("2006-01-02")
+	var out []Spend
+	for _, rule := range rules {
+		scope := rule.EffectiveScope()
+		type key struct{ subject, session string }
+		sessions := make(map[key]LedgerEntry)
+		for _, e := range entries {
+			if e.EndedAt.Local().Format("2006-01-02") != today {
+				continue
+			}
+			if (rule.Rig != "" && e.Rig != rule.Rig) || (rule.Role != "" && e.Role != rule.Role) {
+				continue
+			}
+			var subject string
+			switch scope {
+			case config.BudgetScopeRig:
+				subject = e.Rig
+			case config.BudgetScopeConvoy:
+				subject = e.Convoy
+			case config.BudgetScopeSession:
+				subject = e.SessionID
+			}
+			if subject == "" && scope != config.BudgetScopeTown {
+				continue
+			}
+			k := key{subject, e.SessionID}
+			if prev, ok := sessions[k]; !ok || e.CostUSD > prev.CostUSD {
+				sessions[k] = e
+			}
+		}
+
+		bySubject := make(map[string]*Spend)
+		rigSeen := make(map[string]map[string]bool)
+		for k, e := range sessions {
+			s, ok := bySubject[k.subject]
+			if !ok {
+				s = &Spend{Rule: rule, Subject: k.subject}
+				bySubject[k.subject] = s
+				rigSeen[k.subject] = make(map[string]bool)
+			}
+			s.SpentUSD += e.CostUSD
+			if e.Rig != "" && !rigSeen[k.subject][e.Rig] {
+				rigSeen[k.subject][e.Rig] = true
+				s.Rigs = append(s.Rigs, e.Rig)
+			}
+		}
+		subjects := make([]string, 0, len(bySubject))
+		for subject := range bySubject {
+			subjects = append(subjects, subject)
+		}
+		sort.Strings(subjects)
+		for _, subject := range subjects {
+			s := bySubject[subject]
+			sort.Strings(s.Rigs)
+			out = append(out, *s)
+		}
+	}
+	return out
+}
+
+// Breach records a budget limit, or its warning threshold, crossed today.
+type Breach struct {
+	Key      string    `json:"key"` // rule name, plus ":" and the subject if any
+	Rule     string    `json:"rule"`
+	Scope    string    `json:"scope"`
+	Subject  string    `json:"subject,omitempty"`
+	Rigs     []string  `json:"rigs,omitempty"`
+	SpentUSD float64   `json:"spent_usd"`
+	LimitUSD float64   `json:"limit_usd"`
+	Warning  bool      `json:"warning,omitempty"` // over warn_at, under the limit
+	Actions  []string  `json:"actions,omitempty"`
+	CostTier string    `json:"cost_tier,omitempty"`
+	At       time.Time `json:"at"`
+	Handled  bool      `json:"handled,omitempty"` // actions have been taken
+}
+
+// BreachFor returns the breach for a spend, if it crossed a threshold.
+func BreachFor(s Spend, now time.Time) (Breach, bool) {
+	if !s.Over() && !s.Warn() {
+		return Breach{}, false
+	}
+	key := s.Rule.RuleName()
+	if s.Subject != "" {
+		key += ":" + s.Subject
+	}
+	b := Breach{
+		Key:      key,
+		Rule:     s.Rule.RuleName(),
+		Scope:    s.Rule.EffectiveScope(),
+		Subject:  s.Subject,
+		Rigs:     s.Rigs,
+		SpentUSD: s.SpentUSD,
+		LimitUSD: s.Rule.LimitUSD,
+		Warning:  !s.Over(),
+		Actions:  s.Rule.Actions,
+		CostTier: s.Rule.CostTier,
+		At:       now,
+	}
+	if b.Warning {
+		b.Actions = []string{config.BudgetActionEscalate}
+	} else if len(b.Actions) == 0 {
+		b.Actions = []string{config.BudgetActionEscalate}
+	}
+	return b, true
+}
+
+// HasAction reports whether the breach takes the given action.
+func (b Breach) HasAction(action string) bool {
+	for _, a := range b.Actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// coversRig reports whether a breach applies to a rig's work.
+func (b Breach) coversRig(rig string) bool {
+	if b.Scope == config.BudgetScopeTown {
+		return true
+	}
+	for _, r := range b.Rigs {
+		if r == rig {
+			return true
+		}
+	}
+	return false
+}
+
+// BudgetState is the daemon's record of today's budget breaches. Holds
+// (stop_sling, pause_polecats, cost_tier) last until the day ends or the
+// breach is cleared with 'gt costs budget clear'.
+type BudgetState struct {
+	Day       string    `json:"day"`
+	Breaches  []Breach  `json:"breaches,omitempty"`
+	Cleared   []string  `json:"cleared,omitempty"` // breach keys cleared by hand today
+	UpdatedAt time.Time `json:"updated_at"`
+	// CostTiers are settings switched by cost_tier actions, kept across
+	// days until the daemon puts them back.
+	CostTiers []CostTierHold `json:"cost_tiers,omitempty"`
+}
+
+// CostTierHold records that breaches switched the cost tier of one rig's
+// settings, or the town's if Rig is empty. Snapshot is the settings before
+// the first of them; they are restored once none of Keys is active.
+type CostTierHold struct {
+	Rig      string                   `json:"rig,omitempty"`
+	Keys     []string                 `json:"keys"`
+	Snapshot *config.CostTierSnapshot `json:"snapshot"`
+}
+
+// BudgetStatePath returns the path to the town's budget state file.
+func BudgetStatePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "budgets.json")
+}
+
+// LoadBudgetState reads the budget state for now's day. A missing file or
+// a state from an earlier day yields an empty state.
+func LoadBudgetState(townRoot string, now time.Time) (*BudgetState, error) {
+	today := now.Format("2006-01-02")
+	empty := &BudgetState{Day: today}
+	data, err := os.ReadFile(BudgetStatePath(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return empty, nil
+		}
+		return empty, err
+	}
+	var st BudgetState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return empty, err
+	}
+	if st.Day != today {
+		// Yesterday's breaches are over, but the settings they switched
+		// still need putting back.
+		empty.CostTiers = st.CostTiers
+		return empty, nil
+	}
+	return &st, nil
+}
+
+// SaveBudgetState writes the budget state.
+func SaveBudgetState(townRoot string, st *BudgetState) error {
+	path := BudgetStatePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, st)
+}
+
+// IsCleared reports whether a breach key was cleared by hand today.
+func (st *BudgetState) IsCleared(key string) bool {
+	for _, k := range st.Cleared {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// HoldCostTier records that breach key switched the cost tier of a rig's
+// settings (the town's for rig ""). snap, taken before the switch, is kept
+// only if no other breach holds those settings already.
+func (st *BudgetState) HoldCostTier(rig, key string, snap *config.CostTierSnapshot) {
+	for i := range st.CostTiers {
+		h := &st.CostTiers[i]
+		if h.Rig != rig {
+			continue
+		}
+		if !slices.Contains(h.Keys, key) {
+			h.Keys = append(h.Keys, key)
+		}
+		return
+	}
+	st.CostTiers = append(st.CostTiers, CostTierHold{Rig: rig, Keys: []string{key}, Snapshot: snap})
+}
+
+// ReleaseCostTiers drops from each cost tier hold the breaches that are no
+// longer active (cleared, gone from the rules, or from an earlier day), and
+// removes and returns the holds left without any, whose settings are due
+// to be restored.
+func (st *BudgetState) ReleaseCostTiers() []CostTierHold {
+	active := map[string]bool{}
+	for _, b := range st.active(config.BudgetActionCostTier) {
+		active[b.Key] = true
+	}
+	var kept, released []CostTierHold
+	for _, h := range st.CostTiers {
+		var keys []string
+		for _, k := range h.Keys {
+			if active[k] {
+				keys = append(keys, k)
+			}
+		}
+		h.Keys = keys
+		if len(keys) == 0 {
+			released = append(released, h)
+		} else {
+			kept = append(kept, h)
+		}
+	}
+	st.CostTiers = kept
+	return released
+}
+
+// active returns the breaches over their limit that take action.
+func (st *BudgetState) active(action string) []Breach {
+	var out []Breach
+	for _, b := range st.Breaches {
+		if !b.Warning && b.HasAction(action) && !st.IsCleared(b.Key) {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// SlingHold returns the breach that stops new work for a rig or convoy,
+// if any. Either argument may be empty; town-wide holds apply regardless.
+func (st *BudgetState) SlingHold(rig, convoy string) *Breach {
+	for _, b := range st.active(config.BudgetActionStopSling) {
+		if b.Scope == config.BudgetScopeConvoy {
+			if convoy != "" && b.Subject == convoy {
+				return &b
+			}
+			continue
+		}
+		if b.Scope == config.BudgetScopeTown || (rig != "" && b.coversRig(rig)) {
+			return &b
+		}
+	}
+	return nil
+}
+
+// HasConvoySlingHolds reports whether any convoy is held, so callers can
+// skip looking up a bead's convoy otherwise.
+func (st *BudgetState) HasConvoySlingHolds() bool {
+	for _, b := range st.active(config.BudgetActionStopSling) {
+		if b.Scope == config.BudgetScopeConvoy {
+			return true
+		}
+	}
+	return false
+}
+
+// PolecatPause returns the breach pausing a polecat session, if any.
+// Session-scoped breaches pause only their own session.
+func (st *BudgetState) PolecatPause(rig, sessionName string) *Breach {
+	for _, b := range st.active(config.BudgetActionPausePolecats) {
+		if b.Scope == config.BudgetScopeSession {
+			if b.Subject == sessionName {
+				return &b
+			}
+			continue
+		}
+		if b.coversRig(rig) {
+			return &b
+		}
+	}
+	return nil
+}
@@ -0,0 +1,336 @@
+package costs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func day(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestPriceTable_Lookup(t *testing.T) {
+	table := NewPriceTable([]config.ModelPrice{
+		{Model: "claude-sonnet-4*", EffectiveFrom: "2026-11-01", Input: 2, Output: 10},
+		{Model: "my-local-model", Input: 0, Output: 0},
+		{Model: "gemini-2.5-pro*", Input: 9, Output: 9}, // replaces the built-in entry
+	})
+
+	tests := []struct {
+		model string
+		at    string
+		want  float64 // input price
+	}{
+		{"claude-sonnet-4-20250514", "2026-12-01", 3},   // exact beats prefix
+		{"claude-sonnet-4-5-20250929", "2026-10-31", 3}, // before the override
+		{"claude-sonnet-4-5-20250929", "2026-11-01", 2}, // override in effect
+		{"gemini-2.5-pro", "2026-01-01", 9},
+		{"gemini-2.5-flash-lite", "2026-01-01", 0.10}, // longest prefix
+		{"gemini-2.5-flash", "2026-01-01", 0.30},
+		{"my-local-model", "2026-01-01", 0},
+		{"mystery-model", "2026-01-01", 3}, // fallback
+	}
+	for _, tt := range tests {
+		if got := table.Lookup(tt.model, day(tt.at)).Input; got != tt.want {
+			t.Errorf("Lookup(%q, %s).Input = %v, want %v", tt.model, tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestPriceTable_Cost(t *testing.T) {
+	u := newUsage("claude")
+	u.add("claude-sonnet-4-20250514", Tokens{Input: 1_000_000, Output: 100_000, CacheRead: 2_000_000, CacheWrite: 1_000_000})
+	u.add("claude-3-5-haiku-20241022", Tokens{Input: 1_000_000, Output: 1_000_000})
+
+	total, byModel := NewPriceTable(nil).Cost(u, day("2026-01-01"))
+	// Sonnet: 3 + 1.5 + 0.6 + 3.75 = 8.85; Haiku: 1 + 5 = 6
+	if math.Abs(byModel["claude-sonnet-4-20250514"]-8.85) > 1e-9 || math.Abs(byModel["claude-3-5-haiku-20241022"]-6) > 1e-9 {
+		t.Errorf("byModel = %v", byModel)
+	}
+	if math.Abs(total-14.85) > 1e-9 {
+		t.Errorf("total = %v, want 14.85", total)
+	}
+	if got := u.Model(); got != "claude-3-5-haiku-20241022" {
+		t.Errorf("Model() = %q, want the model with most output", got)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParsers(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CODEX_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	workDir := "/town/gastown/polecats/toast"
+
+	writeFile(t, filepath.Join(home, ".claude", "projects", "-town-gastown-polecats-toast", "a.jsonl"), strings.Join([]string{
+		`{"type":"user","message":{"role":"user"}}`,
+		`{"type":"assistant","requestId":"req_1","message":{"model":"claude-sonnet-4-20250514","usage":{"input_tokens":10,"cache_creation_input_tokens":5,"cache_read_input_tokens":100,"output_tokens":20}}}`,
+		// A second content block of the same request repeats its usage.
+		`{"type":"assistant","requestId":"req_1","message":{"model":"claude-sonnet-4-20250514","usage":{"input_tokens":10,"cache_creation_input_tokens":5,"cache_read_input_tokens":100,"output_tokens":20}}}`,
+		`not json`,
+		`{"type":"assistant","message":{"model":"claude-sonnet-4-20250514","usage":{"input_tokens":1,"output_tokens":2}}}`,
+	}, "\n"))
+
+	sum := sha256.Sum256([]byte(workDir))
+	writeFile(t, filepath.Join(home, ".gemini", "tmp", hex.EncodeToString(sum[:]), "chats", "session-1.json"), `{
+		"sessionId": "s1",
+		"messages": [
+			{"type": "user", "content": "hi"},
+			{"type": "gemini", "model": "gemini-2.5-pro", "tokens": {"input": 1000, "output": 50, "cached": 800, "thoughts": 30, "total": 1080}}
+		]
+	}`)
+
+	writeFile(t, filepath.Join(home, ".codex", "sessions", "2026", "10", "17", "rollout-other.jsonl"),
+		`{"type":"session_meta","payload":{"cwd":"/elsewhere"}}`+"\n")
+	writeFile(t, filepath.Join(home, ".codex", "sessions", "2026", "10", "17", "rollout-toast.jsonl"), strings.Join([]string{
+		`{"type":"session_meta","payload":{"id":"r1","cwd":"` + workDir + `"}}`,
+		`{"type":"turn_context","payload":{"cwd":"` + workDir + `","model":"gpt-5-codex"}}`,
+		`{"type":"event_msg","payload":{"type":"token_count","info":null}}`,
+		`{"type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":1000,"cached_input_tokens":600,"output_tokens":100}}}}`,
+		`{"type":"turn_context","payload":{"model":"gpt-5-mini"}}`,
+		`{"type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":1500,"cached_input_tokens":900,"output_tokens":130}}}}`,
+	}, "\n"))
+
+	storage := filepath.Join(home, ".local", "share", "opencode", "storage")
+	writeFile(t, filepath.Join(storage, "session", "p1", "ses_old.json"), `{"id":"ses_old","directory":"`+workDir+`","time":{"updated":1}}`)
+	writeFile(t, filepath.Join(storage, "session", "p1", "ses_new.json"), `{"id":"ses_new","directory":"`+workDir+`","time":{"updated":2}}`)
+	writeFile(t, filepath.Join(storage, "message", "ses_new", "msg_1.json"), `{"role":"user"}`)
+	writeFile(t, filepath.Join(storage, "message", "ses_new", "msg_2.json"),
+		`{"role":"assistant","modelID":"claude-sonnet-4-20250514","tokens":{"input":7,"output":3,"reasoning":2,"cache":{"read":40,"write":4}}}`)
+
+	tests := []struct {
+		preset string
+		want   map[string]Tokens
+	}{
+		{"claude", map[string]Tokens{"claude-sonnet-4-20250514": {Input: 11, Output: 22, CacheRead: 100, CacheWrite: 5}}},
+		{"gemini", map[string]Tokens{"gemini-2.5-pro": {Input: 200, Output: 80, CacheRead: 800}}},
+		{"codex", map[string]Tokens{
+			"gpt-5-codex": {Input: 400, Output: 100, CacheRead: 600},
+			"gpt-5-mini":  {Input: 200, Output: 30, CacheRead: 300},
+		}},
+		{"opencode", map[string]Tokens{"claude-sonnet-4-20250514": {Input: 7, Output: 5, CacheRead: 40, CacheWrite: 4}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.preset, func(t *testing.T) {
+			u, err := SessionUsage(tt.preset, workDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if u.Agent != tt.preset {
+				t.Errorf("Agent = %q", u.Agent)
+			}
+			if len(u.Models) != len(tt.want) {
+				t.Errorf("models = %v, want %v", u.Models, tt.want)
+			}
+			for model, want := range tt.want {
+				if got := u.Models[model]; got == nil || *got != want {
+					t.Errorf("%s = %+v, want %+v", model, got, want)
+				}
+			}
+		})
+	}
+
+	if _, err := SessionUsage("amp", workDir); err == nil {
+		t.Error("SessionUsage(amp) succeeded, want no-parser error")
+	}
+}
+
+func TestEvaluateBudgets(t *testing.T) {
+	now := time.Date(2026, 10, 17, 15, 0, 0, 0, time.Local)
+	entries := []LedgerEntry{
+		// Two records for one session: only the larger counts.
+		{SessionID: "gt-gastown-toast", Role: "polecat", Rig: "gastown", CostUSD: 3, EndedAt: now.Add(-2 * time.Hour)},
+		{SessionID: "gt-gastown-toast", Role: "polecat", Rig: "gastown", CostUSD: 5, EndedAt: now.Add(-time.Hour), Convoy: "hq-cv-1"},
+		{SessionID: "gt-gastown-witness", Role: "witness", Rig: "gastown", CostUSD: 2, EndedAt: now.Add(-time.Hour)},
+		{SessionID: "gt-beads-nux", Role: "polecat", Rig: "beads", CostUSD: 4, EndedAt: now.Add(-time.Hour), Convoy: "hq-cv-1"},
+		// Yesterday's spend is ignored.
+		{SessionID: "gt-beads-old", Role: "polecat", Rig: "beads", CostUSD: 100, EndedAt: now.Add(-24 * time.Hour)},
+	}
+	rules := []config.BudgetRule{
+		{Scope: config.BudgetScopeTown, LimitUSD: 10},
+		{LimitUSD: 6},
+		{Scope: config.BudgetScopeConvoy, LimitUSD: 20},
+		{Scope: config.BudgetScopeSession, Role: "polecat", LimitUSD: 4.5},
+	}
+
+	got := make(map[string]float64)
+	for _, s := range EvaluateBudgets(rules, entries, now) {
+		got[s.Rule.RuleName()+":"+s.Subject] = s.SpentUSD
+	}
+	want := map[string]float64{
+		"town-10:":                             11,
+		"rig-6:gastown":                        7,
+		"rig-6:beads":                          4,
+		"convoy-20:hq-cv-1":                    9,
+		"session-polecat-4.5:gt-gastown-toast": 5,
+		"session-polecat-4.5:gt-beads-nux":     4,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("spends = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %v, want %v", k, got[k], v)
+		}
+	}
+}
+
+func TestBreachFor(t *testing.T) {
+	now := time.Now()
+	rule := config.BudgetRule{LimitUSD: 10, WarnAt: 0.8, Actions: []string{config.BudgetActionStopSling}}
+
+	if _, ok := BreachFor(Spend{Rule: rule, Subject: "gastown", SpentUSD: 7}, now); ok {
+		t.Error("spend under warn_at should not breach")
+	}
+	b, ok := BreachFor(Spend{Rule: rule, Subject: "gastown", SpentUSD: 8.5}, now)
+	if !ok || !b.Warning || b.HasAction(config.BudgetActionStopSling) || !b.HasAction(config.BudgetActionEscalate) {
+		t.Errorf("warning breach = %+v, want an escalate-only warning", b)
+	}
+	b, ok = BreachFor(Spend{Rule: rule, Subject: "gastown", SpentUSD: 12}, now)
+	if !ok || b.Warning || !b.HasAction(config.BudgetActionStopSling) || b.Key != "rig-10:gastown" {
+		t.Errorf("over breach = %+v", b)
+	}
+}
+
+func TestBudgetState_Holds(t *testing.T) {
+	now := time.Now()
+	st := &BudgetState{Day: now.Format("2006-01-02"), Breaches: []Breach{
+		{Key: "rig-10:gastown", Scope: config.BudgetScopeRig, Subject: "gastown", Rigs: []string{"gastown"},
+			Actions: []string{config.BudgetActionStopSling, config.BudgetActionPausePolecats}},
+		{Key: "convoy-20:hq-cv-1", Scope: config.BudgetScopeConvoy, Subject: "hq-cv-1", Rigs: []string{"beads"},
+			Actions: []string{config.BudgetActionStopSling}},
+		{Key: "session-5:gt-beads-nux", Scope: config.BudgetScopeSession, Subject: "gt-beads-nux", Rigs: []string{"beads"},
+			Actions: []string{config.BudgetActionPausePolecats}},
+		{Key: "town-100", Scope: config.BudgetScopeTown, Warning: true,
+			Actions: []string{config.BudgetActionStopSling}},
+	}}
+
+	if st.SlingHold("gastown", "") == nil {
+		t.Error("gastown should be held")
+	}
+	if st.SlingHold("beads", "") != nil {
+		t.Error("beads should not be held by rig or warning breaches")
+	}
+	if !st.HasConvoySlingHolds() || st.SlingHold("beads", "hq-cv-1") == nil {
+		t.Error("convoy hq-cv-1 should be held")
+	}
+	if st.PolecatPause("beads", "gt-beads-nux") == nil || st.PolecatPause("beads", "gt-beads-furiosa") != nil {
+		t.Error("session pause should cover only its own session")
+	}
+
+	st.Cleared = []string{"rig-10:gastown"}
+	if st.SlingHold("gastown", "") != nil || st.PolecatPause("gastown", "gt-gastown-toast") != nil {
+		t.Error("cleared breach should not hold")
+	}
+
+	st.Breaches[3].Warning = false
+	if st.SlingHold("", "") == nil || st.SlingHold("beads", "") == nil {
+		t.Error("a town breach should hold work with or without a rig")
+	}
+}
+
+func TestBudgetState_ResetsDaily(t *testing.T) {
+	townRoot := t.TempDir()
+	yesterday := time.Now().Add(-24 * time.Hour)
+	if err := SaveBudgetState(townRoot, &BudgetState{Day: yesterday.Format("2006-01-02"), Cleared: []string{"x"}}); err != nil {
+		t.Fatal(err)
+	}
+	st, err := LoadBudgetState(townRoot, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(st.Cleared) != 0 || st.Day != time.Now().Format("2006-01-02") {
+		t.Errorf("state = %+v, want a fresh state for today", st)
+	}
+}
+
+func TestBudgetState_CostTierHolds(t *testing.T) {
+	townRoot := t.TempDir()
+	day := time.Now().Format("2006-01-02")
+	st := &BudgetState{Day: day, Breaches: []Breach{
+		{Key: "town-100", Scope: config.BudgetScopeTown, Actions: []string{config.BudgetActionCostTier}},
+		{Key: "rig-50:gastown", Scope: config.BudgetScopeRig, Actions: []string{config.BudgetActionCostTier}},
+	}}
+	first := &config.CostTierSnapshot{RoleAgents: map[string]string{"witness": "gemini"}}
+	st.HoldCostTier("", "town-100", first)
+	st.HoldCostTier("", "rig-50:gastown", &config.CostTierSnapshot{})
+	if len(st.CostTiers) != 1 || st.CostTiers[0].Snapshot != first || len(st.CostTiers[0].Keys) != 2 {
+		t.Fatalf("holds = %+v, want one town hold with the first snapshot", st.CostTiers)
+	}
+
+	// Still held while either breach is active.
+	st.Cleared = []string{"town-100"}
+	if released := st.ReleaseCostTiers(); len(released) != 0 {
+		t.Errorf("released %+v with a breach still active", released)
+	}
+	st.Cleared = append(st.Cleared, "rig-50:gastown")
+	if released := st.ReleaseCostTiers(); len(released) != 1 || released[0].Snapshot != first {
+		t.Errorf("released = %+v, want the town hold", released)
+	}
+	if len(st.CostTiers) != 0 {
+		t.Errorf("holds left = %+v", st.CostTiers)
+	}
+
+	// Holds outlive the day, so the next one can restore them.
+	st.HoldCostTier("gastown", "rig-50:gastown", first)
+	st.Day = time.Now().Add(-24 * time.Hour).Format("2006-01-02")
+	if err := SaveBudgetState(townRoot, st); err != nil {
+		t.Fatal(err)
+	}
+	next, err := LoadBudgetState(townRoot, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(next.Breaches) != 0 || len(next.CostTiers) != 1 || next.CostTiers[0].Rig != "gastown" {
+		t.Errorf("next day's state = %+v, want no breaches and the gastown hold", next)
+	}
+	if released := next.ReleaseCostTiers(); len(released) != 1 {
+		t.Errorf("released on the next day = %+v, want the gastown hold", released)
+	}
+}
+
+func TestLoadBudgetRules_SkipsInvalidRigRules(t *testing.T) {
+	townRoot := t.TempDir()
+	rigs := &config.RigsConfig{Version: config.CurrentRigsVersion, Rigs: map[string]config.RigEntry{"gastown": {}}}
+	if err := config.SaveRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"), rigs); err != nil {
+		t.Fatal(err)
+	}
+	rs := config.NewRigSettings()
+	rs.Budgets = []config.BudgetRule{
+		{LimitUSD: 20},
+		{Name: "broken", LimitUSD: 5, Actions: []string{"shout"}},
+	}
+	if err := config.SaveRigSettings(config.RigSettingsPath(filepath.Join(townRoot, "gastown")), rs); err != nil {
+		t.Fatal(err)
+	}
+
+	rules, errs := LoadBudgetRules(townRoot)
+	if len(rules) != 1 || rules[0].Rig != "gastown" || rules[0].LimitUSD != 20 {
+		t.Errorf("rules = %+v, want only the valid gastown rule", rules)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), `unknown action "shout"`) {
+		t.Errorf("errs = %v, want the broken rule reported", errs)
+	}
+}
@@ -0,0 +1,59 @@
+package costs
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LedgerEntry is one line of the costs ledger written by 'gt costs record'.
+type LedgerEntry struct {
+	SessionID string             `json:"session_id"`
+	Role      string             `json:"role"`
+	Rig       string             `json:"rig,omitempty"`
+	Worker    string             `json:"worker,omitempty"`
+	Agent     string             `json:"agent,omitempty"`
+	Model     string             `json:"model,omitempty"`
+	ByModel   map[string]float64 `json:"by_model,omitempty"`
+	CostUSD   float64            `json:"cost_usd"`
+	EndedAt   time.Time          `json:"ended_at"`
+	WorkItem  string             `json:"work_item,omitempty"`
+	Convoy    string             `json:"convoy,omitempty"`
+	Account   string             `json:"account,omitempty"` // quota account handle, if any
+}
+
+// LedgerPath returns the path to the costs ledger (~/.gt/costs.jsonl).
+func LedgerPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "/tmp/gt-costs.jsonl" // Fallback
+	}
+	return filepath.Join(home, ".gt", "costs.jsonl")
+}
+
+// ReadLedger reads every entry in the ledger at path, skipping malformed
+// lines. A missing ledger has no entries.
+func ReadLedger(path string) ([]LedgerEntry, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is the ledger path
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var entries []LedgerEntry
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var e LedgerEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
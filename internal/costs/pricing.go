@@ -0,0 +1,152 @@
+// Package costs turns agent transcripts into token usage and USD cost.
+//
+// Each agent preset writes its own transcript format; a Parser per preset
+// finds the latest session for a working directory and sums its tokens by
+// model. A PriceTable prices those tokens, using town settings overrides
+// with effective dates on top of the built-in table.
+package costs
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// FallbackModel is the price table entry used for models with no other match.
+const FallbackModel = "*"
+
+// DefaultPrices is the built-in price table, in USD per million tokens.
+// Town settings "pricing" entries are merged over it.
+var DefaultPrices = []config.ModelPrice{
+	// Anthropic. See https://www.anthropic.com/pricing
+	{Model: "claude-opus-4-5-20251101", Input: 15.0, Output: 75.0, CacheRead: 1.5, CacheWrite: 18.75},
+	{Model: "claude-opus-4*", Input: 15.0, Output: 75.0, CacheRead: 1.5, CacheWrite: 18.75},
+	{Model: "claude-sonnet-4-20250514", Input: 3.0, Output: 15.0, CacheRead: 0.3, CacheWrite: 3.75},
+	{Model: "claude-sonnet-4*", Input: 3.0, Output: 15.0, CacheRead: 0.3, CacheWrite: 3.75},
+	{Model: "claude-3-5-haiku-20241022", Input: 1.0, Output: 5.0, CacheRead: 0.1, CacheWrite: 1.25},
+	{Model: "claude-haiku-4*", Input: 1.0, Output: 5.0, CacheRead: 0.1, CacheWrite: 1.25},
+
+	// Google. See https://ai.google.dev/pricing
+	{Model: "gemini-2.5-pro*", Input: 1.25, Output: 10.0, CacheRead: 0.31},
+	{Model: "gemini-2.5-flash*", Input: 0.30, Output: 2.50, CacheRead: 0.075},
+	{Model: "gemini-2.5-flash-lite*", Input: 0.10, Output: 0.40, CacheRead: 0.025},
+
+	// OpenAI. See https://openai.com/api/pricing
+	{Model: "gpt-5*", Input: 1.25, Output: 10.0, CacheRead: 0.125},
+	{Model: "gpt-5-mini*", Input: 0.25, Output: 2.0, CacheRead: 0.025},
+	{Model: "gpt-5-nano*", Input: 0.05, Output: 0.40, CacheRead: 0.005},
+	{Model: "gpt-4.1*", Input: 2.0, Output: 8.0, CacheRead: 0.5},
+	{Model: "o4-mini*", Input: 1.1, Output: 4.4, CacheRead: 0.275},
+
+	// Unknown models are priced like Sonnet.
+	{Model: FallbackModel, Input: 3.0, Output: 15.0, CacheRead: 0.3, CacheWrite: 3.75},
+}
+
+// PriceTable looks up model prices as of a given time.
+type PriceTable struct {
+	prices []config.ModelPrice
+}
+
+// NewPriceTable builds a table from the built-in prices plus overrides.
+// An override with the same model and effective date replaces the built-in
+// entry; any other override is added alongside it.
+func NewPriceTable(overrides []config.ModelPrice) *PriceTable {
+	prices := append([]config.ModelPrice(nil), DefaultPrices...)
+	for _, o := range overrides {
+		replaced := false
+		for i, p := range prices {
+			if p.Model == o.Model && p.EffectiveFrom == o.EffectiveFrom {
+				prices[i] = o
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			prices = append(prices, o)
+		}
+	}
+	return &PriceTable{prices: prices}
+}
+
+// PriceTableFor returns the price table configured for a town. A missing or
+// unreadable settings file yields the built-in table.
+func PriceTableFor(townRoot string) *PriceTable {
+	if townRoot == "" {
+		return NewPriceTable(nil)
+	}
+	ts, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return NewPriceTable(nil)
+	}
+	return NewPriceTable(ts.Pricing)
+}
+
+// Lookup returns the price for model at time at. An exact model match beats
+// a prefix pattern, and a longer prefix beats a shorter one. Among entries for
+// the same pattern, the latest one effective on or before at wins.
+func (t *PriceTable) Lookup(model string, at time.Time) config.ModelPrice {
+	day := at.Format("2006-01-02")
+	best := -1
+	bestRank := -1
+	for i, p := range t.prices {
+		rank, ok := matchModel(p.Model, model)
+		if !ok || (p.EffectiveFrom != "" && p.EffectiveFrom > day) {
+			continue
+		}
+		if rank > bestRank || (rank == bestRank && p.EffectiveFrom > t.prices[best].EffectiveFrom) {
+			best, bestRank = i, rank
+		}
+	}
+	if best < 0 {
+		return config.ModelPrice{Model: model}
+	}
+	return t.prices[best]
+}
+
+// matchModel reports whether pattern matches model and how specific the
+// match is. Exact matches rank above every prefix match.
+func matchModel(pattern, model string) (int, bool) {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		if strings.HasPrefix(model, prefix) {
+			return len(prefix), true
+		}
+		return 0, false
+	}
+	if pattern == model {
+		return int(^uint(0) >> 1), true
+	}
+	return 0, false
+}
+
+// Cost prices usage at time at, returning the total and the per-model split.
+func (t *PriceTable) Cost(u *Usage, at time.Time) (float64, map[string]float64) {
+	if u == nil {
+		return 0, nil
+	}
+	var total float64
+	byModel := make(map[string]float64, len(u.Models))
+	for model, tok := range u.Models {
+		p := t.Lookup(model, at)
+		c := (float64(tok.Input)*p.Input +
+			float64(tok.Output)*p.Output +
+			float64(tok.CacheRead)*p.CacheRead +
+			float64(tok.CacheWrite)*p.CacheWrite) / 1_000_000
+		byModel[model] = c
+		total += c
+	}
+	return total, byModel
+}
+
+// Prices returns the merged table sorted by model and effective date.
+func (t *PriceTable) Prices() []config.ModelPrice {
+	out := append([]config.ModelPrice(nil), t.prices...)
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Model != out[j].Model {
+			return out[i].Model < out[j].Model
+		}
+		return out[i].EffectiveFrom < out[j].EffectiveFrom
+	})
+	return out
+}
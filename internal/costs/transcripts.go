@@ -0,0 +1,449 @@
+package costs
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// newLineScanner returns a scanner sized for large transcript lines.
+func newLineScanner(f *os.File) *bufio.Scanner {
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 256*1024), 16*1024*1024)
+	return sc
+}
+
+// latestFile returns the most recently modified file in dir whose name
+// matches the glob pattern.
+func latestFile(dir, pattern string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, pattern))
+	if err != nil {
+		return "", err
+	}
+	var latest string
+	var latestTime time.Time
+	for _, m := range matches {
+		info, err := os.Stat(m)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if info.ModTime().After(latestTime) {
+			latest, latestTime = m, info.ModTime()
+		}
+	}
+	if latest == "" {
+		return "", fmt.Errorf("no transcript files found in %s", dir)
+	}
+	return latest, nil
+}
+
+// Claude Code stores transcripts in ~/.claude/projects/<workdir with / as ->/<session>.jsonl.
+
+type claudeLine struct {
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	RequestID string    `json:"requestId"`
+	Message   *struct {
+		Model string `json:"model"`
+		Usage *struct {
+			InputTokens              int `json:"input_tokens"`
+			CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+			CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+			OutputTokens             int `json:"output_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+// ClaudeProjectDir returns the Claude Code transcript directory for workDir.
+func ClaudeProjectDir(workDir string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".claude", "projects", strings.ReplaceAll(workDir, "/", "-")), nil
+}
+
+func parseClaude(workDir string) (*Usage, error) {
+	dir, err := ClaudeProjectDir(workDir)
+	if err != nil {
+		return nil, err
+	}
+	path, err := latestFile(dir, "*.jsonl")
+	if err != nil {
+		return nil, err
+	}
+	return parseClaudeTranscript(path)
+}
+
+// parseClaudeTranscript sums usage over the transcript's requests.
+func parseClaudeTranscript(path string) (*Usage, error) {
+	reqs, err := claudeTranscriptRequests(path, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	usage := newUsage("claude")
+	for _, r := range reqs {
+		usage.add(r.Model, r.Tokens)
+	}
+	return usage, nil
+}
+
+// Request is one model request read from a transcript.
+type Request struct {
+	ID     string
+	At     time.Time
+	Model  string
+	Tokens Tokens
+}
+
+// ClaudeRequests returns the requests in every Claude Code transcript under
+// configDir (e.g. ~/.claude or an account's CLAUDE_CONFIG_DIR) made after
+// since. Files not modified since then are skipped.
+func ClaudeRequests(configDir string, since time.Time) ([]Request, error) {
+	files, err := filepath.Glob(filepath.Join(configDir, "projects", "*", "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	var out []Request
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil || !info.ModTime().After(since) {
+			continue
+		}
+		reqs, err := claudeTranscriptRequests(path, since)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, reqs...)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].At.Before(out[j].At) })
+	return out, nil
+}
+
+// claudeTranscriptRequests reads the requests in one transcript made after
+// since (all of them for the zero time). Claude Code writes one assistant
+// line per content block, each repeating the request's usage, so only the
+// first line of each request ID counts.
+func claudeTranscriptRequests(path string, since time.Time) ([]Request, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var out []Request
+	seen := make(map[string]bool)
+	sc := newLineScanner(f)
+	for sc.Scan() {
+		var line claudeLine
+		if err := json.Unmarshal(sc.Bytes(), &line); err != nil {
+			continue
+		}
+		if line.Type != "assistant" || line.Message == nil || line.Message.Usage == nil {
+			continue
+		}
+		if !since.IsZero() && !line.Timestamp.After(since) {
+			continue
+		}
+		if line.RequestID != "" {
+			if seen[line.RequestID] {
+				continue
+			}
+			seen[line.RequestID] = true
+		}
+		u := line.Message.Usage
+		out = append(out, Request{
+			ID:    line.RequestID,
+			At:    line.Timestamp,
+			Model: line.Message.Model,
+			Tokens: Tokens{
+				Input:      u.InputTokens,
+				Output:     u.OutputTokens,
+				CacheRead:  u.CacheReadInputTokens,
+				CacheWrite: u.CacheCreationInputTokens,
+			},
+		})
+	}
+	return out, sc.Err()
+}
+
+// Gemini CLI stores one JSON file per session in
+// ~/.gemini/tmp/<sha256 of project root>/chats/session-*.json.
+
+type geminiSession struct {
+	Messages []struct {
+		Type   string `json:"type"`
+		Model  string `json:"model"`
+		Tokens *struct {
+			Input    int `json:"input"`
+			Output   int `json:"output"`
+			Cached   int `json:"cached"`
+			Thoughts int `json:"thoughts"`
+		} `json:"tokens"`
+	} `json:"messages"`
+}
+
+func parseGemini(workDir string) (*Usage, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256([]byte(workDir))
+	dir := filepath.Join(home, ".gemini", "tmp", hex.EncodeToString(sum[:]), "chats")
+	path, err := latestFile(dir, "session-*.json")
+	if err != nil {
+		return nil, err
+	}
+	return parseGeminiSession(path)
+}
+
+// parseGeminiSession sums usage from model replies. Gemini reports cached
+// tokens as part of input and thinking tokens apart from output.
+func parseGeminiSession(path string) (*Usage, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var sess geminiSession
+	if err := json.Unmarshal(data, &sess); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	usage := newUsage("gemini")
+	for _, m := range sess.Messages {
+		if m.Type != "gemini" || m.Tokens == nil {
+			continue
+		}
+		usage.add(m.Model, Tokens{
+			Input:     max(m.Tokens.Input-m.Tokens.Cached, 0),
+			Output:    m.Tokens.Output + m.Tokens.Thoughts,
+			CacheRead: m.Tokens.Cached,
+		})
+	}
+	return usage, nil
+}
+
+// Codex stores rollouts in $CODEX_HOME/sessions/YYYY/MM/DD/rollout-*.jsonl
+// (CODEX_HOME defaults to ~/.codex). The first line records the session cwd.
+
+type codexLine struct {
+	Type    string `json:"type"`
+	Payload struct {
+		Type  string `json:"type"`
+		CWD   string `json:"cwd"`
+		Model string `json:"model"`
+		Info  *struct {
+			Total codexTokens `json:"total_token_usage"`
+		} `json:"info"`
+	} `json:"payload"`
+}
+
+type codexTokens struct {
+	InputTokens       int `json:"input_tokens"`
+	CachedInputTokens int `json:"cached_input_tokens"`
+	OutputTokens      int `json:"output_tokens"`
+}
+
+func codexHome() (string, error) {
+	if dir := os.Getenv("CODEX_HOME"); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".codex"), nil
+}
+
+func parseCodex(workDir string) (*Usage, error) {
+	home, err := codexHome()
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(home, "sessions")
+	type rollout struct {
+		path string
+		mod  time.Time
+	}
+	var rollouts []rollout
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasPrefix(d.Name(), "rollout-") || !strings.HasSuffix(d.Name(), ".jsonl") {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			rollouts = append(rollouts, rollout{path, info.ModTime()})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(rollouts, func(i, j int) bool { return rollouts[i].mod.After(rollouts[j].mod) })
+	for _, r := range rollouts {
+		if codexRolloutCWD(r.path) == workDir {
+			return parseCodexRollout(r.path)
+		}
+	}
+	return nil, fmt.Errorf("no codex rollout found for %s in %s", workDir, dir)
+}
+
+// codexRolloutCWD returns the working directory from a rollout's session_meta line.
+func codexRolloutCWD(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	sc := newLineScanner(f)
+	if !sc.Scan() {
+		return ""
+	}
+	var line codexLine
+	if json.Unmarshal(sc.Bytes(), &line) != nil || line.Type != "session_meta" {
+		return ""
+	}
+	return line.Payload.CWD
+}
+
+// parseCodexRollout attributes token usage to models. Codex reports running
+// totals, so each token_count event is charged the growth since the last one
+// to the model from the most recent turn_context.
+func parseCodexRollout(path string) (*Usage, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	usage := newUsage("codex")
+	var model string
+	var prev codexTokens
+	sc := newLineScanner(f)
+	for sc.Scan() {
+		var line codexLine
+		if err := json.Unmarshal(sc.Bytes(), &line); err != nil {
+			continue
+		}
+		switch {
+		case line.Type == "turn_context":
+			if line.Payload.Model != "" {
+				model = line.Payload.Model
+			}
+		case line.Type == "event_msg" && line.Payload.Type == "token_count" && line.Payload.Info != nil:
+			cur := line.Payload.Info.Total
+			if cur.InputTokens < prev.InputTokens {
+				prev = codexTokens{} // totals reset
+			}
+			cached := cur.CachedInputTokens - prev.CachedInputTokens
+			usage.add(model, Tokens{
+				Input:     max(cur.InputTokens-prev.InputTokens-cached, 0),
+				Output:    cur.OutputTokens - prev.OutputTokens,
+				CacheRead: cached,
+			})
+			prev = cur
+		}
+	}
+	return usage, sc.Err()
+}
+
+// OpenCode stores sessions under $XDG_DATA_HOME/opencode/storage
+// (default ~/.local/share): session/<project>/<id>.json holds the directory
+// and message/<id>/*.json holds one file per message.
+
+type openCodeSession struct {
+	ID        string `json:"id"`
+	Directory string `json:"directory"`
+	Time      struct {
+		Updated int64 `json:"updated"`
+	} `json:"time"`
+}
+
+type openCodeMessage struct {
+	Role    string `json:"role"`
+	ModelID string `json:"modelID"`
+	Tokens  *struct {
+		Input     int `json:"input"`
+		Output    int `json:"output"`
+		Reasoning int `json:"reasoning"`
+		Cache     struct {
+			Read  int `json:"read"`
+			Write int `json:"write"`
+		} `json:"cache"`
+	} `json:"tokens"`
+}
+
+func openCodeStorage() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "opencode", "storage"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", "opencode", "storage"), nil
+}
+
+func parseOpenCode(workDir string) (*Usage, error) {
+	storage, err := openCodeStorage()
+	if err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(storage, "session", "*", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var latest *openCodeSession
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var s openCodeSession
+		if json.Unmarshal(data, &s) != nil || s.Directory != workDir || s.ID == "" {
+			continue
+		}
+		if latest == nil || s.Time.Updated > latest.Time.Updated {
+			latest = &s
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("no opencode session found for %s in %s", workDir, storage)
+	}
+	return parseOpenCodeMessages(filepath.Join(storage, "message", latest.ID))
+}
+
+// parseOpenCodeMessages sums usage from the assistant messages in dir.
+func parseOpenCodeMessages(dir string) (*Usage, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	usage := newUsage("opencode")
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var m openCodeMessage
+		if json.Unmarshal(data, &m) != nil || m.Role != "assistant" || m.Tokens == nil {
+			continue
+		}
+		usage.add(m.ModelID, Tokens{
+			Input:      m.Tokens.Input,
+			Output:     m.Tokens.Output + m.Tokens.Reasoning,
+			CacheRead:  m.Tokens.Cache.Read,
+			CacheWrite: m.Tokens.Cache.Write,
+		})
+	}
+	return usage, nil
+}
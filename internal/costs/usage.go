@@ -0,0 +1,87 @@
+package costs
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Tokens counts the tokens billed for one model. Input excludes cached
+// input, which is counted in CacheRead.
+type Tokens struct {
+	Input      int `json:"input"`
+	Output     int `json:"output"`
+	CacheRead  int `json:"cache_read,omitempty"`
+	CacheWrite int `json:"cache_write,omitempty"`
+}
+
+// Usage is the token usage of one agent session, split by model.
+type Usage struct {
+	Agent  string
+	Models map[string]*Tokens
+}
+
+func newUsage(agent string) *Usage {
+	return &Usage{Agent: agent, Models: make(map[string]*Tokens)}
+}
+
+func (u *Usage) add(model string, t Tokens) {
+	if model == "" {
+		model = "unknown"
+	}
+	cur, ok := u.Models[model]
+	if !ok {
+		cur = &Tokens{}
+		u.Models[model] = cur
+	}
+	cur.Input += t.Input
+	cur.Output += t.Output
+	cur.CacheRead += t.CacheRead
+	cur.CacheWrite += t.CacheWrite
+}
+
+// Model returns the session's main model: the one with the most output
+// tokens, ties broken by name.
+func (u *Usage) Model() string {
+	if u == nil {
+		return ""
+	}
+	names := make([]string, 0, len(u.Models))
+	for m := range u.Models {
+		names = append(names, m)
+	}
+	sort.Strings(names)
+	best := ""
+	for _, m := range names {
+		if best == "" || u.Models[m].Output > u.Models[best].Output {
+			best = m
+		}
+	}
+	return best
+}
+
+// Parser finds the most recent session transcript an agent wrote for
+// workDir and sums its token usage.
+type Parser func(workDir string) (*Usage, error)
+
+// parsers maps agent presets to their transcript parsers.
+var parsers = map[string]Parser{
+	"claude":   parseClaude,
+	"gemini":   parseGemini,
+	"codex":    parseCodex,
+	"opencode": parseOpenCode,
+}
+
+// ParserFor returns the transcript parser for an agent preset.
+func ParserFor(preset string) (Parser, bool) {
+	p, ok := parsers[preset]
+	return p, ok
+}
+
+// SessionUsage reads the latest session usage for an agent preset in workDir.
+func SessionUsage(preset, workDir string) (*Usage, error) {
+	p, ok := ParserFor(preset)
+	if !ok {
+		return nil, fmt.Errorf("no usage parser for agent %q", preset)
+	}
+	return p(workDir)
+}
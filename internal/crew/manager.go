@@ -645,6 +645,11 @@ func (m *Manager) setupSharedBeads(crewPath string) error {
 	return beads.SetupRedirect(townRoot, crewPath)
 }
 
+// backend returns the session backend for the crew's town.
+func (m *Manager) backend() session.Backend {
+	return session.NewBackend(filepath.Dir(m.rig.Path))
+}
+
 // SessionName returns the tmux session name for a crew member.
 func (m *Manager) SessionName(name string) string {
 	return session.CrewSessionName(session.PrefixFor(m.rig.Name), name)
@@ -754,7 +759,7 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 		}
 	}
 
-	t := tmux.NewTmux()
+	t := m.backend()
 	sessionID := m.SessionName(name)
 
 	// Check if session already exists — kill AFTER command is fully built
@@ -821,7 +826,7 @@ func (m *Manager) Stop(name string) error {
 		return err
 	}
 
-	t := tmux.NewTmux()
+	t := m.backend()
 	sessionID := m.SessionName(name)
 
 	// Check if session exists
@@ -845,7 +850,7 @@ func (m *Manager) Stop(name string) error {
 
 // IsRunning checks if a crew member's session is active.
 func (m *Manager) IsRunning(name string) (bool, error) {
-	t := tmux.NewTmux()
+	t := m.backend()
 	sessionID := m.SessionName(name)
 	return t.HasSession(sessionID)
 }
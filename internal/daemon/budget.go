@@ -0,0 +1,266 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/costs"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// enforceBudgets evaluates budget rules against today's costs ledger and
+// acts once on each newly crossed limit. Breaches are kept in the budget
+// state file, which gt sling and the polecat health check read for holds.
+func (d *Daemon) enforceBudgets() {
+	townRoot := d.config.TownRoot
+	rules, errs := costs.LoadBudgetRules(townRoot)
+	for _, err := range errs {
+		d.logger.Printf("Budget: %v", err)
+	}
+
+	now := time.Now()
+	state, err := costs.LoadBudgetState(townRoot, now)
+	if err != nil {
+		d.logger.Printf("Budget: reading state: %v (starting fresh)", err)
+	}
+	d.budgetState = state
+	if len(rules) == 0 && len(state.Breaches) == 0 && len(state.CostTiers) == 0 {
+		return
+	}
+
+	entries, err := costs.ReadLedger(costs.LedgerPath())
+	if err != nil {
+		d.logger.Printf("Budget: reading costs ledger: %v", err)
+		return
+	}
+
+	previous := make(map[string]costs.Breach, len(state.Breaches))
+	for _, b := range state.Breaches {
+		previous[b.Key] = b
+	}
+	var breaches []costs.Breach
+	for _, spend := range costs.EvaluateBudgets(rules, entries, now) {
+		b, ok := costs.BreachFor(spend, now)
+		if !ok {
+			continue
+		}
+		// A warning that turns into a breach is acted on again.
+		if old, ok := previous[b.Key]; ok && old.Warning == b.Warning {
+			b.At, b.Handled = old.At, old.Handled
+		}
+		if !b.Handled && !state.IsCleared(b.Key) {
+			d.actOnBudgetBreach(b)
+			b.Handled = true
+		}
+		breaches = append(breaches, b)
+	}
+
+	state.Breaches = breaches
+	d.restoreBudgetCostTiers(state)
+	state.UpdatedAt = now
+	if err := costs.SaveBudgetState(townRoot, state); err != nil {
+		d.logger.Printf("Budget: saving state: %v", err)
+	}
+}
+
+// actOnBudgetBreach takes a breach's configured actions. stop_sling needs
+// no work here: gt sling reads the hold from the budget state.
+func (d *Daemon) actOnBudgetBreach(b costs.Breach) {
+	what := "over budget"
+	if b.Warning {
+		what = "nearing budget"
+	}
+	d.logger.Printf("Budget %s: %s $%.2f of $%.2f (actions: %s)",
+		b.Key, what, b.SpentUSD, b.LimitUSD, strings.Join(b.Actions, ", "))
+
+	var done []string
+	if b.HasAction(config.BudgetActionCostTier) {
+		if where, err := d.applyBudgetCostTier(b); err != nil {
+			d.logger.Printf("Budget %s: applying cost tier %s: %v", b.Key, b.CostTier, err)
+		} else {
+			done = append(done, "switched "+where+" role_agents to the "+b.CostTier+" cost tier")
+		}
+	}
+	if b.HasAction(config.BudgetActionPausePolecats) {
+		if n := d.pauseBudgetPolecats(b); n > 0 {
+			done = append(done, fmt.Sprintf("paused %d polecat session(s)", n))
+		}
+	}
+	if b.HasAction(config.BudgetActionStopSling) {
+		done = append(done, "stopped new gt sling work")
+	}
+	if b.HasAction(config.BudgetActionEscalate) {
+		d.escalateBudget(b, done)
+	}
+}
+
+// applyBudgetCostTier switches role_agents to the breach's cheaper tier and
+// returns where it did so. A town breach changes the town's role_agents;
+// any other breach changes only the rig settings of the rigs it covers, so
+// one rig's overspend doesn't downgrade the whole town. New sessions pick
+// the tier up; running sessions keep their agent. The settings it replaces
+// are held in the budget state for restoreBudgetCostTiers.
+func (d *Daemon) applyBudgetCostTier(b costs.Breach) (string, error) {
+	tier := config.CostTier(b.CostTier)
+	if b.Scope == config.BudgetScopeTown || len(b.Rigs) == 0 {
+		path := config.TownSettingsPath(d.config.TownRoot)
+		ts, err := config.LoadOrCreateTownSettings(path)
+		if err != nil {
+			return "", err
+		}
+		d.budgetState.HoldCostTier("", b.Key, config.SnapshotCostTier(ts))
+		if ts.CostTier == string(tier) {
+			return "town", nil
+		}
+		if err := config.ApplyCostTier(ts, tier); err != nil {
+			return "", err
+		}
+		return "town", config.SaveTownSettings(path, ts)
+	}
+
+	for _, rigName := range b.Rigs {
+		path := config.RigSettingsPath(filepath.Join(d.config.TownRoot, rigName))
+		rs, err := config.LoadRigSettings(path)
+		if errors.Is(err, config.ErrNotFound) {
+			rs, err = config.NewRigSettings(), nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", rigName, err)
+		}
+		d.budgetState.HoldCostTier(rigName, b.Key, config.SnapshotRigCostTier(rs))
+		if err := config.ApplyCostTierToRig(rs, tier); err != nil {
+			return "", err
+		}
+		if err := config.SaveRigSettings(path, rs); err != nil {
+			return "", fmt.Errorf("%s: %w", rigName, err)
+		}
+	}
+	return strings.Join(b.Rigs, ", "), nil
+}
+
+// restoreBudgetCostTiers puts back the role_agents and agents a cost_tier
+// action replaced once no breach holding them is active: it was cleared,
+// its rule changed, or the day ended. Settings that can't be written stay
+// held and are tried again next heartbeat.
+func (d *Daemon) restoreBudgetCostTiers(state *costs.BudgetState) {
+	for _, h := range state.ReleaseCostTiers() {
+		where := "town"
+		var err error
+		if h.Rig == "" {
+			path := config.TownSettingsPath(d.config.TownRoot)
+			var ts *config.TownSettings
+			if ts, err = config.LoadOrCreateTownSettings(path); err == nil {
+				config.RestoreCostTier(ts, h.Snapshot)
+				err = config.SaveTownSettings(path, ts)
+			}
+		} else {
+			where = h.Rig
+			path := config.RigSettingsPath(filepath.Join(d.config.TownRoot, h.Rig))
+			var rs *config.RigSettings
+			rs, err = config.LoadRigSettings(path)
+			if errors.Is(err, config.ErrNotFound) {
+				continue // the rig's settings are gone; nothing to put back
+			}
+			if err == nil {
+				config.RestoreRigCostTier(rs, h.Snapshot)
+				err = config.SaveRigSettings(path, rs)
+			}
+		}
+		if err != nil {
+			d.logger.Printf("Budget: restoring %s role_agents: %v", where, err)
+			state.CostTiers = append(state.CostTiers, h)
+			continue
+		}
+		d.logger.Printf("Budget: restored %s role_agents from before the cost tier switch", where)
+	}
+}
+
+// pauseBudgetPolecats stops the polecat sessions a breach covers and
+// returns how many were stopped. Their hooked work stays; the health check
+// restarts them once the hold lifts.
+func (d *Daemon) pauseBudgetPolecats(b costs.Breach) int {
+	var sessions []string
+	if b.Scope == config.BudgetScopeSession {
+		sessions = append(sessions, b.Subject)
+	} else {
+		rigs := b.Rigs
+		if b.Scope == config.BudgetScopeTown {
+			rigs = d.getKnownRigs()
+		}
+		for _, rigName := range rigs {
+			polecats, _ := listPolecatWorktrees(filepath.Join(d.config.TownRoot, rigName, "polecats"))
+			for _, name := range polecats {
+				sessions = append(sessions, session.PolecatSessionName(session.PrefixFor(rigName), name))
+			}
+		}
+	}
+
+	stopped := 0
+	for _, name := range sessions {
+		if alive, _ := d.tmux.HasSession(name); !alive {
+			continue
+		}
+		if err := d.tmux.KillSessionWithProcesses(name); err != nil {
+			d.logger.Printf("Budget %s: stopping %s: %v", b.Key, name, err)
+			continue
+		}
+		stopped++
+	}
+	return stopped
+}
+
+// escalateBudget files an escalation for a breach. Runs asynchronously to
+// avoid blocking the heartbeat.
+func (d *Daemon) escalateBudget(b costs.Breach, done []string) {
+	if d.escalateBudgetFn != nil {
+		d.escalateBudgetFn(b, done)
+		return
+	}
+
+	severity := "high"
+	title := fmt.Sprintf("Budget %s exceeded: $%.2f of $%.2f today", b.Rule, b.SpentUSD, b.LimitUSD)
+	if b.Warning {
+		severity = "medium"
+		title = fmt.Sprintf("Budget %s at %.0f%%: $%.2f of $%.2f today", b.Rule, 100*b.SpentUSD/b.LimitUSD, b.SpentUSD, b.LimitUSD)
+	}
+	reason := fmt.Sprintf("Scope: %s", b.Scope)
+	if b.Subject != "" {
+		reason += " " + b.Subject
+	}
+	if len(b.Rigs) > 0 {
+		reason += fmt.Sprintf(" (rigs: %s)", strings.Join(b.Rigs, ", "))
+	}
+	if len(done) > 0 {
+		reason += ". Actions taken: " + strings.Join(done, "; ")
+	}
+	reason += ". Review with 'gt costs budget'; lift holds with 'gt costs budget clear " + b.Key + "'."
+
+	gtPath := d.gtPath
+	if gtPath == "" {
+		gtPath = "gt"
+	}
+	townRoot := d.config.TownRoot
+	logger := d.logger
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, gtPath, "escalate", title, "--severity", severity, "--reason", reason, "--source", "budget:"+b.Rule) //nolint:gosec // G204: args are constructed internally
+		cmd.Dir = townRoot
+		cmd.Env = os.Environ()
+		if out, err := cmd.CombinedOutput(); err != nil {
+			logger.Printf("Budget %s: escalation failed: %v: %s", b.Key, err, strings.TrimSpace(string(out)))
+		}
+	}()
+}
+
+// isBudgetPaused reports whether a polecat session is paused by a budget.
+func (d *Daemon) isBudgetPaused(rigName, sessionName string) bool {
+	return d.budgetState != nil && d.budgetState.PolecatPause(rigName, sessionName) != nil
+}
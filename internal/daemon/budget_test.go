@@ -0,0 +1,236 @@
+package daemon
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/costs"
+)
+
+// setupBudgetTown writes town settings with the given budgets and a costs
+// ledger with the given entries under a temporary HOME.
+func setupBudgetTown(t *testing.T, budgets []config.BudgetRule, entries []costs.LedgerEntry) *Daemon {
+	t.Helper()
+	townRoot := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+
+	ts := config.NewTownSettings()
+	ts.Budgets = budgets
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), ts); err != nil {
+		t.Fatalf("saving town settings: %v", err)
+	}
+	writeBudgetLedger(t, entries)
+
+	return &Daemon{
+		config: &Config{TownRoot: townRoot},
+		logger: log.New(io.Discard, "", 0),
+	}
+}
+
+func writeBudgetLedger(t *testing.T, entries []costs.LedgerEntry) {
+	t.Helper()
+	path := costs.LedgerPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	enc := json.NewEncoder(f)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestEnforceBudgets_ActsOncePerBreach(t *testing.T) {
+	now := time.Now()
+	d := setupBudgetTown(t, []config.BudgetRule{{
+		Scope:    config.BudgetScopeTown,
+		LimitUSD: 10,
+		WarnAt:   0.5,
+		Actions:  []string{config.BudgetActionEscalate, config.BudgetActionStopSling, config.BudgetActionCostTier},
+		CostTier: string(config.TierEconomy),
+	}}, []costs.LedgerEntry{
+		{SessionID: "gt-gastown-toast", Role: "polecat", Rig: "gastown", CostUSD: 6, EndedAt: now},
+	})
+	var escalated []costs.Breach
+	d.escalateBudgetFn = func(b costs.Breach, done []string) { escalated = append(escalated, b) }
+
+	// Over warn_at: a warning escalation, no holds.
+	d.enforceBudgets()
+	d.enforceBudgets()
+	if len(escalated) != 1 || !escalated[0].Warning {
+		t.Fatalf("escalations after warning = %+v, want one warning", escalated)
+	}
+	if d.budgetState.SlingHold("gastown", "") != nil {
+		t.Error("a warning should not stop slinging")
+	}
+
+	// Over the limit: acts again, once.
+	writeBudgetLedger(t, []costs.LedgerEntry{
+		{SessionID: "gt-gastown-toast", Role: "polecat", Rig: "gastown", CostUSD: 6, EndedAt: now},
+		{SessionID: "gt-gastown-toast", Role: "polecat", Rig: "gastown", CostUSD: 11, EndedAt: now},
+	})
+	d.enforceBudgets()
+	d.enforceBudgets()
+	if len(escalated) != 2 || escalated[1].Warning {
+		t.Fatalf("escalations after breach = %+v, want a second, non-warning one", escalated)
+	}
+
+	state, err := costs.LoadBudgetState(d.config.TownRoot, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.SlingHold("gastown", "") == nil {
+		t.Error("saved state should stop slinging to gastown")
+	}
+	ts, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(d.config.TownRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts.CostTier != string(config.TierEconomy) {
+		t.Errorf("cost tier = %q, want %q", ts.CostTier, config.TierEconomy)
+	}
+}
+
+func TestEnforceBudgets_SkipsClearedBreach(t *testing.T) {
+	now := time.Now()
+	d := setupBudgetTown(t, []config.BudgetRule{{LimitUSD: 5}}, []costs.LedgerEntry{
+		{SessionID: "gt-gastown-toast", Role: "polecat", Rig: "gastown", CostUSD: 6, EndedAt: now},
+	})
+	if err := costs.SaveBudgetState(d.config.TownRoot, &costs.BudgetState{
+		Day:     now.Format("2006-01-02"),
+		Cleared: []string{"rig-5:gastown"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	escalations := 0
+	d.escalateBudgetFn = func(costs.Breach, []string) { escalations++ }
+
+	d.enforceBudgets()
+	if escalations != 0 {
+		t.Errorf("escalations = %d, want 0 for a cleared breach", escalations)
+	}
+}
+
+func TestEnforceBudgets_RigCostTierStaysInRig(t *testing.T) {
+	now := time.Now()
+	d := setupBudgetTown(t, []config.BudgetRule{{
+		Scope:    config.BudgetScopeRig,
+		LimitUSD: 10,
+		Actions:  []string{config.BudgetActionCostTier},
+		CostTier: string(config.TierBudget),
+	}}, []costs.LedgerEntry{
+		{SessionID: "gt-gastown-toast", Role: "polecat", Rig: "gastown", CostUSD: 11, EndedAt: now},
+		{SessionID: "bd-beads-nux", Role: "polecat", Rig: "beads", CostUSD: 1, EndedAt: now},
+	})
+
+	d.enforceBudgets()
+
+	ts, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(d.config.TownRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts.CostTier != "" || len(ts.RoleAgents) != 0 {
+		t.Errorf("town settings changed by a rig breach: tier %q, role_agents %v", ts.CostTier, ts.RoleAgents)
+	}
+	rs, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(d.config.TownRoot, "gastown")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs.RoleAgents["polecat"] != "claude-sonnet" || rs.RoleAgents["witness"] != "claude-haiku" || rs.Agents["claude-haiku"] == nil {
+		t.Errorf("gastown settings = role_agents %v, agents %v", rs.RoleAgents, rs.Agents)
+	}
+	if _, ok := rs.RoleAgents["mayor"]; ok {
+		t.Error("town-level roles belong in town settings")
+	}
+	if _, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(d.config.TownRoot, "beads"))); err == nil {
+		t.Error("a rig under budget should be left alone")
+	}
+}
+
+func TestEnforceBudgets_RestoresCostTierWhenCleared(t *testing.T) {
+	now := time.Now()
+	d := setupBudgetTown(t, []config.BudgetRule{{
+		Scope:    config.BudgetScopeTown,
+		LimitUSD: 10,
+		Actions:  []string{config.BudgetActionCostTier},
+		CostTier: string(config.TierBudget),
+	}, {
+		Scope:    config.BudgetScopeRig,
+		LimitUSD: 10,
+		Actions:  []string{config.BudgetActionCostTier},
+		CostTier: string(config.TierEconomy),
+	}}, []costs.LedgerEntry{
+		{SessionID: "gt-gastown-toast", Role: "polecat", Rig: "gastown", CostUSD: 11, EndedAt: now},
+	})
+	townPath := config.TownSettingsPath(d.config.TownRoot)
+	ts, err := config.LoadOrCreateTownSettings(townPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts.RoleAgents = map[string]string{"witness": "gemini"}
+	if err := config.SaveTownSettings(townPath, ts); err != nil {
+		t.Fatal(err)
+	}
+	rigPath := config.RigSettingsPath(filepath.Join(d.config.TownRoot, "gastown"))
+
+	d.enforceBudgets()
+	if ts, _ = config.LoadOrCreateTownSettings(townPath); ts.RoleAgents["witness"] != "claude-haiku" {
+		t.Fatalf("town role_agents after breach = %v, want the budget tier", ts.RoleAgents)
+	}
+	if rs, _ := config.LoadRigSettings(rigPath); rs == nil || rs.RoleAgents["witness"] != "claude-sonnet" {
+		t.Fatalf("gastown settings after breach = %+v, want the economy tier", rs)
+	}
+
+	// Clearing the town breach puts the town's role_agents back; the rig
+	// stays switched while its breach is active.
+	state, err := costs.LoadBudgetState(d.config.TownRoot, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.Cleared = append(state.Cleared, "town-10")
+	if err := costs.SaveBudgetState(d.config.TownRoot, state); err != nil {
+		t.Fatal(err)
+	}
+	d.enforceBudgets()
+	ts, err = config.LoadOrCreateTownSettings(townPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts.CostTier != "" || ts.RoleAgents["witness"] != "gemini" || ts.RoleAgents["polecat"] != "" {
+		t.Errorf("town after clear = tier %q, role_agents %v, want the original", ts.CostTier, ts.RoleAgents)
+	}
+	if rs, _ := config.LoadRigSettings(rigPath); rs == nil || rs.RoleAgents["witness"] != "claude-sonnet" {
+		t.Errorf("gastown settings after town clear = %+v, want still switched", rs)
+	}
+
+	// The next day the rig's breach is over too.
+	state, err = costs.LoadBudgetState(d.config.TownRoot, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.Day = now.Add(-24 * time.Hour).Format("2006-01-02")
+	if err := costs.SaveBudgetState(d.config.TownRoot, state); err != nil {
+		t.Fatal(err)
+	}
+	writeBudgetLedger(t, nil)
+	d.enforceBudgets()
+	rs, err := config.LoadRigSettings(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs.RoleAgents) != 0 || len(rs.Agents) != 0 {
+		t.Errorf("gastown settings the next day = role_agents %v, agents %v, want none", rs.RoleAgents, rs.Agents)
+	}
+}
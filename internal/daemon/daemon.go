@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -21,18 +22,23 @@ import (
 	"github.com/steveyegge/gastown/internal/boot"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/costs"
 	"github.com/steveyegge/gastown/internal/deacon"
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/feed"
 	gitpkg "github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mayor"
+	"github.com/steveyegge/gastown/internal/plugin"
 	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/pty"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/util"
+	"github.com/steveyegge/gastown/internal/webhooks"
 	"github.com/steveyegge/gastown/internal/wisp"
 	"github.com/steveyegge/gastown/internal/witness"
 )
@@ -44,7 +50,7 @@ import (
 type Daemon struct {
 	config        *Config
 	patrolConfig  *DaemonPatrolConfig
-	tmux          *tmux.Tmux
+	tmux          sessionOps
 	logger        *log.Logger
 	ctx           context.Context
 	cancel        context.CancelFunc
@@ -53,6 +59,17 @@ type Daemon struct {
 	beadsStores   map[string]beadsdk.Storage
 	doltServer    *DoltServerManager
 	krcPruner     *KRCPruner
+	pluginEvents  *plugin.EventBus
+
+	// Headless session supervisor, hosted when session_backend is "pty".
+	ptySupervisor *pty.Supervisor
+	ptyServer     *pty.Server
+
+	// Prometheus endpoint, served when telemetry.listen is set.
+	metricsServer *http.Server
+
+	// Delivers town events to the webhooks in town settings.
+	webhooks *webhooks.Dispatcher
 
 	// Mass death detection: track recent session deaths
 	deathsMu     sync.Mutex
@@ -75,6 +92,22 @@ type Daemon struct {
 
 	// Restart tracking with exponential backoff to prevent crash loops
 	restartTracker *RestartTracker
+
+	// budgetState is today's budget breaches, refreshed each heartbeat by
+	// enforceBudgets. Only accessed from heartbeat loop goroutine.
+	budgetState *costs.BudgetState
+	// escalateBudgetFn replaces the gt escalate call in tests.
+	escalateBudgetFn func(b costs.Breach, done []string)
+
+	// quotaRotating is held while a proactive gt quota rotate runs, so a
+	// slow rotation is not started twice.
+	quotaRotating sync.Mutex
+	// rotateQuotaFn replaces the gt quota rotate call in tests.
+	rotateQuotaFn func()
+
+	// pluginGatesRunning is held while a plugin gate pass runs, so slow
+	// condition checks never stall the heartbeat or overlap.
+	pluginGatesRunning sync.Mutex
 }
 
 // sessionDeath records a detected session death for mass death analysis.
@@ -152,7 +185,7 @@ func New(config *Config) (*Daemon, error) {
 	return &Daemon{
 		config:         config,
 		patrolConfig:   patrolConfig,
-		tmux:           tmux.NewTmux(),
+		tmux:           newSessionOps(config.TownRoot),
 		logger:         logger,
 		ctx:            ctx,
 		cancel:         cancel,
@@ -160,6 +193,7 @@ func New(config *Config) (*Daemon, error) {
 		gtPath:         gtPath,
 		bdPath:         bdPath,
 		restartTracker: restartTracker,
+		pluginEvents:   plugin.NewEventBus(config.TownRoot),
 	}, nil
 }
 
@@ -225,6 +259,19 @@ func (d *Daemon) Run() error {
 
 	d.logger.Printf("Daemon running, recovery heartbeat interval %v", recoveryHeartbeatInterval)
 
+	// Host the PTY supervisor before anything starts agent sessions.
+	if session.UsePTY(d.config.TownRoot) {
+		if err := d.startPTYSupervisor(); err != nil {
+			return fmt.Errorf("starting PTY supervisor: %w", err)
+		}
+		d.logger.Printf("PTY supervisor listening on %s", pty.SocketPath(d.config.TownRoot))
+	}
+
+	// Serve Prometheus metrics if telemetry.listen is configured
+	if err := d.startMetricsServer(); err != nil {
+		d.logger.Printf("Warning: failed to start metrics endpoint: %v", err)
+	}
+
 	// Start feed curator goroutine
 	d.curator = feed.NewCurator(d.config.TownRoot)
 	if err := d.curator.Start(); err != nil {
@@ -233,6 +280,10 @@ func (d *Daemon) Run() error {
 		d.logger.Println("Feed curator started")
 	}
 
+	// Deliver town events to configured webhooks
+	d.webhooks = webhooks.NewDispatcher(d.config.TownRoot, d.logger.Printf)
+	d.webhooks.Start()
+
 	// Start convoy manager (event-driven + periodic stranded scan)
 	// Try opening beads stores eagerly; if Dolt isn't ready yet,
 	// pass the opener as a callback for lazy retry on each poll tick.
@@ -295,6 +346,10 @@ func (d *Daemon) Run() error {
 	// Global pane-died hooks don't fire reliably in tmux 3.2a, so we rely on the
 	// per-session approach which has been tested to work for continuous recovery.
 
+	// Fire plugin event gates waiting on daemon startup. The initial
+	// heartbeat below evaluates them.
+	d.pluginEvents.Publish(plugin.EventStartup)
+
 	// Initial heartbeat
 	d.heartbeat(state)
 
@@ -430,6 +485,17 @@ func (d *Daemon) heartbeat(state *State) {
 	// 11. Check for orphaned work (assigned to dead agents)
 	d.checkOrphanedWork()
 
+	// 11a. Enforce cost budgets (escalate, hold slings, switch cost tier,
+	// pause polecats). Runs before the polecat health check so paused
+	// polecats are not restarted.
+	d.enforceBudgets()
+
+	// 11b. Forecast account quota usage; put accounts about to hit their
+	// limit in cooldown and rotate idle sessions off them.
+	if IsPatrolEnabled(d.patrolConfig, "quota") {
+		d.forecastQuota()
+	}
+
 	// 12. Check polecat session health (proactive crash detection)
 	// This validates tmux sessions are still alive for polecats with work-on-hook
 	d.checkPolecatSessionHealth()
@@ -445,6 +511,9 @@ func (d *Daemon) heartbeat(state *State) {
 	// branches persist indefinitely. This cleans them up periodically.
 	d.pruneStaleBranches()
 
+	// 14. Evaluate plugin gates and dispatch due plugins to dogs.
+	d.runPluginGates()
+
 	// Update state
 	state.LastHeartbeat = time.Now()
 	state.HeartbeatCount++
@@ -782,8 +851,7 @@ func (d *Daemon) ensureWitnessRunning(rigName string) {
 	// indicating Claude is stuck. Kill it so Start() can recreate a fresh one.
 	if status := mgr.IsHealthy(hungSessionThreshold); status == tmux.AgentHung {
 		d.logger.Printf("Witness for %s is hung (no activity for %v), killing for restart", rigName, hungSessionThreshold)
-		t := tmux.NewTmux()
-		_ = t.KillSessionWithProcesses(mgr.SessionName())
+		_ = d.tmux.KillSessionWithProcesses(mgr.SessionName())
 	}
 
 	if err := mgr.Start(false, "", nil); err != nil {
@@ -832,8 +900,7 @@ func (d *Daemon) ensureRefineryRunning(rigName string) {
 	// can recreate a fresh one. See: gt-tr3d
 	if status := mgr.IsHealthy(hungSessionThreshold); status == tmux.AgentHung {
 		d.logger.Printf("Refinery for %s is hung (no activity for %v), killing for restart", rigName, hungSessionThreshold)
-		t := tmux.NewTmux()
-		_ = t.KillSessionWithProcesses(mgr.SessionName())
+		_ = d.tmux.KillSessionWithProcesses(mgr.SessionName())
 	}
 
 	if err := mgr.Start(false, ""); err != nil {
@@ -1106,6 +1173,11 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 		d.logger.Println("Feed curator stopped")
 	}
 
+	// Stop webhook dispatcher
+	if d.webhooks != nil {
+		d.webhooks.Stop()
+	}
+
 	// Stop convoy manager (also closes beads stores)
 	if d.convoyManager != nil {
 		d.convoyManager.Stop()
@@ -1119,6 +1191,18 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 		d.logger.Println("KRC pruner stopped")
 	}
 
+	// Stop metrics endpoint
+	if d.metricsServer != nil {
+		d.stopMetricsServer()
+		d.logger.Println("Metrics endpoint stopped")
+	}
+
+	// Stop PTY sessions (they are children of the daemon)
+	if d.ptyServer != nil {
+		d.stopPTYSupervisor()
+		d.logger.Println("PTY supervisor stopped")
+	}
+
 	// Stop Dolt server if we're managing it
 	if d.doltServer != nil && d.doltServer.IsEnabled() && !d.doltServer.IsExternal() {
 		if err := d.doltServer.Stop(); err != nil {
@@ -1443,6 +1527,11 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 		return
 	}
 
+	// Paused by a cost budget: the session was stopped on purpose.
+	if d.isBudgetPaused(rigName, sessionName) {
+		return
+	}
+
 	// Session is dead. Check if the polecat has work-on-hook.
 	prefix := beads.GetPrefixForRig(d.config.TownRoot, rigName)
 	agentBeadID := beads.PolecatBeadIDWithPrefix(prefix, rigName, polecatName)
@@ -1500,6 +1589,10 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 	d.logger.Printf("CRASH DETECTED: polecat %s/%s has hook_bead=%s but session %s is dead",
 		rigName, polecatName, info.HookBead, sessionName)
 
+	agentID := rigName + "/polecats/" + polecatName
+	_ = events.LogFeed(events.TypeSessionDeath, agentID,
+		events.SessionDeathPayload(sessionName, agentID, "crashed with hooked work", "daemon"))
+
 	// Track this death for mass death detection
 	d.recordSessionDeath(sessionName)
 
@@ -1510,11 +1603,19 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 		d.notifyWitnessOfCrashedPolecat(rigName, polecatName, info.HookBead, err)
 	} else {
 		d.logger.Printf("Successfully restarted crashed polecat %s/%s", rigName, polecatName)
+		_ = telemetry.Inc(d.config.TownRoot, telemetry.SessionRestartsTotal,
+			telemetry.Labels{"agent": agentID})
 	}
 }
 
 // recordSessionDeath records a session death and checks for mass death pattern.
 func (d *Daemon) recordSessionDeath(sessionName string) {
+	labels := telemetry.Labels{"rig": "", "role": "unknown"}
+	if id, err := session.ParseSessionName(sessionName); err == nil {
+		labels = telemetry.Labels{"rig": id.Rig, "role": string(id.Role)}
+	}
+	_ = telemetry.Inc(d.config.TownRoot, telemetry.SessionDeathsTotal, labels)
+
 	d.deathsMu.Lock()
 	defer d.deathsMu.Unlock()
 
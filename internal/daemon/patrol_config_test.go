@@ -98,3 +98,13 @@ func TestDoltRemotesInterval(t *testing.T) {
 		t.Errorf("expected 5m interval, got %v", got)
 	}
 }
+
+func TestIsPatrolEnabled_Plugins(t *testing.T) {
+	if !IsPatrolEnabled(nil, "plugins") {
+		t.Error("plugins should default to enabled")
+	}
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{Plugins: &PatrolConfig{Enabled: false}}}
+	if IsPatrolEnabled(config, "plugins") {
+		t.Error("plugins should be disabled when explicitly set to false")
+	}
+}
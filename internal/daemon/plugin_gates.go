@@ -0,0 +1,117 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/plugin"
+)
+
+// pluginDispatchTimeout bounds a single gt dog dispatch call.
+const pluginDispatchTimeout = 60 * time.Second
+
+// runPluginGates evaluates every plugin's gate and dispatches the due ones
+// to dogs. Condition checks and dispatches can each take tens of seconds, so
+// the pass runs off the heartbeat goroutine; a heartbeat that finds the
+// previous pass still running skips this one.
+func (d *Daemon) runPluginGates() {
+	if !IsPatrolEnabled(d.patrolConfig, "plugins") {
+		return
+	}
+	if !d.pluginGatesRunning.TryLock() {
+		d.logger.Printf("plugins: previous gate pass still running, skipping")
+		return
+	}
+	go func() {
+		defer d.pluginGatesRunning.Unlock()
+		d.evaluatePluginGates()
+	}()
+}
+
+// evaluatePluginGates is one gate pass. Gate evaluation is mechanical
+// (timestamps, cron schedules, exit codes, event names); the plugin's
+// instructions are still executed by a dog. Non-fatal: errors are logged
+// and the remaining plugins are still evaluated.
+func (d *Daemon) evaluatePluginGates() {
+	townRoot := d.config.TownRoot
+
+	state, err := plugin.LoadGateState(townRoot)
+	if err != nil {
+		d.logger.Printf("plugins: %v", err)
+		return
+	}
+	batch := d.pluginEvents.Drain(state)
+
+	plugins, err := plugin.NewScanner(townRoot, d.getKnownRigs()).DiscoverAll()
+	if err != nil {
+		d.logger.Printf("plugins: discovery failed: %v", err)
+		return
+	}
+
+	evaluator := plugin.NewGateEvaluator(townRoot, plugin.NewRecorder(townRoot), state)
+	now := time.Now()
+	var dispatched []string
+	eventDispatchFailed := false
+	for _, decision := range evaluator.Evaluate(plugins, batch.Events) {
+		if !decision.Open {
+			continue
+		}
+		d.logger.Printf("plugins: %s gate open (%s), dispatching", decision.Name, decision.Reason)
+		if err := d.dispatchPlugin(decision.Plugin); err != nil {
+			d.logger.Printf("plugins: dispatching %s: %v", decision.Name, err)
+			if decision.Gate == plugin.GateEvent {
+				eventDispatchFailed = true
+			}
+			continue
+		}
+		dispatched = append(dispatched, decision.Name)
+	}
+
+	// Events are consumed only when every event gate they opened was
+	// dispatched. Otherwise the batch is replayed next pass; plugins that
+	// were dispatched are not fired again because their LastDispatched is
+	// newer than the events.
+	if eventDispatchFailed {
+		d.logger.Printf("plugins: event dispatch failed, keeping events for the next pass")
+	} else {
+		batch.Commit(state)
+	}
+
+	// Merge into the on-disk state rather than overwriting it: gt dog dispatch
+	// records its own LastDispatched entries under the same lock.
+	err = plugin.UpdateGateState(townRoot, func(s *plugin.GateState) {
+		s.LastEvaluated = now
+		s.EventsCursor = state.EventsCursor
+		for _, name := range dispatched {
+			s.LastDispatched[name] = now
+		}
+	})
+	if err != nil {
+		d.logger.Printf("plugins: saving gate state: %v", err)
+	}
+}
+
+// dispatchPlugin hands a plugin to an idle dog, creating one if the pool is empty.
+func (d *Daemon) dispatchPlugin(p *plugin.Plugin) error {
+	args := []string{"dog", "dispatch", "--plugin", p.Name, "--create"}
+	if p.RigName != "" {
+		args = append(args, "--rig", p.RigName)
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, pluginDispatchTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, d.gtPath, args...) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = os.Environ()
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			d.logger.Printf("plugins: gt dog dispatch output: %s", msg)
+		}
+		return err
+	}
+	return nil
+}
@@ -87,6 +87,7 @@ func TestCheckPolecatHealth_SkipsSpawning(t *testing.T) {
 // This ensures the spawning guard in issue #1752 does not accidentally suppress
 // legitimate crash detection for polecats that were running normally.
 func TestCheckPolecatHealth_DetectsCrashedPolecat(t *testing.T) {
+	t.Chdir(t.TempDir()) // keep events out of any enclosing town
 	if runtime.GOOS == "windows" {
 		t.Skip("test uses Unix shell script mocks for tmux and bd")
 	}
@@ -117,6 +118,7 @@ func TestCheckPolecatHealth_DetectsCrashedPolecat(t *testing.T) {
 // has a time-bound: polecats stuck in agent_state=spawning for more than 5 minutes
 // are treated as crashed (gt sling may have failed during spawn).
 func TestCheckPolecatHealth_SpawningGuardExpires(t *testing.T) {
+	t.Chdir(t.TempDir()) // keep events out of any enclosing town
 	if runtime.GOOS == "windows" {
 		t.Skip("test uses Unix shell script mocks for tmux and bd")
 	}
@@ -153,6 +155,7 @@ func TestCheckPolecatHealth_SpawningGuardExpires(t *testing.T) {
 // polecat that transitioned from "spawning" to "working" will have stale
 // description text. The DB column must be authoritative.
 func TestCheckPolecatHealth_DBStateOverridesDescription(t *testing.T) {
+	t.Chdir(t.TempDir()) // keep events out of any enclosing town
 	if runtime.GOOS == "windows" {
 		t.Skip("test uses Unix shell script mocks for tmux and bd")
 	}
@@ -0,0 +1,47 @@
+package daemon
+
+import (
+	"github.com/steveyegge/gastown/internal/pty"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// sessionOps is the session backend the daemon drives.
+// *tmux.Tmux and *pty.Client both implement it.
+type sessionOps = session.Backend
+
+// newSessionOps returns the backend the town's settings select.
+func newSessionOps(townRoot string) sessionOps {
+	return session.NewBackend(townRoot)
+}
+
+// startPTYSupervisor hosts the headless session supervisor on the town's
+// PTY socket. Sessions are children of the daemon and stop with it.
+func (d *Daemon) startPTYSupervisor() error {
+	sup := pty.NewSupervisor()
+	sup.OnExit = func(name string, exitCode int) {
+		d.logger.Printf("PTY session %s exited (code %d)", name, exitCode)
+	}
+	srv, err := pty.Listen(sup, pty.SocketPath(d.config.TownRoot))
+	if err != nil {
+		return err
+	}
+	d.ptySupervisor = sup
+	d.ptyServer = srv
+	go func() {
+		if err := srv.Serve(); err != nil {
+			d.logger.Printf("PTY supervisor stopped serving: %v", err)
+		}
+	}()
+	return nil
+}
+
+// stopPTYSupervisor closes the socket and kills all PTY sessions.
+func (d *Daemon) stopPTYSupervisor() {
+	if d.ptyServer == nil {
+		return
+	}
+	_ = d.ptyServer.Close()
+	d.ptySupervisor.Shutdown()
+	d.ptyServer = nil
+	d.ptySupervisor = nil
+}
@@ -0,0 +1,164 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/quota"
+)
+
+const defaultQuotaLeadTime = 20 * time.Minute
+
+// quotaLeadTime returns how far ahead of predicted exhaustion accounts are
+// put in cooldown.
+func quotaLeadTime(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.Quota != nil {
+		if d, err := time.ParseDuration(config.Patrols.Quota.LeadTime); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultQuotaLeadTime
+}
+
+// quotaRotateEnabled reports whether idle sessions are rotated off accounts
+// in cooldown.
+func quotaRotateEnabled(config *DaemonPatrolConfig) bool {
+	if config != nil && config.Patrols != nil && config.Patrols.Quota != nil && config.Patrols.Quota.Rotate != nil {
+		return *config.Patrols.Quota.Rotate
+	}
+	return true
+}
+
+// forecastQuota updates account usage from transcripts and acts on the
+// forecast: an account predicted to hit its limit within the lead time goes
+// into cooldown until its window resets, and idle sessions on it are rotated
+// to accounts with headroom. Acting before the limit means no session loses
+// an in-flight turn to a rate-limit message.
+func (d *Daemon) forecastQuota() {
+	townRoot := d.config.TownRoot
+	acctCfg, err := config.LoadAccountsConfig(constants.MayorAccountsPath(townRoot))
+	if err != nil || len(acctCfg.Accounts) == 0 {
+		return // No accounts registered: nothing to forecast
+	}
+
+	mgr := quota.NewManager(townRoot)
+	now := time.Now()
+	usage, err := mgr.LoadUsage()
+	if err != nil {
+		d.logger.Printf("Quota: %v (starting fresh)", err)
+	}
+	quotaState, err := mgr.Load()
+	if err != nil {
+		d.logger.Printf("Quota: loading state: %v", err)
+		return
+	}
+	for _, err := range quota.RefreshUsage(usage, acctCfg, quotaState, now) {
+		d.logger.Printf("Quota: %v", err)
+	}
+	if err := mgr.SaveUsage(usage); err != nil {
+		d.logger.Printf("Quota: saving usage: %v", err)
+	}
+
+	lead := quotaLeadTime(d.patrolConfig)
+	var draining []string
+	err = mgr.WithLock(func() error {
+		state, err := mgr.Load()
+		if err != nil {
+			return err
+		}
+		mgr.EnsureAccountsTracked(state, acctCfg.Accounts)
+
+		changed := false
+		handles := make([]string, 0, len(acctCfg.Accounts))
+		for handle := range acctCfg.Accounts {
+			handles = append(handles, handle)
+		}
+		sort.Strings(handles)
+		for _, handle := range handles {
+			qs := state.Accounts[handle]
+
+			// Lift cooldowns whose window has reset.
+			if qs.Status == config.QuotaStatusCooldown {
+				if until, err := time.Parse(time.RFC3339, qs.CooldownUntil); err != nil || !until.After(now) {
+					qs.Status = config.QuotaStatusAvailable
+					qs.CooldownUntil = ""
+					state.Accounts[handle] = qs
+					changed = true
+					d.logger.Printf("Quota: %s window reset, cooldown lifted", handle)
+				}
+			}
+
+			if qs.Status == config.QuotaStatusAvailable || qs.Status == "" {
+				f := quota.ForecastUsage(handle, usage.Accounts[handle], now)
+				if f.ExhaustsWithin(now, lead) {
+					qs.Status = config.QuotaStatusCooldown
+					qs.CooldownUntil = f.WindowEnd.UTC().Format(time.RFC3339)
+					state.Accounts[handle] = qs
+					changed = true
+					d.logger.Printf("Quota: %s predicted to hit its limit at %s (%d of ~%d tokens, window resets %s); cooling down",
+						handle, f.ExhaustsAt.Format("15:04"), f.UsedTokens, f.CapacityTokens, f.WindowEnd.Format("15:04"))
+				}
+			}
+
+			if state.Accounts[handle].Status == config.QuotaStatusCooldown {
+				draining = append(draining, handle)
+			}
+		}
+		if !changed {
+			return nil
+		}
+		return mgr.SaveUnlocked(state)
+	})
+	if err != nil {
+		d.logger.Printf("Quota: updating state: %v", err)
+		return
+	}
+
+	if len(draining) == 0 || len(acctCfg.Accounts) < 2 || !quotaRotateEnabled(d.patrolConfig) {
+		return
+	}
+	d.rotateQuota(draining)
+}
+
+// rotateQuota runs 'gt quota rotate --proactive' in the background to move
+// idle sessions off the draining accounts. Skipped while a previous
+// rotation is still running.
+func (d *Daemon) rotateQuota(draining []string) {
+	if !d.quotaRotating.TryLock() {
+		return
+	}
+	if d.rotateQuotaFn != nil {
+		defer d.quotaRotating.Unlock()
+		d.rotateQuotaFn()
+		return
+	}
+
+	gtPath := d.gtPath
+	if gtPath == "" {
+		gtPath = "gt"
+	}
+	townRoot := d.config.TownRoot
+	logger := d.logger
+	go func() {
+		defer d.quotaRotating.Unlock()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, gtPath, "quota", "rotate", "--proactive") //nolint:gosec // G204: args are constructed internally
+		cmd.Dir = townRoot
+		cmd.Env = os.Environ()
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			logger.Printf("Quota: proactive rotation off %s failed: %v: %s", strings.Join(draining, ", "), err, strings.TrimSpace(string(out)))
+			return
+		}
+		if text := strings.TrimSpace(string(out)); text != "" && !strings.Contains(text, "No idle sessions") {
+			logger.Printf("Quota: proactive rotation off %s:\n%s", strings.Join(draining, ", "), text)
+		}
+	}()
+}
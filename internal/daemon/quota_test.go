@@ -0,0 +1,100 @@
+package daemon
+
+import (
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/costs"
+	"github.com/steveyegge/gastown/internal/quota"
+)
+
+// setupQuotaTown registers two accounts with empty config dirs.
+func setupQuotaTown(t *testing.T, patrols *PatrolsConfig) *Daemon {
+	t.Helper()
+	townRoot := t.TempDir()
+	acctCfg := &config.AccountsConfig{
+		Version: config.CurrentAccountsVersion,
+		Accounts: map[string]config.Account{
+			"work":     {ConfigDir: t.TempDir()},
+			"personal": {ConfigDir: t.TempDir()},
+		},
+	}
+	if err := config.SaveAccountsConfig(constants.MayorAccountsPath(townRoot), acctCfg); err != nil {
+		t.Fatal(err)
+	}
+	return &Daemon{
+		config:       &Config{TownRoot: townRoot},
+		patrolConfig: &DaemonPatrolConfig{Patrols: patrols},
+		logger:       log.New(io.Discard, "", 0),
+	}
+}
+
+func requestsFrom(start time.Time, n int) []costs.Request {
+	var reqs []costs.Request
+	for i := 0; i < n; i++ {
+		reqs = append(reqs, costs.Request{At: start.Add(time.Duration(i) * time.Minute), Tokens: costs.Tokens{Output: 1000}})
+	}
+	return reqs
+}
+
+func TestForecastQuota_CoolsDownAccountBeforeLimit(t *testing.T) {
+	d := setupQuotaTown(t, &PatrolsConfig{Quota: &QuotaPatrolConfig{Enabled: true, LeadTime: "90m"}})
+	rotations := 0
+	d.rotateQuotaFn = func() { rotations++ }
+
+	// Yesterday "work" used ~120k tokens in two hours and hit a 4h window's
+	// limit; today it has used 60k in the last hour, so it runs out in ~1h.
+	now := time.Now()
+	prev := now.Add(-26 * time.Hour)
+	mgr := quota.NewManager(d.config.TownRoot)
+	usage := &quota.UsageState{Accounts: map[string]*quota.AccountUsage{}}
+	work := usage.Account("work")
+	work.Add(requestsFrom(prev, 120))
+	work.ObserveReset(prev.Add(2*time.Hour), prev.Add(4*time.Hour))
+	work.Add(requestsFrom(now.Add(-time.Hour), 60))
+	if err := mgr.SaveUsage(usage); err != nil {
+		t.Fatal(err)
+	}
+
+	d.forecastQuota()
+
+	state, err := mgr.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := state.Accounts["work"]; got.Status != config.QuotaStatusCooldown || got.CooldownUntil == "" {
+		t.Errorf("work = %+v, want cooldown until its window resets", got)
+	}
+	if got := state.Accounts["personal"].Status; got != config.QuotaStatusAvailable {
+		t.Errorf("personal = %s, want available", got)
+	}
+	if rotations != 1 {
+		t.Errorf("rotations = %d, want 1", rotations)
+	}
+}
+
+func TestForecastQuota_LiftsExpiredCooldown(t *testing.T) {
+	d := setupQuotaTown(t, nil)
+	d.rotateQuotaFn = func() { t.Error("no rotation expected without accounts in cooldown") }
+
+	mgr := quota.NewManager(d.config.TownRoot)
+	if err := mgr.Save(&config.QuotaState{Accounts: map[string]config.AccountQuotaState{
+		"work": {Status: config.QuotaStatusCooldown, CooldownUntil: time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	d.forecastQuota()
+
+	state, err := mgr.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := state.Accounts["work"]; got.Status != config.QuotaStatusAvailable || got.CooldownUntil != "" {
+		t.Errorf("work = %+v, want available after the window reset", got)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/telemetry"
 )
 
 // RestartTracker tracks agent restart attempts with exponential backoff.
@@ -102,6 +104,8 @@ func (rt *RestartTracker) CanRestart(agentID string) bool {
 
 // RecordRestart records a restart attempt and calculates next backoff.
 func (rt *RestartTracker) RecordRestart(agentID string) {
+	_ = telemetry.Inc(rt.townRoot, telemetry.SessionRestartsTotal, telemetry.Labels{"agent": agentID})
+
 	rt.mu.Lock()
 	defer rt.mu.Unlock()
 
@@ -0,0 +1,46 @@
+package daemon
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/telemetry"
+)
+
+// startMetricsServer serves the town's metrics in Prometheus text format on
+// telemetry.listen. It does nothing when telemetry is disabled or no listen
+// address is configured.
+func (d *Daemon) startMetricsServer() error {
+	cfg := telemetry.Settings(d.config.TownRoot)
+	if cfg == nil || cfg.Listen == "" {
+		return nil
+	}
+	ln, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", telemetry.Handler(d.config.TownRoot))
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	d.metricsServer = srv
+	d.logger.Printf("Metrics endpoint listening on http://%s/metrics", ln.Addr())
+	go func() {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			d.logger.Printf("Metrics endpoint stopped serving: %v", err)
+		}
+	}()
+	return nil
+}
+
+// stopMetricsServer shuts the metrics endpoint down.
+func (d *Daemon) stopMetricsServer() {
+	if d.metricsServer == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = d.metricsServer.Shutdown(ctx)
+	d.metricsServer = nil
+}
@@ -116,8 +116,28 @@ type PatrolsConfig struct {
 	Refinery    *PatrolConfig      `json:"refinery,omitempty"`
 	Witness     *PatrolConfig      `json:"witness,omitempty"`
 	Deacon      *PatrolConfig      `json:"deacon,omitempty"`
+	Plugins     *PatrolConfig      `json:"plugins,omitempty"`
 	DoltServer  *DoltServerConfig  `json:"dolt_server,omitempty"`
 	DoltRemotes *DoltRemotesConfig `json:"dolt_remotes,omitempty"`
+	Quota       *QuotaPatrolConfig `json:"quota,omitempty"`
+}
+
+// QuotaPatrolConfig holds configuration for the quota forecast patrol.
+// The patrol forecasts each account's usage, puts accounts predicted to
+// hit their limit in cooldown, and rotates idle sessions off them.
+type QuotaPatrolConfig struct {
+	// Enabled controls whether the patrol runs (default true when the
+	// town has accounts).
+	Enabled bool `json:"enabled"`
+
+	// LeadTime is how far ahead of predicted exhaustion an account is put
+	// in cooldown, as a duration string (default "20m").
+	LeadTime string `json:"lead_time,omitempty"`
+
+	// Rotate controls whether idle sessions are rotated off accounts in
+	// cooldown (default true). With rotation off, cooldown only keeps the
+	// account from being a rotation target.
+	Rotate *bool `json:"rotate,omitempty"`
 }
 
 // DoltRemotesConfig holds configuration for the dolt_remotes patrol.
@@ -200,6 +220,14 @@ func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 		if config.Patrols.Deacon != nil {
 			return config.Patrols.Deacon.Enabled
 		}
+	case "plugins":
+		if config.Patrols.Plugins != nil {
+			return config.Patrols.Plugins.Enabled
+		}
+	case "quota":
+		if config.Patrols.Quota != nil {
+			return config.Patrols.Quota.Enabled
+		}
 	}
 	return true // Default: enabled
 }
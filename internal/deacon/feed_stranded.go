@@ -348,19 +348,9 @@ func PruneFeedStrandedState(townRoot string) (int, error) {
 
 // getConvoyStatus returns the current status of a convoy bead.
 func getConvoyStatus(townRoot, convoyID string) string {
-	cmd := exec.Command("bd", "show", convoyID, "--json")
-	cmd.Dir = townRoot
-
-	output, err := cmd.Output()
+	issue, err := newBeadsStore(townRoot).Show(convoyID)
 	if err != nil {
 		return ""
 	}
-
-	var issues []struct {
-		Status string `json:"status"`
-	}
-	if err := json.Unmarshal(output, &issues); err != nil || len(issues) == 0 {
-		return ""
-	}
-	return issues[0].Status
+	return issue.Status
 }
@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
 )
 
 func TestFeedStrandedStateFile(t *testing.T) {
@@ -219,3 +221,27 @@ func TestSaveFeedStrandedState_CreatesDirectory(t *testing.T) {
 		t.Fatal("state file not created")
 	}
 }
+
+func TestGetConvoyStatus_MemoryStore(t *testing.T) {
+	store := beads.NewMemoryStore("hq")
+	orig := newBeadsStore
+	newBeadsStore = func(string) beads.Store { return store }
+	t.Cleanup(func() { newBeadsStore = orig })
+
+	convoy, err := store.Create(beads.CreateOptions{Title: "convoy", Type: "convoy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := getConvoyStatus("", convoy.ID); got != "open" {
+		t.Errorf("getConvoyStatus = %q, want open", got)
+	}
+	if err := store.Close(convoy.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := getConvoyStatus("", convoy.ID); got != "closed" {
+		t.Errorf("getConvoyStatus after close = %q, want closed", got)
+	}
+	if got := getConvoyStatus("", "hq-missing"); got != "" {
+		t.Errorf("getConvoyStatus(missing) = %q, want empty", got)
+	}
+}
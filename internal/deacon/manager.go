@@ -46,7 +46,7 @@ type Manager struct {
 func NewManager(townRoot string) *Manager {
 	return &Manager{
 		townRoot: townRoot,
-		tmux:     tmux.NewTmux(),
+		tmux:     session.NewBackend(townRoot),
 	}
 }
 
@@ -143,8 +143,8 @@ func (m *Manager) Start(agentOverride string) error {
 	}
 
 	// Track PID for defense-in-depth orphan cleanup (non-fatal)
-	if realTmux, ok := t.(*tmux.Tmux); ok {
-		_ = session.TrackSessionPID(m.townRoot, sessionID, realTmux)
+	if backend, ok := t.(session.Backend); ok {
+		_ = session.TrackSessionPID(m.townRoot, sessionID, backend)
 	}
 
 	// PATCH-010: Set auto-respawn hook for Deacon resilience.
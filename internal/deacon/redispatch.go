@@ -320,23 +320,20 @@ func resolveRigFromBead(townRoot, beadID string) string {
 	return beads.GetRigNameForPrefix(townRoot, prefix)
 }
 
+// newBeadsStore returns the beads storage the deacon uses from townRoot.
+// Lookups and writes route to rig databases by prefix. Tests replace it to
+// run against a *beads.MemoryStore.
+var newBeadsStore = func(townRoot string) beads.Store {
+	return beads.New(townRoot)
+}
+
 // getBeadStatusForRedispatch returns the current status of a bead.
 func getBeadStatusForRedispatch(townRoot, beadID string) string {
-	cmd := exec.Command("bd", "show", beadID, "--json")
-	cmd.Dir = townRoot
-
-	output, err := cmd.Output()
+	issue, err := newBeadsStore(townRoot).Show(beadID)
 	if err != nil {
 		return ""
 	}
-
-	var issues []struct {
-		Status string `json:"status"`
-	}
-	if err := json.Unmarshal(output, &issues); err != nil || len(issues) == 0 {
-		return ""
-	}
-	return issues[0].Status
+	return issue.Status
 }
 
 // slingBead dispatches a bead to a rig via gt sling.
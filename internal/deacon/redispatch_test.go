@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
 )
 
 func TestParseRecoveredBeadSubject(t *testing.T) {
@@ -203,3 +205,37 @@ func TestRedispatchState_GetBeadState(t *testing.T) {
 		t.Error("expected different bead state for different ID")
 	}
 }
+
+func TestPruneRedispatchState_MemoryStore(t *testing.T) {
+	store := beads.NewMemoryStore("gt")
+	orig := newBeadsStore
+	newBeadsStore = func(string) beads.Store { return store }
+	t.Cleanup(func() { newBeadsStore = orig })
+
+	open, _ := store.Create(beads.CreateOptions{Title: "still open", Priority: -1})
+	closed, _ := store.Create(beads.CreateOptions{Title: "done", Priority: -1})
+	if err := store.Close(closed.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	townRoot := t.TempDir()
+	state := &RedispatchState{Beads: map[string]*BeadRedispatchState{}}
+	for _, id := range []string{open.ID, closed.ID, "gt-gone"} {
+		state.GetBeadState(id).RecordAttempt("testrig")
+	}
+	if err := SaveRedispatchState(townRoot, state); err != nil {
+		t.Fatal(err)
+	}
+
+	pruned, err := PruneRedispatchState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pruned != 2 {
+		t.Errorf("pruned = %d, want 2 (closed and missing beads)", pruned)
+	}
+	state, _ = LoadRedispatchState(townRoot)
+	if _, ok := state.Beads[open.ID]; !ok || len(state.Beads) != 1 {
+		t.Errorf("remaining state = %v, want only %s", state.Beads, open.ID)
+	}
+}
@@ -2,17 +2,15 @@
 package deacon
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/session"
-	"github.com/steveyegge/gastown/internal/tmux"
 )
 
 // StaleHookConfig holds configurable parameters for stale hook detection.
@@ -91,7 +89,7 @@ func ScanStaleHooks(townRoot string, cfg *StaleHookConfig) (*StaleHookScanResult
 	result.TotalHooked = len(hookedBeads)
 
 	threshold := time.Now().Add(-cfg.MaxAge)
-	t := tmux.NewTmux()
+	t := session.NewBackend(townRoot)
 
 	for _, bead := range hookedBeads {
 		hookResult := &StaleHookResult{
@@ -154,28 +152,23 @@ func ScanStaleHooks(townRoot string, cfg *StaleHookConfig) (*StaleHookScanResult
 
 // listHookedBeads returns all beads with status=hooked.
 func listHookedBeads(townRoot string) ([]*HookedBead, error) {
-	cmd := exec.Command("bd", "list", "--status=hooked", "--json", "--limit=0")
-	cmd.Dir = townRoot
-
-	output, err := cmd.Output()
+	issues, err := newBeadsStore(townRoot).List(beads.ListOptions{Status: "hooked", Priority: -1})
 	if err != nil {
-		// No hooked beads is not an error
-		if strings.Contains(string(output), "no issues found") {
-			return nil, nil
-		}
 		return nil, err
 	}
 
-	if len(output) == 0 || string(output) == "[]" || string(output) == "null\n" {
-		return nil, nil
+	hooked := make([]*HookedBead, 0, len(issues))
+	for _, issue := range issues {
+		updatedAt, _ := time.Parse(time.RFC3339, issue.UpdatedAt)
+		hooked = append(hooked, &HookedBead{
+			ID:        issue.ID,
+			Title:     issue.Title,
+			Status:    issue.Status,
+			Assignee:  issue.Assignee,
+			UpdatedAt: updatedAt,
+		})
 	}
-
-	var beads []*HookedBead
-	if err := json.Unmarshal(output, &beads); err != nil {
-		return nil, fmt.Errorf("parsing hooked beads: %w", err)
-	}
-
-	return beads, nil
+	return hooked, nil
 }
 
 // assigneeToSessionName converts an assignee address to a tmux session name.
@@ -248,7 +241,6 @@ func assigneeToWorktreePath(townRoot, assignee string) string {
 
 // unhookBead sets a bead's status back to 'open'.
 func unhookBead(townRoot, beadID string) error {
-	cmd := exec.Command("bd", "update", beadID, "--status=open")
-	cmd.Dir = townRoot
-	return cmd.Run()
+	open := "open"
+	return newBeadsStore(townRoot).Update(beadID, beads.UpdateOptions{Status: &open})
 }
@@ -5,6 +5,9 @@ import (
 	"os/exec"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
 )
 
 func TestAssigneeToSessionName(t *testing.T) {
@@ -271,3 +274,45 @@ func TestStaleHookResult_PartialWorkFields(t *testing.T) {
 		t.Errorf("UnpushedCount = %d, want 3", result.UnpushedCount)
 	}
 }
+
+func TestScanStaleHooks_MemoryStore(t *testing.T) {
+	store := beads.NewMemoryStore("gt")
+	orig := newBeadsStore
+	newBeadsStore = func(string) beads.Store { return store }
+	t.Cleanup(func() { newBeadsStore = orig })
+
+	hook := func(title string) *beads.Issue {
+		t.Helper()
+		issue, err := store.Create(beads.CreateOptions{Title: title, Priority: -1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		hooked, assignee := "hooked", "not a valid address"
+		if err := store.Update(issue.ID, beads.UpdateOptions{Status: &hooked, Assignee: &assignee}); err != nil {
+			t.Fatal(err)
+		}
+		return issue
+	}
+	// Liveness can't be checked for these assignees, so age decides.
+	store.SetClock(func() time.Time { return time.Now().Add(-2 * time.Hour) })
+	old := hook("old hook")
+	store.SetClock(time.Now)
+	fresh := hook("fresh hook")
+
+	result, err := ScanStaleHooks(t.TempDir(), &StaleHookConfig{MaxAge: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.TotalHooked != 2 || result.StaleCount != 1 || result.Unhooked != 1 {
+		t.Fatalf("result = %+v", result)
+	}
+	if r := result.Results[0]; r.BeadID != old.ID || !r.Unhooked {
+		t.Errorf("stale result = %+v, want %s unhooked", r, old.ID)
+	}
+	if got, _ := store.Show(old.ID); got.Status != "open" {
+		t.Errorf("old hook status = %s, want open", got.Status)
+	}
+	if got, _ := store.Show(fresh.ID); got.Status != "hooked" {
+		t.Errorf("fresh hook status = %s, want hooked", got.Status)
+	}
+}
@@ -12,7 +12,6 @@ import (
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 )
 
 // gitFileStatus represents the git status of a file.
@@ -654,7 +653,7 @@ func (c *ClaudeSettingsCheck) Fix(ctx *CheckContext) error {
 	var errors []string
 	var skipped []string
 	var needsRestart bool
-	t := tmux.NewTmux()
+	t := session.NewBackend(ctx.TownRoot)
 
 	for _, sf := range c.staleSettings {
 		// Skip files that aren't stale (correct settings.json files)
@@ -40,10 +40,14 @@ func (c *FormulaCheck) Run(ctx *CheckContext) *CheckResult {
 
 	// All good
 	if report.Outdated == 0 && report.Missing == 0 && report.Modified == 0 && report.New == 0 && report.Untracked == 0 {
+		message := fmt.Sprintf("%d formulas up-to-date", report.OK)
+		if report.Registry > 0 {
+			message += fmt.Sprintf(" (%d installed from registry)", report.Registry)
+		}
 		return &CheckResult{
 			Name:    c.Name(),
 			Status:  StatusOK,
-			Message: fmt.Sprintf("%d formulas up-to-date", report.OK),
+			Message: message,
 		}
 	}
 
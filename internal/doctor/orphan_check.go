@@ -26,7 +26,7 @@ type SessionLister interface {
 }
 
 type realSessionLister struct {
-	t *tmux.Tmux
+	t session.Backend
 }
 
 func (r *realSessionLister) ListSessions() ([]string, error) {
@@ -57,7 +57,7 @@ func NewOrphanSessionCheckWithSessionLister(lister SessionLister) *OrphanSession
 func (c *OrphanSessionCheck) Run(ctx *CheckContext) *CheckResult {
 	lister := c.sessionLister
 	if lister == nil {
-		lister = &realSessionLister{t: tmux.NewTmux()}
+		lister = &realSessionLister{t: session.NewBackend(ctx.TownRoot)}
 	}
 
 	sessions, err := lister.ListSessions()
@@ -137,7 +137,7 @@ func (c *OrphanSessionCheck) Fix(ctx *CheckContext) error {
 		return nil
 	}
 
-	t := tmux.NewTmux()
+	t := session.NewBackend(ctx.TownRoot)
 	var lastErr error
 
 	for _, sess := range c.orphanSessions {
@@ -62,7 +62,7 @@ func NewMalformedSessionNameCheck() *MalformedSessionNameCheck {
 func (c *MalformedSessionNameCheck) Run(ctx *CheckContext) *CheckResult {
 	lister := c.sessionListerForTest
 	if lister == nil {
-		lister = &realSessionLister{t: tmux.NewTmux()}
+		lister = &realSessionLister{t: session.NewBackend(ctx.TownRoot)}
 	}
 
 	reg := c.registryForTest
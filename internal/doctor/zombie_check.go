@@ -5,7 +5,6 @@ import (
 
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/session"
-	"github.com/steveyegge/gastown/internal/tmux"
 )
 
 // ZombieSessionCheck detects tmux sessions that are valid Gas Town sessions
@@ -31,7 +30,7 @@ func NewZombieSessionCheck() *ZombieSessionCheck {
 
 // Run checks for zombie Gas Town sessions (tmux alive but Claude dead).
 func (c *ZombieSessionCheck) Run(ctx *CheckContext) *CheckResult {
-	t := tmux.NewTmux()
+	t := session.NewBackend(ctx.TownRoot)
 
 	sessions, err := t.ListSessions()
 	if err != nil {
@@ -115,7 +114,7 @@ func (c *ZombieSessionCheck) Fix(ctx *CheckContext) error {
 		return nil
 	}
 
-	t := tmux.NewTmux()
+	t := session.NewBackend(ctx.TownRoot)
 	var lastErr error
 
 	for _, sess := range c.zombieSessions {
@@ -55,6 +55,7 @@ func TestZombieSessionCheck_SkipsCrewSessions(t *testing.T) {
 }
 
 func TestZombieSessionCheck_FixProtectsCrewSessions(t *testing.T) {
+	t.Chdir(t.TempDir()) // keep events out of any enclosing town
 	// Verify that Fix() never kills crew sessions
 	check := NewZombieSessionCheck()
 
@@ -22,7 +22,7 @@ var (
 
 // SessionManager handles dog session lifecycle.
 type SessionManager struct {
-	tmux     *tmux.Tmux
+	tmux     session.Backend
 	mgr      *Manager
 	townRoot string
 }
@@ -30,7 +30,7 @@ type SessionManager struct {
 // NewSessionManager creates a new dog session manager.
 // The Manager parameter is used to sync persistent dog state (idle/working)
 // when sessions start and stop.
-func NewSessionManager(t *tmux.Tmux, townRoot string, mgr *Manager) *SessionManager {
+func NewSessionManager(t session.Backend, townRoot string, mgr *Manager) *SessionManager {
 	return &SessionManager{
 		tmux:     t,
 		mgr:      mgr,
@@ -47,6 +47,7 @@ import (
 	"github.com/gofrs/flock"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/util"
 )
 
@@ -2005,6 +2006,7 @@ func MeasureQueryLatency(townRoot string) (time.Duration, error) {
 		return 0, fmt.Errorf("SELECT 1 failed: %w (output: %s)", err, strings.TrimSpace(string(output)))
 	}
 
+	_ = telemetry.ObserveDuration(townRoot, telemetry.DoltQueryLatencySeconds, nil, elapsed)
 	return elapsed, nil
 }
 
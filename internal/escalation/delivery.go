@@ -0,0 +1,190 @@
+// Package escalation delivers escalations to humans outside Gas Town:
+// email over SMTP, SMS through an HTTP gateway, and Slack incoming webhooks.
+package escalation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Notice is the content of an escalation as sent to external channels.
+type Notice struct {
+	BeadID   string
+	Severity string
+	Title    string
+	Body     string // Full plain-text body (same text as the gt mail body)
+}
+
+// Status is the final state of a delivery.
+type Status string
+
+const (
+	StatusSent    Status = "sent"
+	StatusFailed  Status = "failed"
+	StatusSkipped Status = "skipped" // Channel not configured
+)
+
+// Outcome records what happened to one external action.
+type Outcome struct {
+	Action   string
+	Status   Status
+	Attempts int
+	At       time.Time
+	Err      error
+}
+
+// String formats the outcome as a single "delivery:" line for the escalation bead.
+func (o Outcome) String() string {
+	s := fmt.Sprintf("%s %s attempts=%d at=%s", o.Action, o.Status, o.Attempts, o.At.UTC().Format(time.RFC3339))
+	if o.Err != nil {
+		// Bead fields are line-oriented; keep the error on one line.
+		msg := strings.Join(strings.Fields(o.Err.Error()), " ")
+		s += " error=" + truncateRunes(msg, 203)
+	}
+	return s
+}
+
+// IsExternal reports whether action is delivered by this package.
+func IsExternal(action string) bool {
+	return strings.HasPrefix(action, "email:") || strings.HasPrefix(action, "sms:") || action == "slack"
+}
+
+// Deliverer sends notices to the channels configured in an EscalationConfig.
+type Deliverer struct {
+	contacts config.EscalationContacts
+
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	timeout        time.Duration
+	deadline       time.Duration
+
+	client *http.Client
+	sleep  func(time.Duration)
+}
+
+// NewDeliverer creates a Deliverer using the contacts and retry policy in cfg.
+func NewDeliverer(cfg *config.EscalationConfig) *Deliverer {
+	d := &Deliverer{
+		contacts: cfg.Contacts,
+		sleep:    time.Sleep,
+	}
+	d.maxAttempts, d.initialBackoff, d.maxBackoff, d.timeout = cfg.GetDeliveryPolicy()
+	d.deadline = cfg.GetDeliveryDeadline()
+	d.client = &http.Client{Timeout: d.timeout}
+	return d
+}
+
+// Deliver sends n through a single external action ("email:human",
+// "sms:human" or "slack"), retrying transient failures with exponential
+// backoff until the delivery deadline. It never returns an error; the
+// outcome carries the result.
+func (d *Deliverer) Deliver(action string, n Notice) Outcome {
+	send, err := d.sender(action)
+	if err != nil {
+		return Outcome{Action: action, Status: StatusSkipped, At: time.Now(), Err: err}
+	}
+
+	out := Outcome{Action: action}
+	deadline, cancelAll := context.WithTimeout(context.Background(), d.deadline)
+	defer cancelAll()
+	backoff := d.initialBackoff
+	for attempt := 1; attempt <= d.maxAttempts; attempt++ {
+		out.Attempts = attempt
+		ctx, cancel := context.WithTimeout(deadline, d.timeout)
+		err = send(ctx, n)
+		cancel()
+		if err == nil {
+			out.Status, out.Err, out.At = StatusSent, nil, time.Now()
+			return out
+		}
+		out.Err = err
+
+		var perm *permanentError
+		if errors.As(err, &perm) || attempt == d.maxAttempts {
+			break
+		}
+		if until, _ := deadline.Deadline(); time.Until(until) <= backoff {
+			break
+		}
+		d.sleep(backoff)
+		backoff *= 2
+		if backoff > d.maxBackoff {
+			backoff = d.maxBackoff
+		}
+	}
+	out.Status, out.At = StatusFailed, time.Now()
+	return out
+}
+
+// sender returns the send function for action, or an error describing why
+// the action cannot be attempted (unknown action or missing configuration).
+func (d *Deliverer) sender(action string) (func(context.Context, Notice) error, error) {
+	c := d.contacts
+	switch {
+	case strings.HasPrefix(action, "email:"):
+		to := strings.TrimPrefix(action, "email:")
+		if to == "human" {
+			if c.HumanEmail == "" {
+				return nil, errors.New("contacts.human_email not configured")
+			}
+			to = c.HumanEmail
+		}
+		if to == "" {
+			return nil, errors.New("email action has no recipient")
+		}
+		if c.SMTP == nil || c.SMTP.Host == "" {
+			return nil, errors.New("contacts.smtp.host not configured")
+		}
+		return func(ctx context.Context, n Notice) error {
+			return sendEmail(ctx, c.SMTP, to, n)
+		}, nil
+
+	case strings.HasPrefix(action, "sms:"):
+		to := strings.TrimPrefix(action, "sms:")
+		if to == "human" {
+			if c.HumanSMS == "" {
+				return nil, errors.New("contacts.human_sms not configured")
+			}
+			to = c.HumanSMS
+		}
+		if to == "" {
+			return nil, errors.New("sms action has no recipient")
+		}
+		if c.SMSGateway == nil || c.SMSGateway.URL == "" {
+			return nil, errors.New("contacts.sms_gateway.url not configured")
+		}
+		return func(ctx context.Context, n Notice) error {
+			return sendSMS(ctx, d.client, c.SMSGateway, to, n)
+		}, nil
+
+	case action == "slack":
+		if c.SlackWebhook == "" {
+			return nil, errors.New("contacts.slack_webhook not configured")
+		}
+		return func(ctx context.Context, n Notice) error {
+			return postSlack(ctx, d.client, c.SlackWebhook, n)
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown external action %q", action)
+}
+
+// permanentError marks a failure that retrying cannot fix
+// (bad credentials, rejected recipient, 4xx from a webhook).
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+func permanent(err error) error { return &permanentError{err: err} }
+
+// subject returns the one-line summary used for email subjects and chat messages.
+func (n Notice) subject() string {
+	return fmt.Sprintf("[%s] %s", strings.ToUpper(n.Severity), n.Title)
+}
@@ -0,0 +1,407 @@
+package escalation
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// fakeSMTP is a minimal SMTP server that records the messages it accepts.
+type fakeSMTP struct {
+	ln         net.Listener
+	mu         sync.Mutex
+	messages   []smtpMessage
+	authUser   string
+	authPass   string
+	rejectRcpt bool
+}
+
+type smtpMessage struct {
+	from, to, data, auth string
+}
+
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeSMTP{ln: ln}
+	go s.serve()
+	t.Cleanup(func() { _ = ln.Close() })
+	return s
+}
+
+func (s *fakeSMTP) port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTP) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeSMTP) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+
+	var msg smtpMessage
+	reply("220 fake ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch cmd {
+		case "EHLO", "HELO":
+			reply("250-fake")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			fields := strings.Fields(line)
+			if len(fields) == 3 {
+				raw, _ := base64.StdEncoding.DecodeString(fields[2])
+				msg.auth = string(raw)
+			}
+			if s.authUser != "" && msg.auth != "\x00"+s.authUser+"\x00"+s.authPass {
+				reply("535 authentication failed")
+				continue
+			}
+			reply("235 ok")
+		case "MAIL":
+			msg.from = line
+			reply("250 ok")
+		case "RCPT":
+			if s.rejectRcpt {
+				reply("550 no such user")
+				continue
+			}
+			msg.to = line
+			reply("250 ok")
+		case "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			msg.data = data.String()
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func (s *fakeSMTP) received() []smtpMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]smtpMessage(nil), s.messages...)
+}
+
+func testDeliverer(contacts config.EscalationContacts, maxAttempts int) *Deliverer {
+	d := NewDeliverer(&config.EscalationConfig{
+		Contacts: contacts,
+		Delivery: &config.EscalationDelivery{MaxAttempts: maxAttempts, Timeout: "5s"},
+	})
+	d.sleep = func(time.Duration) {}
+	return d
+}
+
+var testNotice = Notice{
+	BeadID:   "hq-abc",
+	Severity: "critical",
+	Title:    "Dolt server down 🚨",
+	Body:     "Escalation ID: hq-abc\nSeverity: critical",
+}
+
+func TestDeliver_Email(t *testing.T) {
+	srv := newFakeSMTP(t)
+	srv.authUser, srv.authPass = "gt", "s3cret"
+	t.Setenv("GT_TEST_SMTP_PASSWORD", "s3cret")
+
+	d := testDeliverer(config.EscalationContacts{
+		HumanEmail: "oncall@example.com",
+		SMTP: &config.EscalationSMTP{
+			Host:        "127.0.0.1",
+			Port:        srv.port(),
+			From:        "gastown@example.com",
+			Username:    "gt",
+			PasswordEnv: "GT_TEST_SMTP_PASSWORD",
+			TLS:         "none",
+		},
+	}, 1)
+
+	out := d.Deliver("email:human", testNotice)
+	if out.Status != StatusSent {
+		t.Fatalf("status = %s (%v), want sent", out.Status, out.Err)
+	}
+
+	msgs := srv.received()
+	if len(msgs) != 1 {
+		t.Fatalf("got %d messages, want 1", len(msgs))
+	}
+	m := msgs[0]
+	if !strings.Contains(m.from, "gastown@example.com") || !strings.Contains(m.to, "oncall@example.com") {
+		t.Errorf("envelope = %q / %q", m.from, m.to)
+	}
+	for _, want := range []string{"Subject: =?utf-8?q?", "X-Gastown-Escalation: hq-abc", "Severity: critical"} {
+		if !strings.Contains(m.data, want) {
+			t.Errorf("message missing %q:\n%s", want, m.data)
+		}
+	}
+}
+
+func TestDeliver_EmailExplicitRecipient(t *testing.T) {
+	srv := newFakeSMTP(t)
+	d := testDeliverer(config.EscalationContacts{
+		HumanEmail: "oncall@example.com",
+		SMTP:       &config.EscalationSMTP{Host: "127.0.0.1", Port: srv.port(), From: "gt@example.com", TLS: "none"},
+	}, 1)
+
+	out := d.Deliver("email:ops@example.com", testNotice)
+	if out.Status != StatusSent {
+		t.Fatalf("status = %s (%v), want sent", out.Status, out.Err)
+	}
+	msgs := srv.received()
+	if len(msgs) != 1 || !strings.Contains(msgs[0].to, "ops@example.com") {
+		t.Errorf("messages = %+v, want one to ops@example.com", msgs)
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	s := strings.Repeat("a", 8) + "🚨🚨"
+	got := truncateRunes(s, 13)
+	if !utf8.ValidString(got) {
+		t.Fatalf("truncateRunes split a rune: %q", got)
+	}
+	if got != strings.Repeat("a", 8)+"..." {
+		t.Errorf("truncateRunes = %q", got)
+	}
+	if got := truncateRunes("short", 13); got != "short" {
+		t.Errorf("truncateRunes(short) = %q", got)
+	}
+}
+
+func TestDeliver_EmailRejectedIsPermanent(t *testing.T) {
+	srv := newFakeSMTP(t)
+	srv.rejectRcpt = true
+
+	d := testDeliverer(config.EscalationContacts{
+		HumanEmail: "nobody@example.com",
+		SMTP:       &config.EscalationSMTP{Host: "127.0.0.1", Port: srv.port(), From: "gt@example.com", TLS: "none"},
+	}, 3)
+
+	out := d.Deliver("email:human", testNotice)
+	if out.Status != StatusFailed {
+		t.Fatalf("status = %s, want failed", out.Status)
+	}
+	if out.Attempts != 1 {
+		t.Errorf("attempts = %d, want 1 (5xx must not be retried)", out.Attempts)
+	}
+}
+
+func TestDeliver_SlackRetriesThenSucceeds(t *testing.T) {
+	var calls int32
+	var body map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	d := testDeliverer(config.EscalationContacts{SlackWebhook: srv.URL + "/services/T/B/secret"}, 3)
+	var slept []time.Duration
+	d.sleep = func(dur time.Duration) { slept = append(slept, dur) }
+
+	out := d.Deliver("slack", testNotice)
+	if out.Status != StatusSent || out.Attempts != 3 {
+		t.Fatalf("outcome = %s after %d attempts (%v), want sent after 3", out.Status, out.Attempts, out.Err)
+	}
+	if len(slept) != 2 || slept[1] != 2*slept[0] {
+		t.Errorf("backoff = %v, want two doubling waits", slept)
+	}
+	if !strings.Contains(body["text"], "[CRITICAL] Dolt server down") {
+		t.Errorf("slack text = %q", body["text"])
+	}
+}
+
+func TestDeliver_SlackClientErrorIsPermanent(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	d := testDeliverer(config.EscalationContacts{SlackWebhook: srv.URL + "/services/T/B/secret"}, 3)
+	out := d.Deliver("slack", testNotice)
+	if out.Status != StatusFailed || calls != 1 {
+		t.Fatalf("outcome = %s after %d calls, want failed after 1", out.Status, calls)
+	}
+	if strings.Contains(out.Err.Error(), "secret") {
+		t.Errorf("error leaks webhook path: %v", out.Err)
+	}
+}
+
+func TestDeliver_SMSGateway(t *testing.T) {
+	var gotPath, gotAuth, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.String()
+		gotAuth = r.Header.Get("Authorization")
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+	t.Setenv("GT_TEST_SMS_TOKEN", "tok")
+
+	d := testDeliverer(config.EscalationContacts{
+		HumanSMS: "+15551234567",
+		SMSGateway: &config.EscalationSMSGateway{
+			URL:     srv.URL + "/send?to={{.To}}",
+			Headers: map[string]string{"Authorization": "Bearer $GT_TEST_SMS_TOKEN"},
+		},
+	}, 1)
+
+	out := d.Deliver("sms:human", testNotice)
+	if out.Status != StatusSent {
+		t.Fatalf("status = %s (%v), want sent", out.Status, out.Err)
+	}
+	if gotPath != "/send?to=%2B15551234567" {
+		t.Errorf("path = %q", gotPath)
+	}
+	if gotAuth != "Bearer tok" {
+		t.Errorf("Authorization = %q", gotAuth)
+	}
+	var payload map[string]string
+	if err := json.Unmarshal([]byte(gotBody), &payload); err != nil {
+		t.Fatalf("default body is not JSON: %v (%s)", err, gotBody)
+	}
+	if payload["to"] != "+15551234567" || !strings.Contains(payload["message"], "hq-abc") {
+		t.Errorf("payload = %v", payload)
+	}
+}
+
+func TestDeliver_Unconfigured(t *testing.T) {
+	d := testDeliverer(config.EscalationContacts{HumanEmail: "a@example.com"}, 3)
+	for _, action := range []string{"email:human", "sms:human", "slack"} {
+		out := d.Deliver(action, testNotice)
+		if out.Status != StatusSkipped || out.Attempts != 0 {
+			t.Errorf("%s: outcome = %s after %d attempts, want skipped", action, out.Status, out.Attempts)
+		}
+	}
+}
+
+func TestOutcomeString(t *testing.T) {
+	at := time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC)
+	tests := []struct {
+		o    Outcome
+		want string
+	}{
+		{Outcome{Action: "slack", Status: StatusSent, Attempts: 1, At: at}, "slack sent attempts=1 at=2026-03-01T03:00:00Z"},
+		{
+			Outcome{Action: "email:human", Status: StatusFailed, Attempts: 3, At: at, Err: errors.New("dial tcp:\nrefused")},
+			"email:human failed attempts=3 at=2026-03-01T03:00:00Z error=dial tcp: refused",
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.o.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestOutcomeString_TruncatesOnRuneBoundary(t *testing.T) {
+	o := Outcome{Action: "slack", Status: StatusFailed, Attempts: 1, Err: errors.New("x" + strings.Repeat("é", 150))}
+	got := o.String()
+	if !utf8.ValidString(got) {
+		t.Fatalf("String() is not valid UTF-8: %q", got)
+	}
+	msg := got[strings.Index(got, "error=")+len("error="):]
+	if !strings.HasSuffix(msg, "...") || len(msg) > 203 {
+		t.Errorf("error = %q (%d bytes), want at most 200 bytes plus ...", msg, len(msg))
+	}
+}
+
+func TestIsExternal(t *testing.T) {
+	for action, want := range map[string]bool{
+		"email:human": true, "sms:human": true, "slack": true,
+		"bead": false, "mail:mayor": false, "log": false,
+	} {
+		if got := IsExternal(action); got != want {
+			t.Errorf("IsExternal(%q) = %v, want %v", action, got, want)
+		}
+	}
+}
+
+func TestDeliver_TransportErrorRedactsURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	webhook := srv.URL + "/services/T/B/secret"
+	srv.Close()
+
+	d := testDeliverer(config.EscalationContacts{SlackWebhook: webhook}, 1)
+	out := d.Deliver("slack", testNotice)
+	if out.Status != StatusFailed {
+		t.Fatalf("outcome = %s, want failed", out.Status)
+	}
+	if strings.Contains(out.Err.Error(), "secret") || strings.Contains(out.String(), "secret") {
+		t.Errorf("error leaks webhook path: %v", out.Err)
+	}
+}
+
+func TestDeliver_StopsAtDeadline(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	d := NewDeliverer(&config.EscalationConfig{
+		Contacts: config.EscalationContacts{SlackWebhook: srv.URL + "/hook"},
+		Delivery: &config.EscalationDelivery{MaxAttempts: 5, InitialBackoff: "1m", Deadline: "30s"},
+	})
+	d.sleep = func(time.Duration) { t.Error("slept past the deadline") }
+
+	out := d.Deliver("slack", testNotice)
+	if out.Status != StatusFailed || calls != 1 {
+		t.Errorf("outcome = %s after %d calls, want failed after 1", out.Status, calls)
+	}
+}
@@ -0,0 +1,126 @@
+package escalation
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// sendEmail delivers n to a single recipient through the configured SMTP server.
+func sendEmail(ctx context.Context, cfg *config.EscalationSMTP, to string, n Notice) error {
+	port := cfg.Port
+	if port == 0 {
+		port = 587
+		if cfg.TLS == "tls" {
+			port = 465
+		}
+	}
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(port))
+	tlsConfig := &tls.Config{ServerName: cfg.Host, InsecureSkipVerify: cfg.InsecureSkipVerify} //nolint:gosec // G402: opt-in for self-signed relays
+
+	dialer := &net.Dialer{}
+	var conn net.Conn
+	var err error
+	if cfg.TLS == "tls" {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("connecting to %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("smtp handshake with %s: %w", addr, err)
+	}
+	defer c.Close()
+
+	if cfg.TLS == "" || cfg.TLS == "starttls" {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(tlsConfig); err != nil {
+				return fmt.Errorf("starttls: %w", err)
+			}
+		} else if cfg.TLS == "starttls" {
+			return permanent(fmt.Errorf("%s does not offer STARTTLS (set contacts.smtp.tls to \"none\" for plaintext relays)", addr))
+		}
+	}
+
+	if cfg.Username != "" {
+		password := cfg.Password
+		if cfg.PasswordEnv != "" {
+			password = os.Getenv(cfg.PasswordEnv)
+		}
+		if err := c.Auth(smtp.PlainAuth("", cfg.Username, password, cfg.Host)); err != nil {
+			return smtpError("auth", err)
+		}
+	}
+
+	from := cfg.From
+	if from == "" {
+		from = cfg.Username
+	}
+	if err := c.Mail(from); err != nil {
+		return smtpError("MAIL FROM", err)
+	}
+	if err := c.Rcpt(to); err != nil {
+		return smtpError("RCPT TO", err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return smtpError("DATA", err)
+	}
+	if _, err := w.Write(formatEmail(from, to, n, time.Now())); err != nil {
+		return fmt.Errorf("writing message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return smtpError("DATA", err)
+	}
+	return c.Quit()
+}
+
+// smtpError wraps a server reply; 5xx codes are permanent and not retried.
+func smtpError(stage string, err error) error {
+	wrapped := fmt.Errorf("smtp %s: %w", stage, err)
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) && protoErr.Code >= 500 {
+		return permanent(wrapped)
+	}
+	return wrapped
+}
+
+// formatEmail renders an RFC 5322 message with a UTF-8 plain-text body.
+func formatEmail(from, to string, n Notice, now time.Time) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + to + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", n.subject()) + "\r\n")
+	b.WriteString("Date: " + now.Format(time.RFC1123Z) + "\r\n")
+	if n.BeadID != "" {
+		b.WriteString("X-Gastown-Escalation: " + n.BeadID + "\r\n")
+	}
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	for _, line := range strings.Split(n.Body, "\n") {
+		// Dot-stuffing is handled by the DATA writer; only normalize line endings.
+		b.WriteString(strings.TrimRight(line, "\r") + "\r\n")
+	}
+	return []byte(b.String())
+}
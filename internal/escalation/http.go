@@ -0,0 +1,155 @@
+package escalation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// defaultSMSBody is used when the gateway config has no body template.
+const defaultSMSBody = `{"to": {{json .To}}, "message": {{json .Message}}}`
+
+// smsMaxLen keeps SMS messages to a few segments; the full text is on the bead.
+const smsMaxLen = 320
+
+// smsData is the template context for SMS gateway URL and body templates.
+type smsData struct {
+	To       string
+	Message  string
+	Severity string
+	BeadID   string
+}
+
+var templateFuncs = template.FuncMap{
+	// json renders a value as a JSON literal, so templates can embed
+	// arbitrary text in a JSON body without breaking quoting.
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// postSlack posts n to a Slack incoming webhook.
+func postSlack(ctx context.Context, client *http.Client, webhook string, n Notice) error {
+	text := n.subject()
+	if n.Body != "" {
+		text += "\n```\n" + n.Body + "\n```"
+	}
+	payload, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return permanent(err)
+	}
+	return doHTTP(ctx, client, http.MethodPost, webhook, "application/json", nil, payload)
+}
+
+// sendSMS renders the gateway templates for n and sends the request.
+func sendSMS(ctx context.Context, client *http.Client, gw *config.EscalationSMSGateway, to string, n Notice) error {
+	msg := n.subject()
+	if n.BeadID != "" {
+		msg += " (" + n.BeadID + ")"
+	}
+	msg = truncateRunes(msg, smsMaxLen)
+	data := smsData{To: to, Message: msg, Severity: n.Severity, BeadID: n.BeadID}
+
+	// Values substituted into the URL are query-escaped, so a leading "+"
+	// in a phone number survives instead of decoding to a space.
+	urlData := smsData{
+		To:       url.QueryEscape(data.To),
+		Message:  url.QueryEscape(data.Message),
+		Severity: url.QueryEscape(data.Severity),
+		BeadID:   url.QueryEscape(data.BeadID),
+	}
+	gwURL, err := render("url", gw.URL, urlData)
+	if err != nil {
+		return permanent(err)
+	}
+	bodyTmpl := gw.Body
+	if bodyTmpl == "" {
+		bodyTmpl = defaultSMSBody
+	}
+	body, err := render("body", bodyTmpl, data)
+	if err != nil {
+		return permanent(err)
+	}
+
+	method := strings.ToUpper(gw.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+	contentType := gw.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	headers := make(map[string]string, len(gw.Headers))
+	for k, v := range gw.Headers {
+		headers[k] = os.ExpandEnv(v)
+	}
+	return doHTTP(ctx, client, method, gwURL, contentType, headers, []byte(body))
+}
+
+// truncateRunes shortens s to at most max bytes, cutting on a rune
+// boundary and marking the cut with "...".
+func truncateRunes(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	cut := max - 3
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "..."
+}
+
+func render(name, text string, data smsData) (string, error) {
+	t, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parsing sms_gateway.%s template: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("rendering sms_gateway.%s template: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// doHTTP sends a request and maps the response to an error.
+// 4xx responses other than 408 and 429 are permanent.
+func doHTTP(ctx context.Context, client *http.Client, method, rawURL, contentType string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return permanent(fmt.Errorf("building request: %w", util.RedactURLError(rawURL, err)))
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", "gastown-escalation")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return util.RedactURLError(rawURL, err)
+	}
+	defer resp.Body.Close()
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	err = fmt.Errorf("%s returned %d: %s", util.RedactSecretURL(rawURL), resp.StatusCode, strings.TrimSpace(string(snippet)))
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return permanent(err)
+	}
+	return err
+}
@@ -1,17 +1,14 @@
 // Package events provides event logging for the gt activity feed.
 //
-// Events are written to ~/gt/.events.jsonl (raw audit log) and later
-// curated by the feed daemon into ~/.feed.jsonl (user-facing).
+// Events are written to the event store in ~/gt/.events/ (raw audit log)
+// and later curated by the feed daemon into ~/.feed.jsonl (user-facing).
 package events
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
 	"time"
 
-	"github.com/gofrs/flock"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -25,6 +22,20 @@ type Event struct {
 	Visibility string                 `json:"visibility"`
 }
 
+// TimestampFormat is the layout of Event.Timestamp: RFC3339 in UTC with
+// microseconds. The fixed width keeps timestamps sortable as strings.
+const TimestampFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+// Time returns the event timestamp, or the zero time if it cannot be parsed.
+// Timestamps written before TimestampFormat (whole seconds) parse too.
+func (e Event) Time() time.Time {
+	t, err := time.Parse(time.RFC3339, e.Timestamp)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
 // Visibility levels for events.
 const (
 	VisibilityAudit = "audit" // Only in raw events log
@@ -71,17 +82,25 @@ const (
 	TypeMerged       = "merged"
 	TypeMergeFailed  = "merge_failed"
 	TypeMergeSkipped = "merge_skipped"
+
+	// Convoy events
+	TypeConvoyClosed = "convoy_closed"
+
+	// Dashboard events (audit-only, emitted by gt dashboard)
+	TypeDashboardAPI   = "dashboard_api"   // Mutating API call
+	TypeDashboardLogin = "dashboard_login" // Login attempt
 )
 
-// EventsFile is the name of the raw events log.
+// EventsFile is the name of the flat events log used before the event store.
+// An existing file is migrated into the store (see EventsDir).
 const EventsFile = ".events.jsonl"
 
 // Log writes an event to the events log.
-// The event is appended to ~/gt/.events.jsonl.
+// The event is appended to the town's event store.
 // Returns nil if logging fails (events are best-effort).
 func Log(eventType, actor string, payload map[string]interface{}, visibility string) error {
 	event := Event{
-		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		Timestamp:  time.Now().UTC().Format(TimestampFormat),
 		Source:     "gt",
 		Type:       eventType,
 		Actor:      actor,
@@ -101,9 +120,7 @@ func LogAudit(eventType, actor string, payload map[string]interface{}) error {
 	return Log(eventType, actor, payload, VisibilityAudit)
 }
 
-// write appends an event to the events file.
-// Uses flock for cross-process synchronization — sync.Mutex only protects
-// intra-process goroutines, but multiple gt processes write concurrently.
+// write appends an event to the town's event store.
 func write(event Event) error {
 	// Find town root
 	townRoot, err := workspace.FindFromCwd()
@@ -111,34 +128,7 @@ func write(event Event) error {
 		// Silently ignore - we're not in a Gas Town workspace
 		return nil
 	}
-
-	eventsPath := filepath.Join(townRoot, EventsFile)
-
-	// Marshal event to JSON
-	data, err := json.Marshal(event)
-	if err != nil {
-		return fmt.Errorf("marshaling event: %w", err)
-	}
-	data = append(data, '\n')
-
-	// Acquire cross-process file lock
-	fl := flock.New(eventsPath + ".lock")
-	if err := fl.Lock(); err != nil {
-		return fmt.Errorf("acquiring events file lock: %w", err)
-	}
-	defer fl.Unlock() //nolint:errcheck // best-effort unlock
-
-	f, err := os.OpenFile(eventsPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: events file is non-sensitive operational data
-	if err != nil {
-		return fmt.Errorf("opening events file: %w", err)
-	}
-	defer f.Close()
-
-	if _, err := f.Write(data); err != nil {
-		return fmt.Errorf("writing event: %w", err)
-	}
-
-	return nil
+	return OpenStore(townRoot).Append(event)
 }
 
 // Payload helpers for common event structures.
@@ -218,6 +208,33 @@ func MergePayload(mrID, worker, branch, reason string) map[string]interface{} {
 	return p
 }
 
+// ConvoyClosedPayload creates a payload for convoy_closed events.
+func ConvoyClosedPayload(convoyID, title, reason string) map[string]interface{} {
+	return map[string]interface{}{
+		"convoy": convoyID,
+		"title":  title,
+		"reason": reason,
+	}
+}
+
+// DashboardAPIPayload creates a payload for dashboard_api and dashboard_login events.
+// detail is the command run or target affected; status is the HTTP status returned.
+func DashboardAPIPayload(method, path, detail, role, remote string, status int) map[string]interface{} {
+	p := map[string]interface{}{
+		"method": method,
+		"path":   path,
+		"remote": remote,
+		"status": status,
+	}
+	if detail != "" {
+		p["detail"] = detail
+	}
+	if role != "" {
+		p["role"] = role
+	}
+	return p
+}
+
 // PatrolPayload creates a payload for patrol start/complete events.
 func PatrolPayload(rig string, polecatCount int, message string) map[string]interface{} {
 	p := map[string]interface{}{
@@ -0,0 +1,960 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// EventsDir is the directory of the segmented event store, relative to the
+// town root. It replaces the flat EventsFile, which is migrated into the
+// store the first time the store is used.
+const EventsDir = ".events"
+
+// Store rotation defaults. A segment is sealed when it reaches
+// DefaultMaxSegmentBytes or its first event is DefaultMaxSegmentAge old.
+const (
+	DefaultMaxSegmentBytes int64 = 16 << 20 // 16MB
+	DefaultMaxSegmentAge         = 24 * time.Hour
+)
+
+// SubscribePollInterval is how often Subscribe checks for new events.
+const SubscribePollInterval = 100 * time.Millisecond
+
+const (
+	indexFile     = "index.json"
+	storeLockFile = ".lock"
+	indexVersion  = 1
+)
+
+// Store is the town's event log: a directory of append-only JSONL segments.
+//
+// Events are appended to the active segment. When it grows past
+// MaxSegmentBytes or spans more than MaxSegmentAge it is sealed: its time
+// range and per-type and per-actor counts are recorded in index.json and a
+// new segment is started. Queries use the index to skip sealed segments that
+// cannot match, so readers no longer scan the town's whole history.
+//
+// Appends, sealing and pruning are serialized across processes by a flock in
+// the store directory. Readers take no lock: segments are only ever appended
+// to or replaced atomically, and the index is written atomically.
+type Store struct {
+	townRoot string
+	dir      string
+
+	// MaxSegmentBytes and MaxSegmentAge control rotation of the active
+	// segment. Zero values use the defaults.
+	MaxSegmentBytes int64
+	MaxSegmentAge   time.Duration
+}
+
+// OpenStore returns the event store of the town at townRoot. It does not
+// touch the filesystem; the store directory is created by the first Append.
+func OpenStore(townRoot string) *Store {
+	return &Store{
+		townRoot: townRoot,
+		dir:      filepath.Join(townRoot, EventsDir),
+	}
+}
+
+// Dir returns the store directory.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Exists reports whether the town has any events, in the store or in a
+// not yet migrated EventsFile.
+func (s *Store) Exists() bool {
+	if _, err := os.Stat(s.dir); err == nil {
+		return true
+	}
+	_, err := os.Stat(s.legacyPath())
+	return err == nil
+}
+
+// SegmentInfo describes a sealed segment in the index.
+type SegmentInfo struct {
+	Seq   int       `json:"seq"`
+	First time.Time `json:"first"`
+	Last  time.Time `json:"last"`
+	Count int       `json:"count"`
+	Size  int64     `json:"size"`
+	// Undated counts events whose timestamp could not be parsed. They are
+	// not reflected in First and Last.
+	Undated int            `json:"undated,omitempty"`
+	Types   map[string]int `json:"types"`
+	Actors  map[string]int `json:"actors"`
+}
+
+// storeIndex is the on-disk index of the store.
+type storeIndex struct {
+	Version int `json:"version"`
+	// Active is the sequence number of the segment being appended to.
+	Active   int           `json:"active"`
+	Segments []SegmentInfo `json:"segments"`
+}
+
+// Record is a stored event together with the line it was read from.
+type Record struct {
+	Event
+	Line string
+	// Pos is where the line starts in the store.
+	Pos Cursor
+}
+
+// Query selects events from the store. Zero fields match everything.
+type Query struct {
+	// Types matches events of any of these types.
+	Types []string
+	// Actors matches events from any of these actors, exactly.
+	Actors []string
+	// Since and Until bound the event time, inclusively. Events with an
+	// unparseable timestamp never match a time bound.
+	Since time.Time
+	Until time.Time
+	// Match is an additional predicate applied after the other filters.
+	Match func(Event) bool
+	// Limit keeps only the newest Limit matching events.
+	Limit int
+}
+
+// matches reports whether ev passes the query filters.
+func (q Query) matches(ev Event) bool {
+	if len(q.Types) > 0 && !slices.Contains(q.Types, ev.Type) {
+		return false
+	}
+	if len(q.Actors) > 0 && !slices.Contains(q.Actors, ev.Actor) {
+		return false
+	}
+	if !q.Since.IsZero() || !q.Until.IsZero() {
+		t := ev.Time()
+		if t.IsZero() || (!q.Since.IsZero() && t.Before(q.Since)) || (!q.Until.IsZero() && t.After(q.Until)) {
+			return false
+		}
+	}
+	return q.Match == nil || q.Match(ev)
+}
+
+// skips reports whether a sealed segment cannot contain a matching event.
+func (q Query) skips(seg SegmentInfo) bool {
+	if len(q.Types) > 0 && !slices.ContainsFunc(q.Types, func(t string) bool { return seg.Types[t] > 0 }) {
+		return true
+	}
+	if len(q.Actors) > 0 && !slices.ContainsFunc(q.Actors, func(a string) bool { return seg.Actors[a] > 0 }) {
+		return true
+	}
+	if seg.Count == seg.Undated {
+		// Only undated events: no time bound can match.
+		return !q.Since.IsZero() || !q.Until.IsZero()
+	}
+	if !q.Since.IsZero() && seg.Last.Before(q.Since) {
+		return true
+	}
+	return !q.Until.IsZero() && seg.First.After(q.Until)
+}
+
+// Append writes events to the active segment, sealing it first if it is
+// due for rotation.
+func (s *Store) Append(evs ...Event) error {
+	var buf bytes.Buffer
+	for _, ev := range evs {
+		data, err := json.Marshal(ev)
+		if err != nil {
+			return fmt.Errorf("marshaling event: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("creating events dir: %w", err)
+	}
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := s.migrateLocked(); err != nil {
+		return err
+	}
+	idx, err := s.loadIndex()
+	if err != nil {
+		return err
+	}
+	due, err := s.rotationDue(idx.Active)
+	if err != nil {
+		return err
+	}
+	if due {
+		if err := s.sealLocked(idx); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(s.segmentPath(idx.Active), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: events are non-sensitive operational data
+	if err != nil {
+		return fmt.Errorf("opening events segment: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("writing event: %w", err)
+	}
+	return nil
+}
+
+// Query returns the events matching q, oldest first.
+func (s *Store) Query(q Query) ([]Record, error) {
+	if err := s.migrate(); err != nil {
+		return nil, err
+	}
+	segs, err := s.segments()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Record
+	if q.Limit <= 0 {
+		for _, seg := range segs {
+			if seg.sealed && q.skips(seg.SegmentInfo) {
+				continue
+			}
+			if err := s.scanSegment(seg.Seq, q, func(r Record) bool {
+				out = append(out, r)
+				return true
+			}); err != nil {
+				return nil, err
+			}
+		}
+		sortRecords(out)
+		return out, nil
+	}
+
+	// With a limit, read the newest segments first and stop once enough
+	// events have been found.
+	for i := len(segs) - 1; i >= 0 && len(out) < q.Limit; i-- {
+		seg := segs[i]
+		if seg.sealed && q.skips(seg.SegmentInfo) {
+			continue
+		}
+		var found []Record
+		if err := s.scanSegment(seg.Seq, q, func(r Record) bool {
+			found = append(found, r)
+			return true
+		}); err != nil {
+			return nil, err
+		}
+		out = append(found, out...)
+	}
+	sortRecords(out)
+	if len(out) > q.Limit {
+		out = out[len(out)-q.Limit:]
+	}
+	return out, nil
+}
+
+// Each calls fn for every event matching q in storage order, stopping early
+// if fn returns false. Unlike Query it does not hold the results in memory,
+// so q.Limit is ignored.
+func (s *Store) Each(q Query, fn func(Record) bool) error {
+	if err := s.migrate(); err != nil {
+		return err
+	}
+	segs, err := s.segments()
+	if err != nil {
+		return err
+	}
+	stopped := false
+	for _, seg := range segs {
+		if stopped {
+			break
+		}
+		if seg.sealed && q.skips(seg.SegmentInfo) {
+			continue
+		}
+		if err := s.scanSegment(seg.Seq, q, func(r Record) bool {
+			if !fn(r) {
+				stopped = true
+			}
+			return !stopped
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortRecords orders records, given in storage order, by event time. An
+// undated record takes the time of the dated record stored before it, so
+// it stays next to its neighbours; ties fall back to segment, then offset.
+func sortRecords(recs []Record) {
+	type keyed struct {
+		at  time.Time
+		rec Record
+	}
+	keys := make([]keyed, len(recs))
+	var last time.Time
+	for i, r := range recs {
+		if t := r.Time(); !t.IsZero() {
+			last = t
+		}
+		keys[i] = keyed{at: last, rec: r}
+	}
+	slices.SortFunc(keys, func(a, b keyed) int {
+		if c := a.at.Compare(b.at); c != 0 {
+			return c
+		}
+		if a.rec.Pos.Seq != b.rec.Pos.Seq {
+			return a.rec.Pos.Seq - b.rec.Pos.Seq
+		}
+		return int(a.rec.Pos.Offset - b.rec.Pos.Offset)
+	})
+	for i, k := range keys {
+		recs[i] = k.rec
+	}
+}
+
+// Cursor is a position in the store: a segment and a byte offset into it.
+type Cursor struct {
+	Seq    int
+	Offset int64
+}
+
+// String formats the cursor as "<seq>:<offset>" for persisting.
+func (c Cursor) String() string {
+	return fmt.Sprintf("%d:%d", c.Seq, c.Offset)
+}
+
+// ParseCursor parses a cursor formatted by Cursor.String.
+func ParseCursor(s string) (Cursor, error) {
+	seq, off, ok := strings.Cut(s, ":")
+	if !ok {
+		return Cursor{}, fmt.Errorf("invalid events cursor %q", s)
+	}
+	n, err := strconv.Atoi(seq)
+	if err != nil || n < 1 {
+		return Cursor{}, fmt.Errorf("invalid events cursor %q", s)
+	}
+	o, err := strconv.ParseInt(off, 10, 64)
+	if err != nil || o < 0 {
+		return Cursor{}, fmt.Errorf("invalid events cursor %q", s)
+	}
+	return Cursor{Seq: n, Offset: o}, nil
+}
+
+// End returns the cursor just past the newest event.
+func (s *Store) End() (Cursor, error) {
+	if err := s.migrate(); err != nil {
+		return Cursor{}, err
+	}
+	idx, err := s.loadIndex()
+	if err != nil {
+		return Cursor{}, err
+	}
+	c := Cursor{Seq: idx.Active}
+	if info, err := os.Stat(s.segmentPath(idx.Active)); err == nil {
+		c.Offset = info.Size()
+	}
+	return c, nil
+}
+
+// Tailer reads events appended to the store after a cursor.
+type Tailer struct {
+	store *Store
+	query Query
+	pos   Cursor
+}
+
+// Tail returns a tailer that reads events after from that match q. q.Limit
+// is ignored.
+func (s *Store) Tail(from Cursor, q Query) *Tailer {
+	if from.Seq < 1 {
+		from = Cursor{Seq: 1}
+	}
+	return &Tailer{store: s, query: q, pos: from}
+}
+
+// Cursor returns the position after the last event returned by Next.
+func (t *Tailer) Cursor() Cursor {
+	return t.pos
+}
+
+// Next returns the complete events written since the previous call and
+// advances the cursor past them, following the tailer into newer segments
+// as the store rotates. A partially written line is left for the next call.
+func (t *Tailer) Next() ([]Record, error) {
+	s := t.store
+	if err := s.migrate(); err != nil {
+		return nil, err
+	}
+	var out []Record
+	for {
+		// A newer segment means the current one is sealed. Checking before
+		// reading ensures nothing written before the seal is skipped.
+		next, hasNext, err := s.nextSeq(t.pos.Seq)
+		if err != nil {
+			return out, err
+		}
+		recs, offset, err := s.readFrom(t.pos, t.query)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, recs...)
+		t.pos.Offset = offset
+		if !hasNext {
+			return out, nil
+		}
+		t.pos = Cursor{Seq: next}
+	}
+}
+
+// Subscribe streams events matching q that are appended after from, until
+// ctx is done. The channel is closed when ctx is done. Read errors are
+// retried on the next poll.
+func (s *Store) Subscribe(ctx context.Context, from Cursor, q Query) <-chan Record {
+	ch := make(chan Record, 64)
+	t := s.Tail(from, q)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(SubscribePollInterval)
+		defer ticker.Stop()
+		for {
+			recs, _ := t.Next()
+			for _, r := range recs {
+				select {
+				case ch <- r:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return ch
+}
+
+// PruneResult summarizes a Prune.
+type PruneResult struct {
+	Processed    int
+	Pruned       int
+	BytesBefore  int64
+	BytesAfter   int64
+	PrunedByType map[string]int
+}
+
+// Prune removes events for which expired returns true. Sealed segments whose
+// every event has expired are deleted without being read, and segments
+// that cannot contain an expired event are left alone. If the active
+// segment holds expired events it is sealed first, so tailers following it
+// are not disturbed. Events with an unparseable timestamp are kept.
+func (s *Store) Prune(expired func(eventType string, ts time.Time) bool) (*PruneResult, error) {
+	result := &PruneResult{PrunedByType: make(map[string]int)}
+	if !s.Exists() {
+		return result, nil
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating events dir: %w", err)
+	}
+	unlock, err := s.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if err := s.migrateLocked(); err != nil {
+		return nil, err
+	}
+	idx, err := s.loadIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := s.scanInfo(idx.Active)
+	if err != nil {
+		return nil, err
+	}
+	if active.Count > 0 && mayExpire(active, expired) {
+		if err := s.sealLocked(idx); err != nil {
+			return nil, err
+		}
+	} else {
+		result.Processed += active.Count
+		result.BytesBefore += active.Size
+		result.BytesAfter += active.Size
+	}
+
+	var kept []SegmentInfo
+	for _, seg := range idx.Segments {
+		result.Processed += seg.Count
+		result.BytesBefore += seg.Size
+		switch {
+		case allExpired(seg, expired):
+			if err := os.Remove(s.segmentPath(seg.Seq)); err != nil && !os.IsNotExist(err) {
+				return nil, fmt.Errorf("removing events segment: %w", err)
+			}
+			result.Pruned += seg.Count
+			for typ, n := range seg.Types {
+				result.PrunedByType[typ] += n
+			}
+			continue
+		case mayExpire(seg, expired):
+			pruned, err := s.rewriteSegment(&seg, expired)
+			if err != nil {
+				return nil, err
+			}
+			result.Pruned += len(pruned)
+			for _, typ := range pruned {
+				result.PrunedByType[typ]++
+			}
+			if seg.Count == 0 {
+				if err := os.Remove(s.segmentPath(seg.Seq)); err != nil && !os.IsNotExist(err) {
+					return nil, fmt.Errorf("removing events segment: %w", err)
+				}
+				continue
+			}
+		}
+		result.BytesAfter += seg.Size
+		kept = append(kept, seg)
+	}
+	idx.Segments = kept
+	if err := s.saveIndex(idx); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// mayExpire reports whether a segment could hold an expired event.
+func mayExpire(seg SegmentInfo, expired func(string, time.Time) bool) bool {
+	if seg.Count == seg.Undated {
+		return false
+	}
+	for typ := range seg.Types {
+		if expired(typ, seg.First) {
+			return true
+		}
+	}
+	return false
+}
+
+// allExpired reports whether every event of a segment has expired.
+func allExpired(seg SegmentInfo, expired func(string, time.Time) bool) bool {
+	if seg.Count == 0 || seg.Undated > 0 {
+		return false
+	}
+	for typ := range seg.Types {
+		if !expired(typ, seg.Last) {
+			return false
+		}
+	}
+	return true
+}
+
+// rewriteSegment drops expired events from a sealed segment, replacing the
+// file atomically and updating seg. It returns the types of the dropped
+// events.
+func (s *Store) rewriteSegment(seg *SegmentInfo, expired func(string, time.Time) bool) ([]string, error) {
+	data, err := os.ReadFile(s.segmentPath(seg.Seq))
+	if err != nil {
+		return nil, fmt.Errorf("reading events segment: %w", err)
+	}
+	var kept bytes.Buffer
+	var pruned []string
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var ev Event
+		if json.Unmarshal(line, &ev) == nil {
+			if t := ev.Time(); !t.IsZero() && expired(ev.Type, t) {
+				pruned = append(pruned, ev.Type)
+				continue
+			}
+		}
+		kept.Write(line)
+	}
+	if err := util.AtomicWriteFile(s.segmentPath(seg.Seq), kept.Bytes(), 0644); err != nil {
+		return nil, fmt.Errorf("rewriting events segment: %w", err)
+	}
+	info, err := s.scanInfo(seg.Seq)
+	if err != nil {
+		return nil, err
+	}
+	*seg = info
+	return pruned, nil
+}
+
+// StoreStats summarizes the store.
+type StoreStats struct {
+	Dir      string
+	Size     int64
+	Count    int
+	Segments int
+}
+
+// Stats returns the size of the store. Sealed segments are counted from the
+// index; only the active segment is read.
+func (s *Store) Stats() (StoreStats, error) {
+	stats := StoreStats{Dir: s.dir}
+	if err := s.migrate(); err != nil {
+		return stats, err
+	}
+	segs, err := s.segments()
+	if err != nil {
+		return stats, err
+	}
+	for _, seg := range segs {
+		stats.Size += seg.Size
+		stats.Count += seg.Count
+		stats.Segments++
+	}
+	return stats, nil
+}
+
+// segment is a sealed segment from the index, or the active segment with
+// info scanned from disk.
+type segment struct {
+	SegmentInfo
+	sealed bool
+}
+
+// segments lists the sealed segments and the active segment, oldest first.
+func (s *Store) segments() ([]segment, error) {
+	idx, err := s.loadIndex()
+	if err != nil {
+		return nil, err
+	}
+	segs := make([]segment, 0, len(idx.Segments)+1)
+	for _, info := range idx.Segments {
+		segs = append(segs, segment{SegmentInfo: info, sealed: true})
+	}
+	active, err := s.scanInfo(idx.Active)
+	if err != nil {
+		return nil, err
+	}
+	if active.Count > 0 {
+		segs = append(segs, segment{SegmentInfo: active})
+	}
+	return segs, nil
+}
+
+// nextSeq returns the first existing segment after seq. The common case of a
+// plain rotation is answered with a stat; the index is consulted only when
+// seq itself is gone, i.e. it was pruned.
+func (s *Store) nextSeq(seq int) (int, bool, error) {
+	if _, err := os.Stat(s.segmentPath(seq + 1)); err == nil {
+		return seq + 1, true, nil
+	}
+	if _, err := os.Stat(s.segmentPath(seq)); err == nil {
+		return 0, false, nil
+	}
+	idx, err := s.loadIndex()
+	if err != nil {
+		return 0, false, err
+	}
+	for _, seg := range idx.Segments {
+		if seg.Seq > seq {
+			return seg.Seq, true, nil
+		}
+	}
+	if idx.Active > seq {
+		return idx.Active, true, nil
+	}
+	return 0, false, nil
+}
+
+// readFrom reads the complete lines of a segment after pos and returns the
+// matching records and the offset after the last complete line. A cursor
+// beyond the end of the segment (the segment was rewritten by Prune) reads
+// nothing.
+func (s *Store) readFrom(pos Cursor, q Query) ([]Record, int64, error) {
+	f, err := os.Open(s.segmentPath(pos.Seq))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, pos.Offset, nil
+		}
+		return nil, pos.Offset, err
+	}
+	defer f.Close()
+	if _, err := f.Seek(pos.Offset, io.SeekStart); err != nil {
+		return nil, pos.Offset, err
+	}
+
+	var out []Record
+	offset := pos.Offset
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			// EOF, possibly after a partial line still being written.
+			return out, offset, nil
+		}
+		if rec, ok := parseRecord(line); ok && q.matches(rec.Event) {
+			rec.Pos = Cursor{Seq: pos.Seq, Offset: offset}
+			out = append(out, rec)
+		}
+		offset += int64(len(line))
+	}
+}
+
+// scanSegment calls fn for every event in a segment that matches q.
+func (s *Store) scanSegment(seq int, q Query, fn func(Record) bool) error {
+	f, err := os.Open(s.segmentPath(seq))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // pruned since the index was read
+		}
+		return fmt.Errorf("opening events segment: %w", err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var offset int64
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 && err == nil {
+			if rec, ok := parseRecord(line); ok && q.matches(rec.Event) {
+				rec.Pos = Cursor{Seq: seq, Offset: offset}
+				if !fn(rec) {
+					return nil
+				}
+			}
+		}
+		offset += int64(len(line))
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading events segment: %w", err)
+		}
+	}
+}
+
+// parseRecord parses one segment line.
+func parseRecord(line []byte) (Record, bool) {
+	line = bytes.TrimRight(line, "\r\n")
+	if len(bytes.TrimSpace(line)) == 0 {
+		return Record{}, false
+	}
+	var ev Event
+	if err := json.Unmarshal(line, &ev); err != nil {
+		return Record{}, false
+	}
+	return Record{Event: ev, Line: string(line)}, true
+}
+
+// scanInfo reads a segment and summarizes it. A missing segment yields an
+// empty summary.
+func (s *Store) scanInfo(seq int) (SegmentInfo, error) {
+	info := SegmentInfo{Seq: seq, Types: map[string]int{}, Actors: map[string]int{}}
+	f, err := os.Open(s.segmentPath(seq))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return info, nil
+		}
+		return info, fmt.Errorf("opening events segment: %w", err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		info.Size += int64(len(line))
+		if rec, ok := parseRecord(line); ok {
+			info.Count++
+			info.Types[rec.Type]++
+			info.Actors[rec.Actor]++
+			t := rec.Time()
+			switch {
+			case t.IsZero():
+				info.Undated++
+			case info.First.IsZero() || t.Before(info.First):
+				info.First = t
+				if info.Last.IsZero() {
+					info.Last = t
+				}
+			case t.After(info.Last):
+				info.Last = t
+			}
+		}
+		if err == io.EOF {
+			return info, nil
+		}
+		if err != nil {
+			return info, fmt.Errorf("reading events segment: %w", err)
+		}
+	}
+}
+
+// rotationDue reports whether the active segment should be sealed before
+// the next append.
+func (s *Store) rotationDue(seq int) (bool, error) {
+	f, err := os.Open(s.segmentPath(seq))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+	if info.Size() == 0 {
+		return false, nil
+	}
+	maxBytes := s.MaxSegmentBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxSegmentBytes
+	}
+	if info.Size() >= maxBytes {
+		return true, nil
+	}
+
+	maxAge := s.MaxSegmentAge
+	if maxAge <= 0 {
+		maxAge = DefaultMaxSegmentAge
+	}
+	first, err := bufio.NewReader(f).ReadBytes('\n')
+	if err != nil {
+		return false, nil
+	}
+	rec, ok := parseRecord(first)
+	if !ok {
+		return false, nil
+	}
+	t := rec.Time()
+	return !t.IsZero() && time.Since(t) >= maxAge, nil
+}
+
+// sealLocked records the active segment in the index and starts a new one.
+// An empty active segment is left as is. The caller holds the store lock.
+func (s *Store) sealLocked(idx *storeIndex) error {
+	info, err := s.scanInfo(idx.Active)
+	if err != nil {
+		return err
+	}
+	if info.Count == 0 {
+		return nil
+	}
+	idx.Segments = append(idx.Segments, info)
+	idx.Active++
+	return s.saveIndex(idx)
+}
+
+// migrate folds a legacy EventsFile into the store. It is cheap when there
+// is nothing to migrate, so every store operation calls it: events appended
+// by older gt binaries during an upgrade are picked up as they appear.
+func (s *Store) migrate() error {
+	if _, err := os.Stat(s.legacyPath()); err != nil {
+		return nil
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("creating events dir: %w", err)
+	}
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return s.migrateLocked()
+}
+
+// migrateLocked moves the legacy EventsFile in as a sealed segment after the
+// active one. It holds the legacy file's lock too, so older writers are not
+// mid-append. The caller holds the store lock.
+func (s *Store) migrateLocked() error {
+	legacy := s.legacyPath()
+	if _, err := os.Stat(legacy); err != nil {
+		return nil
+	}
+	fl := flock.New(legacy + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("acquiring events file lock: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	info, err := os.Stat(legacy)
+	if err != nil {
+		return nil // migrated by another process meanwhile
+	}
+	if info.Size() == 0 {
+		return os.Remove(legacy)
+	}
+
+	idx, err := s.loadIndex()
+	if err != nil {
+		return err
+	}
+	// Seal the active segment so the legacy events get a segment of their own.
+	if err := s.sealLocked(idx); err != nil {
+		return err
+	}
+	seq := idx.Active
+	if err := os.Rename(legacy, s.segmentPath(seq)); err != nil {
+		return fmt.Errorf("migrating %s: %w", EventsFile, err)
+	}
+	return s.sealLocked(idx)
+}
+
+// lock takes the cross-process store lock and returns its release func.
+func (s *Store) lock() (func(), error) {
+	fl := flock.New(filepath.Join(s.dir, storeLockFile))
+	if err := fl.Lock(); err != nil {
+		return nil, fmt.Errorf("acquiring events store lock: %w", err)
+	}
+	return func() { _ = fl.Unlock() }, nil
+}
+
+// loadIndex reads the index, returning a fresh one if none exists.
+func (s *Store) loadIndex() (*storeIndex, error) {
+	idx := &storeIndex{Version: indexVersion, Active: 1}
+	data, err := os.ReadFile(filepath.Join(s.dir, indexFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return idx, nil
+		}
+		return nil, fmt.Errorf("reading events index: %w", err)
+	}
+	if err := json.Unmarshal(data, idx); err != nil {
+		return nil, fmt.Errorf("parsing events index: %w", err)
+	}
+	if idx.Active < 1 {
+		idx.Active = 1
+	}
+	return idx, nil
+}
+
+// saveIndex writes the index atomically.
+func (s *Store) saveIndex(idx *storeIndex) error {
+	idx.Version = indexVersion
+	if err := util.AtomicWriteJSON(filepath.Join(s.dir, indexFile), idx); err != nil {
+		return fmt.Errorf("writing events index: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) segmentPath(seq int) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%08d.jsonl", seq))
+}
+
+func (s *Store) legacyPath() string {
+	return filepath.Join(s.townRoot, EventsFile)
+}
@@ -0,0 +1,289 @@
+package events
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func testEvent(typ, actor string, at time.Time) Event {
+	return Event{
+		Timestamp:  at.UTC().Format(TimestampFormat),
+		Source:     "gt",
+		Type:       typ,
+		Actor:      actor,
+		Visibility: VisibilityFeed,
+	}
+}
+
+func recordTypes(recs []Record) []string {
+	var types []string
+	for _, r := range recs {
+		types = append(types, r.Type)
+	}
+	return types
+}
+
+func TestStore_QueryFilters(t *testing.T) {
+	store := OpenStore(t.TempDir())
+	base := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	if err := store.Append(
+		testEvent(TypeSling, "mayor", base),
+		testEvent(TypeHook, "gastown/polecats/toast", base.Add(time.Minute)),
+		testEvent(TypeDone, "gastown/polecats/toast", base.Add(2*time.Minute)),
+		testEvent(TypeSling, "gastown/crew/max", base.Add(3*time.Minute)),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		q    Query
+		want []string
+	}{
+		{"all", Query{}, []string{TypeSling, TypeHook, TypeDone, TypeSling}},
+		{"types", Query{Types: []string{TypeHook, TypeDone}}, []string{TypeHook, TypeDone}},
+		{"actors", Query{Actors: []string{"mayor"}}, []string{TypeSling}},
+		{"since", Query{Since: base.Add(2 * time.Minute)}, []string{TypeDone, TypeSling}},
+		{"until", Query{Until: base.Add(time.Minute)}, []string{TypeSling, TypeHook}},
+		{"limit keeps newest", Query{Limit: 2}, []string{TypeDone, TypeSling}},
+		{"match", Query{Match: func(e Event) bool { return e.Actor != "mayor" }}, []string{TypeHook, TypeDone, TypeSling}},
+	}
+	for _, tt := range tests {
+		recs, err := store.Query(tt.q)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := recordTypes(recs); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSortRecords_UndatedKeepTheirPlace(t *testing.T) {
+	base := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	rec := func(typ string, at time.Time, seq int, off int64) Record {
+		ev := testEvent(typ, "a", at)
+		if at.IsZero() {
+			ev.Timestamp = ""
+		}
+		return Record{Event: ev, Pos: Cursor{Seq: seq, Offset: off}}
+	}
+	// Storage order; "late" was written before "early" with a later time,
+	// and each undated record follows the dated record it was stored after.
+	recs := []Record{
+		rec("late", base.Add(2*time.Minute), 1, 0),
+		rec("undated-a", time.Time{}, 1, 100),
+		rec("early", base, 2, 0),
+		rec("undated-b", time.Time{}, 2, 100),
+		rec("tie", base, 2, 200),
+	}
+	sortRecords(recs)
+	want := []string{"early", "undated-b", "tie", "late", "undated-a"}
+	if got := recordTypes(recs); !slices.Equal(got, want) {
+		t.Errorf("sorted = %v, want %v", got, want)
+	}
+}
+
+func TestStore_RotatesBySize(t *testing.T) {
+	store := OpenStore(t.TempDir())
+	store.MaxSegmentBytes = 1 // every append after the first seals the segment
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < 3; i++ {
+		if err := store.Append(testEvent(TypeSling, "mayor", base.Add(time.Duration(i)*time.Minute))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Append(testEvent(TypeDone, "mayor", base.Add(5*time.Minute))); err != nil {
+		t.Fatal(err)
+	}
+
+	idx, err := store.loadIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(idx.Segments) != 3 || idx.Active != 4 {
+		t.Fatalf("index = %d sealed, active %d; want 3 sealed, active 4", len(idx.Segments), idx.Active)
+	}
+	if idx.Segments[0].Types[TypeSling] != 1 || idx.Segments[0].Actors["mayor"] != 1 {
+		t.Errorf("sealed segment info = %+v", idx.Segments[0])
+	}
+
+	recs, err := store.Query(Query{Types: []string{TypeDone}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 1 {
+		t.Errorf("done events = %d, want 1", len(recs))
+	}
+	stats, err := store.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Count != 4 || stats.Segments != 4 {
+		t.Errorf("stats = %+v, want 4 events in 4 segments", stats)
+	}
+}
+
+func TestStore_RotatesByAge(t *testing.T) {
+	store := OpenStore(t.TempDir())
+	store.MaxSegmentAge = time.Hour
+	if err := store.Append(testEvent(TypeSling, "mayor", time.Now().Add(-2*time.Hour))); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Append(testEvent(TypeSling, "mayor", time.Now())); err != nil {
+		t.Fatal(err)
+	}
+	idx, err := store.loadIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(idx.Segments) != 1 {
+		t.Errorf("sealed segments = %d, want 1", len(idx.Segments))
+	}
+}
+
+func TestStore_TailFollowsRotation(t *testing.T) {
+	store := OpenStore(t.TempDir())
+	store.MaxSegmentBytes = 1
+	now := time.Now()
+	if err := store.Append(testEvent(TypeSling, "old", now)); err != nil {
+		t.Fatal(err)
+	}
+	end, err := store.End()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tail := store.Tail(end, Query{})
+	if recs, _ := tail.Next(); len(recs) != 0 {
+		t.Fatalf("tail from end returned %v", recordTypes(recs))
+	}
+
+	for _, typ := range []string{TypeHook, TypeDone, TypeUnhook} {
+		if err := store.Append(testEvent(typ, "new", now)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A partially written line is not returned until it is complete.
+	f, err := os.OpenFile(store.segmentPath(4), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(`{"type":"mer`); err != nil {
+		t.Fatal(err)
+	}
+
+	recs, err := tail.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := recordTypes(recs), []string{TypeHook, TypeDone, TypeUnhook}; !slices.Equal(got, want) {
+		t.Fatalf("tail = %v, want %v", got, want)
+	}
+
+	if _, err := f.WriteString(`ged"}` + "\n"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	recs, err = tail.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := recordTypes(recs); !slices.Equal(got, []string{TypeMerged}) {
+		t.Errorf("tail after completing line = %v, want [merged]", got)
+	}
+
+	c, err := ParseCursor(tail.Cursor().String())
+	if err != nil || c != tail.Cursor() {
+		t.Errorf("cursor round trip = %v, %v", c, err)
+	}
+}
+
+func TestStore_Subscribe(t *testing.T) {
+	store := OpenStore(t.TempDir())
+	end, err := store.End()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ch := store.Subscribe(ctx, end, Query{Types: []string{TypeDone}})
+
+	if err := store.Append(testEvent(TypeSling, "mayor", time.Now()), testEvent(TypeDone, "mayor", time.Now())); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case r := <-ch:
+		if r.Type != TypeDone || r.Line == "" {
+			t.Errorf("subscribed record = %+v", r)
+		}
+	case <-ctx.Done():
+		t.Fatal("no event from Subscribe")
+	}
+}
+
+func TestStore_MigratesLegacyFile(t *testing.T) {
+	town := t.TempDir()
+	legacy := filepath.Join(town, EventsFile)
+	old := `{"ts":"2026-01-02T03:04:05Z","type":"sling","actor":"mayor"}` + "\n"
+	if err := os.WriteFile(legacy, []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := OpenStore(town)
+	if err := store.Append(testEvent(TypeDone, "mayor", time.Now())); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("legacy events file still present: %v", err)
+	}
+	recs, err := store.Query(Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := recordTypes(recs); !slices.Equal(got, []string{TypeSling, TypeDone}) {
+		t.Errorf("events after migration = %v, want [sling done]", got)
+	}
+	if recs[0].Time().IsZero() {
+		t.Error("whole-second legacy timestamp should parse")
+	}
+}
+
+func TestStore_Prune(t *testing.T) {
+	store := OpenStore(t.TempDir())
+	now := time.Now()
+	old := now.Add(-10 * 24 * time.Hour)
+	if err := store.Append(testEvent(TypeNudge, "a", old), testEvent(TypeNudge, "b", old.Add(time.Hour))); err != nil {
+		t.Fatal(err)
+	}
+	// Seal the old events into their own segment, then add fresh ones.
+	idx, _ := store.loadIndex()
+	if err := store.sealLocked(idx); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Append(testEvent(TypeNudge, "c", old), testEvent(TypeMail, "d", now)); err != nil {
+		t.Fatal(err)
+	}
+
+	expired := func(_ string, ts time.Time) bool { return now.Sub(ts) > 7*24*time.Hour }
+	result, err := store.Prune(expired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Processed != 4 || result.Pruned != 3 || result.PrunedByType[TypeNudge] != 3 {
+		t.Errorf("prune result = %+v, want 3 of 4 nudges pruned", result)
+	}
+	recs, err := store.Query(Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := recordTypes(recs); !slices.Equal(got, []string{TypeMail}) {
+		t.Errorf("events after prune = %v, want [mail]", got)
+	}
+	if _, err := os.Stat(store.segmentPath(1)); !os.IsNotExist(err) {
+		t.Error("fully expired segment should be removed")
+	}
+}
@@ -0,0 +1,293 @@
+package fakeagent
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SessionIDEnv carries the fake agent's session ID to hooks and child
+// commands, matching the scripted preset's SessionIDEnv.
+const SessionIDEnv = "GT_FAKE_AGENT_SESSION_ID"
+
+// errStepFailed stops the script after a failing step without allow_failure.
+var errStepFailed = errors.New("step failed")
+
+// Agent replays a script in a work directory.
+type Agent struct {
+	Script    *Script
+	Hooks     *Hooks
+	Dir       string
+	Env       []string
+	SessionID string
+	Stdin     io.Reader
+	Stdout    io.Writer
+	Stderr    io.Writer
+
+	input chan string
+}
+
+// exitError ends the run with a specific status.
+type exitError struct{ code int }
+
+func (e *exitError) Error() string { return fmt.Sprintf("exit %d", e.code) }
+
+// Run executes the script and returns the process exit status. prompt is the
+// initial prompt passed on the command line (the startup beacon), if any.
+func (a *Agent) Run(prompt string) int {
+	a.input = make(chan string)
+	go a.readInput()
+
+	if a.Script.StartupDelay > 0 {
+		time.Sleep(a.Script.StartupDelay)
+	}
+	a.runHooks(HookInput{Event: EventSessionStart, Source: "startup"}, "")
+	if prompt != "" {
+		fmt.Fprintf(a.Stdout, "> %s\n", prompt)
+		a.runHooks(HookInput{Event: EventUserPromptSubmit, Prompt: prompt}, "")
+	}
+
+	for i := range a.Script.Steps {
+		if err := a.step(&a.Script.Steps[i]); err != nil {
+			var exit *exitError
+			switch {
+			case errors.As(err, &exit):
+				return exit.code
+			case errors.Is(err, io.EOF):
+				return 0
+			default:
+				fmt.Fprintf(a.Stderr, "fake agent: step %d (%s): %v\n", i+1, a.Script.Steps[i].Action, err)
+				return 1
+			}
+		}
+	}
+	if a.Script.ExitWhenDone {
+		return 0
+	}
+	// Out of script: sit at the prompt like an agent waiting for work.
+	for {
+		if err := a.idle(&Step{Action: ActionIdle}); err != nil {
+			return 0
+		}
+	}
+}
+
+func (a *Agent) step(s *Step) error {
+	switch s.Action {
+	case ActionRun:
+		return a.tool(s, "Bash("+s.Command+")", s.Command)
+	case ActionDone:
+		command := strings.Join(append([]string{"gt", "done"}, s.Args...), " ")
+		return a.tool(s, "Bash("+command+")", command)
+	case ActionCommit:
+		command := "git add -A && git commit -q -m " + shellQuote(s.Message)
+		return a.tool(s, "Bash("+command+")", command)
+	case ActionWrite:
+		return a.write(s)
+	case ActionEdit:
+		return a.edit(s)
+	case ActionSay, ActionQuota:
+		fmt.Fprintf(a.Stdout, "● %s\n", s.Message)
+		return nil
+	case ActionIdle:
+		return a.idle(s)
+	case ActionCrash:
+		if s.Message != "" {
+			fmt.Fprintln(a.Stderr, s.Message)
+		}
+		return &exitError{code: s.ExitCode}
+	}
+	return fmt.Errorf("unknown action %q", s.Action)
+}
+
+// tool runs a shell command as a Bash tool call, subject to PreToolUse hooks.
+func (a *Agent) tool(s *Step, target, command string) error {
+	fmt.Fprintf(a.Stdout, "● %s\n", target)
+	if a.runHooks(HookInput{
+		Event:     EventPreToolUse,
+		ToolName:  "Bash",
+		ToolInput: map[string]any{"command": command},
+	}, target) {
+		return a.failed(s, fmt.Errorf("blocked by PreToolUse hook"))
+	}
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Dir = a.Dir
+	cmd.Env = a.Env
+	cmd.Stdout = a.Stdout
+	cmd.Stderr = a.Stderr
+	if err := cmd.Run(); err != nil {
+		return a.failed(s, err)
+	}
+	return nil
+}
+
+func (a *Agent) failed(s *Step, err error) error {
+	fmt.Fprintf(a.Stdout, "  ⎿ %v\n", err)
+	if s.AllowFailure {
+		return nil
+	}
+	return fmt.Errorf("%w: %v", errStepFailed, err)
+}
+
+func (a *Agent) path(p string) string {
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(a.Dir, p)
+}
+
+func (a *Agent) write(s *Step) error {
+	fmt.Fprintf(a.Stdout, "● Write(%s)\n", s.Path)
+	p := a.path(s.Path)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if s.Append {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	f, err := os.OpenFile(p, flags, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(s.Content); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (a *Agent) edit(s *Step) error {
+	fmt.Fprintf(a.Stdout, "● Edit(%s)\n", s.Path)
+	p := a.path(s.Path)
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(string(data), s.Old) {
+		return fmt.Errorf("%s: text to replace not found", s.Path)
+	}
+	return os.WriteFile(p, []byte(strings.Replace(string(data), s.Old, s.New, 1)), 0644)
+}
+
+// idle runs Stop hooks, shows the ready prompt and waits for a matching input
+// line, which is then submitted as a prompt. It returns io.EOF when stdin
+// closes.
+func (a *Agent) idle(s *Step) error {
+	a.runHooks(HookInput{Event: EventStop}, "")
+	fmt.Fprint(a.Stdout, a.Script.Prompt)
+
+	var timeout <-chan time.Time
+	if s.Timeout > 0 {
+		timer := time.NewTimer(s.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	for {
+		select {
+		case line, ok := <-a.input:
+			if !ok {
+				return io.EOF
+			}
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			a.runHooks(HookInput{Event: EventUserPromptSubmit, Prompt: line}, "")
+			if s.until == nil || s.until.MatchString(line) {
+				return nil
+			}
+			fmt.Fprint(a.Stdout, a.Script.Prompt)
+		case <-timeout:
+			fmt.Fprintln(a.Stdout)
+			return nil
+		}
+	}
+}
+
+func (a *Agent) readInput() {
+	defer close(a.input)
+	if a.Stdin == nil {
+		return
+	}
+	sc := bufio.NewScanner(a.Stdin)
+	for sc.Scan() {
+		a.input <- sc.Text()
+	}
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// newSessionID returns a random session identifier.
+func newSessionID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// Main is the gt-fake-agent entry point. It returns the exit status.
+func Main(args []string) int {
+	fs := flag.NewFlagSet("gt-fake-agent", flag.ContinueOnError)
+	scriptPath := fs.String("script", os.Getenv(ScriptEnv), "script file, or directory of per-role scripts")
+	settingsPath := fs.String("settings", "", "hooks settings file (default: nearest "+filepath.Join(SettingsDir, SettingsFile)+")")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *scriptPath == "" {
+		fmt.Fprintf(os.Stderr, "gt-fake-agent: no script (set %s or pass --script)\n", ScriptEnv)
+		return 2
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gt-fake-agent: %v\n", err)
+		return 1
+	}
+	path, err := ResolveScript(*scriptPath, os.Getenv("GT_ROLE"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gt-fake-agent: %v\n", err)
+		return 2
+	}
+	script, err := LoadScript(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gt-fake-agent: %v\n", err)
+		return 2
+	}
+	if *settingsPath == "" {
+		*settingsPath = FindSettings(dir)
+	}
+	hooks := &Hooks{}
+	if *settingsPath != "" {
+		if hooks, err = LoadHooks(*settingsPath); err != nil {
+			fmt.Fprintf(os.Stderr, "gt-fake-agent: %v\n", err)
+			return 2
+		}
+	}
+
+	sessionID := os.Getenv(SessionIDEnv)
+	if sessionID == "" {
+		sessionID = newSessionID()
+	}
+	a := &Agent{
+		Script:    script,
+		Hooks:     hooks,
+		Dir:       dir,
+		Env:       append(os.Environ(), SessionIDEnv+"="+sessionID),
+		SessionID: sessionID,
+		Stdin:     os.Stdin,
+		Stdout:    os.Stdout,
+		Stderr:    os.Stderr,
+	}
+	return a.Run(strings.Join(fs.Args(), " "))
+}
@@ -0,0 +1,259 @@
+package fakeagent
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseScript(t *testing.T) {
+	s, err := ParseScript(`
+startup_delay = "10ms"
+
+[[step]]
+action = "run"
+command = "gt hook"
+
+[[step]]
+action = "idle"
+until = "^go$"
+timeout = "2s"
+
+[[step]]
+action = "quota"
+
+[[step]]
+action = "crash"
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Prompt != DefaultPrompt || s.StartupDelay != 10*time.Millisecond {
+		t.Errorf("defaults: prompt %q, delay %v", s.Prompt, s.StartupDelay)
+	}
+	if len(s.Steps) != 4 || s.Steps[1].Timeout != 2*time.Second || s.Steps[1].until == nil {
+		t.Fatalf("steps = %+v", s.Steps)
+	}
+	if s.Steps[2].Message != DefaultQuotaMessage || s.Steps[3].ExitCode != 1 {
+		t.Errorf("quota/crash defaults: %q, %d", s.Steps[2].Message, s.Steps[3].ExitCode)
+	}
+
+	for _, bad := range []string{
+		"[[step]]\naction = \"fly\"",
+		"[[step]]\naction = \"run\"",
+		"[[step]]\naction = \"idle\"\nuntil = \"(\"",
+		"[[step]]\naction = \"say\"\ncomand = \"typo\"",
+	} {
+		if _, err := ParseScript(bad); err == nil {
+			t.Errorf("ParseScript(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestResolveScript(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"default.toml", "polecat.toml", "gastown-witness.toml"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := map[string]string{
+		"gastown/polecats/toast": "polecat.toml",
+		"gastown/witness":        "gastown-witness.toml",
+		"gastown/refinery":       "default.toml",
+		"":                       "default.toml",
+	}
+	for role, want := range tests {
+		got, err := ResolveScript(dir, role)
+		if err != nil || filepath.Base(got) != want {
+			t.Errorf("ResolveScript(%q) = %q, %v; want %s", role, got, err, want)
+		}
+	}
+	file := filepath.Join(dir, "polecat.toml")
+	if got, _ := ResolveScript(file, "mayor"); got != file {
+		t.Errorf("ResolveScript(file) = %q", got)
+	}
+}
+
+func TestMatchTool(t *testing.T) {
+	tests := []struct {
+		matcher, target string
+		want            bool
+	}{
+		{"", "Bash(ls)", true},
+		{"Bash", "Bash(ls)", true},
+		{"Edit", "Bash(ls)", false},
+		{"Bash(gh pr create*)", "Bash(gh pr create --fill)", true},
+		{"Bash(gh pr create*)", "Bash(gh pr list)", false},
+		{"Bash(git checkout -b*)", "Bash(git checkout main)", false},
+		{"Bash(gt done)", "Bash(gt done)", true},
+	}
+	for _, tt := range tests {
+		if got := matchTool(tt.matcher, tt.target); got != tt.want {
+			t.Errorf("matchTool(%q, %q) = %v, want %v", tt.matcher, tt.target, got, tt.want)
+		}
+	}
+}
+
+// syncBuffer lets the test poll output while the agent writes it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestAgentRun(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root := t.TempDir()
+	work := filepath.Join(root, "polecats", "toast")
+	bin := filepath.Join(root, "bin")
+	for _, d := range []string{work, bin, filepath.Join(root, "polecats", SettingsDir)} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A stub gt that records its invocations.
+	gtLog := filepath.Join(root, "gt.log")
+	stub := "#!/bin/sh\necho \"$*\" >> " + gtLog + "\n"
+	if err := os.WriteFile(filepath.Join(bin, "gt"), []byte(stub), 0755); err != nil {
+		t.Fatal(err)
+	}
+	settings := `{"hooks": {
+  "SessionStart": [{"matcher": "", "hooks": [{"type": "command", "command": "gt prime --hook"}]}],
+  "PreToolUse": [{"matcher": "Bash(git push*)", "hooks": [{"type": "command", "command": "echo no pushing >&2; exit 2"}]}],
+  "Stop": [{"matcher": "", "hooks": [{"type": "command", "command": "gt costs record"}]}]
+}}`
+	if err := os.WriteFile(filepath.Join(root, "polecats", SettingsDir, SettingsFile), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git := exec.Command("git", "init", "-q")
+	git.Dir = work
+	if out, err := git.CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+
+	script, err := ParseScript(`
+[[step]]
+action = "run"
+command = "gt hook"
+
+[[step]]
+action = "write"
+path = "src/fix.txt"
+content = "broken\n"
+
+[[step]]
+action = "edit"
+path = "src/fix.txt"
+old = "broken"
+new = "fixed"
+
+[[step]]
+action = "commit"
+message = "Fix it's thing"
+
+[[step]]
+action = "run"
+command = "git push origin HEAD"
+allow_failure = true
+
+[[step]]
+action = "idle"
+until = "^proceed"
+
+[[step]]
+action = "done"
+
+[[step]]
+action = "quota"
+
+[[step]]
+action = "crash"
+exit_code = 7
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	settingsPath := FindSettings(work)
+	if settingsPath == "" {
+		t.Fatal("FindSettings did not find the parent settings file")
+	}
+	hooks, err := LoadHooks(settingsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdinR, stdinW := io.Pipe()
+	defer stdinW.Close()
+	var out syncBuffer
+	a := &Agent{
+		Script:    script,
+		Hooks:     hooks,
+		Dir:       work,
+		SessionID: "sess-1",
+		Env: append(os.Environ(), "PATH="+bin+string(os.PathListSeparator)+os.Getenv("PATH"),
+			"GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t"),
+		Stdin:  stdinR,
+		Stdout: &out,
+		Stderr: &out,
+	}
+	exitc := make(chan int, 1)
+	go func() { exitc <- a.Run("beacon") }()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for !strings.HasSuffix(out.String(), DefaultPrompt) {
+		if time.Now().After(deadline) {
+			t.Fatalf("agent never went idle; output:\n%s", out.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	_, _ = io.WriteString(stdinW, "not yet\nproceed now\n")
+
+	select {
+	case code := <-exitc:
+		if code != 7 {
+			t.Errorf("exit code = %d, want 7", code)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("agent did not exit; output:\n%s", out.String())
+	}
+
+	got := out.String()
+	for _, want := range []string{"> beacon", "● Bash(gt hook)", "no pushing", "PreToolUse hook blocked", DefaultQuotaMessage} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+	calls, _ := os.ReadFile(gtLog)
+	if want := "prime --hook\nhook\ncosts record\ndone\n"; string(calls) != want {
+		t.Errorf("gt calls = %q, want %q", calls, want)
+	}
+	data, _ := os.ReadFile(filepath.Join(work, "src", "fix.txt"))
+	if string(data) != "fixed\n" {
+		t.Errorf("fix.txt = %q", data)
+	}
+	logCmd := exec.Command("git", "log", "--format=%s")
+	logCmd.Dir = work
+	if msg, _ := logCmd.Output(); strings.TrimSpace(string(msg)) != "Fix it's thing" {
+		t.Errorf("commit subject = %q", msg)
+	}
+}
@@ -0,0 +1,165 @@
+package fakeagent
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Hook events, named as in Claude Code settings files.
+const (
+	EventSessionStart     = "SessionStart"
+	EventUserPromptSubmit = "UserPromptSubmit"
+	EventPreToolUse       = "PreToolUse"
+	EventStop             = "Stop"
+)
+
+// Default settings location, relative to the settings directory. Matches the
+// scripted preset's HooksDir and HooksSettingsFile.
+const (
+	SettingsDir  = ".fake-agent"
+	SettingsFile = "settings.json"
+)
+
+// blockExitCode is the hook exit status that blocks the prompt or tool call.
+const blockExitCode = 2
+
+// Hooks are the lifecycle hooks from a Claude-format settings file. Gas Town
+// installs the same templates for the scripted preset as for Claude, so the
+// fake agent runs exactly the commands a real session would.
+type Hooks struct {
+	Events map[string][]HookMatcher `json:"hooks"`
+}
+
+// HookMatcher is a group of hook commands selected by a tool matcher.
+type HookMatcher struct {
+	Matcher string        `json:"matcher"`
+	Hooks   []HookCommand `json:"hooks"`
+}
+
+// HookCommand is a single hook.
+type HookCommand struct {
+	Type    string `json:"type"`
+	Command string `json:"command"`
+}
+
+// HookInput is the JSON document hooks receive on stdin.
+type HookInput struct {
+	SessionID string         `json:"session_id"`
+	Event     string         `json:"hook_event_name"`
+	Cwd       string         `json:"cwd"`
+	Source    string         `json:"source,omitempty"`
+	Prompt    string         `json:"prompt,omitempty"`
+	ToolName  string         `json:"tool_name,omitempty"`
+	ToolInput map[string]any `json:"tool_input,omitempty"`
+}
+
+// LoadHooks reads a settings file. A missing file yields no hooks.
+func LoadHooks(path string) (*Hooks, error) {
+	h := &Hooks{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return h, nil
+}
+
+// FindSettings walks up from dir looking for SettingsDir/SettingsFile. Gas
+// Town installs role settings in a parent of the work dir (e.g. the rig's
+// polecats/ directory), which Claude reaches via --settings.
+func FindSettings(dir string) string {
+	for {
+		p := filepath.Join(dir, SettingsDir, SettingsFile)
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// commands returns the hooks for an event whose matcher accepts target.
+// target is the tool call for PreToolUse (e.g. "Bash(gt done)") and empty
+// otherwise.
+func (h *Hooks) commands(event, target string) []string {
+	if h == nil {
+		return nil
+	}
+	var out []string
+	for _, m := range h.Events[event] {
+		if !matchTool(m.Matcher, target) {
+			continue
+		}
+		for _, c := range m.Hooks {
+			if c.Type == "command" && c.Command != "" {
+				out = append(out, c.Command)
+			}
+		}
+	}
+	return out
+}
+
+// matchTool reports whether a settings matcher selects a tool call. Empty and
+// "*" match everything, a bare tool name matches any call to it, and
+// "Tool(pattern*)" globs the call's argument.
+func matchTool(matcher, target string) bool {
+	if matcher == "" || matcher == "*" || target == "" {
+		return true
+	}
+	name, arg, _ := strings.Cut(target, "(")
+	if !strings.Contains(matcher, "(") {
+		return matcher == name
+	}
+	mName, mArg, _ := strings.Cut(matcher, "(")
+	if mName != name {
+		return false
+	}
+	pattern := "^" + strings.ReplaceAll(regexp.QuoteMeta(strings.TrimSuffix(mArg, ")")), `\*`, ".*") + "$"
+	ok, _ := regexp.MatchString(pattern, strings.TrimSuffix(arg, ")"))
+	return ok
+}
+
+// runHooks runs every hook for an event. Hook stdout is shown in the
+// terminal, standing in for context injected into the conversation. It
+// reports whether a hook blocked the action by exiting with status 2.
+func (a *Agent) runHooks(in HookInput, target string) bool {
+	in.SessionID = a.SessionID
+	in.Cwd = a.Dir
+	payload, _ := json.Marshal(in)
+	for _, command := range a.Hooks.commands(in.Event, target) {
+		var stderr bytes.Buffer
+		cmd := exec.Command("sh", "-c", command)
+		cmd.Dir = a.Dir
+		cmd.Env = a.Env
+		cmd.Stdin = bytes.NewReader(payload)
+		cmd.Stdout = a.Stdout
+		cmd.Stderr = io.MultiWriter(a.Stderr, &stderr)
+		err := cmd.Run()
+		var exitErr *exec.ExitError
+		switch {
+		case err == nil:
+		case errors.As(err, &exitErr) && exitErr.ExitCode() == blockExitCode:
+			fmt.Fprintf(a.Stdout, "  ⎿ %s hook blocked: %s\n", in.Event, strings.TrimSpace(stderr.String()))
+			return true
+		default:
+			fmt.Fprintf(a.Stdout, "  ⎿ %s hook failed: %v\n", in.Event, err)
+		}
+	}
+	return false
+}
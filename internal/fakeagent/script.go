@@ -0,0 +1,227 @@
+// Package fakeagent implements the "scripted" agent runtime: a stand-in for an
+// LLM CLI that replays a fixed list of actions from a TOML script. It prints
+// the ready prompt, runs the same lifecycle hooks a real agent would, and
+// shells out to gt and git, so patrol and merge-queue logic can be exercised
+// end to end without a model or network access.
+package fakeagent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ScriptEnv names the script file, or a directory of per-role scripts.
+const ScriptEnv = "GT_FAKE_AGENT_SCRIPT"
+
+// DefaultPrompt matches the scripted preset's ReadyPromptPrefix.
+const DefaultPrompt = "❯ "
+
+// DefaultQuotaMessage matches constants.DefaultRateLimitPatterns, so the quota
+// scanner treats the session as rate-limited.
+const DefaultQuotaMessage = "You've hit your limit · resets 7pm (UTC)"
+
+// Step actions.
+const (
+	ActionRun    = "run"    // run a shell command (e.g. "gt hook")
+	ActionWrite  = "write"  // create, overwrite or append to a file
+	ActionEdit   = "edit"   // replace text in an existing file
+	ActionCommit = "commit" // git add -A && git commit
+	ActionDone   = "done"   // gt done [args]
+	ActionSay    = "say"    // print a message
+	ActionIdle   = "idle"   // show the ready prompt and wait for input
+	ActionCrash  = "crash"  // exit non-zero
+	ActionQuota  = "quota"  // print a rate-limit message
+)
+
+// Script is a parsed agent script.
+//
+//	prompt = "❯ "
+//	startup_delay = "500ms"
+//
+//	[[step]]
+//	action = "run"
+//	command = "gt hook"
+//
+//	[[step]]
+//	action = "idle"
+//	until = "gt-\\w+"
+//	timeout = "30s"
+type Script struct {
+	// Prompt is printed when the agent is idle. Defaults to DefaultPrompt.
+	Prompt string `toml:"prompt"`
+
+	// StartupDelay is slept before SessionStart hooks run, to mimic a slow
+	// CLI and exercise readiness detection.
+	StartupDelay time.Duration `toml:"startup_delay"`
+
+	// ExitWhenDone exits with status 0 after the last step instead of
+	// idling at the prompt until stdin closes.
+	ExitWhenDone bool `toml:"exit_when_done"`
+
+	Steps []Step `toml:"step"`
+}
+
+// Step is one scripted action. Which fields apply depends on Action.
+type Step struct {
+	Action string `toml:"action"`
+
+	// Command is the shell command for run steps.
+	Command string `toml:"command"`
+
+	// Args are extra arguments for done steps (e.g. ["--status", "ESCALATED"]).
+	Args []string `toml:"args"`
+
+	// AllowFailure keeps going when a run, commit or done step fails.
+	// Otherwise the agent exits with status 1.
+	AllowFailure bool `toml:"allow_failure"`
+
+	// Path is the file for write and edit steps, relative to the work dir.
+	Path string `toml:"path"`
+
+	// Content is written by write steps; Append adds it to the end instead.
+	Content string `toml:"content"`
+	Append  bool   `toml:"append"`
+
+	// Old and New are the replacement for edit steps.
+	Old string `toml:"old"`
+	New string `toml:"new"`
+
+	// Message is the commit message, or the text printed by say, crash and
+	// quota steps.
+	Message string `toml:"message"`
+
+	// ExitCode is the status for crash steps. Defaults to 1.
+	ExitCode int `toml:"exit_code"`
+
+	// Until is a regexp an input line must match to end an idle step.
+	// Empty means any line.
+	Until string `toml:"until"`
+
+	// Timeout ends an idle step without input. Zero waits indefinitely.
+	Timeout time.Duration `toml:"timeout"`
+
+	until *regexp.Regexp
+}
+
+// ParseScript decodes and validates a script.
+func ParseScript(data string) (*Script, error) {
+	var s Script
+	md, err := toml.Decode(data, &s)
+	if err != nil {
+		return nil, err
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		return nil, fmt.Errorf("unknown key %q", undecoded[0].String())
+	}
+	if s.Prompt == "" {
+		s.Prompt = DefaultPrompt
+	}
+	for i := range s.Steps {
+		if err := s.Steps[i].validate(); err != nil {
+			return nil, fmt.Errorf("step %d: %w", i+1, err)
+		}
+	}
+	return &s, nil
+}
+
+// LoadScript reads and parses a script file.
+func LoadScript(path string) (*Script, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s, err := ParseScript(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// ResolveScript picks the script for a role. If path is a directory, it looks
+// for the full role ("gastown-polecats-toast.toml"), then the role kind
+// ("polecat.toml"), then "default.toml". role is a GT_ROLE value.
+func ResolveScript(path, role string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return path, nil
+	}
+	var candidates []string
+	if role != "" {
+		candidates = append(candidates, strings.ReplaceAll(role, "/", "-"), roleKind(role))
+	}
+	candidates = append(candidates, "default")
+	for _, name := range candidates {
+		p := filepath.Join(path, name+".toml")
+		if _, err := os.Stat(p); err == nil {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("no script for role %q in %s", role, path)
+}
+
+// roleKind reduces a compound GT_ROLE ("gastown/polecats/toast",
+// "deacon/boot") to the role name used for script lookup.
+func roleKind(role string) string {
+	parts := strings.Split(role, "/")
+	if n := len(parts); n >= 2 {
+		switch parts[n-2] {
+		case "polecats":
+			return "polecat"
+		case "crew":
+			return "crew"
+		}
+	}
+	return parts[len(parts)-1]
+}
+
+func (s *Step) validate() error {
+	switch s.Action {
+	case ActionRun:
+		if s.Command == "" {
+			return fmt.Errorf("run requires command")
+		}
+	case ActionWrite:
+		if s.Path == "" {
+			return fmt.Errorf("write requires path")
+		}
+	case ActionEdit:
+		if s.Path == "" || s.Old == "" {
+			return fmt.Errorf("edit requires path and old")
+		}
+	case ActionCommit:
+		if s.Message == "" {
+			return fmt.Errorf("commit requires message")
+		}
+	case ActionIdle:
+		if s.Until != "" {
+			re, err := regexp.Compile(s.Until)
+			if err != nil {
+				return fmt.Errorf("idle until: %w", err)
+			}
+			s.until = re
+		}
+	case ActionCrash:
+		if s.ExitCode == 0 {
+			s.ExitCode = 1
+		}
+	case ActionQuota:
+		if s.Message == "" {
+			s.Message = DefaultQuotaMessage
+		}
+	case ActionDone, ActionSay:
+	case "":
+		return fmt.Errorf("missing action")
+	default:
+		return fmt.Errorf("unknown action %q", s.Action)
+	}
+	return nil
+}
@@ -1,7 +1,7 @@
 // Package feed provides the feed daemon that curates raw events into a user-facing feed.
 //
 // The curator:
-// 1. Tails ~/gt/.events.jsonl (raw events)
+// 1. Tails the event store in ~/gt/.events/ (raw events)
 // 2. Filters by visibility tag (drops audit-only events)
 // 3. Deduplicates repeated updates (5 molecule updates → "agent active")
 // 4. Aggregates related events (3 issues closed → "batch complete")
@@ -96,24 +96,21 @@ func NewCurator(townRoot string) *Curator {
 // only the first call starts the goroutine — subsequent calls are no-ops.
 func (c *Curator) Start() error {
 	c.startOnce.Do(func() {
-		eventsPath := filepath.Join(c.townRoot, events.EventsFile)
-
-		// Open events file, creating if needed
-		file, err := os.OpenFile(eventsPath, os.O_RDONLY|os.O_CREATE, 0600)
-		if err != nil {
-			c.startErr = fmt.Errorf("opening events file: %w", err)
+		if _, err := os.Stat(c.townRoot); err != nil {
+			c.startErr = fmt.Errorf("opening event store: %w", err)
 			return
 		}
 
-		// Seek to end to only process new events
-		if _, err := file.Seek(0, io.SeekEnd); err != nil {
-			_ = file.Close() //nolint:gosec // G104: best effort cleanup on error
-			c.startErr = fmt.Errorf("seeking to end: %w", err)
+		// Start at the end of the store to only process new events
+		store := events.OpenStore(c.townRoot)
+		end, err := store.End()
+		if err != nil {
+			c.startErr = fmt.Errorf("opening event store: %w", err)
 			return
 		}
 
 		c.wg.Add(1)
-		go c.run(file)
+		go c.run(store.Subscribe(c.ctx, end, events.Query{}))
 	})
 	return c.startErr
 }
@@ -126,33 +123,15 @@ func (c *Curator) Stop() {
 
 // run is the main curator loop.
 // ZFC: No in-memory state to clean up - state is derived from the events file.
-func (c *Curator) run(file *os.File) {
+func (c *Curator) run(recs <-chan events.Record) {
 	defer c.wg.Done()
-	defer file.Close()
-
-	reader := bufio.NewReader(file)
-	ticker := time.NewTicker(100 * time.Millisecond)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-c.ctx.Done():
-			return
 
-		case <-ticker.C:
-			// Read available lines
-			for {
-				line, err := reader.ReadString('\n')
-				if err != nil {
-					break // No more data available
-				}
-				c.processLine(line)
-			}
-		}
+	for rec := range recs {
+		c.processLine(rec.Line)
 	}
 }
 
-// processLine processes a single line from the events file.
+// processLine processes a single line from the event store.
 func (c *Curator) processLine(line string) {
 	if line == "" || line == "\n" {
 		return
@@ -266,49 +245,16 @@ func (c *Curator) readRecentFeedEvents(window time.Duration) []FeedEvent {
 	return result
 }
 
-// readRecentEvents reads events from the events file within the given time window.
+// readRecentEvents reads events from the event store within the given time window.
 // ZFC: This is the observable state that replaces in-memory caching.
-// Reads at most tailReadSize bytes from the end to bound memory usage.
 func (c *Curator) readRecentEvents(window time.Duration) []events.Event {
-	eventsPath := filepath.Join(c.townRoot, events.EventsFile)
-	f, err := os.Open(eventsPath)
+	recs, err := events.OpenStore(c.townRoot).Query(events.Query{Since: time.Now().Add(-window)})
 	if err != nil {
 		return nil
 	}
-	defer f.Close()
-
-	info, err := f.Stat()
-	if err != nil || info.Size() == 0 {
-		return nil
-	}
-
-	seekTo := info.Size() - tailReadSize
-	if seekTo < 0 {
-		seekTo = 0
-	}
-	if _, err := f.Seek(seekTo, io.SeekStart); err != nil {
-		return nil
-	}
-
-	scanner := bufio.NewScanner(f)
-	if seekTo > 0 {
-		scanner.Scan() // skip potential partial first line at cut point
-	}
-
-	cutoff := time.Now().Add(-window)
-	var result []events.Event
-	for scanner.Scan() {
-		var event events.Event
-		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
-			continue
-		}
-		ts, err := time.Parse(time.RFC3339, event.Timestamp)
-		if err != nil {
-			continue
-		}
-		if !ts.Before(cutoff) {
-			result = append(result, event)
-		}
+	result := make([]events.Event, 0, len(recs))
+	for _, rec := range recs {
+		result = append(result, rec.Event)
 	}
 	return result
 }
@@ -23,8 +23,7 @@ func TestCurator_FiltersByVisibility(t *testing.T) {
 	}
 	defer os.RemoveAll(tmpDir)
 
-	// Create events file with test events
-	eventsPath := filepath.Join(tmpDir, events.EventsFile)
+	store := events.OpenStore(tmpDir)
 	feedPath := filepath.Join(tmpDir, FeedFile)
 
 	// Write a feed-visible event
@@ -36,7 +35,6 @@ func TestCurator_FiltersByVisibility(t *testing.T) {
 		Payload:    map[string]interface{}{"bead": "gt-123", "target": "gastown/slit"},
 		Visibility: events.VisibilityFeed,
 	}
-	feedData, _ := json.Marshal(feedEvent)
 
 	// Write an audit-only event (should be filtered out)
 	auditEvent := events.Event{
@@ -46,12 +44,6 @@ func TestCurator_FiltersByVisibility(t *testing.T) {
 		Actor:      "daemon",
 		Visibility: events.VisibilityAudit,
 	}
-	auditData, _ := json.Marshal(auditEvent)
-
-	// Create events file
-	if err := os.WriteFile(eventsPath, []byte{}, 0644); err != nil {
-		t.Fatalf("creating events file: %v", err)
-	}
 
 	// Start curator
 	curator := NewCurator(tmpDir)
@@ -64,13 +56,9 @@ func TestCurator_FiltersByVisibility(t *testing.T) {
 	time.Sleep(50 * time.Millisecond)
 
 	// Append events
-	f, err := os.OpenFile(eventsPath, os.O_APPEND|os.O_WRONLY, 0644)
-	if err != nil {
-		t.Fatalf("opening events file: %v", err)
+	if err := store.Append(feedEvent, auditEvent); err != nil {
+		t.Fatalf("appending events: %v", err)
 	}
-	f.Write(append(feedData, '\n'))
-	f.Write(append(auditData, '\n'))
-	f.Close()
 
 	// Wait for processing
 	time.Sleep(300 * time.Millisecond)
@@ -106,14 +94,9 @@ func TestCurator_DedupesDoneEvents(t *testing.T) {
 	}
 	defer os.RemoveAll(tmpDir)
 
-	eventsPath := filepath.Join(tmpDir, events.EventsFile)
+	store := events.OpenStore(tmpDir)
 	feedPath := filepath.Join(tmpDir, FeedFile)
 
-	// Create events file
-	if err := os.WriteFile(eventsPath, []byte{}, 0644); err != nil {
-		t.Fatalf("creating events file: %v", err)
-	}
-
 	// Start curator
 	curator := NewCurator(tmpDir)
 	if err := curator.Start(); err != nil {
@@ -124,7 +107,6 @@ func TestCurator_DedupesDoneEvents(t *testing.T) {
 	time.Sleep(50 * time.Millisecond)
 
 	// Write 3 identical done events from same actor
-	f, _ := os.OpenFile(eventsPath, os.O_APPEND|os.O_WRONLY, 0644)
 	for i := 0; i < 3; i++ {
 		doneEvent := events.Event{
 			Timestamp:  time.Now().UTC().Format(time.RFC3339),
@@ -134,10 +116,10 @@ func TestCurator_DedupesDoneEvents(t *testing.T) {
 			Payload:    map[string]interface{}{"bead": "slit-12345"},
 			Visibility: events.VisibilityFeed,
 		}
-		data, _ := json.Marshal(doneEvent)
-		f.Write(append(data, '\n'))
+		if err := store.Append(doneEvent); err != nil {
+			t.Fatalf("appending event: %v", err)
+		}
 	}
-	f.Close()
 
 	// Wait for processing
 	time.Sleep(300 * time.Millisecond)
@@ -529,10 +511,7 @@ func TestCurator_DefaultMaxFeedFileSize(t *testing.T) {
 // Regression test for steveyegge/gastown#1230 item 6.
 func TestCurator_ConcurrentStartIsIdempotent(t *testing.T) {
 	tmpDir := t.TempDir()
-	eventsPath := filepath.Join(tmpDir, events.EventsFile)
-	if err := os.WriteFile(eventsPath, []byte{}, 0644); err != nil {
-		t.Fatalf("creating events file: %v", err)
-	}
+	store := events.OpenStore(tmpDir)
 
 	curator := NewCurator(tmpDir)
 	defer curator.Stop()
@@ -561,10 +540,6 @@ func TestCurator_ConcurrentStartIsIdempotent(t *testing.T) {
 	// (not N duplicates from N goroutines).
 	time.Sleep(50 * time.Millisecond)
 
-	f, err := os.OpenFile(eventsPath, os.O_APPEND|os.O_WRONLY, 0644)
-	if err != nil {
-		t.Fatal(err)
-	}
 	ev := events.Event{
 		Timestamp:  time.Now().UTC().Format(time.RFC3339),
 		Source:     "gt",
@@ -573,9 +548,9 @@ func TestCurator_ConcurrentStartIsIdempotent(t *testing.T) {
 		Payload:    map[string]interface{}{"bead": "test"},
 		Visibility: events.VisibilityFeed,
 	}
-	data, _ := json.Marshal(ev)
-	f.Write(append(data, '\n'))
-	f.Close()
+	if err := store.Append(ev); err != nil {
+		t.Fatal(err)
+	}
 
 	time.Sleep(300 * time.Millisecond)
 
@@ -669,7 +644,7 @@ func TestCurator_ConcurrentFeedReadWrite(t *testing.T) {
 // Regression test: startErr must be a struct field (not function-local) so
 // sync.Once's happens-before guarantee makes it visible to all callers.
 func TestCurator_StartErrorPersistsAcrossCalls(t *testing.T) {
-	// Use a non-existent directory so OpenFile fails.
+	// Use a non-existent directory so opening the event store fails.
 	curator := NewCurator("/nonexistent/path/that/does/not/exist")
 	defer curator.Stop()
 
@@ -0,0 +1,82 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// requestTimeout bounds a single API request.
+const requestTimeout = 30 * time.Second
+
+// client is the JSON-over-HTTP transport shared by the forge backends.
+type client struct {
+	base    string // API root without trailing slash
+	auth    string // Authorization header value; empty for anonymous
+	headers map[string]string
+	http    *http.Client
+}
+
+func newClient(base, auth string, headers map[string]string) *client {
+	return &client{
+		base:    strings.TrimSuffix(base, "/"),
+		auth:    auth,
+		headers: headers,
+		http:    &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// do sends a request with in (if non-nil) as the JSON body and decodes a
+// 2xx response into out (if non-nil). Other responses return an *APIError.
+func (c *client) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("encoding request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.base+path, body)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.auth != "" {
+		req.Header.Set("Authorization", c.auth)
+	}
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var errResp struct {
+			Message string `json:"message"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		_ = json.Unmarshal(data, &errResp)
+		return &APIError{Status: resp.StatusCode, Message: errResp.Message}
+	}
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && err != io.EOF {
+		return fmt.Errorf("decoding %s %s: %w", method, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,325 @@
+// Package forge talks to code hosts (GitHub, Gitea/Forgejo) over their REST
+// APIs so the refinery can land work through pull requests instead of
+// pushing to the target branch itself.
+package forge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Forge types accepted in ForgeConfig.Type.
+const (
+	TypeGitHub = "github"
+	TypeGitea  = "gitea"
+)
+
+// Merge methods accepted in ForgeConfig.MergeMethod.
+const (
+	MergeMethodMerge  = "merge"
+	MergeMethodSquash = "squash"
+	MergeMethodRebase = "rebase"
+)
+
+// Forge is a code host that can open, inspect and merge pull requests.
+type Forge interface {
+	// Type returns TypeGitHub or TypeGitea.
+	Type() string
+
+	// FindPR returns the open pull request from head into base, or nil.
+	FindPR(ctx context.Context, head, base string) (*PullRequest, error)
+
+	// CreatePR opens a pull request.
+	CreatePR(ctx context.Context, req NewPullRequest) (*PullRequest, error)
+
+	// GetPR fetches a pull request by number.
+	GetPR(ctx context.Context, number int) (*PullRequest, error)
+
+	// Checks summarizes the CI checks reported on the pull request's head.
+	Checks(ctx context.Context, pr *PullRequest) (*CheckSummary, error)
+
+	// Reviews lists the reviews and inline review comments on a pull
+	// request, oldest first.
+	Reviews(ctx context.Context, number int) ([]Review, error)
+
+	// Merge merges the pull request at its current head with method and
+	// returns the resulting commit SHA. A pull request the host will not
+	// merge yet (missing approvals, head moved) returns an *APIError with
+	// NotMergeable() true.
+	Merge(ctx context.Context, pr *PullRequest, method, title string) (string, error)
+}
+
+// PRState is the state of a pull request.
+type PRState string
+
+const (
+	PROpen   PRState = "open"
+	PRClosed PRState = "closed" // Closed without merging
+	PRMerged PRState = "merged"
+)
+
+// PullRequest is a pull request on a forge.
+type PullRequest struct {
+	Number      int
+	URL         string
+	State       PRState
+	Head        string // Head branch name
+	HeadSHA     string
+	Base        string // Base branch name
+	MergeCommit string // Set once merged
+
+	// Conflicted is true when the host reports the head cannot be merged
+	// into the base cleanly.
+	Conflicted bool
+
+	// Blocked is true when the host reports branch protection (required
+	// reviews or checks) is not yet satisfied. Only GitHub reports this;
+	// on Gitea a blocked merge fails with a not-mergeable error instead.
+	Blocked bool
+
+	// MergeablePending is true when the host has not settled whether the
+	// head can merge: GitHub is still computing it ("unknown"), or the
+	// head is behind a base that requires up-to-date branches ("behind").
+	MergeablePending bool
+
+	// UpdatedAt is when the pull request last changed, pushes to the head
+	// included. Zero if the host doesn't say.
+	UpdatedAt time.Time
+}
+
+// NewPullRequest is the input to CreatePR.
+type NewPullRequest struct {
+	Title string
+	Body  string
+	Head  string
+	Base  string
+}
+
+// CheckState is the rolled-up state of a set of checks.
+type CheckState string
+
+const (
+	ChecksPending CheckState = "pending"
+	ChecksSuccess CheckState = "success"
+	ChecksFailure CheckState = "failure"
+
+	// ChecksNone means nothing has reported on the head and no checks are
+	// required. CI may simply not have started yet.
+	ChecksNone CheckState = "none"
+)
+
+// Check is one CI check or commit status.
+type Check struct {
+	Name  string
+	State CheckState
+	URL   string
+}
+
+// CheckSummary is the result of Checks.
+type CheckSummary struct {
+	State  CheckState
+	Checks []Check
+}
+
+// Failed returns the names of failed checks.
+func (s *CheckSummary) Failed() []string {
+	var names []string
+	for _, c := range s.Checks {
+		if c.State == ChecksFailure {
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
+
+// summarize rolls checks up. With required names, only those checks count
+// and a required check that has not reported yet is pending. Without them,
+// every reported check must pass, and a head with no checks is ChecksNone.
+func summarize(checks []Check, required []string) *CheckSummary {
+	sum := &CheckSummary{State: ChecksSuccess, Checks: checks}
+	if len(checks) == 0 && len(required) == 0 {
+		sum.State = ChecksNone
+		return sum
+	}
+	counted := checks
+	if len(required) > 0 {
+		counted = nil
+		for _, name := range required {
+			found := Check{Name: name, State: ChecksPending}
+			for _, c := range checks {
+				if c.Name == name {
+					found = c
+					break
+				}
+			}
+			counted = append(counted, found)
+		}
+	}
+	for _, c := range counted {
+		switch c.State {
+		case ChecksFailure:
+			sum.State = ChecksFailure
+		case ChecksPending:
+			if sum.State != ChecksFailure {
+				sum.State = ChecksPending
+			}
+		}
+	}
+	return sum
+}
+
+// ReviewState is the verdict of a review.
+type ReviewState string
+
+const (
+	ReviewApproved         ReviewState = "approved"
+	ReviewChangesRequested ReviewState = "changes_requested"
+	ReviewCommented        ReviewState = "commented"
+)
+
+// Review is a submitted review or an inline comment on a pull request.
+// Inline comments have Path set and State ReviewCommented.
+type Review struct {
+	ID        string // Unique within the pull request
+	Author    string
+	State     ReviewState
+	Body      string
+	Path      string
+	Line      int
+	URL       string
+	CreatedAt time.Time
+}
+
+// Actionable reports whether the review asks for changes: a changes
+// requested verdict, or an inline comment on the code.
+func (r Review) Actionable() bool {
+	return r.State == ReviewChangesRequested || (r.Path != "" && strings.TrimSpace(r.Body) != "")
+}
+
+// APIError is a non-2xx response from a forge.
+type APIError struct {
+	Status  int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("forge API error (HTTP %d)", e.Status)
+	}
+	return fmt.Sprintf("forge API error (HTTP %d): %s", e.Status, e.Message)
+}
+
+// NotMergeable reports whether the host refused a merge because the pull
+// request is not mergeable yet (405) or its head changed (409).
+func (e *APIError) NotMergeable() bool {
+	return e.Status == http.StatusMethodNotAllowed || e.Status == http.StatusConflict
+}
+
+// IsNotMergeable reports whether err is an *APIError with NotMergeable true.
+func IsNotMergeable(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.NotMergeable()
+}
+
+// New returns the forge described by cfg. gitURL is the rig's repository
+// URL, used when cfg does not name the repo or (for GitHub) the server.
+func New(cfg *config.ForgeConfig, gitURL string) (Forge, error) {
+	if cfg == nil {
+		return nil, errors.New("no forge configured")
+	}
+	repo := cfg.Repo
+	if repo == "" {
+		_, repo = ParseRepoURL(gitURL)
+	}
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("forge repo %q: want owner/name", repo)
+	}
+
+	switch strings.ToLower(cfg.Type) {
+	case TypeGitHub:
+		base := cfg.URL
+		if base == "" {
+			base = "https://api.github.com"
+		}
+		token := tokenFromEnv(cfg.TokenEnv, "GITHUB_TOKEN", "GH_TOKEN")
+		return newGitHub(base, owner, name, token, cfg.RequiredChecks), nil
+	case TypeGitea, "forgejo":
+		base := cfg.URL
+		if base == "" {
+			host, _ := ParseRepoURL(gitURL)
+			if host == "" {
+				return nil, errors.New("gitea forge needs a url")
+			}
+			base = "https://" + host
+		}
+		token := tokenFromEnv(cfg.TokenEnv, "GITEA_TOKEN")
+		return newGitea(base, owner, name, token, cfg.RequiredChecks), nil
+	case "":
+		return nil, errors.New("forge type is required (github or gitea)")
+	default:
+		return nil, fmt.Errorf("unknown forge type %q (want github or gitea)", cfg.Type)
+	}
+}
+
+// MergeMethod returns cfg's merge method, defaulting to squash.
+func MergeMethod(cfg *config.ForgeConfig) (string, error) {
+	if cfg == nil || cfg.MergeMethod == "" {
+		return MergeMethodSquash, nil
+	}
+	switch cfg.MergeMethod {
+	case MergeMethodMerge, MergeMethodSquash, MergeMethodRebase:
+		return cfg.MergeMethod, nil
+	}
+	return "", fmt.Errorf("invalid merge_method %q: want merge, squash or rebase", cfg.MergeMethod)
+}
+
+func tokenFromEnv(configured string, defaults ...string) string {
+	if configured != "" {
+		return os.Getenv(configured)
+	}
+	for _, name := range defaults {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// ParseRepoURL extracts the host and "owner/name" from a git remote URL in
+// HTTPS (https://host/owner/name.git), SSH (ssh://git@host/owner/name) or
+// scp-like (git@host:owner/name.git) form. It returns empty strings for
+// URLs it does not recognize.
+func ParseRepoURL(gitURL string) (host, repo string) {
+	u := strings.TrimSpace(gitURL)
+	u = strings.TrimSuffix(strings.TrimSuffix(u, "/"), ".git")
+	switch {
+	case strings.Contains(u, "://"):
+		u = u[strings.Index(u, "://")+3:]
+		if at := strings.Index(u, "@"); at >= 0 && at < strings.Index(u+"/", "/") {
+			u = u[at+1:]
+		}
+		host, repo, _ = strings.Cut(u, "/")
+		if i := strings.Index(host, ":"); i >= 0 {
+			host = host[:i]
+		}
+	case strings.Contains(u, ":"):
+		if at := strings.Index(u, "@"); at >= 0 {
+			u = u[at+1:]
+		}
+		host, repo, _ = strings.Cut(u, ":")
+	default:
+		return "", ""
+	}
+	if strings.Count(repo, "/") != 1 {
+		return "", ""
+	}
+	return host, repo
+}
@@ -0,0 +1,219 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestParseRepoURL(t *testing.T) {
+	tests := []struct {
+		url, host, repo string
+	}{
+		{"https://github.com/steveyegge/gastown.git", "github.com", "steveyegge/gastown"},
+		{"https://github.com/steveyegge/gastown/", "github.com", "steveyegge/gastown"},
+		{"git@github.com:steveyegge/gastown.git", "github.com", "steveyegge/gastown"},
+		{"ssh://git@codeberg.org:2222/town/rig", "codeberg.org", "town/rig"},
+		{"/srv/git/rig.git", "", ""},
+	}
+	for _, tt := range tests {
+		host, repo := ParseRepoURL(tt.url)
+		if host != tt.host || repo != tt.repo {
+			t.Errorf("ParseRepoURL(%q) = %q, %q; want %q, %q", tt.url, host, repo, tt.host, tt.repo)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	f, err := New(&config.ForgeConfig{Type: "github"}, "git@github.com:town/rig.git")
+	if err != nil || f.Type() != TypeGitHub {
+		t.Fatalf("New(github) = %v, %v", f, err)
+	}
+	if _, err := New(&config.ForgeConfig{Type: "forgejo"}, "https://codeberg.org/town/rig"); err != nil {
+		t.Errorf("New(forgejo) error = %v", err)
+	}
+	if _, err := New(&config.ForgeConfig{Type: "gitea"}, "/srv/git/rig.git"); err == nil {
+		t.Error("New(gitea) without repo or url should fail")
+	}
+	if _, err := New(&config.ForgeConfig{Type: "gitlab", Repo: "town/rig"}, ""); err == nil {
+		t.Error("New(gitlab) should fail")
+	}
+	if _, err := MergeMethod(&config.ForgeConfig{MergeMethod: "octopus"}); err == nil {
+		t.Error("MergeMethod(octopus) should fail")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	checks := []Check{
+		{Name: "lint", State: ChecksSuccess},
+		{Name: "test", State: ChecksFailure},
+		{Name: "e2e", State: ChecksPending},
+	}
+	if got := summarize(checks, nil); got.State != ChecksFailure || !reflect.DeepEqual(got.Failed(), []string{"test"}) {
+		t.Errorf("all checks: state = %s, failed = %v", got.State, got.Failed())
+	}
+	if got := summarize(checks, []string{"lint"}); got.State != ChecksSuccess {
+		t.Errorf("required lint: state = %s, want success", got.State)
+	}
+	if got := summarize(checks, []string{"lint", "deploy"}); got.State != ChecksPending {
+		t.Errorf("required check not reported: state = %s, want pending", got.State)
+	}
+	if got := summarize(nil, nil); got.State != ChecksNone {
+		t.Errorf("no checks: state = %s, want none", got.State)
+	}
+	if got := summarize(nil, []string{"lint"}); got.State != ChecksPending {
+		t.Errorf("no checks, lint required: state = %s, want pending", got.State)
+	}
+}
+
+func TestGitHubMergeableState(t *testing.T) {
+	for state, want := range map[string]bool{"unknown": true, "behind": true, "clean": false, "blocked": false} {
+		p := &githubPR{State: "open", MergeableState: state}
+		if got := p.convert().MergeablePending; got != want {
+			t.Errorf("mergeable_state %q: MergeablePending = %v, want %v", state, got, want)
+		}
+	}
+}
+
+// fakeAPI serves canned JSON per "METHOD path" and records request bodies.
+type fakeAPI struct {
+	responses map[string]interface{}
+	status    map[string]int
+	bodies    map[string]map[string]interface{}
+}
+
+func newFakeAPI(t *testing.T) (*fakeAPI, *httptest.Server) {
+	api := &fakeAPI{
+		responses: map[string]interface{}{},
+		status:    map[string]int{},
+		bodies:    map[string]map[string]interface{}{},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.RequestURI()
+		if r.Body != nil {
+			var body map[string]interface{}
+			if json.NewDecoder(r.Body).Decode(&body) == nil {
+				api.bodies[key] = body
+			}
+		}
+		if code, ok := api.status[key]; ok {
+			w.WriteHeader(code)
+			_, _ = w.Write([]byte(`{"message":"not mergeable"}`))
+			return
+		}
+		resp, ok := api.responses[key]
+		if !ok {
+			t.Errorf("unexpected request %s", key)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return api, srv
+}
+
+func TestGitHub(t *testing.T) {
+	api, srv := newFakeAPI(t)
+	g := newGitHub(srv.URL, "town", "rig", "tok", nil)
+	ctx := context.Background()
+
+	pr := map[string]interface{}{
+		"number": 7, "html_url": "https://github.com/town/rig/pull/7", "state": "open",
+		"mergeable_state": "blocked",
+		"head":            map[string]string{"ref": "polecat/a", "sha": "abc"},
+		"base":            map[string]string{"ref": "main"},
+	}
+	api.responses["GET /repos/town/rig/pulls?base=main&head=town%3Apolecat%2Fa&state=open"] = []interface{}{pr}
+	api.responses["GET /repos/town/rig/commits/abc/check-runs?per_page=100"] = map[string]interface{}{
+		"check_runs": []map[string]string{{"name": "test", "status": "completed", "conclusion": "success"}},
+	}
+	api.responses["GET /repos/town/rig/commits/abc/status"] = map[string]interface{}{
+		"statuses": []map[string]string{{"context": "ci/lint", "state": "pending"}},
+	}
+	api.responses["GET /repos/town/rig/pulls/7/reviews?per_page=100"] = []map[string]interface{}{
+		{"id": 1, "user": map[string]string{"login": "mayor"}, "state": "CHANGES_REQUESTED", "body": "fix it", "submitted_at": "2026-01-02T00:00:00Z"},
+		{"id": 2, "user": map[string]string{"login": "mayor"}, "state": "DISMISSED", "submitted_at": "2026-01-01T00:00:00Z"},
+	}
+	api.responses["GET /repos/town/rig/pulls/7/comments?per_page=100"] = []map[string]interface{}{
+		{"id": 3, "user": map[string]string{"login": "crew"}, "body": "nit", "path": "main.go", "line": 4, "created_at": "2026-01-01T00:00:00Z"},
+	}
+	api.status["PUT /repos/town/rig/pulls/7/merge"] = http.StatusMethodNotAllowed
+
+	found, err := g.FindPR(ctx, "polecat/a", "main")
+	if err != nil || found == nil {
+		t.Fatalf("FindPR = %v, %v", found, err)
+	}
+	if found.Number != 7 || found.State != PROpen || !found.Blocked || found.HeadSHA != "abc" {
+		t.Errorf("FindPR = %+v", found)
+	}
+
+	sum, err := g.Checks(ctx, found)
+	if err != nil || sum.State != ChecksPending || len(sum.Checks) != 2 {
+		t.Errorf("Checks = %+v, %v", sum, err)
+	}
+
+	reviews, err := g.Reviews(ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reviews) != 2 || reviews[0].ID != "comment-3" || reviews[1].State != ReviewChangesRequested {
+		t.Errorf("Reviews = %+v (want comment then review, dismissed dropped)", reviews)
+	}
+	if !reviews[0].Actionable() || !reviews[1].Actionable() {
+		t.Errorf("inline comment and changes requested should be actionable")
+	}
+
+	_, err = g.Merge(ctx, found, MergeMethodSquash, "Fix (gt-1)")
+	if !IsNotMergeable(err) {
+		t.Errorf("Merge error = %v, want not mergeable", err)
+	}
+	if body := api.bodies["PUT /repos/town/rig/pulls/7/merge"]; body["merge_method"] != "squash" || body["sha"] != "abc" || body["commit_title"] != "Fix (gt-1)" {
+		t.Errorf("merge body = %v", body)
+	}
+}
+
+func TestGitea(t *testing.T) {
+	api, srv := newFakeAPI(t)
+	g := newGitea(srv.URL, "town", "rig", "tok", []string{"ci/test"})
+	ctx := context.Background()
+
+	api.responses["POST /api/v1/repos/town/rig/pulls"] = map[string]interface{}{
+		"number": 3, "state": "open", "mergeable": true,
+		"head": map[string]string{"ref": "polecat/a", "sha": "def"},
+		"base": map[string]string{"ref": "main"},
+	}
+	api.responses["GET /api/v1/repos/town/rig/commits/def/status"] = map[string]interface{}{
+		"statuses": []map[string]string{{"context": "ci/test", "status": "success"}, {"context": "ci/flaky", "status": "failure"}},
+	}
+	api.responses["POST /api/v1/repos/town/rig/pulls/3/merge"] = map[string]interface{}{}
+	api.responses["GET /api/v1/repos/town/rig/pulls/3"] = map[string]interface{}{
+		"number": 3, "state": "closed", "merged": true, "merge_commit_sha": "fff",
+	}
+
+	pr, err := g.CreatePR(ctx, NewPullRequest{Title: "t", Head: "polecat/a", Base: "main"})
+	if err != nil || pr.Number != 3 || pr.Conflicted {
+		t.Fatalf("CreatePR = %+v, %v", pr, err)
+	}
+	if body := api.bodies["POST /api/v1/repos/town/rig/pulls"]; body["head"] != "polecat/a" || body["base"] != "main" {
+		t.Errorf("create body = %v", body)
+	}
+
+	sum, err := g.Checks(ctx, pr)
+	if err != nil || sum.State != ChecksSuccess {
+		t.Errorf("Checks = %+v, %v (only required ci/test counts)", sum, err)
+	}
+
+	sha, err := g.Merge(ctx, pr, MergeMethodRebase, "ignored")
+	if err != nil || sha != "fff" {
+		t.Errorf("Merge = %q, %v", sha, err)
+	}
+	if body := api.bodies["POST /api/v1/repos/town/rig/pulls/3/merge"]; body["Do"] != "rebase" || body["MergeTitleField"] != nil {
+		t.Errorf("merge body = %v", body)
+	}
+}
@@ -0,0 +1,214 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// gitea implements Forge with the Gitea API (v1), which Forgejo shares.
+type gitea struct {
+	api      *client
+	owner    string
+	repo     string
+	required []string
+}
+
+func newGitea(base, owner, repo, token string, required []string) *gitea {
+	auth := ""
+	if token != "" {
+		auth = "token " + token
+	}
+	base = strings.TrimSuffix(base, "/")
+	if !strings.HasSuffix(base, "/api/v1") {
+		base += "/api/v1"
+	}
+	return &gitea{api: newClient(base, auth, nil), owner: owner, repo: repo, required: required}
+}
+
+func (g *gitea) Type() string { return TypeGitea }
+
+func (g *gitea) path(format string, args ...interface{}) string {
+	return fmt.Sprintf("/repos/%s/%s", url.PathEscape(g.owner), url.PathEscape(g.repo)) + fmt.Sprintf(format, args...)
+}
+
+type giteaPR struct {
+	Number         int       `json:"number"`
+	HTMLURL        string    `json:"html_url"`
+	State          string    `json:"state"`
+	Merged         bool      `json:"merged"`
+	MergeCommitSHA string    `json:"merge_commit_sha"`
+	Mergeable      bool      `json:"mergeable"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	Head           struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+func (p *giteaPR) convert() *PullRequest {
+	pr := &PullRequest{
+		Number:    p.Number,
+		URL:       p.HTMLURL,
+		State:     PROpen,
+		Head:      p.Head.Ref,
+		HeadSHA:   p.Head.SHA,
+		Base:      p.Base.Ref,
+		UpdatedAt: p.UpdatedAt,
+	}
+	switch {
+	case p.Merged:
+		pr.State = PRMerged
+		pr.MergeCommit = p.MergeCommitSHA
+	case p.State == "closed":
+		pr.State = PRClosed
+	default:
+		pr.Conflicted = !p.Mergeable
+	}
+	return pr
+}
+
+// FindPR pages through open pull requests; Gitea has no head filter that
+// works across versions.
+func (g *gitea) FindPR(ctx context.Context, head, base string) (*PullRequest, error) {
+	for page := 1; page <= 20; page++ {
+		var prs []giteaPR
+		if err := g.api.do(ctx, http.MethodGet, g.path("/pulls?state=open&limit=50&page=%d", page), nil, &prs); err != nil {
+			return nil, err
+		}
+		for i := range prs {
+			if prs[i].Head.Ref == head && prs[i].Base.Ref == base {
+				return prs[i].convert(), nil
+			}
+		}
+		if len(prs) < 50 {
+			break
+		}
+	}
+	return nil, nil
+}
+
+func (g *gitea) CreatePR(ctx context.Context, req NewPullRequest) (*PullRequest, error) {
+	in := map[string]string{"title": req.Title, "body": req.Body, "head": req.Head, "base": req.Base}
+	var pr giteaPR
+	if err := g.api.do(ctx, http.MethodPost, g.path("/pulls"), in, &pr); err != nil {
+		return nil, err
+	}
+	return pr.convert(), nil
+}
+
+func (g *gitea) GetPR(ctx context.Context, number int) (*PullRequest, error) {
+	var pr giteaPR
+	if err := g.api.do(ctx, http.MethodGet, g.path("/pulls/%d", number), nil, &pr); err != nil {
+		return nil, err
+	}
+	return pr.convert(), nil
+}
+
+// Checks reads commit statuses, which Gitea and Forgejo Actions report.
+func (g *gitea) Checks(ctx context.Context, pr *PullRequest) (*CheckSummary, error) {
+	var combined struct {
+		Statuses []struct {
+			Context   string `json:"context"`
+			Status    string `json:"status"`
+			TargetURL string `json:"target_url"`
+		} `json:"statuses"`
+	}
+	if err := g.api.do(ctx, http.MethodGet, g.path("/commits/%s/status", pr.HeadSHA), nil, &combined); err != nil {
+		return nil, err
+	}
+	var checks []Check
+	for _, s := range combined.Statuses {
+		checks = append(checks, Check{Name: s.Context, State: statusState(s.Status), URL: s.TargetURL})
+	}
+	return summarize(checks, g.required), nil
+}
+
+type giteaUser struct {
+	Login string `json:"login"`
+}
+
+func (g *gitea) Reviews(ctx context.Context, number int) ([]Review, error) {
+	var reviews []struct {
+		ID            int64     `json:"id"`
+		User          giteaUser `json:"user"`
+		State         string    `json:"state"`
+		Body          string    `json:"body"`
+		HTMLURL       string    `json:"html_url"`
+		SubmittedAt   time.Time `json:"submitted_at"`
+		CommentsCount int       `json:"comments_count"`
+	}
+	if err := g.api.do(ctx, http.MethodGet, g.path("/pulls/%d/reviews", number), nil, &reviews); err != nil {
+		return nil, err
+	}
+
+	var out []Review
+	for _, r := range reviews {
+		var state ReviewState
+		switch r.State {
+		case "APPROVED":
+			state = ReviewApproved
+		case "REQUEST_CHANGES":
+			state = ReviewChangesRequested
+		case "COMMENT":
+			state = ReviewCommented
+		default:
+			continue // PENDING or REQUEST_REVIEW
+		}
+		out = append(out, Review{
+			ID: fmt.Sprintf("review-%d", r.ID), Author: r.User.Login, State: state,
+			Body: r.Body, URL: r.HTMLURL, CreatedAt: r.SubmittedAt,
+		})
+		if r.CommentsCount == 0 {
+			continue
+		}
+		var comments []struct {
+			ID        int64     `json:"id"`
+			User      giteaUser `json:"user"`
+			Body      string    `json:"body"`
+			Path      string    `json:"path"`
+			Position  int       `json:"position"`
+			HTMLURL   string    `json:"html_url"`
+			CreatedAt time.Time `json:"created_at"`
+		}
+		if err := g.api.do(ctx, http.MethodGet, g.path("/pulls/%d/reviews/%d/comments", number, r.ID), nil, &comments); err != nil {
+			return nil, err
+		}
+		for _, c := range comments {
+			out = append(out, Review{
+				ID: fmt.Sprintf("comment-%d", c.ID), Author: c.User.Login, State: ReviewCommented,
+				Body: c.Body, Path: c.Path, Line: c.Position, URL: c.HTMLURL, CreatedAt: c.CreatedAt,
+			})
+		}
+	}
+	sortReviews(out)
+	return out, nil
+}
+
+// Merge merges the pull request. Gitea answers with an empty body, so the
+// merge commit is read back from the pull request.
+func (g *gitea) Merge(ctx context.Context, pr *PullRequest, method, title string) (string, error) {
+	in := map[string]interface{}{"Do": method, "head_commit_id": pr.HeadSHA}
+	if title != "" && method != MergeMethodRebase {
+		in["MergeTitleField"] = title
+	}
+	if err := g.api.do(ctx, http.MethodPost, g.path("/pulls/%d/merge", pr.Number), in, nil); err != nil {
+		return "", err
+	}
+	merged, err := g.GetPR(ctx, pr.Number)
+	if err != nil {
+		return "", fmt.Errorf("merged, but reading the merge commit failed: %w", err)
+	}
+	return merged.MergeCommit, nil
+}
+
+func sortReviews(rs []Review) {
+	sort.SliceStable(rs, func(i, j int) bool { return rs[i].CreatedAt.Before(rs[j].CreatedAt) })
+}
@@ -0,0 +1,240 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// github implements Forge with the GitHub REST API (v3).
+type github struct {
+	api      *client
+	owner    string
+	repo     string
+	required []string
+}
+
+func newGitHub(base, owner, repo, token string, required []string) *github {
+	auth := ""
+	if token != "" {
+		auth = "Bearer " + token
+	}
+	return &github{
+		api: newClient(base, auth, map[string]string{
+			"Accept":               "application/vnd.github+json",
+			"X-GitHub-Api-Version": "2022-11-28",
+		}),
+		owner:    owner,
+		repo:     repo,
+		required: required,
+	}
+}
+
+func (g *github) Type() string { return TypeGitHub }
+
+func (g *github) path(format string, args ...interface{}) string {
+	return fmt.Sprintf("/repos/%s/%s", url.PathEscape(g.owner), url.PathEscape(g.repo)) + fmt.Sprintf(format, args...)
+}
+
+type githubPR struct {
+	Number         int       `json:"number"`
+	HTMLURL        string    `json:"html_url"`
+	State          string    `json:"state"`
+	Merged         bool      `json:"merged"`
+	MergedAt       string    `json:"merged_at"`
+	MergeCommitSHA string    `json:"merge_commit_sha"`
+	Mergeable      *bool     `json:"mergeable"`
+	MergeableState string    `json:"mergeable_state"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	Head           struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+func (p *githubPR) convert() *PullRequest {
+	pr := &PullRequest{
+		Number:     p.Number,
+		URL:        p.HTMLURL,
+		State:      PROpen,
+		Head:       p.Head.Ref,
+		HeadSHA:    p.Head.SHA,
+		Base:       p.Base.Ref,
+		Conflicted: p.MergeableState == "dirty" || (p.Mergeable != nil && !*p.Mergeable),
+		Blocked:    p.MergeableState == "blocked",
+		UpdatedAt:  p.UpdatedAt,
+	}
+	switch {
+	case p.Merged || p.MergedAt != "":
+		pr.State = PRMerged
+		pr.MergeCommit = p.MergeCommitSHA
+	case p.State == "closed":
+		pr.State = PRClosed
+	default:
+		pr.MergeablePending = p.MergeableState == "unknown" || p.MergeableState == "behind"
+	}
+	return pr
+}
+
+func (g *github) FindPR(ctx context.Context, head, base string) (*PullRequest, error) {
+	q := url.Values{"state": {"open"}, "head": {g.owner + ":" + head}, "base": {base}}
+	var prs []githubPR
+	if err := g.api.do(ctx, http.MethodGet, g.path("/pulls?%s", q.Encode()), nil, &prs); err != nil {
+		return nil, err
+	}
+	if len(prs) == 0 {
+		return nil, nil
+	}
+	return prs[0].convert(), nil
+}
+
+func (g *github) CreatePR(ctx context.Context, req NewPullRequest) (*PullRequest, error) {
+	in := map[string]string{"title": req.Title, "body": req.Body, "head": req.Head, "base": req.Base}
+	var pr githubPR
+	if err := g.api.do(ctx, http.MethodPost, g.path("/pulls"), in, &pr); err != nil {
+		return nil, err
+	}
+	return pr.convert(), nil
+}
+
+func (g *github) GetPR(ctx context.Context, number int) (*PullRequest, error) {
+	var pr githubPR
+	if err := g.api.do(ctx, http.MethodGet, g.path("/pulls/%d", number), nil, &pr); err != nil {
+		return nil, err
+	}
+	return pr.convert(), nil
+}
+
+// Checks merges check runs (GitHub Actions and apps) with legacy commit
+// statuses, which GitHub shows side by side on a pull request.
+func (g *github) Checks(ctx context.Context, pr *PullRequest) (*CheckSummary, error) {
+	var runs struct {
+		CheckRuns []struct {
+			Name       string `json:"name"`
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+			HTMLURL    string `json:"html_url"`
+		} `json:"check_runs"`
+	}
+	if err := g.api.do(ctx, http.MethodGet, g.path("/commits/%s/check-runs?per_page=100", pr.HeadSHA), nil, &runs); err != nil {
+		return nil, err
+	}
+	var combined struct {
+		Statuses []struct {
+			Context   string `json:"context"`
+			State     string `json:"state"`
+			TargetURL string `json:"target_url"`
+		} `json:"statuses"`
+	}
+	if err := g.api.do(ctx, http.MethodGet, g.path("/commits/%s/status", pr.HeadSHA), nil, &combined); err != nil {
+		return nil, err
+	}
+
+	var checks []Check
+	for _, r := range runs.CheckRuns {
+		state := ChecksPending
+		if r.Status == "completed" {
+			switch r.Conclusion {
+			case "success", "neutral", "skipped":
+				state = ChecksSuccess
+			default:
+				state = ChecksFailure
+			}
+		}
+		checks = append(checks, Check{Name: r.Name, State: state, URL: r.HTMLURL})
+	}
+	for _, s := range combined.Statuses {
+		checks = append(checks, Check{Name: s.Context, State: statusState(s.State), URL: s.TargetURL})
+	}
+	return summarize(checks, g.required), nil
+}
+
+// statusState maps a commit status state (GitHub and Gitea use the same
+// vocabulary) to a CheckState.
+func statusState(s string) CheckState {
+	switch s {
+	case "success":
+		return ChecksSuccess
+	case "failure", "error":
+		return ChecksFailure
+	default:
+		return ChecksPending
+	}
+}
+
+func (g *github) Reviews(ctx context.Context, number int) ([]Review, error) {
+	var reviews []struct {
+		ID   int64 `json:"id"`
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		State       string    `json:"state"`
+		Body        string    `json:"body"`
+		HTMLURL     string    `json:"html_url"`
+		SubmittedAt time.Time `json:"submitted_at"`
+	}
+	if err := g.api.do(ctx, http.MethodGet, g.path("/pulls/%d/reviews?per_page=100", number), nil, &reviews); err != nil {
+		return nil, err
+	}
+	var comments []struct {
+		ID   int64 `json:"id"`
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		Body      string    `json:"body"`
+		Path      string    `json:"path"`
+		Line      int       `json:"line"`
+		HTMLURL   string    `json:"html_url"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+	if err := g.api.do(ctx, http.MethodGet, g.path("/pulls/%d/comments?per_page=100", number), nil, &comments); err != nil {
+		return nil, err
+	}
+
+	var out []Review
+	for _, r := range reviews {
+		var state ReviewState
+		switch r.State {
+		case "APPROVED":
+			state = ReviewApproved
+		case "CHANGES_REQUESTED":
+			state = ReviewChangesRequested
+		case "COMMENTED":
+			state = ReviewCommented
+		default:
+			continue // PENDING (unsubmitted) or DISMISSED
+		}
+		out = append(out, Review{
+			ID: fmt.Sprintf("review-%d", r.ID), Author: r.User.Login, State: state,
+			Body: r.Body, URL: r.HTMLURL, CreatedAt: r.SubmittedAt,
+		})
+	}
+	for _, c := range comments {
+		out = append(out, Review{
+			ID: fmt.Sprintf("comment-%d", c.ID), Author: c.User.Login, State: ReviewCommented,
+			Body: c.Body, Path: c.Path, Line: c.Line, URL: c.HTMLURL, CreatedAt: c.CreatedAt,
+		})
+	}
+	sortReviews(out)
+	return out, nil
+}
+
+func (g *github) Merge(ctx context.Context, pr *PullRequest, method, title string) (string, error) {
+	in := map[string]string{"merge_method": method, "sha": pr.HeadSHA}
+	if title != "" && method != MergeMethodRebase {
+		in["commit_title"] = title
+	}
+	var out struct {
+		SHA    string `json:"sha"`
+		Merged bool   `json:"merged"`
+	}
+	if err := g.api.do(ctx, http.MethodPut, g.path("/pulls/%d/merge", pr.Number), in, &out); err != nil {
+		return "", err
+	}
+	return out.SHA, nil
+}
@@ -0,0 +1,537 @@
+package formula
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Formula composition. A workflow formula can be built from others:
+//
+//	extends = "shiny"            start from shiny's steps and vars
+//	[[steps]]                    override a step by ID, or add one
+//	before = "review"            (new steps) splice in before/after a step
+//	[[include]]                  add another formula's steps as <prefix>.<id>
+//	[compose]
+//	remove = ["review"]          drop steps
+//	[[compose.expand]]           replace a step with an expansion formula
+//	aspects = ["security-audit"] weave advice around matching steps
+//
+// Resolver.Expand applies these in that order and validates the result.
+
+// composes reports whether f refers to other formulas and must be flattened
+// before use.
+func (f *Formula) composes() bool {
+	return len(f.Extends) > 0 || len(f.Include) > 0 || f.Compose != nil
+}
+
+// Composes reports whether the resolved formula refers to other formulas,
+// i.e. whether Parse flattens it. A formula that fails to decode does not.
+func (r *Resolved) Composes() bool {
+	f, err := decode(r.content)
+	return err == nil && f.composes()
+}
+
+// EncodeTOML writes f as a formula file. Encoding a flattened formula gives
+// a standalone file that tools without composition support (bd cook) can
+// read.
+func (f *Formula) EncodeTOML() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := toml.NewEncoder(&buf)
+	enc.Indent = ""
+	if err := enc.Encode(f); err != nil {
+		return nil, fmt.Errorf("encoding formula: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ComposedFrom returns the formulas flattened into f by Resolver.Expand, in
+// the order they were applied.
+func (f *Formula) ComposedFrom() []string {
+	return f.composedFrom
+}
+
+// Expand resolves name and flattens its composition into a plain, validated
+// formula. A formula that doesn't compose is returned as parsed.
+func (r *Resolver) Expand(name string, tier Tier) (*Formula, error) {
+	res, err := r.Resolve(name, tier)
+	if err != nil {
+		return nil, err
+	}
+	return res.Parse()
+}
+
+// composer flattens one formula, tracking the chain of formulas being
+// loaded to catch cycles.
+type composer struct {
+	r       *Resolver
+	stack   []string
+	sources []string
+}
+
+// flatten composes f (called name) and validates the result.
+func (r *Resolver) flatten(name string, f *Formula) (*Formula, error) {
+	c := &composer{r: r, stack: []string{name}}
+	out, err := c.compose(f)
+	if err != nil {
+		return nil, err
+	}
+	if err := out.Validate(); err != nil {
+		return nil, fmt.Errorf("expanded formula: %w", err)
+	}
+	out.composedFrom = c.sources
+	return out, nil
+}
+
+// load resolves and flattens a formula referred to by the one being composed.
+func (c *composer) load(name string) (*Formula, error) {
+	for _, n := range c.stack {
+		if n == name {
+			return nil, fmt.Errorf("composition cycle: %s -> %s", strings.Join(c.stack, " -> "), name)
+		}
+	}
+	res, err := c.r.Resolve(name, "")
+	if err != nil {
+		return nil, err
+	}
+	f, err := decode(res.content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", res.Path, err)
+	}
+	c.sources = append(c.sources, name)
+
+	c.stack = append(c.stack, name)
+	defer func() { c.stack = c.stack[:len(c.stack)-1] }()
+	return c.compose(f)
+}
+
+// compose applies f's extends, include and compose sections. The result is
+// not validated.
+func (c *composer) compose(f *Formula) (*Formula, error) {
+	if !f.composes() {
+		return f, nil
+	}
+
+	out := &Formula{}
+	for _, parent := range f.Extends {
+		p, err := c.load(parent)
+		if err != nil {
+			return nil, fmt.Errorf("extends %s: %w", parent, err)
+		}
+		if err := out.overlay(p); err != nil {
+			return nil, fmt.Errorf("extends %s: %w", parent, err)
+		}
+	}
+
+	for _, inc := range f.Include {
+		if inc.Formula == "" {
+			return nil, fmt.Errorf("include missing required formula field")
+		}
+		g, err := c.load(inc.Formula)
+		if err != nil {
+			return nil, fmt.Errorf("include %s: %w", inc.Formula, err)
+		}
+		if err := out.include(inc, g); err != nil {
+			return nil, fmt.Errorf("include %s: %w", inc.Formula, err)
+		}
+	}
+
+	if err := out.overlay(f); err != nil {
+		return nil, err
+	}
+
+	if f.Compose != nil {
+		for _, id := range f.Compose.Remove {
+			if err := out.removeStep(id); err != nil {
+				return nil, fmt.Errorf("compose.remove: %w", err)
+			}
+		}
+		for _, rule := range f.Compose.Expand {
+			if rule.Target == "" || rule.With == "" {
+				return nil, fmt.Errorf("compose.expand requires target and with")
+			}
+			exp, err := c.load(rule.With)
+			if err != nil {
+				return nil, fmt.Errorf("compose.expand %s: %w", rule.With, err)
+			}
+			if err := out.expandStep(rule.Target, exp); err != nil {
+				return nil, fmt.Errorf("compose.expand %s: %w", rule.With, err)
+			}
+		}
+		for _, name := range f.Compose.Aspects {
+			aspect, err := c.load(name)
+			if err != nil {
+				return nil, fmt.Errorf("compose.aspects %s: %w", name, err)
+			}
+			if err := out.weave(aspect); err != nil {
+				return nil, fmt.Errorf("compose.aspects %s: %w", name, err)
+			}
+		}
+	}
+
+	out.Extends, out.Include, out.Compose = nil, nil, nil
+	out.inferType()
+	if out.Type != TypeWorkflow {
+		return nil, fmt.Errorf("composition applies to workflow formulas, not %q", out.Type)
+	}
+	return out, nil
+}
+
+// overlay applies child on top of f: header fields and vars that child sets
+// replace f's, steps with a known ID are overridden field by field, and new
+// steps are added (spliced in with before/after, or appended).
+func (f *Formula) overlay(child *Formula) error {
+	if child.Name != "" {
+		f.Name = child.Name
+	}
+	if child.Description != "" {
+		f.Description = child.Description
+	}
+	if child.Type != "" {
+		f.Type = child.Type
+	}
+	if child.Version != 0 {
+		f.Version = child.Version
+	}
+	for name, v := range child.Vars {
+		if f.Vars == nil {
+			f.Vars = map[string]Var{}
+		}
+		f.Vars[name] = v
+	}
+
+	for _, s := range child.Steps {
+		if base := f.GetStep(s.ID); base != nil {
+			if s.Before != "" || s.After != "" {
+				return fmt.Errorf("step %q: before/after only apply to new steps", s.ID)
+			}
+			base.override(s)
+			continue
+		}
+		before, after := s.Before, s.After
+		s.Before, s.After = "", ""
+		var err error
+		switch {
+		case before != "" && after != "":
+			err = fmt.Errorf("step %q: set before or after, not both", s.ID)
+		case before != "":
+			err = f.insertBefore(before, []Step{s})
+		case after != "":
+			err = f.insertAfter(after, []Step{s})
+		default:
+			f.Steps = append(f.Steps, s)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// override replaces the fields of s that o sets.
+func (s *Step) override(o Step) {
+	if o.Title != "" {
+		s.Title = o.Title
+	}
+	if o.Description != "" {
+		s.Description = o.Description
+	}
+	if o.Needs != nil {
+		s.Needs = o.Needs
+	}
+	if o.Parallel {
+		s.Parallel = true
+	}
+	if o.Acceptance != "" {
+		s.Acceptance = o.Acceptance
+	}
+	if o.When != "" {
+		s.When = o.When
+	}
+	if o.Retry != nil {
+		s.Retry = o.Retry
+	}
+	if o.ForEach != "" {
+		s.ForEach, s.As = o.ForEach, o.As
+	}
+	if o.Timeout != "" {
+		s.Timeout = o.Timeout
+	}
+}
+
+// include appends g's steps with IDs prefixed, so that several formulas
+// (or one formula twice) can be combined without collisions. g's vars are
+// added unless f already declares them.
+func (f *Formula) include(inc Include, g *Formula) error {
+	if g.Type != TypeWorkflow {
+		return fmt.Errorf("only workflow formulas can be included, not %q", g.Type)
+	}
+	prefix := inc.Prefix
+	if prefix == "" {
+		prefix = inc.Formula
+	}
+	id := func(s string) string { return prefix + "." + s }
+
+	var pairs []string
+	for _, s := range g.Steps {
+		pairs = append(pairs, "steps."+s.ID+".outcome", "steps."+id(s.ID)+".outcome")
+	}
+	refs := strings.NewReplacer(pairs...)
+
+	for _, s := range g.Steps {
+		s.ID = id(s.ID)
+		needs := make([]string, 0, len(s.Needs))
+		for _, n := range s.Needs {
+			needs = append(needs, id(n))
+		}
+		if len(needs) == 0 {
+			needs = append(needs, inc.Needs...)
+		}
+		s.Needs = needs
+		s.When = refs.Replace(s.When)
+		if s.Retry != nil && s.Retry.From != "" {
+			retry := *s.Retry
+			retry.From = id(retry.From)
+			s.Retry = &retry
+		}
+		f.Steps = append(f.Steps, s)
+	}
+
+	for name, v := range g.Vars {
+		if _, ok := f.Vars[name]; ok {
+			continue
+		}
+		if f.Vars == nil {
+			f.Vars = map[string]Var{}
+		}
+		f.Vars[name] = v
+	}
+	if f.Type == "" {
+		f.Type = TypeWorkflow
+	}
+	return nil
+}
+
+// stepIndex returns the index of step id, or -1.
+func (f *Formula) stepIndex(id string) int {
+	for i := range f.Steps {
+		if f.Steps[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// replaceNeed rewrites every step's need on id to with, except in skip.
+func (f *Formula) replaceNeed(id string, with []string, skip map[string]bool) {
+	for i := range f.Steps {
+		s := &f.Steps[i]
+		if skip[s.ID] {
+			continue
+		}
+		var needs []string
+		changed := false
+		for _, n := range s.Needs {
+			if n == id {
+				needs = append(needs, with...)
+				changed = true
+			} else {
+				needs = append(needs, n)
+			}
+		}
+		if changed {
+			s.Needs = dedupe(needs)
+		}
+	}
+}
+
+// chain links steps in order, each needing the one before it. The first
+// step also needs first.
+func chain(steps []Step, first []string) {
+	for i := range steps {
+		prev := first
+		if i > 0 {
+			prev = []string{steps[i-1].ID}
+		}
+		steps[i].Needs = dedupe(append(append([]string{}, prev...), steps[i].Needs...))
+	}
+}
+
+// insertBefore splices steps in ahead of anchor: they take over its needs
+// and anchor needs the last of them.
+func (f *Formula) insertBefore(anchor string, steps []Step) error {
+	idx := f.stepIndex(anchor)
+	if idx < 0 {
+		return fmt.Errorf("before references unknown step: %s", anchor)
+	}
+	chain(steps, f.Steps[idx].Needs)
+	f.Steps[idx].Needs = []string{steps[len(steps)-1].ID}
+	f.Steps = append(f.Steps[:idx], append(steps, f.Steps[idx:]...)...)
+	return nil
+}
+
+// insertAfter splices steps in behind anchor: they need it, and steps that
+// needed anchor now need the last of them.
+func (f *Formula) insertAfter(anchor string, steps []Step) error {
+	idx := f.stepIndex(anchor)
+	if idx < 0 {
+		return fmt.Errorf("after references unknown step: %s", anchor)
+	}
+	f.replaceNeed(anchor, []string{steps[len(steps)-1].ID}, nil)
+	chain(steps, []string{anchor})
+	f.Steps = append(f.Steps[:idx+1], append(steps, f.Steps[idx+1:]...)...)
+	return nil
+}
+
+// removeStep drops id; steps that needed it need its needs instead.
+func (f *Formula) removeStep(id string) error {
+	idx := f.stepIndex(id)
+	if idx < 0 {
+		return fmt.Errorf("unknown step: %s", id)
+	}
+	removed := f.Steps[idx]
+	f.Steps = append(f.Steps[:idx], f.Steps[idx+1:]...)
+	f.replaceNeed(id, removed.Needs, nil)
+	return nil
+}
+
+// expandStep replaces target with exp's templates. {target},
+// {target.title} and {target.description} refer to the replaced step.
+// Entry templates take over the target's needs and condition; steps that
+// needed the target need the final templates, which also inherit its
+// acceptance criteria.
+func (f *Formula) expandStep(target string, exp *Formula) error {
+	if exp.Type != TypeExpansion {
+		return fmt.Errorf("%s is a %s formula, not an expansion", exp.Name, exp.Type)
+	}
+	if err := exp.validateExpansion(); err != nil {
+		return err
+	}
+	idx := f.stepIndex(target)
+	if idx < 0 {
+		return fmt.Errorf("target references unknown step: %s", target)
+	}
+	t := f.Steps[idx]
+	r := strings.NewReplacer("{target.title}", t.Title, "{target.description}", t.Description, "{target}", t.ID)
+
+	needed := map[string]bool{}
+	steps := make([]Step, 0, len(exp.Template))
+	for _, tmpl := range exp.Template {
+		s := Step{ID: r.Replace(tmpl.ID), Title: r.Replace(tmpl.Title), Description: r.Replace(tmpl.Description)}
+		for _, n := range tmpl.Needs {
+			s.Needs = append(s.Needs, r.Replace(n))
+			needed[r.Replace(n)] = true
+		}
+		if len(tmpl.Needs) == 0 {
+			s.Needs = append(s.Needs, t.Needs...)
+			s.When = t.When
+		}
+		steps = append(steps, s)
+	}
+	var final []string
+	for i := range steps {
+		if !needed[steps[i].ID] {
+			final = append(final, steps[i].ID)
+			steps[i].Acceptance = t.Acceptance
+		}
+	}
+
+	f.Steps = append(f.Steps[:idx], append(steps, f.Steps[idx+1:]...)...)
+	f.replaceNeed(target, final, nil)
+	return nil
+}
+
+// weave applies an aspect formula's advice to f. Advice only matches the
+// steps present before weaving, so added steps are never advised again.
+func (f *Formula) weave(aspect *Formula) error {
+	if aspect.Type != TypeAspect || len(aspect.Advice) == 0 {
+		return fmt.Errorf("%s has no advice to weave", aspect.Name)
+	}
+	if err := aspect.validateAdvice(); err != nil {
+		return err
+	}
+	ids := make([]string, 0, len(f.Steps))
+	for _, s := range f.Steps {
+		ids = append(ids, s.ID)
+	}
+
+	for _, adv := range aspect.Advice {
+		for _, id := range ids {
+			if ok, _ := path.Match(adv.Target, id); !ok || !aspect.inPointcut(id) {
+				continue
+			}
+			step := *f.GetStep(id)
+			if before := adviceSteps(adv.Around.Before, step); len(before) > 0 {
+				if err := f.insertBefore(id, before); err != nil {
+					return err
+				}
+			}
+			if after := adviceSteps(adv.Around.After, step); len(after) > 0 {
+				if err := f.insertAfter(id, after); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// inPointcut reports whether id matches one of the aspect's pointcuts. An
+// aspect without pointcuts applies everywhere its advice matches.
+func (f *Formula) inPointcut(id string) bool {
+	if len(f.Pointcuts) == 0 {
+		return true
+	}
+	for _, pc := range f.Pointcuts {
+		if ok, _ := path.Match(pc.Glob, id); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// adviceSteps instantiates advice steps for the advised step.
+func adviceSteps(tmpl []Step, advised Step) []Step {
+	r := strings.NewReplacer("{step.id}", advised.ID, "{step.title}", advised.Title)
+	steps := make([]Step, 0, len(tmpl))
+	for _, s := range tmpl {
+		s.ID = r.Replace(s.ID)
+		s.Title = r.Replace(s.Title)
+		s.Description = r.Replace(s.Description)
+		s.Acceptance = r.Replace(s.Acceptance)
+		s.Needs = nil
+		steps = append(steps, s)
+	}
+	return steps
+}
+
+// validateAdvice checks an aspect formula's advice and pointcuts.
+func (f *Formula) validateAdvice() error {
+	globs := make([]string, 0, len(f.Advice)+len(f.Pointcuts))
+	for _, adv := range f.Advice {
+		if adv.Target == "" {
+			return fmt.Errorf("advice missing required target field")
+		}
+		if len(adv.Around.Before)+len(adv.Around.After) == 0 {
+			return fmt.Errorf("advice for %q adds no steps", adv.Target)
+		}
+		for _, s := range append(append([]Step{}, adv.Around.Before...), adv.Around.After...) {
+			if s.ID == "" {
+				return fmt.Errorf("advice for %q: step missing required id field", adv.Target)
+			}
+		}
+		globs = append(globs, adv.Target)
+	}
+	for _, pc := range f.Pointcuts {
+		globs = append(globs, pc.Glob)
+	}
+	for _, g := range globs {
+		if _, err := path.Match(g, ""); err != nil {
+			return fmt.Errorf("invalid glob %q: %w", g, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,376 @@
+package formula
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// composeResolver returns a resolver whose town tier holds files.
+func composeResolver(t *testing.T, files map[string]string) *Resolver {
+	t.Helper()
+	town := t.TempDir()
+	dir := filepath.Join(town, ".beads", "formulas")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name+".formula.toml"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewResolver(town, "")
+}
+
+// stepGraph renders steps as "id<-need+need" for comparison.
+func stepGraph(f *Formula) string {
+	var parts []string
+	for _, s := range f.Steps {
+		parts = append(parts, s.ID+"<-"+strings.Join(s.Needs, "+"))
+	}
+	return strings.Join(parts, " ")
+}
+
+const composeBase = `
+formula = "base"
+type = "workflow"
+version = 3
+
+[vars]
+feature = "thing"
+
+[[steps]]
+id = "design"
+title = "Design"
+
+[[steps]]
+id = "implement"
+title = "Implement"
+needs = ["design"]
+
+[[steps]]
+id = "review"
+title = "Review"
+needs = ["implement"]
+
+[[steps]]
+id = "submit"
+title = "Submit"
+needs = ["review"]
+acceptance = "Pushed"
+`
+
+func TestExpand_Extends(t *testing.T) {
+	r := composeResolver(t, map[string]string{
+		"base": composeBase,
+		"child": `
+formula = "child"
+extends = "base"
+description = "Base with linting, no review"
+
+[vars]
+feature = "other"
+
+[[steps]]
+id = "implement"
+title = "Implement carefully"
+acceptance = "Tests pass"
+
+[[steps]]
+id = "lint"
+title = "Lint"
+after = "implement"
+
+[[steps]]
+id = "plan"
+title = "Plan"
+before = "design"
+
+[[steps]]
+id = "announce"
+title = "Announce"
+needs = ["submit"]
+
+[compose]
+remove = ["review"]
+`,
+	})
+
+	f, err := r.Expand("child", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Name != "child" || f.Type != TypeWorkflow || f.Version != 3 || f.Description != "Base with linting, no review" {
+		t.Errorf("header = %q %q v%d %q", f.Name, f.Type, f.Version, f.Description)
+	}
+	if f.Vars["feature"].Default != "other" {
+		t.Errorf("vars = %+v", f.Vars)
+	}
+	want := "plan<- design<-plan implement<-design lint<-implement submit<-lint announce<-submit"
+	if got := stepGraph(f); got != want {
+		t.Errorf("steps = %s\nwant    %s", got, want)
+	}
+	if s := f.GetStep("implement"); s.Title != "Implement carefully" || s.Acceptance != "Tests pass" {
+		t.Errorf("override = %+v", s)
+	}
+	if s := f.GetStep("submit"); s.Acceptance != "Pushed" {
+		t.Errorf("inherited step = %+v", s)
+	}
+	if !reflect.DeepEqual(f.ComposedFrom(), []string{"base"}) {
+		t.Errorf("ComposedFrom = %v", f.ComposedFrom())
+	}
+	if f.Extends != nil || f.Compose != nil {
+		t.Error("composition sections should be cleared after expanding")
+	}
+}
+
+func TestExpand_Include(t *testing.T) {
+	r := composeResolver(t, map[string]string{
+		"checks": `
+formula = "checks"
+type = "workflow"
+[vars]
+suite = "all"
+[[steps]]
+id = "unit"
+[[steps]]
+id = "e2e"
+needs = ["unit"]
+`,
+		"pipeline": `
+formula = "pipeline"
+type = "workflow"
+[[steps]]
+id = "build"
+[[steps]]
+id = "ship"
+needs = ["web.e2e", "api.e2e"]
+
+[[include]]
+formula = "checks"
+prefix = "web"
+needs = ["build"]
+
+[[include]]
+formula = "checks"
+prefix = "api"
+needs = ["build"]
+`,
+	})
+
+	f, err := r.Expand("pipeline", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "web.unit<-build web.e2e<-web.unit api.unit<-build api.e2e<-api.unit build<- ship<-web.e2e+api.e2e"
+	if got := stepGraph(f); got != want {
+		t.Errorf("steps = %s\nwant    %s", got, want)
+	}
+	if _, ok := f.Vars["suite"]; !ok {
+		t.Error("included vars should be merged")
+	}
+}
+
+func TestExpand_AspectsAndExpansion(t *testing.T) {
+	r := composeResolver(t, map[string]string{
+		"base": composeBase,
+		"review-all": `
+formula = "review-all"
+type = "aspect"
+
+[[advice]]
+target = "*implement"
+[[advice.around.after]]
+id = "{step.id}-security-review"
+title = "Security review of {step.title}"
+
+[[pointcuts]]
+glob = "*"
+`,
+		"twice": `
+formula = "twice"
+type = "expansion"
+[[template]]
+id = "{target}.draft"
+title = "Draft: {target.title}"
+[[template]]
+id = "{target}.final"
+needs = ["{target}.draft"]
+`,
+		"secure": `
+formula = "secure"
+extends = ["base"]
+
+[[include]]
+formula = "base"
+prefix = "docs"
+needs = ["submit"]
+
+[compose]
+aspects = ["review-all"]
+
+[[compose.expand]]
+target = "submit"
+with = "twice"
+`,
+	})
+
+	f, err := r.Expand("secure", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "design<- implement<-design implement-security-review<-implement review<-implement-security-review " +
+		"submit.draft<-review submit.final<-submit.draft " +
+		"docs.design<-submit.final docs.implement<-docs.design docs.implement-security-review<-docs.implement " +
+		"docs.review<-docs.implement-security-review docs.submit<-docs.review"
+	if got := stepGraph(f); got != want {
+		t.Errorf("steps = %s\nwant    %s", got, want)
+	}
+	if s := f.GetStep("implement-security-review"); s.Title != "Security review of Implement" {
+		t.Errorf("advice step = %+v", s)
+	}
+	if s := f.GetStep("submit.draft"); s.Title != "Draft: Submit" {
+		t.Errorf("expanded step = %+v", s)
+	}
+	if s := f.GetStep("submit.final"); s.Acceptance != "Pushed" {
+		t.Errorf("final expansion step should inherit acceptance: %+v", s)
+	}
+}
+
+func TestExpand_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		formula string
+		want    string
+	}{
+		{"cycle", `formula = "a"
+extends = "loop"`, "composition cycle"},
+		{"missing parent", `formula = "a"
+extends = "nope"`, "formula not found"},
+		{"unknown anchor", `formula = "a"
+extends = "base"
+[[steps]]
+id = "x"
+after = "nope"`, "unknown step: nope"},
+		{"remove unknown", `formula = "a"
+extends = "base"
+[compose]
+remove = ["nope"]`, "unknown step: nope"},
+		{"condition on removed step", `formula = "a"
+extends = "base"
+[compose]
+remove = ["design"]
+[[steps]]
+id = "implement"
+when = "steps.design.outcome == success"`, "expanded formula"},
+		{"aspect is not an aspect", `formula = "a"
+extends = "base"
+[compose]
+aspects = ["base"]`, "no advice to weave"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := composeResolver(t, map[string]string{
+				"base": composeBase,
+				"loop": `formula = "loop"
+extends = "a"`,
+				"a": tt.formula,
+			})
+			_, err := r.Expand("a", "")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_RejectsComposedFormula(t *testing.T) {
+	_, err := Parse([]byte(`formula = "a"
+extends = "shiny"`))
+	if err == nil || !strings.Contains(err.Error(), "Resolver") {
+		t.Errorf("err = %v", err)
+	}
+	_, err = Parse([]byte(`formula = "a"
+[[steps]]
+id = "x"
+after = "y"`))
+	if err == nil || !strings.Contains(err.Error(), "extends another") {
+		t.Errorf("before/after outside extends: err = %v", err)
+	}
+}
+
+func TestExpand_EmbeddedFormulas(t *testing.T) {
+	r := NewResolver("", "")
+	list, err := r.List(TierSystem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, res := range list {
+		if _, err := res.Parse(); err != nil && !errors.Is(err, ErrFormulaNotFound) {
+			t.Errorf("%s: %v", res.Name, err)
+		}
+	}
+
+	f, err := r.Expand("shiny-secure", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"threat-model", "implement-security-prescan", "implement-security-postscan", "submit-security-postscan"} {
+		if f.GetStep(id) == nil {
+			t.Errorf("shiny-secure missing step %s: %s", id, stepGraph(f))
+		}
+	}
+	if s := f.GetStep("review"); s == nil || s.Title != "Security review" || len(s.Needs) != 1 {
+		t.Errorf("shiny-secure should override review in place: %+v", s)
+	}
+	f, err = r.Expand("shiny-enterprise", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.GetStep("implement") != nil || f.GetStep("implement.refine-4") == nil {
+		t.Errorf("shiny-enterprise should expand implement: %s", stepGraph(f))
+	}
+	if f.GetStep("threat-model") == nil {
+		t.Errorf("shiny-enterprise should extend shiny-secure: %s", stepGraph(f))
+	}
+	if s := f.GetStep("document"); s == nil || strings.Join(s.Needs, "+") != "test" {
+		t.Errorf("shiny-enterprise document step = %+v, want after test", s)
+	}
+}
+
+func TestEncodeTOML_FlattenedIsStandalone(t *testing.T) {
+	r := NewResolver("", "")
+	res, err := r.Resolve("shiny-enterprise", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Composes() {
+		t.Fatal("shiny-enterprise should compose")
+	}
+	f, err := res.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := f.EncodeTOML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := Parse(data)
+	if err != nil {
+		t.Fatalf("flattened formula does not parse standalone: %v\n%s", err, data)
+	}
+	if g.Name != "shiny-enterprise" || stepGraph(g) != stepGraph(f) {
+		t.Errorf("round trip = %s %s, want shiny-enterprise %s", g.Name, stepGraph(g), stepGraph(f))
+	}
+
+	plain, err := r.Resolve("shiny", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain.Composes() {
+		t.Error("shiny should not compose")
+	}
+}
@@ -0,0 +1,281 @@
+package formula
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// A Condition is a parsed step `when` expression. The language is small:
+//
+//	vars.<name>              a formula variable
+//	steps.<id>.outcome       "success", "failure", "skipped", or "" if unfinished
+//	"text", 'text', word     string literals (a bare word is a literal)
+//	a == b, a != b           string comparison
+//	!a, a && b, a || b, (a)  boolean logic
+//
+// A value used as a boolean is true unless it is "", "false" or "0".
+type Condition struct {
+	src  string
+	root condNode
+}
+
+// ConditionEnv supplies the values a condition refers to.
+type ConditionEnv struct {
+	Vars     map[string]string
+	Outcomes map[string]StepOutcome
+}
+
+type condNode interface {
+	eval(env *ConditionEnv) string
+}
+
+type (
+	condLiteral string
+	condVar     string
+	condOutcome string
+	condNot     struct{ x condNode }
+	condBinary  struct {
+		op   string
+		x, y condNode
+	}
+)
+
+func (n condLiteral) eval(*ConditionEnv) string { return string(n) }
+func (n condVar) eval(env *ConditionEnv) string { return env.Vars[string(n)] }
+func (n condOutcome) eval(env *ConditionEnv) string {
+	return string(env.Outcomes[string(n)])
+}
+func (n condNot) eval(env *ConditionEnv) string { return condBool(!truthy(n.x.eval(env))) }
+func (n condBinary) eval(env *ConditionEnv) string {
+	switch n.op {
+	case "==":
+		return condBool(n.x.eval(env) == n.y.eval(env))
+	case "!=":
+		return condBool(n.x.eval(env) != n.y.eval(env))
+	case "&&":
+		return condBool(truthy(n.x.eval(env)) && truthy(n.y.eval(env)))
+	default: // "||"
+		return condBool(truthy(n.x.eval(env)) || truthy(n.y.eval(env)))
+	}
+}
+
+func condBool(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+func truthy(s string) bool {
+	return s != "" && s != "false" && s != "0"
+}
+
+// ParseCondition parses a `when` expression.
+func ParseCondition(src string) (*Condition, error) {
+	toks, err := lexCondition(src)
+	if err != nil {
+		return nil, fmt.Errorf("when %q: %w", src, err)
+	}
+	p := &condParser{toks: toks}
+	root, err := p.parseOr()
+	if err == nil && p.pos < len(p.toks) {
+		err = fmt.Errorf("unexpected %q", p.toks[p.pos].text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("when %q: %w", src, err)
+	}
+	return &Condition{src: src, root: root}, nil
+}
+
+// Eval evaluates the condition.
+func (c *Condition) Eval(env *ConditionEnv) bool {
+	if env == nil {
+		env = &ConditionEnv{}
+	}
+	return truthy(c.root.eval(env))
+}
+
+// String returns the source expression.
+func (c *Condition) String() string {
+	return c.src
+}
+
+// Refs returns the vars and steps the condition refers to.
+func (c *Condition) Refs() (vars, steps []string) {
+	var walk func(n condNode)
+	walk = func(n condNode) {
+		switch n := n.(type) {
+		case condVar:
+			vars = append(vars, string(n))
+		case condOutcome:
+			steps = append(steps, string(n))
+		case condNot:
+			walk(n.x)
+		case condBinary:
+			walk(n.x)
+			walk(n.y)
+		}
+	}
+	walk(c.root)
+	return vars, steps
+}
+
+type condToken struct {
+	text   string
+	quoted bool
+}
+
+func lexCondition(src string) ([]condToken, error) {
+	var toks []condToken
+	rs := []rune(src)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			toks = append(toks, condToken{text: string(r)})
+			i++
+		case r == '!' && i+1 < len(rs) && rs[i+1] == '=':
+			toks = append(toks, condToken{text: "!="})
+			i += 2
+		case r == '!':
+			toks = append(toks, condToken{text: "!"})
+			i++
+		case (r == '=' || r == '&' || r == '|') && i+1 < len(rs) && rs[i+1] == r:
+			toks = append(toks, condToken{text: string([]rune{r, r})})
+			i += 2
+		case r == '"' || r == '\'':
+			j := i + 1
+			for j < len(rs) && rs[j] != r {
+				j++
+			}
+			if j == len(rs) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			toks = append(toks, condToken{text: string(rs[i+1 : j]), quoted: true})
+			i = j + 1
+		case isCondWordRune(r):
+			j := i
+			for j < len(rs) && isCondWordRune(rs[j]) {
+				j++
+			}
+			toks = append(toks, condToken{text: string(rs[i:j])})
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected %q", r)
+		}
+	}
+	return toks, nil
+}
+
+func isCondWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.'
+}
+
+type condParser struct {
+	toks []condToken
+	pos  int
+}
+
+func (p *condParser) peek() string {
+	if p.pos < len(p.toks) && !p.toks[p.pos].quoted {
+		return p.toks[p.pos].text
+	}
+	return ""
+}
+
+func (p *condParser) parseOr() (condNode, error) {
+	x, err := p.parseAnd()
+	for err == nil && p.peek() == "||" {
+		p.pos++
+		var y condNode
+		if y, err = p.parseAnd(); err == nil {
+			x = condBinary{op: "||", x: x, y: y}
+		}
+	}
+	return x, err
+}
+
+func (p *condParser) parseAnd() (condNode, error) {
+	x, err := p.parseNot()
+	for err == nil && p.peek() == "&&" {
+		p.pos++
+		var y condNode
+		if y, err = p.parseNot(); err == nil {
+			x = condBinary{op: "&&", x: x, y: y}
+		}
+	}
+	return x, err
+}
+
+func (p *condParser) parseNot() (condNode, error) {
+	if p.peek() == "!" {
+		p.pos++
+		x, err := p.parseNot()
+		return condNot{x: x}, err
+	}
+	return p.parseCompare()
+}
+
+func (p *condParser) parseCompare() (condNode, error) {
+	x, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if op := p.peek(); op == "==" || op == "!=" {
+		p.pos++
+		y, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return condBinary{op: op, x: x, y: y}, nil
+	}
+	return x, nil
+}
+
+func (p *condParser) parseOperand() (condNode, error) {
+	if p.pos >= len(p.toks) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	tok := p.toks[p.pos]
+	p.pos++
+	if tok.quoted {
+		return condLiteral(tok.text), nil
+	}
+	switch {
+	case tok.text == "(":
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return x, nil
+	case strings.HasPrefix(tok.text, "vars."):
+		name := strings.TrimPrefix(tok.text, "vars.")
+		if name == "" {
+			return nil, fmt.Errorf("empty var name in %q", tok.text)
+		}
+		return condVar(name), nil
+	case strings.HasPrefix(tok.text, "steps."):
+		id, field, ok := cutLast(strings.TrimPrefix(tok.text, "steps."), ".")
+		if !ok || id == "" || field != "outcome" {
+			return nil, fmt.Errorf("%q: want steps.<id>.outcome", tok.text)
+		}
+		return condOutcome(id), nil
+	case isCondWordRune([]rune(tok.text)[0]):
+		return condLiteral(tok.text), nil
+	}
+	return nil, fmt.Errorf("unexpected %q", tok.text)
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
@@ -0,0 +1,453 @@
+package formula
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRetries bounds Retry.Max so a failing step cannot loop indefinitely.
+const maxRetries = 10
+
+// defaultForEachVar is the item placeholder for foreach steps without `as`.
+const defaultForEachVar = "item"
+
+var identPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ErrStepControlUnsupported is returned by Validate for a step that uses
+// when, retry, foreach or timeout. Poured molecules are worked step by step
+// by agents, and neither bd nor gt skips, retries, expands or times out a
+// step, so a formula using them would quietly do less than it says. The
+// settings are parsed and checked, and RunState implements them, for the
+// runner that will lift this.
+var ErrStepControlUnsupported = errors.New("poured molecules don't run step control yet; spell it out in the step description")
+
+// StepOutcome is how a finished workflow step ended.
+type StepOutcome string
+
+const (
+	OutcomeSuccess StepOutcome = "success"
+	OutcomeFailure StepOutcome = "failure"
+	OutcomeSkipped StepOutcome = "skipped"
+)
+
+// RunState tracks one run of a workflow formula: the vars it was started
+// with, the outcome of each finished step, and retries used per step.
+type RunState struct {
+	Vars     map[string]string
+	Outcomes map[string]StepOutcome
+	Attempts map[string]int
+}
+
+// NewRunState returns an empty run with the given vars.
+func NewRunState(vars map[string]string) *RunState {
+	return &RunState{Vars: vars, Outcomes: map[string]StepOutcome{}, Attempts: map[string]int{}}
+}
+
+// Complete records that step id succeeded.
+func (s *RunState) Complete(id string) {
+	s.Outcomes[id] = OutcomeSuccess
+}
+
+// RetryDecision is the result of recording a step failure.
+type RetryDecision struct {
+	// Retry is false once the step's retries are used up (or it has none);
+	// the failure then stands.
+	Retry bool
+	// Attempt is the retry number (1 for the first retry).
+	Attempt int
+	// Delay is how long to wait before re-running.
+	Delay time.Duration
+	// Rerun lists the steps whose outcomes were cleared to run again, in
+	// workflow order: the retry's from step through the failed step.
+	Rerun []string
+}
+
+// TimeoutDuration returns the step's timeout, or 0 if it has none.
+func (s *Step) TimeoutDuration() time.Duration {
+	d, _ := time.ParseDuration(s.Timeout)
+	return d
+}
+
+// Delay returns the backoff before retry number attempt (1-based): Backoff
+// doubled for each earlier retry, capped at MaxBackoff.
+func (r *Retry) Delay(attempt int) time.Duration {
+	d, _ := time.ParseDuration(r.Backoff)
+	limit, _ := time.ParseDuration(r.MaxBackoff)
+	for i := 1; i < attempt && d > 0; i++ {
+		d *= 2
+		if limit > 0 && d >= limit {
+			break
+		}
+	}
+	if limit > 0 && d > limit {
+		d = limit
+	}
+	return d
+}
+
+// validateStepControl checks when, retry, foreach and timeout on each step.
+// Conditions and retries may only refer to steps the step needs (directly or
+// transitively), since only those are guaranteed to have finished.
+func (f *Formula) validateStepControl() error {
+	foreach := map[string]bool{}
+	for _, step := range f.Steps {
+		if step.ForEach != "" {
+			foreach[step.ID] = true
+		}
+	}
+
+	for _, step := range f.Steps {
+		ancestors := f.ancestors(step.ID)
+
+		if step.When != "" {
+			cond, err := ParseCondition(step.When)
+			if err != nil {
+				return fmt.Errorf("step %q: %w", step.ID, err)
+			}
+			vars, steps := cond.Refs()
+			for _, v := range vars {
+				if _, ok := f.Vars[v]; !ok {
+					return fmt.Errorf("step %q: when refers to undeclared var %q", step.ID, v)
+				}
+			}
+			for _, s := range steps {
+				if !ancestors[s] {
+					return fmt.Errorf("step %q: when refers to step %q, which it does not need", step.ID, s)
+				}
+			}
+		}
+
+		if r := step.Retry; r != nil {
+			if r.Max < 1 || r.Max > maxRetries {
+				return fmt.Errorf("step %q: retry max must be between 1 and %d, got %d", step.ID, maxRetries, r.Max)
+			}
+			for _, d := range []struct{ name, value string }{{"backoff", r.Backoff}, {"max_backoff", r.MaxBackoff}} {
+				if d.value == "" {
+					continue
+				}
+				if v, err := time.ParseDuration(d.value); err != nil || v < 0 {
+					return fmt.Errorf("step %q: invalid retry %s %q", step.ID, d.name, d.value)
+				}
+			}
+			if r.From != "" && r.From != step.ID {
+				if !ancestors[r.From] {
+					return fmt.Errorf("step %q: retry from %q must be a step it needs", step.ID, r.From)
+				}
+				if foreach[r.From] {
+					return fmt.Errorf("step %q: retry from %q cannot be a foreach step", step.ID, r.From)
+				}
+			}
+		}
+
+		if step.ForEach != "" {
+			v, ok := f.Vars[step.ForEach]
+			if !ok {
+				return fmt.Errorf("step %q: foreach refers to undeclared var %q", step.ID, step.ForEach)
+			}
+			if v.Type != "" && v.Type != VarList {
+				return fmt.Errorf("step %q: foreach var %q is a %s, not a list", step.ID, step.ForEach, v.Type)
+			}
+			if step.As != "" && !identPattern.MatchString(step.As) {
+				return fmt.Errorf("step %q: invalid foreach name %q", step.ID, step.As)
+			}
+		} else if step.As != "" {
+			return fmt.Errorf("step %q: as is only valid with foreach", step.ID)
+		}
+
+		if step.Timeout != "" {
+			if d, err := time.ParseDuration(step.Timeout); err != nil || d <= 0 {
+				return fmt.Errorf("step %q: invalid timeout %q", step.ID, step.Timeout)
+			}
+		}
+	}
+	return nil
+}
+
+// rejectStepControl fails with ErrStepControlUnsupported for the first step
+// that uses when, retry, foreach or timeout.
+func (f *Formula) rejectStepControl() error {
+	for _, step := range f.Steps {
+		var keys []string
+		if step.When != "" {
+			keys = append(keys, "when")
+		}
+		if step.Retry != nil {
+			keys = append(keys, "retry")
+		}
+		if step.ForEach != "" {
+			keys = append(keys, "foreach")
+		}
+		if step.Timeout != "" {
+			keys = append(keys, "timeout")
+		}
+		if len(keys) > 0 {
+			return fmt.Errorf("step %q uses %s: %w", step.ID, strings.Join(keys, ", "), ErrStepControlUnsupported)
+		}
+	}
+	return nil
+}
+
+// ancestors returns every step id transitively needs.
+func (f *Formula) ancestors(id string) map[string]bool {
+	seen := map[string]bool{}
+	var visit func(id string)
+	visit = func(id string) {
+		step := f.GetStep(id)
+		if step == nil {
+			return
+		}
+		for _, need := range step.Needs {
+			if !seen[need] {
+				seen[need] = true
+				visit(need)
+			}
+		}
+	}
+	visit(id)
+	return seen
+}
+
+// conditionEnv returns the values conditions see: declared var defaults
+// overlaid with the run's vars, and step outcomes.
+func (f *Formula) conditionEnv(state *RunState) *ConditionEnv {
+	vars := make(map[string]string, len(f.Vars)+len(state.Vars))
+	for name, v := range f.Vars {
+		vars[name] = v.Default
+	}
+	for name, v := range state.Vars {
+		vars[name] = v
+	}
+	outcomes := make(map[string]StepOutcome, len(state.Outcomes)+len(f.expanded))
+	for id, o := range state.Outcomes {
+		outcomes[id] = o
+	}
+	for parent, children := range f.expanded {
+		outcomes[parent] = aggregateOutcome(children, state.Outcomes)
+	}
+	return &ConditionEnv{Vars: vars, Outcomes: outcomes}
+}
+
+// aggregateOutcome is a foreach step's outcome: unfinished until every
+// child finishes, then failure if any failed, skipped if all were skipped,
+// and success otherwise.
+func aggregateOutcome(children []string, outcomes map[string]StepOutcome) StepOutcome {
+	result := OutcomeSkipped
+	for _, id := range children {
+		switch outcomes[id] {
+		case "":
+			return ""
+		case OutcomeFailure:
+			result = OutcomeFailure
+		case OutcomeSuccess:
+			if result == OutcomeSkipped {
+				result = OutcomeSuccess
+			}
+		}
+	}
+	return result
+}
+
+// Advance returns the workflow steps that can start now, in formula order.
+// A step is considered once every step it needs has an outcome. Steps whose
+// `when` is false are recorded as skipped in state, which lets their
+// dependents proceed. A step without `when` waits forever behind a failed
+// need; give it a `when` on that need's outcome to run anyway.
+func (f *Formula) Advance(state *RunState) ([]string, error) {
+	if state.Outcomes == nil {
+		state.Outcomes = map[string]StepOutcome{}
+	}
+	for {
+		env := f.conditionEnv(state)
+		var ready []string
+		skipped := false
+		for _, step := range f.Steps {
+			if state.Outcomes[step.ID] != "" {
+				continue
+			}
+			finished, failed := true, false
+			for _, need := range step.Needs {
+				switch state.Outcomes[need] {
+				case "":
+					finished = false
+				case OutcomeFailure:
+					failed = true
+				}
+			}
+			if !finished {
+				continue
+			}
+			if step.When == "" {
+				if !failed {
+					ready = append(ready, step.ID)
+				}
+				continue
+			}
+			cond, err := ParseCondition(step.When)
+			if err != nil {
+				return nil, fmt.Errorf("step %q: %w", step.ID, err)
+			}
+			if cond.Eval(env) {
+				ready = append(ready, step.ID)
+			} else {
+				state.Outcomes[step.ID] = OutcomeSkipped
+				skipped = true
+			}
+		}
+		if !skipped {
+			return ready, nil
+		}
+	}
+}
+
+// Fail records that step id failed and applies its retry policy. When a
+// retry is due, the outcomes of the steps to re-run are cleared so Advance
+// offers them again.
+func (f *Formula) Fail(id string, state *RunState) (*RetryDecision, error) {
+	step := f.GetStep(id)
+	if step == nil {
+		return nil, fmt.Errorf("unknown step %q", id)
+	}
+	if state.Outcomes == nil {
+		state.Outcomes = map[string]StepOutcome{}
+	}
+	if state.Attempts == nil {
+		state.Attempts = map[string]int{}
+	}
+	state.Outcomes[id] = OutcomeFailure
+
+	decision := &RetryDecision{Attempt: state.Attempts[id]}
+	if step.Retry == nil || state.Attempts[id] >= step.Retry.Max {
+		return decision, nil
+	}
+	state.Attempts[id]++
+	decision.Retry = true
+	decision.Attempt = state.Attempts[id]
+	decision.Delay = step.Retry.Delay(decision.Attempt)
+
+	from := step.Retry.From
+	if from == "" {
+		from = id
+	}
+	// Re-run the from step and everything after it that leads to the
+	// failed step.
+	for _, s := range f.Steps {
+		if s.ID == from || s.ID == id || (f.ancestors(s.ID)[from] && f.ancestors(id)[s.ID]) {
+			delete(state.Outcomes, s.ID)
+			decision.Rerun = append(decision.Rerun, s.ID)
+		}
+	}
+	return decision, nil
+}
+
+// ExpandForEach returns a copy of the formula with every foreach step
+// replaced by one step per item of its list var, taken from vars or the
+// var's default. A list var holds items separated by commas or newlines.
+//
+// Child steps are named <id>-1, <id>-2, ...; {{<as>}} and {{index}} in
+// their title, description, acceptance and when are replaced with the item
+// and its 1-based position. Steps that needed the foreach step need all of
+// its children. Conditions on steps.<id>.outcome of the foreach step see the
+// children's combined outcome.
+func (f *Formula) ExpandForEach(vars map[string]string) (*Formula, error) {
+	out := *f
+	out.Steps = nil
+	out.expanded = map[string][]string{}
+	for parent, children := range f.expanded {
+		out.expanded[parent] = children
+	}
+	parentNeeds := map[string][]string{}
+
+	for _, step := range f.Steps {
+		if step.ForEach == "" {
+			out.Steps = append(out.Steps, step)
+			continue
+		}
+		value, ok := vars[step.ForEach]
+		if !ok {
+			value = f.Vars[step.ForEach].Default
+		}
+		as := step.As
+		if as == "" {
+			as = defaultForEachVar
+		}
+		children := []string{}
+		for i, item := range SplitListVar(value) {
+			child := step
+			child.ID = fmt.Sprintf("%s-%d", step.ID, i+1)
+			child.ForEach, child.As = "", ""
+			r := strings.NewReplacer("{{"+as+"}}", item, "{{index}}", strconv.Itoa(i+1))
+			child.Title = r.Replace(step.Title)
+			child.Description = r.Replace(step.Description)
+			child.Acceptance = r.Replace(step.Acceptance)
+			child.When = r.Replace(step.When)
+			out.Steps = append(out.Steps, child)
+			children = append(children, child.ID)
+		}
+		out.expanded[step.ID] = children
+		parentNeeds[step.ID] = step.Needs
+	}
+
+	// Point needs at a foreach step's children; an empty foreach passes its
+	// own needs through so ordering is kept.
+	var remap func(needs []string, depth int) ([]string, error)
+	remap = func(needs []string, depth int) ([]string, error) {
+		if depth > len(f.Steps) {
+			return nil, fmt.Errorf("cycle expanding foreach steps")
+		}
+		var result []string
+		for _, need := range needs {
+			children, isParent := out.expanded[need]
+			switch {
+			case !isParent:
+				result = append(result, need)
+				continue
+			case len(children) > 0:
+				result = append(result, children...)
+				continue
+			}
+			inner, err := remap(parentNeeds[need], depth+1)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, inner...)
+		}
+		return dedupe(result), nil
+	}
+	for i := range out.Steps {
+		needs, err := remap(out.Steps[i].Needs, 0)
+		if err != nil {
+			return nil, err
+		}
+		out.Steps[i].Needs = needs
+	}
+	return &out, nil
+}
+
+// SplitListVar splits a list var's value into items, on commas or newlines.
+func SplitListVar(value string) []string {
+	var items []string
+	for _, line := range strings.Split(value, "\n") {
+		for _, item := range strings.Split(line, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+func dedupe(ids []string) []string {
+	seen := map[string]bool{}
+	out := ids[:0]
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
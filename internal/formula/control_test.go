@@ -0,0 +1,288 @@
+package formula
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCondition(t *testing.T) {
+	env := &ConditionEnv{
+		Vars:     map[string]string{"mode": "full", "dry": "false", "empty": ""},
+		Outcomes: map[string]StepOutcome{"test": OutcomeFailure, "build": OutcomeSuccess},
+	}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`vars.mode == "full"`, true},
+		{`vars.mode == 'quick'`, false},
+		{`vars.mode != quick`, true},
+		{`vars.dry`, false},
+		{`!vars.dry`, true},
+		{`vars.empty`, false},
+		{`steps.test.outcome == "failure"`, true},
+		{`steps.build.outcome == success && steps.test.outcome == success`, false},
+		{`steps.build.outcome == success || steps.test.outcome == success`, true},
+		{`!(vars.mode == full) || steps.lint.outcome == ""`, true},
+		{`true`, true},
+	}
+	for _, tt := range tests {
+		c, err := ParseCondition(tt.expr)
+		if err != nil {
+			t.Errorf("ParseCondition(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := c.Eval(env); got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{`vars.mode ==`, `(vars.mode`, `"open`, `steps.test == failure`, `vars.a = b`} {
+		if _, err := ParseCondition(bad); err == nil {
+			t.Errorf("ParseCondition(%q) should fail", bad)
+		}
+	}
+
+	c, _ := ParseCondition(`vars.a == x && (steps.s1.outcome != failure || vars.b)`)
+	vars, steps := c.Refs()
+	if !reflect.DeepEqual(vars, []string{"a", "b"}) || !reflect.DeepEqual(steps, []string{"s1"}) {
+		t.Errorf("Refs() = %v, %v", vars, steps)
+	}
+}
+
+const controlFormula = `
+formula = "release"
+type = "workflow"
+version = 1
+
+[vars.targets]
+default = "linux, darwin"
+[vars.notify]
+default = "false"
+
+[[steps]]
+id = "implement"
+title = "Implement"
+
+[[steps]]
+id = "test"
+title = "Test"
+needs = ["implement"]
+timeout = "30m"
+retry = { max = 2, from = "implement", backoff = "1m", max_backoff = "90s" }
+
+[[steps]]
+id = "build"
+title = "Build {{target}} ({{index}})"
+needs = ["test"]
+foreach = "targets"
+as = "target"
+
+[[steps]]
+id = "announce"
+title = "Announce"
+needs = ["build"]
+when = "vars.notify"
+
+[[steps]]
+id = "done"
+title = "Done"
+needs = ["announce"]
+`
+
+// decodeControl decodes a formula and checks its step control without
+// rejecting it, as the runner that lifts ErrStepControlUnsupported will.
+func decodeControl(t *testing.T, src string) *Formula {
+	t.Helper()
+	f, err := decode([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.validateStepControl(); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestParse_StepControl(t *testing.T) {
+	// Nothing runs step control for poured molecules, so Validate refuses it.
+	_, err := Parse([]byte(controlFormula))
+	if !errors.Is(err, ErrStepControlUnsupported) || !strings.Contains(err.Error(), `step "test" uses retry, timeout`) {
+		t.Fatalf("Parse err = %v, want ErrStepControlUnsupported for test", err)
+	}
+
+	f := decodeControl(t, controlFormula)
+	test := f.GetStep("test")
+	if test.Retry == nil || test.Retry.Max != 2 || test.Retry.From != "implement" {
+		t.Errorf("retry = %+v", test.Retry)
+	}
+	if test.TimeoutDuration() != 30*time.Minute {
+		t.Errorf("timeout = %v", test.TimeoutDuration())
+	}
+	if d := test.Retry.Delay(1); d != time.Minute {
+		t.Errorf("Delay(1) = %v", d)
+	}
+	if d := test.Retry.Delay(2); d != 90*time.Second {
+		t.Errorf("Delay(2) = %v, want capped at max_backoff", d)
+	}
+
+	// retry may be a bare count.
+	short := decodeControl(t, `formula = "x"
+[[steps]]
+id = "a"
+retry = 3
+`)
+	if short.Steps[0].Retry == nil || short.Steps[0].Retry.Max != 3 {
+		t.Errorf("retry = 3: %+v", short.Steps[0].Retry)
+	}
+}
+
+func TestValidate_StepControl(t *testing.T) {
+	tests := []struct {
+		name, steps, want string
+	}{
+		{"when unknown var", `[[steps]]
+id = "a"
+when = "vars.nope"`, "undeclared var"},
+		{"when on non-ancestor", `[[steps]]
+id = "a"
+[[steps]]
+id = "b"
+when = "steps.a.outcome == success"`, "does not need"},
+		{"bad when", `[[steps]]
+id = "a"
+when = "vars.x =="`, "unexpected end"},
+		{"unbounded retry", `[[steps]]
+id = "a"
+retry = 100`, "between 1 and"},
+		{"retry from non-ancestor", `[[steps]]
+id = "a"
+[[steps]]
+id = "b"
+retry = { max = 1, from = "a" }`, "must be a step it needs"},
+		{"bad timeout", `[[steps]]
+id = "a"
+timeout = "soon"`, "invalid timeout"},
+		{"foreach unknown var", `[[steps]]
+id = "a"
+foreach = "nope"`, "undeclared var"},
+		{"as without foreach", `[[steps]]
+id = "a"
+as = "x"`, "only valid with foreach"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte("formula = \"x\"\n[vars]\nx = \"1\"\n" + tt.steps))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdvance_WhenSkipsAndRetries(t *testing.T) {
+	f, err := decodeControl(t, controlFormula).ExpandForEach(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := NewRunState(nil)
+	next := func(want ...string) {
+		t.Helper()
+		got, err := f.Advance(state)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Fatalf("Advance = %v, want %v", got, want)
+		}
+	}
+
+	next("implement")
+	state.Complete("implement")
+	next("test")
+
+	// test fails: retry from implement, twice.
+	for attempt := 1; attempt <= 2; attempt++ {
+		d, err := f.Fail("test", state)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !d.Retry || d.Attempt != attempt || !reflect.DeepEqual(d.Rerun, []string{"implement", "test"}) {
+			t.Fatalf("retry %d = %+v", attempt, d)
+		}
+		next("implement")
+		state.Complete("implement")
+		next("test")
+	}
+	if d, _ := f.Fail("test", state); d.Retry {
+		t.Fatalf("third failure should exhaust retries: %+v", d)
+	}
+	next() // build waits behind the failed test
+	state.Outcomes["test"] = OutcomeSuccess
+
+	next("build-1", "build-2")
+	state.Complete("build-1")
+	state.Complete("build-2")
+
+	// announce's when is false (notify defaults to "false"), so it is
+	// skipped and done runs.
+	next("done")
+	if state.Outcomes["announce"] != OutcomeSkipped {
+		t.Errorf("announce outcome = %q, want skipped", state.Outcomes["announce"])
+	}
+
+	// With notify set, announce runs.
+	state = NewRunState(map[string]string{"notify": "true"})
+	for _, id := range []string{"implement", "test", "build-1", "build-2"} {
+		state.Complete(id)
+	}
+	next("announce")
+}
+
+func TestReadySteps_SkipsFalseWhen(t *testing.T) {
+	f := decodeControl(t, controlFormula)
+	ready := f.ReadySteps(map[string]bool{"implement": true, "test": true, "build": true})
+	if !reflect.DeepEqual(ready, []string{"done"}) {
+		t.Errorf("ReadySteps = %v, want [done] (announce skipped)", ready)
+	}
+}
+
+func TestExpandForEach(t *testing.T) {
+	f := decodeControl(t, controlFormula)
+	x, err := f.ExpandForEach(map[string]string{"targets": "linux\nwindows,\n darwin "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, s := range x.Steps {
+		ids = append(ids, s.ID)
+	}
+	want := []string{"implement", "test", "build-1", "build-2", "build-3", "announce", "done"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Fatalf("steps = %v, want %v", ids, want)
+	}
+	if s := x.GetStep("build-2"); s.Title != "Build windows (2)" || !reflect.DeepEqual(s.Needs, []string{"test"}) || s.ForEach != "" {
+		t.Errorf("build-2 = %+v", s)
+	}
+	if s := x.GetStep("announce"); !reflect.DeepEqual(s.Needs, []string{"build-1", "build-2", "build-3"}) {
+		t.Errorf("announce needs = %v", s.Needs)
+	}
+	if order, err := x.TopologicalSort(); err != nil || len(order) != len(want) {
+		t.Errorf("TopologicalSort = %v, %v", order, err)
+	}
+	if f.GetStep("build") == nil {
+		t.Error("ExpandForEach modified the original formula")
+	}
+
+	// An empty list drops the step and passes its needs through.
+	x, err = f.ExpandForEach(map[string]string{"targets": ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := x.GetStep("announce"); !reflect.DeepEqual(s.Needs, []string{"test"}) {
+		t.Errorf("announce needs with no targets = %v", s.Needs)
+	}
+}
@@ -25,7 +25,7 @@ type InstalledRecord struct {
 // FormulaStatus represents the status of a single formula during health check.
 type FormulaStatus struct {
 	Name          string
-	Status        string // "ok", "outdated", "modified", "missing", "new", "untracked"
+	Status        string // "ok", "outdated", "modified", "missing", "new", "untracked", "registry"
 	EmbeddedHash  string // hash computed from embedded content
 	InstalledHash string // hash we installed (from .installed.json)
 	CurrentHash   string // hash of current file on disk
@@ -42,6 +42,7 @@ type HealthReport struct {
 	New       int // new formula not yet installed
 	Untracked int // file exists but not in .installed.json (safe to update)
 	Error     int // file could not be read (e.g. permission denied)
+	Registry  int // replaced by a registry install (gt formula install); left alone
 }
 
 // computeHash computes SHA256 hash of data.
@@ -191,12 +192,19 @@ func CheckFormulaHealth(beadsPath string) (*HealthReport, error) {
 	}
 
 	report := &HealthReport{}
+	registry := lockedFormulaFiles(formulasDir)
 
 	for filename, embeddedHash := range embedded {
 		status := FormulaStatus{
 			Name:         filename,
 			EmbeddedHash: embeddedHash,
 		}
+		if registry[filename] {
+			status.Status = "registry"
+			report.Registry++
+			report.Formulas = append(report.Formulas, status)
+			continue
+		}
 
 		installedHash, wasInstalled := installed.Formulas[filename]
 		status.InstalledHash = installedHash
@@ -268,7 +276,13 @@ func UpdateFormulas(beadsPath string) (updated, skipped, reinstalled int, err er
 		return 0, 0, 0, err
 	}
 
+	registry := lockedFormulaFiles(formulasDir)
+
 	for filename, embeddedHash := range embedded {
+		if registry[filename] {
+			// Installed from a registry; gt formula upgrade owns it.
+			continue
+		}
 		installedHash, wasInstalled := installed.Formulas[filename]
 		destPath := filepath.Join(formulasDir, filename)
 		currentHash, fileErr := computeFileHash(destPath)
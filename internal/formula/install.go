@@ -0,0 +1,423 @@
+package formula
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// LockFileName is the town's record of registry-installed formulas, kept in
+// <town>/.beads/formulas/ next to the formulas themselves.
+const LockFileName = ".lock.json"
+
+const lockFileVersion = 1
+
+// LockFile records which formulas came from a registry, at which version,
+// and with which checksums.
+type LockFile struct {
+	Version  int                       `json:"version"`
+	Formulas map[string]*LockedFormula `json:"formulas"`
+}
+
+// LockedFormula is one registry-installed formula.
+type LockedFormula struct {
+	Version string `json:"version"`
+	// Pinned formulas are skipped by upgrade.
+	Pinned bool `json:"pinned"`
+	// Checksum is the release tarball's "sha256:<hex>", as verified.
+	Checksum string `json:"checksum"`
+	// FileChecksum is the installed formula file's sha256, used to notice
+	// local edits before overwriting or removing it.
+	FileChecksum string    `json:"file_checksum"`
+	InstalledAt  time.Time `json:"installed_at"`
+	// Source is "<registry>#<name>@<version>".
+	Source string `json:"source"`
+}
+
+// InstalledFormula is a locked formula with its on-disk state.
+type InstalledFormula struct {
+	Name string `json:"name"`
+	LockedFormula
+	Path     string `json:"path"`
+	Modified bool   `json:"modified"` // file differs from what was installed
+	Missing  bool   `json:"missing"`  // file was deleted
+}
+
+// InstallResult describes the outcome of Install, Upgrade or Pin for one
+// formula.
+type InstallResult struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Previous    string `json:"previous,omitempty"` // version replaced, if any
+	Pinned      bool   `json:"pinned"`
+	Unchanged   bool   `json:"unchanged,omitempty"` // already at this version
+	Skipped     string `json:"skipped,omitempty"`   // why upgrade left it alone
+	Path        string `json:"path"`
+	Checksum    string `json:"checksum,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// ErrNotInstalled is returned for formulas the lockfile does not know.
+var ErrNotInstalled = errors.New("formula not installed from a registry")
+
+// Installer installs registry formulas into a town's .beads/formulas/.
+type Installer struct {
+	// Dir is the town formulas directory.
+	Dir string
+	// Registry is needed by Install, Upgrade and Pin with a version.
+	Registry *Registry
+
+	now func() time.Time
+}
+
+// NewInstaller returns an installer for the town at townRoot.
+func NewInstaller(townRoot string, reg *Registry) *Installer {
+	return &Installer{
+		Dir:      filepath.Join(townRoot, ".beads", "formulas"),
+		Registry: reg,
+		now:      time.Now,
+	}
+}
+
+func (in *Installer) formulaPath(name string) string {
+	return filepath.Join(in.Dir, name+".formula.toml")
+}
+
+// LoadLockFile reads the lockfile in formulasDir, or returns an empty one.
+func LoadLockFile(formulasDir string) (*LockFile, error) {
+	lock := &LockFile{Version: lockFileVersion, Formulas: map[string]*LockedFormula{}}
+	data, err := os.ReadFile(filepath.Join(formulasDir, LockFileName))
+	if os.IsNotExist(err) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading formula lockfile: %w", err)
+	}
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("parsing formula lockfile: %w", err)
+	}
+	if lock.Formulas == nil {
+		lock.Formulas = map[string]*LockedFormula{}
+	}
+	return lock, nil
+}
+
+// withLock runs fn with the lockfile loaded and exclusively locked, saving
+// it afterwards if fn succeeds.
+func (in *Installer) withLock(fn func(lock *LockFile) error) error {
+	if err := os.MkdirAll(in.Dir, 0755); err != nil {
+		return fmt.Errorf("creating formulas directory: %w", err)
+	}
+	fl := flock.New(filepath.Join(in.Dir, LockFileName+".flock"))
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking formula lockfile: %w", err)
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	lock, err := LoadLockFile(in.Dir)
+	if err != nil {
+		return err
+	}
+	if err := fn(lock); err != nil {
+		return err
+	}
+	lock.Version = lockFileVersion
+	return util.AtomicWriteJSON(filepath.Join(in.Dir, LockFileName), lock)
+}
+
+// Installed lists the registry-installed formulas, sorted by name.
+func (in *Installer) Installed() ([]InstalledFormula, error) {
+	lock, err := LoadLockFile(in.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var out []InstalledFormula
+	for name, lf := range lock.Formulas {
+		item := InstalledFormula{Name: name, LockedFormula: *lf, Path: in.formulaPath(name)}
+		hash, err := computeFileHash(item.Path)
+		switch {
+		case os.IsNotExist(err):
+			item.Missing = true
+		case err == nil:
+			item.Modified = hash != lf.FileChecksum
+		}
+		out = append(out, item)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// Install installs name at the newest release matching spec. An exact
+// version (1.2.3) pins the formula. A formula file that exists but was not
+// installed from the registry, or was edited since, is only replaced with
+// force.
+func (in *Installer) Install(ctx context.Context, name, spec string, force bool) (*InstallResult, error) {
+	var result *InstallResult
+	err := in.withLock(func(lock *LockFile) error {
+		if prev := lock.Formulas[name]; prev != nil && prev.Pinned && spec == "" && !force {
+			return fmt.Errorf("%s is pinned at %s (install %s@<version> to change it, or unpin it first)", name, prev.Version, name)
+		}
+		var err error
+		result, err = in.install(ctx, lock, name, spec, IsExactVersion(spec), force)
+		return err
+	})
+	return result, err
+}
+
+func (in *Installer) install(ctx context.Context, lock *LockFile, name, spec string, pin, force bool) (*InstallResult, error) {
+	if err := validateFormulaName(name); err != nil {
+		return nil, err
+	}
+	if in.Registry == nil {
+		return nil, errors.New("no formula registry configured")
+	}
+	idx, err := in.Registry.Index(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := idx.Resolve(name, spec)
+	if err != nil {
+		return nil, err
+	}
+	dest := in.formulaPath(name)
+	result := &InstallResult{Name: name, Version: rel.Version, Pinned: pin, Path: dest}
+	if entry := idx.Formulas[name]; entry != nil {
+		result.Description = entry.Description
+	}
+
+	prev := lock.Formulas[name]
+	if err := in.checkReplaceable(name, prev, force); err != nil {
+		return nil, err
+	}
+	if prev != nil {
+		result.Previous = prev.Version
+		if prev.Version == rel.Version && !force {
+			if hash, err := computeFileHash(dest); err == nil && hash == prev.FileChecksum {
+				prev.Pinned = pin
+				result.Unchanged, result.Checksum = true, prev.Checksum
+				return result, nil
+			}
+		}
+	}
+
+	archive, checksum, err := in.Registry.Download(ctx, rel)
+	if err != nil {
+		return nil, err
+	}
+	content, err := extractFormula(archive, name)
+	if err != nil {
+		return nil, fmt.Errorf("%s@%s: %w", name, rel.Version, err)
+	}
+	f, err := Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s@%s: %w", name, rel.Version, err)
+	}
+	if f.Name != "" && f.Name != name {
+		return nil, fmt.Errorf("%s@%s: tarball contains formula %q", name, rel.Version, f.Name)
+	}
+	if err := util.AtomicWriteFile(dest, content, 0644); err != nil {
+		return nil, fmt.Errorf("writing %s: %w", dest, err)
+	}
+
+	lock.Formulas[name] = &LockedFormula{
+		Version:      rel.Version,
+		Pinned:       pin,
+		Checksum:     checksum,
+		FileChecksum: computeHash(content),
+		InstalledAt:  in.now().UTC(),
+		Source:       fmt.Sprintf("%s#%s@%s", in.Registry.Location, name, rel.Version),
+	}
+	result.Checksum = checksum
+	return result, nil
+}
+
+// checkReplaceable refuses to overwrite a formula file the registry does
+// not own, or one edited since it was installed, unless force is set.
+func (in *Installer) checkReplaceable(name string, prev *LockedFormula, force bool) error {
+	if force {
+		return nil
+	}
+	hash, err := computeFileHash(in.formulaPath(name))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if prev == nil {
+		return fmt.Errorf("%s already exists and was not installed from a registry (use --force to replace it)", in.formulaPath(name))
+	}
+	if hash != prev.FileChecksum {
+		return fmt.Errorf("%s was modified since it was installed (use --force to replace it)", in.formulaPath(name))
+	}
+	return nil
+}
+
+// Upgrade moves the named installed formulas (all of them if names is
+// empty) to their newest release. Pinned and locally modified formulas are
+// skipped unless force is set.
+func (in *Installer) Upgrade(ctx context.Context, names []string, force bool) ([]*InstallResult, error) {
+	var results []*InstallResult
+	err := in.withLock(func(lock *LockFile) error {
+		if len(names) == 0 {
+			for name := range lock.Formulas {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+		}
+		for _, name := range names {
+			prev := lock.Formulas[name]
+			if prev == nil {
+				return fmt.Errorf("%s: %w", name, ErrNotInstalled)
+			}
+			if prev.Pinned && !force {
+				results = append(results, &InstallResult{
+					Name: name, Version: prev.Version, Pinned: true, Path: in.formulaPath(name),
+					Skipped: "pinned",
+				})
+				continue
+			}
+			if !force && in.checkReplaceable(name, prev, false) != nil {
+				results = append(results, &InstallResult{
+					Name: name, Version: prev.Version, Path: in.formulaPath(name),
+					Skipped: "modified locally",
+				})
+				continue
+			}
+			r, err := in.install(ctx, lock, name, "", false, force)
+			if err != nil {
+				return err
+			}
+			results = append(results, r)
+		}
+		return nil
+	})
+	return results, err
+}
+
+// Pin pins an installed formula so upgrade leaves it alone. With a
+// version spec the matching release is installed first.
+func (in *Installer) Pin(ctx context.Context, name, spec string) (*InstallResult, error) {
+	var result *InstallResult
+	err := in.withLock(func(lock *LockFile) error {
+		if spec != "" {
+			var err error
+			result, err = in.install(ctx, lock, name, spec, true, false)
+			return err
+		}
+		lf := lock.Formulas[name]
+		if lf == nil {
+			return fmt.Errorf("%s: %w", name, ErrNotInstalled)
+		}
+		lf.Pinned = true
+		result = &InstallResult{Name: name, Version: lf.Version, Pinned: true, Unchanged: true, Path: in.formulaPath(name)}
+		return nil
+	})
+	return result, err
+}
+
+// Unpin lets upgrade move name again.
+func (in *Installer) Unpin(name string) error {
+	return in.withLock(func(lock *LockFile) error {
+		lf := lock.Formulas[name]
+		if lf == nil {
+			return fmt.Errorf("%s: %w", name, ErrNotInstalled)
+		}
+		lf.Pinned = false
+		return nil
+	})
+}
+
+// Uninstall removes an installed formula and its lockfile entry. A file
+// edited since it was installed is only removed with force.
+func (in *Installer) Uninstall(name string, force bool) error {
+	return in.withLock(func(lock *LockFile) error {
+		prev := lock.Formulas[name]
+		if prev == nil {
+			return fmt.Errorf("%s: %w", name, ErrNotInstalled)
+		}
+		if err := in.checkReplaceable(name, prev, force); err != nil {
+			return err
+		}
+		if err := os.Remove(in.formulaPath(name)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing %s: %w", in.formulaPath(name), err)
+		}
+		delete(lock.Formulas, name)
+		return nil
+	})
+}
+
+// lockedFormulaFiles returns the file names (<name>.formula.toml) of
+// registry-installed formulas in formulasDir. Embedded-formula provisioning
+// leaves these alone.
+func lockedFormulaFiles(formulasDir string) map[string]bool {
+	lock, err := LoadLockFile(formulasDir)
+	if err != nil {
+		return nil
+	}
+	files := make(map[string]bool, len(lock.Formulas))
+	for name := range lock.Formulas {
+		files[name+".formula.toml"] = true
+	}
+	return files
+}
+
+// extractFormula returns name's formula file from a .tar.gz release: the
+// entry named <name>.formula.toml or formula.toml at the archive root or
+// under one top-level directory.
+func extractFormula(archive []byte, name string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("reading tarball: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading tarball: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		clean := path.Clean(strings.TrimPrefix(hdr.Name, "./"))
+		if strings.Count(clean, "/") > 1 {
+			continue
+		}
+		base := path.Base(clean)
+		if base != name+".formula.toml" && base != "formula.toml" {
+			continue
+		}
+		if hdr.Size > maxFormulaSize {
+			return nil, fmt.Errorf("%s is larger than %d bytes", hdr.Name, maxFormulaSize)
+		}
+		return io.ReadAll(io.LimitReader(tr, maxFormulaSize))
+	}
+	return nil, fmt.Errorf("tarball has no %s.formula.toml", name)
+}
+
+// validateFormulaName rejects names that could escape the formulas directory.
+func validateFormulaName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("invalid formula name %q", name)
+	}
+	return nil
+}
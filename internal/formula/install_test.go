@@ -0,0 +1,314 @@
+package formula
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func registryFormula(name, version string) string {
+	return `formula = "` + name + `"
+description = "release ` + version + `"
+type = "workflow"
+version = 1
+
+[[steps]]
+id = "work"
+title = "Work"
+`
+}
+
+func tarball(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// testRegistry writes a registry directory publishing the given versions of
+// each formula and returns its path.
+func testRegistry(t *testing.T, releases map[string][]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	idx := RegistryIndex{Version: 1, Formulas: map[string]*RegistryEntry{}}
+	for name, versions := range releases {
+		entry := &RegistryEntry{Description: name + " formula"}
+		for _, v := range versions {
+			archive := tarball(t, map[string]string{
+				name + "-" + v + "/" + name + ".formula.toml": registryFormula(name, v),
+			})
+			file := name + "-" + v + ".tar.gz"
+			if err := os.WriteFile(filepath.Join(dir, file), archive, 0644); err != nil {
+				t.Fatal(err)
+			}
+			sum := sha256.Sum256(archive)
+			entry.Versions = append(entry.Versions, RegistryRelease{Version: v, URL: file, SHA256: hex.EncodeToString(sum[:])})
+		}
+		idx.Formulas[name] = entry
+	}
+	data, _ := json.Marshal(idx)
+	if err := os.WriteFile(filepath.Join(dir, RegistryIndexFile), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func testInstaller(t *testing.T, registryDir string) *Installer {
+	t.Helper()
+	reg, err := OpenRegistry(registryDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewInstaller(t.TempDir(), reg)
+}
+
+func installedVersion(t *testing.T, in *Installer, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(in.formulaPath(name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimPrefix(f.Description, "release ")
+}
+
+func TestSemverMatches(t *testing.T) {
+	tests := []struct {
+		version, spec string
+		want          bool
+	}{
+		{"1.2.3", "", true},
+		{"1.2.3", "latest", true},
+		{"1.2.3", "1", true},
+		{"1.2.3", "1.2", true},
+		{"1.2.3", "1.2.3", true},
+		{"1.2.3", "v1.2.3", true},
+		{"1.2.3", "1.3", false},
+		{"1.2.3", "2", false},
+		{"1.2.3", "1.2.4", false},
+		{"1.2.3", "bogus", false},
+	}
+	for _, tt := range tests {
+		v, err := ParseSemver(tt.version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := v.Matches(tt.spec); got != tt.want {
+			t.Errorf("%s.Matches(%q) = %v, want %v", tt.version, tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestRegistryIndexResolve(t *testing.T) {
+	idx := &RegistryIndex{Formulas: map[string]*RegistryEntry{
+		"mol-x": {Versions: []RegistryRelease{{Version: "1.9.0"}, {Version: "1.10.0"}, {Version: "2.0.0"}}},
+	}}
+	for spec, want := range map[string]string{"": "2.0.0", "1": "1.10.0", "1.9": "1.9.0"} {
+		rel, err := idx.Resolve("mol-x", spec)
+		if err != nil || rel.Version != want {
+			t.Errorf("Resolve(%q) = %v, %v; want %s", spec, rel, err, want)
+		}
+	}
+	if _, err := idx.Resolve("mol-x", "3"); err == nil {
+		t.Error("Resolve(3) should fail")
+	}
+	if _, err := idx.Resolve("mol-missing", ""); err == nil {
+		t.Error("Resolve of unknown formula should fail")
+	}
+}
+
+func TestInstall_NewestAndLockfile(t *testing.T) {
+	in := testInstaller(t, testRegistry(t, map[string][]string{"mol-review": {"1.0.0", "1.1.0"}}))
+
+	r, err := in.Install(context.Background(), "mol-review", "", false)
+	if err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+	if r.Version != "1.1.0" || r.Pinned || !strings.HasPrefix(r.Checksum, "sha256:") {
+		t.Errorf("result = %+v", r)
+	}
+	if got := installedVersion(t, in, "mol-review"); got != "1.1.0" {
+		t.Errorf("installed file is release %s", got)
+	}
+
+	lock, err := LoadLockFile(in.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lf := lock.Formulas["mol-review"]
+	if lf == nil || lf.Version != "1.1.0" || lf.Checksum != r.Checksum || !strings.HasSuffix(lf.Source, "#mol-review@1.1.0") {
+		t.Errorf("lock entry = %+v", lf)
+	}
+
+	// Installing again is a no-op.
+	r, err = in.Install(context.Background(), "mol-review", "", false)
+	if err != nil || !r.Unchanged {
+		t.Errorf("reinstall = %+v, %v", r, err)
+	}
+}
+
+func TestInstall_ExactVersionPins(t *testing.T) {
+	in := testInstaller(t, testRegistry(t, map[string][]string{"mol-review": {"1.0.0", "1.1.0"}}))
+	ctx := context.Background()
+
+	if _, err := in.Install(ctx, "mol-review", "1.0.0", false); err != nil {
+		t.Fatal(err)
+	}
+	results, err := in.Upgrade(ctx, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Skipped != "pinned" {
+		t.Errorf("upgrade of pinned formula = %+v", results)
+	}
+	if _, err := in.Install(ctx, "mol-review", "", false); err == nil {
+		t.Error("install without a version should refuse to move a pinned formula")
+	}
+
+	if err := in.Unpin("mol-review"); err != nil {
+		t.Fatal(err)
+	}
+	results, err = in.Upgrade(ctx, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Previous != "1.0.0" || results[0].Version != "1.1.0" {
+		t.Errorf("upgrade = %+v", results)
+	}
+	if got := installedVersion(t, in, "mol-review"); got != "1.1.0" {
+		t.Errorf("installed file is release %s", got)
+	}
+}
+
+func TestInstall_ChecksumMismatch(t *testing.T) {
+	dir := testRegistry(t, map[string][]string{"mol-review": {"1.0.0"}})
+	if err := os.WriteFile(filepath.Join(dir, "mol-review-1.0.0.tar.gz"), tarball(t, map[string]string{
+		"mol-review.formula.toml": registryFormula("mol-review", "tampered"),
+	}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	in := testInstaller(t, dir)
+
+	_, err := in.Install(context.Background(), "mol-review", "", false)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("Install = %v, want checksum mismatch", err)
+	}
+	if _, err := os.Stat(in.formulaPath("mol-review")); !os.IsNotExist(err) {
+		t.Error("formula was written despite the checksum mismatch")
+	}
+}
+
+func TestInstall_RefusesUnownedAndModified(t *testing.T) {
+	in := testInstaller(t, testRegistry(t, map[string][]string{"mol-review": {"1.0.0", "1.1.0"}}))
+	ctx := context.Background()
+	path := in.formulaPath("mol-review")
+
+	// A hand-written formula with the same name is not replaced.
+	if err := os.MkdirAll(in.Dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("# local\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := in.Install(ctx, "mol-review", "1.0.0", false); err == nil {
+		t.Fatal("install should refuse to replace a formula it did not install")
+	}
+	if _, err := in.Install(ctx, "mol-review", "1.0.0", true); err != nil {
+		t.Fatalf("install --force: %v", err)
+	}
+	if err := in.Unpin("mol-review"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Local edits are reported, and upgrade and uninstall leave them alone.
+	if err := os.WriteFile(path, []byte(registryFormula("mol-review", "edited")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	installed, err := in.Installed()
+	if err != nil || len(installed) != 1 || !installed[0].Modified {
+		t.Errorf("Installed = %+v, %v", installed, err)
+	}
+	results, err := in.Upgrade(ctx, nil, false)
+	if err != nil || len(results) != 1 || results[0].Skipped != "modified locally" {
+		t.Errorf("upgrade of modified formula = %+v, %v", results, err)
+	}
+	if err := in.Uninstall("mol-review", false); err == nil {
+		t.Error("uninstall should refuse a modified formula")
+	}
+	if err := in.Uninstall("mol-review", true); err != nil {
+		t.Fatalf("uninstall --force: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("formula file still present after uninstall")
+	}
+	if err := in.Uninstall("mol-review", false); !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("second uninstall = %v, want ErrNotInstalled", err)
+	}
+}
+
+func TestInstall_HTTPRegistry(t *testing.T) {
+	dir := testRegistry(t, map[string][]string{"mol-review": {"2.0.0"}})
+	srv := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	defer srv.Close()
+	in := testInstaller(t, srv.URL+"/")
+
+	r, err := in.Install(context.Background(), "mol-review", "2", false)
+	if err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+	if r.Version != "2.0.0" || r.Pinned {
+		t.Errorf("result = %+v", r)
+	}
+}
+
+func TestProvisionFormulas_SkipsRegistryInstalled(t *testing.T) {
+	in := testInstaller(t, testRegistry(t, map[string][]string{"mol-deacon-patrol": {"9.0.0"}}))
+	if _, err := in.Install(context.Background(), "mol-deacon-patrol", "", false); err != nil {
+		t.Fatal(err)
+	}
+
+	townRoot := filepath.Dir(filepath.Dir(in.Dir))
+
+	if _, _, _, err := UpdateFormulas(townRoot); err != nil {
+		t.Fatal(err)
+	}
+	if got := installedVersion(t, in, "mol-deacon-patrol"); got != "9.0.0" {
+		t.Errorf("embedded update replaced the registry-installed formula (now %q)", got)
+	}
+	report, err := CheckFormulaHealth(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Registry != 1 {
+		t.Errorf("report.Registry = %d, want 1", report.Registry)
+	}
+}
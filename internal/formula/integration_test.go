@@ -33,7 +33,8 @@ func TestParseRealFormulas(t *testing.T) {
 		t.Skip("No formula files found to test")
 	}
 
-	// Known files that use advanced features not yet supported:
+	// Known files that ParseFile can't handle on their own (see
+	// TestExpand_EmbeddedFormulas, which flattens them with a Resolver):
 	// - Composition (extends, compose): shiny-enterprise, shiny-secure
 	// - Aspect-oriented (advice, pointcuts): security-audit
 	skipAdvanced := map[string]string{
@@ -18,7 +18,26 @@ func ParseFile(path string) (*Formula, error) {
 }
 
 // Parse parses formula.toml content from bytes.
+// A formula that uses extends, include or compose refers to other formulas
+// and must be flattened with Resolver.Expand instead.
 func Parse(data []byte) (*Formula, error) {
+	f, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	if f.composes() {
+		return nil, fmt.Errorf("formula %q is composed from other formulas (extends, include or compose); expand it with a Resolver", f.Name)
+	}
+
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// decode parses formula TOML without validating it.
+func decode(data []byte) (*Formula, error) {
 	var f Formula
 	if _, err := toml.Decode(string(data), &f); err != nil {
 		return nil, fmt.Errorf("parsing TOML: %w", err)
@@ -27,10 +46,6 @@ func Parse(data []byte) (*Formula, error) {
 	// Infer type from content if not explicitly set
 	f.inferType()
 
-	if err := f.Validate(); err != nil {
-		return nil, err
-	}
-
 	return &f, nil
 }
 
@@ -63,6 +78,10 @@ func (f *Formula) Validate() error {
 		return fmt.Errorf("invalid formula type %q (must be convoy, workflow, expansion, or aspect)", f.Type)
 	}
 
+	if err := f.validateVars(); err != nil {
+		return err
+	}
+
 	// Type-specific validation
 	switch f.Type {
 	case TypeConvoy:
@@ -130,6 +149,9 @@ func (f *Formula) validateWorkflow() error {
 		if seen[step.ID] {
 			return fmt.Errorf("duplicate step id: %s", step.ID)
 		}
+		if step.Before != "" || step.After != "" {
+			return fmt.Errorf("step %q: before/after only apply in a formula that extends another", step.ID)
+		}
 		seen[step.ID] = true
 	}
 
@@ -147,7 +169,10 @@ func (f *Formula) validateWorkflow() error {
 		return err
 	}
 
-	return nil
+	if err := f.validateStepControl(); err != nil {
+		return err
+	}
+	return f.rejectStepControl()
 }
 
 func (f *Formula) validateExpansion() error {
@@ -185,8 +210,11 @@ func (f *Formula) validateExpansion() error {
 }
 
 func (f *Formula) validateAspect() error {
-	if len(f.Aspects) == 0 {
-		return fmt.Errorf("aspect formula requires at least one aspect")
+	if len(f.Aspects) == 0 && len(f.Advice) == 0 {
+		return fmt.Errorf("aspect formula requires at least one aspect or advice")
+	}
+	if err := f.validateAdvice(); err != nil {
+		return err
 	}
 
 	// Check aspect IDs are unique
@@ -267,6 +295,9 @@ func checkDependencyCycles(deps map[string][]string) error {
 // TopologicalSort returns steps in dependency order (dependencies before dependents).
 // Only applicable to workflow and expansion formulas.
 // Returns an error if there are cycles.
+// Conditional steps are included (they may be skipped at run time), retry
+// loops back to an earlier step are not dependency edges, and a foreach step
+// is one entry until ExpandForEach replaces it with its children.
 func (f *Formula) TopologicalSort() ([]string, error) {
 	var items []string
 	var deps map[string][]string
@@ -357,26 +388,22 @@ func (f *Formula) TopologicalSort() ([]string, error) {
 
 // ReadySteps returns steps that have no unmet dependencies.
 // completed is a set of step IDs that have been completed.
+// For workflows, `when` conditions are evaluated with var defaults and the
+// completed steps as successes; a step whose condition is false is treated
+// as skipped, so its dependents become ready. Use Advance to track failures,
+// retries and run vars.
 func (f *Formula) ReadySteps(completed map[string]bool) []string {
 	var ready []string
 
 	switch f.Type {
 	case TypeWorkflow:
-		for _, step := range f.Steps {
-			if completed[step.ID] {
-				continue
-			}
-			allMet := true
-			for _, need := range step.Needs {
-				if !completed[need] {
-					allMet = false
-					break
-				}
-			}
-			if allMet {
-				ready = append(ready, step.ID)
+		state := NewRunState(nil)
+		for id, done := range completed {
+			if done {
+				state.Complete(id)
 			}
 		}
+		ready, _ = f.Advance(state)
 	case TypeExpansion:
 		for _, tmpl := range f.Template {
 			if completed[tmpl.ID] {
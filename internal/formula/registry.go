@@ -0,0 +1,328 @@
+package formula
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A formula registry is a static JSON index plus release tarballs, served
+// from a directory or over plain HTTP:
+//
+//	index.json
+//	mol-code-review-1.2.0.tar.gz
+//
+// The index lists each formula's releases with the tarball location
+// (relative to the index) and its SHA-256:
+//
+//	{
+//	  "version": 1,
+//	  "formulas": {
+//	    "mol-code-review": {
+//	      "description": "Multi-leg code review",
+//	      "versions": [
+//	        {"version": "1.2.0", "url": "mol-code-review-1.2.0.tar.gz", "sha256": "9f2c..."}
+//	      ]
+//	    }
+//	  }
+//	}
+//
+// A tarball holds the formula as <name>.formula.toml (or formula.toml), at
+// its root or under a single top-level directory.
+
+// RegistryIndexFile is the index file name looked up under a registry root.
+const RegistryIndexFile = "index.json"
+
+const (
+	registryTimeout  = 30 * time.Second
+	maxIndexSize     = 8 << 20
+	maxArchiveSize   = 16 << 20
+	maxFormulaSize   = 1 << 20
+	registryIndexVer = 1
+)
+
+// RegistryIndex is a registry's index.json.
+type RegistryIndex struct {
+	Version  int                       `json:"version"`
+	Formulas map[string]*RegistryEntry `json:"formulas"`
+}
+
+// RegistryEntry lists the published releases of one formula.
+type RegistryEntry struct {
+	Description string            `json:"description,omitempty"`
+	Versions    []RegistryRelease `json:"versions"`
+}
+
+// RegistryRelease is one published version of a formula.
+type RegistryRelease struct {
+	Version   string `json:"version"`
+	URL       string `json:"url"`
+	SHA256    string `json:"sha256"`
+	Changelog string `json:"changelog,omitempty"`
+}
+
+// Registry reads a formula registry from a directory or an HTTP(S) URL.
+type Registry struct {
+	// Location is the registry as configured: a URL or directory holding
+	// index.json, or the index file itself.
+	Location string
+
+	index string // index file path or URL
+	http  *http.Client
+}
+
+// OpenRegistry returns the registry at location. Nothing is fetched until
+// Index is called.
+func OpenRegistry(location string) (*Registry, error) {
+	location = strings.TrimSpace(location)
+	if location == "" {
+		return nil, errors.New("no formula registry configured (set formula_registry in settings/config.json or pass --registry)")
+	}
+	r := &Registry{Location: location, http: &http.Client{Timeout: registryTimeout}}
+	switch {
+	case isHTTP(location):
+		r.index = location
+		if !strings.HasSuffix(location, ".json") {
+			r.index = strings.TrimSuffix(location, "/") + "/" + RegistryIndexFile
+		}
+	default:
+		path := strings.TrimPrefix(location, "file://")
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			path = filepath.Join(path, RegistryIndexFile)
+		}
+		r.index = path
+	}
+	return r, nil
+}
+
+func isHTTP(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+// Index fetches and parses the registry index.
+func (r *Registry) Index(ctx context.Context) (*RegistryIndex, error) {
+	data, err := r.read(ctx, r.index, maxIndexSize)
+	if err != nil {
+		return nil, fmt.Errorf("reading registry index: %w", err)
+	}
+	var idx RegistryIndex
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("parsing registry index %s: %w", r.index, err)
+	}
+	if idx.Version > registryIndexVer {
+		return nil, fmt.Errorf("registry index version %d is newer than this gt supports (%d)", idx.Version, registryIndexVer)
+	}
+	return &idx, nil
+}
+
+// Download fetches rel's tarball and verifies its checksum, returning the
+// archive bytes and their "sha256:<hex>" checksum.
+func (r *Registry) Download(ctx context.Context, rel *RegistryRelease) ([]byte, string, error) {
+	want := strings.ToLower(strings.TrimPrefix(rel.SHA256, "sha256:"))
+	if want == "" {
+		return nil, "", fmt.Errorf("release %s has no sha256 in the index", rel.Version)
+	}
+	loc, err := r.resolve(rel.URL)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := r.read(ctx, loc, maxArchiveSize)
+	if err != nil {
+		return nil, "", fmt.Errorf("downloading %s: %w", loc, err)
+	}
+	sum := sha256.Sum256(data)
+	got := hex.EncodeToString(sum[:])
+	if got != want {
+		return nil, "", fmt.Errorf("checksum mismatch for %s: index says sha256:%s, got sha256:%s", loc, want, got)
+	}
+	return data, "sha256:" + got, nil
+}
+
+// resolve makes a release URL absolute against the index location.
+func (r *Registry) resolve(ref string) (string, error) {
+	if ref == "" {
+		return "", errors.New("release has no url")
+	}
+	if isHTTP(r.index) {
+		base, err := url.Parse(r.index)
+		if err != nil {
+			return "", fmt.Errorf("parsing registry url: %w", err)
+		}
+		u, err := base.Parse(ref)
+		if err != nil {
+			return "", fmt.Errorf("parsing release url %q: %w", ref, err)
+		}
+		return u.String(), nil
+	}
+	if isHTTP(ref) {
+		return ref, nil
+	}
+	ref = strings.TrimPrefix(ref, "file://")
+	if filepath.IsAbs(ref) {
+		return ref, nil
+	}
+	return filepath.Join(filepath.Dir(r.index), filepath.FromSlash(ref)), nil
+}
+
+// read returns the content at loc (a URL or file path), up to limit bytes.
+func (r *Registry) read(ctx context.Context, loc string, limit int64) ([]byte, error) {
+	var body io.ReadCloser
+	if isHTTP(loc) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, loc, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := r.http.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("GET %s: %s", loc, resp.Status)
+		}
+		body = resp.Body
+	} else {
+		f, err := os.Open(loc) //nolint:gosec // G304: registry path is configured by the user
+		if err != nil {
+			return nil, err
+		}
+		body = f
+	}
+	defer func() { _ = body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%s is larger than %d bytes", loc, limit)
+	}
+	return data, nil
+}
+
+// Resolve returns the newest release of name matching spec: "" or "latest"
+// for the newest, "2" or "2.1" for the newest in that series, or an exact
+// version such as "2.1.0".
+func (idx *RegistryIndex) Resolve(name, spec string) (*RegistryRelease, error) {
+	entry := idx.Formulas[name]
+	if entry == nil || len(entry.Versions) == 0 {
+		return nil, fmt.Errorf("formula %q is not in the registry", name)
+	}
+	var best *RegistryRelease
+	var bestVer Semver
+	for i := range entry.Versions {
+		rel := &entry.Versions[i]
+		v, err := ParseSemver(rel.Version)
+		if err != nil || !v.Matches(spec) {
+			continue
+		}
+		if best == nil || v.Compare(bestVer) > 0 {
+			best, bestVer = rel, v
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("no release of %s matches %q (available: %s)", name, spec, strings.Join(entry.VersionList(), ", "))
+	}
+	return best, nil
+}
+
+// VersionList returns the entry's versions, newest first.
+func (e *RegistryEntry) VersionList() []string {
+	var vs []Semver
+	for _, rel := range e.Versions {
+		if v, err := ParseSemver(rel.Version); err == nil {
+			vs = append(vs, v)
+		}
+	}
+	sort.Slice(vs, func(i, j int) bool { return vs[i].Compare(vs[j]) > 0 })
+	out := make([]string, len(vs))
+	for i, v := range vs {
+		out[i] = v.String()
+	}
+	return out
+}
+
+// ParseFormulaRef splits "name@spec" into its name and version spec.
+func ParseFormulaRef(ref string) (name, spec string) {
+	name, spec, _ = strings.Cut(ref, "@")
+	return name, spec
+}
+
+// Semver is a MAJOR.MINOR.PATCH version. Parts is how many components were
+// written, so "2.1" can match any 2.1.x.
+type Semver struct {
+	Major, Minor, Patch int
+	Parts               int
+}
+
+// ParseSemver parses "1", "1.2" or "1.2.3", with an optional "v" prefix.
+func ParseSemver(s string) (Semver, error) {
+	fields := strings.Split(strings.TrimPrefix(strings.TrimSpace(s), "v"), ".")
+	if len(fields) == 0 || len(fields) > 3 {
+		return Semver{}, fmt.Errorf("invalid version %q", s)
+	}
+	var nums [3]int
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return Semver{}, fmt.Errorf("invalid version %q", s)
+		}
+		nums[i] = n
+	}
+	return Semver{Major: nums[0], Minor: nums[1], Patch: nums[2], Parts: len(fields)}, nil
+}
+
+// IsExactVersion reports whether spec names a single version (MAJOR.MINOR.PATCH).
+func IsExactVersion(spec string) bool {
+	v, err := ParseSemver(spec)
+	return err == nil && v.Parts == 3
+}
+
+// Compare returns -1, 0 or 1 as v is older than, equal to or newer than o.
+func (v Semver) Compare(o Semver) int {
+	for _, d := range []int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+// Matches reports whether v satisfies spec ("", "latest", or a full or
+// partial version).
+func (v Semver) Matches(spec string) bool {
+	if spec == "" || spec == "latest" {
+		return true
+	}
+	want, err := ParseSemver(spec)
+	if err != nil {
+		return false
+	}
+	got := [3]int{v.Major, v.Minor, v.Patch}
+	need := [3]int{want.Major, want.Minor, want.Patch}
+	for i := 0; i < want.Parts; i++ {
+		if got[i] != need[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (v Semver) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
@@ -0,0 +1,303 @@
+package formula
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Tier is a level of the formula search path. Higher tiers override lower
+// ones: a rig's own formula wins over the town's, which wins over the
+// formulas embedded in gt.
+type Tier string
+
+const (
+	// TierProject is the rig's <rig>/.beads/formulas/.
+	TierProject Tier = "project"
+	// TierTown is the town's <town>/.beads/formulas/.
+	TierTown Tier = "town"
+	// TierSystem is the formulas embedded in the gt binary.
+	TierSystem Tier = "system"
+)
+
+// Tiers lists the tiers in search order.
+var Tiers = []Tier{TierProject, TierTown, TierSystem}
+
+// ParseTier parses a --tier value.
+func ParseTier(s string) (Tier, error) {
+	for _, t := range Tiers {
+		if string(t) == s {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("invalid tier %q (want project, town or system)", s)
+}
+
+// formulaExtensions are the file suffixes tried for a formula name, in order.
+var formulaExtensions = []string{".formula.toml", ".formula.json"}
+
+// embeddedPathPrefix marks Resolved.Path for system-tier formulas.
+const embeddedPathPrefix = "embedded:"
+
+// ErrFormulaNotFound is returned when no tier has the formula.
+var ErrFormulaNotFound = errors.New("formula not found")
+
+// Resolver finds formulas across the project, town and system tiers.
+type Resolver struct {
+	// ProjectDir is the rig's formulas directory ("" outside a rig).
+	ProjectDir string
+	// TownDir is the town's formulas directory ("" outside a town).
+	TownDir string
+}
+
+// NewResolver returns a resolver for the town at townRoot and the rig at
+// rigPath. Either may be empty.
+func NewResolver(townRoot, rigPath string) *Resolver {
+	r := &Resolver{}
+	if rigPath != "" {
+		r.ProjectDir = filepath.Join(rigPath, ".beads", "formulas")
+	}
+	if townRoot != "" {
+		r.TownDir = filepath.Join(townRoot, ".beads", "formulas")
+	}
+	return r
+}
+
+// Resolved is a formula found by the resolver, with where it came from.
+type Resolved struct {
+	Name string `json:"name"`
+	Tier Tier   `json:"tier"`
+	// Path is the file read, or "embedded:formulas/<file>" for the system tier.
+	Path        string      `json:"path"`
+	Version     int         `json:"version"`
+	Type        FormulaType `json:"type,omitempty"`
+	Description string      `json:"description,omitempty"`
+	// Installed is the registry release, for formulas installed with
+	// gt formula install.
+	Installed string `json:"installed,omitempty"`
+	// Shadows lists the same formula in lower tiers, which this one overrides.
+	Shadows []*Resolved `json:"shadows,omitempty"`
+
+	content  []byte
+	resolver *Resolver
+}
+
+// Content returns the formula file's raw bytes.
+func (r *Resolved) Content() []byte {
+	return r.content
+}
+
+// Parse parses and validates the resolved formula. A composed formula is
+// flattened first, with the formulas it refers to resolved from every tier.
+func (r *Resolved) Parse() (*Formula, error) {
+	f, err := decode(r.content)
+	if err == nil {
+		if f.composes() {
+			resolver := r.resolver
+			if resolver == nil {
+				resolver = &Resolver{}
+			}
+			f, err = resolver.flatten(r.Name, f)
+		} else {
+			err = f.Validate()
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", r.Path, err)
+	}
+	return f, nil
+}
+
+// Stale reports whether a shadowed lower-tier copy has a newer version than
+// this one, i.e. the override was made against an older formula.
+func (r *Resolved) Stale() *Resolved {
+	for _, s := range r.Shadows {
+		if s.Version > r.Version {
+			return s
+		}
+	}
+	return nil
+}
+
+// SearchStep is one location checked while resolving a formula.
+type SearchStep struct {
+	Tier  Tier   `json:"tier"`
+	Path  string `json:"path"`
+	Found bool   `json:"found"`
+	// Skipped is set for tiers excluded by a tier override.
+	Skipped bool `json:"skipped,omitempty"`
+}
+
+// dir returns the directory searched for tier ("" if the tier is unavailable).
+func (r *Resolver) dir(t Tier) string {
+	switch t {
+	case TierProject:
+		return r.ProjectDir
+	case TierTown:
+		return r.TownDir
+	}
+	return ""
+}
+
+// Resolve returns the highest-tier formula called name. With a tier, only
+// that tier is searched.
+func (r *Resolver) Resolve(name string, tier Tier) (*Resolved, error) {
+	res, _, err := r.Trace(name, tier)
+	return res, err
+}
+
+// Trace resolves name like Resolve and also returns every location it
+// checked, in order. Lower tiers are still read after a match so the
+// result records what it shadows.
+func (r *Resolver) Trace(name string, tier Tier) (*Resolved, []SearchStep, error) {
+	if err := validateFormulaName(name); err != nil {
+		return nil, nil, err
+	}
+	var steps []SearchStep
+	var found []*Resolved
+	for _, t := range Tiers {
+		if tier != "" && t != tier {
+			if path := r.location(t, name+formulaExtensions[0]); path != "" {
+				steps = append(steps, SearchStep{Tier: t, Path: path, Skipped: true})
+			}
+			continue
+		}
+		for _, ext := range formulaExtensions {
+			step := SearchStep{Tier: t, Path: r.location(t, name+ext)}
+			if step.Path == "" {
+				continue
+			}
+			res, err := r.load(t, name, name+ext)
+			if err != nil {
+				return nil, steps, err
+			}
+			step.Found = res != nil
+			steps = append(steps, step)
+			if res != nil {
+				found = append(found, res)
+				break
+			}
+		}
+	}
+	if len(found) == 0 {
+		where := "any tier"
+		if tier != "" {
+			where = "the " + string(tier) + " tier"
+		}
+		return nil, steps, fmt.Errorf("%w: %q in %s", ErrFormulaNotFound, name, where)
+	}
+	found[0].Shadows = found[1:]
+	return found[0], steps, nil
+}
+
+// location returns the path checked for file in tier, or "" if the tier is
+// unavailable.
+func (r *Resolver) location(t Tier, file string) string {
+	if t == TierSystem {
+		return embeddedPathPrefix + "formulas/" + file
+	}
+	if dir := r.dir(t); dir != "" {
+		return filepath.Join(dir, file)
+	}
+	return ""
+}
+
+// load reads file from tier, returning nil if it is not there.
+func (r *Resolver) load(t Tier, name, file string) (*Resolved, error) {
+	var data []byte
+	var err error
+	if t == TierSystem {
+		data, err = formulasFS.ReadFile("formulas/" + file)
+	} else {
+		data, err = os.ReadFile(filepath.Join(r.dir(t), file)) //nolint:gosec // G304: path is from the formula search path
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", r.location(t, file), err)
+	}
+
+	res := &Resolved{Name: name, Tier: t, Path: r.location(t, file), content: data, resolver: r}
+	var hdr struct {
+		Version     int         `toml:"version"`
+		Type        FormulaType `toml:"type"`
+		Description string      `toml:"description"`
+	}
+	// The header is informational; a formula that fails to decode is still
+	// resolved and reports its error when parsed.
+	if strings.HasSuffix(file, ".toml") {
+		if _, err := toml.Decode(string(data), &hdr); err == nil {
+			res.Version, res.Type, res.Description = hdr.Version, hdr.Type, hdr.Description
+		}
+	}
+	if t == TierTown {
+		if lock, err := LoadLockFile(r.TownDir); err == nil && lock.Formulas[name] != nil {
+			res.Installed = lock.Formulas[name].Version
+		}
+	}
+	return res, nil
+}
+
+// List resolves every formula visible from any tier (or only tier, if set),
+// sorted by name. Each result is the winning copy, with its shadows.
+func (r *Resolver) List(tier Tier) ([]*Resolved, error) {
+	names := map[string]bool{}
+	for _, t := range Tiers {
+		if tier != "" && t != tier {
+			continue
+		}
+		files, err := r.files(t)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			for _, ext := range formulaExtensions {
+				if strings.HasSuffix(f, ext) {
+					names[strings.TrimSuffix(f, ext)] = true
+				}
+			}
+		}
+	}
+
+	out := make([]*Resolved, 0, len(names))
+	for name := range names {
+		res, err := r.Resolve(name, tier)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, res)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// files lists the file names in tier's directory.
+func (r *Resolver) files(t Tier) ([]string, error) {
+	var entries []fs.DirEntry
+	var err error
+	if t == TierSystem {
+		entries, err = formulasFS.ReadDir("formulas")
+	} else if dir := r.dir(t); dir != "" {
+		entries, err = os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("listing %s formulas: %w", t, err)
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
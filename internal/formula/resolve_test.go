@@ -0,0 +1,170 @@
+package formula
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func writeTierFormula(t *testing.T, root, name string, version int) {
+	t.Helper()
+	dir := filepath.Join(root, ".beads", "formulas")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := `formula = "` + name + `"
+type = "workflow"
+version = ` + strconv.Itoa(version) + `
+
+[[steps]]
+id = "work"
+title = "Work"
+`
+	if err := os.WriteFile(filepath.Join(dir, name+".formula.toml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func tierTestResolver(t *testing.T) (*Resolver, string, string) {
+	t.Helper()
+	town := t.TempDir()
+	rig := filepath.Join(town, "myrig")
+	return NewResolver(town, rig), town, rig
+}
+
+func TestResolve_TierPrecedence(t *testing.T) {
+	r, town, rig := tierTestResolver(t)
+
+	res, err := r.Resolve("mol-polecat-work", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Tier != TierSystem || res.Path != "embedded:formulas/mol-polecat-work.formula.toml" || res.Version == 0 {
+		t.Errorf("embedded only: %+v", res)
+	}
+	systemVersion := res.Version
+
+	writeTierFormula(t, town, "mol-polecat-work", 9)
+	if res, _ = r.Resolve("mol-polecat-work", ""); res.Tier != TierTown || res.Version != 9 {
+		t.Errorf("with town copy: %+v", res)
+	}
+
+	writeTierFormula(t, rig, "mol-polecat-work", 1)
+	res, err = r.Resolve("mol-polecat-work", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Tier != TierProject || res.Path != filepath.Join(rig, ".beads", "formulas", "mol-polecat-work.formula.toml") {
+		t.Errorf("with project copy: %+v", res)
+	}
+	if len(res.Shadows) != 2 || res.Shadows[0].Tier != TierTown || res.Shadows[1].Tier != TierSystem {
+		t.Errorf("shadows = %+v", res.Shadows)
+	}
+	if stale := res.Stale(); stale == nil || stale.Tier != TierTown {
+		t.Errorf("Stale() = %+v, want the newer town copy", stale)
+	}
+	if f, err := res.Parse(); err != nil || f.Version != 1 {
+		t.Errorf("Parse() = %+v, %v", f, err)
+	}
+
+	// A tier override reads only that tier.
+	res, err = r.Resolve("mol-polecat-work", TierSystem)
+	if err != nil || res.Tier != TierSystem || res.Version != systemVersion {
+		t.Errorf("--tier system = %+v, %v", res, err)
+	}
+}
+
+func TestTrace_SearchPath(t *testing.T) {
+	r, town, _ := tierTestResolver(t)
+	writeTierFormula(t, town, "mol-custom", 2)
+
+	res, steps, err := r.Trace("mol-custom", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Tier != TierTown {
+		t.Errorf("resolved tier = %s", res.Tier)
+	}
+	var got []string
+	for _, s := range steps {
+		got = append(got, string(s.Tier)+":"+filepath.Base(s.Path)+map[bool]string{true: "+"}[s.Found])
+	}
+	want := []string{
+		"project:mol-custom.formula.toml", "project:mol-custom.formula.json",
+		"town:mol-custom.formula.toml+",
+		"system:mol-custom.formula.toml", "system:mol-custom.formula.json",
+	}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("steps = %v, want %v", got, want)
+	}
+
+	_, steps, err = r.Trace("mol-custom", TierSystem)
+	if !errors.Is(err, ErrFormulaNotFound) {
+		t.Errorf("--tier system err = %v, want ErrFormulaNotFound", err)
+	}
+	if len(steps) == 0 || !steps[0].Skipped || steps[0].Tier != TierProject {
+		t.Errorf("steps with tier override = %+v", steps)
+	}
+}
+
+func TestResolve_OutsideTown(t *testing.T) {
+	r := NewResolver("", "")
+	_, steps, err := r.Trace("mol-polecat-work", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range steps {
+		if s.Tier != TierSystem {
+			t.Errorf("searched %s tier with no town or rig", s.Tier)
+		}
+	}
+	if _, err := r.Resolve("../etc/passwd", ""); err == nil {
+		t.Error("path-like names should be rejected")
+	}
+}
+
+func TestResolverList(t *testing.T) {
+	r, town, rig := tierTestResolver(t)
+	writeTierFormula(t, town, "mol-town-only", 1)
+	writeTierFormula(t, rig, "mol-polecat-work", 7)
+
+	list, err := r.List("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := map[string]*Resolved{}
+	for _, f := range list {
+		byName[f.Name] = f
+	}
+	if f := byName["mol-town-only"]; f == nil || f.Tier != TierTown {
+		t.Errorf("mol-town-only = %+v", f)
+	}
+	if f := byName["mol-polecat-work"]; f == nil || f.Tier != TierProject || len(f.Shadows) != 1 {
+		t.Errorf("mol-polecat-work = %+v", f)
+	}
+	if f := byName["mol-deacon-patrol"]; f == nil || f.Tier != TierSystem {
+		t.Errorf("mol-deacon-patrol = %+v", f)
+	}
+
+	list, err = r.List(TierTown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Name != "mol-town-only" {
+		t.Errorf("List(town) = %+v", list)
+	}
+}
+
+func TestParseTier(t *testing.T) {
+	for _, s := range []string{"project", "town", "system"} {
+		if tier, err := ParseTier(s); err != nil || string(tier) != s {
+			t.Errorf("ParseTier(%q) = %q, %v", s, tier, err)
+		}
+	}
+	if _, err := ParseTier("user"); err == nil {
+		t.Error("ParseTier(user) should fail")
+	}
+}
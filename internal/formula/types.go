@@ -27,93 +27,255 @@ const (
 // Formula represents a parsed formula.toml file.
 type Formula struct {
 	// Common fields
-	Name        string      `toml:"formula"`
-	Description string      `toml:"description"`
-	Type        FormulaType `toml:"type"`
-	Version     int         `toml:"version"`
+	Name        string      `toml:"formula,omitempty"`
+	Description string      `toml:"description,omitempty"`
+	Type        FormulaType `toml:"type,omitempty"`
+	Version     int         `toml:"version,omitempty"`
 
 	// Convoy-specific
-	Inputs    map[string]Input `toml:"inputs"`
-	Prompts   map[string]string `toml:"prompts"`
-	Output    *Output           `toml:"output"`
-	Legs      []Leg             `toml:"legs"`
-	Synthesis *Synthesis        `toml:"synthesis"`
+	Inputs    map[string]Input `toml:"inputs,omitempty"`
+	Prompts   map[string]string `toml:"prompts,omitempty"`
+	Output    *Output           `toml:"output,omitempty"`
+	Legs      []Leg             `toml:"legs,omitempty"`
+	Synthesis *Synthesis        `toml:"synthesis,omitempty"`
 
 	// Workflow-specific
-	Steps []Step           `toml:"steps"`
-	Vars  map[string]Var   `toml:"vars"`
+	Steps []Step           `toml:"steps,omitempty"`
+	Vars  map[string]Var   `toml:"vars,omitempty"`
 
 	// Expansion-specific
-	Template []Template `toml:"template"`
+	Template []Template `toml:"template,omitempty"`
 
 	// Aspect-specific (similar to convoy but for analysis)
-	Aspects []Aspect `toml:"aspects"`
+	Aspects []Aspect `toml:"aspects,omitempty"`
+
+	// Aspect weaving: steps added around the matching steps of formulas
+	// that list this aspect in compose.aspects.
+	Advice    []Advice   `toml:"advice,omitempty"`
+	Pointcuts []Pointcut `toml:"pointcuts,omitempty"`
+
+	// Composition, flattened by Resolver.Expand
+	Extends FormulaRefs `toml:"extends,omitempty"`
+	Include []Include   `toml:"include,omitempty"`
+	Compose *Compose    `toml:"compose,omitempty"`
+
+	// expanded maps each foreach step replaced by ExpandForEach to its
+	// child step IDs.
+	expanded map[string][]string
+	// composedFrom lists the formulas flattened into this one.
+	composedFrom []string
+}
+
+// FormulaRefs is a list of formula names. It may be written as a single
+// string (extends = "shiny") or a list (extends = ["shiny", "base"]).
+type FormulaRefs []string
+
+// UnmarshalTOML allows FormulaRefs to be decoded from a string or an array
+// of strings.
+func (r *FormulaRefs) UnmarshalTOML(data any) error {
+	switch val := data.(type) {
+	case string:
+		*r = FormulaRefs{val}
+		return nil
+	case []any:
+		for _, item := range val {
+			s, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("expected formula name, got %T", item)
+			}
+			*r = append(*r, s)
+		}
+		return nil
+	default:
+		return fmt.Errorf("expected string or array of formula names, got %T", data)
+	}
+}
+
+// Include pulls another workflow formula's steps into this one, with IDs
+// namespaced as <prefix>.<id>.
+type Include struct {
+	Formula string `toml:"formula,omitempty"`
+	// Prefix defaults to the included formula's name.
+	Prefix string `toml:"prefix,omitempty"`
+	// Needs are added to the included formula's entry steps.
+	Needs []string `toml:"needs,omitempty"`
+}
+
+// Compose lists the changes applied on top of extended and included steps.
+type Compose struct {
+	// Remove drops steps; their dependents inherit their needs.
+	Remove []string `toml:"remove,omitempty"`
+	// Expand replaces a step with an expansion formula's templates.
+	Expand []ExpandRule `toml:"expand,omitempty"`
+	// Aspects weaves aspect formulas' advice into the steps, in order.
+	Aspects []string `toml:"aspects,omitempty"`
+}
+
+// ExpandRule replaces the Target step with the templates of the With
+// expansion formula.
+type ExpandRule struct {
+	Target string `toml:"target,omitempty"`
+	With   string `toml:"with,omitempty"`
+}
+
+// Advice adds steps around every step whose ID matches the Target glob.
+// {step.id} and {step.title} in the advice steps refer to the matched step.
+type Advice struct {
+	Target string      `toml:"target,omitempty"`
+	Around AdviceSteps `toml:"around,omitempty"`
+}
+
+// AdviceSteps are the steps run before and after an advised step.
+type AdviceSteps struct {
+	Before []Step `toml:"before,omitempty"`
+	After  []Step `toml:"after,omitempty"`
+}
+
+// Pointcut limits an aspect to steps whose ID matches Glob.
+type Pointcut struct {
+	Glob string `toml:"glob,omitempty"`
 }
 
 // Aspect represents a parallel analysis aspect in an aspect formula.
 type Aspect struct {
-	ID          string `toml:"id"`
-	Title       string `toml:"title"`
-	Focus       string `toml:"focus"`
-	Description string `toml:"description"`
+	ID          string `toml:"id,omitempty"`
+	Title       string `toml:"title,omitempty"`
+	Focus       string `toml:"focus,omitempty"`
+	Description string `toml:"description,omitempty"`
 }
 
 // Input represents an input parameter for a formula.
 type Input struct {
-	Description    string   `toml:"description"`
-	Type           string   `toml:"type"`
-	Required       bool     `toml:"required"`
-	RequiredUnless []string `toml:"required_unless"`
-	Default        string   `toml:"default"`
+	Description    string   `toml:"description,omitempty"`
+	Type           string   `toml:"type,omitempty"`
+	Required       bool     `toml:"required,omitempty"`
+	RequiredUnless []string `toml:"required_unless,omitempty"`
+	Default        string   `toml:"default,omitempty"`
 }
 
 // Output configures where formula outputs are written.
 type Output struct {
-	Directory  string `toml:"directory"`
-	LegPattern string `toml:"leg_pattern"`
-	Synthesis  string `toml:"synthesis"`
+	Directory  string `toml:"directory,omitempty"`
+	LegPattern string `toml:"leg_pattern,omitempty"`
+	Synthesis  string `toml:"synthesis,omitempty"`
 }
 
 // Leg represents a parallel execution unit in a convoy formula.
 type Leg struct {
-	ID          string `toml:"id"`
-	Title       string `toml:"title"`
-	Focus       string `toml:"focus"`
-	Description string `toml:"description"`
+	ID          string `toml:"id,omitempty"`
+	Title       string `toml:"title,omitempty"`
+	Focus       string `toml:"focus,omitempty"`
+	Description string `toml:"description,omitempty"`
 }
 
 // Synthesis represents the synthesis step that combines leg outputs.
 type Synthesis struct {
-	Title       string   `toml:"title"`
-	Description string   `toml:"description"`
-	DependsOn   []string `toml:"depends_on"`
+	Title       string   `toml:"title,omitempty"`
+	Description string   `toml:"description,omitempty"`
+	DependsOn   []string `toml:"depends_on,omitempty"`
 }
 
 // Step represents a sequential step in a workflow formula.
 type Step struct {
-	ID          string   `toml:"id"`
-	Title       string   `toml:"title"`
-	Description string   `toml:"description"`
-	Needs       []string `toml:"needs"`
-	Parallel    bool     `toml:"parallel"`   // If true, this step can run concurrently with other parallel steps that share the same needs
-	Acceptance  string   `toml:"acceptance"` // Exit criteria for this step (used by Ralph loop mode)
+	ID          string   `toml:"id,omitempty"`
+	Title       string   `toml:"title,omitempty"`
+	Description string   `toml:"description,omitempty"`
+	Needs       []string `toml:"needs,omitempty"`
+	Parallel    bool     `toml:"parallel,omitempty"`   // If true, this step can run concurrently with other parallel steps that share the same needs
+	Acceptance  string   `toml:"acceptance,omitempty"` // Exit criteria for this step (used by Ralph loop mode)
+
+	// The control fields below are checked and RunState implements them,
+	// but nothing runs them for poured molecules yet, so Validate rejects
+	// them (ErrStepControlUnsupported).
+
+	// When is a condition over vars and prior step outcomes, e.g.
+	// `vars.mode == "full" && steps.test.outcome == "failure"`. The step is
+	// skipped when it is false. See ParseCondition.
+	When string `toml:"when,omitempty"`
+	// Retry re-runs the step (or an earlier step, with from) when it fails.
+	Retry *Retry `toml:"retry,omitempty"`
+	// ForEach names a list var; the step expands into one child step per
+	// item, with the item available as {{<as>}} (default {{item}}).
+	ForEach string `toml:"foreach,omitempty"`
+	As      string `toml:"as,omitempty"`
+	// Timeout bounds how long the step may run, as a Go duration ("30m").
+	Timeout string `toml:"timeout,omitempty"`
+
+	// Before and After splice a new step into an extended formula: before
+	// a step (taking over its needs) or after it (its dependents now need
+	// the new step). Only valid in a formula that extends another.
+	Before string `toml:"before,omitempty"`
+	After  string `toml:"after,omitempty"`
+}
+
+// Retry is a step's bounded retry policy. It may be written as a table or
+// as a bare attempt count (retry = 3).
+type Retry struct {
+	// Max is how many times the step is retried after the first failure.
+	Max int `toml:"max,omitempty"`
+	// Backoff is the delay before the first retry ("30s"); it doubles on
+	// each further retry, up to MaxBackoff.
+	Backoff    string `toml:"backoff,omitempty"`
+	MaxBackoff string `toml:"max_backoff,omitempty"`
+	// From re-runs the workflow from this earlier step (one the failing step
+	// needs, directly or transitively) instead of the step alone, e.g. going
+	// back to "fix" when "test" fails.
+	From string `toml:"from,omitempty"`
+}
+
+// UnmarshalTOML allows Retry to be decoded from an integer (the attempt
+// count) or a full TOML table.
+func (r *Retry) UnmarshalTOML(data any) error {
+	switch val := data.(type) {
+	case int64:
+		r.Max = int(val)
+		return nil
+	case map[string]any:
+		if m, ok := val["max"]; ok {
+			n, ok := m.(int64)
+			if !ok {
+				return fmt.Errorf("retry max must be an integer, got %T", m)
+			}
+			r.Max = int(n)
+		}
+		for key, dst := range map[string]*string{"backoff": &r.Backoff, "max_backoff": &r.MaxBackoff, "from": &r.From} {
+			if v, ok := val[key]; ok {
+				s, ok := v.(string)
+				if !ok {
+					return fmt.Errorf("retry %s must be a string, got %T", key, v)
+				}
+				*dst = s
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("expected integer or table for retry, got %T", data)
+	}
 }
 
 // Template represents a template step in an expansion formula.
 type Template struct {
-	ID          string   `toml:"id"`
-	Title       string   `toml:"title"`
-	Description string   `toml:"description"`
-	Needs       []string `toml:"needs"`
+	ID          string   `toml:"id,omitempty"`
+	Title       string   `toml:"title,omitempty"`
+	Description string   `toml:"description,omitempty"`
+	Needs       []string `toml:"needs,omitempty"`
 }
 
 // Var represents a variable definition for formulas.
 // Supports both shorthand string syntax (wisp_type = "gc_report")
 // and full table syntax ([vars.wisp_type] with description/required/default).
 type Var struct {
-	Description string `toml:"description"`
-	Required    bool   `toml:"required"`
-	Default     string `toml:"default"`
+	Description string `toml:"description,omitempty"`
+	Required    bool   `toml:"required,omitempty"`
+	Default     string `toml:"default,omitempty"`
+
+	// Type is the kind of value the var holds; untyped vars are strings.
+	Type VarType `toml:"type,omitempty"`
+	// Enum lists the allowed values of an enum var, or of a list var's items.
+	Enum []string `toml:"enum,omitempty"`
+	// Pattern is a regular expression the whole value (each item, for a
+	// list) must match.
+	Pattern string `toml:"pattern,omitempty"`
 }
 
 // UnmarshalTOML allows Var to be decoded from either a plain string
@@ -139,6 +301,25 @@ func (v *Var) UnmarshalTOML(data any) error {
 				v.Default = s
 			}
 		}
+		if t, ok := val["type"]; ok {
+			if s, ok := t.(string); ok {
+				v.Type = VarType(s)
+			}
+		}
+		if e, ok := val["enum"]; ok {
+			items, ok := e.([]any)
+			if !ok {
+				return fmt.Errorf("expected array for Var enum, got %T", e)
+			}
+			for _, item := range items {
+				v.Enum = append(v.Enum, fmt.Sprint(item))
+			}
+		}
+		if p, ok := val["pattern"]; ok {
+			if s, ok := p.(string); ok {
+				v.Pattern = s
+			}
+		}
 		return nil
 	default:
 		return fmt.Errorf("expected string or table for Var, got %T", data)
@@ -0,0 +1,244 @@
+package formula
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// VarType is the kind of value a formula var holds.
+type VarType string
+
+const (
+	// VarString is any text. Untyped vars are strings.
+	VarString VarType = "string"
+	// VarInt is a base-10 integer.
+	VarInt VarType = "int"
+	// VarBool is "true" or "false", the values step conditions understand.
+	VarBool VarType = "bool"
+	// VarEnum is one of the var's enum values.
+	VarEnum VarType = "enum"
+	// VarBeadID is the ID of an existing bead, e.g. "gt-abc12".
+	VarBeadID VarType = "bead-id"
+	// VarRigName is the name of a rig registered in the town.
+	VarRigName VarType = "rig-name"
+	// VarPath is a file system path.
+	VarPath VarType = "path"
+	// VarList is a comma- or newline-separated list (see SplitListVar),
+	// usable by foreach steps.
+	VarList VarType = "list"
+)
+
+// VarTypes lists the supported var types.
+var VarTypes = []VarType{VarString, VarInt, VarBool, VarEnum, VarBeadID, VarRigName, VarPath, VarList}
+
+// IsValid returns true if the var type is recognized. The empty type is a
+// string.
+func (t VarType) IsValid() bool {
+	return t == "" || slices.Contains(VarTypes, t)
+}
+
+var (
+	beadIDPattern  = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9]*-[a-zA-Z0-9][a-zA-Z0-9.-]*$`)
+	rigNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+)
+
+// VarLookup checks var values that name things outside the formula: beads
+// and rigs. Without one, only their syntax is checked.
+type VarLookup interface {
+	BeadExists(id string) error
+	RigExists(name string) error
+}
+
+// validateVars checks the var declarations: known types, enum values,
+// patterns that compile and defaults that fit. Called by Validate.
+func (f *Formula) validateVars() error {
+	names := make([]string, 0, len(f.Vars))
+	for name := range f.Vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		v := f.Vars[name]
+		if !v.Type.IsValid() {
+			return fmt.Errorf("var %q: invalid type %q (must be one of %s)", name, v.Type, joinVarTypes())
+		}
+		if v.Type == VarEnum && len(v.Enum) == 0 {
+			return fmt.Errorf("var %q: enum type requires enum values", name)
+		}
+		if len(v.Enum) > 0 && v.Type != VarEnum && v.Type != VarList {
+			return fmt.Errorf("var %q: enum values only apply to enum and list vars", name)
+		}
+		if v.Pattern != "" {
+			if _, err := regexp.Compile(v.Pattern); err != nil {
+				return fmt.Errorf("var %q: invalid pattern: %w", name, err)
+			}
+		}
+		if v.Default != "" {
+			if err := v.CheckValue(v.Default, nil); err != nil {
+				return fmt.Errorf("var %q: default %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func joinVarTypes() string {
+	names := make([]string, len(VarTypes))
+	for i, t := range VarTypes {
+		names[i] = string(t)
+	}
+	return strings.Join(names, ", ")
+}
+
+// CheckValue reports whether value fits the var's type, enum and pattern.
+// An empty value means unset and is only an error for required vars.
+// lookup, if non-nil, checks that bead-id and rig-name values exist.
+func (v Var) CheckValue(value string, lookup VarLookup) error {
+	if value == "" {
+		if v.Required {
+			return fmt.Errorf("is required")
+		}
+		return nil
+	}
+	if v.Type == VarList {
+		for _, item := range SplitListVar(value) {
+			if err := v.checkItem(item, lookup); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return v.checkItem(value, lookup)
+}
+
+// checkItem validates a single value (or list item).
+func (v Var) checkItem(value string, lookup VarLookup) error {
+	switch v.Type {
+	case VarInt:
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Errorf("%q is not an integer", value)
+		}
+	case VarBool:
+		if value != "true" && value != "false" {
+			return fmt.Errorf("%q is not a bool (want true or false)", value)
+		}
+	case VarBeadID:
+		if !beadIDPattern.MatchString(value) {
+			return fmt.Errorf("%q is not a bead ID", value)
+		}
+		if lookup != nil {
+			if err := lookup.BeadExists(value); err != nil {
+				return err
+			}
+		}
+	case VarRigName:
+		if !rigNamePattern.MatchString(value) {
+			return fmt.Errorf("%q is not a rig name", value)
+		}
+		if lookup != nil {
+			if err := lookup.RigExists(value); err != nil {
+				return err
+			}
+		}
+	case VarPath:
+		if strings.ContainsAny(value, "\x00\n\r") {
+			return fmt.Errorf("%q is not a valid path", value)
+		}
+	}
+
+	if len(v.Enum) > 0 && !slices.Contains(v.Enum, value) {
+		return fmt.Errorf("%q is not one of %s", value, strings.Join(v.Enum, ", "))
+	}
+	if v.Pattern != "" {
+		re, err := regexp.Compile("^(?:" + v.Pattern + ")$")
+		if err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("%q does not match pattern %s", value, v.Pattern)
+		}
+	}
+	return nil
+}
+
+// Completions returns the values a shell can offer for the var: enum
+// values, or true and false for a bool.
+func (v Var) Completions() []string {
+	switch {
+	case len(v.Enum) > 0:
+		return v.Enum
+	case v.Type == VarBool:
+		return []string{"true", "false"}
+	}
+	return nil
+}
+
+// ValidateVars checks var values supplied at run time (e.g. from --var)
+// against the formula's declarations, so a typo or a forgotten var fails
+// now rather than inside a polecat hours later. Names the formula doesn't
+// declare are rejected, and so are required vars with no default that are
+// neither in values nor in provided (names the caller fills in itself, such
+// as issue when slinging onto a bead). lookup, if non-nil, checks bead-id
+// and rig-name values exist.
+func (f *Formula) ValidateVars(values map[string]string, lookup VarLookup, provided ...string) error {
+	problems := f.checkVarValues(values, lookup)
+	for _, name := range f.MissingVars(values, provided...) {
+		problems = append(problems, fmt.Sprintf("missing required var %q", name))
+	}
+	return f.varProblems(problems)
+}
+
+// ValidateVarValues checks only the values supplied, without requiring the
+// rest, for callers that don't instantiate the formula (e.g. drawing it).
+func (f *Formula) ValidateVarValues(values map[string]string, lookup VarLookup) error {
+	return f.varProblems(f.checkVarValues(values, lookup))
+}
+
+// MissingVars returns, sorted, the required vars without a default that are
+// neither in values nor in provided.
+func (f *Formula) MissingVars(values map[string]string, provided ...string) []string {
+	var missing []string
+	for name, v := range f.Vars {
+		if !v.Required || v.Default != "" || slices.Contains(provided, name) {
+			continue
+		}
+		if _, ok := values[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+func (f *Formula) checkVarValues(values map[string]string, lookup VarLookup) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []string
+	for _, name := range names {
+		v, ok := f.Vars[name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown var %q", name))
+			continue
+		}
+		if err := v.CheckValue(values[name], lookup); err != nil {
+			problems = append(problems, fmt.Sprintf("var %q: %v", name, err))
+		}
+	}
+	return problems
+}
+
+func (f *Formula) varProblems(problems []string) error {
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid formula vars for %s:\n  %s", f.Name, strings.Join(problems, "\n  "))
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package formula
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const typedVarsFormula = `
+formula = "typed"
+type = "workflow"
+
+[vars.count]
+type = "int"
+default = "3"
+
+[vars.dry_run]
+type = "bool"
+default = "false"
+
+[vars.period]
+type = "enum"
+enum = ["daily", "weekly"]
+default = "daily"
+
+[vars.issue]
+type = "bead-id"
+
+[vars.rig]
+type = "rig-name"
+
+[vars.out]
+type = "path"
+
+[vars.targets]
+type = "list"
+enum = ["linux", "darwin", "windows"]
+default = "linux,darwin"
+
+[vars.version]
+pattern = 'v\d+\.\d+\.\d+'
+required = true
+
+[vars.note]
+description = "Untyped vars stay strings"
+
+[[steps]]
+id = "build"
+title = "Build {{version}}"
+`
+
+// fakeLookup knows one bead and one rig.
+type fakeLookup struct{}
+
+func (fakeLookup) BeadExists(id string) error {
+	if id != "gt-abc12" {
+		return fmt.Errorf("bead %q not found", id)
+	}
+	return nil
+}
+
+func (fakeLookup) RigExists(name string) error {
+	if name != "gastown" {
+		return fmt.Errorf("rig %q is not registered", name)
+	}
+	return nil
+}
+
+func TestValidateVars(t *testing.T) {
+	f, err := Parse([]byte(typedVarsFormula))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := f.Vars["period"]; v.Type != VarEnum || !reflect.DeepEqual(v.Enum, []string{"daily", "weekly"}) {
+		t.Errorf("period = %+v", v)
+	}
+
+	valid := map[string]string{
+		"count": "12", "dry_run": "true", "period": "weekly", "issue": "gt-abc12",
+		"rig": "gastown", "out": "/tmp/out dir", "targets": "windows\nlinux",
+		"version": "v1.2.3", "note": "anything at all",
+	}
+	if err := f.ValidateVars(valid, fakeLookup{}); err != nil {
+		t.Errorf("valid vars: %v", err)
+	}
+
+	tests := []struct {
+		name, value string
+		lookup      VarLookup
+		want        string
+	}{
+		{"count", "twelve", nil, "not an integer"},
+		{"dry_run", "yes", nil, "not a bool"},
+		{"period", "monthly", nil, "not one of daily, weekly"},
+		{"issue", "not a bead", nil, "not a bead ID"},
+		{"issue", "gt-zzz99", fakeLookup{}, `bead "gt-zzz99" not found`},
+		{"rig", "no/slash", nil, "not a rig name"},
+		{"rig", "beads", fakeLookup{}, "not registered"},
+		{"out", "a\nb", nil, "not a valid path"},
+		{"targets", "linux, solaris", nil, `"solaris" is not one of`},
+		{"version", "1.2", nil, "does not match pattern"},
+		{"version", "", nil, "is required"},
+		{"verison", "v1.2.3", nil, `unknown var "verison"`},
+	}
+	for _, tt := range tests {
+		err := f.ValidateVarValues(map[string]string{tt.name: tt.value}, tt.lookup)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s=%q: err = %v, want %q", tt.name, tt.value, err, tt.want)
+		}
+	}
+
+	// Syntax is still checked without a lookup, but existence isn't.
+	if err := f.ValidateVarValues(map[string]string{"issue": "gt-zzz99", "rig": "beads"}, nil); err != nil {
+		t.Errorf("without lookup: %v", err)
+	}
+	// An empty optional value means unset.
+	if err := f.ValidateVarValues(map[string]string{"count": ""}, nil); err != nil {
+		t.Errorf("empty optional: %v", err)
+	}
+}
+
+func TestValidateVars_MissingRequired(t *testing.T) {
+	f, err := Parse([]byte(typedVarsFormula))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = f.ValidateVars(nil, nil)
+	if err == nil || !strings.Contains(err.Error(), `missing required var "version"`) {
+		t.Errorf("no vars: err = %v, want missing version", err)
+	}
+	// Vars with defaults, and optional ones, aren't reported.
+	if got := f.MissingVars(nil); !reflect.DeepEqual(got, []string{"version"}) {
+		t.Errorf("MissingVars = %v, want [version]", got)
+	}
+	// A var the caller fills in itself counts as set.
+	if err := f.ValidateVars(nil, nil, "version"); err != nil {
+		t.Errorf("provided by caller: %v", err)
+	}
+	if err := f.ValidateVars(map[string]string{"version": "v1.0.0"}, nil); err != nil {
+		t.Errorf("supplied: %v", err)
+	}
+}
+
+func TestValidate_VarDeclarations(t *testing.T) {
+	tests := []struct {
+		name, vars, want string
+	}{
+		{"unknown type", `[vars.x]
+type = "float"`, `invalid type "float"`},
+		{"enum without values", `[vars.x]
+type = "enum"`, "requires enum values"},
+		{"enum on int", `[vars.x]
+type = "int"
+enum = ["1"]`, "only apply to enum and list"},
+		{"bad pattern", `[vars.x]
+pattern = "("`, "invalid pattern"},
+		{"bad default", `[vars.x]
+type = "int"
+default = "many"`, "default \"many\" is not an integer"},
+		{"foreach over non-list", `[vars.x]
+type = "int"
+[[steps]]
+id = "b"
+foreach = "x"`, "not a list"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte("formula = \"x\"\n[[steps]]\nid = \"a\"\n" + tt.vars))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVarCompletions(t *testing.T) {
+	f, err := Parse([]byte(typedVarsFormula))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := f.Vars["period"].Completions(); !reflect.DeepEqual(got, []string{"daily", "weekly"}) {
+		t.Errorf("enum completions = %v", got)
+	}
+	if got := f.Vars["dry_run"].Completions(); !reflect.DeepEqual(got, []string{"true", "false"}) {
+		t.Errorf("bool completions = %v", got)
+	}
+	if got := f.Vars["note"].Completions(); got != nil {
+		t.Errorf("string completions = %v", got)
+	}
+}
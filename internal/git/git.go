@@ -9,6 +9,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 )
 
@@ -394,6 +395,13 @@ func (g *Git) Pull(remote, branch string) error {
 	return err
 }
 
+// PullFastForward pulls from the remote branch only if the local branch can
+// fast-forward to it; it never creates a merge commit.
+func (g *Git) PullFastForward(remote, branch string) error {
+	_, err := g.run("pull", "--ff-only", remote, branch)
+	return err
+}
+
 // ConfigurePushURL sets the push URL for a remote while keeping the fetch URL.
 // This is useful for read-only upstream repos where you want to push to a fork.
 // Example: ConfigurePushURL("origin", "https://github.com/user/fork.git")
@@ -639,6 +647,79 @@ func (g *Git) MergeSquash(branch, message string) error {
 	return err
 }
 
+// MergeFFOnly fast-forwards the current branch to ref, failing if that is
+// not possible.
+func (g *Git) MergeFFOnly(ref string) error {
+	_, err := g.run("merge", "--ff-only", ref)
+	return err
+}
+
+// CheckoutDetached checks out ref with a detached HEAD, so that commits made
+// there (e.g. by a rebase) leave every branch untouched.
+func (g *Git) CheckoutDetached(ref string) error {
+	_, err := g.run("checkout", "--detach", ref)
+	return err
+}
+
+// CommitSubjects returns the subject lines of the commits on branch that are
+// not on base, oldest first.
+func (g *Git) CommitSubjects(base, branch string) ([]string, error) {
+	out, err := g.run("log", "--reverse", "--format=%s", base+".."+branch)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// Trailer is a "Key: value" line at the end of a commit message, in the
+// form `git interpret-trailers` reads (e.g. "Issue: gt-abc").
+type Trailer struct {
+	Key   string
+	Value string
+}
+
+// WithTrailers appends trailers to message as a final paragraph. Trailers
+// with an empty value are skipped. If message already ends in a trailer
+// block, the new trailers join it instead of starting a new paragraph.
+func WithTrailers(message string, trailers ...Trailer) string {
+	var lines []string
+	for _, t := range trailers {
+		if t.Value != "" {
+			lines = append(lines, t.Key+": "+t.Value)
+		}
+	}
+	message = strings.TrimRight(message, "\n")
+	if len(lines) == 0 {
+		return message
+	}
+	paragraphs := strings.Split(message, "\n\n")
+	sep := "\n\n"
+	if len(paragraphs) > 1 && isTrailerBlock(paragraphs[len(paragraphs)-1]) {
+		sep = "\n"
+	}
+	return message + sep + strings.Join(lines, "\n")
+}
+
+// isTrailerBlock reports whether every line of paragraph is "Key: value"
+// with a token key (letters, digits and dashes).
+func isTrailerBlock(paragraph string) bool {
+	for _, line := range strings.Split(paragraph, "\n") {
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok || key == "" || strings.TrimSpace(value) == "" {
+			return false
+		}
+		for _, r := range key {
+			if !(r == '-' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // GetBranchCommitMessage returns the commit message of the HEAD commit on the given branch.
 // This is useful for preserving the original conventional commit message (feat:/fix:) when
 // performing squash merges.
@@ -746,6 +827,33 @@ func (g *Git) runMergeCheck(args ...string) (string, error) {
 	return strings.TrimSpace(stdout.String()), nil
 }
 
+// DiffFileStat is one file's line counts from git diff --numstat.
+type DiffFileStat struct {
+	Path    string
+	Added   int // 0 for binary files
+	Deleted int // 0 for binary files
+}
+
+// DiffNumstat returns per-file line counts for the changes head introduces
+// since its merge base with base (git diff --numstat base...head).
+func (g *Git) DiffNumstat(base, head string) ([]DiffFileStat, error) {
+	out, err := g.run("diff", "--numstat", base+"..."+head)
+	if err != nil {
+		return nil, err
+	}
+	var stats []DiffFileStat
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		added, _ := strconv.Atoi(parts[0]) // "-" for binary files
+		deleted, _ := strconv.Atoi(parts[1])
+		stats = append(stats, DiffFileStat{Path: parts[2], Added: added, Deleted: deleted})
+	}
+	return stats, nil
+}
+
 // GetConflictingFiles returns the list of files with merge conflicts.
 // ZFC: Uses git's porcelain output (diff --diff-filter=U) instead of parsing stderr.
 // This is the proper way to detect conflicts without violating ZFC.
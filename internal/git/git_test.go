@@ -1554,3 +1554,86 @@ func TestClearPushURL(t *testing.T) {
 		t.Errorf("ClearPushURL (idempotent) should not error, got: %v", err)
 	}
 }
+
+func TestWithTrailers(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		trailers []Trailer
+		want     string
+	}{
+		{"subject only", "Fix login (gt-1)\n", []Trailer{{"Issue", "gt-1"}, {"Polecat", ""}},
+			"Fix login (gt-1)\n\nIssue: gt-1"},
+		{"body", "Fix login\n\nLonger text.", []Trailer{{"Issue", "gt-1"}},
+			"Fix login\n\nLonger text.\n\nIssue: gt-1"},
+		{"existing trailers", "Fix login\n\nSigned-off-by: A <a@b>", []Trailer{{"Issue", "gt-1"}},
+			"Fix login\n\nSigned-off-by: A <a@b>\nIssue: gt-1"},
+		{"no trailers", "Fix login\n", nil, "Fix login"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WithTrailers(tt.message, tt.trailers...); got != tt.want {
+				t.Errorf("WithTrailers() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeFFOnlyAndCommitSubjects(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	main, err := g.CurrentBranch()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.CreateBranch("feature"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Checkout("feature"); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := g.Add(name); err != nil {
+			t.Fatal(err)
+		}
+		if err := g.Commit("add " + name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	subjects, err := g.CommitSubjects(main, "feature")
+	if err != nil || strings.Join(subjects, ",") != "add a.txt,add b.txt" {
+		t.Errorf("CommitSubjects = %v, %v", subjects, err)
+	}
+
+	if err := g.Checkout(main); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.MergeFFOnly("feature"); err != nil {
+		t.Fatalf("MergeFFOnly: %v", err)
+	}
+	head, _ := g.Rev("HEAD")
+	feature, _ := g.Rev("feature")
+	if head != feature {
+		t.Errorf("HEAD = %s, want feature tip %s", head, feature)
+	}
+
+	// A diverged branch cannot be fast-forwarded.
+	if err := g.CheckoutDetached("HEAD~1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "c.txt"), []byte("c"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_ = g.Add("c.txt")
+	if err := g.Commit("add c.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.MergeFFOnly("feature"); err == nil {
+		t.Error("MergeFFOnly of a diverged branch should fail")
+	}
+}
@@ -187,7 +187,7 @@ func NewPruner(townRoot string, config *Config) *Pruner {
 	}
 }
 
-// Prune removes expired events from the events and feed files.
+// Prune removes expired events from the event store and the feed file.
 // It operates atomically by writing to temp files then renaming.
 func (p *Pruner) Prune() (*PruneResult, error) {
 	start := time.Now()
@@ -195,14 +195,18 @@ func (p *Pruner) Prune() (*PruneResult, error) {
 		PrunedByType: make(map[string]int),
 	}
 
-	// Prune events file
-	eventsResult, err := p.pruneFile(filepath.Join(p.townRoot, events.EventsFile))
+	// Prune the event store. Whole segments past their TTL are dropped
+	// without being read.
+	now := time.Now()
+	eventsResult, err := events.OpenStore(p.townRoot).Prune(func(eventType string, ts time.Time) bool {
+		return now.Sub(ts) > p.config.GetTTL(eventType)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("pruning events: %w", err)
 	}
-	result.EventsProcessed += eventsResult.EventsProcessed
-	result.EventsPruned += eventsResult.EventsPruned
-	result.EventsRetained += eventsResult.EventsRetained
+	result.EventsProcessed += eventsResult.Processed
+	result.EventsPruned += eventsResult.Pruned
+	result.EventsRetained += eventsResult.Processed - eventsResult.Pruned
 	result.BytesBefore += eventsResult.BytesBefore
 	result.BytesAfter += eventsResult.BytesAfter
 	for k, v := range eventsResult.PrunedByType {
@@ -370,7 +374,7 @@ func (p *Pruner) pruneFile(filePath string) (result *PruneResult, err error) {
 
 // Stats contains statistics about the current ephemeral data.
 type Stats struct {
-	EventsFile   FileStats          `json:"events_file"`
+	EventsFile   FileStats          `json:"events_file"` // the event store directory
 	FeedFile     FileStats          `json:"feed_file"`
 	ByType       map[string]int     `json:"by_type"`
 	ByAge        map[string]int     `json:"by_age"` // "0-1d", "1-7d", "7-30d", "30d+"
@@ -379,7 +383,7 @@ type Stats struct {
 	TTLBreakdown map[string]TTLInfo `json:"ttl_breakdown"`
 }
 
-// FileStats contains statistics for a single file.
+// FileStats contains statistics for a single file or the event store.
 type FileStats struct {
 	Path       string `json:"path"`
 	Size       int64  `json:"size"`
@@ -404,10 +408,9 @@ func GetStats(townRoot string, config *Config) (*Stats, error) {
 
 	now := time.Now()
 
-	// Process events file
-	eventsPath := filepath.Join(townRoot, events.EventsFile)
-	eventsStats, oldest, newest, err := getFileStats(eventsPath, config, now, stats.ByType, stats.ByAge, stats.TTLBreakdown)
-	if err != nil && !os.IsNotExist(err) {
+	// Process event store
+	eventsStats, oldest, newest, err := getStoreStats(events.OpenStore(townRoot), config, now, stats.ByType, stats.ByAge, stats.TTLBreakdown)
+	if err != nil {
 		return nil, err
 	}
 	stats.EventsFile = eventsStats
@@ -435,6 +438,27 @@ func GetStats(townRoot string, config *Config) (*Stats, error) {
 	return stats, nil
 }
 
+func getStoreStats(store *events.Store, config *Config, now time.Time, byType, byAge map[string]int, ttlBreakdown map[string]TTLInfo) (FileStats, time.Time, time.Time, error) {
+	stats := FileStats{Path: store.Dir()}
+	var oldest, newest time.Time
+
+	storeStats, err := store.Stats()
+	if err != nil {
+		return stats, oldest, newest, err
+	}
+	stats.Size = storeStats.Size
+	stats.EventCount = storeStats.Count
+
+	err = store.Each(events.Query{}, func(rec events.Record) bool {
+		byType[rec.Type]++
+		if ts := rec.Time(); !ts.IsZero() {
+			oldest, newest = recordEventAge(rec.Type, ts, oldest, newest, config, now, byAge, ttlBreakdown)
+		}
+		return true
+	})
+	return stats, oldest, newest, err
+}
+
 func getFileStats(filePath string, config *Config, now time.Time, byType, byAge map[string]int, ttlBreakdown map[string]TTLInfo) (FileStats, time.Time, time.Time, error) {
 	stats := FileStats{Path: filePath}
 	var oldest, newest time.Time
@@ -476,43 +500,50 @@ func getFileStats(filePath string, config *Config, now time.Time, byType, byAge
 			continue
 		}
 
-		// Track oldest/newest
-		if oldest.IsZero() || ts.Before(oldest) {
-			oldest = ts
-		}
-		if newest.IsZero() || ts.After(newest) {
-			newest = ts
-		}
+		oldest, newest = recordEventAge(event.Type, ts, oldest, newest, config, now, byAge, ttlBreakdown)
+	}
 
-		// Age bucket
-		age := now.Sub(ts)
-		switch {
-		case age < 24*time.Hour:
-			byAge["0-1d"]++
-		case age < 7*24*time.Hour:
-			byAge["1-7d"]++
-		case age < 30*24*time.Hour:
-			byAge["7-30d"]++
-		default:
-			byAge["30d+"]++
-		}
+	return stats, oldest, newest, scanner.Err()
+}
 
-		// TTL breakdown
-		ttl := config.GetTTL(event.Type)
-		info := ttlBreakdown[event.Type]
-		info.TTL = ttl
-		info.Count++
-		if age > ttl {
-			info.Expired++
-		} else {
-			// Calculate time until this event expires
-			expiresIn := ttl - age
-			if info.ExpiresIn == 0 || expiresIn < info.ExpiresIn {
-				info.ExpiresIn = expiresIn
-			}
+// recordEventAge adds one event to the age and TTL breakdowns and returns
+// the updated oldest and newest timestamps.
+func recordEventAge(eventType string, ts, oldest, newest time.Time, config *Config, now time.Time, byAge map[string]int, ttlBreakdown map[string]TTLInfo) (time.Time, time.Time) {
+	// Track oldest/newest
+	if oldest.IsZero() || ts.Before(oldest) {
+		oldest = ts
+	}
+	if newest.IsZero() || ts.After(newest) {
+		newest = ts
+	}
+
+	// Age bucket
+	age := now.Sub(ts)
+	switch {
+	case age < 24*time.Hour:
+		byAge["0-1d"]++
+	case age < 7*24*time.Hour:
+		byAge["1-7d"]++
+	case age < 30*24*time.Hour:
+		byAge["7-30d"]++
+	default:
+		byAge["30d+"]++
+	}
+
+	// TTL breakdown
+	ttl := config.GetTTL(eventType)
+	info := ttlBreakdown[eventType]
+	info.TTL = ttl
+	info.Count++
+	if age > ttl {
+		info.Expired++
+	} else {
+		// Calculate time until this event expires
+		expiresIn := ttl - age
+		if info.ExpiresIn == 0 || expiresIn < info.ExpiresIn {
+			info.ExpiresIn = expiresIn
 		}
-		ttlBreakdown[event.Type] = info
 	}
-
-	return stats, oldest, newest, scanner.Err()
+	ttlBreakdown[eventType] = info
+	return oldest, newest
 }
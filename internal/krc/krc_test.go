@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -145,7 +147,7 @@ func TestPruner_Prune(t *testing.T) {
 	eventsPath := filepath.Join(tmpDir, ".events.jsonl")
 	now := time.Now().UTC()
 
-	events := []struct {
+	fixtures := []struct {
 		ts     time.Time
 		typ    string
 		actor  string
@@ -163,7 +165,7 @@ func TestPruner_Prune(t *testing.T) {
 		t.Fatalf("failed to create events file: %v", err)
 	}
 
-	for _, e := range events {
+	for _, e := range fixtures {
 		event := map[string]interface{}{
 			"ts":    e.ts.Format(time.RFC3339),
 			"type":  e.typ,
@@ -196,22 +198,13 @@ func TestPruner_Prune(t *testing.T) {
 		t.Errorf("expected 2 events retained, got %d", result.EventsRetained)
 	}
 
-	// Verify file was updated
-	data, err := os.ReadFile(eventsPath)
+	// Verify the store was updated (the flat file is migrated into it)
+	remaining, err := events.OpenStore(tmpDir).Query(events.Query{})
 	if err != nil {
-		t.Fatalf("failed to read events file: %v", err)
+		t.Fatalf("failed to query events: %v", err)
 	}
-
-	// Count lines
-	lines := 0
-	for _, b := range data {
-		if b == '\n' {
-			lines++
-		}
-	}
-
-	if lines != 2 {
-		t.Errorf("expected 2 lines in pruned file, got %d", lines)
+	if len(remaining) != 2 {
+		t.Errorf("expected 2 events after pruning, got %d", len(remaining))
 	}
 }
 
@@ -226,7 +219,7 @@ func TestGetStats(t *testing.T) {
 	eventsPath := filepath.Join(tmpDir, ".events.jsonl")
 	now := time.Now().UTC()
 
-	events := []struct {
+	fixtures := []struct {
 		ts  time.Time
 		typ string
 	}{
@@ -240,7 +233,7 @@ func TestGetStats(t *testing.T) {
 		t.Fatalf("failed to create events file: %v", err)
 	}
 
-	for _, e := range events {
+	for _, e := range fixtures {
 		event := map[string]interface{}{
 			"ts":   e.ts.Format(time.RFC3339),
 			"type": e.typ,
@@ -0,0 +1,168 @@
+package mail
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EnvelopeVersion is the current protocol envelope schema version.
+// Decoders accept envelopes up to this version; a newer version is an error
+// rather than a silent partial parse.
+const EnvelopeVersion = 1
+
+// EnvelopeField is the body line key that carries the JSON envelope.
+// The envelope is a single line so that the human-readable "Key: value" lines
+// above it stay intact for agents and legacy parsers.
+const EnvelopeField = "Gt-Envelope"
+
+var (
+	// ErrNoEnvelope is returned when a message body carries no envelope.
+	// Callers fall back to legacy subject/body parsing.
+	ErrNoEnvelope = errors.New("message has no protocol envelope")
+
+	// ErrUnsupportedEnvelopeVersion is returned for envelopes newer than
+	// EnvelopeVersion.
+	ErrUnsupportedEnvelopeVersion = errors.New("unsupported protocol envelope version")
+)
+
+// Envelope is a typed, versioned protocol payload carried in a message body.
+//
+// Protocol messages between agents (POLECAT_DONE, MERGE_READY, MERGED, ...)
+// used to be recognized by subject prefix and parsed by scraping "Key: value"
+// lines, so a reformatted body broke the pipeline silently. The envelope makes
+// the payload explicit: decoders prefer it and report malformed envelopes as
+// errors, falling back to legacy parsing only when no envelope is present.
+type Envelope struct {
+	// Version is the envelope schema version (EnvelopeVersion when sent).
+	Version int `json:"v"`
+
+	// ID uniquely identifies this protocol message.
+	ID string `json:"id"`
+
+	// CorrelationID ties together messages about the same unit of work,
+	// e.g. POLECAT_DONE → MERGE_READY → MERGED for one issue.
+	CorrelationID string `json:"correlation_id,omitempty"`
+
+	// Type is the protocol message type (e.g. "MERGE_READY").
+	Type string `json:"type"`
+
+	// SentAt is when the envelope was created.
+	SentAt time.Time `json:"sent_at"`
+
+	// Body is the type-specific payload.
+	Body json.RawMessage `json:"body"`
+}
+
+// NewEnvelope creates an envelope of the given type around body.
+func NewEnvelope(msgType, correlationID string, body interface{}) (*Envelope, error) {
+	if msgType == "" {
+		return nil, errors.New("envelope type is required")
+	}
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("encoding %s envelope body: %w", msgType, err)
+	}
+	return &Envelope{
+		Version:       EnvelopeVersion,
+		ID:            generateEnvelopeID(),
+		CorrelationID: correlationID,
+		Type:          msgType,
+		SentAt:        time.Now().UTC(),
+		Body:          raw,
+	}, nil
+}
+
+// AttachEnvelope appends env to the message body, replacing any envelope
+// already present.
+func (m *Message) AttachEnvelope(env *Envelope) error {
+	data, err := json.Marshal(env)
+	if err != nil {
+		return fmt.Errorf("encoding envelope: %w", err)
+	}
+	body := strings.TrimRight(stripEnvelope(m.Body), "\n")
+	if body != "" {
+		body += "\n\n"
+	}
+	m.Body = body + EnvelopeField + ": " + string(data) + "\n"
+	return nil
+}
+
+// Envelope returns the message's protocol envelope, or ErrNoEnvelope.
+func (m *Message) Envelope() (*Envelope, error) {
+	return ExtractEnvelope(m.Body)
+}
+
+// ExtractEnvelope finds and parses the envelope line in a message body.
+// It returns ErrNoEnvelope if there is none, and a descriptive error if the
+// envelope is malformed or from a newer schema version.
+func ExtractEnvelope(body string) (*Envelope, error) {
+	line, ok := findEnvelopeLine(body)
+	if !ok {
+		return nil, ErrNoEnvelope
+	}
+
+	var env Envelope
+	if err := json.Unmarshal([]byte(line), &env); err != nil {
+		return nil, fmt.Errorf("malformed protocol envelope: %w", err)
+	}
+	if env.Version < 1 || env.Version > EnvelopeVersion {
+		return nil, fmt.Errorf("%w: v%d (supported: v1-v%d)", ErrUnsupportedEnvelopeVersion, env.Version, EnvelopeVersion)
+	}
+	if env.Type == "" {
+		return nil, errors.New("malformed protocol envelope: missing type")
+	}
+	return &env, nil
+}
+
+// DecodeBody unmarshals the envelope body into v after checking that the
+// envelope has the expected type.
+func (e *Envelope) DecodeBody(wantType string, v interface{}) error {
+	if e.Type != wantType {
+		return fmt.Errorf("protocol envelope type %s, want %s", e.Type, wantType)
+	}
+	if len(e.Body) == 0 {
+		return fmt.Errorf("%s envelope has no body", e.Type)
+	}
+	if err := json.Unmarshal(e.Body, v); err != nil {
+		return fmt.Errorf("decoding %s envelope body: %w", e.Type, err)
+	}
+	return nil
+}
+
+func findEnvelopeLine(body string) (string, bool) {
+	prefix := EnvelopeField + ":"
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, prefix) {
+			return strings.TrimSpace(strings.TrimPrefix(line, prefix)), true
+		}
+	}
+	return "", false
+}
+
+func stripEnvelope(body string) string {
+	prefix := EnvelopeField + ":"
+	lines := strings.Split(body, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if !strings.HasPrefix(strings.TrimSpace(line), prefix) {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// generateEnvelopeID creates a random envelope ID.
+// Falls back to a time-based ID if crypto/rand fails (extremely rare).
+func generateEnvelopeID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("env-%x", time.Now().UnixNano())
+	}
+	return "env-" + hex.EncodeToString(b)
+}
@@ -0,0 +1,76 @@
+package mail
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEnvelope_RoundTrip(t *testing.T) {
+	msg := NewMessage("gastown/witness", "gastown/refinery", "MERGE_READY nux", "Branch: polecat/nux\nIssue: gt-abc\n")
+
+	env, err := NewEnvelope("MERGE_READY", "gt-abc", map[string]string{"branch": "polecat/nux"})
+	if err != nil {
+		t.Fatalf("NewEnvelope: %v", err)
+	}
+	if err := msg.AttachEnvelope(env); err != nil {
+		t.Fatalf("AttachEnvelope: %v", err)
+	}
+	if !strings.HasPrefix(msg.Body, "Branch: polecat/nux\nIssue: gt-abc\n\n"+EnvelopeField+": {") {
+		t.Errorf("legacy lines not preserved:\n%s", msg.Body)
+	}
+
+	got, err := msg.Envelope()
+	if err != nil {
+		t.Fatalf("Envelope: %v", err)
+	}
+	if got.Version != EnvelopeVersion || got.Type != "MERGE_READY" || got.CorrelationID != "gt-abc" || got.ID != env.ID {
+		t.Errorf("envelope = %+v", got)
+	}
+	var body map[string]string
+	if err := got.DecodeBody("MERGE_READY", &body); err != nil {
+		t.Fatalf("DecodeBody: %v", err)
+	}
+	if body["branch"] != "polecat/nux" {
+		t.Errorf("body = %v", body)
+	}
+	if err := got.DecodeBody("MERGED", &body); err == nil {
+		t.Error("DecodeBody with wrong type succeeded")
+	}
+
+	// Re-attaching replaces rather than duplicates.
+	if err := msg.AttachEnvelope(env); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(msg.Body, EnvelopeField+":"); n != 1 {
+		t.Errorf("found %d envelope lines, want 1", n)
+	}
+}
+
+func TestExtractEnvelope_Errors(t *testing.T) {
+	if _, err := ExtractEnvelope("Branch: x\nIssue: y"); !errors.Is(err, ErrNoEnvelope) {
+		t.Errorf("no envelope: err = %v, want ErrNoEnvelope", err)
+	}
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"malformed", EnvelopeField + `: {"v":1,"type":"MERGED",`},
+		{"missing type", EnvelopeField + `: {"v":1,"id":"env-1","body":{}}`},
+		{"future version", EnvelopeField + `: {"v":99,"type":"MERGED","body":{}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ExtractEnvelope("Branch: x\n" + tt.body)
+			if err == nil || errors.Is(err, ErrNoEnvelope) {
+				t.Errorf("err = %v, want a decode error", err)
+			}
+		})
+	}
+
+	_, err := ExtractEnvelope(EnvelopeField + `: {"v":2,"type":"MERGED","body":{}}`)
+	if !errors.Is(err, ErrUnsupportedEnvelopeVersion) {
+		t.Errorf("v2 err = %v, want ErrUnsupportedEnvelopeVersion", err)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/nudge"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -37,7 +38,7 @@ const DefaultIdleNotifyTimeout = 1 * time.Second
 type Router struct {
 	workDir  string // fallback directory to run bd commands in
 	townRoot string // town root directory (e.g., ~/gt)
-	tmux     *tmux.Tmux
+	tmux     session.Backend
 
 	// IdleNotifyTimeout controls how long to wait for a session to become
 	// idle before falling back to a queued nudge. Zero uses the default.
@@ -56,7 +57,7 @@ func NewRouter(workDir string) *Router {
 	return &Router{
 		workDir:  workDir,
 		townRoot: townRoot,
-		tmux:     tmux.NewTmux(),
+		tmux:     session.NewBackend(townRoot),
 	}
 }
 
@@ -65,7 +66,7 @@ func NewRouterWithTownRoot(workDir, townRoot string) *Router {
 	return &Router{
 		workDir:  workDir,
 		townRoot: townRoot,
-		tmux:     tmux.NewTmux(),
+		tmux:     session.NewBackend(townRoot),
 	}
 }
 
@@ -774,17 +775,17 @@ func (r *Router) Send(msg *Message) error {
 
 	// Check for queue address - single message for claiming
 	if isQueueAddress(msg.To) {
-		return r.sendToQueue(msg)
+		return r.counted(msg.To, r.sendToQueue(msg))
 	}
 
 	// Check for announce address - bulletin board (single copy, no claiming)
 	if isAnnounceAddress(msg.To) {
-		return r.sendToAnnounce(msg)
+		return r.counted(msg.To, r.sendToAnnounce(msg))
 	}
 
 	// Check for beads-native channel address - broadcast with retention
 	if isChannelAddress(msg.To) {
-		return r.sendToChannel(msg)
+		return r.counted(msg.To, r.sendToChannel(msg))
 	}
 
 	// Check for @group address - resolve and fan-out
@@ -793,7 +794,16 @@ func (r *Router) Send(msg *Message) error {
 	}
 
 	// Single recipient - send directly
-	return r.sendToSingle(msg)
+	return r.counted(msg.To, r.sendToSingle(msg))
+}
+
+// counted records a delivery to addr in the mail volume metric when err is
+// nil, and returns err. Fan-out addresses are counted per recipient.
+func (r *Router) counted(addr string, err error) error {
+	if err == nil {
+		_ = telemetry.Inc(r.townRoot, telemetry.MailMessagesTotal, telemetry.Labels{"to": addr})
+	}
+	return err
 }
 
 // sendToGroup resolves a @group address and sends individual messages to each member.
@@ -820,7 +830,7 @@ func (r *Router) sendToGroup(msg *Message) error {
 		msgCopy.To = recipient
 		msgCopy.ID = "" // Each fan-out copy gets its own ID from bd create
 
-		if err := r.sendToSingle(&msgCopy); err != nil {
+		if err := r.counted(recipient, r.sendToSingle(&msgCopy)); err != nil {
 			errs = append(errs, fmt.Sprintf("%s: %v", recipient, err))
 		}
 	}
@@ -40,6 +40,11 @@ func (m *Manager) SessionName() string {
 	return SessionName()
 }
 
+// backend returns the session backend for the mayor's town.
+func (m *Manager) backend() session.Backend {
+	return session.NewBackend(m.townRoot)
+}
+
 // mayorDir returns the working directory for the mayor.
 func (m *Manager) mayorDir() string {
 	return filepath.Join(m.townRoot, "mayor")
@@ -48,7 +53,7 @@ func (m *Manager) mayorDir() string {
 // Start starts the mayor session.
 // agentOverride optionally specifies a different agent alias to use.
 func (m *Manager) Start(agentOverride string) error {
-	t := tmux.NewTmux()
+	t := m.backend()
 	sessionID := m.SessionName()
 
 	// Kill any existing zombie session (tmux alive but agent dead).
@@ -95,7 +100,7 @@ func (m *Manager) Start(agentOverride string) error {
 
 // Stop stops the mayor session.
 func (m *Manager) Stop() error {
-	t := tmux.NewTmux()
+	t := m.backend()
 	sessionID := m.SessionName()
 
 	// Check if session exists
@@ -121,13 +126,13 @@ func (m *Manager) Stop() error {
 
 // IsRunning checks if the mayor session is active.
 func (m *Manager) IsRunning() (bool, error) {
-	t := tmux.NewTmux()
+	t := m.backend()
 	return t.HasSession(m.SessionName())
 }
 
 // Status returns information about the mayor session.
 func (m *Manager) Status() (*tmux.SessionInfo, error) {
-	t := tmux.NewTmux()
+	t := m.backend()
 	sessionID := m.SessionName()
 
 	running, err := t.HasSession(sessionID)
@@ -0,0 +1,87 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron_Errors(t *testing.T) {
+	bad := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"1,,2 * * * *",
+	}
+	for _, expr := range bad {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	base := time.Date(2026, time.March, 4, 10, 17, 30, 0, time.UTC) // Wednesday
+
+	tests := []struct {
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"* * * * *", base, time.Date(2026, 3, 4, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", base, time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)},
+		{"0 9 * * *", base, time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC)},
+		{"30 10 * * *", base, time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)},
+		{"0 9 * * mon-fri", time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC), time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", base, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", base, time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", base, time.Date(2026, 3, 4, 11, 0, 0, 0, time.UTC)},
+		{"@yearly", base, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * jun *", base, time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)},
+		{"5/20 * * * *", base, time.Date(2026, 3, 4, 10, 25, 0, 0, time.UTC)},
+		// Day-of-month OR day-of-week when both are restricted:
+		// the 13th, or any Friday. March 6 2026 is a Friday.
+		{"0 0 13 * fri", base, time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", base, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron: %v", err)
+			}
+			if got := s.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.from, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronSchedule_Matches(t *testing.T) {
+	s, err := ParseCron("0,30 9-17 * * 1-5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		at   time.Time
+		want bool
+	}{
+		{time.Date(2026, 3, 4, 9, 30, 0, 0, time.UTC), true},  // Wed 09:30
+		{time.Date(2026, 3, 4, 9, 15, 0, 0, time.UTC), false}, // wrong minute
+		{time.Date(2026, 3, 4, 18, 0, 0, 0, time.UTC), false}, // after hours
+		{time.Date(2026, 3, 7, 10, 0, 0, 0, time.UTC), false}, // Saturday
+		{time.Date(2026, 3, 9, 17, 0, 0, 0, time.UTC), true},  // Mon 17:00
+	}
+	for _, tt := range tests {
+		if got := s.Matches(tt.at); got != tt.want {
+			t.Errorf("Matches(%v) = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+}
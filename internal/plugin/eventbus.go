@@ -0,0 +1,112 @@
+package plugin
+
+import (
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Well-known events for event gates. Any event type written to the town's
+// event store (see internal/events) can also be used in a gate's On field.
+const (
+	// EventStartup fires once when the daemon starts.
+	EventStartup = "startup"
+
+	// EventConvoyClosed fires when a convoy is closed.
+	EventConvoyClosed = "convoy_closed"
+
+	// EventMerged fires when the refinery merges a branch.
+	EventMerged = "merged"
+)
+
+// FiredEvent is an event observed by the bus.
+type FiredEvent struct {
+	Name string
+	At   time.Time
+}
+
+// EventBus collects events for event gates between evaluations.
+//
+// Events come from two places: Publish, for events raised inside the
+// daemon (startup), and the town's event store, which other gt processes
+// append to. The store is tailed from the cursor kept in GateState. A
+// drained batch is only consumed once it is committed, so events whose
+// dispatch failed are seen again, even across daemon restarts.
+type EventBus struct {
+	store *events.Store
+
+	mu      sync.Mutex
+	pending []FiredEvent
+}
+
+// EventBatch is the result of a Drain.
+type EventBatch struct {
+	Events []FiredEvent
+
+	bus       *EventBus
+	published int
+	cursor    string
+}
+
+// NewEventBus creates an event bus for a town.
+func NewEventBus(townRoot string) *EventBus {
+	return &EventBus{store: events.OpenStore(townRoot)}
+}
+
+// Publish queues an in-process event for the next Drain.
+func (b *EventBus) Publish(event string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pending = append(b.pending, FiredEvent{Name: event, At: time.Now()})
+}
+
+// Drain returns all events since the last committed batch. Nothing is
+// consumed until the batch is committed. Store read errors are not fatal:
+// published events are still returned and the cursor is left unchanged.
+func (b *EventBus) Drain(state *GateState) EventBatch {
+	b.mu.Lock()
+	batch := EventBatch{
+		Events:    append([]FiredEvent(nil), b.pending...),
+		bus:       b,
+		published: len(b.pending),
+	}
+	b.mu.Unlock()
+
+	// A missing or unreadable cursor starts at the end of the store rather
+	// than replaying old events.
+	cursor, err := events.ParseCursor(state.EventsCursor)
+	if err != nil {
+		if end, err := b.store.End(); err == nil {
+			state.EventsCursor = end.String()
+		}
+		return batch
+	}
+
+	tail := b.store.Tail(cursor, events.Query{})
+	recs, err := tail.Next()
+	if err != nil {
+		return batch
+	}
+	batch.cursor = tail.Cursor().String()
+	for _, rec := range recs {
+		if rec.Type != "" {
+			batch.Events = append(batch.Events, FiredEvent{Name: rec.Type, At: rec.Time()})
+		}
+	}
+	return batch
+}
+
+// Commit consumes the batch: its published events are dropped and the store
+// cursor in state moves past its stored events.
+func (b EventBatch) Commit(state *GateState) {
+	if b.bus == nil {
+		return
+	}
+	b.bus.mu.Lock()
+	b.bus.pending = b.bus.pending[b.published:]
+	b.bus.mu.Unlock()
+	if b.cursor != "" {
+		state.EventsCursor = b.cursor
+	}
+}
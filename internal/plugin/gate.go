@@ -0,0 +1,372 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DefaultCooldown is used by cooldown gates that don't specify a duration.
+const DefaultCooldown = time.Hour
+
+// ConditionCheckTimeout bounds a condition gate's check command.
+const ConditionCheckTimeout = 30 * time.Second
+
+// DispatchInFlightTimeout is how long a dispatch with no recorded run keeps a
+// condition gate without a duration closed. A dog that dies without
+// recording its run holds the plugin back no longer than this.
+const DispatchInFlightTimeout = 30 * time.Minute
+
+// GateStateFile returns the path of the gate state shared by the daemon's
+// gate evaluator and gt dog dispatch.
+func GateStateFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "plugin-gates.json")
+}
+
+// GateState is the evaluator's memory between heartbeats.
+//
+// Run history lives on the ledger (see Recorder), but a dog records its run
+// only when it finishes. LastDispatched closes that gap so a plugin is not
+// dispatched again while its previous dispatch is still in flight.
+type GateState struct {
+	// LastDispatched maps plugin name to the last time it was dispatched,
+	// either by the daemon or by a manual gt dog dispatch.
+	LastDispatched map[string]time.Time `json:"last_dispatched,omitempty"`
+
+	// LastEvaluated is when the daemon last evaluated gates. Cron gates for
+	// plugins with no recorded run fire for schedule points after this time.
+	LastEvaluated time.Time `json:"last_evaluated,omitempty"`
+
+	// EventsCursor is how far into the town's event store the event bus has
+	// read (see events.Cursor). Empty means "not started": the bus begins at
+	// the end of the store rather than replaying old events.
+	EventsCursor string `json:"events_cursor,omitempty"`
+}
+
+// LoadGateState reads the gate state, returning an empty state if none exists.
+func LoadGateState(townRoot string) (*GateState, error) {
+	data, err := os.ReadFile(GateStateFile(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &GateState{LastDispatched: make(map[string]time.Time)}, nil
+		}
+		return nil, fmt.Errorf("reading gate state: %w", err)
+	}
+	var s GateState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing gate state: %w", err)
+	}
+	if s.LastDispatched == nil {
+		s.LastDispatched = make(map[string]time.Time)
+	}
+	return &s, nil
+}
+
+// SaveGateState writes the gate state atomically.
+func SaveGateState(townRoot string, s *GateState) error {
+	path := GateStateFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating daemon dir: %w", err)
+	}
+	return util.AtomicWriteJSON(path, s)
+}
+
+// UpdateGateState applies fn to the gate state under a cross-process lock.
+func UpdateGateState(townRoot string, fn func(*GateState)) error {
+	path := GateStateFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating daemon dir: %w", err)
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking gate state: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	s, err := LoadGateState(townRoot)
+	if err != nil {
+		return err
+	}
+	fn(s)
+	return SaveGateState(townRoot, s)
+}
+
+// MarkDispatched records that a plugin was just dispatched to a dog.
+func MarkDispatched(townRoot, pluginName string, at time.Time) error {
+	return UpdateGateState(townRoot, func(s *GateState) {
+		s.LastDispatched[pluginName] = at
+	})
+}
+
+// RunHistory provides the last recorded run of a plugin.
+// *Recorder implements it against the ledger.
+type RunHistory interface {
+	GetLastRun(pluginName string) (*PluginRunBead, error)
+}
+
+// GateDecision is the result of evaluating one plugin's gate.
+type GateDecision struct {
+	Plugin *Plugin  `json:"-"`
+	Name   string   `json:"name"`
+	Gate   GateType `json:"gate"`
+	Open   bool     `json:"open"`
+	Reason string   `json:"reason"`
+}
+
+// GateEvaluator decides which plugins are due to run.
+type GateEvaluator struct {
+	townRoot string
+	history  RunHistory
+	state    *GateState
+
+	// Now and RunCheck are replaceable for tests.
+	Now      func() time.Time
+	RunCheck func(ctx context.Context, dir, command string) error
+}
+
+// NewGateEvaluator creates an evaluator over the given run history and state.
+func NewGateEvaluator(townRoot string, history RunHistory, state *GateState) *GateEvaluator {
+	if state == nil {
+		state = &GateState{}
+	}
+	if state.LastDispatched == nil {
+		state.LastDispatched = make(map[string]time.Time)
+	}
+	return &GateEvaluator{
+		townRoot: townRoot,
+		history:  history,
+		state:    state,
+		Now:      time.Now,
+		RunCheck: runShellCheck,
+	}
+}
+
+// Evaluate checks every plugin's gate. events holds the events observed
+// since the last committed batch (see EventBus). An event gate opens only for
+// events newer than the plugin's last dispatch, so a batch replayed after a
+// failed dispatch does not fire the plugins that were already dispatched.
+// Manual and gateless plugins are never open.
+func (e *GateEvaluator) Evaluate(plugins []*Plugin, events []FiredEvent) []GateDecision {
+	fired := make(map[string]time.Time, len(events))
+	for _, ev := range events {
+		if last, ok := fired[ev.Name]; !ok || ev.At.After(last) {
+			fired[ev.Name] = ev.At
+		}
+	}
+
+	decisions := make([]GateDecision, 0, len(plugins))
+	for _, p := range plugins {
+		d := e.evaluate(p, fired)
+		d.Plugin, d.Name = p, p.Name
+		decisions = append(decisions, d)
+	}
+	return decisions
+}
+
+func (e *GateEvaluator) evaluate(p *Plugin, fired map[string]time.Time) GateDecision {
+	g := p.Gate
+	if g == nil || g.Type == GateManual || g.Type == "" {
+		return GateDecision{Gate: GateManual, Reason: "manual gate"}
+	}
+	d := GateDecision{Gate: g.Type}
+	now := e.Now()
+
+	switch g.Type {
+	case GateCooldown:
+		cooldown, err := gateDuration(g.Duration, DefaultCooldown)
+		if err != nil {
+			d.Reason = err.Error()
+			return d
+		}
+		last, err := e.lastRun(p.Name)
+		if err != nil {
+			d.Reason = fmt.Sprintf("checking run history: %v", err)
+			return d
+		}
+		if !last.IsZero() && now.Sub(last) < cooldown {
+			d.Reason = fmt.Sprintf("last run %s ago (cooldown %s)", now.Sub(last).Round(time.Second), cooldown)
+			return d
+		}
+		d.Open, d.Reason = true, "cooldown elapsed"
+
+	case GateCron:
+		sched, err := ParseCron(g.Schedule)
+		if err != nil {
+			d.Reason = err.Error()
+			return d
+		}
+		last, err := e.lastRun(p.Name)
+		if err != nil {
+			d.Reason = fmt.Sprintf("checking run history: %v", err)
+			return d
+		}
+		since := last
+		if since.IsZero() {
+			// Never run: only schedule points after the evaluator started count,
+			// so a fresh install doesn't fire every cron plugin at once.
+			since = e.state.LastEvaluated
+			if since.IsZero() {
+				since = now
+			}
+		}
+		next := sched.Next(since)
+		if next.IsZero() || next.After(now) {
+			d.Reason = fmt.Sprintf("next run at %s", formatNext(next))
+			return d
+		}
+		d.Open, d.Reason = true, fmt.Sprintf("scheduled for %s", next.Format(time.RFC3339))
+
+	case GateCondition:
+		if g.Check == "" {
+			d.Reason = "condition gate has no check command"
+			return d
+		}
+		// An optional duration rate-limits how often a true condition fires.
+		if g.Duration != "" {
+			cooldown, err := gateDuration(g.Duration, 0)
+			if err != nil {
+				d.Reason = err.Error()
+				return d
+			}
+			last, err := e.lastRun(p.Name)
+			if err != nil {
+				d.Reason = fmt.Sprintf("checking run history: %v", err)
+				return d
+			}
+			if !last.IsZero() && now.Sub(last) < cooldown {
+				d.Reason = fmt.Sprintf("last run %s ago (cooldown %s)", now.Sub(last).Round(time.Second), cooldown)
+				return d
+			}
+		} else {
+			// Without one, a true condition still waits for the previous
+			// dispatch to finish.
+			dispatched, err := e.inFlight(p.Name, now)
+			if err != nil {
+				d.Reason = fmt.Sprintf("checking run history: %v", err)
+				return d
+			}
+			if !dispatched.IsZero() {
+				d.Reason = fmt.Sprintf("dispatched %s ago, still in flight", now.Sub(dispatched).Round(time.Second))
+				return d
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), ConditionCheckTimeout)
+		err := e.RunCheck(ctx, p.Path, g.Check)
+		cancel()
+		if err != nil {
+			d.Reason = fmt.Sprintf("check failed: %v", err)
+			return d
+		}
+		d.Open, d.Reason = true, "check passed"
+
+	case GateEvent:
+		dispatched := e.state.LastDispatched[p.Name]
+		for _, ev := range gateEvents(g.On) {
+			if at, ok := fired[ev]; ok && (dispatched.IsZero() || at.After(dispatched)) {
+				d.Open, d.Reason = true, "event "+ev
+				return d
+			}
+		}
+		d.Reason = fmt.Sprintf("waiting for %s", g.On)
+
+	default:
+		d.Reason = fmt.Sprintf("unknown gate type %q", g.Type)
+	}
+	return d
+}
+
+// lastRun returns the later of the last recorded run and the last dispatch.
+func (e *GateEvaluator) lastRun(name string) (time.Time, error) {
+	last := e.state.LastDispatched[name]
+	if e.history == nil {
+		return last, nil
+	}
+	run, err := e.history.GetLastRun(name)
+	if err != nil {
+		return last, err
+	}
+	if run != nil && run.CreatedAt.After(last) {
+		last = run.CreatedAt
+	}
+	return last, nil
+}
+
+// inFlight returns when name was last dispatched if that dispatch has not
+// recorded a run yet and is younger than DispatchInFlightTimeout, or the
+// zero time.
+func (e *GateEvaluator) inFlight(name string, now time.Time) (time.Time, error) {
+	dispatched := e.state.LastDispatched[name]
+	if dispatched.IsZero() || now.Sub(dispatched) >= DispatchInFlightTimeout {
+		return time.Time{}, nil
+	}
+	if e.history != nil {
+		run, err := e.history.GetLastRun(name)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if run != nil && !run.CreatedAt.Before(dispatched) {
+			return time.Time{}, nil
+		}
+	}
+	return dispatched, nil
+}
+
+// gateEvents splits a gate's On field; several events may be comma-separated.
+func gateEvents(on string) []string {
+	var out []string
+	for _, ev := range strings.Split(on, ",") {
+		if ev = strings.TrimSpace(ev); ev != "" {
+			out = append(out, ev)
+		}
+	}
+	return out
+}
+
+func gateDuration(s string, def time.Duration) (time.Duration, error) {
+	if s == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid gate duration %q: %w", s, err)
+	}
+	return d, nil
+}
+
+func formatNext(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Format(time.RFC3339)
+}
+
+// runShellCheck runs a condition gate's check command with sh in dir.
+// A non-zero exit (or a timeout) keeps the gate closed.
+func runShellCheck(ctx context.Context, dir, command string) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command) //nolint:gosec // G204: check commands come from trusted plugin.md files
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", ConditionCheckTimeout)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return fmt.Errorf("exit %d", exitErr.ExitCode())
+	}
+	if msg := strings.TrimSpace(string(out)); msg != "" {
+		return fmt.Errorf("%w: %s", err, msg)
+	}
+	return err
+}
@@ -0,0 +1,227 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+type fakeHistory map[string]time.Time
+
+func (h fakeHistory) GetLastRun(name string) (*PluginRunBead, error) {
+	at, ok := h[name]
+	if !ok {
+		return nil, nil
+	}
+	return &PluginRunBead{CreatedAt: at}, nil
+}
+
+func testEvaluator(history RunHistory, state *GateState, now time.Time) *GateEvaluator {
+	e := NewGateEvaluator("", history, state)
+	e.Now = func() time.Time { return now }
+	return e
+}
+
+func gated(name string, g *Gate) *Plugin {
+	return &Plugin{Name: name, Gate: g}
+}
+
+func TestGateEvaluator_Cooldown(t *testing.T) {
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	history := fakeHistory{
+		"recent": now.Add(-10 * time.Minute),
+		"stale":  now.Add(-2 * time.Hour),
+	}
+	state := &GateState{LastDispatched: map[string]time.Time{
+		"inflight": now.Add(-5 * time.Minute),
+	}}
+	e := testEvaluator(history, state, now)
+
+	decisions := e.Evaluate([]*Plugin{
+		gated("recent", &Gate{Type: GateCooldown, Duration: "1h"}),
+		gated("stale", &Gate{Type: GateCooldown, Duration: "1h"}),
+		gated("never", &Gate{Type: GateCooldown, Duration: "1h"}),
+		gated("inflight", &Gate{Type: GateCooldown}),
+		gated("bad", &Gate{Type: GateCooldown, Duration: "soon"}),
+	}, nil)
+
+	want := map[string]bool{"recent": false, "stale": true, "never": true, "inflight": false, "bad": false}
+	for _, d := range decisions {
+		if d.Open != want[d.Name] {
+			t.Errorf("%s: open = %v (%s), want %v", d.Name, d.Open, d.Reason, want[d.Name])
+		}
+	}
+}
+
+func TestGateEvaluator_Cron(t *testing.T) {
+	now := time.Date(2026, 3, 4, 9, 5, 0, 0, time.UTC)
+	history := fakeHistory{
+		"ran-yesterday": now.Add(-24 * time.Hour),
+		"ran-today":     now.Add(-2 * time.Minute),
+	}
+	gate := &Gate{Type: GateCron, Schedule: "0 9 * * *"}
+
+	e := testEvaluator(history, &GateState{}, now)
+	decisions := e.Evaluate([]*Plugin{
+		gated("ran-yesterday", gate),
+		gated("ran-today", gate),
+		gated("never", gate),
+	}, nil)
+	want := map[string]bool{"ran-yesterday": true, "ran-today": false, "never": false}
+	for _, d := range decisions {
+		if d.Open != want[d.Name] {
+			t.Errorf("%s: open = %v (%s), want %v", d.Name, d.Open, d.Reason, want[d.Name])
+		}
+	}
+
+	// A plugin that has never run fires once the evaluator has been running
+	// across a schedule point.
+	e = testEvaluator(history, &GateState{LastEvaluated: now.Add(-10 * time.Minute)}, now)
+	if d := e.Evaluate([]*Plugin{gated("never", gate)}, nil)[0]; !d.Open {
+		t.Errorf("never: open = false (%s), want true after schedule point", d.Reason)
+	}
+}
+
+func TestGateEvaluator_Condition(t *testing.T) {
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	history := fakeHistory{
+		"limited":  now.Add(-time.Minute),
+		"finished": now.Add(-time.Minute),
+	}
+	state := &GateState{LastDispatched: map[string]time.Time{
+		"inflight": now.Add(-5 * time.Minute),
+		"finished": now.Add(-10 * time.Minute),
+		"stuck":    now.Add(-DispatchInFlightTimeout),
+	}}
+	e := testEvaluator(history, state, now)
+	var ran []string
+	e.RunCheck = func(_ context.Context, _, command string) error {
+		ran = append(ran, command)
+		if command == "false" {
+			return errors.New("exit 1")
+		}
+		return nil
+	}
+
+	decisions := e.Evaluate([]*Plugin{
+		gated("pass", &Gate{Type: GateCondition, Check: "true"}),
+		gated("fail", &Gate{Type: GateCondition, Check: "false"}),
+		gated("limited", &Gate{Type: GateCondition, Check: "true", Duration: "1h"}),
+		gated("empty", &Gate{Type: GateCondition}),
+		gated("inflight", &Gate{Type: GateCondition, Check: "true"}),
+		gated("finished", &Gate{Type: GateCondition, Check: "true"}),
+		gated("stuck", &Gate{Type: GateCondition, Check: "true"}),
+	}, nil)
+	want := map[string]bool{
+		"pass": true, "fail": false, "limited": false, "empty": false,
+		"inflight": false, "finished": true, "stuck": true,
+	}
+	for _, d := range decisions {
+		if d.Open != want[d.Name] {
+			t.Errorf("%s: open = %v (%s), want %v", d.Name, d.Open, d.Reason, want[d.Name])
+		}
+	}
+	if len(ran) != 4 {
+		t.Errorf("ran checks %v, want pass, fail, finished and stuck (limited is rate-limited, inflight in flight)", ran)
+	}
+}
+
+func TestGateEvaluator_EventAndManual(t *testing.T) {
+	now := time.Now()
+	fired := now.Add(-time.Minute)
+	state := &GateState{LastDispatched: map[string]time.Time{
+		// Dispatched after the event: a replayed batch must not fire it again.
+		"already-dispatched": now.Add(-30 * time.Second),
+		// Dispatched before the event: the new event fires it.
+		"dispatched-earlier": now.Add(-time.Hour),
+	}}
+	e := testEvaluator(nil, state, now)
+	decisions := e.Evaluate([]*Plugin{
+		gated("on-merge", &Gate{Type: GateEvent, On: "merged"}),
+		gated("on-either", &Gate{Type: GateEvent, On: "startup, convoy_closed"}),
+		gated("on-other", &Gate{Type: GateEvent, On: "session_death"}),
+		gated("already-dispatched", &Gate{Type: GateEvent, On: "merged"}),
+		gated("dispatched-earlier", &Gate{Type: GateEvent, On: "merged"}),
+		gated("manual", &Gate{Type: GateManual}),
+		gated("nogate", nil),
+	}, []FiredEvent{{Name: EventMerged, At: fired}, {Name: EventConvoyClosed, At: fired}})
+
+	want := map[string]bool{
+		"on-merge": true, "on-either": true, "on-other": false,
+		"already-dispatched": false, "dispatched-earlier": true,
+		"manual": false, "nogate": false,
+	}
+	for _, d := range decisions {
+		if d.Open != want[d.Name] {
+			t.Errorf("%s: open = %v (%s), want %v", d.Name, d.Open, d.Reason, want[d.Name])
+		}
+	}
+}
+
+func TestMarkDispatched(t *testing.T) {
+	town := t.TempDir()
+	at := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	if err := MarkDispatched(town, "rebuild-gt", at); err != nil {
+		t.Fatalf("MarkDispatched: %v", err)
+	}
+	s, err := LoadGateState(town)
+	if err != nil {
+		t.Fatalf("LoadGateState: %v", err)
+	}
+	if !s.LastDispatched["rebuild-gt"].Equal(at) {
+		t.Errorf("LastDispatched = %v, want %v", s.LastDispatched, at)
+	}
+}
+
+func TestEventBus_Drain(t *testing.T) {
+	town := t.TempDir()
+	store := events.OpenStore(town)
+	appendEvent := func(typ string) {
+		if err := store.Append(events.Event{Type: typ}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	names := func(b EventBatch) []string {
+		var out []string
+		for _, ev := range b.Events {
+			out = append(out, ev.Name)
+		}
+		return out
+	}
+	appendEvent(EventMerged)
+
+	bus := NewEventBus(town)
+	state := &GateState{}
+
+	// First drain starts at the end of the store: old events are not replayed.
+	bus.Publish(EventStartup)
+	batch := bus.Drain(state)
+	if got := names(batch); len(got) != 1 || got[0] != EventStartup {
+		t.Fatalf("first drain = %v, want [startup]", got)
+	}
+	batch.Commit(state)
+
+	// An uncommitted batch is seen again by the next drain.
+	appendEvent(EventConvoyClosed)
+	if got := names(bus.Drain(state)); len(got) != 1 || got[0] != EventConvoyClosed {
+		t.Fatalf("second drain = %v, want [convoy_closed]", got)
+	}
+	batch = bus.Drain(state)
+	if got := names(batch); len(got) != 1 || got[0] != EventConvoyClosed {
+		t.Fatalf("drain after failed dispatch = %v, want [convoy_closed]", got)
+	}
+	batch.Commit(state)
+	if got := names(bus.Drain(state)); len(got) != 0 {
+		t.Fatalf("drain after commit = %v, want none", got)
+	}
+
+	// The cursor survives a round trip through the saved state.
+	appendEvent(EventMerged)
+	restored := &GateState{EventsCursor: state.EventsCursor}
+	if got := names(NewEventBus(town).Drain(restored)); len(got) != 1 || got[0] != EventMerged {
+		t.Fatalf("drain after restart = %v, want [merged]", got)
+	}
+}
@@ -24,7 +24,6 @@ import (
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/session"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -120,11 +119,11 @@ type Manager struct {
 	git      *git.Git
 	beads    *beads.Beads
 	namePool *NamePool
-	tmux     *tmux.Tmux
+	tmux     session.Backend
 }
 
 // NewManager creates a new polecat manager.
-func NewManager(r *rig.Rig, g *git.Git, t *tmux.Tmux) *Manager {
+func NewManager(r *rig.Rig, g *git.Git, t session.Backend) *Manager {
 	// Use the resolved beads directory to find where bd commands should run.
 	// For tracked beads: rig/.beads/redirect -> mayor/rig/.beads, so use mayor/rig
 	// For local beads: rig/.beads is the database, so use rig root
@@ -156,7 +155,7 @@ func NewManager(r *rig.Rig, g *git.Git, t *tmux.Tmux) *Manager {
 		git:      g,
 		beads:    beads.NewWithBeadsDir(beadsPath, resolvedBeads),
 		namePool: pool,
-		tmux:     t,
+		tmux:     t, // nil disables session checks
 	}
 }
 
@@ -479,6 +478,7 @@ func (m *Manager) exists(name string) bool {
 type AddOptions struct {
 	HookBead   string // Bead ID to set as hook_bead at spawn time (atomic assignment)
 	BaseBranch string // Override base branch for worktree (e.g., "origin/integration/gt-epic")
+	Machine    string // Registered machine to place the polecat on (empty or "local" = this machine)
 }
 
 // Add creates a new polecat as a git worktree from the repo base.
@@ -613,6 +613,10 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 		return nil, ErrPolecatExists
 	}
 
+	if opts.Machine != "" && opts.Machine != "local" {
+		return m.addRemote(name, opts)
+	}
+
 	// New structure: polecats/<name>/<rigname>/ for LLM ergonomics
 	// The polecat's home dir is polecats/<name>/, worktree is polecats/<name>/<rigname>/
 	polecatDir := m.polecatDir(name)
@@ -825,6 +829,11 @@ func (m *Manager) RemoveWithOptions(name string, force, nuclear, selfNuke bool)
 	// Polecat dir is the parent directory (polecats/<name>/)
 	polecatDir := m.polecatDir(name)
 
+	placement, err := LoadPlacement(m.rig.Path, name)
+	if err != nil {
+		return err
+	}
+
 	// Check for uncommitted work unless bypassed
 	if !nuclear {
 		// ZFC #10: First try to read cleanup_status from agent bead
@@ -836,6 +845,12 @@ func (m *Manager) RemoveWithOptions(name string, force, nuclear, selfNuke bool)
 			if err := m.checkCleanupStatus(name, cleanupStatus, force); err != nil {
 				return err
 			}
+		} else if placement.IsRemote() {
+			// Remote worktree: the git fallback below can't see it, so only
+			// proceed on the polecat's self-reported status or with force.
+			if !force {
+				return fmt.Errorf("cannot verify git state of remote polecat %s on %s (no cleanup_status reported)\nUse --force to remove anyway", name, placement.Machine)
+			}
 		} else {
 			// Fallback path: Check git directly (for polecats that haven't reported yet)
 			polecatGit := git.NewGit(clonePath)
@@ -883,6 +898,10 @@ func (m *Manager) RemoveWithOptions(name string, force, nuclear, selfNuke bool)
 		}
 	}
 
+	if placement.IsRemote() {
+		return m.removeRemote(name, placement)
+	}
+
 	// Check if user's shell is cd'd into the worktree (prevents broken shell)
 	// This check runs unless selfNuke=true (polecat deleting its own worktree).
 	// When a polecat calls `gt done`, it's inside its worktree by design - the session
@@ -1368,7 +1387,7 @@ func (m *Manager) ReconcilePoolWith(namesWithDirs, namesWithSessions []string) {
 // isSessionProcessDead checks if a tmux session's pane process has exited.
 // Returns true only when we can confirm the process is dead, not on transient
 // tmux query failures (gt-kncti: permission denied false positives).
-func isSessionProcessDead(t *tmux.Tmux, sessionName string) bool {
+func isSessionProcessDead(t session.Backend, sessionName string) bool {
 	pidStr, err := t.GetPanePID(sessionName)
 	if err != nil {
 		// Tmux query failed — could be permission denied, server busy, etc.
@@ -1627,12 +1646,24 @@ func (m *Manager) loadFromBeads(name string) (*Polecat, error) {
 	// and old (polecats/<name>/) structures
 	clonePath := m.clonePath(name)
 
-	// Get actual branch from worktree (branches are now timestamped)
-	polecatGit := git.NewGit(clonePath)
-	branchName, err := polecatGit.CurrentBranch()
-	if err != nil {
-		// Fall back to old format if we can't read the branch
-		branchName = fmt.Sprintf("polecat/%s", name)
+	// Remote polecats record their worktree path and branch at spawn time,
+	// which avoids an ssh round-trip per polecat when listing.
+	placement, _ := LoadPlacement(m.rig.Path, name)
+	machine := ""
+	var branchName string
+	if placement.IsRemote() {
+		clonePath = placement.ClonePath
+		branchName = placement.Branch
+		machine = placement.Machine
+	} else {
+		// Get actual branch from worktree (branches are now timestamped)
+		polecatGit := git.NewGit(clonePath)
+		var err error
+		branchName, err = polecatGit.CurrentBranch()
+		if err != nil {
+			// Fall back to old format if we can't read the branch
+			branchName = fmt.Sprintf("polecat/%s", name)
+		}
 	}
 
 	// Check agent bead's hook_bead field first — this is the authoritative source
@@ -1648,6 +1679,7 @@ func (m *Manager) loadFromBeads(name string) (*Polecat, error) {
 			Rig:       m.rig.Name,
 			State:     StateWorking,
 			ClonePath: clonePath,
+			Machine:   machine,
 			Branch:    branchName,
 			Issue:     fields.HookBead,
 		}, nil
@@ -1665,6 +1697,7 @@ func (m *Manager) loadFromBeads(name string) (*Polecat, error) {
 			Rig:       m.rig.Name,
 			State:     StateWorking,
 			ClonePath: clonePath,
+			Machine:   machine,
 			Branch:    branchName,
 		}, nil
 	}
@@ -1679,6 +1712,13 @@ func (m *Manager) loadFromBeads(name string) (*Polecat, error) {
 	if issue != nil {
 		issueID = issue.ID
 		state = StateWorking
+	} else if placement.IsRemote() {
+		sessionName := session.PolecatSessionName(session.PrefixFor(m.rig.Name), name)
+		if conn, err := PlacementConnection(filepath.Dir(m.rig.Path), placement); err == nil {
+			if running, _ := conn.TmuxHasSession(sessionName); running {
+				state = StateWorking
+			}
+		}
 	} else if m.tmux != nil {
 		sessionName := session.PolecatSessionName(session.PrefixFor(m.rig.Name), name)
 		if running, _ := m.tmux.HasSession(sessionName); running {
@@ -1691,6 +1731,7 @@ func (m *Manager) loadFromBeads(name string) (*Polecat, error) {
 		Rig:       m.rig.Name,
 		State:     state,
 		ClonePath: clonePath,
+		Machine:   machine,
 		Branch:    branchName,
 		Issue:     issueID,
 	}, nil
@@ -1797,7 +1838,7 @@ func (m *Manager) DetectStalePolecats(threshold int) ([]*StalenessInfo, error) {
 		// Check for active tmux session
 		// Session name follows pattern: gt-<rig>-<polecat>
 		sessionName := session.PolecatSessionName(session.PrefixFor(m.rig.Name), p.Name)
-		info.HasActiveSession = checkTmuxSession(sessionName)
+		info.HasActiveSession = m.sessionExists(sessionName)
 
 		// Check how far behind main
 		polecatGit := git.NewGit(p.ClonePath)
@@ -1824,6 +1865,16 @@ func (m *Manager) DetectStalePolecats(threshold int) ([]*StalenessInfo, error) {
 	return results, nil
 }
 
+// sessionExists reports whether a polecat session exists, asking the PTY
+// supervisor when that backend is selected.
+func (m *Manager) sessionExists(sessionName string) bool {
+	if townRoot := filepath.Dir(m.rig.Path); session.UsePTY(townRoot) {
+		exists, _ := session.NewBackend(townRoot).HasSession(sessionName)
+		return exists
+	}
+	return checkTmuxSession(sessionName)
+}
+
 // checkTmuxSession checks if a tmux session exists.
 func checkTmuxSession(sessionName string) bool {
 	// Use has-session command which returns 0 if session exists
@@ -0,0 +1,296 @@
+package polecat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/connection"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// placementFile is the name of the marker written into polecats/<name>/
+// when the polecat's worktree and session live on another machine.
+// Its absence means the polecat is local.
+const placementFile = ".placement.json"
+
+// ErrRemoteUnsupported is returned for operations that only work on local polecats.
+var ErrRemoteUnsupported = errors.New("operation not supported for remote polecats")
+
+// Placement records where a remote polecat's worktree and session live.
+// The local polecats/<name>/ directory is kept as the polecat's home so that
+// name allocation, listing and nuking work the same way for every polecat;
+// only the worktree and the tmux session move to the remote machine.
+type Placement struct {
+	// Machine is the name of the machine in the town's MachineRegistry.
+	Machine string `json:"machine"`
+
+	// ClonePath is the worktree path on the remote machine.
+	ClonePath string `json:"clone_path"`
+
+	// Branch is the polecat branch checked out in the remote worktree.
+	Branch string `json:"branch"`
+
+	// CreatedAt is when the remote worktree was created.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IsRemote returns true if the placement targets a non-local machine.
+func (p *Placement) IsRemote() bool {
+	return p != nil && p.Machine != "" && p.Machine != "local"
+}
+
+// LoadPlacement reads the placement marker for a polecat.
+// Returns nil, nil if the polecat is local (no marker present).
+func LoadPlacement(rigPath, name string) (*Placement, error) {
+	data, err := os.ReadFile(filepath.Join(rigPath, "polecats", name, placementFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading placement: %w", err)
+	}
+	var p Placement
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parsing placement: %w", err)
+	}
+	return &p, nil
+}
+
+// savePlacement writes the placement marker for a polecat.
+func savePlacement(rigPath, name string, p *Placement) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling placement: %w", err)
+	}
+	return os.WriteFile(filepath.Join(rigPath, "polecats", name, placementFile), data, 0644)
+}
+
+// PlacementConnection opens a connection to the machine a placement targets.
+func PlacementConnection(townRoot string, p *Placement) (connection.Connection, error) {
+	reg, err := connection.LoadTownRegistry(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	return reg.Connection(p.Machine)
+}
+
+// RemotePolecatConnection returns the connection for a polecat that lives on
+// another machine, or nil if the polecat is local.
+func RemotePolecatConnection(townRoot, rigName, name string) (connection.Connection, *Placement, error) {
+	p, err := LoadPlacement(filepath.Join(townRoot, rigName), name)
+	if err != nil || !p.IsRemote() {
+		return nil, nil, err
+	}
+	conn, err := PlacementConnection(townRoot, p)
+	if err != nil {
+		return nil, p, fmt.Errorf("connecting to %s: %w", p.Machine, err)
+	}
+	return conn, p, nil
+}
+
+// remoteRigPath returns the rig directory on a remote machine.
+// Remote machines host a town with the same layout as the local one.
+func remoteRigPath(m *connection.Machine, rigName string) string {
+	return path.Join(m.TownPath, rigName)
+}
+
+// remoteGit runs git on the remote machine in dir.
+func remoteGit(conn connection.Connection, dir string, args ...string) (string, error) {
+	out, err := conn.ExecDir(dir, "git", args...)
+	if err != nil {
+		return "", fmt.Errorf("git %s on %s: %w (%s)", args[0], conn.Name(), err, trimOutput(out))
+	}
+	return string(out), nil
+}
+
+func trimOutput(out []byte) string {
+	s := string(out)
+	if len(s) > 500 {
+		s = s[len(s)-500:]
+	}
+	return s
+}
+
+// addRemote creates a polecat whose worktree lives on a registered machine.
+// The caller holds the per-polecat lock and has verified the name is free.
+//
+// The remote machine must have a town at Machine.TownPath containing this rig
+// (set up with gt install / gt rig add there) that shares the town's Dolt
+// server, so the remote polecat's bd and gt calls reach the same beads.
+func (m *Manager) addRemote(name string, opts AddOptions) (*Polecat, error) {
+	townRoot := filepath.Dir(m.rig.Path)
+	reg, err := connection.LoadTownRegistry(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("loading machine registry: %w", err)
+	}
+	machine, err := reg.Get(opts.Machine)
+	if err != nil {
+		return nil, err
+	}
+	if machine.TownPath == "" {
+		return nil, fmt.Errorf("machine %s has no town_path configured", machine.Name)
+	}
+	conn, err := reg.Connection(opts.Machine)
+	if err != nil {
+		return nil, err
+	}
+
+	rigPath := remoteRigPath(machine, m.rig.Name)
+	repoBase := path.Join(rigPath, ".repo.git")
+	if ok, err := conn.Exists(repoBase); err != nil {
+		return nil, fmt.Errorf("checking %s on %s: %w", repoBase, machine.Name, err)
+	} else if !ok {
+		return nil, fmt.Errorf("rig %s is not set up on %s (missing %s)\n\n"+
+			"Run 'gt rig add' in the town at %s on that machine first.",
+			m.rig.Name, machine.Name, repoBase, machine.TownPath)
+	}
+
+	polecatDir := m.polecatDir(name)
+	if err := os.MkdirAll(polecatDir, 0755); err != nil {
+		return nil, fmt.Errorf("creating polecat dir: %w", err)
+	}
+	_ = os.Remove(m.pendingPath(name))
+
+	remotePolecatDir := path.Join(rigPath, "polecats", name)
+	clonePath := path.Join(remotePolecatDir, m.rig.Name)
+	branchName := m.buildBranchName(name, opts.HookBead)
+
+	var worktreeCreated bool
+	cleanupOnError := func() {
+		_ = m.beads.ResetAgentBeadForReuse(m.agentBeadID(name), "spawn rollback")
+		if worktreeCreated {
+			_, _ = remoteGit(conn, repoBase, "worktree", "remove", "--force", clonePath)
+		}
+		_ = conn.RemoveAll(remotePolecatDir)
+		_ = os.RemoveAll(polecatDir)
+		m.namePool.Release(name)
+		_ = m.namePool.Save()
+	}
+
+	if _, err := remoteGit(conn, repoBase, "fetch", "origin"); err != nil {
+		style.PrintWarning("could not fetch origin on %s: %v", machine.Name, err)
+	}
+
+	startPoint := opts.BaseBranch
+	if startPoint == "" {
+		startPoint = "origin/" + m.rig.DefaultBranch()
+	}
+	if _, err := remoteGit(conn, repoBase, "rev-parse", "--verify", startPoint); err != nil {
+		cleanupOnError()
+		return nil, fmt.Errorf("start point %s not found on %s: %w", startPoint, machine.Name, err)
+	}
+
+	if err := conn.MkdirAll(remotePolecatDir, 0755); err != nil {
+		cleanupOnError()
+		return nil, fmt.Errorf("creating remote polecat dir: %w", err)
+	}
+	if _, err := remoteGit(conn, repoBase, "worktree", "add", "-b", branchName, clonePath, startPoint); err != nil {
+		cleanupOnError()
+		return nil, fmt.Errorf("creating remote worktree from %s: %w", startPoint, err)
+	}
+	worktreeCreated = true
+
+	// Submodules are initialized the same way WorktreeAddFromRef does locally.
+	if _, err := remoteGit(conn, clonePath, "submodule", "update", "--init", "--recursive"); err != nil {
+		style.PrintWarning("could not init submodules on %s: %v", machine.Name, err)
+	}
+
+	// Point the remote worktree at the remote rig's shared beads. The remote
+	// town mirrors the local layout, so the redirect computed for the local
+	// equivalent path is valid there too.
+	localEquivalent := filepath.Join(polecatDir, m.rig.Name)
+	if target, err := beads.ComputeRedirectTarget(townRoot, localEquivalent); err == nil {
+		beadsDir := path.Join(clonePath, ".beads")
+		if err := conn.MkdirAll(beadsDir, 0755); err == nil {
+			err = conn.WriteFile(path.Join(beadsDir, "redirect"), []byte(target+"\n"), 0644)
+		}
+		if err != nil {
+			style.PrintWarning("could not set up shared beads on %s: %v", machine.Name, err)
+		}
+	}
+
+	now := time.Now()
+	if err := savePlacement(m.rig.Path, name, &Placement{
+		Machine:   machine.Name,
+		ClonePath: clonePath,
+		Branch:    branchName,
+		CreatedAt: now,
+	}); err != nil {
+		cleanupOnError()
+		return nil, err
+	}
+
+	agentID := m.agentBeadID(name)
+	if err := m.createAgentBeadWithRetry(agentID, &beads.AgentFields{
+		RoleType:   "polecat",
+		Rig:        m.rig.Name,
+		AgentState: "spawning",
+		HookBead:   opts.HookBead,
+	}); err != nil {
+		cleanupOnError()
+		return nil, fmt.Errorf("agent bead required for polecat tracking: %w", err)
+	}
+
+	return &Polecat{
+		Name:      name,
+		Rig:       m.rig.Name,
+		State:     StateWorking,
+		ClonePath: clonePath,
+		Machine:   machine.Name,
+		Branch:    branchName,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// removeRemote tears down a remote polecat: its session, its remote worktree
+// and finally the local home directory. Safety checks (cleanup status, open
+// MRs) have already been done by RemoveWithOptions.
+func (m *Manager) removeRemote(name string, p *Placement) error {
+	townRoot := filepath.Dir(m.rig.Path)
+	conn, err := PlacementConnection(townRoot, p)
+	if err != nil {
+		return fmt.Errorf("connecting to %s: %w", p.Machine, err)
+	}
+
+	sessionName := session.PolecatSessionName(session.PrefixFor(m.rig.Name), name)
+	if running, err := conn.TmuxHasSession(sessionName); err != nil {
+		return fmt.Errorf("checking session on %s: %w", p.Machine, err)
+	} else if running {
+		if err := conn.TmuxKillSession(sessionName); err != nil {
+			return fmt.Errorf("killing session on %s: %w", p.Machine, err)
+		}
+	}
+
+	reg, err := connection.LoadTownRegistry(townRoot)
+	if err != nil {
+		return err
+	}
+	machine, err := reg.Get(p.Machine)
+	if err != nil {
+		return err
+	}
+	repoBase := path.Join(remoteRigPath(machine, m.rig.Name), ".repo.git")
+	if _, err := remoteGit(conn, repoBase, "worktree", "remove", "--force", p.ClonePath); err != nil {
+		style.PrintWarning("could not remove remote worktree (falling back to rm): %v", err)
+	}
+	if err := conn.RemoveAll(path.Dir(p.ClonePath)); err != nil {
+		return fmt.Errorf("removing remote polecat dir on %s: %w", p.Machine, err)
+	}
+	_, _ = remoteGit(conn, repoBase, "worktree", "prune")
+
+	if err := os.RemoveAll(m.polecatDir(name)); err != nil {
+		return fmt.Errorf("removing polecat dir: %w", err)
+	}
+
+	m.namePool.Release(name)
+	_ = m.namePool.Save()
+	return nil
+}
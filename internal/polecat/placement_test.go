@@ -0,0 +1,172 @@
+package polecat
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadPlacement_Absent(t *testing.T) {
+	rigPath := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(rigPath, "polecats", "toast"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := LoadPlacement(rigPath, "toast")
+	if err != nil {
+		t.Fatalf("LoadPlacement: %v", err)
+	}
+	if p != nil {
+		t.Errorf("expected nil placement for local polecat, got %+v", p)
+	}
+	if p.IsRemote() {
+		t.Error("nil placement should not be remote")
+	}
+}
+
+func TestPlacement_RoundTrip(t *testing.T) {
+	rigPath := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(rigPath, "polecats", "toast"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Placement{
+		Machine:   "gpu1",
+		ClonePath: "/srv/gt/myrig/polecats/toast/myrig",
+		Branch:    "polecat/toast/gt-abc",
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	if err := savePlacement(rigPath, "toast", want); err != nil {
+		t.Fatalf("savePlacement: %v", err)
+	}
+
+	got, err := LoadPlacement(rigPath, "toast")
+	if err != nil {
+		t.Fatalf("LoadPlacement: %v", err)
+	}
+	if got == nil {
+		t.Fatal("expected placement, got nil")
+	}
+	if got.Machine != want.Machine || got.ClonePath != want.ClonePath ||
+		got.Branch != want.Branch || !got.CreatedAt.Equal(want.CreatedAt) {
+		t.Errorf("round trip mismatch: got %+v, want %+v", got, want)
+	}
+	if !got.IsRemote() {
+		t.Error("placement on gpu1 should be remote")
+	}
+}
+
+func TestLoadPlacement_Corrupt(t *testing.T) {
+	rigPath := t.TempDir()
+	dir := filepath.Join(rigPath, "polecats", "toast")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, placementFile), []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadPlacement(rigPath, "toast"); err == nil {
+		t.Error("expected error for corrupt placement")
+	}
+}
+
+func TestPlacement_IsRemote(t *testing.T) {
+	tests := []struct {
+		machine string
+		want    bool
+	}{
+		{"", false},
+		{"local", false},
+		{"gpu1", true},
+	}
+	for _, tt := range tests {
+		p := &Placement{Machine: tt.machine}
+		if got := p.IsRemote(); got != tt.want {
+			t.Errorf("Placement{Machine: %q}.IsRemote() = %v, want %v", tt.machine, got, tt.want)
+		}
+	}
+}
+
+func TestRewriteTownRoot(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		local   string
+		remote  string
+		want    string
+	}{
+		{
+			name:    "rewrites all occurrences",
+			command: "export GT_TOWN_ROOT=/home/me/gt GT_POLECAT_PATH=/home/me/gt/rig/polecats/a/rig && claude",
+			local:   "/home/me/gt",
+			remote:  "/srv/gt",
+			want:    "export GT_TOWN_ROOT=/srv/gt GT_POLECAT_PATH=/srv/gt/rig/polecats/a/rig && claude",
+		},
+		{
+			name:    "trailing slashes ignored",
+			command: "cd /home/me/gt/rig",
+			local:   "/home/me/gt/",
+			remote:  "/srv/gt/",
+			want:    "cd /srv/gt/rig",
+		},
+		{
+			name:    "quoted assignment and flag values",
+			command: "export GT_ROOT='/home/me/gt' && claude --settings=\"/home/me/gt/rig/.claude\"",
+			local:   "/home/me/gt",
+			remote:  "/srv/gt",
+			want:    "export GT_ROOT='/srv/gt' && claude --settings=\"/srv/gt/rig/.claude\"",
+		},
+		{
+			name:    "sibling directory with shared prefix untouched",
+			command: "cd /home/me/gtx/rig && ls /home/me/gt/rig",
+			local:   "/home/me/gt",
+			remote:  "/srv/gt",
+			want:    "cd /home/me/gtx/rig && ls /srv/gt/rig",
+		},
+		{
+			name:    "beacon text untouched",
+			command: "claude 'Work in /home/me/gt/rig please' /home/me/gt",
+			local:   "/home/me/gt",
+			remote:  "/srv/gt",
+			want:    "claude 'Work in /home/me/gt/rig please' /srv/gt",
+		},
+		{
+			name:    "same root is a no-op",
+			command: "cd /srv/gt/rig",
+			local:   "/srv/gt",
+			remote:  "/srv/gt",
+			want:    "cd /srv/gt/rig",
+		},
+		{
+			name:    "empty remote is a no-op",
+			command: "cd /home/me/gt",
+			local:   "/home/me/gt",
+			remote:  "",
+			want:    "cd /home/me/gt",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rewriteTownRoot(tt.command, tt.local, tt.remote); got != tt.want {
+				t.Errorf("rewriteTownRoot() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemotePolecatConnection_Local(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "myrig", "polecats", "toast"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	conn, p, err := RemotePolecatConnection(townRoot, "myrig", "toast")
+	if err != nil {
+		t.Fatalf("RemotePolecatConnection: %v", err)
+	}
+	if conn != nil || p != nil {
+		t.Errorf("expected nil connection and placement for local polecat, got %v, %+v", conn, p)
+	}
+}
@@ -0,0 +1,328 @@
+package polecat
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/connection"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// remoteConn returns the connection for a polecat placed on another machine,
+// or nil if the polecat is local.
+func (m *SessionManager) remoteConn(polecat string) (connection.Connection, *Placement, error) {
+	return RemotePolecatConnection(filepath.Dir(m.rig.Path), m.rig.Name, polecat)
+}
+
+// startRemote starts a polecat session on the machine hosting its worktree.
+//
+// The startup command is built exactly as for a local session and then has
+// the local town root rewritten to the remote one: the remote town mirrors
+// the local layout, so every path in the command maps one-to-one.
+func (m *SessionManager) startRemote(polecat string, conn connection.Connection, p *Placement, opts SessionStartOptions) error {
+	sessionID := m.SessionName(polecat)
+
+	running, err := conn.TmuxHasSession(sessionID)
+	if err != nil {
+		return fmt.Errorf("checking session on %s: %w", p.Machine, err)
+	}
+	if running {
+		return fmt.Errorf("%w: %s on %s", ErrSessionRunning, sessionID, p.Machine)
+	}
+
+	townRoot := filepath.Dir(m.rig.Path)
+	reg, err := connection.LoadTownRegistry(townRoot)
+	if err != nil {
+		return err
+	}
+	machine, err := reg.Get(p.Machine)
+	if err != nil {
+		return err
+	}
+
+	workDir := opts.WorkDir
+	if workDir == "" {
+		workDir = p.ClonePath
+	}
+
+	if opts.Issue != "" {
+		// Beads are shared through the town's Dolt server, so the issue can be
+		// validated from the local rig.
+		if err := m.validateIssue(opts.Issue, m.rig.Path); err != nil {
+			return err
+		}
+	}
+
+	runtimeConfig := config.ResolveRoleAgentConfig("polecat", townRoot, m.rig.Path)
+	fallbackInfo := runtime.GetStartupFallbackInfo(runtimeConfig)
+
+	beacon := session.FormatStartupBeacon(session.BeaconConfig{
+		Recipient:               session.BeaconRecipient("polecat", polecat, m.rig.Name),
+		Sender:                  "witness",
+		Topic:                   "assigned",
+		MolID:                   opts.Issue,
+		IncludePrimeInstruction: fallbackInfo.IncludePrimeInBeacon,
+		ExcludeWorkInstructions: fallbackInfo.SendStartupNudge,
+	})
+
+	command := opts.Command
+	if command == "" {
+		command = config.BuildPolecatStartupCommand(m.rig.Name, polecat, m.rig.Path, beacon)
+	}
+	if runtimeConfig.Session != nil && runtimeConfig.Session.ConfigDirEnv != "" && opts.RuntimeConfigDir != "" {
+		command = config.PrependEnv(command, map[string]string{runtimeConfig.Session.ConfigDirEnv: opts.RuntimeConfigDir})
+	}
+	if opts.DoltBranch != "" {
+		command = config.PrependEnv(command, map[string]string{"BD_BRANCH": opts.DoltBranch})
+	}
+	env := map[string]string{
+		"BD_DOLT_AUTO_COMMIT": "off",
+		"GT_RIG":              m.rig.Name,
+		"GT_POLECAT":          polecat,
+		"GT_ROLE":             fmt.Sprintf("%s/polecats/%s", m.rig.Name, polecat),
+		"GT_POLECAT_PATH":     workDir,
+		"GT_TOWN_ROOT":        townRoot,
+		"GT_MACHINE":          p.Machine,
+	}
+	if p.Branch != "" {
+		env["GT_BRANCH"] = p.Branch
+	}
+	if runtimeConfig.ResolvedAgent != "" {
+		env["GT_AGENT"] = runtimeConfig.ResolvedAgent
+	}
+	if opts.Agent != "" {
+		env["GT_AGENT"] = opts.Agent
+	}
+	command = config.PrependEnv(command, env)
+	command = rewriteTownRoot(command, townRoot, machine.TownPath)
+
+	if err := conn.TmuxNewSession(sessionID, workDir); err != nil {
+		return fmt.Errorf("creating session on %s: %w", p.Machine, err)
+	}
+	if err := conn.TmuxSendKeys(sessionID, command); err != nil {
+		_ = conn.TmuxKillSession(sessionID)
+		return fmt.Errorf("starting agent on %s: %w", p.Machine, err)
+	}
+
+	if opts.Issue != "" {
+		agentID := fmt.Sprintf("%s/polecats/%s", m.rig.Name, polecat)
+		if err := m.hookIssue(opts.Issue, agentID, m.rig.Path); err != nil {
+			style.PrintWarning("could not hook issue %s: %v", opts.Issue, err)
+		}
+	}
+
+	runtime.SleepForReadyDelay(runtimeConfig)
+
+	if fallbackInfo.SendBeaconNudge {
+		debugSession("SendBeaconNudge (remote)", conn.TmuxSendKeys(sessionID, beacon))
+	}
+	if fallbackInfo.StartupNudgeDelayMs > 0 {
+		time.Sleep(time.Duration(fallbackInfo.StartupNudgeDelayMs) * time.Millisecond)
+	}
+	if fallbackInfo.SendStartupNudge {
+		debugSession("SendStartupNudge (remote)", conn.TmuxSendKeys(sessionID, runtime.StartupNudgeContent()))
+	}
+
+	running, err = conn.TmuxHasSession(sessionID)
+	if err != nil {
+		return fmt.Errorf("verifying session on %s: %w", p.Machine, err)
+	}
+	if !running {
+		return fmt.Errorf("session %s on %s died during startup (agent command may have failed)", sessionID, p.Machine)
+	}
+	return nil
+}
+
+// rewriteTownRoot maps local town paths in a command onto the remote town.
+//
+// Only shell words that are paths under the local town are rewritten: a bare
+// path, a NAME=path assignment or a --flag=path option, optionally quoted.
+// The local root must match up to a path boundary, so /home/u/gtx is left
+// alone for /home/u/gt, and free text such as the startup beacon is never
+// touched.
+func rewriteTownRoot(command, localTown, remoteTown string) string {
+	localTown = strings.TrimSuffix(localTown, "/")
+	remoteTown = strings.TrimSuffix(remoteTown, "/")
+	if localTown == "" || remoteTown == "" || localTown == remoteTown {
+		return command
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, w := range shellWords(command) {
+		start := w[0] + pathValueOffset(command[w[0]:w[1]])
+		if start < w[1] && (command[start] == '\'' || command[start] == '"') {
+			start++
+		}
+		rest := command[start:w[1]]
+		if !strings.HasPrefix(rest, localTown) || !isPathBoundary(rest[len(localTown):]) {
+			continue
+		}
+		b.WriteString(command[last:start])
+		b.WriteString(remoteTown)
+		last = start + len(localTown)
+	}
+	if last == 0 {
+		return command
+	}
+	b.WriteString(command[last:])
+	return b.String()
+}
+
+// shellWords returns the [start, end) offsets of the whitespace-separated
+// words in a shell command, keeping quoted spans inside a single word.
+func shellWords(command string) [][2]int {
+	var words [][2]int
+	start := -1
+	var quote byte
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && quote == '"' {
+				i++
+			}
+			continue
+		case c == ' ' || c == '\t' || c == '\n':
+			if start >= 0 {
+				words = append(words, [2]int{start, i})
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+		switch c {
+		case '\'', '"':
+			quote = c
+		case '\\':
+			i++
+		}
+	}
+	if start >= 0 {
+		words = append(words, [2]int{start, len(command)})
+	}
+	return words
+}
+
+// pathValueOffset returns where the value of a NAME=value or --flag=value
+// word starts, or 0 when the word is not of that form.
+func pathValueOffset(word string) int {
+	eq := strings.IndexByte(word, '=')
+	if eq <= 0 {
+		return 0
+	}
+	name := strings.TrimLeft(word[:eq], "-")
+	if name == "" {
+		return 0
+	}
+	for _, r := range name {
+		if r != '_' && r != '-' && (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return 0
+		}
+	}
+	return eq + 1
+}
+
+// isPathBoundary reports whether s, the text following a matched town root,
+// ends the path component: end of word, a separator, a closing quote or a
+// shell operator.
+func isPathBoundary(s string) bool {
+	if s == "" {
+		return true
+	}
+	switch s[0] {
+	case '/', '\'', '"', ';', '&', '|', ')':
+		return true
+	}
+	return false
+}
+
+// stopRemote terminates a polecat session on its remote machine.
+func (m *SessionManager) stopRemote(polecat string, conn connection.Connection, force bool) error {
+	sessionID := m.SessionName(polecat)
+	running, err := conn.TmuxHasSession(sessionID)
+	if err != nil {
+		return fmt.Errorf("checking session on %s: %w", conn.Name(), err)
+	}
+	if !running {
+		return ErrSessionNotFound
+	}
+
+	if !force {
+		// Interrupt the agent and give it the same grace period as locally.
+		if err := conn.TmuxSendKeysRaw(sessionID, "C-c"); err == nil {
+			deadline := time.Now().Add(constants.GracefulShutdownTimeout)
+			for time.Now().Before(deadline) {
+				if alive, _ := conn.TmuxHasSession(sessionID); !alive {
+					return nil
+				}
+				time.Sleep(500 * time.Millisecond)
+			}
+		}
+	}
+
+	if err := conn.TmuxKillSession(sessionID); err != nil {
+		return fmt.Errorf("killing session on %s: %w", conn.Name(), err)
+	}
+	return nil
+}
+
+// listRemote returns sessions for this rig's polecats that live on other machines.
+func (m *SessionManager) listRemote() []SessionInfo {
+	entries, err := filepath.Glob(filepath.Join(m.rig.Path, "polecats", "*", placementFile))
+	if err != nil {
+		return nil
+	}
+
+	// Group by machine so each machine is asked for its sessions only once.
+	townRoot := filepath.Dir(m.rig.Path)
+	byMachine := make(map[string][]string)
+	for _, entry := range entries {
+		name := filepath.Base(filepath.Dir(entry))
+		p, err := LoadPlacement(m.rig.Path, name)
+		if err != nil || !p.IsRemote() {
+			continue
+		}
+		byMachine[p.Machine] = append(byMachine[p.Machine], name)
+	}
+
+	var infos []SessionInfo
+	for machine, names := range byMachine {
+		conn, err := PlacementConnection(townRoot, &Placement{Machine: machine})
+		if err != nil {
+			continue
+		}
+		sessions, err := conn.TmuxListSessions()
+		if err != nil {
+			style.PrintWarning("could not list sessions on %s: %v", machine, err)
+			continue
+		}
+		live := make(map[string]bool, len(sessions))
+		for _, s := range sessions {
+			live[s] = true
+		}
+		for _, name := range names {
+			sessionID := m.SessionName(name)
+			if live[sessionID] {
+				infos = append(infos, SessionInfo{
+					Polecat:   name,
+					SessionID: sessionID,
+					Running:   true,
+					RigName:   m.rig.Name,
+					Machine:   machine,
+				})
+			}
+		}
+	}
+	return infos
+}
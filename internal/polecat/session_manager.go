@@ -40,12 +40,13 @@ var (
 
 // SessionManager handles polecat session lifecycle.
 type SessionManager struct {
-	tmux *tmux.Tmux
+	tmux session.Backend
 	rig  *rig.Rig
 }
 
 // NewSessionManager creates a new polecat session manager for a rig.
-func NewSessionManager(t *tmux.Tmux, r *rig.Rig) *SessionManager {
+// Callers pass the town's backend from session.NewBackend.
+func NewSessionManager(t session.Backend, r *rig.Rig) *SessionManager {
 	return &SessionManager{
 		tmux: t,
 		rig:  r,
@@ -94,6 +95,9 @@ type SessionInfo struct {
 	// RigName is the rig this session belongs to.
 	RigName string `json:"rig_name"`
 
+	// Machine is the registered machine running the session (empty = local).
+	Machine string `json:"machine,omitempty"`
+
 	// Attached indicates if someone is attached to the session.
 	Attached bool `json:"attached,omitempty"`
 
@@ -192,6 +196,14 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 		return fmt.Errorf("%w: %s", ErrPolecatNotFound, polecat)
 	}
 
+	conn, placement, err := m.remoteConn(polecat)
+	if err != nil {
+		return err
+	}
+	if conn != nil {
+		return m.startRemote(polecat, conn, placement, opts)
+	}
+
 	sessionID := m.SessionName(polecat)
 
 	// Check if session already exists.
@@ -442,6 +454,12 @@ func (m *SessionManager) isSessionStale(sessionID string) bool {
 
 // Stop terminates a polecat session.
 func (m *SessionManager) Stop(polecat string, force bool) error {
+	if conn, _, err := m.remoteConn(polecat); err != nil {
+		return err
+	} else if conn != nil {
+		return m.stopRemote(polecat, conn, force)
+	}
+
 	sessionID := m.SessionName(polecat)
 
 	running, err := m.tmux.HasSession(sessionID)
@@ -472,6 +490,13 @@ func (m *SessionManager) Stop(polecat string, force bool) error {
 // reporting zombie sessions (tmux alive but Claude dead) as "running".
 func (m *SessionManager) IsRunning(polecat string) (bool, error) {
 	sessionID := m.SessionName(polecat)
+	if conn, _, err := m.remoteConn(polecat); err != nil {
+		return false, err
+	} else if conn != nil {
+		// Process-level health isn't visible over the connection;
+		// session existence is the remote liveness signal.
+		return conn.TmuxHasSession(sessionID)
+	}
 	status := m.tmux.CheckSessionHealth(sessionID, 0)
 	return status == tmux.SessionHealthy, nil
 }
@@ -480,6 +505,22 @@ func (m *SessionManager) IsRunning(polecat string) (bool, error) {
 func (m *SessionManager) Status(polecat string) (*SessionInfo, error) {
 	sessionID := m.SessionName(polecat)
 
+	if conn, p, err := m.remoteConn(polecat); err != nil {
+		return nil, err
+	} else if conn != nil {
+		running, err := conn.TmuxHasSession(sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("checking session on %s: %w", p.Machine, err)
+		}
+		return &SessionInfo{
+			Polecat:   polecat,
+			SessionID: sessionID,
+			Running:   running,
+			RigName:   m.rig.Name,
+			Machine:   p.Machine,
+		}, nil
+	}
+
 	running, err := m.tmux.HasSession(sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("checking session: %w", err)
@@ -556,6 +597,8 @@ func (m *SessionManager) List() ([]SessionInfo, error) {
 		})
 	}
 
+	infos = append(infos, m.listRemote()...)
+
 	return infos, nil
 }
 
@@ -583,6 +626,13 @@ func (m *SessionManager) ListPolecats() ([]SessionInfo, error) {
 func (m *SessionManager) Attach(polecat string) error {
 	sessionID := m.SessionName(polecat)
 
+	if conn, p, err := m.remoteConn(polecat); err != nil {
+		return err
+	} else if conn != nil {
+		return fmt.Errorf("%w: %s runs on %s (attach with: ssh -t <host> tmux attach -t %s)",
+			ErrRemoteUnsupported, polecat, p.Machine, sessionID)
+	}
+
 	running, err := m.tmux.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
@@ -598,6 +648,19 @@ func (m *SessionManager) Attach(polecat string) error {
 func (m *SessionManager) Capture(polecat string, lines int) (string, error) {
 	sessionID := m.SessionName(polecat)
 
+	if conn, _, err := m.remoteConn(polecat); err != nil {
+		return "", err
+	} else if conn != nil {
+		running, err := conn.TmuxHasSession(sessionID)
+		if err != nil {
+			return "", fmt.Errorf("checking session: %w", err)
+		}
+		if !running {
+			return "", ErrSessionNotFound
+		}
+		return conn.TmuxCapturePane(sessionID, lines)
+	}
+
 	running, err := m.tmux.HasSession(sessionID)
 	if err != nil {
 		return "", fmt.Errorf("checking session: %w", err)
@@ -626,6 +689,19 @@ func (m *SessionManager) CaptureSession(sessionID string, lines int) (string, er
 func (m *SessionManager) Inject(polecat, message string) error {
 	sessionID := m.SessionName(polecat)
 
+	if conn, _, err := m.remoteConn(polecat); err != nil {
+		return err
+	} else if conn != nil {
+		running, err := conn.TmuxHasSession(sessionID)
+		if err != nil {
+			return fmt.Errorf("checking session: %w", err)
+		}
+		if !running {
+			return ErrSessionNotFound
+		}
+		return conn.TmuxSendKeys(sessionID, message)
+	}
+
 	running, err := m.tmux.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
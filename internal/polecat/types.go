@@ -68,8 +68,13 @@ type Polecat struct {
 	State State `json:"state"`
 
 	// ClonePath is the path to the polecat's clone of the rig.
+	// For remote polecats this is the path on Machine.
 	ClonePath string `json:"clone_path"`
 
+	// Machine is the registered machine hosting the worktree and session.
+	// Empty for polecats on the local machine.
+	Machine string `json:"machine,omitempty"`
+
 	// Branch is the current git branch.
 	Branch string `json:"branch"`
 
@@ -0,0 +1,170 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// AttachEnvelope adds a typed protocol envelope to msg. The correlation ID
+// should identify the unit of work the message is about: the source issue
+// for the merge pipeline, the convoy ID for convoy messages.
+func AttachEnvelope(msg *mail.Message, msgType MessageType, correlationID string, payload interface{}) error {
+	env, err := mail.NewEnvelope(string(msgType), correlationID, payload)
+	if err != nil {
+		return err
+	}
+	return msg.AttachEnvelope(env)
+}
+
+// attachEnvelope is AttachEnvelope for the package's own payload structs,
+// which always marshal. A failure leaves the legacy body in place.
+func attachEnvelope(msg *mail.Message, msgType MessageType, correlationID string, payload interface{}) {
+	_ = AttachEnvelope(msg, msgType, correlationID, payload)
+}
+
+// MessageTypeOf returns the protocol type of a message. The envelope type
+// wins; messages without an envelope fall back to the legacy subject prefix.
+// A malformed envelope yields "" so the message is not silently treated as
+// its subject suggests.
+func MessageTypeOf(msg *mail.Message) MessageType {
+	env, err := msg.Envelope()
+	if err == nil {
+		return MessageType(env.Type)
+	}
+	if errors.Is(err, mail.ErrNoEnvelope) {
+		return ParseMessageType(msg.Subject)
+	}
+	return ""
+}
+
+// decodeEnvelope decodes msg's envelope into v. It reports false (with no
+// error) when the message has no envelope, so the caller can use the legacy
+// body parser.
+func decodeEnvelope(msg *mail.Message, msgType MessageType, v interface{}) (bool, error) {
+	env, err := msg.Envelope()
+	if errors.Is(err, mail.ErrNoEnvelope) {
+		return false, nil
+	}
+	if err != nil {
+		return true, fmt.Errorf("%s: %w", msgType, err)
+	}
+	return true, env.DecodeBody(string(msgType), v)
+}
+
+// DecodeMergeReady decodes a MERGE_READY message, preferring the envelope.
+func DecodeMergeReady(msg *mail.Message) (*MergeReadyPayload, error) {
+	var p MergeReadyPayload
+	if ok, err := decodeEnvelope(msg, TypeMergeReady, &p); !ok {
+		return ParseMergeReadyPayload(msg.Body)
+	} else if err != nil {
+		return nil, err
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// DecodeMerged decodes a MERGED message, preferring the envelope.
+func DecodeMerged(msg *mail.Message) (*MergedPayload, error) {
+	var p MergedPayload
+	if ok, err := decodeEnvelope(msg, TypeMerged, &p); !ok {
+		return ParseMergedPayload(msg.Body)
+	} else if err != nil {
+		return nil, err
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// DecodeMergeFailed decodes a MERGE_FAILED message, preferring the envelope.
+func DecodeMergeFailed(msg *mail.Message) (*MergeFailedPayload, error) {
+	var p MergeFailedPayload
+	if ok, err := decodeEnvelope(msg, TypeMergeFailed, &p); !ok {
+		return ParseMergeFailedPayload(msg.Body)
+	} else if err != nil {
+		return nil, err
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// DecodeReworkRequest decodes a REWORK_REQUEST message, preferring the envelope.
+func DecodeReworkRequest(msg *mail.Message) (*ReworkRequestPayload, error) {
+	var p ReworkRequestPayload
+	if ok, err := decodeEnvelope(msg, TypeReworkRequest, &p); !ok {
+		return ParseReworkRequestPayload(msg.Body)
+	} else if err != nil {
+		return nil, err
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// DecodeConvoyNeedsFeeding decodes a CONVOY_NEEDS_FEEDING message, preferring
+// the envelope.
+func DecodeConvoyNeedsFeeding(msg *mail.Message) (*ConvoyNeedsFeedingPayload, error) {
+	var p ConvoyNeedsFeedingPayload
+	if ok, err := decodeEnvelope(msg, TypeConvoyNeedsFeeding, &p); !ok {
+		return ParseConvoyNeedsFeedingPayload(msg.Body)
+	} else if err != nil {
+		return nil, err
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// missingFields formats a required-field error, or returns nil if none are missing.
+func missingFields(msgType MessageType, fields map[string]string, order ...string) error {
+	var missing []string
+	for _, name := range order {
+		if fields[name] == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid %s payload: missing required fields: %s", msgType, strings.Join(missing, ", "))
+}
+
+func (p *MergeReadyPayload) validate() error {
+	return missingFields(TypeMergeReady, map[string]string{
+		"Branch": p.Branch, "Polecat": p.Polecat, "Rig": p.Rig,
+	}, "Branch", "Polecat", "Rig")
+}
+
+func (p *MergedPayload) validate() error {
+	return missingFields(TypeMerged, map[string]string{
+		"Branch": p.Branch, "Polecat": p.Polecat, "Rig": p.Rig,
+	}, "Branch", "Polecat", "Rig")
+}
+
+func (p *MergeFailedPayload) validate() error {
+	return missingFields(TypeMergeFailed, map[string]string{
+		"Branch": p.Branch, "Polecat": p.Polecat, "Rig": p.Rig,
+	}, "Branch", "Polecat", "Rig")
+}
+
+func (p *ReworkRequestPayload) validate() error {
+	return missingFields(TypeReworkRequest, map[string]string{
+		"Branch": p.Branch, "Polecat": p.Polecat, "Rig": p.Rig,
+	}, "Branch", "Polecat", "Rig")
+}
+
+func (p *ConvoyNeedsFeedingPayload) validate() error {
+	return missingFields(TypeConvoyNeedsFeeding, map[string]string{
+		"ConvoyID": p.ConvoyID, "Rig": p.Rig,
+	}, "ConvoyID", "Rig")
+}
@@ -0,0 +1,98 @@
+package protocol
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+func TestNewMessages_CarryEnvelope(t *testing.T) {
+	tests := []struct {
+		msg  *mail.Message
+		want MessageType
+		corr string
+	}{
+		{NewMergeReadyMessage("gastown", "nux", "polecat/nux", "gt-abc"), TypeMergeReady, "gt-abc"},
+		{NewMergedMessage("gastown", "nux", "polecat/nux", "gt-abc", "main", "abc123"), TypeMerged, "gt-abc"},
+		{NewMergeFailedMessage("gastown", "nux", "polecat/nux", "gt-abc", "main", "tests", "boom"), TypeMergeFailed, "gt-abc"},
+		{NewReworkRequestMessage("gastown", "nux", "polecat/nux", "gt-abc", "main", nil), TypeReworkRequest, "gt-abc"},
+		{NewConvoyNeedsFeedingMessage("gastown", "hq-cv-1", "gt-abc"), TypeConvoyNeedsFeeding, "hq-cv-1"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.want), func(t *testing.T) {
+			env, err := tt.msg.Envelope()
+			if err != nil {
+				t.Fatalf("Envelope: %v", err)
+			}
+			if MessageType(env.Type) != tt.want || env.CorrelationID != tt.corr {
+				t.Errorf("envelope type=%s corr=%s, want %s/%s", env.Type, env.CorrelationID, tt.want, tt.corr)
+			}
+			if got := MessageTypeOf(tt.msg); got != tt.want {
+				t.Errorf("MessageTypeOf = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeMerged_SurvivesReformattedBody(t *testing.T) {
+	msg := NewMergedMessage("gastown", "nux", "polecat/nux", "gt-abc", "main", "abc123")
+
+	// An agent rewrites the human-readable part and the subject.
+	env := msg.Body[strings.Index(msg.Body, mail.EnvelopeField):]
+	msg.Subject = "Re: your branch got merged!"
+	msg.Body = "Good news — nux's branch is in.\n\n" + env
+
+	p, err := DecodeMerged(msg)
+	if err != nil {
+		t.Fatalf("DecodeMerged: %v", err)
+	}
+	if p.Polecat != "nux" || p.Branch != "polecat/nux" || p.MergeCommit != "abc123" || p.TargetBranch != "main" {
+		t.Errorf("payload = %+v", p)
+	}
+	if MessageTypeOf(msg) != TypeMerged {
+		t.Errorf("MessageTypeOf = %q, want MERGED from envelope", MessageTypeOf(msg))
+	}
+}
+
+func TestDecode_LegacyFallback(t *testing.T) {
+	msg := &mail.Message{
+		Subject: "MERGE_READY nux",
+		Body:    "Branch: polecat/nux\nIssue: gt-abc\nPolecat: nux\nRig: gastown\nMR: gt-mr1\n",
+	}
+	p, err := DecodeMergeReady(msg)
+	if err != nil {
+		t.Fatalf("DecodeMergeReady: %v", err)
+	}
+	if p.Branch != "polecat/nux" || p.MR != "gt-mr1" {
+		t.Errorf("payload = %+v", p)
+	}
+	if MessageTypeOf(msg) != TypeMergeReady {
+		t.Errorf("MessageTypeOf = %q, want MERGE_READY from subject", MessageTypeOf(msg))
+	}
+}
+
+func TestDecode_BrokenEnvelopeIsAnError(t *testing.T) {
+	msg := &mail.Message{
+		Subject: "MERGED nux",
+		Body:    "Branch: polecat/nux\nPolecat: nux\nRig: gastown\n" + mail.EnvelopeField + `: {"v":1,"type":"MERGED","body":`,
+	}
+	if _, err := DecodeMerged(msg); err == nil {
+		t.Error("DecodeMerged succeeded on a truncated envelope")
+	}
+
+	registry := NewHandlerRegistry()
+	registry.Register(TypeMerged, func(*mail.Message) error { return nil })
+	handled, err := registry.ProcessProtocolMessage(msg)
+	if !handled || err == nil || errors.Is(err, ErrNoHandler) {
+		t.Errorf("ProcessProtocolMessage = (%v, %v), want (true, envelope error)", handled, err)
+	}
+}
+
+func TestDecode_EnvelopeTypeMismatch(t *testing.T) {
+	msg := NewMergeFailedMessage("gastown", "nux", "polecat/nux", "gt-abc", "main", "tests", "boom")
+	if _, err := DecodeMerged(msg); err == nil {
+		t.Error("DecodeMerged accepted a MERGE_FAILED envelope")
+	}
+}
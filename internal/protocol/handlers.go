@@ -36,7 +36,10 @@ func (r *HandlerRegistry) Register(msgType MessageType, handler Handler) {
 // Handle dispatches a message to the appropriate handler.
 // Returns an error if no handler is registered for the message type.
 func (r *HandlerRegistry) Handle(msg *mail.Message) error {
-	msgType := ParseMessageType(msg.Subject)
+	if _, err := msg.Envelope(); err != nil && !errors.Is(err, mail.ErrNoEnvelope) {
+		return err
+	}
+	msgType := MessageTypeOf(msg)
 	if msgType == "" {
 		return fmt.Errorf("unknown message type for subject: %s", msg.Subject)
 	}
@@ -51,7 +54,7 @@ func (r *HandlerRegistry) Handle(msg *mail.Message) error {
 
 // CanHandle returns true if a handler is registered for the message's type.
 func (r *HandlerRegistry) CanHandle(msg *mail.Message) bool {
-	msgType := ParseMessageType(msg.Subject)
+	msgType := MessageTypeOf(msg)
 	if msgType == "" {
 		return false
 	}
@@ -85,7 +88,7 @@ func WrapWitnessHandlers(h WitnessHandler) *HandlerRegistry {
 	registry := NewHandlerRegistry()
 
 	registry.Register(TypeMerged, func(msg *mail.Message) error {
-		payload, err := ParseMergedPayload(msg.Body)
+		payload, err := DecodeMerged(msg)
 		if err != nil {
 			return err
 		}
@@ -93,7 +96,7 @@ func WrapWitnessHandlers(h WitnessHandler) *HandlerRegistry {
 	})
 
 	registry.Register(TypeMergeFailed, func(msg *mail.Message) error {
-		payload, err := ParseMergeFailedPayload(msg.Body)
+		payload, err := DecodeMergeFailed(msg)
 		if err != nil {
 			return err
 		}
@@ -101,7 +104,7 @@ func WrapWitnessHandlers(h WitnessHandler) *HandlerRegistry {
 	})
 
 	registry.Register(TypeReworkRequest, func(msg *mail.Message) error {
-		payload, err := ParseReworkRequestPayload(msg.Body)
+		payload, err := DecodeReworkRequest(msg)
 		if err != nil {
 			return err
 		}
@@ -116,7 +119,7 @@ func WrapRefineryHandlers(h RefineryHandler) *HandlerRegistry {
 	registry := NewHandlerRegistry()
 
 	registry.Register(TypeMergeReady, func(msg *mail.Message) error {
-		payload, err := ParseMergeReadyPayload(msg.Body)
+		payload, err := DecodeMergeReady(msg)
 		if err != nil {
 			return err
 		}
@@ -126,13 +129,28 @@ func WrapRefineryHandlers(h RefineryHandler) *HandlerRegistry {
 	return registry
 }
 
+func isRegistryType(t MessageType) bool {
+	for _, rt := range registryTypes {
+		if t == rt {
+			return true
+		}
+	}
+	return false
+}
+
 // ProcessProtocolMessage processes a protocol message using the registry.
 // It returns (true, nil) if the message was handled successfully,
 // (true, error) if handling failed, (true, ErrNoHandler) if the message is
 // a recognized protocol message but no handler is registered, or
 // (false, nil) if not a protocol message.
 func (r *HandlerRegistry) ProcessProtocolMessage(msg *mail.Message) (bool, error) {
-	if !IsProtocolMessage(msg.Subject) {
+	// A broken envelope on a protocol-looking message is an error, not
+	// "not a protocol message": otherwise a mangled MERGED mail is dropped
+	// without anyone noticing.
+	if _, err := msg.Envelope(); err != nil && !errors.Is(err, mail.ErrNoEnvelope) {
+		return true, err
+	}
+	if !isRegistryType(MessageTypeOf(msg)) {
 		return false, nil
 	}
 
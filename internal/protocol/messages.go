@@ -30,6 +30,7 @@ func NewMergeReadyMessage(rig, polecat, branch, issue string) *mail.Message {
 	)
 	msg.Priority = mail.PriorityHigh
 	msg.Type = mail.TypeTask
+	attachEnvelope(msg, TypeMergeReady, issue, payload)
 
 	return msg
 }
@@ -41,6 +42,9 @@ func formatMergeReadyBody(p MergeReadyPayload) string {
 	sb.WriteString(fmt.Sprintf("Issue: %s\n", p.Issue))
 	sb.WriteString(fmt.Sprintf("Polecat: %s\n", p.Polecat))
 	sb.WriteString(fmt.Sprintf("Rig: %s\n", p.Rig))
+	if p.MR != "" {
+		sb.WriteString(fmt.Sprintf("MR: %s\n", p.MR))
+	}
 	if p.Verified != "" {
 		sb.WriteString(fmt.Sprintf("Verified: %s\n", p.Verified))
 	}
@@ -70,6 +74,7 @@ func NewMergedMessage(rig, polecat, branch, issue, targetBranch, mergeCommit str
 	)
 	msg.Priority = mail.PriorityHigh
 	msg.Type = mail.TypeNotification
+	attachEnvelope(msg, TypeMerged, issue, payload)
 
 	return msg
 }
@@ -113,6 +118,7 @@ func NewMergeFailedMessage(rig, polecat, branch, issue, targetBranch, failureTyp
 	)
 	msg.Priority = mail.PriorityHigh
 	msg.Type = mail.TypeTask
+	attachEnvelope(msg, TypeMergeFailed, issue, payload)
 
 	return msg
 }
@@ -155,6 +161,7 @@ func NewReworkRequestMessage(rig, polecat, branch, issue, targetBranch string, c
 	)
 	msg.Priority = mail.PriorityHigh
 	msg.Type = mail.TypeTask
+	attachEnvelope(msg, TypeReworkRequest, issue, payload)
 
 	return msg
 }
@@ -212,6 +219,7 @@ func NewConvoyNeedsFeedingMessage(rig, convoyID, sourceIssue string) *mail.Messa
 	)
 	msg.Priority = mail.PriorityHigh
 	msg.Type = mail.TypeTask
+	attachEnvelope(msg, TypeConvoyNeedsFeeding, convoyID, payload)
 
 	return msg
 }
@@ -241,17 +249,9 @@ func ParseConvoyNeedsFeedingPayload(body string) (*ConvoyNeedsFeedingPayload, er
 		}
 	}
 
-	var errs []string
-	if payload.ConvoyID == "" {
-		errs = append(errs, "ConvoyID")
-	}
-	if payload.Rig == "" {
-		errs = append(errs, "Rig")
-	}
-	if len(errs) > 0 {
-		return nil, fmt.Errorf("invalid CONVOY_NEEDS_FEEDING payload: missing required fields: %s", strings.Join(errs, ", "))
+	if err := payload.validate(); err != nil {
+		return nil, err
 	}
-
 	return payload, nil
 }
 
@@ -263,24 +263,14 @@ func ParseMergeReadyPayload(body string) (*MergeReadyPayload, error) {
 		Issue:     parseField(body, "Issue"),
 		Polecat:   parseField(body, "Polecat"),
 		Rig:       parseField(body, "Rig"),
+		MR:        parseField(body, "MR"),
 		Verified:  parseField(body, "Verified"),
 		Timestamp: time.Now(), // Use current time if not parseable
 	}
 
-	var errs []string
-	if payload.Branch == "" {
-		errs = append(errs, "Branch")
-	}
-	if payload.Polecat == "" {
-		errs = append(errs, "Polecat")
+	if err := payload.validate(); err != nil {
+		return nil, err
 	}
-	if payload.Rig == "" {
-		errs = append(errs, "Rig")
-	}
-	if len(errs) > 0 {
-		return nil, fmt.Errorf("invalid MERGE_READY payload: missing required fields: %s", strings.Join(errs, ", "))
-	}
-
 	return payload, nil
 }
 
@@ -303,20 +293,9 @@ func ParseMergedPayload(body string) (*MergedPayload, error) {
 		}
 	}
 
-	var errs []string
-	if payload.Branch == "" {
-		errs = append(errs, "Branch")
-	}
-	if payload.Polecat == "" {
-		errs = append(errs, "Polecat")
-	}
-	if payload.Rig == "" {
-		errs = append(errs, "Rig")
+	if err := payload.validate(); err != nil {
+		return nil, err
 	}
-	if len(errs) > 0 {
-		return nil, fmt.Errorf("invalid MERGED payload: missing required fields: %s", strings.Join(errs, ", "))
-	}
-
 	return payload, nil
 }
 
@@ -340,20 +319,9 @@ func ParseMergeFailedPayload(body string) (*MergeFailedPayload, error) {
 		}
 	}
 
-	var errs []string
-	if payload.Branch == "" {
-		errs = append(errs, "Branch")
-	}
-	if payload.Polecat == "" {
-		errs = append(errs, "Polecat")
-	}
-	if payload.Rig == "" {
-		errs = append(errs, "Rig")
-	}
-	if len(errs) > 0 {
-		return nil, fmt.Errorf("invalid MERGE_FAILED payload: missing required fields: %s", strings.Join(errs, ", "))
+	if err := payload.validate(); err != nil {
+		return nil, err
 	}
-
 	return payload, nil
 }
 
@@ -380,20 +348,9 @@ func ParseReworkRequestPayload(body string) (*ReworkRequestPayload, error) {
 		payload.ConflictFiles = strings.Split(files, ", ")
 	}
 
-	var errs []string
-	if payload.Branch == "" {
-		errs = append(errs, "Branch")
-	}
-	if payload.Polecat == "" {
-		errs = append(errs, "Polecat")
-	}
-	if payload.Rig == "" {
-		errs = append(errs, "Rig")
+	if err := payload.validate(); err != nil {
+		return nil, err
 	}
-	if len(errs) > 0 {
-		return nil, fmt.Errorf("invalid REWORK_REQUEST payload: missing required fields: %s", strings.Join(errs, ", "))
-	}
-
 	return payload, nil
 }
 
@@ -407,6 +364,7 @@ func ParsePolecatDonePayload(polecatName, body string) *PolecatDonePayload {
 		Issue:         parseField(body, "Issue"),
 		Branch:        parseField(body, "Branch"),
 		MR:            parseField(body, "MR"),
+		Gate:          parseField(body, "Gate"),
 		ConvoyID:      parseField(body, "ConvoyID"),
 		MergeStrategy: parseField(body, "MergeStrategy"),
 		Errors:        parseField(body, "Errors"),
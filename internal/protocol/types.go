@@ -46,21 +46,35 @@ const (
 	TypeConvoyNeedsFeeding MessageType = "CONVOY_NEEDS_FEEDING"
 )
 
+// Witness inbox message types. These only appear as envelope types: they are
+// classified by the witness (see witness.ClassifyMail), not dispatched through
+// a HandlerRegistry, and their legacy subjects use other formats
+// ("LIFECYCLE:Shutdown <name>", "HELP: <topic>", "🤝 HANDOFF: ...").
+const (
+	TypePolecatDone       MessageType = "POLECAT_DONE"
+	TypeLifecycleShutdown MessageType = "LIFECYCLE_SHUTDOWN"
+	TypeHelp              MessageType = "HELP"
+	TypeHandoff           MessageType = "HANDOFF"
+	TypeSwarmStart        MessageType = "SWARM_START"
+)
+
+// registryTypes are the message types dispatched through a HandlerRegistry.
+var registryTypes = []MessageType{
+	TypeMergeReady,
+	TypeMerged,
+	TypeMergeFailed,
+	TypeReworkRequest,
+	TypeConvoyNeedsFeeding,
+}
+
 // ParseMessageType extracts the protocol message type from a mail subject.
 // Returns empty string if subject doesn't match a known protocol type.
+// This is the legacy fallback; prefer MessageTypeOf, which reads the envelope.
 func ParseMessageType(subject string) MessageType {
 	subject = strings.TrimSpace(subject)
 
 	// Check each known prefix
-	prefixes := []MessageType{
-		TypeMergeReady,
-		TypeMerged,
-		TypeMergeFailed,
-		TypeReworkRequest,
-		TypeConvoyNeedsFeeding,
-	}
-
-	for _, prefix := range prefixes {
+	for _, prefix := range registryTypes {
 		p := string(prefix)
 		if subject == p || strings.HasPrefix(subject, p+" ") {
 			return prefix
@@ -85,6 +99,9 @@ type MergeReadyPayload struct {
 	// Rig is the rig name containing the polecat.
 	Rig string `json:"rig"`
 
+	// MR is the merge-request bead ID, if known.
+	MR string `json:"mr,omitempty"`
+
 	// Verified contains verification notes.
 	Verified string `json:"verified,omitempty"`
 
@@ -192,6 +209,9 @@ type PolecatDonePayload struct {
 	// MR is the merge-request bead ID (empty for owned+direct convoys).
 	MR string `json:"mr,omitempty"`
 
+	// Gate is the gate ID when ExitType is PHASE_COMPLETE.
+	Gate string `json:"gate,omitempty"`
+
 	// ConvoyID is the tracking convoy ID (if any).
 	ConvoyID string `json:"convoy_id,omitempty"`
 
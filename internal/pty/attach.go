@@ -0,0 +1,75 @@
+package pty
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// DetachKey detaches an attached terminal (Ctrl-]).
+const DetachKey = 0x1d
+
+// Attach connects the current terminal to a session: scrollback is replayed,
+// then input and output are streamed until the session ends or DetachKey is
+// pressed.
+func (c *Client) Attach(session string) error {
+	fd := int(os.Stdin.Fd())
+	var rows, cols uint16
+	if w, h, err := term.GetSize(fd); err == nil {
+		rows, cols = uint16(h), uint16(w)
+	}
+
+	conn, r, err := c.dialAttach(session, rows, cols)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	if term.IsTerminal(fd) {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("setting raw mode: %w", err)
+		}
+		defer func() { _ = term.Restore(fd, state) }()
+	}
+
+	detached := make(chan struct{})
+	go func() {
+		defer close(detached)
+		buf := make([]byte, 1024)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if n > 0 {
+				if i := bytes.IndexByte(buf[:n], DetachKey); i >= 0 {
+					_, _ = conn.Write(buf[:i])
+					return
+				}
+				if _, err := conn.Write(buf[:n]); err != nil {
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	copied := make(chan struct{})
+	go func() {
+		defer close(copied)
+		_, _ = io.Copy(os.Stdout, r)
+	}()
+
+	select {
+	case <-detached:
+		_ = conn.Close()
+		<-copied
+		_, _ = fmt.Fprintf(os.Stdout, "\r\n[detached from %s]\r\n", session)
+	case <-copied:
+		_, _ = fmt.Fprintf(os.Stdout, "\r\n[session %s ended]\r\n", session)
+	}
+	return nil
+}
@@ -0,0 +1,496 @@
+package pty
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Client talks to a Supervisor over its unix socket. Its methods mirror
+// *tmux.Tmux so it can stand in for tmux as a session backend.
+type Client struct {
+	socket  string
+	timeout time.Duration
+}
+
+// NewClient returns a client for the supervisor listening on socket.
+func NewClient(socket string) *Client {
+	return &Client{socket: socket, timeout: 30 * time.Second}
+}
+
+func (c *Client) call(req request) (*response, error) {
+	conn, err := net.DialTimeout("unix", c.socket, 2*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("%w (%s): is the daemon running?", ErrNoServer, c.socket)
+	}
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetDeadline(time.Now().Add(c.timeout))
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(append(data, '\n')); err != nil {
+		return nil, err
+	}
+	var resp response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("reading pty supervisor response: %w", err)
+	}
+	return &resp, resp.err()
+}
+
+func (r *response) err() error {
+	switch {
+	case r.Error == "":
+		return nil
+	case r.NotFound:
+		return ErrSessionNotFound
+	case r.Exists:
+		return ErrSessionExists
+	default:
+		return errors.New(r.Error)
+	}
+}
+
+// IsAvailable reports whether the supervisor is reachable.
+func (c *Client) IsAvailable() bool {
+	conn, err := net.DialTimeout("unix", c.socket, time.Second)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
+
+// NewSessionWithCommand starts command in a new session.
+func (c *Client) NewSessionWithCommand(name, workDir, command string) error {
+	_, err := c.call(request{Op: "new", Session: name, WorkDir: workDir, Command: command})
+	return err
+}
+
+// NewSessionWithCommandAndEnv starts command with extra environment variables.
+func (c *Client) NewSessionWithCommandAndEnv(name, workDir, command string, env map[string]string) error {
+	_, err := c.call(request{Op: "new", Session: name, WorkDir: workDir, Command: command, Env: env})
+	return err
+}
+
+// EnsureSessionFresh starts an interactive shell session, replacing an
+// existing session whose agent has died.
+func (c *Client) EnsureSessionFresh(name, workDir string) error {
+	if running, _ := c.HasSession(name); running {
+		if c.IsAgentAlive(name) {
+			return nil
+		}
+		if err := c.KillSessionWithProcesses(name); err != nil {
+			return fmt.Errorf("killing zombie session: %w", err)
+		}
+	}
+	return c.NewSessionWithCommand(name, workDir, `exec "${SHELL:-sh}" -i`)
+}
+
+// KillSessionWithProcesses kills the session's process group and removes it.
+func (c *Client) KillSessionWithProcesses(name string) error {
+	_, err := c.call(request{Op: "kill", Session: name})
+	return err
+}
+
+// KillSession kills the session. The supervisor always kills the whole
+// process group, so this is KillSessionWithProcesses.
+func (c *Client) KillSession(name string) error {
+	return c.KillSessionWithProcesses(name)
+}
+
+// KillSessionWithProcessesExcluding kills the session. The supervisor
+// signals the whole process group at once, so there is no window in which
+// an excluded caller could finish first; like tmux's final kill-session,
+// a caller inside the session goes down with it.
+func (c *Client) KillSessionWithProcessesExcluding(name string, _ []string) error {
+	return c.KillSessionWithProcesses(name)
+}
+
+// CleanupOrphanedSessions kills Gas Town sessions whose agent has exited
+// and returns how many were removed.
+func (c *Client) CleanupOrphanedSessions(isGTSession func(string) bool) (int, error) {
+	sessions, err := c.ListSessions()
+	if err != nil {
+		return 0, fmt.Errorf("listing sessions: %w", err)
+	}
+	cleaned := 0
+	for _, sess := range sessions {
+		if !isGTSession(sess) || c.IsAgentAlive(sess) {
+			continue
+		}
+		if err := c.KillSessionWithProcesses(sess); err != nil {
+			fmt.Printf("  warning: failed to kill orphaned session %s: %v\n", sess, err)
+			continue
+		}
+		cleaned++
+	}
+	return cleaned, nil
+}
+
+// HasSession reports whether a session exists. An unreachable supervisor
+// means no sessions, like a missing tmux server.
+func (c *Client) HasSession(name string) (bool, error) {
+	resp, err := c.call(request{Op: "has", Session: name})
+	if errors.Is(err, ErrNoServer) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return resp.Bool, nil
+}
+
+// ListSessions returns all session names.
+func (c *Client) ListSessions() ([]string, error) {
+	resp, err := c.call(request{Op: "list"})
+	if errors.Is(err, ErrNoServer) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp.Sessions, nil
+}
+
+// SendKeysRaw sends a tmux key name or literal text without Enter.
+func (c *Client) SendKeysRaw(session, keys string) error {
+	_, err := c.call(request{Op: "keys", Session: session, Value: keys})
+	return err
+}
+
+// SendKeys sends text followed by Enter after the default debounce.
+func (c *Client) SendKeys(session, keys string) error {
+	return c.SendKeysDebounced(session, keys, constants.DefaultDebounceMs)
+}
+
+// SendKeysDebounced sends text, waits debounceMs, then sends Enter.
+func (c *Client) SendKeysDebounced(session, keys string, debounceMs int) error {
+	if _, err := c.call(request{Op: "write", Session: session, Value: keys}); err != nil {
+		return err
+	}
+	if debounceMs > 0 {
+		time.Sleep(time.Duration(debounceMs) * time.Millisecond)
+	}
+	return c.SendKeysRaw(session, "Enter")
+}
+
+// NudgeSession types message into the session and submits it.
+func (c *Client) NudgeSession(session, message string) error {
+	_, err := c.call(request{Op: "nudge", Session: session, Value: message})
+	return err
+}
+
+// CapturePane returns the last lines of the session's scrollback as text.
+func (c *Client) CapturePane(session string, lines int) (string, error) {
+	resp, err := c.call(request{Op: "capture", Session: session, Lines: lines})
+	if err != nil {
+		return "", err
+	}
+	return resp.Value, nil
+}
+
+// CapturePaneLines returns the last lines of scrollback as a slice.
+func (c *Client) CapturePaneLines(session string, lines int) ([]string, error) {
+	out, err := c.CapturePane(session, lines)
+	if err != nil || out == "" {
+		return nil, err
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// Info returns the supervisor's view of a session.
+func (c *Client) Info(session string) (*Info, error) {
+	resp, err := c.call(request{Op: "info", Session: session})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Info, nil
+}
+
+// GetSessionActivity returns the time of the session's last output.
+func (c *Client) GetSessionActivity(session string) (time.Time, error) {
+	info, err := c.Info(session)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.Activity, nil
+}
+
+// GetSessionInfo returns session details in the tmux backend's format.
+func (c *Client) GetSessionInfo(name string) (*tmux.SessionInfo, error) {
+	info, err := c.Info(name)
+	if err != nil {
+		return nil, err
+	}
+	return &tmux.SessionInfo{
+		Name:     info.Name,
+		Windows:  1,
+		Created:  info.Created.Format("2006-01-02 15:04:05"),
+		Attached: info.Attached > 0,
+		Activity: strconv.FormatInt(info.Activity.Unix(), 10),
+	}, nil
+}
+
+// GetSessionCreatedUnix returns when the session was created, in Unix seconds.
+func (c *Client) GetSessionCreatedUnix(session string) (int64, error) {
+	info, err := c.Info(session)
+	if err != nil {
+		return 0, err
+	}
+	return info.Created.Unix(), nil
+}
+
+// GetPaneID returns the session's pane target. A PTY session has a single
+// pane, addressed by the session name.
+func (c *Client) GetPaneID(session string) (string, error) {
+	if _, err := c.Info(session); err != nil {
+		return "", err
+	}
+	return session, nil
+}
+
+// GetPaneWorkDir returns the directory the session was started in.
+func (c *Client) GetPaneWorkDir(session string) (string, error) {
+	info, err := c.Info(session)
+	if err != nil {
+		return "", err
+	}
+	return info.WorkDir, nil
+}
+
+// GetPanePID returns the PID of the session's command.
+func (c *Client) GetPanePID(session string) (string, error) {
+	info, err := c.Info(session)
+	if err != nil {
+		return "", err
+	}
+	if info.Exited {
+		return "", nil
+	}
+	return formatPID(info.PID), nil
+}
+
+// SetEnvironment records an environment variable on the session.
+func (c *Client) SetEnvironment(session, key, value string) error {
+	_, err := c.call(request{Op: "setenv", Session: session, Key: key, Value: value})
+	return err
+}
+
+// GetEnvironment returns a session environment variable.
+func (c *Client) GetEnvironment(session, key string) (string, error) {
+	resp, err := c.call(request{Op: "getenv", Session: session, Key: key})
+	if err != nil {
+		return "", err
+	}
+	return resp.Value, nil
+}
+
+// IsAgentAlive reports whether the agent process is running in the session.
+func (c *Client) IsAgentAlive(session string) bool {
+	resp, err := c.call(request{Op: "alive", Session: session})
+	return err == nil && resp.Bool
+}
+
+// IsAgentRunning reports whether the session runs one of
+// expectedPaneCommands, or any live command when none are given.
+func (c *Client) IsAgentRunning(session string, expectedPaneCommands ...string) bool {
+	if len(expectedPaneCommands) == 0 {
+		return c.IsAgentAlive(session)
+	}
+	resp, err := c.call(request{Op: "command", Session: session})
+	return err == nil && resp.Value != "" && contains(expectedPaneCommands, resp.Value)
+}
+
+// SetRemainOnExit keeps the session after its command exits.
+func (c *Client) SetRemainOnExit(session string, on bool) error {
+	_, err := c.call(request{Op: "remain-on-exit", Session: session, On: on})
+	return err
+}
+
+// SetAutoRespawnHook restarts the session's command whenever it exits.
+func (c *Client) SetAutoRespawnHook(session string) error {
+	_, err := c.call(request{Op: "auto-respawn", Session: session, On: true})
+	return err
+}
+
+// SetPaneDiedHook is a no-op: the supervisor logs exits itself.
+func (c *Client) SetPaneDiedHook(session, agentID string) error {
+	return nil
+}
+
+// ConfigureGasTownSession is a no-op: headless sessions have no status bar
+// or key bindings to theme.
+func (c *Client) ConfigureGasTownSession(session string, theme tmux.Theme, rig, worker, role string) error {
+	return nil
+}
+
+// NudgePane nudges the session behind a pane target from GetPaneID.
+func (c *Client) NudgePane(pane, message string) error {
+	return c.NudgeSession(pane, message)
+}
+
+// SendNotificationBanner is a no-op: nobody watches a headless session's
+// terminal, and agents get the nudge that follows.
+func (c *Client) SendNotificationBanner(session, from, subject string) error {
+	return nil
+}
+
+// SetCrewCycleBindings is a no-op: headless sessions have no key bindings.
+func (c *Client) SetCrewCycleBindings(session string) error {
+	return nil
+}
+
+// WaitForCommand polls until the session is running something other than
+// one of excludeCommands.
+func (c *Client) WaitForCommand(session string, excludeCommands []string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		resp, err := c.call(request{Op: "command", Session: session})
+		if err == nil && resp.Value != "" && !contains(excludeCommands, resp.Value) {
+			return nil
+		}
+		time.Sleep(constants.PollInterval)
+	}
+	return fmt.Errorf("timeout waiting for command (still running excluded command)")
+}
+
+// WaitForRuntimeReady polls the scrollback for the runtime's ready prompt,
+// falling back to its fixed ready delay.
+func (c *Client) WaitForRuntimeReady(session string, rc *config.RuntimeConfig, timeout time.Duration) error {
+	if rc == nil || rc.Tmux == nil {
+		return nil
+	}
+	if rc.Tmux.ReadyPromptPrefix == "" {
+		if rc.Tmux.ReadyDelayMs <= 0 {
+			return nil
+		}
+		delay := time.Duration(rc.Tmux.ReadyDelayMs) * time.Millisecond
+		if delay > timeout {
+			delay = timeout
+		}
+		time.Sleep(delay)
+		return nil
+	}
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		lines, err := c.CapturePaneLines(session, 10)
+		if err == nil {
+			for _, line := range lines {
+				if tmux.MatchesPromptPrefix(line, rc.Tmux.ReadyPromptPrefix) {
+					return nil
+				}
+			}
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	return fmt.Errorf("timeout waiting for runtime prompt")
+}
+
+// WaitForIdle polls until the agent shows its idle prompt. It returns the
+// session error if the session is gone, or tmux.ErrIdleTimeout.
+func (c *Client) WaitForIdle(session string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		lines, err := c.CapturePaneLines(session, 5)
+		if errors.Is(err, ErrSessionNotFound) || errors.Is(err, ErrNoServer) {
+			return err
+		}
+		for _, line := range lines {
+			if tmux.MatchesPromptPrefix(line, tmux.DefaultReadyPromptPrefix) {
+				return nil
+			}
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	return tmux.ErrIdleTimeout
+}
+
+// AcceptBypassPermissionsWarning dismisses Claude's bypass permissions
+// dialog if it is showing.
+func (c *Client) AcceptBypassPermissionsWarning(session string) error {
+	time.Sleep(1 * time.Second)
+	content, err := c.CapturePane(session, 30)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(content, "Bypass Permissions mode") {
+		return nil
+	}
+	if err := c.SendKeysRaw(session, "Down"); err != nil {
+		return err
+	}
+	time.Sleep(200 * time.Millisecond)
+	return c.SendKeysRaw(session, "Enter")
+}
+
+// CheckSessionHealth classifies the session the same way as the tmux backend.
+func (c *Client) CheckSessionHealth(session string, maxInactivity time.Duration) tmux.ZombieStatus {
+	info, err := c.Info(session)
+	if err != nil {
+		return tmux.SessionDead
+	}
+	if info.Exited || !c.IsAgentAlive(session) {
+		return tmux.AgentDead
+	}
+	if maxInactivity > 0 && !info.Activity.IsZero() && time.Since(info.Activity) > maxInactivity {
+		return tmux.AgentHung
+	}
+	return tmux.SessionHealthy
+}
+
+// AttachSession attaches the current terminal to the session.
+func (c *Client) AttachSession(session string) error {
+	return c.Attach(session)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// dialAttach opens an attach stream and returns the connection positioned
+// after the handshake.
+func (c *Client) dialAttach(session string, rows, cols uint16) (net.Conn, *bufio.Reader, error) {
+	conn, err := net.DialTimeout("unix", c.socket, 2*time.Second)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w (%s): is the daemon running?", ErrNoServer, c.socket)
+	}
+	data, _ := json.Marshal(request{Op: "attach", Session: session, Rows: rows, Cols: cols})
+	if _, err := conn.Write(append(data, '\n')); err != nil {
+		_ = conn.Close()
+		return nil, nil, err
+	}
+	r := bufio.NewReader(conn)
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		_ = conn.Close()
+		return nil, nil, fmt.Errorf("reading attach response: %w", err)
+	}
+	var resp response
+	if err := json.Unmarshal(line, &resp); err != nil {
+		_ = conn.Close()
+		return nil, nil, err
+	}
+	if err := resp.err(); err != nil {
+		_ = conn.Close()
+		return nil, nil, err
+	}
+	return conn, r, nil
+}
@@ -0,0 +1,63 @@
+package pty
+
+import (
+	"strings"
+)
+
+// namedKeys maps tmux key names to the bytes a terminal sends for them.
+var namedKeys = map[string]string{
+	"Enter":    "\r",
+	"Tab":      "\t",
+	"BTab":     "\x1b[Z",
+	"Escape":   "\x1b",
+	"Space":    " ",
+	"BSpace":   "\x7f",
+	"Up":       "\x1b[A",
+	"Down":     "\x1b[B",
+	"Right":    "\x1b[C",
+	"Left":     "\x1b[D",
+	"Home":     "\x1b[H",
+	"End":      "\x1b[F",
+	"PageUp":   "\x1b[5~",
+	"PPage":    "\x1b[5~",
+	"PageDown": "\x1b[6~",
+	"NPage":    "\x1b[6~",
+	"DC":       "\x1b[3~",
+	"Delete":   "\x1b[3~",
+}
+
+// KeyBytes translates a tmux send-keys argument into terminal input. Key
+// names ("Enter", "Down", "C-c", "M-x") become their escape sequences;
+// anything else is sent literally, as tmux does.
+func KeyBytes(key string) []byte {
+	if seq, ok := namedKeys[key]; ok {
+		return []byte(seq)
+	}
+	if len(key) == 3 && strings.HasPrefix(key, "C-") {
+		if seq, ok := ctrl(key[2]); ok {
+			return []byte(seq)
+		}
+	}
+	if len(key) == 2 && key[0] == '^' {
+		if seq, ok := ctrl(key[1]); ok {
+			return []byte(seq)
+		}
+	}
+	if len(key) == 3 && strings.HasPrefix(key, "M-") {
+		return []byte("\x1b" + key[2:])
+	}
+	return []byte(key)
+}
+
+// ctrl returns the control character for c (C-c is 0x03, C-[ is ESC).
+func ctrl(c byte) (string, bool) {
+	switch {
+	case c >= 'a' && c <= 'z':
+		return string(rune(c - 'a' + 1)), true
+	case c >= '@' && c <= '_':
+		return string(rune(c - '@')), true
+	case c == '?':
+		return "\x7f", true
+	}
+	return "", false
+}
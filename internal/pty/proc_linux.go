@@ -0,0 +1,71 @@
+//go:build linux
+
+package pty
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// processName returns the executable name of pid, preferring the kernel comm
+// and falling back to the base of /proc/<pid>/exe.
+func processName(pid int) string {
+	if data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "comm")); err == nil {
+		if name := strings.TrimSpace(string(data)); name != "" {
+			return name
+		}
+	}
+	if exe, err := os.Readlink(filepath.Join("/proc", strconv.Itoa(pid), "exe")); err == nil {
+		return filepath.Base(exe)
+	}
+	return ""
+}
+
+// processNames returns every name pid is known by: its comm and the base
+// name of its executable. They differ when a runtime rewrites its title
+// (e.g., Claude showing its version as argv[0]).
+func processNames(pid int) []string {
+	var names []string
+	if data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "comm")); err == nil {
+		names = append(names, strings.TrimSpace(string(data)))
+	}
+	if exe, err := os.Readlink(filepath.Join("/proc", strconv.Itoa(pid), "exe")); err == nil {
+		names = append(names, filepath.Base(exe))
+	}
+	return names
+}
+
+// childPIDs returns the direct children of pid in ascending PID order.
+func childPIDs(pid int) []int {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil
+	}
+	var children []int
+	for _, e := range entries {
+		child, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join("/proc", e.Name(), "stat"))
+		if err != nil {
+			continue
+		}
+		// Fields after the parenthesised comm: state ppid ...
+		stat := string(data)
+		end := strings.LastIndexByte(stat, ')')
+		if end < 0 {
+			continue
+		}
+		fields := strings.Fields(stat[end+1:])
+		if len(fields) < 2 {
+			continue
+		}
+		if ppid, err := strconv.Atoi(fields[1]); err == nil && ppid == pid {
+			children = append(children, child)
+		}
+	}
+	return children
+}
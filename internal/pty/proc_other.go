@@ -0,0 +1,9 @@
+//go:build !linux
+
+package pty
+
+func processName(pid int) string { return "" }
+
+func processNames(pid int) []string { return nil }
+
+func childPIDs(pid int) []int { return nil }
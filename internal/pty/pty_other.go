@@ -0,0 +1,25 @@
+//go:build !linux
+
+package pty
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+func start(cmd *exec.Cmd, rows, cols uint16) (*os.File, error) {
+	return nil, ErrUnsupported
+}
+
+func setSize(f *os.File, rows, cols uint16) error {
+	return ErrUnsupported
+}
+
+func killGroup(pid int, sig syscall.Signal) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Kill()
+}
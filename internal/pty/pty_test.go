@@ -0,0 +1,64 @@
+package pty
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRing(t *testing.T) {
+	r := NewRing(8)
+	_, _ = r.Write([]byte("abc"))
+	if got := string(r.Bytes()); got != "abc" {
+		t.Errorf("Bytes = %q, want abc", got)
+	}
+	_, _ = r.Write([]byte("defgh"))
+	if got := string(r.Bytes()); got != "abcdefgh" || r.Len() != 8 {
+		t.Errorf("full ring = %q (len %d)", got, r.Len())
+	}
+	_, _ = r.Write([]byte("ij"))
+	if got := string(r.Bytes()); got != "cdefghij" {
+		t.Errorf("wrapped ring = %q, want cdefghij", got)
+	}
+	_, _ = r.Write([]byte("0123456789"))
+	if got := string(r.Bytes()); got != "23456789" {
+		t.Errorf("oversized write = %q, want 23456789", got)
+	}
+}
+
+func TestKeyBytes(t *testing.T) {
+	tests := map[string]string{
+		"C-c":    "\x03",
+		"C-u":    "\x15",
+		"^D":     "\x04",
+		"Enter":  "\r",
+		"Escape": "\x1b",
+		"Down":   "\x1b[B",
+		"M-x":    "\x1bx",
+		"hello":  "hello",
+		"C-":     "C-",
+	}
+	for in, want := range tests {
+		if got := string(KeyBytes(in)); got != want {
+			t.Errorf("KeyBytes(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	raw := "\x1b[1;32mgreen\x1b[0m line\r\n" +
+		"\x1b]0;title\x07progress 10%\rprogress 100%\r\n" +
+		"\x1b(Bcharset\x1b[?25h\n" +
+		"\n\n"
+	got := PlainText([]byte(raw))
+	want := []string{"green line", "progress 100%", "charset", "", ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PlainText = %q, want %q", got, want)
+	}
+	if last := lastLines(got, 2); !reflect.DeepEqual(last, []string{"progress 100%", "charset"}) {
+		t.Errorf("lastLines = %q", last)
+	}
+	if all := strings.Join(lastLines(got, 0), "|"); all != "green line|progress 100%|charset" {
+		t.Errorf("lastLines(0) = %q", all)
+	}
+}
@@ -0,0 +1,60 @@
+package pty
+
+// DefaultScrollback is the number of bytes of output kept per session.
+const DefaultScrollback = 1 << 20
+
+// Ring is a fixed-size byte buffer that keeps the most recent output of a
+// session. Writes never fail; once full, the oldest bytes are overwritten.
+// Ring is not safe for concurrent use.
+type Ring struct {
+	buf  []byte
+	next int  // index of the next write
+	full bool // buf has wrapped at least once
+}
+
+// NewRing returns a ring holding up to size bytes.
+func NewRing(size int) *Ring {
+	if size <= 0 {
+		size = DefaultScrollback
+	}
+	return &Ring{buf: make([]byte, size)}
+}
+
+// Write appends p, discarding the oldest bytes if needed.
+func (r *Ring) Write(p []byte) (int, error) {
+	n := len(p)
+	if n >= len(r.buf) {
+		copy(r.buf, p[n-len(r.buf):])
+		r.next = 0
+		r.full = true
+		return n, nil
+	}
+	c := copy(r.buf[r.next:], p)
+	if c < n {
+		copy(r.buf, p[c:])
+		r.full = true
+	}
+	r.next = (r.next + n) % len(r.buf)
+	if r.next == 0 {
+		r.full = true
+	}
+	return n, nil
+}
+
+// Bytes returns a copy of the buffered output, oldest first.
+func (r *Ring) Bytes() []byte {
+	if !r.full {
+		return append([]byte(nil), r.buf[:r.next]...)
+	}
+	out := make([]byte, 0, len(r.buf))
+	out = append(out, r.buf[r.next:]...)
+	return append(out, r.buf[:r.next]...)
+}
+
+// Len returns the number of buffered bytes.
+func (r *Ring) Len() int {
+	if r.full {
+		return len(r.buf)
+	}
+	return r.next
+}
@@ -0,0 +1,216 @@
+package pty
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SocketPath returns the path of the supervisor socket for a town. It lives
+// in its own directory so Listen can make that directory private.
+func SocketPath(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "pty", "pty.sock")
+}
+
+// request is one client call. Each connection carries a single request; an
+// "attach" request turns the connection into a raw terminal stream.
+type request struct {
+	Op      string            `json:"op"`
+	Session string            `json:"session,omitempty"`
+	WorkDir string            `json:"work_dir,omitempty"`
+	Command string            `json:"command,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	Key     string            `json:"key,omitempty"`
+	Value   string            `json:"value,omitempty"`
+	Lines   int               `json:"lines,omitempty"`
+	On      bool              `json:"on,omitempty"`
+	Rows    uint16            `json:"rows,omitempty"`
+	Cols    uint16            `json:"cols,omitempty"`
+}
+
+type response struct {
+	Error    string    `json:"error,omitempty"`
+	NotFound bool      `json:"not_found,omitempty"`
+	Exists   bool      `json:"exists,omitempty"`
+	Value    string    `json:"value,omitempty"`
+	Bool     bool      `json:"bool,omitempty"`
+	Sessions []string  `json:"sessions,omitempty"`
+	Info     *Info     `json:"info,omitempty"`
+	Time     time.Time `json:"time,omitempty"`
+}
+
+// Server exposes a Supervisor on a unix socket.
+type Server struct {
+	sup      *Supervisor
+	listener net.Listener
+}
+
+// Listen creates the socket at path, replacing a stale one, and returns a
+// server for sup. Call Serve to accept connections.
+//
+// The socket's directory is made 0700 before the socket exists: the socket
+// gets the umask's mode when it is created, and only then is narrowed to
+// 0600, so another local user must not be able to reach it in between.
+func Listen(sup *Supervisor, path string) (*Server, error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("creating socket directory: %w", err)
+	}
+	if err := os.Chmod(dir, 0700); err != nil {
+		return nil, fmt.Errorf("securing socket directory: %w", err)
+	}
+	if conn, err := net.Dial("unix", path); err == nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("pty supervisor already listening on %s", path)
+	}
+	_ = os.Remove(path)
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("listening on %s: %w", path, err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		_ = l.Close()
+		return nil, fmt.Errorf("securing %s: %w", path, err)
+	}
+	return &Server{sup: sup, listener: l}, nil
+}
+
+// Serve accepts connections until Close is called.
+func (srv *Server) Serve() error {
+	for {
+		conn, err := srv.listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go srv.handle(conn)
+	}
+}
+
+// Close stops accepting connections and removes the socket. Sessions keep
+// running; call Supervisor.Shutdown to stop them.
+func (srv *Server) Close() error {
+	return srv.listener.Close()
+}
+
+func (srv *Server) handle(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	r := bufio.NewReader(conn)
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		return
+	}
+	var req request
+	if err := json.Unmarshal(line, &req); err != nil {
+		_ = writeResponse(conn, &response{Error: fmt.Sprintf("bad request: %v", err)})
+		return
+	}
+	if req.Op == "attach" {
+		srv.attach(conn, r, req)
+		return
+	}
+	_ = writeResponse(conn, srv.dispatch(req))
+}
+
+func (srv *Server) dispatch(req request) *response {
+	sup := srv.sup
+	resp := &response{}
+	var err error
+	switch req.Op {
+	case "new":
+		err = sup.Create(req.Session, req.WorkDir, req.Command, req.Env)
+	case "kill":
+		err = sup.Kill(req.Session)
+	case "has":
+		resp.Bool = sup.Has(req.Session)
+	case "list":
+		resp.Sessions = sup.List()
+	case "keys":
+		err = sup.SendKeys(req.Session, req.Value)
+	case "write":
+		err = sup.Write(req.Session, []byte(req.Value))
+	case "nudge":
+		err = sup.Nudge(req.Session, req.Value)
+	case "capture":
+		resp.Value, err = sup.Capture(req.Session, req.Lines)
+	case "info":
+		resp.Info, err = sup.Info(req.Session)
+	case "setenv":
+		err = sup.SetEnv(req.Session, req.Key, req.Value)
+	case "getenv":
+		resp.Value, err = sup.GetEnv(req.Session, req.Key)
+	case "remain-on-exit":
+		err = sup.SetRemainOnExit(req.Session, req.On)
+	case "auto-respawn":
+		err = sup.SetAutoRespawn(req.Session, req.On)
+	case "command":
+		resp.Value, err = sup.CurrentCommand(req.Session)
+	case "alive":
+		resp.Bool = sup.AgentAlive(req.Session)
+	case "resize":
+		err = sup.Resize(req.Session, req.Rows, req.Cols)
+	default:
+		err = fmt.Errorf("unknown op %q", req.Op)
+	}
+	if err != nil {
+		resp.Error = err.Error()
+		resp.NotFound = errors.Is(err, ErrSessionNotFound)
+		resp.Exists = errors.Is(err, ErrSessionExists)
+	}
+	return resp
+}
+
+// attach streams scrollback and live output to conn and forwards bytes
+// read from conn to the session's terminal until either side closes.
+func (srv *Server) attach(conn net.Conn, in *bufio.Reader, req request) {
+	replay, output, cancel, err := srv.sup.Subscribe(req.Session)
+	if err != nil {
+		_ = writeResponse(conn, &response{Error: err.Error(), NotFound: errors.Is(err, ErrSessionNotFound)})
+		return
+	}
+	defer cancel()
+	_ = srv.sup.Resize(req.Session, req.Rows, req.Cols)
+	if err := writeResponse(conn, &response{}); err != nil {
+		return
+	}
+	if _, err := conn.Write(replay); err != nil {
+		return
+	}
+
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			n, err := in.Read(buf)
+			if n > 0 {
+				_ = srv.sup.Write(req.Session, buf[:n])
+			}
+			if err != nil {
+				cancel()
+				return
+			}
+		}
+	}()
+	for chunk := range output {
+		if _, err := conn.Write(chunk); err != nil {
+			return
+		}
+	}
+}
+
+func writeResponse(w io.Writer, resp *response) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,608 @@
+// Package pty provides a headless session backend that runs agents as child
+// processes on pseudo-terminals instead of inside tmux.
+//
+// The Supervisor owns the sessions and is hosted by the daemon. Other gt
+// processes reach it through a unix socket with Client, which implements the
+// same session operations as *tmux.Tmux so lifecycle code can use either.
+package pty
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Errors are shared with the tmux backend so callers can test for them the
+// same way regardless of which backend is in use.
+var (
+	ErrSessionNotFound = tmux.ErrSessionNotFound
+	ErrSessionExists   = tmux.ErrSessionExists
+	ErrNoServer        = errors.New("pty supervisor not running")
+	ErrUnsupported     = errors.New("pty sessions are not supported on this platform")
+)
+
+const (
+	// DefaultRows and DefaultCols size new terminals. Agents render for a
+	// wide terminal since nobody is usually attached.
+	DefaultRows = 50
+	DefaultCols = 200
+
+	// DefaultRespawnDelay matches the tmux auto-respawn hook.
+	DefaultRespawnDelay = 3 * time.Second
+
+	// killGrace is how long a process group gets after SIGTERM.
+	killGrace = 2 * time.Second
+)
+
+// Info describes a supervised session.
+type Info struct {
+	Name     string    `json:"name"`
+	PID      int       `json:"pid"`
+	Command  string    `json:"command"`
+	WorkDir  string    `json:"work_dir"`
+	Created  time.Time `json:"created"`
+	Activity time.Time `json:"activity"`
+	Exited   bool      `json:"exited"`
+	ExitCode int       `json:"exit_code"`
+	Attached int       `json:"attached"`
+	Restarts int       `json:"restarts"`
+}
+
+// Supervisor runs sessions as child processes, each on its own PTY with a
+// scrollback ring buffer. Sessions keep tmux semantics: a session disappears
+// when its command exits unless remain-on-exit or auto-respawn is set.
+type Supervisor struct {
+	// ScrollbackSize is the per-session ring buffer size in bytes.
+	ScrollbackSize int
+	// Rows and Cols size new terminals.
+	Rows, Cols uint16
+	// RespawnDelay is the pause before an auto-respawn restart.
+	RespawnDelay time.Duration
+	// OnExit, if set, is called when a session's command exits.
+	OnExit func(name string, exitCode int)
+
+	mu       sync.Mutex
+	sessions map[string]*ptySession
+}
+
+// NewSupervisor returns a supervisor with default sizes.
+func NewSupervisor() *Supervisor {
+	return &Supervisor{
+		ScrollbackSize: DefaultScrollback,
+		Rows:           DefaultRows,
+		Cols:           DefaultCols,
+		RespawnDelay:   DefaultRespawnDelay,
+		sessions:       make(map[string]*ptySession),
+	}
+}
+
+type ptySession struct {
+	name    string
+	workDir string
+	command string
+	created time.Time
+
+	mu           sync.Mutex
+	env          map[string]string
+	cmd          *exec.Cmd
+	master       *os.File
+	scroll       *Ring
+	activity     time.Time
+	exited       bool
+	exitCode     int
+	remainOnExit bool
+	autoRespawn  bool
+	killed       bool
+	restarts     int
+	subscribers  map[chan []byte]struct{}
+	done         chan struct{} // closed when the current process exits
+
+	nudgeMu sync.Mutex // serializes nudges, like tmux's nudge lock
+}
+
+// Create starts command in a new session named name.
+func (s *Supervisor) Create(name, workDir, command string, env map[string]string) error {
+	if name == "" {
+		return fmt.Errorf("session name is required")
+	}
+	s.mu.Lock()
+	if _, ok := s.sessions[name]; ok {
+		s.mu.Unlock()
+		return ErrSessionExists
+	}
+	sess := &ptySession{
+		name:        name,
+		workDir:     workDir,
+		command:     command,
+		created:     time.Now(),
+		env:         make(map[string]string),
+		scroll:      NewRing(s.ScrollbackSize),
+		subscribers: make(map[chan []byte]struct{}),
+	}
+	for k, v := range env {
+		sess.env[k] = v
+	}
+	s.sessions[name] = sess
+	s.mu.Unlock()
+
+	sess.mu.Lock()
+	err := s.spawn(sess)
+	sess.mu.Unlock()
+	if err != nil {
+		s.mu.Lock()
+		delete(s.sessions, name)
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// spawn starts the session's command. Callers hold sess.mu.
+func (s *Supervisor) spawn(sess *ptySession) error {
+	cmd := exec.Command("sh", "-c", sess.command)
+	cmd.Dir = sess.workDir
+	cmd.Env = append(os.Environ(), "TERM=xterm-256color")
+	for _, k := range sortedKeys(sess.env) {
+		cmd.Env = append(cmd.Env, k+"="+sess.env[k])
+	}
+	master, err := start(cmd, s.Rows, s.Cols)
+	if err != nil {
+		return fmt.Errorf("starting %s: %w", sess.name, err)
+	}
+	sess.cmd = cmd
+	sess.master = master
+	sess.exited = false
+	sess.exitCode = 0
+	sess.activity = time.Now()
+	sess.done = make(chan struct{})
+	go s.pump(sess, master, cmd, sess.done)
+	return nil
+}
+
+// pump copies PTY output into the scrollback and to attached clients until
+// the command exits, then applies the exit policy. The session ends when the
+// command itself exits, even if background children still hold the terminal.
+func (s *Supervisor) pump(sess *ptySession, master *os.File, cmd *exec.Cmd, done chan struct{}) {
+	readDone := make(chan struct{})
+	go func() {
+		defer close(readDone)
+		s.copyOutput(sess, master)
+	}()
+
+	exitCode := 0
+	if err := cmd.Wait(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitCode = exitErr.ExitCode()
+		} else {
+			exitCode = -1
+		}
+	}
+	// Drain whatever the command printed last, then stop reading.
+	select {
+	case <-readDone:
+	case <-time.After(200 * time.Millisecond):
+	}
+	_ = master.Close()
+
+	sess.mu.Lock()
+	sess.exited = true
+	sess.exitCode = exitCode
+	killed, respawn, remain := sess.killed, sess.autoRespawn, sess.remainOnExit
+	close(done)
+	sess.mu.Unlock()
+
+	if s.OnExit != nil && !killed {
+		s.OnExit(sess.name, exitCode)
+	}
+	switch {
+	case killed:
+		// Kill already removed the session.
+	case respawn:
+		go s.respawnAfter(sess, s.RespawnDelay)
+	case !remain:
+		s.remove(sess)
+	}
+}
+
+func (s *Supervisor) copyOutput(sess *ptySession, master *os.File) {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := master.Read(buf)
+		if n > 0 {
+			chunk := append([]byte(nil), buf[:n]...)
+			sess.mu.Lock()
+			_, _ = sess.scroll.Write(chunk)
+			sess.activity = time.Now()
+			for ch := range sess.subscribers {
+				select {
+				case ch <- chunk:
+				default: // slow viewer; it can recapture
+				}
+			}
+			sess.mu.Unlock()
+		}
+		if err != nil {
+			return // EIO once the slave side has closed
+		}
+	}
+}
+
+func (s *Supervisor) respawnAfter(sess *ptySession, delay time.Duration) {
+	time.Sleep(delay)
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if sess.killed || !sess.exited {
+		return
+	}
+	sess.restarts++
+	if err := s.spawn(sess); err != nil {
+		_, _ = fmt.Fprintf(sess.scroll, "\r\n[respawn failed: %v]\r\n", err)
+	}
+}
+
+func (s *Supervisor) remove(sess *ptySession) {
+	s.mu.Lock()
+	if s.sessions[sess.name] == sess {
+		delete(s.sessions, sess.name)
+	}
+	s.mu.Unlock()
+
+	sess.mu.Lock()
+	for ch := range sess.subscribers {
+		close(ch)
+		delete(sess.subscribers, ch)
+	}
+	sess.mu.Unlock()
+}
+
+func (s *Supervisor) get(name string) (*ptySession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[name]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	return sess, nil
+}
+
+// Kill terminates the session's process group and removes the session.
+func (s *Supervisor) Kill(name string) error {
+	sess, err := s.get(name)
+	if err != nil {
+		return err
+	}
+	sess.mu.Lock()
+	sess.killed = true
+	exited, done := sess.exited, sess.done
+	var pid int
+	if sess.cmd != nil && sess.cmd.Process != nil {
+		pid = sess.cmd.Process.Pid
+	}
+	sess.mu.Unlock()
+
+	if !exited && pid > 0 {
+		_ = killGroup(pid, syscall.SIGHUP)
+		_ = killGroup(pid, syscall.SIGTERM)
+		select {
+		case <-done:
+		case <-time.After(killGrace):
+			_ = killGroup(pid, syscall.SIGKILL)
+			<-done
+		}
+	}
+	s.remove(sess)
+	return nil
+}
+
+// Shutdown kills every session.
+func (s *Supervisor) Shutdown() {
+	for _, name := range s.List() {
+		_ = s.Kill(name)
+	}
+}
+
+// Has reports whether a session exists (including exited sessions kept by
+// remain-on-exit).
+func (s *Supervisor) Has(name string) bool {
+	_, err := s.get(name)
+	return err == nil
+}
+
+// List returns session names in sorted order.
+func (s *Supervisor) List() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.sessions))
+	for name := range s.sessions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Write sends raw bytes to the session's terminal.
+func (s *Supervisor) Write(name string, data []byte) error {
+	sess, err := s.get(name)
+	if err != nil {
+		return err
+	}
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if sess.exited {
+		return fmt.Errorf("session %s: process has exited", name)
+	}
+	_, err = sess.master.Write(data)
+	return err
+}
+
+// SendKeys sends a tmux-style key (e.g., "C-c", "Enter") or literal text.
+func (s *Supervisor) SendKeys(name, keys string) error {
+	return s.Write(name, KeyBytes(keys))
+}
+
+// Nudge types message into the session and submits it, using the same
+// paste/Escape/Enter sequence as tmux.NudgeSession.
+func (s *Supervisor) Nudge(name, message string) error {
+	sess, err := s.get(name)
+	if err != nil {
+		return err
+	}
+	sess.nudgeMu.Lock()
+	defer sess.nudgeMu.Unlock()
+
+	if err := s.Write(name, []byte(message)); err != nil {
+		return err
+	}
+	time.Sleep(500 * time.Millisecond)
+	_ = s.Write(name, KeyBytes("Escape"))
+	time.Sleep(100 * time.Millisecond)
+	return s.Write(name, KeyBytes("Enter"))
+}
+
+// Capture returns the last lines of the session's scrollback as plain text.
+// lines <= 0 returns the whole buffer.
+func (s *Supervisor) Capture(name string, lines int) (string, error) {
+	raw, err := s.Scrollback(name)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(lastLines(PlainText(raw), lines), "\n"), nil
+}
+
+// Scrollback returns the raw buffered output of a session.
+func (s *Supervisor) Scrollback(name string) ([]byte, error) {
+	sess, err := s.get(name)
+	if err != nil {
+		return nil, err
+	}
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.scroll.Bytes(), nil
+}
+
+// Info returns a snapshot of the session's state.
+func (s *Supervisor) Info(name string) (*Info, error) {
+	sess, err := s.get(name)
+	if err != nil {
+		return nil, err
+	}
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	info := &Info{
+		Name:     sess.name,
+		Command:  sess.command,
+		WorkDir:  sess.workDir,
+		Created:  sess.created,
+		Activity: sess.activity,
+		Exited:   sess.exited,
+		ExitCode: sess.exitCode,
+		Attached: len(sess.subscribers),
+		Restarts: sess.restarts,
+	}
+	if sess.cmd != nil && sess.cmd.Process != nil {
+		info.PID = sess.cmd.Process.Pid
+	}
+	return info, nil
+}
+
+// SetEnv records an environment variable on the session. Like tmux
+// set-environment, it affects respawned processes and GetEnv lookups, not
+// the running process.
+func (s *Supervisor) SetEnv(name, key, value string) error {
+	sess, err := s.get(name)
+	if err != nil {
+		return err
+	}
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	sess.env[key] = value
+	return nil
+}
+
+// GetEnv returns a session environment variable.
+func (s *Supervisor) GetEnv(name, key string) (string, error) {
+	sess, err := s.get(name)
+	if err != nil {
+		return "", err
+	}
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	value, ok := sess.env[key]
+	if !ok {
+		return "", fmt.Errorf("unknown variable: %s", key)
+	}
+	return value, nil
+}
+
+// SetRemainOnExit keeps the session around after its command exits.
+func (s *Supervisor) SetRemainOnExit(name string, on bool) error {
+	sess, err := s.get(name)
+	if err != nil {
+		return err
+	}
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	sess.remainOnExit = on
+	return nil
+}
+
+// SetAutoRespawn restarts the session's command after it exits.
+func (s *Supervisor) SetAutoRespawn(name string, on bool) error {
+	sess, err := s.get(name)
+	if err != nil {
+		return err
+	}
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	sess.autoRespawn = on
+	return nil
+}
+
+// CurrentCommand returns the name of the process in the foreground of the
+// session: the command itself, or the first child of the wrapping shell.
+func (s *Supervisor) CurrentCommand(name string) (string, error) {
+	pid, err := s.livePID(name)
+	if err != nil {
+		return "", err
+	}
+	cmd := processName(pid)
+	for depth := 0; depth < 4 && isShell(cmd); depth++ {
+		children := childPIDs(pid)
+		if len(children) == 0 {
+			break
+		}
+		pid = children[0]
+		cmd = processName(pid)
+	}
+	return cmd, nil
+}
+
+// AgentAlive reports whether the session's agent process is running, using
+// GT_PROCESS_NAMES (or the GT_AGENT preset) from the session environment.
+func (s *Supervisor) AgentAlive(name string) bool {
+	pid, err := s.livePID(name)
+	if err != nil {
+		return false
+	}
+	var names []string
+	if v, err := s.GetEnv(name, "GT_PROCESS_NAMES"); err == nil && v != "" {
+		names = strings.Split(v, ",")
+	} else {
+		agent, _ := s.GetEnv(name, "GT_AGENT")
+		names = config.GetProcessNames(agent)
+	}
+	return treeHasName(pid, names, 0)
+}
+
+// Resize changes the session's terminal size.
+func (s *Supervisor) Resize(name string, rows, cols uint16) error {
+	sess, err := s.get(name)
+	if err != nil {
+		return err
+	}
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if sess.exited || rows == 0 || cols == 0 {
+		return nil
+	}
+	return setSize(sess.master, rows, cols)
+}
+
+// Subscribe returns the current scrollback and a channel of subsequent
+// output. The channel is closed when the session is removed; cancel stops
+// the subscription early.
+func (s *Supervisor) Subscribe(name string) (replay []byte, output <-chan []byte, cancel func(), err error) {
+	sess, err := s.get(name)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ch := make(chan []byte, 256)
+	sess.mu.Lock()
+	replay = sess.scroll.Bytes()
+	sess.subscribers[ch] = struct{}{}
+	sess.mu.Unlock()
+
+	var once sync.Once
+	cancel = func() {
+		once.Do(func() {
+			sess.mu.Lock()
+			defer sess.mu.Unlock()
+			if _, ok := sess.subscribers[ch]; ok {
+				delete(sess.subscribers, ch)
+				close(ch)
+			}
+		})
+	}
+	return replay, ch, cancel, nil
+}
+
+// livePID returns the PID of the session's running command.
+func (s *Supervisor) livePID(name string) (int, error) {
+	sess, err := s.get(name)
+	if err != nil {
+		return 0, err
+	}
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if sess.exited || sess.cmd == nil || sess.cmd.Process == nil {
+		return 0, fmt.Errorf("session %s: process has exited", name)
+	}
+	return sess.cmd.Process.Pid, nil
+}
+
+func treeHasName(pid int, names []string, depth int) bool {
+	if depth > 10 {
+		return false
+	}
+	for _, have := range processNames(pid) {
+		for _, want := range names {
+			if have == want {
+				return true
+			}
+		}
+	}
+	for _, child := range childPIDs(pid) {
+		if treeHasName(child, names, depth+1) {
+			return true
+		}
+	}
+	return false
+}
+
+func isShell(cmd string) bool {
+	for _, shell := range constants.SupportedShells {
+		if cmd == shell {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatPID renders a PID the way tmux reports pane_pid.
+func formatPID(pid int) string {
+	if pid <= 0 {
+		return ""
+	}
+	return strconv.Itoa(pid)
+}
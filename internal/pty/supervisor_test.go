@@ -0,0 +1,199 @@
+//go:build linux
+
+package pty
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func requirePTY(t *testing.T) {
+	t.Helper()
+	f, err := os.OpenFile("/dev/ptmx", os.O_RDWR, 0)
+	if err != nil {
+		t.Skipf("no pty support: %v", err)
+	}
+	_ = f.Close()
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s", what)
+}
+
+func TestSupervisor_Lifecycle(t *testing.T) {
+	requirePTY(t)
+	sup := NewSupervisor()
+	defer sup.Shutdown()
+
+	dir := t.TempDir()
+	if err := sup.Create("gt-test-echo", dir, `echo "ready in $PWD as $GT_ROLE"; read line; echo "got:$line"; sleep 30`, map[string]string{"GT_ROLE": "polecat"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sup.Create("gt-test-echo", dir, "true", nil); !errors.Is(err, ErrSessionExists) {
+		t.Errorf("duplicate Create: err = %v, want ErrSessionExists", err)
+	}
+	waitFor(t, "startup output", func() bool {
+		out, _ := sup.Capture("gt-test-echo", 10)
+		return strings.Contains(out, "ready in "+dir+" as polecat")
+	})
+
+	if err := sup.Write("gt-test-echo", []byte("hi")); err != nil {
+		t.Fatal(err)
+	}
+	if err := sup.SendKeys("gt-test-echo", "Enter"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "echoed input", func() bool {
+		out, _ := sup.Capture("gt-test-echo", 1)
+		return out == "got:hi"
+	})
+
+	// Without remain-on-exit the session disappears once its command exits.
+	if err := sup.SendKeys("gt-test-echo", "C-c"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "session removal", func() bool { return !sup.Has("gt-test-echo") })
+	if _, err := sup.Capture("gt-test-echo", 1); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Capture after exit: err = %v, want ErrSessionNotFound", err)
+	}
+}
+
+func TestSupervisor_RemainOnExitAndRespawn(t *testing.T) {
+	requirePTY(t)
+	sup := NewSupervisor()
+	sup.RespawnDelay = 10 * time.Millisecond
+	exits := make(chan int, 4)
+	sup.OnExit = func(name string, code int) { exits <- code }
+	defer sup.Shutdown()
+
+	if err := sup.Create("gt-test-dead", t.TempDir(), "exit 3", nil); err != nil {
+		t.Fatal(err)
+	}
+	_ = sup.SetRemainOnExit("gt-test-dead", true)
+	if code := <-exits; code != 3 {
+		t.Errorf("exit code = %d, want 3", code)
+	}
+	waitFor(t, "exited state", func() bool {
+		info, err := sup.Info("gt-test-dead")
+		return err == nil && info.Exited
+	})
+	if sup.AgentAlive("gt-test-dead") {
+		t.Error("exited session reported agent alive")
+	}
+	_ = sup.Kill("gt-test-dead")
+
+	if err := sup.Create("gt-test-loop", t.TempDir(), "sleep 0.05", nil); err != nil {
+		t.Fatal(err)
+	}
+	_ = sup.SetAutoRespawn("gt-test-loop", true)
+	waitFor(t, "respawn", func() bool {
+		info, err := sup.Info("gt-test-loop")
+		return err == nil && info.Restarts >= 2
+	})
+	if err := sup.Kill("gt-test-loop"); err != nil {
+		t.Fatal(err)
+	}
+	if sup.Has("gt-test-loop") {
+		t.Error("killed session still listed")
+	}
+}
+
+func TestListen_SocketIsPrivate(t *testing.T) {
+	sup := NewSupervisor()
+	defer sup.Shutdown()
+	socket := SocketPath(t.TempDir())
+	srv, err := Listen(sup, socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = srv.Close() }()
+
+	for path, want := range map[string]os.FileMode{filepath.Dir(socket): 0700, socket: 0600} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Errorf("%s mode = %o, want %o", path, got, want)
+		}
+	}
+}
+
+func TestClientServer(t *testing.T) {
+	requirePTY(t)
+	sup := NewSupervisor()
+	defer sup.Shutdown()
+	socket := filepath.Join(t.TempDir(), "pty.sock")
+	srv, err := Listen(sup, socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = srv.Serve() }()
+	defer func() { _ = srv.Close() }()
+
+	c := NewClient(socket)
+	if !c.IsAvailable() {
+		t.Fatal("client cannot reach server")
+	}
+	if err := c.NewSessionWithCommand("gt-test-cat", t.TempDir(), "cat"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := c.HasSession("gt-test-cat"); !ok || err != nil {
+		t.Fatalf("HasSession = %v, %v", ok, err)
+	}
+	if err := c.SetEnvironment("gt-test-cat", "GT_PROCESS_NAMES", "cat"); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := c.GetEnvironment("gt-test-cat", "GT_PROCESS_NAMES"); v != "cat" {
+		t.Errorf("GetEnvironment = %q", v)
+	}
+	if err := c.WaitForCommand("gt-test-cat", []string{"sh"}, 5*time.Second); err != nil {
+		t.Error(err)
+	}
+	if !c.IsAgentAlive("gt-test-cat") {
+		t.Error("IsAgentAlive = false for running cat")
+	}
+	if pid, err := c.GetPanePID("gt-test-cat"); err != nil || pid == "" {
+		t.Errorf("GetPanePID = %q, %v", pid, err)
+	}
+
+	if err := c.SendKeysDebounced("gt-test-cat", "ping", 0); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "echo through cat", func() bool {
+		out, _ := c.CapturePane("gt-test-cat", 5)
+		return strings.Count(out, "ping") >= 2 // terminal echo plus cat's output
+	})
+
+	names, _ := c.ListSessions()
+	if len(names) != 1 || names[0] != "gt-test-cat" {
+		t.Errorf("ListSessions = %v", names)
+	}
+	if err := c.KillSessionWithProcesses("gt-test-cat"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CapturePane("gt-test-cat", 5); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("CapturePane after kill: err = %v, want ErrSessionNotFound", err)
+	}
+
+	// An unreachable supervisor behaves like a missing tmux server.
+	gone := NewClient(filepath.Join(t.TempDir(), "missing.sock"))
+	if ok, err := gone.HasSession("x"); ok || err != nil {
+		t.Errorf("HasSession without server = %v, %v", ok, err)
+	}
+	if err := gone.NewSessionWithCommand("x", "/", "true"); !errors.Is(err, ErrNoServer) {
+		t.Errorf("NewSession without server: err = %v, want ErrNoServer", err)
+	}
+}
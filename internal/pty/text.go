@@ -0,0 +1,88 @@
+package pty
+
+import (
+	"strings"
+)
+
+// PlainText converts raw terminal output to plain text lines. Escape
+// sequences (CSI, OSC and two-byte ESC sequences) and control characters
+// are dropped, CRLF becomes a newline, and a bare carriage return restarts
+// the current line. The result approximates what scrolled past; it is not
+// a rendered screen, so full-screen TUIs may capture out of order.
+func PlainText(raw []byte) []string {
+	var (
+		lines []string
+		line  strings.Builder
+	)
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		switch {
+		case c == 0x1b:
+			i = skipEscape(raw, i)
+		case c == '\n':
+			lines = append(lines, line.String())
+			line.Reset()
+		case c == '\r':
+			if i+1 < len(raw) && raw[i+1] == '\n' {
+				continue
+			}
+			line.Reset()
+		case c == '\t':
+			line.WriteByte(c)
+		case c < 0x20 || c == 0x7f:
+			// Other control characters carry no text.
+		default:
+			line.WriteByte(c)
+		}
+	}
+	if line.Len() > 0 {
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+// skipEscape returns the index of the last byte of the escape sequence
+// starting at raw[i].
+func skipEscape(raw []byte, i int) int {
+	if i+1 >= len(raw) {
+		return i
+	}
+	switch raw[i+1] {
+	case '[': // CSI: parameters, then a final byte in 0x40-0x7e
+		for j := i + 2; j < len(raw); j++ {
+			if raw[j] >= 0x40 && raw[j] <= 0x7e {
+				return j
+			}
+		}
+		return len(raw) - 1
+	case ']', 'P', '_', '^': // OSC/DCS/APC/PM: until BEL or ST
+		for j := i + 2; j < len(raw); j++ {
+			if raw[j] == 0x07 {
+				return j
+			}
+			if raw[j] == 0x1b && j+1 < len(raw) && raw[j+1] == '\\' {
+				return j + 1
+			}
+		}
+		return len(raw) - 1
+	case '(', ')', '*', '+', '#': // charset and line-size selection take one more byte
+		if i+2 < len(raw) {
+			return i + 2
+		}
+		return len(raw) - 1
+	default:
+		return i + 1
+	}
+}
+
+// lastLines returns the final n lines of text, ignoring trailing blank lines.
+// n <= 0 returns everything.
+func lastLines(lines []string, n int) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if n > 0 && len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
@@ -18,7 +18,8 @@ type RotateResult struct {
 
 // RotatePlan describes what the rotator will do.
 type RotatePlan struct {
-	// LimitedSessions are sessions detected as rate-limited.
+	// LimitedSessions are sessions detected as rate-limited, or for a
+	// proactive plan, idle sessions on accounts in forecast cooldown.
 	LimitedSessions []ScanResult
 
 	// AvailableAccounts are accounts that can be rotated to.
@@ -67,26 +68,59 @@ func PlanRotation(scanner *Scanner, mgr *Manager, acctCfg *config.AccountsConfig
 	// Get available accounts
 	available := mgr.AvailableAccounts(state)
 
-	// Plan assignments: assign all limited sessions to the best available account.
-	// Strategy: pick the first available account (LRU) that isn't already the
-	// session's current account. All sessions rotate to the same account so the
-	// operator can drain one account at a time, then move on.
-	assignments := make(map[string]string)
-	if len(available) > 0 {
-		for _, r := range limitedSessions {
-			// Find the first available account that differs from current
-			for _, candidate := range available {
-				if candidate != r.AccountHandle {
-					assignments[r.Session] = candidate
-					break
-				}
-			}
+	return &RotatePlan{
+		LimitedSessions:   limitedSessions,
+		AvailableAccounts: available,
+		Assignments:       assignAccounts(limitedSessions, available),
+	}, nil
+}
+
+// PlanProactiveRotation plans moving sessions off accounts in forecast
+// cooldown (predicted to hit their limit soon) before they are blocked.
+// Only sessions isIdle reports as waiting at a prompt are planned, so no
+// in-flight turn is lost; busy sessions are left for a later pass.
+func PlanProactiveRotation(scanner *Scanner, mgr *Manager, acctCfg *config.AccountsConfig, isIdle func(session string) bool) (*RotatePlan, error) {
+	results, err := scanner.ScanAll()
+	if err != nil {
+		return nil, fmt.Errorf("scanning sessions: %w", err)
+	}
+
+	state, err := mgr.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading quota state: %w", err)
+	}
+	mgr.EnsureAccountsTracked(state, acctCfg.Accounts)
+
+	var draining []ScanResult
+	for _, r := range results {
+		if r.AccountHandle == "" || state.Accounts[r.AccountHandle].Status != config.QuotaStatusCooldown {
+			continue
+		}
+		if isIdle(r.Session) {
+			draining = append(draining, r)
 		}
 	}
 
+	available := mgr.AvailableAccounts(state)
 	return &RotatePlan{
-		LimitedSessions:   limitedSessions,
+		LimitedSessions:   draining,
 		AvailableAccounts: available,
-		Assignments:       assignments,
+		Assignments:       assignAccounts(draining, available),
 	}, nil
 }
+
+// assignAccounts assigns each session the first available account (LRU)
+// that isn't already its current account. All sessions rotate to the same
+// account so the operator can drain one account at a time, then move on.
+func assignAccounts(sessions []ScanResult, available []string) map[string]string {
+	assignments := make(map[string]string)
+	for _, r := range sessions {
+		for _, candidate := range available {
+			if candidate != r.AccountHandle {
+				assignments[r.Session] = candidate
+				break
+			}
+		}
+	}
+	return assignments
+}
@@ -287,3 +287,57 @@ func TestPlanRotation_MultipleLimitedSessions(t *testing.T) {
 		}
 	}
 }
+
+func TestPlanProactiveRotation_MovesIdleSessionsOffCooldownAccount(t *testing.T) {
+	setupTestRegistry(t)
+
+	tmux := &mockTmux{
+		sessions: []string{"gt-crew-bear", "gt-crew-wolf", "gt-witness"},
+		paneContent: map[string]string{
+			"gt-crew-bear": "❯ ",
+			"gt-crew-wolf": "working...",
+			"gt-witness":   "❯ ",
+		},
+		envVars: map[string]map[string]string{
+			"gt-crew-bear": {"CLAUDE_CONFIG_DIR": "/home/user/.claude-accounts/work"},
+			"gt-crew-wolf": {"CLAUDE_CONFIG_DIR": "/home/user/.claude-accounts/work"},
+			"gt-witness":   {"CLAUDE_CONFIG_DIR": "/home/user/.claude-accounts/personal"},
+		},
+	}
+
+	accounts := &config.AccountsConfig{
+		Accounts: map[string]config.Account{
+			"work":     {ConfigDir: "/home/user/.claude-accounts/work"},
+			"personal": {ConfigDir: "/home/user/.claude-accounts/personal"},
+		},
+	}
+
+	scanner, err := NewScanner(tmux, nil, accounts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	townRoot := setupTestTown(t)
+	mgr := NewManager(townRoot)
+	state := &config.QuotaState{
+		Version: config.CurrentQuotaVersion,
+		Accounts: map[string]config.AccountQuotaState{
+			"work":     {Status: config.QuotaStatusCooldown, CooldownUntil: "2025-01-01T05:00:00Z"},
+			"personal": {Status: config.QuotaStatusAvailable},
+		},
+	}
+	if err := mgr.Save(state); err != nil {
+		t.Fatal(err)
+	}
+
+	isIdle := func(session string) bool { return tmux.paneContent[session] == "❯ " }
+	plan, err := PlanProactiveRotation(scanner, mgr, accounts, isIdle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the idle session on the cooldown account moves; the busy one waits.
+	if len(plan.Assignments) != 1 || plan.Assignments["gt-crew-bear"] != "personal" {
+		t.Errorf("assignments = %v, want only gt-crew-bear → personal", plan.Assignments)
+	}
+}
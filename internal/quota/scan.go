@@ -129,14 +129,25 @@ func (s *Scanner) resolveAccountHandle(session string) string {
 		return "" // No CLAUDE_CONFIG_DIR = using default config
 	}
 
+	return AccountForConfigDir(s.accounts, configDir)
+}
+
+// AccountForConfigDir maps a CLAUDE_CONFIG_DIR value to a registered account
+// handle. Returns "" if no account uses that directory.
+func AccountForConfigDir(accounts *config.AccountsConfig, configDir string) string {
+	if accounts == nil {
+		return ""
+	}
 	configDir = strings.TrimSpace(configDir)
-	for handle, acct := range s.accounts.Accounts {
+	if configDir == "" {
+		return ""
+	}
+	for handle, acct := range accounts.Accounts {
 		// Compare normalized paths (accounts may use ~/... while tmux has expanded)
 		if acct.ConfigDir == configDir || util.ExpandHome(acct.ConfigDir) == configDir {
 			return handle
 		}
 	}
-
 	return "" // CLAUDE_CONFIG_DIR doesn't match any registered account
 }
 
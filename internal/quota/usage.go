@@ -0,0 +1,480 @@
+package quota
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/costs"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Usage tracking turns the quota manager from reactive (a session prints a
+// rate-limit message, then gets rotated) into predictive. Each account's
+// requests are read from the Claude Code transcripts in its config dir and
+// kept as 5-minute buckets. Every time an account is seen rate-limited, the
+// reset time the provider reported is recorded; from those observations the
+// tracker learns how long the account's window is and how many tokens fit in
+// it, and forecasts when the current window runs out.
+
+const (
+	// DefaultUsageWindow is assumed until a reset has been observed. Claude
+	// subscription limits reset five hours after a window's first request.
+	DefaultUsageWindow = 5 * time.Hour
+
+	usageBucketWidth = 5 * time.Minute
+	usageRetention   = 8 * 24 * time.Hour
+	maxResetHistory  = 20
+	minUsageWindow   = time.Hour
+	maxUsageWindow   = 7 * 24 * time.Hour
+
+	// burnLookback is how far back the burn rate is measured.
+	burnLookback = time.Hour
+	// minBurnSpan keeps a burst at the start of a window from reading as a
+	// huge hourly rate.
+	minBurnSpan = 15 * time.Minute
+)
+
+// UsageBucket is the usage of one account in a 5-minute span.
+type UsageBucket struct {
+	Start    time.Time `json:"start"`
+	Tokens   int64     `json:"tokens"`
+	Requests int       `json:"requests"`
+}
+
+// ResetObservation records one time an account hit its limit.
+type ResetObservation struct {
+	LimitedAt time.Time `json:"limited_at"`
+	ResetsAt  time.Time `json:"resets_at"`
+}
+
+// AccountUsage is the tracked usage of one account.
+type AccountUsage struct {
+	Buckets        []UsageBucket      `json:"buckets,omitempty"`
+	Resets         []ResetObservation `json:"resets,omitempty"`
+	ScannedThrough time.Time          `json:"scanned_through,omitempty"` // newest transcript request counted
+}
+
+// UsageState is the usage tracker's state (mayor/.runtime/quota-usage.json).
+type UsageState struct {
+	Accounts  map[string]*AccountUsage `json:"accounts"`
+	UpdatedAt time.Time                `json:"updated_at"`
+}
+
+// usagePath returns the path to the usage tracker state.
+func (m *Manager) usagePath() string {
+	return filepath.Join(m.townRoot, constants.DirMayor, constants.DirRuntime, "quota-usage.json")
+}
+
+// LoadUsage reads the usage tracker state. Returns an empty state if the
+// file doesn't exist yet.
+func (m *Manager) LoadUsage() (*UsageState, error) {
+	state := &UsageState{Accounts: make(map[string]*AccountUsage)}
+	data, err := os.ReadFile(m.usagePath())
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("reading quota usage: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return &UsageState{Accounts: make(map[string]*AccountUsage)}, fmt.Errorf("parsing quota usage: %w", err)
+	}
+	if state.Accounts == nil {
+		state.Accounts = make(map[string]*AccountUsage)
+	}
+	return state, nil
+}
+
+// SaveUsage writes the usage tracker state.
+func (m *Manager) SaveUsage(state *UsageState) error {
+	return util.EnsureDirAndWriteJSON(m.usagePath(), state)
+}
+
+// Account returns the usage for handle, creating it if needed.
+func (s *UsageState) Account(handle string) *AccountUsage {
+	u := s.Accounts[handle]
+	if u == nil {
+		u = &AccountUsage{}
+		s.Accounts[handle] = u
+	}
+	return u
+}
+
+// RefreshUsage brings the usage state up to date: it reads new transcript
+// requests from each account's config dir, records the reset time of each
+// account the quota state shows as limited, and drops old buckets.
+// Per-account errors are returned without stopping the refresh.
+func RefreshUsage(usage *UsageState, accounts *config.AccountsConfig, quotaState *config.QuotaState, now time.Time) []error {
+	var errs []error
+	for handle, acct := range accounts.Accounts {
+		u := usage.Account(handle)
+		since := u.ScannedThrough
+		if earliest := now.Add(-usageRetention); since.Before(earliest) {
+			since = earliest
+		}
+		reqs, err := costs.ClaudeRequests(util.ExpandHome(acct.ConfigDir), since)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: reading transcripts: %w", handle, err))
+		}
+		u.Add(reqs)
+
+		if quotaState != nil {
+			qs := quotaState.Accounts[handle]
+			if qs.Status == config.QuotaStatusLimited && qs.LimitedAt != "" && qs.ResetsAt != "" {
+				limitedAt, err := time.Parse(time.RFC3339, qs.LimitedAt)
+				if err != nil {
+					continue
+				}
+				if resetsAt, ok := ParseResetTime(qs.ResetsAt, limitedAt); ok {
+					u.ObserveReset(limitedAt, resetsAt)
+				}
+			}
+		}
+		u.Prune(now)
+	}
+	for handle := range usage.Accounts {
+		if _, ok := accounts.Accounts[handle]; !ok {
+			delete(usage.Accounts, handle)
+		}
+	}
+	usage.UpdatedAt = now
+	return errs
+}
+
+// requestTokens is the token count charged against an account's limit.
+// Cache reads are excluded: they are billed at a small fraction of input
+// and count far less toward subscription limits.
+func requestTokens(t costs.Tokens) int64 {
+	return int64(t.Input + t.Output + t.CacheWrite)
+}
+
+// Add records requests in the account's buckets. Requests at or before
+// ScannedThrough are ignored, so overlapping reads never double count.
+func (u *AccountUsage) Add(reqs []costs.Request) {
+	index := make(map[int64]int, len(u.Buckets))
+	for i, b := range u.Buckets {
+		index[b.Start.Unix()] = i
+	}
+	newest := u.ScannedThrough
+	for _, r := range reqs {
+		if !r.At.After(u.ScannedThrough) {
+			continue
+		}
+		start := r.At.UTC().Truncate(usageBucketWidth)
+		i, ok := index[start.Unix()]
+		if !ok {
+			u.Buckets = append(u.Buckets, UsageBucket{Start: start})
+			i = len(u.Buckets) - 1
+			index[start.Unix()] = i
+		}
+		u.Buckets[i].Tokens += requestTokens(r.Tokens)
+		u.Buckets[i].Requests++
+		if r.At.After(newest) {
+			newest = r.At
+		}
+	}
+	u.ScannedThrough = newest
+	sort.Slice(u.Buckets, func(i, j int) bool { return u.Buckets[i].Start.Before(u.Buckets[j].Start) })
+}
+
+// ObserveReset records that the account hit its limit at limitedAt and
+// resets at resetsAt. Repeat sightings of the same limit are ignored.
+func (u *AccountUsage) ObserveReset(limitedAt, resetsAt time.Time) {
+	for _, r := range u.Resets {
+		if d := r.ResetsAt.Sub(resetsAt); d > -time.Minute && d < time.Minute {
+			return
+		}
+	}
+	u.Resets = append(u.Resets, ResetObservation{LimitedAt: limitedAt.UTC(), ResetsAt: resetsAt.UTC()})
+	sort.Slice(u.Resets, func(i, j int) bool { return u.Resets[i].ResetsAt.Before(u.Resets[j].ResetsAt) })
+	if len(u.Resets) > maxResetHistory {
+		u.Resets = u.Resets[len(u.Resets)-maxResetHistory:]
+	}
+}
+
+// Prune drops buckets older than the retention period.
+func (u *AccountUsage) Prune(now time.Time) {
+	cutoff := now.Add(-usageRetention)
+	i := 0
+	for i < len(u.Buckets) && u.Buckets[i].Start.Before(cutoff) {
+		i++
+	}
+	u.Buckets = u.Buckets[i:]
+}
+
+// usageBetween sums the buckets starting in [from, to).
+func (u *AccountUsage) usageBetween(from, to time.Time) (tokens int64, requests int) {
+	for _, b := range u.Buckets {
+		if !b.Start.Before(from) && b.Start.Before(to) {
+			tokens += b.Tokens
+			requests += b.Requests
+		}
+	}
+	return tokens, requests
+}
+
+// firstActivity returns the start of the first non-empty bucket at or after
+// from and before to.
+func (u *AccountUsage) firstActivity(from, to time.Time) (time.Time, bool) {
+	from = from.Truncate(usageBucketWidth)
+	for _, b := range u.Buckets {
+		if b.Tokens > 0 && !b.Start.Before(from) && b.Start.Before(to) {
+			return b.Start, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Window returns the account's learned window length: the median time from
+// a window's first request to its reset, across observed limits. Returns
+// DefaultUsageWindow and false when nothing has been learned yet.
+func (u *AccountUsage) Window() (time.Duration, bool) {
+	var windows []time.Duration
+	for i, r := range u.Resets {
+		from := r.ResetsAt.Add(-maxUsageWindow)
+		if i > 0 && u.Resets[i-1].ResetsAt.After(from) {
+			from = u.Resets[i-1].ResetsAt
+		}
+		start, ok := u.firstActivity(from, r.LimitedAt.Add(time.Nanosecond))
+		if !ok {
+			continue
+		}
+		windows = append(windows, r.ResetsAt.Sub(start))
+	}
+	if len(windows) == 0 {
+		return DefaultUsageWindow, false
+	}
+	w := median(windows).Round(usageBucketWidth)
+	return min(max(w, minUsageWindow), maxUsageWindow), true
+}
+
+// Capacity returns the median tokens used in a window before the account
+// hit its limit, or 0 if no limit has been observed with usage data.
+func (u *AccountUsage) Capacity(window time.Duration) int64 {
+	var used []int64
+	for _, r := range u.Resets {
+		// Include the bucket the limit was hit in.
+		tokens, _ := u.usageBetween(r.ResetsAt.Add(-window), r.LimitedAt.Truncate(usageBucketWidth).Add(usageBucketWidth))
+		if tokens > 0 {
+			used = append(used, tokens)
+		}
+	}
+	if len(used) == 0 {
+		return 0
+	}
+	return median(used)
+}
+
+// currentWindow returns the bounds of the window containing now. A window
+// opens with the first request after the previous one closed; with no
+// request since, there is no current window.
+func (u *AccountUsage) currentWindow(now time.Time, window time.Duration) (start, end time.Time, ok bool) {
+	var anchor time.Time
+	if n := len(u.Resets); n > 0 {
+		last := u.Resets[n-1]
+		if last.ResetsAt.After(now) {
+			// Limited right now: the window ends at the reported reset.
+			return last.ResetsAt.Add(-window), last.ResetsAt, true
+		}
+		anchor = last.ResetsAt
+	} else if len(u.Buckets) > 0 {
+		anchor = u.Buckets[0].Start
+	}
+	for {
+		start, ok := u.firstActivity(anchor, now.Add(time.Nanosecond))
+		if !ok {
+			return time.Time{}, time.Time{}, false
+		}
+		if end := start.Add(window); end.After(now) {
+			return start, end, true
+		}
+		anchor = start.Add(window)
+	}
+}
+
+// Forecast is the predicted usage of an account's current window.
+type Forecast struct {
+	Handle         string        `json:"handle"`
+	Window         time.Duration `json:"window"`
+	Learned        bool          `json:"learned"`      // window learned from observed resets
+	Observations   int           `json:"observations"` // observed resets
+	WindowStart    time.Time     `json:"window_start,omitempty"`
+	WindowEnd      time.Time     `json:"window_end,omitempty"`
+	UsedTokens     int64         `json:"used_tokens"`
+	Requests       int           `json:"requests"`
+	CapacityTokens int64         `json:"capacity_tokens,omitempty"` // 0 until a limit is observed
+	BurnPerHour    float64       `json:"burn_per_hour"`             // tokens per hour, recent rate
+	ExhaustsAt     time.Time     `json:"exhausts_at,omitempty"`     // zero unless predicted before WindowEnd
+}
+
+// ForecastUsage predicts when the account's current window runs out at the
+// recent burn rate. u may be nil.
+func ForecastUsage(handle string, u *AccountUsage, now time.Time) Forecast {
+	if u == nil {
+		u = &AccountUsage{}
+	}
+	window, learned := u.Window()
+	f := Forecast{
+		Handle:         handle,
+		Window:         window,
+		Learned:        learned,
+		Observations:   len(u.Resets),
+		CapacityTokens: u.Capacity(window),
+	}
+	start, end, ok := u.currentWindow(now, window)
+	if !ok {
+		return f
+	}
+	f.WindowStart, f.WindowEnd = start, end
+	f.UsedTokens, f.Requests = u.usageBetween(start, now.Add(time.Nanosecond))
+
+	from := now.Add(-burnLookback)
+	if start.After(from) {
+		from = start
+	}
+	span := max(now.Sub(from), minBurnSpan)
+	recent, _ := u.usageBetween(from.Truncate(usageBucketWidth), now.Add(time.Nanosecond))
+	f.BurnPerHour = float64(recent) / span.Hours()
+
+	if f.CapacityTokens > 0 {
+		remaining := f.CapacityTokens - f.UsedTokens
+		switch {
+		case remaining <= 0:
+			f.ExhaustsAt = now
+		case f.BurnPerHour > 0:
+			at := now.Add(time.Duration(float64(remaining) / f.BurnPerHour * float64(time.Hour)))
+			if at.Before(end) {
+				f.ExhaustsAt = at
+			}
+		}
+	}
+	return f
+}
+
+// ExhaustsWithin reports whether the account is predicted to hit its limit
+// within d of now.
+func (f Forecast) ExhaustsWithin(now time.Time, d time.Duration) bool {
+	return !f.ExhaustsAt.IsZero() && !f.ExhaustsAt.After(now.Add(d))
+}
+
+// UsedFraction returns the share of the learned capacity used in the
+// current window, or -1 when the capacity is unknown.
+func (f Forecast) UsedFraction() float64 {
+	if f.CapacityTokens <= 0 {
+		return -1
+	}
+	return float64(f.UsedTokens) / float64(f.CapacityTokens)
+}
+
+// WindowCost returns what an account's sessions cost since start, from the
+// costs ledger. Ledger records are cumulative per session, so each session
+// counts its highest cost since start minus its highest cost before.
+func WindowCost(entries []costs.LedgerEntry, handle string, start time.Time) float64 {
+	before := make(map[string]float64)
+	after := make(map[string]float64)
+	for _, e := range entries {
+		if e.Account != handle {
+			continue
+		}
+		m := after
+		if e.EndedAt.Before(start) {
+			m = before
+		}
+		m[e.SessionID] = max(m[e.SessionID], e.CostUSD)
+	}
+	var total float64
+	for id, cost := range after {
+		total += max(cost-before[id], 0)
+	}
+	return total
+}
+
+func median[T int64 | time.Duration](xs []T) T {
+	sorted := slices.Clone(xs)
+	slices.Sort(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// Reset times as printed in rate-limit messages, e.g. "7pm
+// (America/Los_Angeles)", "3:00 AM PST" or "Oct 20, 5pm (Europe/Berlin)".
+
+var (
+	resetZonePattern  = regexp.MustCompile(`\(([^)]+)\)`)
+	resetSpacePattern = regexp.MustCompile(`\s+`)
+	resetAMPMPattern  = regexp.MustCompile(`\s+(am|pm)\b`)
+)
+
+// resetZoneAbbrevs maps the zone abbreviations providers print to locations.
+var resetZoneAbbrevs = map[string]string{
+	"utc": "UTC", "gmt": "UTC",
+	"pst": "America/Los_Angeles", "pdt": "America/Los_Angeles", "pt": "America/Los_Angeles",
+	"mst": "America/Denver", "mdt": "America/Denver", "mt": "America/Denver",
+	"cst": "America/Chicago", "cdt": "America/Chicago", "ct": "America/Chicago",
+	"est": "America/New_York", "edt": "America/New_York", "et": "America/New_York",
+}
+
+var resetClockLayouts = []string{"3pm", "3:04pm", "15:04"}
+var resetDateLayouts = []string{"Jan 2 ", "Jan 2, ", "2 Jan "}
+
+// ParseResetTime converts a reset time from a rate-limit message into the
+// first matching instant after ref. Times without a zone are in ref's
+// location.
+func ParseResetTime(s string, ref time.Time) (time.Time, bool) {
+	loc := ref.Location()
+	if m := resetZonePattern.FindStringSubmatch(s); m != nil {
+		l, err := time.LoadLocation(strings.TrimSpace(m[1]))
+		if err != nil {
+			return time.Time{}, false
+		}
+		loc = l
+		s = strings.Replace(s, m[0], "", 1)
+	}
+	s = strings.ToLower(strings.TrimSpace(resetSpacePattern.ReplaceAllString(s, " ")))
+	s = strings.TrimPrefix(s, "at ")
+	s = strings.ReplaceAll(s, " at ", " ")
+	s = resetAMPMPattern.ReplaceAllString(s, "$1")
+	if fields := strings.Fields(s); len(fields) > 1 {
+		if name, ok := resetZoneAbbrevs[fields[len(fields)-1]]; ok {
+			l, err := time.LoadLocation(name)
+			if err != nil {
+				return time.Time{}, false
+			}
+			loc = l
+			s = strings.Join(fields[:len(fields)-1], " ")
+		}
+	}
+
+	local := ref.In(loc)
+	for _, clock := range resetClockLayouts {
+		if t, err := time.ParseInLocation(clock, s, loc); err == nil {
+			at := time.Date(local.Year(), local.Month(), local.Day(), t.Hour(), t.Minute(), 0, 0, loc)
+			if !at.After(ref) {
+				at = at.AddDate(0, 0, 1)
+			}
+			return at, true
+		}
+		for _, date := range resetDateLayouts {
+			if t, err := time.ParseInLocation(date+clock, s, loc); err == nil {
+				at := time.Date(local.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc)
+				if at.Before(ref.AddDate(0, 0, -1)) {
+					at = at.AddDate(1, 0, 0)
+				}
+				return at, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,166 @@
+package quota
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/costs"
+)
+
+func TestParseResetTime_Instant(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+	ref := time.Date(2026, 10, 17, 14, 30, 0, 0, la)
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"7pm (America/Los_Angeles)", time.Date(2026, 10, 17, 19, 0, 0, 0, la)},
+		{"1pm (America/Los_Angeles)", time.Date(2026, 10, 18, 13, 0, 0, 0, la)},
+		{"3:00 AM PST", time.Date(2026, 10, 18, 3, 0, 0, 0, la)},
+		{"Oct 20, 5pm (America/Los_Angeles)", time.Date(2026, 10, 20, 17, 0, 0, 0, la)},
+		{"at 18:45", time.Date(2026, 10, 17, 18, 45, 0, 0, la)},
+	}
+	for _, tt := range tests {
+		got, ok := ParseResetTime(tt.in, ref)
+		if !ok || !got.Equal(tt.want) {
+			t.Errorf("ParseResetTime(%q) = %v, %v; want %v", tt.in, got, ok, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "soon", "7pm (Nowhere/Land)"} {
+		if _, ok := ParseResetTime(bad, ref); ok {
+			t.Errorf("ParseResetTime(%q) should fail", bad)
+		}
+	}
+}
+
+// requestsEvery returns n requests of tokens each, spaced by step from start.
+func requestsEvery(start time.Time, step time.Duration, n, tokens int) []costs.Request {
+	var reqs []costs.Request
+	for i := 0; i < n; i++ {
+		reqs = append(reqs, costs.Request{At: start.Add(time.Duration(i) * step), Tokens: costs.Tokens{Output: tokens}})
+	}
+	return reqs
+}
+
+func TestAccountUsage_Add(t *testing.T) {
+	start := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	u := &AccountUsage{}
+	u.Add(requestsEvery(start, time.Minute, 10, 100))
+	// A re-read of the same transcripts must not double count.
+	u.Add(requestsEvery(start, time.Minute, 10, 100))
+
+	if len(u.Buckets) != 2 {
+		t.Fatalf("buckets = %d, want 2", len(u.Buckets))
+	}
+	tokens, requests := u.usageBetween(start, start.Add(time.Hour))
+	if tokens != 1000 || requests != 10 {
+		t.Errorf("usage = %d tokens, %d requests; want 1000, 10", tokens, requests)
+	}
+}
+
+func TestForecastUsage_LearnsWindowAndCapacity(t *testing.T) {
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	u := &AccountUsage{}
+
+	// Yesterday: a window opened at 08:00, ran at 1000 tokens/min and hit
+	// the limit at 10:00 with a reset reported for 12:00 (a 4h window).
+	u.Add(requestsEvery(day.Add(8*time.Hour), time.Minute, 120, 1000))
+	u.ObserveReset(day.Add(10*time.Hour), day.Add(12*time.Hour))
+
+	window, learned := u.Window()
+	if !learned || window != 4*time.Hour {
+		t.Fatalf("Window() = %v, %v; want 4h learned", window, learned)
+	}
+	if c := u.Capacity(window); c < 120000 || c > 125000 {
+		t.Fatalf("Capacity = %d, want about 120000", c)
+	}
+
+	// Today: a window opened at 09:00 and has run for an hour at the same rate.
+	today := day.Add(24 * time.Hour)
+	u.Add(requestsEvery(today.Add(9*time.Hour), time.Minute, 60, 1000))
+	now := today.Add(10 * time.Hour)
+
+	f := ForecastUsage("work", u, now)
+	if !f.WindowStart.Equal(today.Add(9*time.Hour)) || !f.WindowEnd.Equal(today.Add(13*time.Hour)) {
+		t.Errorf("window = %v–%v, want 09:00–13:00", f.WindowStart, f.WindowEnd)
+	}
+	if f.UsedTokens != 60000 || f.Requests != 60 {
+		t.Errorf("used = %d tokens, %d requests; want 60000, 60", f.UsedTokens, f.Requests)
+	}
+	// About 60k tokens left at 60k/hour: exhausted near 11:00, before the reset.
+	if f.ExhaustsAt.IsZero() || f.ExhaustsAt.Before(now.Add(55*time.Minute)) || f.ExhaustsAt.After(now.Add(65*time.Minute)) {
+		t.Errorf("ExhaustsAt = %v, want about an hour from now", f.ExhaustsAt)
+	}
+	if !f.ExhaustsWithin(now, 90*time.Minute) || f.ExhaustsWithin(now, 30*time.Minute) {
+		t.Error("ExhaustsWithin disagrees with ExhaustsAt")
+	}
+}
+
+func TestForecastUsage_NoHistory(t *testing.T) {
+	now := time.Now()
+	f := ForecastUsage("work", nil, now)
+	if f.Learned || f.Window != DefaultUsageWindow || f.CapacityTokens != 0 || !f.ExhaustsAt.IsZero() {
+		t.Errorf("forecast without history = %+v", f)
+	}
+	if f.UsedFraction() != -1 {
+		t.Errorf("UsedFraction = %v, want -1 when capacity is unknown", f.UsedFraction())
+	}
+}
+
+func TestRefreshUsage_ReadsTranscriptsAndResets(t *testing.T) {
+	configDir := t.TempDir()
+	projDir := filepath.Join(configDir, "projects", "-work")
+	if err := os.MkdirAll(projDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	at := now.Add(-10 * time.Minute).Format(time.RFC3339)
+	transcript := `{"type":"assistant","timestamp":"` + at + `","requestId":"req_1","message":{"model":"claude-sonnet-4","usage":{"input_tokens":10,"output_tokens":90}}}
+{"type":"assistant","timestamp":"` + at + `","requestId":"req_1","message":{"model":"claude-sonnet-4","usage":{"input_tokens":10,"output_tokens":90}}}
+`
+	if err := os.WriteFile(filepath.Join(projDir, "s.jsonl"), []byte(transcript), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	accounts := &config.AccountsConfig{Accounts: map[string]config.Account{"work": {ConfigDir: configDir}}}
+	quotaState := &config.QuotaState{Accounts: map[string]config.AccountQuotaState{
+		"work": {Status: config.QuotaStatusLimited, LimitedAt: now.Format(time.RFC3339), ResetsAt: now.Add(2 * time.Hour).Format("15:04")},
+	}}
+	usage := &UsageState{Accounts: map[string]*AccountUsage{"gone": {}}}
+
+	if errs := RefreshUsage(usage, accounts, quotaState, now); len(errs) > 0 {
+		t.Fatalf("RefreshUsage errors: %v", errs)
+	}
+	if _, ok := usage.Accounts["gone"]; ok {
+		t.Error("unregistered account should be dropped")
+	}
+	u := usage.Accounts["work"]
+	tokens, requests := u.usageBetween(now.Add(-time.Hour), now)
+	if tokens != 100 || requests != 1 {
+		t.Errorf("usage = %d tokens, %d requests; want 100, 1 (duplicate lines count once)", tokens, requests)
+	}
+	if len(u.Resets) != 1 {
+		t.Errorf("resets = %d, want 1", len(u.Resets))
+	}
+}
+
+func TestWindowCost(t *testing.T) {
+	start := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	entries := []costs.LedgerEntry{
+		{SessionID: "a", Account: "work", CostUSD: 2, EndedAt: start.Add(-time.Hour)},
+		{SessionID: "a", Account: "work", CostUSD: 5, EndedAt: start.Add(time.Hour)},
+		{SessionID: "b", Account: "work", CostUSD: 1, EndedAt: start.Add(time.Hour)},
+		{SessionID: "c", Account: "personal", CostUSD: 9, EndedAt: start.Add(time.Hour)},
+	}
+	if got := WindowCost(entries, "work", start); got != 4 {
+		t.Errorf("WindowCost = %v, want 4", got)
+	}
+}
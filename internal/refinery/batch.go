@@ -0,0 +1,504 @@
+package refinery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// Speculative batch merging (the bors / merge-train model).
+//
+// With BatchSize > 1 the Engineer takes the top MRs by score, lands them one
+// after another (with the rig's merge strategy) onto the current target in a
+// scratch worktree, and gates the resulting stack instead of each MR in turn.
+// A green stack lands in a single push. A red stack is bisected over its
+// prefixes to find the first MR whose addition breaks the gates: the MRs
+// before it land, it is failed back to its worker, and the MRs after it
+// return to the queue for the next batch.
+//
+// Each gated prefix gets its own scratch worktree, so up to MaxConcurrent
+// prefixes are gated at once. The target itself is assumed to be green.
+
+// BatchOutcome is what happened to one MR in a speculative batch.
+type BatchOutcome string
+
+const (
+	// BatchMerged means the MR landed on the target.
+	BatchMerged BatchOutcome = "merged"
+
+	// BatchFailed means the MR itself is at fault (conflict with the target,
+	// missing branch, or the culprit of a gate failure).
+	BatchFailed BatchOutcome = "failed"
+
+	// BatchDeferred means no verdict was reached for the MR this round
+	// (it conflicted with another MR in the batch, was stacked after the
+	// culprit, or the push failed). It should be released to the queue.
+	BatchDeferred BatchOutcome = "deferred"
+)
+
+// BatchItem is the outcome for one MR in a batch.
+type BatchItem struct {
+	MR      *MRInfo
+	Outcome BatchOutcome
+	// Result carries the merge commit for merged MRs and the reason for
+	// failed and deferred ones.
+	Result ProcessResult
+}
+
+// BatchResult summarizes a ProcessBatch run.
+type BatchResult struct {
+	Target   string       // Target branch of every MR in the batch
+	Base     string       // Target commit the stack was built on
+	Head     string       // Commit pushed to the target ("" if nothing landed)
+	Items    []*BatchItem // One per input MR, in input order
+	GateRuns int          // Number of stack prefixes gated
+}
+
+// Count returns the number of items with the given outcome.
+func (r *BatchResult) Count(outcome BatchOutcome) int {
+	n := 0
+	for _, item := range r.Items {
+		if item.Outcome == outcome {
+			n++
+		}
+	}
+	return n
+}
+
+// stackEntry is one MR landed onto the speculative stack.
+type stackEntry struct {
+	idx  int    // Index of the MR in the batch
+	head string // Stack commit after this MR was merged
+}
+
+// BatchSize returns the number of MRs to stack per batch (1 when batch mode is off).
+func (e *Engineer) BatchSize() int {
+	if e.config.BatchSize > 1 {
+		return e.config.BatchSize
+	}
+	return 1
+}
+
+// SelectBatch orders mrs by score, highest first, and returns up to size of
+// them. Only MRs targeting the same branch as the top MR are included, since a
+// stack is built on a single target. PR-strategy MRs are left out: they land
+// through their own pull requests ('gt mq pr').
+func SelectBatch(mrs []*MRInfo, size int, scorer *Scorer) []*MRInfo {
+	if size < 1 {
+		return nil
+	}
+	var local []*MRInfo
+	for _, mr := range mrs {
+		if mr.MergeStrategy != MergeStrategyPR {
+			local = append(local, mr)
+		}
+	}
+	if len(local) == 0 {
+		return nil
+	}
+	sorted := scorer.Rank(local)
+
+	target := sorted[0].Target
+	var batch []*MRInfo
+	for _, mr := range sorted {
+		if mr.Target != target {
+			continue
+		}
+		batch = append(batch, mr)
+		if len(batch) == size {
+			break
+		}
+	}
+	return batch
+}
+
+// ProcessBatch merges a batch of MRs that share a target branch, as selected
+// by SelectBatch. A single MR takes the regular ProcessMRInfo path.
+//
+// The caller settles each item: HandleMRInfoSuccess for merged MRs,
+// HandleMRInfoFailure for failed ones, and ReleaseMR for deferred ones.
+func (e *Engineer) ProcessBatch(ctx context.Context, mrs []*MRInfo) *BatchResult {
+	result := &BatchResult{}
+	if len(mrs) == 0 {
+		return result
+	}
+	target := mrs[0].Target
+	result.Target = target
+	result.Items = make([]*BatchItem, len(mrs))
+	for i, mr := range mrs {
+		result.Items[i] = &BatchItem{MR: mr, Outcome: BatchDeferred}
+	}
+
+	if len(mrs) == 1 {
+		r := e.ProcessMRInfo(ctx, mrs[0])
+		result.Items[0].Result = r
+		switch {
+		case r.Success:
+			result.Items[0].Outcome = BatchMerged
+			result.Head = r.MergeCommit
+		case r.Pending:
+			// Still waiting on its pull request; stays deferred.
+		default:
+			result.Items[0].Outcome = BatchFailed
+		}
+		return result
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Speculative batch: %d MR(s) into %s\n", len(mrs), target)
+	for i, mr := range mrs {
+		if mr.Target != target {
+			result.deferItem(i, fmt.Sprintf("targets %s, batch targets %s", mr.Target, target))
+		}
+	}
+
+	if err := e.git.FetchBranch("origin", target); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: fetch origin/%s: %v (continuing)\n", target, err)
+	}
+	base, err := e.git.Rev("origin/" + target)
+	if err != nil {
+		result.deferAll(fmt.Sprintf("failed to resolve origin/%s: %v", target, err))
+		return result
+	}
+	result.Base = base
+
+	scratch, err := os.MkdirTemp("", "gt-batch-"+e.rig.Name+"-")
+	if err != nil {
+		result.deferAll(fmt.Sprintf("failed to create scratch directory: %v", err))
+		return result
+	}
+	defer e.removeScratch(scratch)
+
+	stack := e.buildStack(scratch, base, result)
+	if len(stack) == 0 {
+		return result
+	}
+
+	good, bad, failure, err := e.gateStack(ctx, scratch, stack, result)
+	if err != nil {
+		for _, entry := range stack {
+			result.deferItem(entry.idx, err.Error())
+		}
+		return result
+	}
+	if bad > 0 {
+		culprit := stack[bad-1]
+		result.failItem(culprit.idx, failure)
+		for _, entry := range stack[bad:] {
+			result.deferItem(entry.idx, fmt.Sprintf("stacked after failing MR %s; will be re-gated without it", result.Items[culprit.idx].MR.ID))
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Bisected gate failure to %s (%s)\n",
+			result.Items[culprit.idx].MR.ID, result.Items[culprit.idx].MR.Branch)
+	}
+
+	e.landStack(ctx, stack[:good], result)
+	return result
+}
+
+// buildStack lands the batch onto base in a scratch worktree and
+// returns the MRs that stacked cleanly, with the stack commit after each.
+// MRs that could not be stacked are marked failed or deferred in result.
+func (e *Engineer) buildStack(scratch, base string, result *BatchResult) []stackEntry {
+	dir := filepath.Join(scratch, "stack")
+	if err := e.git.WorktreeAddDetached(dir, base); err != nil {
+		result.deferAll(fmt.Sprintf("failed to create stack worktree: %v", err))
+		return nil
+	}
+	g := git.NewGit(dir)
+
+	var stack []stackEntry
+	for i, item := range result.Items {
+		mr := item.MR
+		if mr.Target != result.Target {
+			continue
+		}
+
+		exists, err := e.git.BranchExists(mr.Branch)
+		if err != nil {
+			result.failItem(i, ProcessResult{Error: fmt.Sprintf("failed to check branch %s: %v", mr.Branch, err)})
+			continue
+		}
+		if !exists {
+			result.failItem(i, ProcessResult{Error: fmt.Sprintf("branch %s not found locally", mr.Branch)})
+			continue
+		}
+
+		if conflicts, err := e.landBranch(g, mr); err != nil {
+			switch {
+			case errors.Is(err, errNotFastForward) && len(stack) == 0:
+				result.failItem(i, ProcessResult{Conflict: true, Error: fmt.Sprintf("branch %s does not fast-forward %s (merge_strategy ff-only): rebase required", mr.Branch, mr.Target)})
+			case errors.Is(err, errNotFastForward):
+				result.deferItem(i, "does not fast-forward earlier MRs in the batch")
+			case len(conflicts) > 0 && len(stack) == 0:
+				result.failItem(i, ProcessResult{Conflict: true, Error: fmt.Sprintf("merge conflicts in: %v", conflicts)})
+			case len(conflicts) > 0:
+				// Conflicts only with MRs ahead of it in the batch: retry
+				// against the target once they have landed.
+				result.deferItem(i, fmt.Sprintf("conflicts with earlier MRs in the batch: %v", conflicts))
+			default:
+				result.failItem(i, ProcessResult{Error: fmt.Sprintf("merge failed: %v", err)})
+			}
+			continue
+		}
+
+		head, err := g.Rev("HEAD")
+		if err != nil {
+			result.deferItem(i, fmt.Sprintf("failed to get stack commit: %v", err))
+			continue
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Stacked %s (%s) at %s\n", mr.ID, mr.Branch, head[:8])
+		stack = append(stack, stackEntry{idx: i, head: head})
+	}
+	return stack
+}
+
+// hasGates reports whether any quality gate or legacy test command is configured.
+func (e *Engineer) hasGates() bool {
+	return len(e.config.Gates) > 0 || (e.config.RunTests && e.config.TestCommand != "")
+}
+
+// runGatesOrTestsIn runs the configured quality gates (or legacy test command) in dir.
+func (e *Engineer) runGatesOrTestsIn(ctx context.Context, dir string) ProcessResult {
+	if len(e.config.Gates) > 0 {
+		return e.runGatesIn(ctx, dir)
+	}
+	if e.config.RunTests && e.config.TestCommand != "" {
+		result := e.runTestsIn(ctx, dir)
+		if !result.Success {
+			return ProcessResult{Success: false, TestsFailed: true, Error: result.Error}
+		}
+		return result
+	}
+	return ProcessResult{Success: true}
+}
+
+// gateStack finds the longest green prefix of the stack. It returns the
+// number of MRs that can land (good), the 1-based stack position of the
+// culprit (bad, 0 if the whole stack is green) and the culprit's gate result.
+//
+// Prefix 0 (the target) is assumed green. Until a failure is seen the full
+// stack is always gated; after that, probes split the interval between the
+// longest known-green and shortest known-red prefix, which is plain bisection
+// when MaxConcurrent is 1.
+func (e *Engineer) gateStack(ctx context.Context, scratch string, stack []stackEntry, result *BatchResult) (good, bad int, failure ProcessResult, err error) {
+	n := len(stack)
+	if !e.hasGates() {
+		return n, 0, ProcessResult{}, nil
+	}
+	width := e.config.MaxConcurrent
+	if width < 1 {
+		width = 1
+	}
+
+	results := make(map[int]ProcessResult)
+	lo, hi := 0, 0 // longest green prefix, shortest red prefix (0 = none seen)
+	for {
+		probes := nextProbes(lo, hi, n, width)
+		if len(probes) == 0 {
+			break
+		}
+		probed, err := e.gatePrefixes(ctx, scratch, stack, probes)
+		if err != nil {
+			return 0, 0, ProcessResult{}, err
+		}
+		for p, r := range probed {
+			results[p] = r
+		}
+		result.GateRuns += len(probes)
+		if ctx.Err() != nil {
+			return 0, 0, ProcessResult{}, fmt.Errorf("batch gating canceled: %w", ctx.Err())
+		}
+
+		for _, p := range probes {
+			if !results[p].Success && (hi == 0 || p < hi) {
+				hi = p
+			}
+		}
+		// Flaky or order-dependent gates can leave a green prefix above a red
+		// one; trust the shortest red prefix and the longest green below it.
+		lo = 0
+		for p, r := range results {
+			if r.Success && p > lo && (hi == 0 || p < hi) {
+				lo = p
+			}
+		}
+	}
+
+	if hi == 0 {
+		return n, 0, ProcessResult{}, nil
+	}
+	return lo, hi, results[hi], nil
+}
+
+// nextProbes picks up to width stack prefixes to gate next, given the longest
+// known-green prefix lo and the shortest known-red prefix hi (0 if none).
+// Probes are returned in ascending order; nil means the search is done.
+func nextProbes(lo, hi, n, width int) []int {
+	var probes []int
+	add := func(p int) {
+		if p <= lo || (hi != 0 && p >= hi) {
+			return
+		}
+		if len(probes) > 0 && probes[len(probes)-1] == p {
+			return
+		}
+		probes = append(probes, p)
+	}
+
+	if hi == 0 {
+		// No failure yet: spread the probes up to and including the full stack.
+		span := n - lo
+		for j := 1; j <= width && span > 0; j++ {
+			add(lo + (j*span+width-1)/width)
+		}
+		return probes
+	}
+	span := hi - lo
+	for j := 1; j <= width; j++ {
+		add(lo + j*span/(width+1))
+	}
+	return probes
+}
+
+// gatePrefixes gates the given stack prefixes concurrently, each in its own
+// scratch worktree. A worktree that can't be created is an infrastructure
+// failure, not a gate failure: it is returned as an error so the batch is
+// deferred instead of blaming an MR.
+func (e *Engineer) gatePrefixes(ctx context.Context, scratch string, stack []stackEntry, probes []int) (map[int]ProcessResult, error) {
+	results := make(map[int]ProcessResult, len(probes))
+	dirs := make(map[int]string, len(probes))
+	removeAll := func() {
+		for _, dir := range dirs {
+			if err := e.git.WorktreeRemove(dir, true); err != nil {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to remove scratch worktree %s: %v\n", dir, err)
+			}
+		}
+	}
+
+	// Worktrees are created one at a time: git serializes writes to the
+	// shared repository's worktree metadata anyway.
+	for _, p := range probes {
+		dir := filepath.Join(scratch, fmt.Sprintf("prefix-%d", p))
+		if err := e.git.WorktreeAddDetached(dir, stack[p-1].head); err != nil {
+			removeAll()
+			return nil, fmt.Errorf("failed to create worktree for stack prefix %d: %w", p, err)
+		}
+		dirs[p] = dir
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for p, dir := range dirs {
+		wg.Add(1)
+		go func(p int, dir string) {
+			defer wg.Done()
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Gating stack prefix %d/%d (%s)\n", p, len(stack), stack[p-1].head[:8])
+			r := e.runGatesOrTestsIn(ctx, dir)
+			mu.Lock()
+			results[p] = r
+			mu.Unlock()
+		}(p, dir)
+	}
+	wg.Wait()
+
+	removeAll()
+	return results, nil
+}
+
+// landStack pushes the top of the landable stack to the target and marks
+// its MRs merged. Submodule commits are pushed first, as in doMerge.
+func (e *Engineer) landStack(ctx context.Context, landable []stackEntry, result *BatchResult) {
+	for i, entry := range landable {
+		mr := result.Items[entry.idx].MR
+		if err := e.pushSubmoduleChanges(result.Base, mr.Branch); err != nil {
+			result.failItem(entry.idx, ProcessResult{Error: err.Error()})
+			for _, rest := range landable[i+1:] {
+				result.deferItem(rest.idx, fmt.Sprintf("stacked after %s, whose submodules failed to push", mr.ID))
+			}
+			landable = landable[:i]
+			break
+		}
+	}
+	if len(landable) == 0 {
+		return
+	}
+	head := landable[len(landable)-1].head
+
+	// Serialize pushes to the default branch, as in doMerge.
+	if result.Target == e.rig.DefaultBranch() {
+		pushHolder, slotErr := e.acquireMainPushSlot(ctx)
+		if slotErr != nil {
+			for _, entry := range landable {
+				result.deferItem(entry.idx, fmt.Sprintf("failed to acquire merge slot before push: %v", slotErr))
+			}
+			return
+		}
+		defer func() {
+			// pushHolder is empty when the self-conflict bypass fires.
+			if pushHolder != "" {
+				if releaseErr := e.mergeSlotRelease(pushHolder); releaseErr != nil {
+					_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to release merge slot for push (%s): %v\n", pushHolder, releaseErr)
+				}
+			}
+		}()
+	}
+
+	// Pushing the stack commit is a fast-forward of the target only if nobody
+	// else pushed since we fetched; otherwise the push is rejected and the
+	// whole batch is retried against the new target.
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing %d stacked MR(s) to origin/%s...\n", len(landable), result.Target)
+	if err := e.git.Push("origin", head+":refs/heads/"+result.Target, false); err != nil {
+		for _, entry := range landable {
+			result.deferItem(entry.idx, fmt.Sprintf("failed to push to origin: %v", err))
+		}
+		return
+	}
+	if err := e.git.FetchBranch("origin", result.Target); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: fetch origin/%s after push: %v\n", result.Target, err)
+	}
+
+	result.Head = head
+	for _, entry := range landable {
+		item := result.Items[entry.idx]
+		item.Outcome = BatchMerged
+		item.Result = ProcessResult{Success: true, MergeCommit: entry.head}
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Successfully merged %d MR(s): %s\n", len(landable), head[:8])
+	e.syncCrewWorkspaces(result.Target)
+}
+
+// removeScratch removes the batch's scratch worktrees and directory.
+func (e *Engineer) removeScratch(scratch string) {
+	entries, _ := os.ReadDir(scratch)
+	for _, entry := range entries {
+		dir := filepath.Join(scratch, entry.Name())
+		if err := e.git.WorktreeRemove(dir, true); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to remove scratch worktree %s: %v\n", dir, err)
+		}
+	}
+	_ = os.RemoveAll(scratch)
+	_ = e.git.WorktreePrune()
+}
+
+func (r *BatchResult) failItem(i int, pr ProcessResult) {
+	pr.Success = false
+	r.Items[i].Outcome = BatchFailed
+	r.Items[i].Result = pr
+}
+
+func (r *BatchResult) deferItem(i int, reason string) {
+	r.Items[i].Outcome = BatchDeferred
+	r.Items[i].Result = ProcessResult{Error: reason}
+}
+
+func (r *BatchResult) deferAll(reason string) {
+	for i, item := range r.Items {
+		if item.Outcome == BatchDeferred && item.Result.Error == "" {
+			r.deferItem(i, reason)
+		}
+	}
+}
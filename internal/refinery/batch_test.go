@@ -0,0 +1,254 @@
+package refinery
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestNextProbes(t *testing.T) {
+	tests := []struct {
+		name         string
+		lo, hi, n, w int
+		want         []int
+	}{
+		{"full stack first", 0, 0, 8, 1, []int{8}},
+		{"spread up to full stack", 0, 0, 8, 3, []int{3, 6, 8}},
+		{"bisect", 0, 8, 8, 1, []int{4}},
+		{"bisect upper half", 4, 8, 8, 1, []int{6}},
+		{"k-ary split", 0, 8, 8, 3, []int{2, 4, 6}},
+		{"narrow interval dedupes", 0, 2, 8, 3, []int{1}},
+		{"culprit found", 3, 4, 8, 2, nil},
+		{"all green", 8, 0, 8, 2, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nextProbes(tt.lo, tt.hi, tt.n, tt.w)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("nextProbes(%d, %d, %d, %d) = %v, want %v", tt.lo, tt.hi, tt.n, tt.w, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectBatch(t *testing.T) {
+	now := time.Now()
+	mrs := []*MRInfo{
+		{ID: "low", Target: "main", Priority: 4, CreatedAt: now},
+		{ID: "other-target", Target: "integration/x", Priority: 0, CreatedAt: now.Add(-time.Hour)},
+		{ID: "high", Target: "integration/x", Priority: 1, CreatedAt: now},
+		{ID: "mid", Target: "integration/x", Priority: 2, CreatedAt: now},
+	}
+
+	got := SelectBatch(mrs, 2, NewScorer(DefaultScoreConfig(), now))
+	var ids []string
+	for _, mr := range got {
+		ids = append(ids, mr.ID)
+	}
+	if want := []string{"other-target", "high"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("SelectBatch = %v, want %v", ids, want)
+	}
+	if SelectBatch(nil, 4, NewScorer(DefaultScoreConfig(), now)) != nil {
+		t.Error("SelectBatch(nil) should be nil")
+	}
+}
+
+// batchTestRig sets up an origin repo and a refinery clone with one polecat
+// branch per entry in files (branch name → file it adds).
+func batchTestRig(t *testing.T, files map[string]string) (*Engineer, string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("batch tests use sh gate commands")
+	}
+	tmp := t.TempDir()
+	origin := filepath.Join(tmp, "origin.git")
+	work := filepath.Join(tmp, "refinery")
+
+	run := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run(tmp, "init", "--bare", "-b", "main", origin)
+	run(tmp, "clone", origin, work)
+	run(work, "config", "user.email", "t@t")
+	run(work, "config", "user.name", "t")
+	run(work, "checkout", "-b", "main")
+	if err := os.WriteFile(filepath.Join(work, "README.md"), []byte("# test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run(work, "add", ".")
+	run(work, "commit", "-m", "initial")
+	run(work, "push", "origin", "main")
+
+	for branch, file := range files {
+		run(work, "checkout", "-b", branch, "main")
+		if err := os.WriteFile(filepath.Join(work, file), []byte(branch+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		run(work, "add", ".")
+		run(work, "commit", "-m", "feat: "+branch)
+	}
+	run(work, "checkout", "main")
+
+	e := &Engineer{
+		rig:     &rig.Rig{Name: "testrig", Path: tmp},
+		git:     git.NewGit(work),
+		config:  DefaultMergeQueueConfig(),
+		workDir: work,
+		output:  io.Discard,
+		mergeSlotEnsureExists: func() (string, error) {
+			return "merge-slot", nil
+		},
+		mergeSlotAcquire: func(holder string, _ bool) (*beads.MergeSlotStatus, error) {
+			return &beads.MergeSlotStatus{Available: true, Holder: holder}, nil
+		},
+		mergeSlotRelease: func(string) error { return nil },
+	}
+	e.config.BatchSize = len(files)
+	return e, origin
+}
+
+func batchOutcomes(r *BatchResult) map[string]BatchOutcome {
+	out := make(map[string]BatchOutcome, len(r.Items))
+	for _, item := range r.Items {
+		out[item.MR.ID] = item.Outcome
+	}
+	return out
+}
+
+func originFiles(t *testing.T, origin string) string {
+	t.Helper()
+	out, err := exec.Command("git", "--git-dir", origin, "ls-tree", "--name-only", "main").Output()
+	if err != nil {
+		t.Fatalf("ls-tree: %v", err)
+	}
+	return strings.Join(strings.Fields(string(out)), " ")
+}
+
+func TestProcessBatch_GreenStackLandsInOnePush(t *testing.T) {
+	e, origin := batchTestRig(t, map[string]string{"polecat/a": "a.txt", "polecat/b": "b.txt", "polecat/c": "c.txt"})
+	e.config.Gates = map[string]*GateConfig{"check": {Cmd: "test -f README.md"}}
+
+	mrs := []*MRInfo{
+		{ID: "mr-a", Branch: "polecat/a", Target: "main"},
+		{ID: "mr-b", Branch: "polecat/b", Target: "main"},
+		{ID: "mr-c", Branch: "polecat/c", Target: "main"},
+	}
+	result := e.ProcessBatch(context.Background(), mrs)
+
+	for id, outcome := range batchOutcomes(result) {
+		if outcome != BatchMerged {
+			t.Errorf("%s: outcome %s, want merged", id, outcome)
+		}
+	}
+	if result.GateRuns != 1 {
+		t.Errorf("GateRuns = %d, want 1 (full stack only)", result.GateRuns)
+	}
+	if got := originFiles(t, origin); got != "README.md a.txt b.txt c.txt" {
+		t.Errorf("origin/main files = %q", got)
+	}
+	if result.Head == "" || result.Items[2].Result.MergeCommit != result.Head {
+		t.Errorf("Head = %q, last merge commit = %q", result.Head, result.Items[2].Result.MergeCommit)
+	}
+}
+
+func TestProcessBatch_BisectsToCulprit(t *testing.T) {
+	for _, width := range []int{1, 3} {
+		e, origin := batchTestRig(t, map[string]string{
+			"polecat/a": "a.txt", "polecat/b": "b.txt", "polecat/bad": "BROKEN", "polecat/d": "d.txt", "polecat/e": "e.txt",
+		})
+		e.config.MaxConcurrent = width
+		e.config.Gates = map[string]*GateConfig{"check": {Cmd: "test ! -f BROKEN"}}
+
+		mrs := []*MRInfo{
+			{ID: "mr-a", Branch: "polecat/a", Target: "main"},
+			{ID: "mr-b", Branch: "polecat/b", Target: "main"},
+			{ID: "mr-bad", Branch: "polecat/bad", Target: "main"},
+			{ID: "mr-d", Branch: "polecat/d", Target: "main"},
+			{ID: "mr-e", Branch: "polecat/e", Target: "main"},
+		}
+		result := e.ProcessBatch(context.Background(), mrs)
+
+		want := map[string]BatchOutcome{
+			"mr-a": BatchMerged, "mr-b": BatchMerged, "mr-bad": BatchFailed, "mr-d": BatchDeferred, "mr-e": BatchDeferred,
+		}
+		if got := batchOutcomes(result); !reflect.DeepEqual(got, want) {
+			t.Errorf("width %d: outcomes = %v, want %v", width, got, want)
+		}
+		if !result.Items[2].Result.TestsFailed {
+			t.Errorf("width %d: culprit result = %+v, want TestsFailed", width, result.Items[2].Result)
+		}
+		if got := originFiles(t, origin); got != "README.md a.txt b.txt" {
+			t.Errorf("width %d: origin/main files = %q", width, got)
+		}
+	}
+}
+
+func TestProcessBatch_ConflictWithinBatchIsDeferred(t *testing.T) {
+	e, origin := batchTestRig(t, map[string]string{"polecat/a": "same.txt", "polecat/b": "same.txt"})
+
+	mrs := []*MRInfo{
+		{ID: "mr-a", Branch: "polecat/a", Target: "main"},
+		{ID: "mr-b", Branch: "polecat/b", Target: "main"},
+		{ID: "mr-gone", Branch: "polecat/missing", Target: "main"},
+	}
+	result := e.ProcessBatch(context.Background(), mrs)
+
+	want := map[string]BatchOutcome{"mr-a": BatchMerged, "mr-b": BatchDeferred, "mr-gone": BatchFailed}
+	if got := batchOutcomes(result); !reflect.DeepEqual(got, want) {
+		t.Errorf("outcomes = %v, want %v", got, want)
+	}
+	if got := originFiles(t, origin); got != "README.md same.txt" {
+		t.Errorf("origin/main files = %q", got)
+	}
+
+	// Scratch worktrees are cleaned up.
+	worktrees, err := e.git.WorktreeList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(worktrees) != 1 {
+		t.Errorf("worktrees after batch = %d, want 1", len(worktrees))
+	}
+}
+
+func TestGateStack_WorktreeFailureIsNotAGateFailure(t *testing.T) {
+	e, _ := batchTestRig(t, map[string]string{"polecat/a": "a.txt"})
+	e.config.Gates = map[string]*GateConfig{"check": {Cmd: "true"}}
+	head, err := e.git.Rev("polecat/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A regular file where the scratch directory should be makes every
+	// worktree add fail.
+	scratch := filepath.Join(t.TempDir(), "scratch")
+	if err := os.WriteFile(scratch, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	result := &BatchResult{Items: []*BatchItem{{MR: &MRInfo{ID: "mr-a"}}}}
+	_, bad, _, err := e.gateStack(context.Background(), scratch, []stackEntry{{idx: 0, head: head}}, result)
+	if err == nil {
+		t.Fatal("gateStack succeeded without a scratch worktree")
+	}
+	if bad != 0 {
+		t.Errorf("culprit = %d, want none", bad)
+	}
+}
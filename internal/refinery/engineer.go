@@ -18,12 +18,15 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/forge"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/protocol"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/telemetry"
 )
 
 // DefaultStaleClaimTimeout is the default duration after which a claimed MR
@@ -78,6 +81,10 @@ type MergeQueueConfig struct {
 	// OnConflict is the strategy for handling conflicts: "assign_back" or "auto_rebase".
 	OnConflict string `json:"on_conflict"`
 
+	// MergeStrategy is how branches land on their target: "merge-commit",
+	// "squash", "rebase-ff" or "ff-only". See landBranch.
+	MergeStrategy string `json:"merge_strategy"`
+
 	// RunTests controls whether to run tests before merging.
 	RunTests bool `json:"run_tests"`
 
@@ -94,8 +101,13 @@ type MergeQueueConfig struct {
 	PollInterval time.Duration `json:"poll_interval"`
 
 	// MaxConcurrent is the maximum number of MRs to process concurrently.
+	// In batch mode it bounds how many stack prefixes are gated at once.
 	MaxConcurrent int `json:"max_concurrent"`
 
+	// BatchSize enables speculative batch merging when greater than 1.
+	// See ProcessBatch.
+	BatchSize int `json:"batch_size"`
+
 	// StaleClaimTimeout is how long a claimed MR can go without updates before
 	// being considered abandoned and eligible for re-claim. This handles the
 	// case where a refinery crashes mid-merge, leaving an MR permanently claimed.
@@ -119,6 +131,7 @@ func DefaultMergeQueueConfig() *MergeQueueConfig {
 	return &MergeQueueConfig{
 		Enabled:              true,
 		OnConflict:           "assign_back",
+		MergeStrategy:        config.MergeStrategySquash,
 		RunTests:             true,
 		TestCommand:          "",
 		DeleteMergedBranches: true,
@@ -146,6 +159,9 @@ type MRInfo struct {
 	ConvoyCreatedAt *time.Time // Convoy creation time
 	CreatedAt       time.Time  // MR creation time
 	BlockedBy       string     // Task ID blocking this MR
+	Expedite        bool       // Labeled for expedited merging (see ExpediteLabels)
+	MergeStrategy   string     // "pr" lands through a forge pull request; empty merges locally
+	PRNumber        int        // Forge pull request number once opened
 
 	// Raw data for agent-side queue health analysis (ZFC: agent decides, Go transports)
 	UpdatedAt          time.Time // When the MR was last updated
@@ -181,11 +197,24 @@ var errMergeSlotTimeout = errors.New("merge slot contention timeout")
 // resolution can cause identical timestamps across concurrent goroutines.
 var mergeSlotSeq uint64
 
+// engineerBeads is the beads storage the Engineer uses. *beads.Beads is the
+// production backend; tests use *beads.MemoryStore.
+type engineerBeads interface {
+	beads.Store
+	UpdateAgentActiveMR(id, activeMR string) error
+}
+
+// newTownBeads returns the town-level beads storage the Engineer uses for
+// convoys. Tests replace it to run against a *beads.MemoryStore.
+var newTownBeads = func(townRoot string) beads.Store {
+	return beads.New(townRoot)
+}
+
 // Engineer is the merge queue processor that polls for ready merge-requests
 // and processes them according to the merge queue design.
 type Engineer struct {
 	rig                   *rig.Rig
-	beads                 *beads.Beads
+	beads                 engineerBeads
 	git                   *git.Git
 	config                *MergeQueueConfig
 	workDir               string
@@ -196,6 +225,10 @@ type Engineer struct {
 	mergeSlotRelease      func(holder string) error
 	mergeSlotMaxRetries   int           // Max retries for slot acquisition (0 = no retry)
 	mergeSlotRetryBackoff time.Duration // Initial backoff between retries
+
+	// Forge for PR-strategy MRs, loaded from rig settings on first use.
+	forge            forge.Forge
+	forgeMergeMethod string
 }
 
 // NewEngineer creates a new Engineer for the given rig.
@@ -269,12 +302,14 @@ func (e *Engineer) LoadConfig() error {
 	var mqRaw struct {
 		Enabled              *bool                      `json:"enabled"`
 		OnConflict           *string                    `json:"on_conflict"`
+		MergeStrategy        *string                    `json:"merge_strategy"`
 		RunTests             *bool                      `json:"run_tests"`
 		TestCommand          *string                    `json:"test_command"`
 		DeleteMergedBranches *bool                      `json:"delete_merged_branches"`
 		RetryFlakyTests      *int                       `json:"retry_flaky_tests"`
 		PollInterval         *string                    `json:"poll_interval"`
 		MaxConcurrent        *int                       `json:"max_concurrent"`
+		BatchSize            *int                       `json:"batch_size"`
 		StaleClaimTimeout    *string                    `json:"stale_claim_timeout"`
 		Gates                map[string]*gateConfigRaw  `json:"gates"`
 		GatesParallel        *bool                      `json:"gates_parallel"`
@@ -291,6 +326,12 @@ func (e *Engineer) LoadConfig() error {
 	if mqRaw.OnConflict != nil {
 		e.config.OnConflict = *mqRaw.OnConflict
 	}
+	if mqRaw.MergeStrategy != nil {
+		if !config.IsValidMergeStrategy(*mqRaw.MergeStrategy) {
+			return fmt.Errorf("invalid merge_strategy %q: want merge-commit, squash, rebase-ff or ff-only", *mqRaw.MergeStrategy)
+		}
+		e.config.MergeStrategy = *mqRaw.MergeStrategy
+	}
 	if mqRaw.RunTests != nil {
 		e.config.RunTests = *mqRaw.RunTests
 	}
@@ -306,6 +347,12 @@ func (e *Engineer) LoadConfig() error {
 	if mqRaw.MaxConcurrent != nil {
 		e.config.MaxConcurrent = *mqRaw.MaxConcurrent
 	}
+	if mqRaw.BatchSize != nil {
+		if *mqRaw.BatchSize < 0 {
+			return fmt.Errorf("batch_size must be non-negative, got %d", *mqRaw.BatchSize)
+		}
+		e.config.BatchSize = *mqRaw.BatchSize
+	}
 	if mqRaw.PollInterval != nil {
 		dur, err := time.ParseDuration(*mqRaw.PollInterval)
 		if err != nil {
@@ -369,10 +416,40 @@ type ProcessResult struct {
 	Conflict    bool
 	TestsFailed bool
 	SlotTimeout bool // Merge slot contention timeout (distinct from build/test failure)
+
+	// Pending means the MR is waiting on its forge pull request (checks
+	// running, review outstanding). It is neither merged nor failed and
+	// stays in the queue.
+	Pending bool
+
+	// ReviewRework means reviewers asked for changes on the pull request.
+	// Reviews holds the feedback to hand back to the worker.
+	ReviewRework bool
+	Reviews      []forge.Review
+}
+
+// FailureType categorizes the result. Failures other than conflicts and
+// test failures (fetch, checkout, push, gate errors) count as build failures.
+func (r ProcessResult) FailureType() FailureType {
+	switch {
+	case r.Success:
+		return FailureNone
+	case r.Pending:
+		return FailureNone
+	case r.Conflict:
+		return FailureConflict
+	case r.ReviewRework:
+		return FailureReview
+	case r.TestsFailed:
+		return FailureTestsFail
+	default:
+		return FailureBuildFail
+	}
 }
 
 // doMerge performs the actual git merge operation.
-func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue string) ProcessResult {
+func (e *Engineer) doMerge(ctx context.Context, mr *MRInfo) ProcessResult {
+	branch, target := mr.Branch, mr.Target
 	// Step 1: Verify source branch exists locally (shared .repo.git with polecats)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking local branch %s...\n", branch)
 	exists, err := e.git.BranchExists(branch)
@@ -404,6 +481,18 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: pull from origin/%s: %v (continuing)\n", target, err)
 	}
 
+	// Step 2.5: ff-only lands nothing that would need rewriting; a branch
+	// that has fallen behind goes back to the worker to rebase.
+	if e.mergeStrategy() == config.MergeStrategyFFOnly {
+		if ok, err := e.git.IsAncestor(target, branch); err == nil && !ok {
+			return ProcessResult{
+				Success:  false,
+				Conflict: true,
+				Error:    fmt.Sprintf("branch %s does not fast-forward %s (merge_strategy ff-only): rebase required", branch, target),
+			}
+		}
+	}
+
 	// Step 3: Check for merge conflicts (using local branch)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking for conflicts...\n")
 	conflicts, err := e.git.CheckConflicts(branch, target)
@@ -425,31 +514,11 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 	// Step 3.5: Push submodule commits if the branch changes submodule pointers.
 	// The refinery owns all remote pushes — submodule commits must land before the
 	// parent pointer is merged, otherwise main gets dangling submodule references.
-	subChanges, err := e.git.SubmoduleChanges(target, branch)
-	if err != nil {
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not check submodule changes: %v\n", err)
-	}
-	if len(subChanges) > 0 {
-		// Ensure submodules are initialized in the refinery worktree
-		if initErr := git.InitSubmodules(e.git.WorkDir()); initErr != nil {
-			return ProcessResult{
-				Success: false,
-				Error:   fmt.Sprintf("failed to init submodules in refinery worktree: %v", initErr),
-			}
-		}
-		for _, sc := range subChanges {
-			if sc.NewSHA == "" {
-				continue // Submodule removed, nothing to push
-			}
-			_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing submodule %s (commit %s)...\n", sc.Path, sc.NewSHA[:8])
-			if pushErr := e.git.PushSubmoduleCommit(sc.Path, sc.NewSHA, "origin"); pushErr != nil {
-				return ProcessResult{
-					Success: false,
-					Error:   fmt.Sprintf("failed to push submodule %s: %v", sc.Path, pushErr),
-				}
-			}
+	if err := e.pushSubmoduleChanges(target, branch); err != nil {
+		return ProcessResult{
+			Success: false,
+			Error:   err.Error(),
 		}
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Pushed %d submodule(s)\n", len(subChanges))
 	}
 
 	// Step 4: Run quality gates (or legacy tests) if configured
@@ -473,25 +542,19 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		_, _ = fmt.Fprintln(e.output, "[Engineer] Tests passed")
 	}
 
-	// Step 5: Perform the actual merge using squash merge
-	// Get the original commit message from the polecat branch to preserve the
-	// conventional commit format (feat:/fix:) instead of creating redundant merge commits
-	originalMsg, err := e.git.GetBranchCommitMessage(branch)
-	if err != nil {
-		// Fallback to a descriptive message if we can't get the original
-		originalMsg = fmt.Sprintf("Squash merge %s into %s", branch, target)
-		if sourceIssue != "" {
-			originalMsg = fmt.Sprintf("Squash merge %s into %s (%s)", branch, target, sourceIssue)
-		}
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not get original commit message: %v\n", err)
-	}
-	_, _ = fmt.Fprintf(e.output, "[Engineer] Squash merging with message: %s\n", strings.TrimSpace(originalMsg))
-	if err := e.git.MergeSquash(branch, originalMsg); err != nil {
-		// ZFC: Use git's porcelain output to detect conflicts instead of parsing stderr.
-		// GetConflictingFiles() uses `git diff --diff-filter=U` which is proper.
-		conflicts, conflictErr := e.git.GetConflictingFiles()
-		if conflictErr == nil && len(conflicts) > 0 {
-			_ = e.git.AbortMerge()
+	// Step 5: Land the branch on the target with the rig's merge strategy
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Merging with strategy %s...\n", e.mergeStrategy())
+	if conflicts, err := e.landBranch(e.git, mr); err != nil {
+		// ZFC: landBranch reads conflicts from git's porcelain output
+		// (`git diff --diff-filter=U`) instead of parsing stderr.
+		if errors.Is(err, errNotFastForward) {
+			return ProcessResult{
+				Success:  false,
+				Conflict: true,
+				Error:    fmt.Sprintf("branch %s does not fast-forward %s (merge_strategy ff-only): rebase required", branch, target),
+			}
+		}
+		if len(conflicts) > 0 {
 			return ProcessResult{
 				Success:  false,
 				Conflict: true,
@@ -504,7 +567,7 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}
 	}
 
-	// Step 6: Get the merge commit SHA
+	// Step 6: Get the merge commit SHA (the landed tip for ff strategies)
 	mergeCommit, err := e.git.Rev("HEAD")
 	if err != nil {
 		return ProcessResult{
@@ -561,12 +624,54 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 	}
 
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Successfully merged: %s\n", mergeCommit[:8])
+	e.syncCrewWorkspaces(target)
 	return ProcessResult{
 		Success:     true,
 		MergeCommit: mergeCommit,
 	}
 }
 
+// squashMessage returns the commit message for squash-merging branch: the
+// branch's own HEAD message, or a descriptive fallback if it can't be read.
+func (e *Engineer) squashMessage(branch, target, sourceIssue string) string {
+	msg, err := e.git.GetBranchCommitMessage(branch)
+	if err == nil {
+		return msg
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not get original commit message: %v\n", err)
+	if sourceIssue != "" {
+		return fmt.Sprintf("Squash merge %s into %s (%s)", branch, target, sourceIssue)
+	}
+	return fmt.Sprintf("Squash merge %s into %s", branch, target)
+}
+
+// pushSubmoduleChanges pushes the submodule commits that branch points at
+// (relative to base) so the parent pointer never lands before its target.
+func (e *Engineer) pushSubmoduleChanges(base, branch string) error {
+	subChanges, err := e.git.SubmoduleChanges(base, branch)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not check submodule changes: %v\n", err)
+	}
+	if len(subChanges) == 0 {
+		return nil
+	}
+	// Ensure submodules are initialized in the refinery worktree
+	if initErr := git.InitSubmodules(e.git.WorkDir()); initErr != nil {
+		return fmt.Errorf("failed to init submodules in refinery worktree: %v", initErr)
+	}
+	for _, sc := range subChanges {
+		if sc.NewSHA == "" {
+			continue // Submodule removed, nothing to push
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing submodule %s (commit %s)...\n", sc.Path, sc.NewSHA[:8])
+		if pushErr := e.git.PushSubmoduleCommit(sc.Path, sc.NewSHA, "origin"); pushErr != nil {
+			return fmt.Errorf("failed to push submodule %s: %v", sc.Path, pushErr)
+		}
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushed %d submodule(s)\n", len(subChanges))
+	return nil
+}
+
 func (e *Engineer) acquireMainPushSlot(ctx context.Context) (string, error) {
 	slotID, err := e.mergeSlotEnsureExists()
 	if err != nil {
@@ -631,6 +736,11 @@ func ValidateTestCommand(cmd string) error {
 
 // runTests runs the configured test command and returns the result.
 func (e *Engineer) runTests(ctx context.Context) ProcessResult {
+	return e.runTestsIn(ctx, e.workDir)
+}
+
+// runTestsIn runs the configured test command in dir.
+func (e *Engineer) runTestsIn(ctx context.Context, dir string) ProcessResult {
 	if err := ValidateTestCommand(e.config.TestCommand); err != nil {
 		return ProcessResult{
 			Success: false,
@@ -655,7 +765,7 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 		// is intentional for flexibility (pipes, env vars, etc).
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Executing test command: %s\n", e.config.TestCommand)
 		cmd := exec.CommandContext(ctx, "sh", "-c", e.config.TestCommand) //nolint:gosec // G204: TestCommand is from trusted rig config
-		cmd.Dir = e.workDir
+		cmd.Dir = dir
 		var stdout, stderr bytes.Buffer
 		cmd.Stdout = &stdout
 		cmd.Stderr = &stderr
@@ -684,6 +794,20 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 
 // runGate executes a single quality gate command and returns the result.
 func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) GateResult {
+	return e.runGateIn(ctx, e.workDir, name, gate)
+}
+
+// runGateIn executes a single quality gate command in dir and records its
+// duration and trace span.
+func (e *Engineer) runGateIn(ctx context.Context, dir, name string, gate *GateConfig) GateResult {
+	span := telemetry.StartSpan(e.townRoot(), "refinery.gate", telemetry.Labels{"rig": e.rig.Name, "gate": name})
+	result := e.execGateIn(ctx, dir, name, gate)
+	e.recordGate(span, result)
+	return result
+}
+
+// execGateIn runs a quality gate command in dir.
+func (e *Engineer) execGateIn(ctx context.Context, dir, name string, gate *GateConfig) GateResult {
 	start := time.Now()
 
 	if strings.TrimSpace(gate.Cmd) == "" {
@@ -704,7 +828,7 @@ func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) G
 	}
 
 	cmd := exec.CommandContext(gateCtx, "sh", "-c", gate.Cmd) //nolint:gosec // G204: Gate commands are from trusted rig config
-	cmd.Dir = e.workDir
+	cmd.Dir = dir
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -744,6 +868,11 @@ func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) G
 // Gates run in parallel if GatesParallel is true; otherwise sequentially.
 // Any single gate failure means overall failure.
 func (e *Engineer) runGates(ctx context.Context) ProcessResult {
+	return e.runGatesIn(ctx, e.workDir)
+}
+
+// runGatesIn executes all configured quality gates in dir.
+func (e *Engineer) runGatesIn(ctx context.Context, dir string) ProcessResult {
 	gates := e.config.Gates
 	if len(gates) == 0 {
 		return ProcessResult{Success: true}
@@ -768,14 +897,14 @@ func (e *Engineer) runGates(ctx context.Context) ProcessResult {
 			go func(idx int, gateName string) {
 				defer wg.Done()
 				_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: starting (%s)\n", gateName, gates[gateName].Cmd)
-				results[idx] = e.runGate(ctx, gateName, gates[gateName])
+				results[idx] = e.runGateIn(ctx, dir, gateName, gates[gateName])
 			}(i, name)
 		}
 		wg.Wait()
 	} else {
 		for _, name := range names {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: starting (%s)\n", name, gates[name].Cmd)
-			result := e.runGate(ctx, name, gates[name])
+			result := e.runGateIn(ctx, dir, name, gates[name])
 			results = append(results, result)
 			if !result.Success {
 				// Sequential mode: stop on first failure
@@ -807,35 +936,43 @@ func (e *Engineer) runGates(ctx context.Context) ProcessResult {
 	return ProcessResult{Success: true}
 }
 
-// syncCrewWorkspaces pulls latest changes to all crew workspaces.
-// This ensures crew members have access to newly merged code without manual sync.
-func (e *Engineer) syncCrewWorkspaces() {
-	crewGit := git.NewGit(e.rig.Path)
-	crewMgr := crew.NewManager(e.rig, crewGit)
+// syncCrewWorkspaces brings crew workspaces up to date after a merge lands
+// on target, so crew members see newly merged code without a manual sync.
+// Only merges to the rig's default branch are synced. Each workspace is
+// fetched, and fast-forwarded only when it is clean and on the default
+// branch; anything else is left for the crew member to update.
+func (e *Engineer) syncCrewWorkspaces(target string) {
+	defaultBranch := e.rig.DefaultBranch()
+	if target != defaultBranch {
+		return
+	}
 
+	crewMgr := crew.NewManager(e.rig, git.NewGit(e.rig.Path))
 	workers, err := crewMgr.List()
 	if err != nil {
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to list crew workspaces: %v\n", err)
 		return
 	}
 
-	if len(workers) == 0 {
-		return
-	}
-
-	_, _ = fmt.Fprintf(e.output, "[Engineer] Syncing %d crew workspace(s)...\n", len(workers))
-
 	for _, worker := range workers {
-		result, err := crewMgr.Pristine(worker.Name)
-		if err != nil {
-			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to sync crew/%s: %v\n", worker.Name, err)
+		crewGit := git.NewGit(worker.ClonePath)
+		if err := crewGit.Fetch("origin"); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: crew/%s fetch failed: %v\n", worker.Name, err)
+			continue
+		}
+		if branch, err := crewGit.CurrentBranch(); err != nil || branch != defaultBranch {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Fetched crew/%s (not on %s, not pulled)\n", worker.Name, defaultBranch)
 			continue
 		}
-		if result.Pulled {
-			_, _ = fmt.Fprintf(e.output, "[Engineer] ✓ Synced crew/%s\n", worker.Name)
-		} else if result.PullError != "" {
-			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: crew/%s pull failed: %s\n", worker.Name, result.PullError)
+		if dirty, err := crewGit.HasUncommittedChanges(); err != nil || dirty {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Fetched crew/%s (uncommitted changes, not pulled)\n", worker.Name)
+			continue
+		}
+		if err := crewGit.PullFastForward("origin", defaultBranch); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: crew/%s cannot fast-forward: %v\n", worker.Name, err)
+			continue
 		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] ✓ Synced crew/%s\n", worker.Name)
 	}
 }
 
@@ -848,8 +985,27 @@ func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult
 	_, _ = fmt.Fprintf(e.output, "  Worker: %s\n", mr.Worker)
 	_, _ = fmt.Fprintf(e.output, "  Source: %s\n", mr.SourceIssue)
 
-	// Use the shared merge logic
-	return e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue)
+	span := telemetry.StartSpan(e.townRoot(), "refinery.merge", telemetry.Labels{
+		"rig": e.rig.Name, "mr": mr.ID, "branch": mr.Branch, "target": mr.Target,
+	})
+
+	// PR-strategy MRs land through the forge; everything else merges locally
+	var result ProcessResult
+	if mr.MergeStrategy == MergeStrategyPR {
+		result = e.processPR(ctx, mr)
+	} else {
+		result = e.doMerge(ctx, mr)
+	}
+
+	var err error
+	if result.Pending {
+		span.SetAttr("pending", "true")
+	} else if !result.Success {
+		span.SetAttr("failure_type", string(result.FailureType()))
+		err = errors.New(result.Error)
+	}
+	_ = span.End(err)
+	return result
 }
 
 // HandleMRInfoSuccess handles a successful merge from MRInfo.
@@ -865,6 +1021,9 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Released merge slot\n")
 	}
 
+	_ = events.LogFeed(events.TypeMerged, e.rig.Name+"/refinery", events.MergePayload(mr.ID, mr.Worker, mr.Branch, ""))
+	e.recordMergeOutcome(mr, result)
+
 	// Update and close the MR bead
 	if mr.ID != "" {
 		// Fetch the MR bead to update its fields
@@ -896,6 +1055,7 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 	// 1. Close source issue with reference to MR
 	if mr.SourceIssue != "" {
 		closeReason := fmt.Sprintf("Merged in %s", mr.ID)
+		e.closeAttachedMolecule(mr.SourceIssue, closeReason)
 		if err := e.beads.CloseWithReason(closeReason, mr.SourceIssue); err != nil {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to close source issue %s: %v\n", mr.SourceIssue, err)
 		} else {
@@ -910,6 +1070,14 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 		}
 	}
 
+	// 1.75. Notify Witness so it can clean up the polecat
+	msg := protocol.NewMergedMessage(e.rig.Name, mr.Worker, mr.Branch, mr.SourceIssue, mr.Target, result.MergeCommit)
+	if err := e.router.Send(msg); err != nil {
+		fmt.Fprintf(e.output, "[Engineer] Warning: failed to send MERGED to witness: %v\n", err)
+	} else {
+		fmt.Fprintf(e.output, "[Engineer] Notified witness of merge for %s\n", mr.Worker)
+	}
+
 	// 2. Delete source branch if configured (local and remote)
 	if e.config.DeleteMergedBranches && mr.Branch != "" {
 		if err := e.git.DeleteBranch(mr.Branch, true); err != nil {
@@ -933,6 +1101,49 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✓ Merged: %s (commit: %s)\n", mr.ID, result.MergeCommit)
 }
 
+// closeAttachedMolecule closes the molecule attached to a merged source issue
+// (the polecat's mol-polecat-work wisp) and its step issues, which otherwise
+// block the issue from closing. gt done only closes it for beads still
+// hooked; a polecat's bead is in_progress by the time it submits.
+func (e *Engineer) closeAttachedMolecule(issueID, reason string) {
+	issue, err := e.beads.Show(issueID)
+	if err != nil {
+		return
+	}
+	fields := beads.ParseAttachmentFields(issue)
+	if fields == nil || fields.AttachedMolecule == "" {
+		return
+	}
+	if err := e.closeMoleculeSteps(fields.AttachedMolecule, reason); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to close steps of molecule %s: %v\n", fields.AttachedMolecule, err)
+	}
+	if err := e.beads.CloseWithReason(reason, fields.AttachedMolecule); err != nil && !errors.Is(err, beads.ErrNotFound) {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to close attached molecule %s: %v\n", fields.AttachedMolecule, err)
+	}
+}
+
+// closeMoleculeSteps closes the open descendants of a molecule, deepest
+// first, so no step is left open under a closed parent.
+func (e *Engineer) closeMoleculeSteps(parentID, reason string) error {
+	children, err := e.beads.List(beads.ListOptions{Parent: parentID, Status: "all", Priority: -1})
+	if err != nil {
+		return fmt.Errorf("listing children of %s: %w", parentID, err)
+	}
+	var open []string
+	for _, child := range children {
+		if err := e.closeMoleculeSteps(child.ID, reason); err != nil {
+			return err
+		}
+		if child.Status != "closed" {
+			open = append(open, child.ID)
+		}
+	}
+	if len(open) == 0 {
+		return nil
+	}
+	return e.beads.CloseWithReason(reason, open...)
+}
+
 // HandleMRInfoFailure handles a failed merge from MRInfo.
 // For conflicts, creates a resolution task and blocks the MR until resolved.
 // For slot timeouts, the MR stays in queue for automatic retry without notifying polecats.
@@ -946,12 +1157,22 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 		_, _ = fmt.Fprintln(e.output, "[Engineer] MR remains in queue for automatic retry (slot contention)")
 		return
 	}
+	// A pending pull request is waiting on the forge, not failing.
+	if result.Pending {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] … Waiting: %s - %s\n", mr.ID, result.Error)
+		return
+	}
+
+	_ = events.LogFeed(events.TypeMergeFailed, e.rig.Name+"/refinery", events.MergePayload(mr.ID, mr.Worker, mr.Branch, result.Error))
+	e.recordMergeOutcome(mr, result)
 
 	// Notify Witness of the failure so polecat can be alerted
 	// Determine failure type from result
 	failureType := "build"
 	if result.Conflict {
 		failureType = "conflict"
+	} else if result.ReviewRework {
+		failureType = "review"
 	} else if result.TestsFailed {
 		failureType = "tests"
 	}
@@ -979,6 +1200,20 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 		}
 	}
 
+	// Review feedback on a pull request becomes a rework task; the MR is
+	// blocked on it the same way as on a conflict task.
+	if result.ReviewRework {
+		taskID, err := e.createReviewReworkTaskForMR(mr, result)
+		if err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to create review rework task: %v\n", err)
+		} else if err := e.beads.AddDependency(mr.ID, taskID); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to block MR on task: %v\n", err)
+		} else {
+			mr.BlockedBy = taskID
+			_, _ = fmt.Fprintf(e.output, "[Engineer] MR %s blocked on review rework task %s\n", mr.ID, taskID)
+		}
+	}
+
 	// Log the failure - MR stays in queue but may be blocked
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✗ Failed: %s - %s\n", mr.ID, result.Error)
 	if mr.BlockedBy != "" {
@@ -1131,6 +1366,10 @@ func (e *Engineer) IsBeadOpen(beadID string) (bool, error) {
 // issueToMRInfo converts a beads issue (with parsed MR fields) into an MRInfo.
 // Shared by ListReadyMRs, ListBlockedMRs, and ListAllOpenMRs.
 func issueToMRInfo(issue *beads.Issue, fields *beads.MRFields) *MRInfo {
+	if fields == nil {
+		fields = &beads.MRFields{}
+	}
+
 	// Parse convoy created_at if present
 	var convoyCreatedAt *time.Time
 	if fields.ConvoyCreatedAt != "" {
@@ -1168,7 +1407,25 @@ func issueToMRInfo(issue *beads.Issue, fields *beads.MRFields) *MRInfo {
 		CreatedAt:       createdAt,
 		UpdatedAt:       updatedAt,
 		Assignee:        issue.Assignee,
+		Expedite:        isExpedited(issue),
+		MergeStrategy:   fields.MergeStrategy,
+		PRNumber:        fields.PRNumber,
+	}
+}
+
+// MRInfoFromIssue converts a merge-request bead to an MRInfo.
+// fields may be nil for beads without MR metadata.
+func MRInfoFromIssue(issue *beads.Issue, fields *beads.MRFields) *MRInfo {
+	return issueToMRInfo(issue, fields)
+}
+
+func isExpedited(issue *beads.Issue) bool {
+	for _, label := range ExpediteLabels {
+		if beads.HasLabel(issue, label) {
+			return true
+		}
 	}
+	return false
 }
 
 // firstOpenBlocker returns the ID of the first open blocker for an issue,
@@ -1249,6 +1506,7 @@ func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
 		mrs = append(mrs, issueToMRInfo(issue, fields))
 	}
 
+	e.recordQueueDepth(len(mrs))
 	return mrs, nil
 }
 
@@ -1453,7 +1711,7 @@ func (e *Engineer) postMergeConvoyCheck(mr *MRInfo) {
 	// Step 1: Run `gt convoy check` to auto-close completed convoys.
 	// This handles cross-rig convoy completion: convoys in town beads (hq-*)
 	// tracking issues in rig beads (gt-*) won't auto-close via bd close alone.
-	closedConvoys := e.checkAndCloseCompletedConvoys(townRoot, townBeads)
+	closedConvoys := e.checkAndCloseCompletedConvoys(townRoot)
 
 	// Step 2: For each closed convoy, check if it has a swarm with an
 	// integration branch that needs landing.
@@ -1491,7 +1749,7 @@ func (e *Engineer) notifyDeaconConvoyFeeding(mr *MRInfo) {
 	}
 
 	// Emit event to wake deacon from await-signal (router.Send doesn't write
-	// to .events.jsonl, but await-signal watches the events file).
+	// to the event store, but await-signal watches it).
 	_ = events.LogFeed(events.TypeMail, e.rig.Name+"/refinery", events.MailPayload("deacon/", "CONVOY_NEEDS_FEEDING "+mr.ConvoyID))
 }
 
@@ -1504,83 +1762,29 @@ type convoyInfo struct {
 
 // checkAndCloseCompletedConvoys finds and closes convoys where all tracked issues
 // are complete. Returns the list of convoys that were closed.
-func (e *Engineer) checkAndCloseCompletedConvoys(townRoot, townBeads string) []convoyInfo {
-	// List all open convoys
-	listCmd := exec.Command("bd", "list", "--type=convoy", "--status=open", "--json")
-	listCmd.Dir = townBeads
-	var stdout bytes.Buffer
-	listCmd.Stdout = &stdout
+func (e *Engineer) checkAndCloseCompletedConvoys(townRoot string) []convoyInfo {
+	store := newTownBeads(townRoot)
 
-	if err := listCmd.Run(); err != nil {
+	// List all open convoys
+	convoys, err := store.List(beads.ListOptions{IssueType: "convoy", Status: "open", Priority: -1})
+	if err != nil {
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to list convoys: %v\n", err)
 		return nil
 	}
 
-	var convoys []struct {
-		ID          string `json:"id"`
-		Title       string `json:"title"`
-		Status      string `json:"status"`
-		Description string `json:"description"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to parse convoy list: %v\n", err)
-		return nil
-	}
-
 	var closed []convoyInfo
 
 	for _, convoy := range convoys {
-		// Get tracked issues for this convoy via bd dep list
-		depCmd := exec.Command("bd", "dep", "list", convoy.ID, "--direction=down", "--type=tracks", "--json")
-		depCmd.Dir = townRoot
-		var depOut bytes.Buffer
-		depCmd.Stdout = &depOut
-
-		if err := depCmd.Run(); err != nil {
-			continue
-		}
-
-		var deps []struct {
-			ID     string `json:"id"`
-			Status string `json:"status"`
-		}
-		if err := json.Unmarshal(depOut.Bytes(), &deps); err != nil {
+		// Tracked issue statuses come from their home rigs (cross-rig lookup).
+		tracked, err := store.TrackedIssues(convoy.ID)
+		if err != nil {
 			continue
 		}
 
-		// Refresh statuses from home rigs (cross-rig lookup)
 		allClosed := true
-		for _, dep := range deps {
-			// Unwrap external:prefix:id format
-			depID := dep.ID
-			if strings.HasPrefix(depID, "external:") {
-				parts := strings.SplitN(depID, ":", 3)
-				if len(parts) == 3 {
-					depID = parts[2]
-				}
-			}
-
-			// Get fresh status from home rig via bd show with routing
-			showCmd := exec.Command("bd", "show", depID, "--json")
-			showCmd.Dir = townRoot
-			var showOut bytes.Buffer
-			showCmd.Stdout = &showOut
-
-			if err := showCmd.Run(); err != nil || showOut.Len() == 0 {
-				// Can't verify - treat as open to be safe
-				allClosed = false
-				break
-			}
-
-			var issues []struct {
-				Status string `json:"status"`
-			}
-			if err := json.Unmarshal(showOut.Bytes(), &issues); err != nil || len(issues) == 0 {
-				allClosed = false
-				break
-			}
-
-			if issues[0].Status != "closed" && issues[0].Status != "tombstone" {
+		for _, issue := range tracked {
+			// An issue we can't verify counts as open to be safe.
+			if issue.Status != "closed" && issue.Status != "tombstone" {
 				allClosed = false
 				break
 			}
@@ -1592,19 +1796,17 @@ func (e *Engineer) checkAndCloseCompletedConvoys(townRoot, townBeads string) []c
 
 		// All tracked issues are complete - close the convoy
 		reason := "All tracked issues completed"
-		if len(deps) == 0 {
+		if len(tracked) == 0 {
 			reason = "Empty convoy — auto-closed as definitionally complete"
 		}
 
-		closeCmd := exec.Command("bd", "close", convoy.ID, "-r", reason)
-		closeCmd.Dir = townBeads
-
-		if err := closeCmd.Run(); err != nil {
+		if err := store.CloseWithReason(reason, convoy.ID); err != nil {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to close convoy %s: %v\n", convoy.ID, err)
 			continue
 		}
 
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Auto-closed convoy %s: %s\n", convoy.ID, convoy.Title)
+		_ = events.LogFeed(events.TypeConvoyClosed, e.rig.Name+"/refinery", events.ConvoyClosedPayload(convoy.ID, convoy.Title, reason))
 		closed = append(closed, convoyInfo{
 			ID:          convoy.ID,
 			Title:       convoy.Title,
@@ -0,0 +1,191 @@
+package refinery
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func memoryEngineer(store *beads.MemoryStore) *Engineer {
+	return &Engineer{
+		rig:    &rig.Rig{Name: "gastown", Path: "/nonexistent"},
+		beads:  store,
+		config: DefaultMergeQueueConfig(),
+		output: io.Discard,
+	}
+}
+
+func createMR(t *testing.T, store *beads.MemoryStore, branch string, labels ...string) *beads.Issue {
+	t.Helper()
+	issue, err := store.Create(beads.CreateOptions{
+		Title:       "Merge: " + branch,
+		Type:        "merge-request",
+		Priority:    2,
+		Description: "branch: " + branch + "\ntarget: main\nworker: Toast",
+		Ephemeral:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(labels) > 0 {
+		if err := store.Update(issue.ID, beads.UpdateOptions{AddLabels: labels}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return issue
+}
+
+func readyIDs(t *testing.T, e *Engineer) []string {
+	t.Helper()
+	mrs, err := e.ListReadyMRs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, mr := range mrs {
+		ids = append(ids, mr.ID)
+	}
+	return ids
+}
+
+func TestListReadyMRs_MemoryStore(t *testing.T) {
+	store := beads.NewMemoryStore("gt")
+	e := memoryEngineer(store)
+
+	ready := createMR(t, store, "polecat/a", "gt:expedite")
+	blocked := createMR(t, store, "polecat/b")
+	createMR(t, store, "polecat/c", "gt:owned-direct")
+	claimed := createMR(t, store, "polecat/d")
+	_, _ = store.Create(beads.CreateOptions{Title: "not an MR", Type: "task"})
+
+	task, err := store.Create(beads.CreateOptions{Title: "Resolve merge conflicts", Type: "task"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddDependency(blocked.ID, task.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.ClaimMR(claimed.ID, "gastown/refinery"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readyIDs(t, e); !reflect.DeepEqual(got, []string{ready.ID}) {
+		t.Errorf("ready = %v, want [%s] (blocked, owned-direct and claimed MRs skipped)", got, ready.ID)
+	}
+	mrs, _ := e.ListReadyMRs()
+	if len(mrs) != 1 || !mrs[0].Expedite || mrs[0].Branch != "polecat/a" || mrs[0].Worker != "Toast" {
+		t.Errorf("MRInfo = %+v", mrs)
+	}
+
+	// Closing the conflict task puts the blocked MR back in the queue.
+	if err := store.CloseWithReason("resolved", task.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := readyIDs(t, e); !reflect.DeepEqual(got, []string{ready.ID, blocked.ID}) {
+		t.Errorf("ready after unblock = %v", got)
+	}
+
+	// Releasing the claim makes that MR ready again too.
+	if err := e.ReleaseMR(claimed.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := readyIDs(t, e); !reflect.DeepEqual(got, []string{ready.ID, blocked.ID, claimed.ID}) {
+		t.Errorf("ready after release = %v", got)
+	}
+}
+
+func TestCloseAttachedMolecule_MemoryStore(t *testing.T) {
+	store := beads.NewMemoryStore("gt")
+	e := memoryEngineer(store)
+
+	mol, err := store.Create(beads.CreateOptions{Title: "mol-polecat-work", Type: "epic", Ephemeral: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	step, err := store.Create(beads.CreateOptions{Title: "Implement", Type: "task", Parent: mol.ID, Ephemeral: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	substep, err := store.Create(beads.CreateOptions{Title: "Run tests", Type: "task", Parent: step.ID, Ephemeral: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	issue, err := store.Create(beads.CreateOptions{Title: "Fix it", Type: "task", Description: "attached_molecule: " + mol.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := store.Create(beads.CreateOptions{Title: "No molecule", Type: "task"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e.closeAttachedMolecule(issue.ID, "Merged in gt-mr1")
+	e.closeAttachedMolecule(plain.ID, "Merged in gt-mr2")
+	e.closeAttachedMolecule("gt-missing", "Merged in gt-mr3")
+
+	for _, id := range []string{mol.ID, step.ID, substep.ID} {
+		if got, _ := store.Show(id); got.Status != "closed" {
+			t.Errorf("%s status = %s, want closed", id, got.Status)
+		}
+	}
+	if got, _ := store.Show(issue.ID); got.Status == "closed" {
+		t.Error("the source issue is closed by HandleMRInfoSuccess, not closeAttachedMolecule")
+	}
+}
+
+func TestCheckAndCloseCompletedConvoys_MemoryStore(t *testing.T) {
+	t.Chdir(t.TempDir()) // keep convoy events out of any enclosing town
+	town := beads.NewMemoryStore("hq")
+	rigStore := town.Routed("gt")
+	orig := newTownBeads
+	newTownBeads = func(string) beads.Store { return town }
+	t.Cleanup(func() { newTownBeads = orig })
+
+	newConvoy := func(title string, tracked ...string) *beads.Issue {
+		t.Helper()
+		convoy, err := town.Create(beads.CreateOptions{Title: title, Type: "convoy"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, id := range tracked {
+			if err := town.Track(convoy.ID, id); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return convoy
+	}
+	done, err := rigStore.Create(beads.CreateOptions{Title: "merged work", Type: "task"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	open, err := rigStore.Create(beads.CreateOptions{Title: "still open", Type: "task"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rigStore.CloseWithReason("merged", done.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	complete := newConvoy("complete", done.ID)
+	pending := newConvoy("pending", done.ID, open.ID)
+	unknown := newConvoy("unverifiable", done.ID, "gt-gone")
+	empty := newConvoy("empty")
+
+	closed := memoryEngineer(rigStore).checkAndCloseCompletedConvoys("/nonexistent")
+
+	var ids []string
+	for _, c := range closed {
+		ids = append(ids, c.ID)
+	}
+	if want := []string{complete.ID, empty.ID}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("closed convoys = %v, want %v", ids, want)
+	}
+	for _, c := range []*beads.Issue{pending, unknown} {
+		if got, _ := town.Show(c.ID); got.Status != "open" {
+			t.Errorf("convoy %q status = %s, want open", c.Title, got.Status)
+		}
+	}
+}
@@ -613,6 +613,7 @@ func TestNotifyDeaconConvoyFeeding_SkipsWhenNoConvoyID(t *testing.T) {
 }
 
 func TestNotifyDeaconConvoyFeeding_AttemptsWhenConvoyID(t *testing.T) {
+	t.Chdir(t.TempDir()) // keep events out of any enclosing town
 	// notifyDeaconConvoyFeeding should attempt to send mail when ConvoyID is set.
 	// The send will fail (no beads setup in tmpdir) but we verify the attempt via output.
 	tmpDir, err := os.MkdirTemp("", "engineer-notify-test-*")
@@ -32,6 +32,7 @@ var (
 // Manager handles refinery lifecycle and queue operations.
 type Manager struct {
 	rig     *rig.Rig
+	beads   beads.Store // Merge-request storage; tests use *beads.MemoryStore
 	workDir string
 	output  io.Writer // Output destination for user-facing messages
 }
@@ -40,6 +41,7 @@ type Manager struct {
 func NewManager(r *rig.Rig) *Manager {
 	return &Manager{
 		rig:     r,
+		beads:   beads.New(r.BeadsPath()),
 		workDir: r.Path,
 		output:  os.Stdout,
 	}
@@ -56,13 +58,18 @@ func (m *Manager) SessionName() string {
 	return session.RefinerySessionName(session.PrefixFor(m.rig.Name))
 }
 
+// backend returns the session backend for the refinery's town.
+func (m *Manager) backend() session.Backend {
+	return session.NewBackend(filepath.Dir(m.rig.Path))
+}
+
 // IsRunning checks if the refinery session is active and healthy.
 // Checks both tmux session existence AND agent process liveness to avoid
 // reporting zombie sessions (tmux alive but Claude dead) as "running".
 // ZFC: tmux session existence is the source of truth for session state,
 // but agent liveness determines if the session is actually functional.
 func (m *Manager) IsRunning() (bool, error) {
-	t := tmux.NewTmux()
+	t := m.backend()
 	sessionName := m.SessionName()
 	status := t.CheckSessionHealth(sessionName, 0)
 	return status == tmux.SessionHealthy, nil
@@ -74,14 +81,14 @@ func (m *Manager) IsRunning() (bool, error) {
 // Returns the detailed ZombieStatus for callers that need to distinguish
 // between different failure modes.
 func (m *Manager) IsHealthy(maxInactivity time.Duration) tmux.ZombieStatus {
-	t := tmux.NewTmux()
+	t := m.backend()
 	return t.CheckSessionHealth(m.SessionName(), maxInactivity)
 }
 
 // Status returns information about the refinery session.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Status() (*tmux.SessionInfo, error) {
-	t := tmux.NewTmux()
+	t := m.backend()
 	sessionID := m.SessionName()
 
 	running, err := t.HasSession(sessionID)
@@ -101,7 +108,7 @@ func (m *Manager) Status() (*tmux.SessionInfo, error) {
 // The agentOverride parameter allows specifying an agent alias to use instead of the town default.
 // ZFC-compliant: no state file, tmux session is source of truth.
 func (m *Manager) Start(foreground bool, agentOverride string) error {
-	t := tmux.NewTmux()
+	t := m.backend()
 	sessionID := m.SessionName()
 
 	if foreground {
@@ -216,7 +223,7 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 // Stop stops the refinery.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Stop() error {
-	t := tmux.NewTmux()
+	t := m.backend()
 	sessionID := m.SessionName()
 
 	// Check if tmux session exists
@@ -234,9 +241,7 @@ func (m *Manager) Stop() error {
 // ZFC-compliant: beads is the source of truth, no state file.
 func (m *Manager) Queue() ([]QueueItem, error) {
 	// Query beads for open merge-request issues
-	// BeadsPath() returns the git-synced beads location
-	b := beads.New(m.rig.BeadsPath())
-	issues, err := b.List(beads.ListOptions{
+	issues, err := m.beads.List(beads.ListOptions{
 		Label:    "gt:merge-request",
 		Status:   "open",
 		Priority: -1, // No priority filter
@@ -246,22 +251,27 @@ func (m *Manager) Queue() ([]QueueItem, error) {
 	}
 
 	// Score and sort issues by priority score (highest first)
-	now := time.Now()
-	type scoredIssue struct {
-		issue *beads.Issue
-		score float64
-	}
-	scored := make([]scoredIssue, 0, len(issues))
+	var open []*beads.Issue
+	var mrs []*MRInfo
 	for _, issue := range issues {
 		// Defensive filter: bd status filters can drift; queue must only include open MRs.
 		if issue == nil || issue.Status != "open" {
 			continue
 		}
-		score := m.calculateIssueScore(issue, now)
-		scored = append(scored, scoredIssue{issue: issue, score: score})
+		open = append(open, issue)
+		mrs = append(mrs, MRInfoFromIssue(issue, beads.ParseMRFields(issue)))
+	}
+	scorer := NewEngineer(m.rig).NewScorer(mrs, time.Now())
+	type scoredIssue struct {
+		issue *beads.Issue
+		score float64
+	}
+	scored := make([]scoredIssue, 0, len(open))
+	for i, issue := range open {
+		scored = append(scored, scoredIssue{issue: issue, score: scorer.Score(mrs[i])})
 	}
 
-	sort.Slice(scored, func(i, j int) bool {
+	sort.SliceStable(scored, func(i, j int) bool {
 		return scored[i].score > scored[j].score
 	})
 
@@ -283,39 +293,6 @@ func (m *Manager) Queue() ([]QueueItem, error) {
 	return items, nil
 }
 
-// calculateIssueScore computes the priority score for an MR issue.
-// Higher scores mean higher priority (process first).
-func (m *Manager) calculateIssueScore(issue *beads.Issue, now time.Time) float64 {
-	fields := beads.ParseMRFields(issue)
-
-	// Parse MR creation time
-	mrCreatedAt := parseTime(issue.CreatedAt)
-	if mrCreatedAt.IsZero() {
-		mrCreatedAt = now // Fallback
-	}
-
-	// Build score input
-	input := ScoreInput{
-		Priority:    issue.Priority,
-		MRCreatedAt: mrCreatedAt,
-		Now:         now,
-	}
-
-	// Add fields from MR metadata if available
-	if fields != nil {
-		input.RetryCount = fields.RetryCount
-
-		// Parse convoy created at if available
-		if fields.ConvoyCreatedAt != "" {
-			if convoyTime := parseTime(fields.ConvoyCreatedAt); !convoyTime.IsZero() {
-				input.ConvoyCreatedAt = &convoyTime
-			}
-		}
-	}
-
-	return ScoreMRWithDefaults(input)
-}
-
 // issueToMR converts a beads issue to a MergeRequest.
 func (m *Manager) issueToMR(issue *beads.Issue) *MergeRequest {
 	if issue == nil {
@@ -452,8 +429,7 @@ func (m *Manager) RejectMR(idOrBranch string, reason string, notify bool) (*Merg
 	}
 
 	// Close the bead in storage with the rejection reason
-	b := beads.New(m.rig.BeadsPath())
-	if err := b.CloseWithReason("rejected: "+reason, mr.ID); err != nil {
+	if err := m.beads.CloseWithReason("rejected: "+reason, mr.ID); err != nil {
 		return nil, fmt.Errorf("failed to close MR bead: %w", err)
 	}
 
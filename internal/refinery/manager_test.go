@@ -179,3 +179,33 @@ func TestManager_Retry_Deprecated(t *testing.T) {
 		t.Errorf("Retry() unexpected error: %v", err)
 	}
 }
+
+func TestManager_Queue_MemoryStore(t *testing.T) {
+	mgr, _ := setupTestManager(t)
+	store := beads.NewMemoryStore("gt")
+	mgr.beads = store
+
+	mr := createMR(t, store, "polecat/a")
+	closed := createMR(t, store, "polecat/b")
+	if err := store.CloseWithReason("merged", closed.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Create(beads.CreateOptions{Title: "not an MR", Type: "task"}); err != nil {
+		t.Fatal(err)
+	}
+
+	queue, err := mgr.Queue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queue) != 1 || queue[0].MR.ID != mr.ID || queue[0].MR.Branch != "polecat/a" {
+		t.Fatalf("queue = %+v, want only %s", queue, mr.ID)
+	}
+
+	if _, err := mgr.RejectMR(mr.ID, "tests fail", false); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := store.Show(mr.ID); got.Status != "closed" {
+		t.Errorf("rejected MR status = %s, want closed", got.Status)
+	}
+}
@@ -0,0 +1,344 @@
+package refinery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/forge"
+)
+
+// MergeStrategyPR is the MR merge_strategy that lands work through a forge
+// pull request: the refinery opens the PR, waits for the forge's required
+// checks and reviews, and merges through the API. Rigs whose target branch
+// is protected use it instead of the local merge-and-push.
+const MergeStrategyPR = "pr"
+
+// prChecksGrace is how long a pull request with no checks reported waits
+// after its last update before it is merged anyway. CI takes a while to
+// pick up a new head; a repository without CI merges after the wait.
+const prChecksGrace = 5 * time.Minute
+
+// forgeClient returns the rig's forge and merge method, loading them from
+// rig settings on first use.
+func (e *Engineer) forgeClient() (forge.Forge, string, error) {
+	if e.forge != nil {
+		return e.forge, e.forgeMergeMethod, nil
+	}
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(e.rig.Path))
+	if err != nil {
+		return nil, "", fmt.Errorf("loading rig settings: %w", err)
+	}
+	if settings.Forge == nil {
+		return nil, "", errors.New(`merge_strategy "pr" needs a "forge" section in the rig's settings/config.json`)
+	}
+	method, err := forge.MergeMethod(settings.Forge)
+	if err != nil {
+		return nil, "", err
+	}
+	f, err := forge.New(settings.Forge, e.rig.GitURL)
+	if err != nil {
+		return nil, "", err
+	}
+	e.forge, e.forgeMergeMethod = f, method
+	return f, method, nil
+}
+
+// processPR advances a PR-strategy MR by one step. It opens the pull
+// request if there is none yet (and waits for the next patrol), then reports
+// it merged, closed, in need of rework, conflicted, failing or still
+// waiting; and merges it once the forge's checks pass. Anything the forge is
+// still deciding comes back as Pending so the MR stays queued for the next
+// patrol: mergeability, running checks, and for a grace period, checks that
+// have not reported at all.
+func (e *Engineer) processPR(ctx context.Context, mr *MRInfo) ProcessResult {
+	f, method, err := e.forgeClient()
+	if err != nil {
+		return ProcessResult{Pending: true, Error: err.Error()}
+	}
+
+	fields := e.mrFields(mr.ID)
+	if fields.PRNumber == 0 {
+		pr, err := e.openPR(ctx, f, mr)
+		if err != nil {
+			return ProcessResult{Error: err.Error()}
+		}
+		fields.PRNumber, fields.PRURL = pr.Number, pr.URL
+		e.saveMRFields(mr.ID, fields)
+		mr.PRNumber = pr.Number
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Opened PR #%d: %s\n", pr.Number, pr.URL)
+		// Checks and mergeability are computed after the PR opens; read
+		// them on the next patrol rather than racing the forge now.
+		return ProcessResult{Pending: true, Error: fmt.Sprintf("opened PR #%d, waiting for checks", pr.Number)}
+	}
+
+	pr, err := f.GetPR(ctx, fields.PRNumber)
+	if err != nil {
+		return ProcessResult{Pending: true, Error: fmt.Sprintf("reading PR #%d: %v", fields.PRNumber, err)}
+	}
+	mr.PRNumber = pr.Number
+	_, _ = fmt.Fprintf(e.output, "  PR: #%d %s\n", pr.Number, pr.URL)
+
+	switch pr.State {
+	case forge.PRMerged:
+		e.syncCrewWorkspaces(pr.Base)
+		return ProcessResult{Success: true, MergeCommit: pr.MergeCommit}
+	case forge.PRClosed:
+		// Forget the closed PR so a fresh one is opened once the worker
+		// has reworked the branch.
+		fields.PRNumber, fields.PRURL = 0, ""
+		e.saveMRFields(mr.ID, fields)
+		return ProcessResult{
+			ReviewRework: true,
+			Error:        fmt.Sprintf("PR #%d was closed without merging", pr.Number),
+		}
+	}
+
+	// New review feedback goes back to the worker before anything else: a
+	// merge would land code a reviewer has asked to change.
+	if result, ok := e.collectReviewRework(ctx, f, pr, fields, mr.ID); ok {
+		return result
+	}
+
+	if pr.Conflicted {
+		return ProcessResult{Conflict: true, Error: fmt.Sprintf("PR #%d has conflicts with %s", pr.Number, pr.Base)}
+	}
+	if pr.MergeablePending {
+		return ProcessResult{Pending: true, Error: fmt.Sprintf("PR #%d mergeability not settled yet", pr.Number)}
+	}
+
+	checks, err := f.Checks(ctx, pr)
+	if err != nil {
+		return ProcessResult{Pending: true, Error: fmt.Sprintf("reading checks for PR #%d: %v", pr.Number, err)}
+	}
+	switch checks.State {
+	case forge.ChecksFailure:
+		return ProcessResult{
+			TestsFailed: true,
+			Error:       fmt.Sprintf("PR #%d checks failed: %s", pr.Number, strings.Join(checks.Failed(), ", ")),
+		}
+	case forge.ChecksPending:
+		return ProcessResult{Pending: true, Error: fmt.Sprintf("PR #%d checks still running", pr.Number)}
+	case forge.ChecksNone:
+		if time.Since(pr.UpdatedAt) < prChecksGrace {
+			return ProcessResult{Pending: true, Error: fmt.Sprintf("PR #%d has no checks reported yet", pr.Number)}
+		}
+	}
+	if pr.Blocked {
+		return ProcessResult{Pending: true, Error: fmt.Sprintf("PR #%d waiting for required review", pr.Number)}
+	}
+
+	sha, err := f.Merge(ctx, pr, method, e.prTitle(mr))
+	if err != nil {
+		if forge.IsNotMergeable(err) {
+			return ProcessResult{Pending: true, Error: fmt.Sprintf("PR #%d not mergeable yet: %v", pr.Number, err)}
+		}
+		return ProcessResult{Error: fmt.Sprintf("merging PR #%d: %v", pr.Number, err)}
+	}
+	e.syncCrewWorkspaces(pr.Base)
+	return ProcessResult{Success: true, MergeCommit: sha}
+}
+
+// openPR makes sure the MR branch is on the remote and returns the open
+// pull request for it, creating one if needed.
+func (e *Engineer) openPR(ctx context.Context, f forge.Forge, mr *MRInfo) (*forge.PullRequest, error) {
+	if err := e.publishBranch(mr.Branch); err != nil {
+		return nil, err
+	}
+	pr, err := f.FindPR(ctx, mr.Branch, mr.Target)
+	if err != nil {
+		return nil, fmt.Errorf("looking up PR for %s: %w", mr.Branch, err)
+	}
+	if pr != nil {
+		return pr, nil
+	}
+	pr, err = f.CreatePR(ctx, forge.NewPullRequest{
+		Title: e.prTitle(mr),
+		Body:  e.prBody(mr),
+		Head:  mr.Branch,
+		Base:  mr.Target,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("opening PR for %s: %w", mr.Branch, err)
+	}
+	return pr, nil
+}
+
+// publishBranch pushes the MR branch to origin if only the refinery's clone
+// has it. Branches that polecats already pushed are left alone.
+func (e *Engineer) publishBranch(branch string) error {
+	if e.git == nil {
+		return nil
+	}
+	if ok, err := e.git.RemoteBranchExists("origin", branch); err == nil && ok {
+		return nil
+	}
+	if ok, _ := e.git.BranchExists(branch); !ok {
+		return fmt.Errorf("branch %s not found locally or on origin", branch)
+	}
+	if err := e.git.Push("origin", branch, false); err != nil {
+		return fmt.Errorf("pushing %s: %w", branch, err)
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushed %s to origin\n", branch)
+	return nil
+}
+
+// collectReviewRework returns a ReviewRework result when reviews newer than
+// the MR's pr_review_seen_at ask for changes, and records them as seen.
+func (e *Engineer) collectReviewRework(ctx context.Context, f forge.Forge, pr *forge.PullRequest, fields *beads.MRFields, mrID string) (ProcessResult, bool) {
+	reviews, err := f.Reviews(ctx, pr.Number)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: reading reviews for PR #%d: %v\n", pr.Number, err)
+		return ProcessResult{}, false
+	}
+	var seen time.Time
+	if fields.PRReviewSeenAt != "" {
+		seen, _ = time.Parse(time.RFC3339, fields.PRReviewSeenAt)
+	}
+	fresh := newReviews(reviews, seen)
+	if len(fresh) == 0 {
+		return ProcessResult{}, false
+	}
+
+	fields.PRReviewSeenAt = fresh[len(fresh)-1].CreatedAt.UTC().Format(time.RFC3339)
+	e.saveMRFields(mrID, fields)
+
+	var authors []string
+	for _, r := range fresh {
+		if !slices.Contains(authors, r.Author) {
+			authors = append(authors, r.Author)
+		}
+	}
+	return ProcessResult{
+		ReviewRework: true,
+		Reviews:      fresh,
+		Error:        fmt.Sprintf("changes requested on PR #%d by %s", pr.Number, strings.Join(authors, ", ")),
+	}, true
+}
+
+// newReviews returns the reviews submitted after seen, if any of them ask
+// for changes. Approvals and plain comments alone do not trigger rework,
+// but are kept alongside actionable feedback for context.
+func newReviews(reviews []forge.Review, seen time.Time) []forge.Review {
+	var fresh []forge.Review
+	actionable := false
+	for _, r := range reviews {
+		if !r.CreatedAt.After(seen) || r.State == forge.ReviewApproved {
+			continue
+		}
+		if strings.TrimSpace(r.Body) == "" && r.State != forge.ReviewChangesRequested {
+			continue
+		}
+		fresh = append(fresh, r)
+		actionable = actionable || r.Actionable()
+	}
+	if !actionable {
+		return nil
+	}
+	return fresh
+}
+
+// createReviewReworkTaskForMR creates a task carrying the pull request's
+// review feedback, for the MR to block on until the worker addresses it.
+func (e *Engineer) createReviewReworkTaskForMR(mr *MRInfo, result ProcessResult) (string, error) {
+	originalTitle := mr.SourceIssue
+	if mr.SourceIssue != "" {
+		if sourceIssue, err := e.beads.Show(mr.SourceIssue); err == nil && sourceIssue != nil {
+			originalTitle = sourceIssue.Title
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Address review feedback on PR #%d for branch %s\n\n", mr.PRNumber, mr.Branch)
+	b.WriteString("## Metadata\n")
+	fmt.Fprintf(&b, "- Original MR: %s\n- Branch: %s\n- Original issue: %s\n- Worker: %s\n\n",
+		mr.ID, mr.Branch, mr.SourceIssue, mr.Worker)
+	b.WriteString("## Feedback\n")
+	if len(result.Reviews) == 0 {
+		fmt.Fprintf(&b, "%s\n", result.Error)
+	}
+	for _, r := range result.Reviews {
+		where := ""
+		if r.Path != "" {
+			where = " on " + r.Path
+			if r.Line > 0 {
+				where += fmt.Sprintf(":%d", r.Line)
+			}
+		}
+		fmt.Fprintf(&b, "\n### %s (%s)%s\n%s\n", r.Author, r.State, where, strings.TrimSpace(r.Body))
+		if r.URL != "" {
+			fmt.Fprintf(&b, "%s\n", r.URL)
+		}
+	}
+	fmt.Fprintf(&b, `
+## Instructions
+1. Check out the branch: git checkout %s
+2. Address the feedback above
+3. Push to the same branch: git push (the PR updates itself)
+4. Close this task: bd close <this-task-id>
+
+The Refinery re-checks the PR once this task is closed.`, mr.Branch)
+
+	task, err := e.beads.Create(beads.CreateOptions{
+		Title:       fmt.Sprintf("Address PR review: %s", originalTitle),
+		Type:        "task",
+		Priority:    max(mr.Priority-1, 0),
+		Description: b.String(),
+		Actor:       e.rig.Name + "/refinery",
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating review rework task: %w", err)
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Created review rework task: %s (P%d)\n", task.ID, task.Priority)
+	return task.ID, nil
+}
+
+// mrFields reads the MR bead's fields, or empty fields if it can't be read.
+func (e *Engineer) mrFields(mrID string) *beads.MRFields {
+	if issue, err := e.beads.Show(mrID); err == nil {
+		if fields := beads.ParseMRFields(issue); fields != nil {
+			return fields
+		}
+	}
+	return &beads.MRFields{}
+}
+
+// saveMRFields writes fields back to the MR bead's description.
+func (e *Engineer) saveMRFields(mrID string, fields *beads.MRFields) {
+	issue, err := e.beads.Show(mrID)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to fetch MR bead %s: %v\n", mrID, err)
+		return
+	}
+	desc := beads.SetMRFields(issue, fields)
+	if err := e.beads.Update(mrID, beads.UpdateOptions{Description: &desc}); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to update MR %s: %v\n", mrID, err)
+	}
+}
+
+// prTitle is the source issue's title and ID, falling back to the MR title.
+func (e *Engineer) prTitle(mr *MRInfo) string {
+	if mr.SourceIssue != "" {
+		if issue, err := e.beads.Show(mr.SourceIssue); err == nil && issue != nil && issue.Title != "" {
+			return fmt.Sprintf("%s (%s)", issue.Title, mr.SourceIssue)
+		}
+	}
+	return strings.TrimPrefix(mr.Title, "Merge: ")
+}
+
+func (e *Engineer) prBody(mr *MRInfo) string {
+	lines := []string{"Opened by the Gas Town refinery."}
+	if mr.SourceIssue != "" {
+		lines = append(lines, "", "Issue: "+mr.SourceIssue)
+	}
+	if mr.Worker != "" {
+		lines = append(lines, "Worker: "+mr.Worker)
+	}
+	lines = append(lines, "Merge request: "+mr.ID)
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,263 @@
+package refinery
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/forge"
+)
+
+// fakeForge is an in-memory forge holding a single pull request.
+type fakeForge struct {
+	pr       *forge.PullRequest
+	checks   forge.CheckState
+	reviews  []forge.Review
+	mergeErr error
+	created  []forge.NewPullRequest
+	merged   []string // merge methods used
+}
+
+func (f *fakeForge) Type() string { return "fake" }
+
+func (f *fakeForge) FindPR(ctx context.Context, head, base string) (*forge.PullRequest, error) {
+	if f.pr != nil && f.pr.Head == head && f.pr.State == forge.PROpen {
+		return f.pr, nil
+	}
+	return nil, nil
+}
+
+func (f *fakeForge) CreatePR(ctx context.Context, req forge.NewPullRequest) (*forge.PullRequest, error) {
+	f.created = append(f.created, req)
+	f.pr = &forge.PullRequest{Number: 40 + len(f.created), URL: "https://forge/pr", State: forge.PROpen, Head: req.Head, HeadSHA: "abc", Base: req.Base}
+	return f.pr, nil
+}
+
+func (f *fakeForge) GetPR(ctx context.Context, number int) (*forge.PullRequest, error) {
+	return f.pr, nil
+}
+
+func (f *fakeForge) Checks(ctx context.Context, pr *forge.PullRequest) (*forge.CheckSummary, error) {
+	if f.checks == forge.ChecksNone {
+		return &forge.CheckSummary{State: forge.ChecksNone}, nil
+	}
+	return &forge.CheckSummary{State: f.checks, Checks: []forge.Check{{Name: "ci", State: f.checks}}}, nil
+}
+
+func (f *fakeForge) Reviews(ctx context.Context, number int) ([]forge.Review, error) {
+	return f.reviews, nil
+}
+
+func (f *fakeForge) Merge(ctx context.Context, pr *forge.PullRequest, method, title string) (string, error) {
+	if f.mergeErr != nil {
+		return "", f.mergeErr
+	}
+	f.merged = append(f.merged, method)
+	pr.State, pr.MergeCommit = forge.PRMerged, "fff"
+	return "fff", nil
+}
+
+func prEngineer(t *testing.T, f *fakeForge) (*Engineer, *beads.MemoryStore, *MRInfo) {
+	t.Helper()
+	store := beads.NewMemoryStore("gt")
+	e := memoryEngineer(store)
+	e.forge, e.forgeMergeMethod = f, forge.MergeMethodSquash
+
+	issue, err := store.Create(beads.CreateOptions{
+		Title:       "Merge: gt-1",
+		Type:        "merge-request",
+		Priority:    2,
+		Description: "branch: polecat/a\ntarget: main\nworker: Toast\nmerge_strategy: pr",
+		Ephemeral:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mrs, err := e.ListReadyMRs()
+	if err != nil || len(mrs) != 1 {
+		t.Fatalf("ListReadyMRs = %v, %v", mrs, err)
+	}
+	if mrs[0].ID != issue.ID || mrs[0].MergeStrategy != MergeStrategyPR {
+		t.Fatalf("MRInfo = %+v", mrs[0])
+	}
+	return e, store, mrs[0]
+}
+
+// recordPR stores an already-open pull request number on the MR bead.
+func recordPR(t *testing.T, store *beads.MemoryStore, mr *MRInfo, number int) {
+	t.Helper()
+	issue, err := store.Show(mr.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := beads.ParseMRFields(issue)
+	fields.PRNumber = number
+	desc := beads.SetMRFields(issue, fields)
+	if err := store.Update(mr.ID, beads.UpdateOptions{Description: &desc}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProcessPR_OpensWaitsAndMerges(t *testing.T) {
+	// Checks are green from the start: a new PR still waits a patrol, since
+	// the forge hasn't computed checks or mergeability for it yet.
+	f := &fakeForge{checks: forge.ChecksSuccess}
+	e, store, mr := prEngineer(t, f)
+	ctx := context.Background()
+
+	result := e.processPR(ctx, mr)
+	if !result.Pending || result.Success || result.FailureType() != FailureNone || len(f.merged) != 0 {
+		t.Fatalf("new PR: result = %+v, merged = %v", result, f.merged)
+	}
+	if len(f.created) != 1 || f.created[0].Head != "polecat/a" || f.created[0].Base != "main" {
+		t.Errorf("created = %+v", f.created)
+	}
+	issue, _ := store.Show(mr.ID)
+	if fields := beads.ParseMRFields(issue); fields.PRNumber != 41 || fields.PRURL == "" {
+		t.Errorf("PR not recorded on MR: %+v", fields)
+	}
+
+	// The PR is reused, not reopened.
+	f.checks = forge.ChecksPending
+	if result = e.processPR(ctx, mr); !result.Pending {
+		t.Fatalf("pending checks: result = %+v", result)
+	}
+	f.checks = forge.ChecksSuccess
+	result = e.processPR(ctx, mr)
+	if !result.Success || result.MergeCommit != "fff" || len(f.created) != 1 {
+		t.Errorf("green PR: result = %+v, created = %d", result, len(f.created))
+	}
+	if len(f.merged) != 1 || f.merged[0] != forge.MergeMethodSquash {
+		t.Errorf("merged = %v", f.merged)
+	}
+}
+
+func TestProcessPR_FailuresAndWaits(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(f *fakeForge)
+		check func(t *testing.T, r ProcessResult)
+	}{
+		{"checks failed", func(f *fakeForge) { f.checks = forge.ChecksFailure }, func(t *testing.T, r ProcessResult) {
+			if !r.TestsFailed || !strings.Contains(r.Error, "ci") {
+				t.Errorf("result = %+v", r)
+			}
+		}},
+		{"conflicted", func(f *fakeForge) { f.pr.Conflicted = true }, func(t *testing.T, r ProcessResult) {
+			if !r.Conflict {
+				t.Errorf("result = %+v", r)
+			}
+		}},
+		{"blocked by branch protection", func(f *fakeForge) { f.pr.Blocked = true }, func(t *testing.T, r ProcessResult) {
+			if !r.Pending {
+				t.Errorf("result = %+v", r)
+			}
+		}},
+		{"mergeability not settled", func(f *fakeForge) { f.pr.MergeablePending = true }, func(t *testing.T, r ProcessResult) {
+			if !r.Pending {
+				t.Errorf("result = %+v", r)
+			}
+		}},
+		{"no checks reported yet", func(f *fakeForge) {
+			f.checks, f.pr.UpdatedAt = forge.ChecksNone, time.Now()
+		}, func(t *testing.T, r ProcessResult) {
+			if !r.Pending {
+				t.Errorf("result = %+v", r)
+			}
+		}},
+		{"no checks after the grace period", func(f *fakeForge) {
+			f.checks, f.pr.UpdatedAt = forge.ChecksNone, time.Now().Add(-prChecksGrace-time.Minute)
+		}, func(t *testing.T, r ProcessResult) {
+			if !r.Success {
+				t.Errorf("result = %+v", r)
+			}
+		}},
+		{"not mergeable yet", func(f *fakeForge) { f.mergeErr = &forge.APIError{Status: 405} }, func(t *testing.T, r ProcessResult) {
+			if !r.Pending {
+				t.Errorf("result = %+v", r)
+			}
+		}},
+		{"merged on the forge", func(f *fakeForge) { f.pr.State, f.pr.MergeCommit = forge.PRMerged, "eee" }, func(t *testing.T, r ProcessResult) {
+			if !r.Success || r.MergeCommit != "eee" {
+				t.Errorf("result = %+v", r)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeForge{checks: forge.ChecksSuccess}
+			e, store, mr := prEngineer(t, f)
+			recordPR(t, store, mr, 9)
+			f.pr = &forge.PullRequest{Number: 9, State: forge.PROpen, Head: "polecat/a", HeadSHA: "abc", Base: "main"}
+			tt.setup(f)
+			tt.check(t, e.processPR(context.Background(), mr))
+		})
+	}
+}
+
+func TestProcessPR_ReviewRework(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	f := &fakeForge{
+		checks: forge.ChecksSuccess,
+		pr:     &forge.PullRequest{Number: 9, State: forge.PROpen, Head: "polecat/a", HeadSHA: "abc", Base: "main"},
+		reviews: []forge.Review{
+			{ID: "review-1", Author: "mayor", State: forge.ReviewApproved, CreatedAt: t0},
+			{ID: "comment-2", Author: "crew", State: forge.ReviewCommented, Body: "rename this", Path: "main.go", Line: 3, CreatedAt: t0.Add(time.Minute)},
+		},
+	}
+	e, store, mr := prEngineer(t, f)
+	recordPR(t, store, mr, 9)
+	ctx := context.Background()
+
+	result := e.processPR(ctx, mr)
+	if !result.ReviewRework || len(result.Reviews) != 1 || result.FailureType() != FailureReview {
+		t.Fatalf("result = %+v", result)
+	}
+	if len(f.merged) != 0 {
+		t.Error("PR with unaddressed review must not be merged")
+	}
+
+	taskID, err := e.createReviewReworkTaskForMR(mr, result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task, _ := store.Show(taskID)
+	if !strings.Contains(task.Description, "main.go:3") || !strings.Contains(task.Description, "rename this") {
+		t.Errorf("task description = %q", task.Description)
+	}
+
+	// The same feedback is not reported twice.
+	result = e.processPR(ctx, mr)
+	if !result.Success {
+		t.Errorf("after rework: result = %+v", result)
+	}
+}
+
+func TestProcessPR_ClosedPRIsForgotten(t *testing.T) {
+	f := &fakeForge{pr: &forge.PullRequest{Number: 9, State: forge.PRClosed, Head: "polecat/a", Base: "main"}}
+	e, store, mr := prEngineer(t, f)
+	recordPR(t, store, mr, 9)
+
+	result := e.processPR(context.Background(), mr)
+	if !result.ReviewRework {
+		t.Fatalf("result = %+v", result)
+	}
+	issue, _ := store.Show(mr.ID)
+	if fields := beads.ParseMRFields(issue); fields.PRNumber != 0 {
+		t.Errorf("closed PR still recorded: %+v", fields)
+	}
+}
+
+func TestSelectBatch_SkipsPullRequestMRs(t *testing.T) {
+	now := time.Now()
+	mrs := []*MRInfo{
+		{ID: "pr", Target: "main", Priority: 0, CreatedAt: now, MergeStrategy: MergeStrategyPR},
+		{ID: "local", Target: "main", Priority: 2, CreatedAt: now},
+	}
+	got := SelectBatch(mrs, 4, NewScorer(DefaultScoreConfig(), now))
+	if len(got) != 1 || got[0].ID != "local" {
+		t.Errorf("SelectBatch = %v, want only the local MR", got)
+	}
+}
@@ -4,7 +4,11 @@
 package refinery
 
 import (
+	"fmt"
+	"math"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
 )
 
 // ScoreConfig contains tunable weights for MR priority scoring.
@@ -37,9 +41,46 @@ type ScoreConfig struct {
 	// MaxRetryPenalty caps the total retry penalty to prevent permanent deprioritization.
 	// Default: 300.0 (after 6 retries, penalty is capped)
 	MaxRetryPenalty float64
+
+	// DiffSizeWeight is subtracted per 100 changed lines, so small MRs land
+	// ahead of large ones with the same priority.
+	// Default: 0 (off; 5.0 makes a 1000-line diff lose 50 pts)
+	DiffSizeWeight float64
+
+	// MaxDiffSizePenalty caps the diff size penalty.
+	// Default: 100.0
+	MaxDiffSizePenalty float64
+
+	// OverlapPenalty is subtracted per other queued MR (same target) that
+	// touches at least one of the same files. Overlapping MRs are the likely
+	// conflicts; independent work flows first.
+	// Default: 0 (off; 25.0 is a reasonable setting)
+	OverlapPenalty float64
+
+	// MaxOverlapPenalty caps the overlap penalty.
+	// Default: 100.0
+	MaxOverlapPenalty float64
+
+	// FailureRateWeight is multiplied by the author's recent failure rate
+	// (0.0-1.0, see FailureRateWindow) and subtracted.
+	// Default: 0 (off; at 200.0 an author whose recent merges all failed loses 200 pts)
+	FailureRateWeight float64
+
+	// ExpediteBonus is added to MRs labeled for expediting (see ExpediteLabels).
+	// The label is the opt-in, so this is on by default.
+	// Default: 1000.0 (ahead of everything that isn't also expedited)
+	ExpediteBonus float64
 }
 
-// DefaultScoreConfig returns sensible defaults for MR scoring.
+// ExpediteLabels are the MR bead labels that request expedited merging.
+var ExpediteLabels = []string{"gt:expedite", "expedite"}
+
+// FailureRateWindow is how far back merge attempts count toward an author's failure rate.
+const FailureRateWindow = 7 * 24 * time.Hour
+
+// DefaultScoreConfig returns sensible defaults for MR scoring. The diff size,
+// overlap and failure rate factors are off, so the queue keeps its original
+// order until a rig opts in through merge_queue.scoring.
 func DefaultScoreConfig() ScoreConfig {
 	return ScoreConfig{
 		BaseScore:       1000.0,
@@ -48,9 +89,56 @@ func DefaultScoreConfig() ScoreConfig {
 		RetryPenalty:    50.0,
 		MRAgeWeight:     1.0,
 		MaxRetryPenalty: 300.0,
+
+		DiffSizeWeight:     0,
+		MaxDiffSizePenalty: 100.0,
+		OverlapPenalty:     0,
+		MaxOverlapPenalty:  100.0,
+		FailureRateWeight:  0,
+		ExpediteBonus:      1000.0,
 	}
 }
 
+// ScoreConfigFromSettings applies rig settings overrides to DefaultScoreConfig.
+func ScoreConfigFromSettings(s *config.MergeQueueScoringConfig) ScoreConfig {
+	cfg := DefaultScoreConfig()
+	if s == nil {
+		return cfg
+	}
+	for _, o := range []struct {
+		dst *float64
+		src *float64
+	}{
+		{&cfg.BaseScore, s.BaseScore},
+		{&cfg.ConvoyAgeWeight, s.ConvoyAgeWeight},
+		{&cfg.PriorityWeight, s.PriorityWeight},
+		{&cfg.RetryPenalty, s.RetryPenalty},
+		{&cfg.MaxRetryPenalty, s.MaxRetryPenalty},
+		{&cfg.MRAgeWeight, s.MRAgeWeight},
+		{&cfg.DiffSizeWeight, s.DiffSizeWeight},
+		{&cfg.MaxDiffSizePenalty, s.MaxDiffSizePenalty},
+		{&cfg.OverlapPenalty, s.OverlapPenalty},
+		{&cfg.MaxOverlapPenalty, s.MaxOverlapPenalty},
+		{&cfg.FailureRateWeight, s.FailureRateWeight},
+		{&cfg.ExpediteBonus, s.ExpediteBonus},
+	} {
+		if o.src != nil {
+			*o.dst = *o.src
+		}
+	}
+	return cfg
+}
+
+// LoadScoreConfig returns the scoring weights for the rig at rigPath:
+// DefaultScoreConfig with merge_queue.scoring overrides from settings/config.json.
+func LoadScoreConfig(rigPath string) ScoreConfig {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil || settings.MergeQueue == nil {
+		return DefaultScoreConfig()
+	}
+	return ScoreConfigFromSettings(settings.MergeQueue.Scoring)
+}
+
 // ScoreInput contains the data needed to score an MR.
 // This struct decouples scoring from the MR struct, allowing the
 // caller to provide convoy age from external lookups.
@@ -69,11 +157,38 @@ type ScoreInput struct {
 	// 0 = first attempt.
 	RetryCount int
 
+	// LinesChanged is the MR's diff size (added + deleted lines vs. its target).
+	LinesChanged int
+
+	// OverlappingMRs is how many other queued MRs touch the same files.
+	OverlappingMRs int
+
+	// AuthorFailureRate is the fraction (0.0-1.0) of the author's recent
+	// merge attempts that failed rather than merged.
+	AuthorFailureRate float64
+
+	// Expedite is true if the MR carries one of the ExpediteLabels.
+	Expedite bool
+
 	// Now is the current time (for deterministic testing).
 	// If zero, time.Now() is used.
 	Now time.Time
 }
 
+// ScoreFactor is one term of an MR's priority score.
+type ScoreFactor struct {
+	Name   string  `json:"name"`
+	Points float64 `json:"points"` // Signed contribution to the score
+	Detail string  `json:"detail"` // Input that produced the points
+}
+
+// ScoreBreakdown is an MR's priority score with its per-factor terms.
+// The factors always sum to Total.
+type ScoreBreakdown struct {
+	Total   float64       `json:"total"`
+	Factors []ScoreFactor `json:"factors"`
+}
+
 // ScoreMR calculates the priority score for a merge request.
 // Higher scores mean higher priority (process first).
 //
@@ -84,21 +199,39 @@ type ScoreInput struct {
 //	      + PriorityWeight * (4 - priority)          // P0=+400, P4=+0
 //	      - min(RetryPenalty * retryCount, MaxRetryPenalty)  // Prevent thrashing
 //	      + MRAgeWeight * hoursOld(MR)               // FIFO tiebreaker
+//	      - min(DiffSizeWeight * lines/100, MaxDiffSizePenalty)    // Small first
+//	      - min(OverlapPenalty * overlaps, MaxOverlapPenalty)      // Independent first
+//	      - FailureRateWeight * authorFailureRate    // Flaky authors later
+//	      + ExpediteBonus (if expedited)             // Explicit override
 func ScoreMR(input ScoreInput, config ScoreConfig) float64 {
+	return ExplainScore(input, config).Total
+}
+
+// ExplainScore calculates the priority score like ScoreMR and returns the
+// contribution of each factor.
+func ExplainScore(input ScoreInput, config ScoreConfig) ScoreBreakdown {
 	now := input.Now
 	if now.IsZero() {
 		now = time.Now()
 	}
 
-	score := config.BaseScore
+	var b ScoreBreakdown
+	add := func(name string, points float64, detail string) {
+		b.Factors = append(b.Factors, ScoreFactor{Name: name, Points: points, Detail: detail})
+		b.Total += points
+	}
+
+	add("base", config.BaseScore, "")
 
 	// Convoy age factor: prevent starvation of old convoys
 	if input.ConvoyCreatedAt != nil {
-		convoyAge := now.Sub(*input.ConvoyCreatedAt)
-		convoyHours := convoyAge.Hours()
-		if convoyHours > 0 {
-			score += config.ConvoyAgeWeight * convoyHours
+		convoyHours := now.Sub(*input.ConvoyCreatedAt).Hours()
+		if convoyHours < 0 {
+			convoyHours = 0
 		}
+		add("convoy_age", config.ConvoyAgeWeight*convoyHours, fmt.Sprintf("convoy %.1fh old", convoyHours))
+	} else {
+		add("convoy_age", 0, "no convoy")
 	}
 
 	// Priority factor: P0 (0) gets +400, P4 (4) gets +0
@@ -109,23 +242,39 @@ func ScoreMR(input ScoreInput, config ScoreConfig) float64 {
 	if priorityBonus > 4 {
 		priorityBonus = 4 // Clamp for invalid priorities < 0
 	}
-	score += config.PriorityWeight * float64(priorityBonus)
+	add("priority", config.PriorityWeight*float64(priorityBonus), fmt.Sprintf("P%d", input.Priority))
 
 	// Retry penalty: prevent thrashing on repeatedly failing MRs
-	retryPenalty := config.RetryPenalty * float64(input.RetryCount)
-	if retryPenalty > config.MaxRetryPenalty {
-		retryPenalty = config.MaxRetryPenalty
-	}
-	score -= retryPenalty
+	retryPenalty := math.Min(config.RetryPenalty*float64(input.RetryCount), config.MaxRetryPenalty)
+	add("retry", -retryPenalty, fmt.Sprintf("%d retries", input.RetryCount))
 
 	// MR age factor: FIFO ordering as tiebreaker
-	mrAge := now.Sub(input.MRCreatedAt)
-	mrHours := mrAge.Hours()
-	if mrHours > 0 {
-		score += config.MRAgeWeight * mrHours
+	mrHours := now.Sub(input.MRCreatedAt).Hours()
+	if mrHours < 0 {
+		mrHours = 0
 	}
+	add("mr_age", config.MRAgeWeight*mrHours, fmt.Sprintf("submitted %.1fh ago", mrHours))
+
+	// Diff size: small changes are cheap to gate and rarely conflict
+	diffPenalty := math.Min(config.DiffSizeWeight*float64(input.LinesChanged)/100, config.MaxDiffSizePenalty)
+	add("diff_size", -diffPenalty, fmt.Sprintf("%d lines changed", input.LinesChanged))
+
+	// Overlap: MRs touching the same files as other queued MRs likely conflict
+	overlapPenalty := math.Min(config.OverlapPenalty*float64(input.OverlappingMRs), config.MaxOverlapPenalty)
+	add("overlap", -overlapPenalty, fmt.Sprintf("shares files with %d queued MR(s)", input.OverlappingMRs))
 
-	return score
+	// Author failure rate: authors whose recent MRs failed go after reliable ones
+	add("failure_rate", -config.FailureRateWeight*input.AuthorFailureRate,
+		fmt.Sprintf("author failure rate %.0f%%", input.AuthorFailureRate*100))
+
+	// Expedite: explicit operator override
+	if input.Expedite {
+		add("expedite", config.ExpediteBonus, "expedite label")
+	} else {
+		add("expedite", 0, "not expedited")
+	}
+
+	return b
 }
 
 // ScoreMRWithDefaults is a convenience wrapper using default config.
@@ -140,13 +289,7 @@ func (mr *MRInfo) Score() float64 {
 }
 
 // ScoreAt calculates the priority score at a specific time (for deterministic testing).
+// Only the per-MR factors apply; use a Scorer for the queue-relative ones.
 func (mr *MRInfo) ScoreAt(now time.Time) float64 {
-	input := ScoreInput{
-		Priority:        mr.Priority,
-		MRCreatedAt:     mr.CreatedAt,
-		ConvoyCreatedAt: mr.ConvoyCreatedAt,
-		RetryCount:      mr.RetryCount,
-		Now:             now,
-	}
-	return ScoreMRWithDefaults(input)
+	return NewScorer(DefaultScoreConfig(), now).Score(mr)
 }
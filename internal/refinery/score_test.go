@@ -0,0 +1,222 @@
+package refinery
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// queueScoreConfig is DefaultScoreConfig with the opt-in queue-relative
+// factors turned on at their suggested weights.
+func queueScoreConfig() ScoreConfig {
+	cfg := DefaultScoreConfig()
+	cfg.DiffSizeWeight = 5
+	cfg.OverlapPenalty = 25
+	cfg.FailureRateWeight = 200
+	return cfg
+}
+
+func TestExplainScore_FactorsSumToTotal(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	convoy := now.Add(-10 * time.Hour)
+	input := ScoreInput{
+		Priority:          1,
+		MRCreatedAt:       now.Add(-2 * time.Hour),
+		ConvoyCreatedAt:   &convoy,
+		RetryCount:        2,
+		Now:               now,
+		LinesChanged:      400,
+		OverlappingMRs:    1,
+		AuthorFailureRate: 0.5,
+		Expedite:          true,
+	}
+	b := ExplainScore(input, queueScoreConfig())
+
+	want := map[string]float64{
+		"base":         1000,
+		"convoy_age":   100,
+		"priority":     300,
+		"retry":        -100,
+		"mr_age":       2,
+		"diff_size":    -20,
+		"overlap":      -25,
+		"failure_rate": -100,
+		"expedite":     1000,
+	}
+	var sum float64
+	for _, f := range b.Factors {
+		sum += f.Points
+		if w, ok := want[f.Name]; !ok || math.Abs(f.Points-w) > 1e-9 {
+			t.Errorf("factor %s = %v, want %v", f.Name, f.Points, w)
+		}
+	}
+	if len(b.Factors) != len(want) {
+		t.Errorf("got %d factors, want %d", len(b.Factors), len(want))
+	}
+	if math.Abs(sum-b.Total) > 1e-9 || math.Abs(b.Total-2157) > 1e-9 {
+		t.Errorf("Total = %v (factor sum %v), want 2157", b.Total, sum)
+	}
+	if got := ScoreMR(input, queueScoreConfig()); got != b.Total {
+		t.Errorf("ScoreMR = %v, want ExplainScore total %v", got, b.Total)
+	}
+}
+
+func TestExplainScore_PenaltiesAreCapped(t *testing.T) {
+	now := time.Now()
+	cfg := queueScoreConfig()
+	b := ExplainScore(ScoreInput{
+		Priority:       4,
+		MRCreatedAt:    now,
+		Now:            now,
+		RetryCount:     100,
+		LinesChanged:   1000000,
+		OverlappingMRs: 50,
+	}, cfg)
+	for _, f := range b.Factors {
+		switch f.Name {
+		case "retry":
+			if f.Points != -cfg.MaxRetryPenalty {
+				t.Errorf("retry = %v, want %v", f.Points, -cfg.MaxRetryPenalty)
+			}
+		case "diff_size":
+			if f.Points != -cfg.MaxDiffSizePenalty {
+				t.Errorf("diff_size = %v, want %v", f.Points, -cfg.MaxDiffSizePenalty)
+			}
+		case "overlap":
+			if f.Points != -cfg.MaxOverlapPenalty {
+				t.Errorf("overlap = %v, want %v", f.Points, -cfg.MaxOverlapPenalty)
+			}
+		}
+	}
+}
+
+func TestScoreConfigFromSettings(t *testing.T) {
+	if got := ScoreConfigFromSettings(nil); got != DefaultScoreConfig() {
+		t.Errorf("nil settings = %+v, want defaults", got)
+	}
+
+	weight, bonus := 5.0, 50.0
+	got := ScoreConfigFromSettings(&config.MergeQueueScoringConfig{
+		DiffSizeWeight: &weight,
+		ExpediteBonus:  &bonus,
+	})
+	want := DefaultScoreConfig()
+	want.DiffSizeWeight = 5
+	want.ExpediteBonus = 50
+	if got != want {
+		t.Errorf("ScoreConfigFromSettings = %+v, want %+v", got, want)
+	}
+}
+
+func TestScorer_QueueRelativeFactors(t *testing.T) {
+	now := time.Now()
+	queue := []*MRInfo{
+		{ID: "a", Target: "main", Worker: "nux", CreatedAt: now},
+		{ID: "b", Target: "main", Worker: "toast", CreatedAt: now},
+		{ID: "c", Target: "main", Worker: "toast", CreatedAt: now},
+		{ID: "d", Target: "integration/x", Worker: "toast", CreatedAt: now},
+	}
+	s := NewScorer(queueScoreConfig(), now)
+	s.SetDiffs(queue, map[string][]git.DiffFileStat{
+		"a": {{Path: "shared.go", Added: 10}, {Path: "a.go", Added: 5, Deleted: 5}},
+		"b": {{Path: "shared.go", Deleted: 1}},
+		"c": {{Path: "c.go", Added: 1}},
+		"d": {{Path: "shared.go", Added: 1}}, // different target: no overlap
+	})
+
+	if in := s.Input(queue[0]); in.LinesChanged != 20 || in.OverlappingMRs != 1 {
+		t.Errorf("a: lines=%d overlaps=%d, want 20 and 1", in.LinesChanged, in.OverlappingMRs)
+	}
+	if in := s.Input(queue[2]); in.OverlappingMRs != 0 {
+		t.Errorf("c: overlaps=%d, want 0", in.OverlappingMRs)
+	}
+	if in := s.Input(queue[3]); in.OverlappingMRs != 0 {
+		t.Errorf("d: overlaps=%d, want 0 (other target)", in.OverlappingMRs)
+	}
+
+	// c is independent, so it outranks a and b despite equal priority and age.
+	if ranked := s.Rank(queue[:3]); ranked[0].ID != "c" {
+		t.Errorf("Rank()[0] = %s, want c", ranked[0].ID)
+	}
+
+	// Expedite beats everything else.
+	queue[1].Expedite = true
+	if ranked := s.Rank(queue); ranked[0].ID != "b" {
+		t.Errorf("Rank()[0] = %s, want expedited b", ranked[0].ID)
+	}
+}
+
+func TestScorer_DefaultsKeepOriginalOrder(t *testing.T) {
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	queue := []*MRInfo{
+		{ID: "big", Target: "main", Worker: "nux", Priority: 2, CreatedAt: now.Add(-3 * time.Hour)},
+		{ID: "small", Target: "main", Worker: "toast", Priority: 2, CreatedAt: now.Add(-time.Hour)},
+	}
+	s := NewScorer(DefaultScoreConfig(), now)
+	s.SetDiffs(queue, map[string][]git.DiffFileStat{
+		"big":   {{Path: "shared.go", Added: 5000}},
+		"small": {{Path: "other.go", Added: 1}},
+	})
+	s.SetFailureRates([]events.Record{
+		{Event: events.Event{Type: events.TypeMergeFailed, Payload: map[string]interface{}{"worker": "nux"}}},
+	})
+
+	// Only the original factors (priority, convoy age, retries, MR age) apply.
+	if ranked := s.Rank(queue); ranked[0].ID != "big" {
+		t.Errorf("Rank()[0] = %s, want older big MR", ranked[0].ID)
+	}
+	for _, mr := range queue {
+		bare := ScoreMR(ScoreInput{Priority: mr.Priority, MRCreatedAt: mr.CreatedAt, Now: now}, DefaultScoreConfig())
+		if got := s.Score(mr); got != bare {
+			t.Errorf("%s: score %v, want %v from the original factors", mr.ID, got, bare)
+		}
+	}
+}
+
+func TestWorkerFailureRates(t *testing.T) {
+	attempt := func(eventType, worker string) events.Record {
+		return events.Record{Event: events.Event{
+			Type:    eventType,
+			Actor:   "gastown/refinery",
+			Payload: events.MergePayload("gt-mr", worker, "polecat/"+worker, ""),
+		}}
+	}
+	recs := []events.Record{
+		attempt(events.TypeMergeFailed, "nux"),
+		attempt(events.TypeMergeFailed, "nux"),
+		attempt(events.TypeMerged, "nux"),
+		attempt(events.TypeMergeSkipped, "nux"), // not an attempt outcome
+		attempt(events.TypeMerged, "toast"),
+		attempt(events.TypeMergeFailed, ""), // no worker
+	}
+
+	rates := workerFailureRates(recs)
+	if math.Abs(rates["nux"]-2.0/3.0) > 1e-9 {
+		t.Errorf("nux rate = %v, want 2/3", rates["nux"])
+	}
+	if rates["toast"] != 0 {
+		t.Errorf("toast rate = %v, want 0", rates["toast"])
+	}
+	if _, ok := rates["slit"]; ok {
+		t.Error("worker with no merge attempts should have no rate")
+	}
+}
+
+func TestMRInfoFromIssue_Expedite(t *testing.T) {
+	issue := &beads.Issue{ID: "gt-mr-1", Labels: []string{"gt:merge-request", "gt:expedite"}}
+	mr := MRInfoFromIssue(issue, nil)
+	if !mr.Expedite {
+		t.Error("gt:expedite label should set Expedite")
+	}
+	if mr.ID != "gt-mr-1" {
+		t.Errorf("ID = %q", mr.ID)
+	}
+	if MRInfoFromIssue(&beads.Issue{ID: "gt-mr-2"}, nil).Expedite {
+		t.Error("unlabeled MR should not be expedited")
+	}
+}
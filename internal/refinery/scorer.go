@@ -0,0 +1,206 @@
+package refinery
+
+import (
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// Scorer scores MRs against the rest of the queue. The queue-relative
+// factors (diff size, file overlap, author failure rate) need git and beads
+// lookups, so they are gathered once and reused for every MR.
+//
+// A Scorer with no diffs or failure rates loaded scores on the per-MR
+// factors only, matching ScoreMR on a bare ScoreInput.
+type Scorer struct {
+	Config ScoreConfig
+	Now    time.Time
+
+	lines        map[string]int     // MR ID → lines changed
+	overlaps     map[string]int     // MR ID → other queued MRs sharing a file
+	failureRates map[string]float64 // worker → recent failure rate
+}
+
+// NewScorer returns a Scorer with no queue-relative data loaded.
+func NewScorer(cfg ScoreConfig, now time.Time) *Scorer {
+	return &Scorer{Config: cfg, Now: now}
+}
+
+// NewScorer returns a Scorer for queue using the rig's scoring weights,
+// the queued branches' diffs against their targets, and the authors'
+// failure rates over FailureRateWindow. Data for factors whose weight is
+// zero is not gathered. Lookups that fail are skipped: the affected factor
+// scores zero rather than blocking the queue.
+func (e *Engineer) NewScorer(queue []*MRInfo, now time.Time) *Scorer {
+	s := NewScorer(LoadScoreConfig(e.rig.Path), now)
+
+	if s.Config.DiffSizeWeight != 0 || s.Config.OverlapPenalty != 0 {
+		diffs := make(map[string][]git.DiffFileStat, len(queue))
+		for _, mr := range queue {
+			if stats, ok := e.diffStats(mr); ok {
+				diffs[mr.ID] = stats
+			}
+		}
+		s.SetDiffs(queue, diffs)
+	}
+
+	if s.Config.FailureRateWeight != 0 {
+		recs, err := events.OpenStore(e.townRoot()).Query(events.Query{
+			Types:  []string{events.TypeMerged, events.TypeMergeFailed},
+			Actors: []string{e.rig.Name + "/refinery"},
+			Since:  now.Add(-FailureRateWindow),
+		})
+		if err == nil {
+			s.SetFailureRates(recs)
+		}
+	}
+	return s
+}
+
+// diffStats returns the files mr changes relative to its target, preferring
+// the remote-tracking refs the refinery merges from.
+func (e *Engineer) diffStats(mr *MRInfo) ([]git.DiffFileStat, bool) {
+	if mr.Branch == "" || mr.Target == "" {
+		return nil, false
+	}
+	if stats, err := e.git.DiffNumstat("origin/"+mr.Target, "origin/"+mr.Branch); err == nil {
+		return stats, true
+	}
+	if stats, err := e.git.DiffNumstat(mr.Target, mr.Branch); err == nil {
+		return stats, true
+	}
+	return nil, false
+}
+
+// SetDiffs records each MR's diff (by MR ID) and counts, per MR, the other
+// MRs in queue with the same target that change at least one of its files.
+func (s *Scorer) SetDiffs(queue []*MRInfo, diffs map[string][]git.DiffFileStat) {
+	s.lines = make(map[string]int, len(diffs))
+	files := make(map[string]map[string]bool, len(diffs))
+	for id, stats := range diffs {
+		set := make(map[string]bool, len(stats))
+		for _, st := range stats {
+			s.lines[id] += st.Added + st.Deleted
+			set[st.Path] = true
+		}
+		files[id] = set
+	}
+	s.overlaps = countOverlaps(queue, files)
+}
+
+// countOverlaps returns, for each MR with known files, how many other MRs
+// targeting the same branch touch at least one of the same files.
+func countOverlaps(queue []*MRInfo, files map[string]map[string]bool) map[string]int {
+	overlaps := make(map[string]int)
+	for i, a := range queue {
+		for _, b := range queue[i+1:] {
+			if a.Target != b.Target || !sharesFile(files[a.ID], files[b.ID]) {
+				continue
+			}
+			overlaps[a.ID]++
+			overlaps[b.ID]++
+		}
+	}
+	return overlaps
+}
+
+func sharesFile(a, b map[string]bool) bool {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	for path := range a {
+		if b[path] {
+			return true
+		}
+	}
+	return false
+}
+
+// SetFailureRates computes each worker's failure rate from the refinery's
+// merged and merge_failed events: of the worker's merge attempts, the
+// fraction that failed. Every failed attempt counts, so an MR that failed
+// twice before merging counts as two failures out of three attempts.
+// Callers pass the events within FailureRateWindow.
+func (s *Scorer) SetFailureRates(recs []events.Record) {
+	s.failureRates = workerFailureRates(recs)
+}
+
+func workerFailureRates(recs []events.Record) map[string]float64 {
+	type tally struct{ failed, total int }
+	tallies := make(map[string]*tally)
+	for _, rec := range recs {
+		worker, _ := rec.Payload["worker"].(string)
+		if worker == "" {
+			continue
+		}
+		var failed bool
+		switch rec.Type {
+		case events.TypeMerged:
+		case events.TypeMergeFailed:
+			failed = true
+		default:
+			continue
+		}
+		t := tallies[worker]
+		if t == nil {
+			t = &tally{}
+			tallies[worker] = t
+		}
+		t.total++
+		if failed {
+			t.failed++
+		}
+	}
+
+	rates := make(map[string]float64, len(tallies))
+	for worker, t := range tallies {
+		rates[worker] = float64(t.failed) / float64(t.total)
+	}
+	return rates
+}
+
+// Input builds the ScoreInput for mr.
+func (s *Scorer) Input(mr *MRInfo) ScoreInput {
+	createdAt := mr.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = s.Now
+	}
+	return ScoreInput{
+		Priority:          mr.Priority,
+		MRCreatedAt:       createdAt,
+		ConvoyCreatedAt:   mr.ConvoyCreatedAt,
+		RetryCount:        mr.RetryCount,
+		Now:               s.Now,
+		LinesChanged:      s.lines[mr.ID],
+		OverlappingMRs:    s.overlaps[mr.ID],
+		AuthorFailureRate: s.failureRates[mr.Worker],
+		Expedite:          mr.Expedite,
+	}
+}
+
+// Score returns mr's priority score.
+func (s *Scorer) Score(mr *MRInfo) float64 {
+	return ScoreMR(s.Input(mr), s.Config)
+}
+
+// Explain returns mr's priority score with its per-factor breakdown.
+func (s *Scorer) Explain(mr *MRInfo) ScoreBreakdown {
+	return ExplainScore(s.Input(mr), s.Config)
+}
+
+// Rank returns a copy of mrs ordered by score, highest first.
+// Ties keep their original order.
+func (s *Scorer) Rank(mrs []*MRInfo) []*MRInfo {
+	scores := make(map[*MRInfo]float64, len(mrs))
+	for _, mr := range mrs {
+		scores[mr] = s.Score(mr)
+	}
+	sorted := make([]*MRInfo, len(mrs))
+	copy(sorted, mrs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return scores[sorted[i]] > scores[sorted[j]]
+	})
+	return sorted
+}
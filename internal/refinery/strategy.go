@@ -0,0 +1,161 @@
+package refinery
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// errNotFastForward is returned by landBranch when the ff-only strategy is
+// used and the branch does not descend from the target.
+var errNotFastForward = errors.New("branch does not fast-forward the target")
+
+// mergeStrategy returns the configured merge strategy, defaulting to squash.
+func (e *Engineer) mergeStrategy() string {
+	if e.config.MergeStrategy == "" {
+		return config.MergeStrategySquash
+	}
+	return e.config.MergeStrategy
+}
+
+// landBranch lands mr's branch on the commit checked out in g (the target
+// branch in doMerge, a detached stack head in batches) using the rig's merge
+// strategy. On failure g is left where it started; conflicts lists the
+// conflicting files when the strategy stopped on conflicts.
+func (e *Engineer) landBranch(g *git.Git, mr *MRInfo) (conflicts []string, err error) {
+	switch strategy := e.mergeStrategy(); strategy {
+	case config.MergeStrategySquash:
+		if err := g.MergeSquash(mr.Branch, e.squashCommitMessage(g, mr)); err != nil {
+			conflicts, _ = g.GetConflictingFiles()
+			// A squash merge leaves no MERGE_HEAD to abort; reset the index instead.
+			_ = g.ResetHard("HEAD")
+			return conflicts, err
+		}
+		return nil, nil
+
+	case config.MergeStrategyMergeCommit:
+		if err := g.MergeNoFF(mr.Branch, e.mergeCommitMessage(mr)); err != nil {
+			conflicts, _ = g.GetConflictingFiles()
+			_ = g.AbortMerge()
+			return conflicts, err
+		}
+		return nil, nil
+
+	case config.MergeStrategyFFOnly:
+		ok, err := g.IsAncestor("HEAD", mr.Branch)
+		if err != nil {
+			return nil, fmt.Errorf("checking fast-forward: %w", err)
+		}
+		if !ok {
+			return nil, errNotFastForward
+		}
+		return nil, g.MergeFFOnly(mr.Branch)
+
+	case config.MergeStrategyRebaseFF:
+		return e.rebaseAndFastForward(g, mr)
+
+	default:
+		return nil, fmt.Errorf("unknown merge strategy %q", strategy)
+	}
+}
+
+// rebaseAndFastForward replays the branch's commits onto the checked-out
+// commit and fast-forwards to the result. The rebase runs on a detached
+// HEAD, so the polecat's branch itself is never rewritten.
+func (e *Engineer) rebaseAndFastForward(g *git.Git, mr *MRInfo) ([]string, error) {
+	base, err := g.Rev("HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("reading HEAD: %w", err)
+	}
+	// Return to the target branch by name, or to the same commit when it
+	// was detached (batch stacks).
+	restore := base
+	if branch, err := g.CurrentBranch(); err == nil && branch != "HEAD" {
+		restore = branch
+	}
+
+	if err := g.CheckoutDetached(mr.Branch); err != nil {
+		return nil, fmt.Errorf("checking out %s: %w", mr.Branch, err)
+	}
+	if err := g.Rebase(base); err != nil {
+		conflicts, _ := g.GetConflictingFiles()
+		_ = g.AbortRebase()
+		_ = g.Checkout(restore)
+		return conflicts, fmt.Errorf("rebase onto %s: %w", shortSHA(base), err)
+	}
+	rebased, err := g.Rev("HEAD")
+	if err == nil {
+		err = g.Checkout(restore)
+	}
+	if err == nil {
+		err = g.MergeFFOnly(rebased)
+	}
+	if err != nil {
+		_ = g.Checkout(restore)
+		return nil, err
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Rebased %s onto %s\n", mr.Branch, shortSHA(base))
+	return nil, nil
+}
+
+// squashCommitMessage is the message for landing mr as a single commit:
+// "<source issue title> (<issue id>)", the subjects of the squashed commits,
+// and trailers naming the issue, MR and polecat. Without a readable source
+// issue it falls back to the branch's HEAD message.
+func (e *Engineer) squashCommitMessage(g *git.Git, mr *MRInfo) string {
+	var title string
+	if mr.SourceIssue != "" && e.beads != nil {
+		if issue, err := e.beads.Show(mr.SourceIssue); err == nil && issue != nil && issue.Title != "" {
+			title = fmt.Sprintf("%s (%s)", issue.Title, mr.SourceIssue)
+		}
+	}
+	if title == "" {
+		return git.WithTrailers(e.squashMessage(mr.Branch, mr.Target, mr.SourceIssue), e.mrTrailers(mr)...)
+	}
+
+	msg := title
+	if subjects, err := g.CommitSubjects("HEAD", mr.Branch); err == nil && len(subjects) > 0 {
+		if len(subjects) > 1 || subjects[0] != title {
+			msg += "\n\n- " + strings.Join(subjects, "\n- ")
+		}
+	}
+	return git.WithTrailers(msg, e.mrTrailers(mr)...)
+}
+
+// mergeCommitMessage is the message for a --no-ff merge of mr.
+func (e *Engineer) mergeCommitMessage(mr *MRInfo) string {
+	msg := fmt.Sprintf("Merge branch '%s' into %s", mr.Branch, mr.Target)
+	if mr.SourceIssue != "" && e.beads != nil {
+		if issue, err := e.beads.Show(mr.SourceIssue); err == nil && issue != nil && issue.Title != "" {
+			msg += fmt.Sprintf("\n\n%s (%s)", issue.Title, mr.SourceIssue)
+		}
+	}
+	return git.WithTrailers(msg, e.mrTrailers(mr)...)
+}
+
+// mrTrailers are the commit trailers that tie a landed commit back to its
+// source issue, merge request and polecat.
+func (e *Engineer) mrTrailers(mr *MRInfo) []git.Trailer {
+	polecat := ""
+	if mr.Worker != "" {
+		polecat = mr.Worker
+		if !strings.Contains(polecat, "/") {
+			polecat = fmt.Sprintf("%s/polecats/%s", e.rig.Name, mr.Worker)
+		}
+	}
+	return []git.Trailer{
+		{Key: "Issue", Value: mr.SourceIssue},
+		{Key: "Merge-Request", Value: mr.ID},
+		{Key: "Polecat", Value: polecat},
+	}
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
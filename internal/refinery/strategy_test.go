@@ -0,0 +1,163 @@
+package refinery
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// originLog returns "<one * per parent> <subject>" for each commit on origin's
+// main, newest first.
+func originLog(t *testing.T, origin string) []string {
+	t.Helper()
+	out, err := exec.Command("git", "--git-dir", origin, "log", "--topo-order", "--format=%P%x09%s", "main").Output()
+	if err != nil {
+		t.Fatalf("git log: %v", err)
+	}
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		parents, subject, _ := strings.Cut(line, "\t")
+		lines = append(lines, strings.Repeat("*", len(strings.Fields(parents)))+" "+subject)
+	}
+	return lines
+}
+
+func originMessage(t *testing.T, origin string) string {
+	t.Helper()
+	out, err := exec.Command("git", "--git-dir", origin, "log", "-1", "--format=%B", "main").Output()
+	if err != nil {
+		t.Fatalf("git log: %v", err)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func strategyMR(id, branch string) *MRInfo {
+	return &MRInfo{ID: id, Branch: branch, Target: "main", Worker: "Toast"}
+}
+
+func TestDoMerge_Squash(t *testing.T) {
+	e, origin := batchTestRig(t, map[string]string{"polecat/a": "a.txt"})
+	store := beads.NewMemoryStore("gt")
+	e.beads = store
+	issue, err := store.Create(beads.CreateOptions{Title: "Add a", Type: "task"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mr := strategyMR("gt-mr1", "polecat/a")
+	mr.SourceIssue = issue.ID
+
+	if r := e.doMerge(context.Background(), mr); !r.Success {
+		t.Fatalf("doMerge = %+v", r)
+	}
+	want := "Add a (" + issue.ID + ")\n\n- feat: polecat/a\n\nIssue: " + issue.ID +
+		"\nMerge-Request: gt-mr1\nPolecat: testrig/polecats/Toast"
+	if got := originMessage(t, origin); got != want {
+		t.Errorf("squash message = %q, want %q", got, want)
+	}
+}
+
+func TestDoMerge_MergeCommit(t *testing.T) {
+	e, origin := batchTestRig(t, map[string]string{"polecat/a": "a.txt"})
+	e.config.MergeStrategy = config.MergeStrategyMergeCommit
+
+	if r := e.doMerge(context.Background(), strategyMR("gt-mr1", "polecat/a")); !r.Success {
+		t.Fatalf("doMerge = %+v", r)
+	}
+	log := originLog(t, origin)
+	if len(log) != 3 || log[0] != "** Merge branch 'polecat/a' into main" || log[1] != "* feat: polecat/a" {
+		t.Errorf("origin log = %q", log)
+	}
+}
+
+func TestDoMerge_RebaseFF(t *testing.T) {
+	e, origin := batchTestRig(t, map[string]string{"polecat/a": "a.txt", "polecat/b": "b.txt"})
+	e.config.MergeStrategy = config.MergeStrategyRebaseFF
+	ctx := context.Background()
+
+	for _, mr := range []*MRInfo{strategyMR("gt-mr1", "polecat/a"), strategyMR("gt-mr2", "polecat/b")} {
+		if r := e.doMerge(ctx, mr); !r.Success {
+			t.Fatalf("doMerge(%s) = %+v", mr.Branch, r)
+		}
+	}
+	log := originLog(t, origin)
+	want := []string{"* feat: polecat/b", "* feat: polecat/a", " initial"}
+	if strings.Join(log, "|") != strings.Join(want, "|") {
+		t.Errorf("origin log = %q, want linear %q", log, want)
+	}
+
+	// The polecat's branch is not rewritten by the rebase.
+	if ok, _ := e.git.IsAncestor("polecat/b", "main"); ok {
+		t.Error("polecat/b was rewritten onto main")
+	}
+}
+
+func TestDoMerge_FFOnly(t *testing.T) {
+	e, origin := batchTestRig(t, map[string]string{"polecat/a": "a.txt", "polecat/b": "b.txt"})
+	e.config.MergeStrategy = config.MergeStrategyFFOnly
+	ctx := context.Background()
+
+	tip, _ := e.git.Rev("polecat/a")
+	if r := e.doMerge(ctx, strategyMR("gt-mr1", "polecat/a")); !r.Success || r.MergeCommit != tip {
+		t.Fatalf("fast-forward should land the branch tip %s itself: %+v", tip, r)
+	}
+
+	// polecat/b was cut from the old main, so it no longer fast-forwards.
+	r := e.doMerge(ctx, strategyMR("gt-mr2", "polecat/b"))
+	if r.Success || !r.Conflict || !strings.Contains(r.Error, "rebase required") {
+		t.Errorf("diverged branch: result = %+v", r)
+	}
+	if log := originLog(t, origin); len(log) != 2 {
+		t.Errorf("origin log = %q, want initial + polecat/a", log)
+	}
+}
+
+func TestDoMerge_SyncsCleanCrewWorkspaces(t *testing.T) {
+	e, origin := batchTestRig(t, map[string]string{"polecat/a": "a.txt"})
+	crewDir := filepath.Join(e.rig.Path, "crew")
+	clone := func(name string, setup ...[]string) string {
+		t.Helper()
+		dir := filepath.Join(crewDir, name)
+		for _, args := range append([][]string{{"clone", "-q", origin, dir}}, setup...) {
+			cmd := exec.Command("git", args...)
+			if args[0] != "clone" {
+				cmd.Dir = dir
+			}
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("git %v: %v\n%s", args, err, out)
+			}
+		}
+		return dir
+	}
+	clean := clone("clean")
+	feature := clone("feature", []string{"checkout", "-q", "-b", "feature"})
+	dirty := clone("dirty")
+	if err := os.WriteFile(filepath.Join(dirty, "README.md"), []byte("edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if r := e.doMerge(context.Background(), strategyMR("gt-mr1", "polecat/a")); !r.Success {
+		t.Fatalf("doMerge = %+v", r)
+	}
+
+	if _, err := os.Stat(filepath.Join(clean, "a.txt")); err != nil {
+		t.Error("clean workspace on main was not fast-forwarded")
+	}
+	for name, dir := range map[string]string{"feature": feature, "dirty": dirty} {
+		if _, err := os.Stat(filepath.Join(dir, "a.txt")); err == nil {
+			t.Errorf("%s workspace was pulled", name)
+		}
+		out, err := exec.Command("git", "-C", dir, "log", "-1", "--format=%s", "origin/main").Output()
+		if err != nil || strings.TrimSpace(string(out)) != "feat: polecat/a" {
+			t.Errorf("%s workspace was not fetched: origin/main = %q, %v", name, out, err)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(dirty, "README.md")); string(data) != "edited\n" {
+		t.Error("dirty workspace's changes were touched")
+	}
+}
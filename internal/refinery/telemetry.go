@@ -0,0 +1,52 @@
+package refinery
+
+import (
+	"errors"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/telemetry"
+)
+
+// townRoot returns the town containing the engineer's rig.
+func (e *Engineer) townRoot() string {
+	return filepath.Dir(e.rig.Path)
+}
+
+// recordGate records a gate's run time and ends its span.
+func (e *Engineer) recordGate(span *telemetry.Span, r GateResult) {
+	result := "passed"
+	var err error
+	if !r.Success {
+		result = "failed"
+		err = errors.New(r.Error)
+	}
+	span.SetAttr("gate.result", result)
+	_ = span.End(err)
+	_ = telemetry.ObserveDuration(e.townRoot(), telemetry.GateDurationSeconds,
+		telemetry.Labels{"rig": e.rig.Name, "gate": r.Name, "result": result}, r.Elapsed)
+}
+
+// recordMergeOutcome counts a settled merge attempt and, for merged MRs,
+// how long the MR waited from creation.
+func (e *Engineer) recordMergeOutcome(mr *MRInfo, result ProcessResult) {
+	townRoot := e.townRoot()
+	outcome := "merged"
+	if !result.Success {
+		outcome = "failed"
+	}
+	_ = telemetry.Inc(townRoot, telemetry.MergesTotal, telemetry.Labels{
+		"rig":          e.rig.Name,
+		"result":       outcome,
+		"failure_type": string(result.FailureType()),
+	})
+	if result.Success && !mr.CreatedAt.IsZero() {
+		_ = telemetry.ObserveDuration(townRoot, telemetry.MQWaitSeconds,
+			telemetry.Labels{"rig": e.rig.Name}, time.Since(mr.CreatedAt))
+	}
+}
+
+// recordQueueDepth sets the ready-queue depth gauge.
+func (e *Engineer) recordQueueDepth(depth int) {
+	_ = telemetry.Set(e.townRoot(), telemetry.MQDepth, telemetry.Labels{"rig": e.rig.Name}, float64(depth))
+}
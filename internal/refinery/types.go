@@ -277,6 +277,9 @@ const (
 
 	// FailureCheckout indicates checkout of target branch failed.
 	FailureCheckout FailureType = "checkout_fail"
+
+	// FailureReview indicates reviewers requested changes on the MR's pull request.
+	FailureReview FailureType = "review"
 )
 
 // FailureLabel returns the beads label for this failure type.
@@ -284,7 +287,7 @@ func (f FailureType) FailureLabel() string {
 	switch f {
 	case FailureConflict:
 		return "needs-rebase"
-	case FailureTestsFail, FailureBuildFail, FailureFlakyTest:
+	case FailureTestsFail, FailureBuildFail, FailureFlakyTest, FailureReview:
 		return "needs-fix"
 	case FailurePushFail:
 		return "needs-retry"
@@ -296,7 +299,7 @@ func (f FailureType) FailureLabel() string {
 // ShouldAssignToWorker returns true if this failure should be assigned back to the worker.
 func (f FailureType) ShouldAssignToWorker() bool {
 	switch f {
-	case FailureConflict, FailureTestsFail, FailureBuildFail, FailureFlakyTest:
+	case FailureConflict, FailureTestsFail, FailureBuildFail, FailureFlakyTest, FailureReview:
 		return true
 	default:
 		return false
@@ -305,3 +305,24 @@ func TestFailureType_ShouldAssignToWorker(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessResult_FailureType(t *testing.T) {
+	tests := []struct {
+		name   string
+		result ProcessResult
+		want   FailureType
+	}{
+		{"success", ProcessResult{Success: true}, FailureNone},
+		{"conflict", ProcessResult{Conflict: true}, FailureConflict},
+		{"tests", ProcessResult{TestsFailed: true}, FailureTestsFail},
+		{"other", ProcessResult{Error: "push rejected"}, FailureBuildFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.result.FailureType(); got != tt.want {
+				t.Errorf("FailureType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -13,7 +13,6 @@ import (
 	"github.com/steveyegge/gastown/internal/gemini"
 	"github.com/steveyegge/gastown/internal/opencode"
 	"github.com/steveyegge/gastown/internal/templates/commands"
-	"github.com/steveyegge/gastown/internal/tmux"
 )
 
 func init() {
@@ -35,6 +34,11 @@ func init() {
 		// Copilot custom instructions stay in workDir — no --settings equivalent.
 		return copilot.EnsureSettingsAt(workDir, hooksDir, hooksFile)
 	})
+	config.RegisterHookInstaller("scripted", func(settingsDir, workDir, role, hooksDir, hooksFile string) error {
+		// The fake agent speaks the Claude hooks protocol, so it gets the same
+		// templates. It finds them by walking up from its work dir.
+		return claude.EnsureSettingsForRoleAt(settingsDir, role, hooksDir, hooksFile)
+	})
 }
 
 // EnsureSettingsForRole provisions all agent-specific configuration for a role.
@@ -129,8 +133,14 @@ func StartupFallbackCommands(role string, rc *config.RuntimeConfig) []string {
 	return []string{command}
 }
 
-// RunStartupFallback sends the startup fallback commands via tmux.
-func RunStartupFallback(t *tmux.Tmux, sessionID, role string, rc *config.RuntimeConfig) error {
+// Nudger delivers a message to an agent session. *tmux.Tmux and the
+// headless PTY backend both implement it.
+type Nudger interface {
+	NudgeSession(session, message string) error
+}
+
+// RunStartupFallback sends the startup fallback commands to the session.
+func RunStartupFallback(t Nudger, sessionID, role string, rc *config.RuntimeConfig) error {
 	commands := StartupFallbackCommands(role, rc)
 	for _, cmd := range commands {
 		if err := t.NudgeSession(sessionID, cmd); err != nil {
@@ -0,0 +1,87 @@
+package session
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/pty"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Session backends selectable with the town's session_backend setting.
+const (
+	BackendTmux = "tmux"
+	BackendPTY  = "pty"
+)
+
+// Backend is the set of session operations the lifecycle code needs.
+// *tmux.Tmux is the default backend; *pty.Client runs sessions headless
+// under the daemon's PTY supervisor, for containers and CI without tmux.
+type Backend interface {
+	IsAvailable() bool
+	NewSessionWithCommand(name, workDir, command string) error
+	NewSessionWithCommandAndEnv(name, workDir, command string, env map[string]string) error
+	EnsureSessionFresh(name, workDir string) error
+	KillSession(name string) error
+	KillSessionWithProcesses(name string) error
+	KillSessionWithProcessesExcluding(name string, excludePIDs []string) error
+	CleanupOrphanedSessions(isGTSession func(string) bool) (int, error)
+	HasSession(name string) (bool, error)
+	ListSessions() ([]string, error)
+	AttachSession(session string) error
+
+	SendKeys(session, keys string) error
+	SendKeysRaw(session, keys string) error
+	SendKeysDebounced(session, keys string, debounceMs int) error
+	NudgeSession(session, message string) error
+	NudgePane(pane, message string) error
+	SendNotificationBanner(session, from, subject string) error
+	CapturePane(session string, lines int) (string, error)
+	CapturePaneLines(session string, lines int) ([]string, error)
+	GetSessionActivity(session string) (time.Time, error)
+	SetEnvironment(session, key, value string) error
+	GetEnvironment(session, key string) (string, error)
+
+	IsAgentAlive(session string) bool
+	IsAgentRunning(session string, expectedPaneCommands ...string) bool
+	CheckSessionHealth(session string, maxInactivity time.Duration) tmux.ZombieStatus
+	GetPanePID(target string) (string, error)
+	GetPaneID(session string) (string, error)
+	GetPaneWorkDir(session string) (string, error)
+	GetSessionCreatedUnix(session string) (int64, error)
+	GetSessionInfo(name string) (*tmux.SessionInfo, error)
+
+	SetRemainOnExit(pane string, on bool) error
+	SetAutoRespawnHook(session string) error
+	SetPaneDiedHook(session, agentID string) error
+	ConfigureGasTownSession(session string, theme tmux.Theme, rig, worker, role string) error
+	SetCrewCycleBindings(session string) error
+	WaitForCommand(session string, excludeCommands []string, timeout time.Duration) error
+	WaitForRuntimeReady(session string, rc *config.RuntimeConfig, timeout time.Duration) error
+	WaitForIdle(session string, timeout time.Duration) error
+	AcceptBypassPermissionsWarning(session string) error
+}
+
+var (
+	_ Backend = (*tmux.Tmux)(nil)
+	_ Backend = (*pty.Client)(nil)
+)
+
+// UsePTY reports whether the town at townRoot runs sessions under the
+// headless PTY supervisor (session_backend "pty" in settings/config.json).
+func UsePTY(townRoot string) bool {
+	if townRoot == "" {
+		return false
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	return err == nil && settings.SessionBackend == BackendPTY
+}
+
+// NewBackend returns the session backend the town's settings select.
+// The PTY backend talks to the supervisor hosted by the town's daemon.
+func NewBackend(townRoot string) Backend {
+	if UsePTY(townRoot) {
+		return pty.NewClient(pty.SocketPath(townRoot))
+	}
+	return tmux.NewTmux()
+}
@@ -0,0 +1,32 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/pty"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+func TestNewBackend_FollowsTownSetting(t *testing.T) {
+	townRoot := t.TempDir()
+
+	if _, ok := NewBackend(townRoot).(*tmux.Tmux); !ok {
+		t.Error("a town without session_backend should use tmux")
+	}
+	if _, ok := NewBackend("").(*tmux.Tmux); !ok {
+		t.Error("no town root should use tmux")
+	}
+
+	settings := config.NewTownSettings()
+	settings.SessionBackend = BackendPTY
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	if !UsePTY(townRoot) {
+		t.Error("UsePTY = false with session_backend pty")
+	}
+	if _, ok := NewBackend(townRoot).(*pty.Client); !ok {
+		t.Error("session_backend pty should use the PTY client")
+	}
+}
@@ -8,8 +8,6 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
-
-	"github.com/steveyegge/gastown/internal/tmux"
 )
 
 // pidStartTimeFunc is overridden in tests. This package's tests must NOT use
@@ -41,7 +39,7 @@ func pidFile(townRoot, sessionID string) string {
 // This is best-effort — errors are returned but callers should treat them
 // as non-fatal since the primary kill mechanism (KillSessionWithProcesses)
 // doesn't depend on PID files.
-func TrackSessionPID(townRoot, sessionID string, t *tmux.Tmux) error {
+func TrackSessionPID(townRoot, sessionID string, t Backend) error {
 	pidStr, err := t.GetPanePID(sessionID)
 	if err != nil {
 		return fmt.Errorf("getting pane PID: %w", err)
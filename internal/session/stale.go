@@ -4,13 +4,10 @@ import (
 	"fmt"
 	"strings"
 	"time"
-
-	"github.com/steveyegge/gastown/internal/tmux"
 )
 
-// SessionCreatedAt returns the time a tmux session was created.
-func SessionCreatedAt(sessionName string) (time.Time, error) {
-	t := tmux.NewTmux()
+// SessionCreatedAt returns the time a session was created.
+func SessionCreatedAt(t Backend, sessionName string) (time.Time, error) {
 	info, err := t.GetSessionInfo(sessionName)
 	if err != nil {
 		return time.Time{}, err
@@ -4,12 +4,13 @@ import (
 	"bytes"
 	"fmt"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/polecat"
-	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/session"
 )
 
 // LandingConfig configures the landing protocol.
@@ -58,7 +59,7 @@ func (m *Manager) ExecuteLanding(swarmID string, config LandingConfig) (*Landing
 	}
 
 	// Phase 1: Stop all polecat sessions
-	t := tmux.NewTmux()
+	t := session.NewBackend(filepath.Dir(m.rig.Path))
 	polecatMgr := polecat.NewSessionManager(t, m.rig)
 
 	for _, worker := range swarm.Workers {
@@ -0,0 +1,91 @@
+package telemetry
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the Prometheus text exposition format media type.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// WritePrometheus writes the snapshot in Prometheus text format. Every
+// metric's HELP and TYPE lines are written even when it has no series yet.
+func WritePrometheus(w io.Writer, snap *Snapshot) error {
+	bw := bufio.NewWriter(w)
+	for _, m := range Metrics {
+		fmt.Fprintf(bw, "# HELP %s %s\n", m.Name, m.Help)
+		fmt.Fprintf(bw, "# TYPE %s %s\n", m.Name, m.Kind)
+		for _, s := range snap.SortedSeries(m.Name) {
+			if m.Kind != KindHistogram {
+				fmt.Fprintf(bw, "%s%s %s\n", m.Name, formatLabels(s.Labels, "", ""), formatFloat(s.Value))
+				continue
+			}
+			var cumulative uint64
+			for i, bound := range m.Buckets {
+				if i < len(s.Buckets) {
+					cumulative += s.Buckets[i]
+				}
+				fmt.Fprintf(bw, "%s_bucket%s %d\n", m.Name, formatLabels(s.Labels, "le", formatFloat(bound)), cumulative)
+			}
+			fmt.Fprintf(bw, "%s_bucket%s %d\n", m.Name, formatLabels(s.Labels, "le", "+Inf"), s.Count)
+			fmt.Fprintf(bw, "%s_sum%s %s\n", m.Name, formatLabels(s.Labels, "", ""), formatFloat(s.Sum))
+			fmt.Fprintf(bw, "%s_count%s %d\n", m.Name, formatLabels(s.Labels, "", ""), s.Count)
+		}
+	}
+	return bw.Flush()
+}
+
+// formatLabels renders {k="v",...} with keys sorted, plus an optional
+// extra pair (the histogram "le" label) last.
+func formatLabels(l Labels, extraKey, extraValue string) string {
+	if len(l) == 0 && extraKey == "" {
+		return ""
+	}
+	keys := make([]string, 0, len(l))
+	for k := range l {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys)+1)
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", k, escapeLabel(l[k])))
+	}
+	if extraKey != "" {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", extraKey, extraValue))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// escapeLabel leaves only characters that %q renders the way the
+// exposition format expects: it drops control characters other than
+// newline, which %q escapes as \n.
+func escapeLabel(v string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 && r != '\n' {
+			return -1
+		}
+		return r
+	}, v)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Handler serves the town's metric snapshot in Prometheus text format.
+func Handler(townRoot string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		snap, err := Load(townRoot)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", ContentType)
+		_ = WritePrometheus(w, snap)
+	})
+}
@@ -0,0 +1,301 @@
+// Package telemetry records town-wide metrics and trace spans.
+//
+// Gas Town runs as many short-lived gt processes plus the daemon, so metrics
+// are not kept in memory: every observation updates a shared snapshot at
+// ~/gt/.runtime/telemetry/metrics.json under a file lock. The daemon serves
+// the snapshot in Prometheus text format, and 'gt metrics' prints it.
+//
+// Nothing is recorded unless telemetry is enabled in town settings
+// (settings/config.json "telemetry"). Recording never fails the caller's
+// operation; callers discard the returned error.
+package telemetry
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Metric names.
+const (
+	PolecatSpawnSeconds     = "gt_polecat_spawn_seconds"
+	PolecatFirstHookSeconds = "gt_polecat_first_hook_seconds"
+	MQDepth                 = "gt_mq_depth"
+	MQWaitSeconds           = "gt_mq_wait_seconds"
+	GateDurationSeconds     = "gt_gate_duration_seconds"
+	MergesTotal             = "gt_merges_total"
+	SessionDeathsTotal      = "gt_session_deaths_total"
+	SessionRestartsTotal    = "gt_session_restarts_total"
+	DoltQueryLatencySeconds = "gt_dolt_query_latency_seconds"
+	MailMessagesTotal       = "gt_mail_messages_total"
+)
+
+// Kind is a Prometheus metric type.
+type Kind string
+
+const (
+	KindCounter   Kind = "counter"
+	KindGauge     Kind = "gauge"
+	KindHistogram Kind = "histogram"
+)
+
+// Metric describes one metric family.
+type Metric struct {
+	Name    string
+	Help    string
+	Kind    Kind
+	Buckets []float64 // histogram upper bounds, ascending
+}
+
+// Histogram bucket sets, in seconds.
+var (
+	// workBuckets suit agent and merge-queue work: seconds to hours.
+	workBuckets = []float64{1, 5, 15, 30, 60, 120, 300, 600, 1800, 3600, 7200, 21600}
+	// queryBuckets suit database round trips.
+	queryBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+)
+
+// Metrics lists every metric the town records, in exposition order.
+var Metrics = []Metric{
+	{PolecatSpawnSeconds, "Time from polecat allocation until its session is started.", KindHistogram, workBuckets},
+	{PolecatFirstHookSeconds, "Time from polecat spawn until the polecat first picks up its hooked work.", KindHistogram, workBuckets},
+	{MQDepth, "Merge requests ready for processing, as last seen by the refinery.", KindGauge, nil},
+	{MQWaitSeconds, "Time from merge request creation until it merged.", KindHistogram, workBuckets},
+	{GateDurationSeconds, "Refinery quality gate run time.", KindHistogram, workBuckets},
+	{MergesTotal, "Merge attempts by result and failure type.", KindCounter, nil},
+	{SessionDeathsTotal, "Agent sessions found dead by the daemon.", KindCounter, nil},
+	{SessionRestartsTotal, "Agent sessions restarted by the daemon.", KindCounter, nil},
+	{DoltQueryLatencySeconds, "Dolt server SELECT 1 round-trip time.", KindHistogram, queryBuckets},
+	{MailMessagesTotal, "Mail messages sent, by recipient address.", KindCounter, nil},
+}
+
+// Lookup returns the metric named name.
+func Lookup(name string) (Metric, bool) {
+	for _, m := range Metrics {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return Metric{}, false
+}
+
+// Labels are a series' label values.
+type Labels map[string]string
+
+// key returns the series key: name followed by sorted label pairs.
+func (l Labels) key(name string) string {
+	keys := make([]string, 0, len(l))
+	for k := range l {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString(name)
+	for _, k := range keys {
+		fmt.Fprintf(&b, ",%s=%s", k, l[k])
+	}
+	return b.String()
+}
+
+// Series is one labelled time series. Counters and gauges use Value;
+// histograms use Buckets (per-bucket, not cumulative), Count and Sum.
+type Series struct {
+	Name    string    `json:"name"`
+	Labels  Labels    `json:"labels,omitempty"`
+	Value   float64   `json:"value,omitempty"`
+	Buckets []uint64  `json:"buckets,omitempty"`
+	Count   uint64    `json:"count,omitempty"`
+	Sum     float64   `json:"sum,omitempty"`
+	Updated time.Time `json:"updated"`
+}
+
+// Snapshot is the persisted metric state.
+type Snapshot struct {
+	Series map[string]*Series `json:"series"`
+	// Spawns holds spawn times of polecats that have not yet picked up
+	// their hooked work, keyed by agent address.
+	Spawns map[string]time.Time `json:"spawns,omitempty"`
+}
+
+// SortedSeries returns the snapshot's series for name, ordered by labels.
+func (s *Snapshot) SortedSeries(name string) []*Series {
+	var out []*Series
+	for _, ser := range s.Series {
+		if ser.Name == name {
+			out = append(out, ser)
+		}
+	}
+	slices.SortFunc(out, func(a, b *Series) int {
+		return strings.Compare(a.Labels.key(""), b.Labels.key(""))
+	})
+	return out
+}
+
+// pendingSpawnTTL bounds how long an unhooked spawn is remembered.
+const pendingSpawnTTL = 24 * time.Hour
+
+// Dir returns the telemetry state directory for a town.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "telemetry")
+}
+
+func metricsPath(townRoot string) string {
+	return filepath.Join(Dir(townRoot), "metrics.json")
+}
+
+// Settings returns the town's telemetry settings, or nil when telemetry is
+// disabled or the town has no settings.
+func Settings(townRoot string) *config.TelemetryConfig {
+	if townRoot == "" {
+		return nil
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Telemetry == nil || !settings.Telemetry.Enabled {
+		return nil
+	}
+	return settings.Telemetry
+}
+
+// Enabled reports whether telemetry is turned on for the town.
+func Enabled(townRoot string) bool {
+	return Settings(townRoot) != nil
+}
+
+// Inc adds one to a counter.
+func Inc(townRoot, name string, labels Labels) error {
+	return record(townRoot, name, labels, func(m Metric, s *Series) { s.Value++ })
+}
+
+// Set sets a gauge.
+func Set(townRoot, name string, labels Labels, v float64) error {
+	return record(townRoot, name, labels, func(m Metric, s *Series) { s.Value = v })
+}
+
+// Observe adds an observation to a histogram.
+func Observe(townRoot, name string, labels Labels, v float64) error {
+	return record(townRoot, name, labels, func(m Metric, s *Series) { observe(m, s, v) })
+}
+
+// ObserveDuration adds a duration, in seconds, to a histogram.
+func ObserveDuration(townRoot, name string, labels Labels, d time.Duration) error {
+	return Observe(townRoot, name, labels, d.Seconds())
+}
+
+func observe(m Metric, s *Series, v float64) {
+	if len(s.Buckets) != len(m.Buckets)+1 {
+		s.Buckets = make([]uint64, len(m.Buckets)+1) // last bucket is +Inf
+	}
+	i, _ := slices.BinarySearch(m.Buckets, v)
+	s.Buckets[i]++
+	s.Count++
+	s.Sum += v
+}
+
+// MarkSpawned remembers when a polecat was spawned, for
+// PolecatFirstHookSeconds.
+func MarkSpawned(townRoot, agent string, at time.Time) error {
+	if !Enabled(townRoot) {
+		return nil
+	}
+	return update(townRoot, func(snap *Snapshot) {
+		if snap.Spawns == nil {
+			snap.Spawns = make(map[string]time.Time)
+		}
+		snap.Spawns[agent] = at
+	})
+}
+
+// MarkHooked records PolecatFirstHookSeconds the first time a spawned
+// polecat picks up its hooked work. Later calls are no-ops.
+func MarkHooked(townRoot, agent, rig string) error {
+	if !Enabled(townRoot) {
+		return nil
+	}
+	m, _ := Lookup(PolecatFirstHookSeconds)
+	return update(townRoot, func(snap *Snapshot) {
+		spawned, ok := snap.Spawns[agent]
+		if !ok {
+			return
+		}
+		delete(snap.Spawns, agent)
+		observe(m, snap.series(PolecatFirstHookSeconds, Labels{"rig": rig}), time.Since(spawned).Seconds())
+	})
+}
+
+func record(townRoot, name string, labels Labels, apply func(Metric, *Series)) error {
+	m, ok := Lookup(name)
+	if !ok {
+		return fmt.Errorf("unknown metric %q", name)
+	}
+	if !Enabled(townRoot) {
+		return nil
+	}
+	return update(townRoot, func(snap *Snapshot) {
+		apply(m, snap.series(name, labels))
+	})
+}
+
+// series returns the series for name and labels, creating it if needed.
+func (s *Snapshot) series(name string, labels Labels) *Series {
+	key := labels.key(name)
+	ser, ok := s.Series[key]
+	if !ok {
+		ser = &Series{Name: name, Labels: labels}
+		s.Series[key] = ser
+	}
+	ser.Updated = time.Now()
+	return ser
+}
+
+// update applies fn to the town's snapshot under the metrics lock.
+func update(townRoot string, fn func(*Snapshot)) error {
+	path := metricsPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking metrics: %w", err)
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	snap, err := Load(townRoot)
+	if err != nil {
+		return err
+	}
+	fn(snap)
+	for agent, at := range snap.Spawns {
+		if time.Since(at) > pendingSpawnTTL {
+			delete(snap.Spawns, agent)
+		}
+	}
+	return util.AtomicWriteJSON(path, snap)
+}
+
+// Load reads the town's metric snapshot. A missing snapshot is empty.
+func Load(townRoot string) (*Snapshot, error) {
+	snap := &Snapshot{Series: make(map[string]*Series)}
+	data, err := os.ReadFile(metricsPath(townRoot))
+	if os.IsNotExist(err) {
+		return snap, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, snap); err != nil {
+		return nil, fmt.Errorf("parsing metrics: %w", err)
+	}
+	if snap.Series == nil {
+		snap.Series = make(map[string]*Series)
+	}
+	return snap, nil
+}
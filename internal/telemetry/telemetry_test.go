@@ -0,0 +1,153 @@
+package telemetry
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func enableTelemetry(t *testing.T, cfg *config.TelemetryConfig) string {
+	t.Helper()
+	town := t.TempDir()
+	settings := config.NewTownSettings()
+	settings.Telemetry = cfg
+	if err := config.SaveTownSettings(config.TownSettingsPath(town), settings); err != nil {
+		t.Fatal(err)
+	}
+	return town
+}
+
+func TestRecord_DisabledWritesNothing(t *testing.T) {
+	town := t.TempDir()
+	if err := Inc(town, MergesTotal, Labels{"rig": "gastown"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(metricsPath(town)); !os.IsNotExist(err) {
+		t.Errorf("metrics written with telemetry disabled: %v", err)
+	}
+	if err := Inc(town, "gt_unknown", nil); err == nil {
+		t.Error("unknown metric should be rejected")
+	}
+}
+
+func TestWritePrometheus(t *testing.T) {
+	town := enableTelemetry(t, &config.TelemetryConfig{Enabled: true})
+	merged := Labels{"rig": "gastown", "result": "merged", "failure_type": ""}
+	for i := 0; i < 2; i++ {
+		if err := Inc(town, MergesTotal, merged); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Set(town, MQDepth, Labels{"rig": "gastown"}, 7); err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []time.Duration{2 * time.Millisecond, 30 * time.Millisecond, 10 * time.Second} {
+		if err := ObserveDuration(town, DoltQueryLatencySeconds, nil, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snap, err := Load(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := WritePrometheus(&b, snap); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"# TYPE gt_merges_total counter\n",
+		`gt_merges_total{failure_type="",result="merged",rig="gastown"} 2` + "\n",
+		`gt_mq_depth{rig="gastown"} 7` + "\n",
+		`gt_dolt_query_latency_seconds_bucket{le="0.001"} 0` + "\n",
+		`gt_dolt_query_latency_seconds_bucket{le="0.005"} 1` + "\n",
+		`gt_dolt_query_latency_seconds_bucket{le="0.05"} 2` + "\n",
+		`gt_dolt_query_latency_seconds_bucket{le="5"} 2` + "\n",
+		`gt_dolt_query_latency_seconds_bucket{le="+Inf"} 3` + "\n",
+		"gt_dolt_query_latency_seconds_count 3\n",
+		"# HELP gt_mail_messages_total ",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("exposition missing %q\n%s", want, out)
+		}
+	}
+}
+
+func TestFirstHook(t *testing.T) {
+	town := enableTelemetry(t, &config.TelemetryConfig{Enabled: true})
+	agent := "gastown/polecats/toast"
+	if err := MarkSpawned(town, agent, time.Now().Add(-90*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := MarkHooked(town, agent, "gastown"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snap, err := Load(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	series := snap.SortedSeries(PolecatFirstHookSeconds)
+	if len(series) != 1 || series[0].Count != 1 {
+		t.Fatalf("first hook series = %+v, want one observation", series)
+	}
+	if series[0].Sum < 90 || series[0].Sum > 120 {
+		t.Errorf("first hook = %vs, want about 90s", series[0].Sum)
+	}
+	if len(snap.Spawns) != 0 {
+		t.Errorf("pending spawns = %v, want none", snap.Spawns)
+	}
+}
+
+func TestSpan_WritesOTLP(t *testing.T) {
+	town := enableTelemetry(t, &config.TelemetryConfig{Enabled: true, TracesFile: "traces.jsonl"})
+	root := StartSpan(town, "refinery.merge", Labels{"rig": "gastown"})
+	child := root.Child("refinery.gate", Labels{"gate": "test"})
+	if err := child.End(errors.New("exit status 1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := root.End(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(filepath.Join(town, "traces.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var spans []otlpSpan
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var req otlpRequest
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			t.Fatal(err)
+		}
+		spans = append(spans, req.ResourceSpans[0].ScopeSpans[0].Spans...)
+	}
+	if len(spans) != 2 {
+		t.Fatalf("spans = %d, want 2", len(spans))
+	}
+	gate, merge := spans[0], spans[1]
+	if gate.TraceID != merge.TraceID || gate.ParentSpanID != merge.SpanID || len(merge.TraceID) != 32 {
+		t.Errorf("gate span not a child of merge span: %+v / %+v", gate, merge)
+	}
+	if gate.Status.Code != otlpStatusError || merge.Status.Code != otlpStatusOK {
+		t.Errorf("statuses = %d, %d", gate.Status.Code, merge.Status.Code)
+	}
+
+	// Tracing disabled: spans are dropped.
+	off := enableTelemetry(t, &config.TelemetryConfig{Enabled: true})
+	if err := StartSpan(off, "noop", nil).End(nil); err != nil {
+		t.Fatal(err)
+	}
+}
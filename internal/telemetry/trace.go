@@ -0,0 +1,214 @@
+package telemetry
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// ServiceName is the OTLP resource service.name of every span.
+const ServiceName = "gastown"
+
+// Span is one timed operation. Spans are written to the town's traces file
+// when they end; a span started with tracing disabled writes nothing.
+type Span struct {
+	townRoot string
+	path     string // traces file; empty when tracing is off
+	name     string
+	traceID  string
+	spanID   string
+	parentID string
+	start    time.Time
+	attrs    Labels
+}
+
+// StartSpan starts a root span.
+func StartSpan(townRoot, name string, attrs Labels) *Span {
+	return startSpan(townRoot, tracesPath(townRoot), name, randomHex(16), "", attrs)
+}
+
+// Child starts a span within the same trace.
+func (s *Span) Child(name string, attrs Labels) *Span {
+	return startSpan(s.townRoot, s.path, name, s.traceID, s.spanID, attrs)
+}
+
+func startSpan(townRoot, path, name, traceID, parentID string, attrs Labels) *Span {
+	return &Span{
+		townRoot: townRoot,
+		path:     path,
+		name:     name,
+		traceID:  traceID,
+		spanID:   randomHex(8),
+		parentID: parentID,
+		start:    time.Now(),
+		attrs:    attrs,
+	}
+}
+
+// Start returns when the span started.
+func (s *Span) Start() time.Time {
+	return s.start
+}
+
+// SetAttr sets an attribute recorded when the span ends.
+func (s *Span) SetAttr(key, value string) {
+	if s.attrs == nil {
+		s.attrs = Labels{}
+	}
+	s.attrs[key] = value
+}
+
+// End ends the span, marking it failed when err is non-nil, and appends it
+// to the traces file.
+func (s *Span) End(err error) error {
+	if s.path == "" {
+		return nil
+	}
+	return appendSpan(s.path, s.otlp(time.Now(), err))
+}
+
+// tracesPath returns the configured traces file, or "" when tracing is off.
+func tracesPath(townRoot string) string {
+	cfg := Settings(townRoot)
+	if cfg == nil || cfg.TracesFile == "" {
+		return ""
+	}
+	if filepath.IsAbs(cfg.TracesFile) {
+		return cfg.TracesFile
+	}
+	return filepath.Join(townRoot, cfg.TracesFile)
+}
+
+// OTLP/JSON encoding (opentelemetry-proto ExportTraceServiceRequest). Each
+// line of the traces file is one request holding one span, the layout the
+// OpenTelemetry Collector's file exporter and otlpjsonfile receiver use.
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpKeyValue struct {
+	Key   string        `json:"key"`
+	Value otlpAnyString `json:"value"`
+}
+
+type otlpAnyString struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// OTLP span kind and status codes.
+const (
+	otlpSpanKindInternal = 1
+	otlpStatusOK         = 1
+	otlpStatusError      = 2
+)
+
+func (s *Span) otlp(end time.Time, err error) otlpRequest {
+	span := otlpSpan{
+		TraceID:           s.traceID,
+		SpanID:            s.spanID,
+		ParentSpanID:      s.parentID,
+		Name:              s.name,
+		Kind:              otlpSpanKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(end.UnixNano(), 10),
+		Attributes:        keyValues(s.attrs),
+		Status:            otlpStatus{Code: otlpStatusOK},
+	}
+	if err != nil {
+		span.Status = otlpStatus{Code: otlpStatusError, Message: err.Error()}
+	}
+	resource := []otlpKeyValue{{Key: "service.name", Value: otlpAnyString{ServiceName}}}
+	if s.townRoot != "" {
+		resource = append(resource, otlpKeyValue{Key: "gastown.town", Value: otlpAnyString{filepath.Base(s.townRoot)}})
+	}
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: resource},
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: ServiceName}, Spans: []otlpSpan{span}}},
+	}}}
+}
+
+func keyValues(l Labels) []otlpKeyValue {
+	keys := make([]string, 0, len(l))
+	for k := range l {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	kvs := make([]otlpKeyValue, 0, len(keys))
+	for _, k := range keys {
+		kvs = append(kvs, otlpKeyValue{Key: k, Value: otlpAnyString{l[k]}})
+	}
+	return kvs
+}
+
+func appendSpan(path string, req otlpRequest) error {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking traces file: %w", err)
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
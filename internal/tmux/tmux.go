@@ -1701,12 +1701,12 @@ func (t *Tmux) WaitForShellReady(session string, timeout time.Duration) error {
 // See: gt deacon pending (ZFC-compliant AI observation)
 // See: gt deacon trigger-pending (bootstrap mode, regex-based)
 
-// matchesPromptPrefix reports whether a captured pane line matches the
+// MatchesPromptPrefix reports whether a captured pane line matches the
 // configured ready-prompt prefix. It normalizes non-breaking spaces
 // (U+00A0) to regular spaces before matching, because Claude Code uses
 // NBSP after its ❯ prompt character while the default ReadyPromptPrefix
 // uses a regular space. See https://github.com/steveyegge/gastown/issues/1387.
-func matchesPromptPrefix(line, readyPromptPrefix string) bool {
+func MatchesPromptPrefix(line, readyPromptPrefix string) bool {
 	if readyPromptPrefix == "" {
 		return false
 	}
@@ -1747,7 +1747,7 @@ func (t *Tmux) WaitForRuntimeReady(session string, rc *config.RuntimeConfig, tim
 		}
 		// Look for runtime prompt indicator at start of line
 		for _, line := range lines {
-			if matchesPromptPrefix(line, rc.Tmux.ReadyPromptPrefix) {
+			if MatchesPromptPrefix(line, rc.Tmux.ReadyPromptPrefix) {
 				return nil
 			}
 		}
@@ -1791,7 +1791,7 @@ func (t *Tmux) WaitForIdle(session string, timeout time.Duration) error {
 			if trimmed == "" {
 				continue
 			}
-			if matchesPromptPrefix(trimmed, promptPrefix) || (prefix != "" && trimmed == prefix) {
+			if MatchesPromptPrefix(trimmed, promptPrefix) || (prefix != "" && trimmed == prefix) {
 				return nil
 			}
 		}
@@ -1796,9 +1796,9 @@ func TestMatchesPromptPrefix(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := matchesPromptPrefix(tt.line, tt.prefix)
+			got := MatchesPromptPrefix(tt.line, tt.prefix)
 			if got != tt.want {
-				t.Errorf("matchesPromptPrefix(%q, %q) = %v, want %v",
+				t.Errorf("MatchesPromptPrefix(%q, %q) = %v, want %v",
 					tt.line, tt.prefix, got, tt.want)
 			}
 		})
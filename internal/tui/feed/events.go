@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
 )
 
 // EventSource represents a source of events
@@ -212,14 +213,14 @@ func parseBeadContext(beadID string) (actor, rig, role string) {
 	return
 }
 
-// GtEventsSource reads events from ~/gt/.events.jsonl (gt activity log)
+// GtEventsSource reads events from the town's event store (gt activity log)
 type GtEventsSource struct {
-	file   *os.File
+	store  *events.Store
 	events chan Event
 	cancel context.CancelFunc
 }
 
-// GtEvent is the structure of events in .events.jsonl
+// GtEvent is the structure of events in the event store
 type GtEvent struct {
 	Timestamp  string                 `json:"ts"`
 	Source     string                 `json:"source"`
@@ -229,18 +230,12 @@ type GtEvent struct {
 	Visibility string                 `json:"visibility"`
 }
 
-// NewGtEventsSource creates a source that tails ~/gt/.events.jsonl
+// NewGtEventsSource creates a source that tails the town's event store
 func NewGtEventsSource(townRoot string) (*GtEventsSource, error) {
-	eventsPath := filepath.Join(townRoot, ".events.jsonl")
-	file, err := os.Open(eventsPath)
-	if err != nil {
-		return nil, err
-	}
-
 	ctx, cancel := context.WithCancel(context.Background())
 
 	source := &GtEventsSource{
-		file:   file,
+		store:  events.OpenStore(townRoot),
 		events: make(chan Event, 200),
 		cancel: cancel,
 	}
@@ -250,76 +245,37 @@ func NewGtEventsSource(townRoot string) (*GtEventsSource, error) {
 	return source, nil
 }
 
-// tail loads recent history then follows the file for new events.
+// tail loads recent history then follows the store for new events.
 func (s *GtEventsSource) tail(ctx context.Context) {
 	defer close(s.events)
 
-	// Load recent events (last 200 lines) for initial display
+	// Load recent events for initial display
 	s.loadRecentEvents()
 
-	// Seek to true EOF so the tail scanner starts cleanly,
-	// regardless of the preload scanner's internal read-ahead buffer.
-	_, _ = s.file.Seek(0, 2)
-
-	// Now tail for new events
-	scanner := bufio.NewScanner(s.file)
-	ticker := time.NewTicker(100 * time.Millisecond)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			for scanner.Scan() {
-				line := scanner.Text()
-				if event := parseGtEventLine(line); event != nil {
-					select {
-					case s.events <- *event:
-					default:
-					}
-				}
+	end, err := s.store.End()
+	if err != nil {
+		return
+	}
+	for rec := range s.store.Subscribe(ctx, end, events.Query{}) {
+		if event := parseGtEventLine(rec.Line); event != nil {
+			select {
+			case s.events <- *event:
+			default:
 			}
 		}
 	}
 }
 
-// loadRecentEvents reads the last N lines of the file and emits them as events.
-// Uses a ring buffer so memory is O(maxLines) regardless of file size.
+// loadRecentEvents emits the last 200 feed-visible events.
 func (s *GtEventsSource) loadRecentEvents() {
-	const maxLines = 200
-
-	if _, err := s.file.Seek(0, 0); err != nil {
-		return
-	}
-
-	// Ring buffer: only keep the last maxLines lines in memory
-	ring := make([]string, maxLines)
-	idx := 0
-	count := 0
+	const maxEvents = 200
 
-	scanner := bufio.NewScanner(s.file)
-	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
-	for scanner.Scan() {
-		ring[idx%maxLines] = scanner.Text()
-		idx++
-		count++
-	}
-	if scanner.Err() != nil {
-		// Scanner failed (e.g. token too long) — seek to EOF so tail starts clean
-		_, _ = s.file.Seek(0, 2)
+	recs, err := s.store.Query(events.Query{Limit: maxEvents, Match: isFeedVisible})
+	if err != nil {
 		return
 	}
-
-	// Emit lines in order (oldest first)
-	n := count
-	if n > maxLines {
-		n = maxLines
-	}
-	start := idx - n
-	for i := start; i < idx; i++ {
-		line := ring[i%maxLines]
-		if event := parseGtEventLine(line); event != nil {
+	for _, rec := range recs {
+		if event := parseGtEventLine(rec.Line); event != nil {
 			select {
 			case s.events <- *event:
 			default:
@@ -328,6 +284,11 @@ func (s *GtEventsSource) loadRecentEvents() {
 	}
 }
 
+// isFeedVisible reports whether a raw event belongs in the feed.
+func isFeedVisible(e events.Event) bool {
+	return e.Visibility == events.VisibilityFeed || e.Visibility == events.VisibilityBoth
+}
+
 // Events returns the event channel
 func (s *GtEventsSource) Events() <-chan Event {
 	return s.events
@@ -336,10 +297,10 @@ func (s *GtEventsSource) Events() <-chan Event {
 // Close stops the source
 func (s *GtEventsSource) Close() error {
 	s.cancel()
-	return s.file.Close()
+	return nil
 }
 
-// parseGtEventLine parses a line from .events.jsonl
+// parseGtEventLine parses a line from the event store
 func parseGtEventLine(line string) *Event {
 	if strings.TrimSpace(line) == "" {
 		return nil
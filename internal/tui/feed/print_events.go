@@ -1,15 +1,14 @@
 package feed
 
 import (
-	"bufio"
 	"context"
 	"fmt"
 	"os"
 	"os/signal"
-	"path/filepath"
-	"sort"
 	"strings"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
 )
 
 // PrintOptions controls filtering and behavior for PrintGtEvents.
@@ -23,16 +22,14 @@ type PrintOptions struct {
 	Ctx    context.Context // optional: controls follow-mode lifecycle; nil uses signal.NotifyContext
 }
 
-// PrintGtEvents reads .events.jsonl and prints events to stdout.
-// When opts.Follow is true, it tails the file for new events after printing
-// the initial batch, polling every 200ms. Canceled via opts.Ctx or SIGINT.
+// PrintGtEvents reads the town's event store and prints events to stdout.
+// When opts.Follow is true, it tails the store for new events after printing
+// the initial batch. Canceled via opts.Ctx or SIGINT.
 func PrintGtEvents(townRoot string, opts PrintOptions) error {
-	eventsPath := filepath.Join(townRoot, ".events.jsonl")
-	file, err := os.Open(eventsPath)
-	if err != nil {
-		return fmt.Errorf("no events file found at %s: %w", eventsPath, err)
+	store := events.OpenStore(townRoot)
+	if !store.Exists() {
+		return fmt.Errorf("no event store found at %s", store.Dir())
 	}
-	defer file.Close()
 
 	// Parse --since into a cutoff time
 	var sinceTime time.Time
@@ -44,44 +41,40 @@ func PrintGtEvents(townRoot string, opts PrintOptions) error {
 		sinceTime = time.Now().Add(-dur)
 	}
 
-	var events []Event
-	scanner := bufio.NewScanner(file)
-	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		if event := parseGtEventLine(line); event != nil {
-			if matchesFilters(event, sinceTime, opts.Mol, opts.Type, opts.Rig) {
-				events = append(events, *event)
-			}
-		}
+	// The store filters by type and time; the rest needs the parsed event.
+	q := events.Query{Since: sinceTime}
+	if opts.Type != "" {
+		q.Types = []string{opts.Type}
 	}
-
-	if err := scanner.Err(); err != nil {
+	recs, err := store.Query(q)
+	if err != nil {
+		return fmt.Errorf("reading events: %w", err)
+	}
+	end, err := store.End()
+	if err != nil {
 		return fmt.Errorf("reading events: %w", err)
 	}
 
-	// Sort by time descending (most recent first)
-	sort.Slice(events, func(i, j int) bool {
-		return events[i].Time.After(events[j].Time)
-	})
-
-	// Apply limit
-	if opts.Limit > 0 && len(events) > opts.Limit {
-		events = events[:opts.Limit]
+	var matched []Event
+	for _, rec := range recs {
+		if event := parseGtEventLine(rec.Line); event != nil {
+			if matchesFilters(event, sinceTime, opts.Mol, opts.Type, opts.Rig) {
+				matched = append(matched, *event)
+			}
+		}
 	}
 
-	// Reverse to show oldest first (chronological)
-	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
-		events[i], events[j] = events[j], events[i]
+	// Apply limit, keeping the most recent (records are oldest first)
+	if opts.Limit > 0 && len(matched) > opts.Limit {
+		matched = matched[len(matched)-opts.Limit:]
 	}
 
-	if len(events) == 0 && !opts.Follow {
-		fmt.Println("No events found in .events.jsonl")
+	if len(matched) == 0 && !opts.Follow {
+		fmt.Println("No events found in the event store")
 		return nil
 	}
 
-	for _, event := range events {
+	for _, event := range matched {
 		printEvent(event)
 	}
 
@@ -89,10 +82,6 @@ func PrintGtEvents(townRoot string, opts PrintOptions) error {
 		return nil
 	}
 
-	// Tail mode: poll for new lines using a fresh scanner each tick.
-	// bufio.Scanner sets an internal 'done' flag after EOF and won't retry,
-	// so we must create a new scanner each poll cycle while preserving the
-	// file offset (os.File tracks position across scanner instances).
 	ctx := opts.Ctx
 	if ctx == nil {
 		var stop context.CancelFunc
@@ -100,26 +89,15 @@ func PrintGtEvents(townRoot string, opts PrintOptions) error {
 		defer stop()
 	}
 
-	ticker := time.NewTicker(200 * time.Millisecond)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		case <-ticker.C:
-			s := bufio.NewScanner(file)
-			s.Buffer(make([]byte, 1024*1024), 1024*1024)
-			for s.Scan() {
-				line := s.Text()
-				if event := parseGtEventLine(line); event != nil {
-					if matchesFilters(event, sinceTime, opts.Mol, opts.Type, opts.Rig) {
-						printEvent(*event)
-					}
-				}
+	q.Since = time.Time{}
+	for rec := range store.Subscribe(ctx, end, q) {
+		if event := parseGtEventLine(rec.Line); event != nil {
+			if matchesFilters(event, sinceTime, opts.Mol, opts.Type, opts.Rig) {
+				printEvent(*event)
 			}
 		}
 	}
+	return nil
 }
 
 // matchesFilters checks whether an event passes the --since, --mol, --type, and --rig filters.
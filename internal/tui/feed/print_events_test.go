@@ -2,33 +2,34 @@ package feed
 
 import (
 	"context"
-	"encoding/json"
 	"os"
-	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
 )
 
-// writeTestEvents writes GtEvent JSON lines to a temporary .events.jsonl file
+// writeTestEvents appends GtEvents to the event store of a temporary town
 // and returns the directory path (townRoot).
-func writeTestEvents(t *testing.T, events []GtEvent) string {
+func writeTestEvents(t *testing.T, evs []GtEvent) string {
 	t.Helper()
 	dir := t.TempDir()
-	eventsPath := filepath.Join(dir, ".events.jsonl")
-	var lines []string
-	for _, ev := range events {
-		b, err := json.Marshal(ev)
-		if err != nil {
-			t.Fatalf("marshal event: %v", err)
-		}
-		lines = append(lines, string(b))
+	appendTestEvents(t, dir, evs...)
+	return dir
+}
+
+// appendTestEvents appends GtEvents to the event store of townRoot.
+func appendTestEvents(t *testing.T, townRoot string, evs ...GtEvent) {
+	t.Helper()
+	var stored []events.Event
+	for _, ev := range evs {
+		stored = append(stored, events.Event(ev))
 	}
-	if err := os.WriteFile(eventsPath, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
-		t.Fatalf("write events file: %v", err)
+	if err := events.OpenStore(townRoot).Append(stored...); err != nil {
+		t.Fatalf("append events: %v", err)
 	}
-	return dir
 }
 
 func TestPrintGtEvents_ReadsAndFormats(t *testing.T) {
@@ -176,12 +177,12 @@ func TestPrintGtEvents_TypeFilter(t *testing.T) {
 }
 
 func TestPrintGtEvents_NoEventsFile(t *testing.T) {
-	dir := t.TempDir() // no .events.jsonl
+	dir := t.TempDir() // no event store
 	err := PrintGtEvents(dir, PrintOptions{Limit: 10})
 	if err == nil {
 		t.Fatal("expected error for missing events file")
 	}
-	if !strings.Contains(err.Error(), "no events file found") {
+	if !strings.Contains(err.Error(), "no event store found") {
 		t.Errorf("unexpected error: %v", err)
 	}
 }
@@ -234,15 +235,11 @@ func TestPrintGtEvents_InvalidSinceDuration(t *testing.T) {
 
 func TestPrintGtEvents_FollowStreamsAppended(t *testing.T) {
 	now := time.Now()
-	dir := t.TempDir()
-	eventsPath := filepath.Join(dir, ".events.jsonl")
-
 	// Write initial event
-	initial, _ := json.Marshal(GtEvent{
+	dir := writeTestEvents(t, []GtEvent{{
 		Timestamp: now.Format(time.RFC3339), Source: "test", Type: "create",
 		Actor: "a", Visibility: "feed", Payload: map[string]interface{}{"message": "initial"},
-	})
-	os.WriteFile(eventsPath, append(initial, '\n'), 0644)
+	}})
 
 	// Capture stdout
 	oldStdout := os.Stdout
@@ -263,13 +260,10 @@ func TestPrintGtEvents_FollowStreamsAppended(t *testing.T) {
 	// Wait for initial event to be printed, then append a second event
 	time.Sleep(500 * time.Millisecond)
 
-	appended, _ := json.Marshal(GtEvent{
+	appendTestEvents(t, dir, GtEvent{
 		Timestamp: now.Add(1 * time.Second).Format(time.RFC3339), Source: "test", Type: "sling",
 		Actor: "b", Visibility: "feed", Payload: map[string]interface{}{"bead": "gt-1", "target": "p1"},
 	})
-	f, _ := os.OpenFile(eventsPath, os.O_APPEND|os.O_WRONLY, 0644)
-	f.Write(append(appended, '\n'))
-	f.Close()
 
 	// Wait for the tail loop to pick it up, then cancel
 	time.Sleep(500 * time.Millisecond)
@@ -3,9 +3,9 @@ package web
 import (
 	"bytes"
 	"context"
-	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
@@ -15,6 +15,8 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/session"
 )
 
@@ -57,6 +59,10 @@ type APIHandler struct {
 	optionsCacheMu   sync.RWMutex
 	// cmdSem limits concurrent command executions to prevent resource exhaustion.
 	cmdSem chan struct{}
+	// audit records mutating calls.
+	audit AuditFunc
+	// hub feeds /api/events. Nil means the stream only sends keepalives.
+	hub *Hub
 }
 
 const optionsCacheTTL = 30 * time.Second
@@ -66,7 +72,11 @@ const optionsCacheTTL = 30 * time.Second
 const maxConcurrentCommands = 12
 
 // NewAPIHandler creates a new API handler with the given run timeouts.
-func NewAPIHandler(defaultRunTimeout, maxRunTimeout time.Duration) *APIHandler {
+// audit receives an event per mutating call; nil discards them.
+func NewAPIHandler(defaultRunTimeout, maxRunTimeout time.Duration, audit AuditFunc) *APIHandler {
+	if audit == nil {
+		audit = noAudit
+	}
 	// Use PATH lookup for gt binary. Do NOT use os.Executable() here - during
 	// tests it returns the test binary, causing fork bombs when executed.
 	workDir, _ := os.Getwd()
@@ -76,22 +86,36 @@ func NewAPIHandler(defaultRunTimeout, maxRunTimeout time.Duration) *APIHandler {
 		defaultRunTimeout: defaultRunTimeout,
 		maxRunTimeout:     maxRunTimeout,
 		cmdSem:            make(chan struct{}, maxConcurrentCommands),
+		audit:             audit,
 	}
 }
 
 // ServeHTTP routes API requests to the appropriate handler.
 func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	// Set CORS headers for dashboard
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
+	// CORS, authentication and CSRF checks happen in Auth, in front of this handler.
 	if r.Method == http.MethodOptions {
 		w.WriteHeader(http.StatusOK)
 		return
 	}
 
 	path := strings.TrimPrefix(r.URL.Path, "/api")
+	if r.Method == http.MethodPost {
+		if detail, mutating := auditDetail(path, r); mutating {
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			defer h.recordCall(r, detail, rec)
+			w = rec
+		}
+		// /run checks the role per command against AllowedCommands.
+		if path != "/run" && !PrincipalFrom(r.Context()).CanOperate() {
+			h.sendError(w, "Operator role required", http.StatusForbidden)
+			return
+		}
+	}
+	// Mail subjects and bodies are for operators; viewers see only activity.
+	if strings.HasPrefix(path, "/mail/") && !PrincipalFrom(r.Context()).CanOperate() {
+		h.sendError(w, "Operator role required", http.StatusForbidden)
+		return
+	}
 	switch {
 	case path == "/run" && r.Method == http.MethodPost:
 		h.handleRun(w, r)
@@ -145,6 +169,11 @@ func (h *APIHandler) handleRun(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !meta.Safe && !PrincipalFrom(r.Context()).CanOperate() {
+		h.sendError(w, "Command blocked: operator role required", http.StatusForbidden)
+		return
+	}
+
 	// Determine timeout
 	timeout := h.defaultRunTimeout
 	if req.Timeout > 0 {
@@ -183,20 +212,25 @@ func (h *APIHandler) handleRun(w http.ResponseWriter, r *http.Request) {
 		resp.Output = output
 	}
 
-	// Log command execution (but not for safe read-only commands to reduce noise)
-	if !meta.Safe || !resp.Success {
-		// Could add structured logging here
-		_ = meta // silence unused warning for now
-	}
-
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
 // handleCommands returns the list of available commands for the palette.
-func (h *APIHandler) handleCommands(w http.ResponseWriter, _ *http.Request) {
+// Viewers only see the Safe commands they are allowed to run.
+func (h *APIHandler) handleCommands(w http.ResponseWriter, r *http.Request) {
+	commands := GetCommandList()
+	if !PrincipalFrom(r.Context()).CanOperate() {
+		safe := commands[:0]
+		for _, c := range commands {
+			if c.Safe {
+				safe = append(safe, c)
+			}
+		}
+		commands = safe
+	}
 	resp := CommandListResponse{
-		Commands: GetCommandList(),
+		Commands: commands,
 	}
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(resp)
@@ -249,6 +283,61 @@ func (h *APIHandler) runGtCommand(ctx context.Context, timeout time.Duration, ar
 	return output, nil
 }
 
+// maxAuditBody bounds how much of a request body is read for the audit log.
+const maxAuditBody = 1 << 20
+
+// auditDetail reports whether a POST mutates town state and, if so, the most
+// useful field from its JSON body for the audit log. The body is restored for
+// the handler. Running a Safe command is not mutating.
+func auditDetail(path string, r *http.Request) (string, bool) {
+	body, _ := io.ReadAll(io.LimitReader(r.Body, maxAuditBody))
+	_ = r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var fields map[string]interface{}
+	_ = json.Unmarshal(body, &fields)
+	str := func(key string) string {
+		v, _ := fields[key].(string)
+		return v
+	}
+	if path == "/run" {
+		command := str("command")
+		if meta, err := ValidateCommand(command); err == nil && meta.Safe {
+			return "", false
+		}
+		return command, true
+	}
+	for _, key := range []string{"id", "to", "title"} {
+		if v := str(key); v != "" {
+			return v, true
+		}
+	}
+	return "", true
+}
+
+// recordCall writes the audit event for a mutating API call.
+func (h *APIHandler) recordCall(r *http.Request, detail string, rec *statusRecorder) {
+	actor, role := anonymousPrincipal, config.DashboardRoleOperator
+	if p := PrincipalFrom(r.Context()); p != nil {
+		actor, role = p.Name, p.Role
+	}
+	payload := events.DashboardAPIPayload(r.Method, r.URL.Path, detail, role, r.RemoteAddr, rec.status)
+	if err := h.audit(events.TypeDashboardAPI, actor, payload); err != nil {
+		log.Printf("dashboard: audit log failed: %v", err)
+	}
+}
+
+// statusRecorder captures the response status for the audit log.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
 // sendError sends a JSON error response.
 func (h *APIHandler) sendError(w http.ResponseWriter, message string, status int) {
 	w.Header().Set("Content-Type", "application/json")
@@ -2037,10 +2126,9 @@ func parseCommandArgs(command string) []string {
 	return args
 }
 
-// handleSSE streams Server-Sent Events to the dashboard client.
-// It polls key dashboard state every 2 seconds and sends an event when
-// changes are detected, allowing the client to trigger a re-render.
-// Falls through gracefully if the client disconnects.
+// handleSSE streams typed dashboard events from the shared Hub. Each event
+// is sent with its kind as the SSE event name and a DashboardEvent as data.
+// Viewers get mail events without their subjects.
 func (h *APIHandler) handleSSE(w http.ResponseWriter, r *http.Request) {
 	flusher, ok := w.(http.Flusher)
 	if !ok {
@@ -2051,18 +2139,21 @@ func (h *APIHandler) handleSSE(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("X-Accel-Buffering", "no")
 
 	ctx := r.Context()
+	viewer := !PrincipalFrom(ctx).CanOperate()
 
 	// Send initial connection event
 	fmt.Fprintf(w, "event: connected\ndata: ok\n\n")
 	flusher.Flush()
 
-	var lastHash string
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
+	var updates <-chan DashboardEvent
+	if h.hub != nil {
+		ch, cancel := h.hub.Subscribe()
+		defer cancel()
+		updates = ch
+	}
 
 	// Send keepalive comment every 30 seconds to prevent connection timeouts
 	keepalive := time.NewTicker(30 * time.Second)
@@ -2075,65 +2166,20 @@ func (h *APIHandler) handleSSE(w http.ResponseWriter, r *http.Request) {
 		case <-keepalive.C:
 			fmt.Fprintf(w, ": keepalive\n\n")
 			flusher.Flush()
-		case <-ticker.C:
-			hash := h.computeDashboardHash(ctx)
-			if hash != "" && hash != lastHash {
-				lastHash = hash
-				fmt.Fprintf(w, "event: dashboard-update\ndata: %s\n\n", hash)
-				flusher.Flush()
+		case ev, ok := <-updates:
+			if !ok {
+				// Dropped for falling behind; the client reconnects and refetches.
+				return
 			}
+			if viewer {
+				ev = ev.forViewer()
+			}
+			data, err := json.Marshal(ev)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Kind, data)
+			flusher.Flush()
 		}
 	}
 }
-
-// computeDashboardHash generates a lightweight hash of key dashboard state.
-// It runs quick commands in parallel and hashes their output to detect changes.
-func (h *APIHandler) computeDashboardHash(ctx context.Context) string {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
-
-	var mu sync.Mutex
-	var parts []string
-
-	var wg sync.WaitGroup
-	wg.Add(3)
-
-	// Check worker/polecat state
-	go func() {
-		defer wg.Done()
-		if out, err := h.runGtCommand(ctx, 3*time.Second, []string{"status", "--json"}); err == nil {
-			mu.Lock()
-			parts = append(parts, "status:"+out)
-			mu.Unlock()
-		}
-	}()
-
-	// Check hooks state
-	go func() {
-		defer wg.Done()
-		if out, err := h.runGtCommand(ctx, 3*time.Second, []string{"hooks", "list"}); err == nil {
-			mu.Lock()
-			parts = append(parts, "hooks:"+out)
-			mu.Unlock()
-		}
-	}()
-
-	// Check mail count
-	go func() {
-		defer wg.Done()
-		if out, err := h.runGtCommand(ctx, 3*time.Second, []string{"mail", "inbox"}); err == nil {
-			mu.Lock()
-			parts = append(parts, "mail:"+out)
-			mu.Unlock()
-		}
-	}()
-
-	wg.Wait()
-
-	if len(parts) == 0 {
-		return ""
-	}
-
-	h256 := sha256.Sum256([]byte(strings.Join(parts, "|")))
-	return fmt.Sprintf("%x", h256[:8])
-}
@@ -0,0 +1,502 @@
+package web
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+const (
+	// sessionCookie holds the login session ID.
+	sessionCookie = "gt_dashboard_session"
+	// csrfHeader carries the CSRF token on state-changing requests.
+	csrfHeader = "X-CSRF-Token"
+	// tokenHashIterations is the PBKDF2 work factor for stored token hashes.
+	tokenHashIterations = 100_000
+	// anonymousPrincipal is the identity used when authentication is off.
+	anonymousPrincipal = "local"
+	// maxAuthFailures failed logins or bearer tokens from one client within
+	// authFailureWindow lock that client out until the oldest one ages out.
+	maxAuthFailures   = 5
+	authFailureWindow = 15 * time.Minute
+)
+
+// Principal is the authenticated caller of a dashboard request.
+type Principal struct {
+	Name string
+	Role string
+	// session keys the CSRF token; empty for bearer-token callers, who carry
+	// no ambient credentials and are exempt from CSRF checks.
+	session string
+	bearer  bool
+}
+
+// CanOperate reports whether the principal may run action commands and use
+// mutating endpoints.
+func (p *Principal) CanOperate() bool {
+	return p == nil || p.Role == config.DashboardRoleOperator
+}
+
+type principalKey struct{}
+
+// PrincipalFrom returns the caller attached by Auth, or nil when the request
+// did not pass through Auth (treated as an operator).
+func PrincipalFrom(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
+
+type csrfKey struct{}
+
+// CSRFTokenFrom returns the CSRF token for the request's session, for
+// embedding in rendered pages.
+func CSRFTokenFrom(ctx context.Context) string {
+	s, _ := ctx.Value(csrfKey{}).(string)
+	return s
+}
+
+type authSession struct {
+	user    string
+	role    string
+	expires time.Time
+}
+
+// Auth enforces dashboard access control: logins, roles, CSRF tokens and
+// the CORS origin allowlist. With no users configured it only applies the
+// CSRF, origin and Host checks, and every caller is an operator.
+type Auth struct {
+	users      map[string]config.DashboardUser
+	origins    map[string]bool
+	sessionTTL time.Duration
+	secret     []byte
+	// audit records login attempts and, through NewDashboardMux, mutating
+	// API calls.
+	audit AuditFunc
+	now   func() time.Time
+
+	mu       sync.Mutex
+	sessions map[string]*authSession
+	// verified caches sha256(token) -> user name so bearer callers do not pay
+	// the PBKDF2 cost on every request.
+	verified map[[sha256.Size]byte]string
+	// failures holds recent failed attempts per client host, oldest first.
+	failures map[string][]time.Time
+}
+
+// AuditFunc records a dashboard audit event, like events.LogAudit.
+type AuditFunc func(eventType, actor string, payload map[string]interface{}) error
+
+// noAudit discards audit events.
+func noAudit(string, string, map[string]interface{}) error { return nil }
+
+// NewAuth builds the access controller for a dashboard config. cfg may be
+// nil. audit receives the dashboard's audit events; nil discards them.
+func NewAuth(cfg *config.DashboardConfig, audit AuditFunc) *Auth {
+	if cfg == nil {
+		cfg = config.DefaultDashboardConfig()
+	}
+	if audit == nil {
+		audit = noAudit
+	}
+	a := &Auth{
+		users:      make(map[string]config.DashboardUser),
+		origins:    make(map[string]bool),
+		sessionTTL: config.ParseDurationOrDefault(cfg.SessionTTL, 12*time.Hour),
+		secret:     make([]byte, 32),
+		audit:      audit,
+		now:        time.Now,
+		sessions:   make(map[string]*authSession),
+		verified:   make(map[[sha256.Size]byte]string),
+		failures:   make(map[string][]time.Time),
+	}
+	_, _ = rand.Read(a.secret)
+	for _, u := range cfg.Users {
+		a.users[u.Name] = u
+	}
+	for _, o := range cfg.AllowedOrigins {
+		a.origins[strings.TrimSuffix(o, "/")] = true
+	}
+	return a
+}
+
+// Enabled reports whether logins are required.
+func (a *Auth) Enabled() bool {
+	return len(a.users) > 0
+}
+
+// Wrap applies access control in front of next.
+func (a *Auth) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.checkOrigin(w, r) {
+			return
+		}
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if !a.Enabled() && !isLoopbackHost(r.Host) {
+			// Without logins the dashboard only answers to loopback names,
+			// which defeats DNS rebinding against the local API.
+			http.Error(w, "Forbidden host", http.StatusForbidden)
+			return
+		}
+
+		switch {
+		case r.URL.Path == "/login" && a.Enabled():
+			a.handleLogin(w, r)
+			return
+		case strings.HasPrefix(r.URL.Path, "/static/"):
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		p := a.authenticate(r)
+		if p == nil {
+			if strings.HasPrefix(r.URL.Path, "/api/") {
+				writeJSONError(w, "Authentication required", http.StatusUnauthorized)
+			} else {
+				http.Redirect(w, r, "/login", http.StatusSeeOther)
+			}
+			return
+		}
+		if !isSafeMethod(r.Method) && !p.bearer {
+			got := r.Header.Get(csrfHeader)
+			if got == "" {
+				got = r.PostFormValue("csrf_token")
+			}
+			if !hmac.Equal([]byte(got), []byte(a.csrfToken(p.session))) {
+				writeJSONError(w, "Missing or invalid CSRF token", http.StatusForbidden)
+				return
+			}
+		}
+		if r.URL.Path == "/logout" && r.Method == http.MethodPost {
+			a.logout(w, p)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), principalKey{}, p)
+		if !p.bearer {
+			ctx = context.WithValue(ctx, csrfKey{}, a.csrfToken(p.session))
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// checkOrigin applies the CORS allowlist. Same-origin requests pass
+// untouched; allowlisted origins get credentialed CORS headers; anything else
+// may not preflight or send state-changing requests. It reports whether the
+// request may proceed.
+func (a *Auth) checkOrigin(w http.ResponseWriter, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || sameOrigin(origin, r.Host) {
+		return true
+	}
+	if a.origins[origin] {
+		h := w.Header()
+		h.Set("Access-Control-Allow-Origin", origin)
+		h.Set("Access-Control-Allow-Credentials", "true")
+		h.Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		h.Set("Access-Control-Allow-Headers", "Content-Type, Authorization, "+csrfHeader)
+		h.Add("Vary", "Origin")
+		return true
+	}
+	if isSafeMethod(r.Method) {
+		// Browsers withhold the response without CORS headers.
+		return true
+	}
+	http.Error(w, "Origin not allowed", http.StatusForbidden)
+	return false
+}
+
+// authenticate resolves the caller from a bearer token or session cookie.
+func (a *Auth) authenticate(r *http.Request) *Principal {
+	if !a.Enabled() {
+		return &Principal{Name: anonymousPrincipal, Role: config.DashboardRoleOperator}
+	}
+	if h := r.Header.Get("Authorization"); strings.HasPrefix(h, "Bearer ") {
+		if a.lockedOut(r) > 0 {
+			return nil
+		}
+		if u, ok := a.verifyBearer(strings.TrimPrefix(h, "Bearer ")); ok {
+			return &Principal{Name: u.Name, Role: u.Role, bearer: true}
+		}
+		a.recordFailure(r)
+		return nil
+	}
+	c, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	s, ok := a.sessions[c.Value]
+	if !ok {
+		return nil
+	}
+	if a.now().After(s.expires) {
+		delete(a.sessions, c.Value)
+		return nil
+	}
+	return &Principal{Name: s.user, Role: s.role, session: c.Value}
+}
+
+// verifyBearer finds the user whose token hash matches token.
+func (a *Auth) verifyBearer(token string) (config.DashboardUser, bool) {
+	key := sha256.Sum256([]byte(token))
+	a.mu.Lock()
+	name, cached := a.verified[key]
+	a.mu.Unlock()
+	if cached {
+		u, ok := a.users[name]
+		return u, ok
+	}
+	for _, u := range a.users {
+		if VerifyToken(u.TokenHash, token) {
+			a.mu.Lock()
+			a.verified[key] = u.Name
+			a.mu.Unlock()
+			return u, true
+		}
+	}
+	return config.DashboardUser{}, false
+}
+
+// csrfToken derives the CSRF token for a session.
+func (a *Auth) csrfToken(session string) string {
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte("csrf:" + session))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+var loginTemplate = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <title>Gas Town Control Center - Login</title>
+    <link rel="stylesheet" href="/static/dashboard.css">
+</head>
+<body>
+    <form class="login-form" method="post" action="/login">
+        <h1>Gas Town Control Center</h1>
+        {{if .}}<p class="login-error">{{.}}</p>{{end}}
+        <label>Name <input name="name" autocomplete="username" required autofocus></label>
+        <label>Token <input name="token" type="password" autocomplete="current-password" required></label>
+        <button type="submit">Log in</button>
+    </form>
+</body>
+</html>
+`))
+
+// handleLogin serves the login form and exchanges a name and token for a
+// session cookie.
+func (a *Auth) handleLogin(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if r.Method != http.MethodPost {
+		_ = loginTemplate.Execute(w, "")
+		return
+	}
+
+	name := r.PostFormValue("name")
+	if wait := a.lockedOut(r); wait > 0 {
+		a.record(events.TypeDashboardLogin, name, r, "rate-limited", "", http.StatusTooManyRequests)
+		w.Header().Set("Retry-After", strconv.Itoa(int(wait.Round(time.Second).Seconds())))
+		w.WriteHeader(http.StatusTooManyRequests)
+		_ = loginTemplate.Execute(w, "Too many failed logins; try again later")
+		return
+	}
+	u, ok := a.users[name]
+	if !ok || !VerifyToken(u.TokenHash, r.PostFormValue("token")) {
+		a.recordFailure(r)
+		a.record(events.TypeDashboardLogin, name, r, "failed", "", http.StatusUnauthorized)
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = loginTemplate.Execute(w, "Invalid name or token")
+		return
+	}
+	a.clearFailures(r)
+
+	id := randomHex(32)
+	a.mu.Lock()
+	a.sessions[id] = &authSession{user: u.Name, role: u.Role, expires: a.now().Add(a.sessionTTL)}
+	a.mu.Unlock()
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    id,
+		Path:     "/",
+		MaxAge:   int(a.sessionTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+	a.record(events.TypeDashboardLogin, u.Name, r, "ok", u.Role, http.StatusSeeOther)
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// clientHost is the rate-limiting key for a request: its remote IP.
+func clientHost(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// recentFailures drops failures older than authFailureWindow and returns
+// the rest. The caller holds a.mu.
+func (a *Auth) recentFailures(host string) []time.Time {
+	cutoff := a.now().Add(-authFailureWindow)
+	times := a.failures[host]
+	for len(times) > 0 && !times[0].After(cutoff) {
+		times = times[1:]
+	}
+	if len(times) == 0 {
+		delete(a.failures, host)
+		return nil
+	}
+	a.failures[host] = times
+	return times
+}
+
+// lockedOut returns how long the request's client must wait before it may
+// try to authenticate again, or 0 if it may try now.
+func (a *Auth) lockedOut(r *http.Request) time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	times := a.recentFailures(clientHost(r))
+	if len(times) < maxAuthFailures {
+		return 0
+	}
+	return times[len(times)-maxAuthFailures].Add(authFailureWindow).Sub(a.now())
+}
+
+// recordFailure counts a failed login or bearer token against the client.
+func (a *Auth) recordFailure(r *http.Request) {
+	host := clientHost(r)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.failures[host] = append(a.recentFailures(host), a.now())
+}
+
+// clearFailures forgets a client's failures after it logs in.
+func (a *Auth) clearFailures(r *http.Request) {
+	a.mu.Lock()
+	delete(a.failures, clientHost(r))
+	a.mu.Unlock()
+}
+
+func (a *Auth) logout(w http.ResponseWriter, p *Principal) {
+	if p.session != "" {
+		a.mu.Lock()
+		delete(a.sessions, p.session)
+		a.mu.Unlock()
+	}
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: "", Path: "/", MaxAge: -1, HttpOnly: true})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *Auth) record(eventType, actor string, r *http.Request, detail, role string, status int) {
+	if actor == "" {
+		actor = "unknown"
+	}
+	payload := events.DashboardAPIPayload(r.Method, r.URL.Path, detail, role, r.RemoteAddr, status)
+	if err := a.audit(eventType, actor, payload); err != nil {
+		log.Printf("dashboard: audit log failed: %v", err)
+	}
+}
+
+// HashToken returns the stored form of a dashboard token.
+func HashToken(token string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key, err := pbkdf2.Key(sha256.New, token, salt, tokenHashIterations, sha256.Size)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("pbkdf2-sha256$%d$%s$%s", tokenHashIterations,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// VerifyToken reports whether token matches a hash from HashToken.
+func VerifyToken(hash, token string) bool {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[0] != "pbkdf2-sha256" || token == "" {
+		return false
+	}
+	iter, err := strconv.Atoi(parts[1])
+	if err != nil || iter <= 0 {
+		return false
+	}
+	salt, err1 := base64.RawStdEncoding.DecodeString(parts[2])
+	want, err2 := base64.RawStdEncoding.DecodeString(parts[3])
+	if err1 != nil || err2 != nil || len(want) == 0 {
+		return false
+	}
+	got, err := pbkdf2.Key(sha256.New, token, salt, iter, len(want))
+	return err == nil && subtle.ConstantTimeCompare(got, want) == 1
+}
+
+// GenerateToken returns a new random dashboard token.
+func GenerateToken() string {
+	return "gtd_" + randomHex(24)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// sameOrigin reports whether an Origin header names the host being served.
+func sameOrigin(origin, host string) bool {
+	u, err := url.Parse(origin)
+	return err == nil && u.Host != "" && strings.EqualFold(u.Host, host)
+}
+
+// isLoopbackHost reports whether a Host header names the local machine.
+func isLoopbackHost(hostport string) bool {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// IsLoopbackBind reports whether a bind address only accepts local
+// connections. An empty host listens on every interface.
+func IsLoopbackBind(bind string) bool {
+	return bind != "" && isLoopbackHost(bind)
+}
+
+func writeJSONError(w http.ResponseWriter, message string, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(CommandResponse{Success: false, Error: message})
+}
@@ -0,0 +1,319 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+type auditRecorder struct {
+	mu     sync.Mutex
+	events []string // "type actor detail status"
+}
+
+func (a *auditRecorder) log(eventType, actor string, payload map[string]interface{}) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	detail, _ := payload["detail"].(string)
+	status, _ := payload["status"].(int)
+	a.events = append(a.events, strings.Join([]string{eventType, actor, detail, http.StatusText(status)}, " "))
+	return nil
+}
+
+func TestHashToken(t *testing.T) {
+	hash, err := HashToken("s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, "pbkdf2-sha256$") || strings.Contains(hash, "s3cret") {
+		t.Fatalf("unexpected hash format: %s", hash)
+	}
+	if !VerifyToken(hash, "s3cret") {
+		t.Error("VerifyToken rejected the right token")
+	}
+	for _, bad := range []string{"", "s3cre", "s3cret "} {
+		if VerifyToken(hash, bad) {
+			t.Errorf("VerifyToken accepted %q", bad)
+		}
+	}
+	if VerifyToken("plain", "plain") || VerifyToken("pbkdf2-sha256$x$y$z", "x") {
+		t.Error("VerifyToken accepted a malformed hash")
+	}
+}
+
+func TestIsLoopbackBind(t *testing.T) {
+	for bind, want := range map[string]bool{
+		"127.0.0.1": true,
+		"localhost": true,
+		"::1":       true,
+		"":          false,
+		"0.0.0.0":   false,
+		"10.0.0.5":  false,
+	} {
+		if got := IsLoopbackBind(bind); got != want {
+			t.Errorf("IsLoopbackBind(%q) = %v, want %v", bind, got, want)
+		}
+	}
+}
+
+// okHandler echoes the principal so tests can see what Auth attached.
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	p := PrincipalFrom(r.Context())
+	_, _ = w.Write([]byte(p.Name + "/" + p.Role + "/" + CSRFTokenFrom(r.Context())))
+})
+
+func TestAuth_NoUsers(t *testing.T) {
+	a := NewAuth(&config.DashboardConfig{AllowedOrigins: []string{"https://ops.example.com/"}}, nil)
+	h := a.Wrap(okHandler)
+	token := a.csrfToken("")
+
+	do := func(method, host, origin, csrf string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/run", strings.NewReader("{}"))
+		req.Host = host
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if csrf != "" {
+			req.Header.Set(csrfHeader, csrf)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do(http.MethodGet, "localhost:8080", "", ""); rec.Code != http.StatusOK || rec.Body.String() != "local/operator/"+token {
+		t.Errorf("local GET = %d %q", rec.Code, rec.Body.String())
+	}
+	if rec := do(http.MethodGet, "evil.example:8080", "", ""); rec.Code != http.StatusForbidden {
+		t.Errorf("rebound Host GET = %d, want 403", rec.Code)
+	}
+	if rec := do(http.MethodPost, "127.0.0.1:8080", "http://127.0.0.1:8080", ""); rec.Code != http.StatusForbidden {
+		t.Errorf("POST without CSRF token = %d, want 403", rec.Code)
+	}
+	if rec := do(http.MethodPost, "127.0.0.1:8080", "http://127.0.0.1:8080", token); rec.Code != http.StatusOK {
+		t.Errorf("POST with CSRF token = %d, want 200", rec.Code)
+	}
+	if rec := do(http.MethodPost, "localhost:8080", "https://attacker.example", token); rec.Code != http.StatusForbidden {
+		t.Errorf("cross-origin POST = %d, want 403", rec.Code)
+	}
+
+	rec := do(http.MethodOptions, "localhost:8080", "https://ops.example.com", "")
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Access-Control-Allow-Origin") != "https://ops.example.com" {
+		t.Errorf("allowlisted preflight = %d, ACAO %q", rec.Code, rec.Header().Get("Access-Control-Allow-Origin"))
+	}
+	rec = do(http.MethodGet, "localhost:8080", "https://attacker.example", "")
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("unlisted origin got ACAO %q", got)
+	}
+}
+
+func TestAuth_Login(t *testing.T) {
+	hash, err := HashToken("op-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := NewAuth(&config.DashboardConfig{
+		SessionTTL: "1h",
+		Users:      []config.DashboardUser{{Name: "alice", Role: config.DashboardRoleOperator, TokenHash: hash}},
+	}, nil)
+	audit := &auditRecorder{}
+	a.audit = audit.log
+	h := a.Wrap(okHandler)
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		req.Host = "gt.lan:8080"
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	login := func(token string) *httptest.ResponseRecorder {
+		form := url.Values{"name": {"alice"}, "token": {token}}
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return serve(req)
+	}
+
+	if rec := serve(httptest.NewRequest(http.MethodGet, "/api/commands", nil)); rec.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous API call = %d, want 401", rec.Code)
+	}
+	if rec := serve(httptest.NewRequest(http.MethodGet, "/", nil)); rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/login" {
+		t.Errorf("anonymous page = %d -> %q, want redirect to /login", rec.Code, rec.Header().Get("Location"))
+	}
+	if rec := login("wrong"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("bad login = %d, want 401", rec.Code)
+	}
+
+	rec := login("op-token")
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("login = %d, want 303", rec.Code)
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != sessionCookie || !cookies[0].HttpOnly {
+		t.Fatalf("login cookies = %+v", cookies)
+	}
+	session := cookies[0]
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(session)
+	rec = serve(req)
+	csrf := a.csrfToken(session.Value)
+	if rec.Code != http.StatusOK || rec.Body.String() != "alice/operator/"+csrf {
+		t.Errorf("session GET = %d %q", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/run", nil)
+	req.AddCookie(session)
+	if rec := serve(req); rec.Code != http.StatusForbidden {
+		t.Errorf("session POST without CSRF = %d, want 403", rec.Code)
+	}
+	req = httptest.NewRequest(http.MethodPost, "/api/run", nil)
+	req.AddCookie(session)
+	req.Header.Set(csrfHeader, csrf)
+	if rec := serve(req); rec.Code != http.StatusOK {
+		t.Errorf("session POST with CSRF = %d, want 200", rec.Code)
+	}
+
+	// Bearer callers carry no ambient credentials, so no CSRF token is needed.
+	req = httptest.NewRequest(http.MethodPost, "/api/run", nil)
+	req.Header.Set("Authorization", "Bearer op-token")
+	if rec := serve(req); rec.Code != http.StatusOK || !strings.HasPrefix(rec.Body.String(), "alice/operator/") {
+		t.Errorf("bearer POST = %d %q", rec.Code, rec.Body.String())
+	}
+	req = httptest.NewRequest(http.MethodGet, "/api/commands", nil)
+	req.Header.Set("Authorization", "Bearer nope")
+	if rec := serve(req); rec.Code != http.StatusUnauthorized {
+		t.Errorf("bad bearer = %d, want 401", rec.Code)
+	}
+
+	a.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	req = httptest.NewRequest(http.MethodGet, "/api/commands", nil)
+	req.AddCookie(session)
+	if rec := serve(req); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expired session = %d, want 401", rec.Code)
+	}
+
+	want := []string{
+		events.TypeDashboardLogin + " alice failed Unauthorized",
+		events.TypeDashboardLogin + " alice ok See Other",
+	}
+	if strings.Join(audit.events, "|") != strings.Join(want, "|") {
+		t.Errorf("audit events = %q, want %q", audit.events, want)
+	}
+}
+
+func TestAuth_LoginRateLimit(t *testing.T) {
+	hash, err := HashToken("op-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := NewAuth(&config.DashboardConfig{
+		Users: []config.DashboardUser{{Name: "alice", Role: config.DashboardRoleOperator, TokenHash: hash}},
+	}, nil)
+	now := time.Now()
+	a.now = func() time.Time { return now }
+	h := a.Wrap(okHandler)
+
+	login := func(token, remote string) *httptest.ResponseRecorder {
+		form := url.Values{"name": {"alice"}, "token": {token}}
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Host = "gt.lan:8080"
+		req.RemoteAddr = remote
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < maxAuthFailures; i++ {
+		if rec := login("wrong", "10.0.0.5:4000"); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("failure %d = %d, want 401", i+1, rec.Code)
+		}
+	}
+	rec := login("op-token", "10.0.0.5:4001")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "900" {
+		t.Errorf("locked-out login = %d (Retry-After %q), want 429 after 900s", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/commands", nil)
+	req.Host = "gt.lan:8080"
+	req.RemoteAddr = "10.0.0.5:4002"
+	req.Header.Set("Authorization", "Bearer op-token")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("locked-out bearer = %d, want 401", rec.Code)
+	}
+	if rec := login("op-token", "10.0.0.6:4000"); rec.Code != http.StatusSeeOther {
+		t.Errorf("other client login = %d, want 303", rec.Code)
+	}
+
+	now = now.Add(authFailureWindow)
+	if rec := login("op-token", "10.0.0.5:4003"); rec.Code != http.StatusSeeOther {
+		t.Errorf("login after the window = %d, want 303", rec.Code)
+	}
+}
+
+func TestAPIHandler_Roles(t *testing.T) {
+	hash, err := HashToken("viewer-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := NewAuth(&config.DashboardConfig{
+		Users: []config.DashboardUser{{Name: "bob", Role: config.DashboardRoleViewer, TokenHash: hash}},
+	}, nil)
+	api := NewAPIHandler(30*time.Second, 60*time.Second, nil)
+	api.gtPath = "/nonexistent/gt"
+	audit := &auditRecorder{}
+	api.audit = audit.log
+	h := auth.Wrap(api)
+
+	call := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer viewer-token")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := call(http.MethodPost, "/api/run", `{"command":"mail send mayor/ -s hi -m there"}`); rec.Code != http.StatusForbidden {
+		t.Errorf("viewer action command = %d, want 403", rec.Code)
+	}
+	if rec := call(http.MethodPost, "/api/issues/close", `{"id":"gt-123"}`); rec.Code != http.StatusForbidden {
+		t.Errorf("viewer issue close = %d, want 403", rec.Code)
+	}
+
+	for _, path := range []string{"/api/mail/inbox", "/api/mail/threads", "/api/mail/read?id=hq-msg1"} {
+		if rec := call(http.MethodGet, path, ""); rec.Code != http.StatusForbidden {
+			t.Errorf("viewer GET %s = %d, want 403", path, rec.Code)
+		}
+	}
+
+	rec := call(http.MethodGet, "/api/commands", "")
+	var list CommandListResponse
+	if err := json.NewDecoder(rec.Body).Decode(&list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Commands) == 0 {
+		t.Fatal("viewer sees no commands")
+	}
+	for _, c := range list.Commands {
+		if !c.Safe {
+			t.Errorf("viewer command list includes unsafe %q", c.Name)
+		}
+	}
+
+	want := []string{
+		events.TypeDashboardAPI + " bob mail send mayor/ -s hi -m there Forbidden",
+		events.TypeDashboardAPI + " bob gt-123 Forbidden",
+	}
+	if strings.Join(audit.events, "|") != strings.Join(want, "|") {
+		t.Errorf("audit events = %q, want %q", audit.events, want)
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/steveyegge/gastown/internal/activity"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -1617,48 +1618,18 @@ func (f *LiveConvoyFetcher) FetchIssues() ([]IssueRow, error) {
 
 // FetchActivity returns recent activity from the event log.
 func (f *LiveConvoyFetcher) FetchActivity() ([]ActivityRow, error) {
-	eventsPath := filepath.Join(f.townRoot, ".events.jsonl")
-
-	// Read events file
-	data, err := os.ReadFile(eventsPath)
+	// Take the last 50 feed events for a richer timeline, skipping audit-only ones
+	recs, err := events.OpenStore(f.townRoot).Query(events.Query{
+		Limit: 50,
+		Match: func(e events.Event) bool { return e.Visibility != events.VisibilityAudit },
+	})
 	if err != nil {
-		return nil, nil // No events file
-	}
-
-	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
-	if len(lines) == 0 {
-		return nil, nil
-	}
-
-	// Take last 50 events for richer timeline
-	start := 0
-	if len(lines) > 50 {
-		start = len(lines) - 50
+		return nil, nil // No readable event store
 	}
 
 	var rows []ActivityRow
-	for i := len(lines) - 1; i >= start; i-- {
-		line := lines[i]
-		if line == "" {
-			continue
-		}
-
-		var event struct {
-			Timestamp  string                 `json:"ts"`
-			Type       string                 `json:"type"`
-			Actor      string                 `json:"actor"`
-			Payload    map[string]interface{} `json:"payload"`
-			Visibility string                 `json:"visibility"`
-		}
-		if err := json.Unmarshal([]byte(line), &event); err != nil {
-			continue
-		}
-
-		// Skip audit-only events
-		if event.Visibility == "audit" {
-			continue
-		}
-
+	for i := len(recs) - 1; i >= 0; i-- {
+		event := recs[i]
 		row := ActivityRow{
 			Type:         event.Type,
 			Category:     eventCategory(event.Type),
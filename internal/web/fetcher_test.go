@@ -459,7 +459,7 @@ func TestNewAPIHandler_StoresTimeouts(t *testing.T) {
 	defTimeout := 45 * time.Second
 	maxTimeout := 90 * time.Second
 
-	handler := NewAPIHandler(defTimeout, maxTimeout)
+	handler := NewAPIHandler(defTimeout, maxTimeout, nil)
 	if handler.defaultRunTimeout != defTimeout {
 		t.Errorf("defaultRunTimeout = %v, want %v", handler.defaultRunTimeout, defTimeout)
 	}
@@ -472,7 +472,7 @@ func TestNewAPIHandler_StoresTimeouts(t *testing.T) {
 
 func TestNewDashboardMux_NilConfig(t *testing.T) {
 	mock := &MockConvoyFetcher{}
-	mux, err := NewDashboardMux(mock, nil)
+	mux, err := NewDashboardMux(mock, nil, nil)
 	if err != nil {
 		t.Fatalf("NewDashboardMux(nil config): %v", err)
 	}
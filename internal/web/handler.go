@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 //go:embed static
@@ -236,6 +237,7 @@ func (h *ConvoyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		Activity:    activity,
 		Summary:     summary,
 		Expand:      expandPanel,
+		CSRFToken:   CSRFTokenFrom(r.Context()),
 	}
 
 	var buf bytes.Buffer
@@ -329,8 +331,10 @@ func enrichIssuesWithAssignees(issues []IssueRow, hooks []HookRow) []IssueRow {
 }
 
 // NewDashboardMux creates an HTTP handler that serves both the dashboard and API.
-// webCfg may be nil, in which case defaults are used.
-func NewDashboardMux(fetcher ConvoyFetcher, webCfg *config.WebTimeoutsConfig) (http.Handler, error) {
+// webCfg may be nil, in which case defaults are used. auth may be nil, in
+// which case logins are off and only same-origin, loopback callers are served.
+// API calls are audited through auth's audit func.
+func NewDashboardMux(fetcher ConvoyFetcher, webCfg *config.WebTimeoutsConfig, auth *Auth) (http.Handler, error) {
 	if webCfg == nil {
 		webCfg = config.DefaultWebTimeoutsConfig()
 	}
@@ -343,7 +347,13 @@ func NewDashboardMux(fetcher ConvoyFetcher, webCfg *config.WebTimeoutsConfig) (h
 
 	defaultRunTimeout := config.ParseDurationOrDefault(webCfg.DefaultRunTimeout, 30*time.Second)
 	maxRunTimeout := config.ParseDurationOrDefault(webCfg.MaxRunTimeout, 60*time.Second)
-	apiHandler := NewAPIHandler(defaultRunTimeout, maxRunTimeout)
+	if auth == nil {
+		auth = NewAuth(nil, nil)
+	}
+	apiHandler := NewAPIHandler(defaultRunTimeout, maxRunTimeout, auth.audit)
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		apiHandler.hub = NewHub(townRoot)
+	}
 
 	// Create static file server from embedded files
 	staticFS, err := fs.Sub(staticFiles, "static")
@@ -357,5 +367,5 @@ func NewDashboardMux(fetcher ConvoyFetcher, webCfg *config.WebTimeoutsConfig) (h
 	mux.Handle("/static/", http.StripPrefix("/static/", staticHandler))
 	mux.Handle("/", convoyHandler)
 
-	return mux, nil
+	return auth.Wrap(mux), nil
 }
@@ -1,6 +1,27 @@
 (function() {
     'use strict';
 
+    // ============================================
+    // CSRF AND LOGIN
+    // ============================================
+    // State-changing API calls must carry the page's CSRF token. A 401 means
+    // the login session expired.
+    var csrfMeta = document.querySelector('meta[name="csrf-token"]');
+    var nativeFetch = window.fetch;
+    window.fetch = function(url, opts) {
+        opts = opts || {};
+        var method = (opts.method || 'GET').toUpperCase();
+        if (csrfMeta && method !== 'GET' && method !== 'HEAD') {
+            opts.headers = Object.assign({}, opts.headers, { 'X-CSRF-Token': csrfMeta.content });
+        }
+        return nativeFetch.call(window, url, opts).then(function(resp) {
+            if (resp.status === 401) {
+                window.location.href = '/login';
+            }
+            return resp;
+        });
+    };
+
     // ============================================
     // SSE (Server-Sent Events) CONNECTION
     // ============================================
@@ -22,13 +43,20 @@
             updateConnectionStatus('live');
         });
 
-        evtSource.addEventListener('dashboard-update', function(e) {
-            if (window.pauseRefresh) return;
-            // Trigger HTMX to re-fetch the dashboard
-            var dashboard = document.getElementById('dashboard-main');
-            if (dashboard && typeof htmx !== 'undefined') {
-                htmx.trigger(dashboard, 'sse:dashboard-update');
-            }
+        // Typed events from the server's shared event hub. Each one refreshes
+        // the dashboard (debounced so a burst costs one refetch); a few also
+        // raise a toast.
+        ['worker', 'mail', 'merge', 'convoy', 'escalation', 'bead'].forEach(function(kind) {
+            evtSource.addEventListener(kind, function(e) {
+                var ev;
+                try {
+                    ev = JSON.parse(e.data);
+                } catch (err) {
+                    return;
+                }
+                notifyDashboardEvent(ev);
+                scheduleDashboardRefresh();
+            });
         });
 
         evtSource.onerror = function() {
@@ -43,6 +71,41 @@
         };
     }
 
+    var refreshTimer = null;
+
+    function scheduleDashboardRefresh() {
+        if (refreshTimer) return;
+        refreshTimer = setTimeout(function() {
+            refreshTimer = null;
+            if (window.pauseRefresh) return;
+            // Trigger HTMX to re-fetch the dashboard
+            var dashboard = document.getElementById('dashboard-main');
+            if (dashboard && typeof htmx !== 'undefined') {
+                htmx.trigger(dashboard, 'sse:dashboard-update');
+            }
+        }, 1000);
+    }
+
+    function notifyDashboardEvent(ev) {
+        switch (ev.kind + ':' + ev.type) {
+            case 'mail:mail':
+                showToast('info', 'New mail', (ev.actor || '') + (ev.summary ? ': ' + ev.summary : ''));
+                break;
+            case 'escalation:escalation_sent':
+                showToast('error', 'Escalation', (ev.actor || '') + (ev.summary ? ': ' + ev.summary : ''));
+                break;
+            case 'merge:merged':
+                showToast('success', 'Merged', ev.target || '');
+                break;
+            case 'merge:merge_failed':
+                showToast('error', 'Merge failed', (ev.target || '') + (ev.summary ? ': ' + ev.summary : ''));
+                break;
+            case 'convoy:convoy_closed':
+                showToast('success', 'Convoy landed', ev.summary || ev.target || '');
+                break;
+        }
+    }
+
     function updateConnectionStatus(state) {
         var el = document.getElementById('connection-status');
         if (!el) return;
@@ -876,6 +939,11 @@
         fetch('/api/mail/threads')
             .then(function(r) { return r.json(); })
             .then(function(data) {
+                if (data.error && !data.threads) {
+                    // e.g. viewers, who may not read mail
+                    loading.textContent = data.error;
+                    return;
+                }
                 loading.style.display = 'none';
 
                 if (data.threads && data.threads.length > 0) {
@@ -0,0 +1,357 @@
+package web
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Dashboard event kinds, sent as the SSE event name.
+const (
+	KindWorker     = "worker"
+	KindMail       = "mail"
+	KindMerge      = "merge"
+	KindConvoy     = "convoy"
+	KindEscalation = "escalation"
+	KindBead       = "bead"
+)
+
+// DashboardEvent is one incremental update pushed to dashboard clients.
+type DashboardEvent struct {
+	Kind    string                 `json:"kind"`
+	Type    string                 `json:"type"`
+	Time    string                 `json:"ts"`
+	Actor   string                 `json:"actor,omitempty"`
+	Target  string                 `json:"target,omitempty"`
+	Summary string                 `json:"summary,omitempty"`
+	Payload map[string]interface{} `json:"payload,omitempty"`
+}
+
+// eventKinds maps town event types to dashboard kinds. Types not listed
+// (patrol chatter, audit-only events) are not pushed.
+var eventKinds = map[string]string{
+	events.TypeSling:            KindWorker,
+	events.TypeHook:             KindWorker,
+	events.TypeUnhook:           KindWorker,
+	events.TypeHandoff:          KindWorker,
+	events.TypeDone:             KindWorker,
+	events.TypeSpawn:            KindWorker,
+	events.TypeKill:             KindWorker,
+	events.TypeBoot:             KindWorker,
+	events.TypeHalt:             KindWorker,
+	events.TypeSessionStart:     KindWorker,
+	events.TypeSessionEnd:       KindWorker,
+	events.TypeSessionDeath:     KindWorker,
+	events.TypeMassDeath:        KindWorker,
+	events.TypePolecatNudged:    KindWorker,
+	events.TypeMail:             KindMail,
+	events.TypeMergeStarted:     KindMerge,
+	events.TypeMerged:           KindMerge,
+	events.TypeMergeFailed:      KindMerge,
+	events.TypeMergeSkipped:     KindMerge,
+	events.TypeDoneMRFailed:     KindMerge,
+	events.TypeConvoyClosed:     KindConvoy,
+	events.TypeEscalationSent:   KindEscalation,
+	events.TypeEscalationAcked:  KindEscalation,
+	events.TypeEscalationClosed: KindEscalation,
+}
+
+// subscriberBuffer is how many events a slow client may fall behind before
+// it is disconnected. EventSource reconnects and the page refetches.
+const subscriberBuffer = 64
+
+// Hub is the single source of dashboard updates. It tails the town's
+// event store and bead activity once, and fans events out to every
+// connected client, so watchers add no bd or tmux load. Sources run only
+// while at least one client is subscribed.
+type Hub struct {
+	store *events.Store
+	// beadActivity streams bead change lines. Defaults to 'bd activity
+	// --follow' in the town root; nil disables bead notifications.
+	beadActivity func(ctx context.Context) (io.ReadCloser, error)
+	// isMessage reports whether a bead is mail. Its activity summary is the
+	// subject, so it is sent as a mail event, which viewers get without it.
+	isMessage func(id string) bool
+
+	mu     sync.Mutex
+	subs   map[chan DashboardEvent]struct{}
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewHub creates a hub for the town at townRoot.
+func NewHub(townRoot string) *Hub {
+	return &Hub{
+		store:        events.OpenStore(townRoot),
+		beadActivity: bdActivity(townRoot),
+		isMessage:    messageBeadLookup(townRoot),
+		subs:         make(map[chan DashboardEvent]struct{}),
+	}
+}
+
+// Subscribe registers a client. The returned channel is closed when the
+// client falls too far behind or the hub stops; call the cancel func when
+// the client goes away.
+func (h *Hub) Subscribe() (<-chan DashboardEvent, func()) {
+	ch := make(chan DashboardEvent, subscriberBuffer)
+	h.mu.Lock()
+	h.subs[ch] = struct{}{}
+	if h.cancel == nil {
+		h.startLocked()
+	}
+	h.mu.Unlock()
+	return ch, func() { h.unsubscribe(ch) }
+}
+
+func (h *Hub) unsubscribe(ch chan DashboardEvent) {
+	h.mu.Lock()
+	if _, ok := h.subs[ch]; ok {
+		delete(h.subs, ch)
+		close(ch)
+	}
+	var done chan struct{}
+	if len(h.subs) == 0 && h.cancel != nil {
+		h.cancel()
+		h.cancel = nil
+		done = h.done
+	}
+	h.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
+// Publish sends an event to every subscriber, dropping clients whose
+// buffers are full.
+func (h *Hub) Publish(ev DashboardEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs {
+		select {
+		case ch <- ev:
+		default:
+			delete(h.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+func (h *Hub) startLocked() {
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	h.done = make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		h.tailEvents(ctx)
+	}()
+	if h.beadActivity != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.followBeads(ctx)
+		}()
+	}
+	done := h.done
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+}
+
+// tailEvents publishes events appended to the event store after the hub
+// started.
+func (h *Hub) tailEvents(ctx context.Context) {
+	end, err := h.store.End()
+	if err != nil {
+		log.Printf("dashboard: reading event store: %v", err)
+		return
+	}
+	for rec := range h.store.Subscribe(ctx, end, events.Query{Match: isDashboardEvent}) {
+		if ev, ok := dashboardEventFromLine(rec.Line); ok {
+			h.Publish(ev)
+		}
+	}
+}
+
+// isDashboardEvent reports whether a stored event is shown on the dashboard.
+func isDashboardEvent(e events.Event) bool {
+	_, ok := eventKinds[e.Type]
+	return ok && e.Visibility != events.VisibilityAudit
+}
+
+// dashboardEventFromLine converts a raw event store line.
+func dashboardEventFromLine(line string) (DashboardEvent, bool) {
+	var raw events.Event
+	if err := json.Unmarshal([]byte(line), &raw); err != nil {
+		return DashboardEvent{}, false
+	}
+	kind, ok := eventKinds[raw.Type]
+	if !ok || raw.Visibility == events.VisibilityAudit {
+		return DashboardEvent{}, false
+	}
+	ev := DashboardEvent{
+		Kind:    kind,
+		Type:    raw.Type,
+		Time:    raw.Timestamp,
+		Actor:   raw.Actor,
+		Payload: raw.Payload,
+	}
+	for _, key := range []string{"bead", "mr", "convoy", "to", "target", "rig"} {
+		if v, ok := raw.Payload[key].(string); ok && v != "" {
+			ev.Target = v
+			break
+		}
+	}
+	for _, key := range []string{"subject", "title", "reason", "message"} {
+		if v, ok := raw.Payload[key].(string); ok && v != "" {
+			ev.Summary = v
+			break
+		}
+	}
+	return ev, true
+}
+
+// forViewer returns the event as sent to a viewer: mail events keep who
+// was written to but drop the subject, which can carry work details.
+// Events are shared between subscribers, so the payload is copied.
+func (ev DashboardEvent) forViewer() DashboardEvent {
+	if ev.Kind != KindMail {
+		return ev
+	}
+	ev.Summary = ""
+	payload := make(map[string]interface{}, len(ev.Payload))
+	for k, v := range ev.Payload {
+		if k != "subject" {
+			payload[k] = v
+		}
+	}
+	ev.Payload = payload
+	return ev
+}
+
+// followBeads publishes bead change notifications, restarting the activity
+// stream with backoff if it exits.
+func (h *Hub) followBeads(ctx context.Context) {
+	backoff := time.Second
+	for {
+		start := time.Now()
+		if stream, err := h.beadActivity(ctx); err == nil {
+			sc := bufio.NewScanner(stream)
+			for sc.Scan() {
+				if ev, ok := dashboardEventFromActivity(sc.Text()); ok {
+					if ev.Kind == KindBead && h.isMessage != nil && h.isMessage(ev.Target) {
+						ev.Kind = KindMail
+					}
+					h.Publish(ev)
+				}
+			}
+			_ = stream.Close()
+		}
+		if time.Since(start) > time.Minute {
+			backoff = time.Second
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff < time.Minute {
+			backoff *= 2
+		}
+	}
+}
+
+// activityLine matches 'bd activity' output: [HH:MM:SS] SYMBOL BEAD_ID action · description
+var activityLine = regexp.MustCompile(`^\[\d{2}:\d{2}:\d{2}\]\s+(\S+)\s+(\S+)\s*(.*)$`)
+
+// dashboardEventFromActivity converts a 'bd activity' line. Convoy beads
+// (hq-cv-*) are reported as convoy progress.
+func dashboardEventFromActivity(line string) (DashboardEvent, bool) {
+	m := activityLine.FindStringSubmatch(strings.TrimSpace(line))
+	if m == nil {
+		return DashboardEvent{}, false
+	}
+	ev := DashboardEvent{
+		Kind:    KindBead,
+		Type:    "update",
+		Time:    time.Now().UTC().Format(time.RFC3339),
+		Target:  m[2],
+		Summary: strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(m[3]), "·")),
+	}
+	switch m[1] {
+	case "+":
+		ev.Type = "create"
+	case "✓":
+		ev.Type = "complete"
+	case "✗":
+		ev.Type = "fail"
+	case "⊘":
+		ev.Type = "delete"
+	}
+	if strings.HasPrefix(ev.Target, "hq-cv-") {
+		ev.Kind = KindConvoy
+	}
+	return ev, true
+}
+
+// messageBeadLookup returns an isMessage that asks bd whether a bead has the
+// gt:message label. Answers are cached, since a bead never changes between
+// mail and not. A bead bd can't show (e.g. deleted) counts as mail, so a
+// subject is never shown to viewers by mistake. Only followBeads calls it.
+func messageBeadLookup(townRoot string) func(id string) bool {
+	b := beads.New(townRoot)
+	cache := map[string]bool{}
+	return func(id string) bool {
+		if isMsg, ok := cache[id]; ok {
+			return isMsg
+		}
+		issue, err := b.Show(id)
+		if err != nil {
+			return true
+		}
+		cache[id] = beads.HasLabel(issue, "gt:message")
+		return cache[id]
+	}
+}
+
+// bdActivity returns a bead activity source that runs 'bd activity --follow'.
+func bdActivity(townRoot string) func(ctx context.Context) (io.ReadCloser, error) {
+	if _, err := exec.LookPath("bd"); err != nil {
+		return nil
+	}
+	return func(ctx context.Context) (io.ReadCloser, error) {
+		cmd := exec.CommandContext(ctx, "bd", "activity", "--follow")
+		cmd.Dir = townRoot
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, err
+		}
+		return &cmdStream{ReadCloser: stdout, cmd: cmd}, nil
+	}
+}
+
+// cmdStream reaps the process when its output is closed.
+type cmdStream struct {
+	io.ReadCloser
+	cmd *exec.Cmd
+}
+
+func (s *cmdStream) Close() error {
+	_ = s.ReadCloser.Close()
+	return s.cmd.Wait()
+}
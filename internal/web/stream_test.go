@@ -0,0 +1,127 @@
+package web
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func recvEvent(t *testing.T, ch <-chan DashboardEvent) DashboardEvent {
+	t.Helper()
+	select {
+	case ev, ok := <-ch:
+		if !ok {
+			t.Fatal("subscription closed")
+		}
+		return ev
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for dashboard event")
+	}
+	return DashboardEvent{}
+}
+
+func TestHub_FansOutTownEvents(t *testing.T) {
+	town := t.TempDir()
+	store := events.OpenStore(town)
+	if err := store.Append(events.Event{Timestamp: "old", Type: "mail", Actor: "a", Visibility: "feed"}); err != nil {
+		t.Fatal(err)
+	}
+
+	beadsR, beadsW := io.Pipe()
+	hub := NewHub(town)
+	hub.beadActivity = func(ctx context.Context) (io.ReadCloser, error) {
+		go func() {
+			<-ctx.Done()
+			_ = beadsW.Close()
+		}()
+		return beadsR, nil
+	}
+	hub.isMessage = func(id string) bool { return id == "hq-msg01" }
+
+	a, cancelA := hub.Subscribe()
+	b, cancelB := hub.Subscribe()
+	defer cancelB()
+	time.Sleep(2 * events.SubscribePollInterval) // let the tailer start past existing events
+
+	if err := store.Append(
+		events.Event{Timestamp: "t1", Type: "dashboard_api", Actor: "alice", Visibility: "audit"},
+		events.Event{Timestamp: "t2", Type: "patrol_started", Actor: "witness", Visibility: "feed"},
+		events.Event{Timestamp: "t3", Type: "merged", Actor: "gastown/refinery", Payload: map[string]interface{}{"mr": "gt-mr1", "branch": "polecat/toast"}, Visibility: "feed"},
+		events.Event{Timestamp: "t4", Type: "mail", Actor: "mayor", Payload: map[string]interface{}{"to": "gastown/witness", "subject": "hi"}, Visibility: "feed"},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, ch := range map[string]<-chan DashboardEvent{"a": a, "b": b} {
+		ev := recvEvent(t, ch)
+		if ev.Kind != KindMerge || ev.Type != events.TypeMerged || ev.Target != "gt-mr1" {
+			t.Errorf("%s: first event = %+v, want merge of gt-mr1", name, ev)
+		}
+		ev = recvEvent(t, ch)
+		if ev.Kind != KindMail || ev.Target != "gastown/witness" || ev.Summary != "hi" {
+			t.Errorf("%s: second event = %+v, want mail to witness", name, ev)
+		}
+	}
+
+	_, _ = io.WriteString(beadsW, "[10:15:02] ✓ gt-abc12 closed · Fix login\n[10:15:03] → hq-cv-xyz updated · 2/3 done\n[10:15:04] + hq-msg01 created · Salary review\n")
+	ev := recvEvent(t, a)
+	if ev.Kind != KindBead || ev.Type != "complete" || ev.Target != "gt-abc12" || ev.Summary != "closed · Fix login" {
+		t.Errorf("bead event = %+v", ev)
+	}
+	if ev := recvEvent(t, a); ev.Kind != KindConvoy || ev.Target != "hq-cv-xyz" {
+		t.Errorf("convoy bead event = %+v", ev)
+	}
+	// A message bead's summary is its subject: it goes out as mail, which
+	// viewers get without the summary.
+	ev = recvEvent(t, a)
+	if ev.Kind != KindMail || ev.Target != "hq-msg01" {
+		t.Errorf("message bead event = %+v, want mail", ev)
+	}
+	if got := ev.forViewer(); got.Summary != "" {
+		t.Errorf("viewer message bead event = %+v, want no summary", got)
+	}
+
+	cancelA()
+	if _, ok := <-a; ok {
+		t.Error("channel still open after cancel")
+	}
+}
+
+func TestHub_DropsSlowSubscriber(t *testing.T) {
+	hub := &Hub{subs: make(map[chan DashboardEvent]struct{})}
+	ch := make(chan DashboardEvent, subscriberBuffer)
+	hub.subs[ch] = struct{}{}
+	for i := 0; i <= subscriberBuffer; i++ {
+		hub.Publish(DashboardEvent{Kind: KindWorker})
+	}
+	n := 0
+	for range ch {
+		n++
+	}
+	if n != subscriberBuffer {
+		t.Errorf("slow subscriber got %d events before disconnect, want %d", n, subscriberBuffer)
+	}
+	if len(hub.subs) != 0 {
+		t.Error("slow subscriber still registered")
+	}
+}
+
+func TestDashboardEvent_ForViewer(t *testing.T) {
+	mail := DashboardEvent{Kind: KindMail, Target: "gastown/witness", Summary: "hi",
+		Payload: map[string]interface{}{"to": "gastown/witness", "subject": "hi"}}
+	got := mail.forViewer()
+	if got.Summary != "" || got.Payload["subject"] != nil || got.Payload["to"] != "gastown/witness" || got.Target != "gastown/witness" {
+		t.Errorf("viewer mail event = %+v, want recipient without subject", got)
+	}
+	if mail.Payload["subject"] != "hi" {
+		t.Error("forViewer modified the shared payload")
+	}
+
+	merge := DashboardEvent{Kind: KindMerge, Summary: "Fix login"}
+	if got := merge.forViewer(); got.Summary != "Fix login" {
+		t.Errorf("viewer merge event = %+v, want unchanged", got)
+	}
+}
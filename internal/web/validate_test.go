@@ -247,7 +247,7 @@ func TestExpandHomePath(t *testing.T) {
 // real binaries (e.g., via gastown-docker).
 
 func TestHandler_MailRead_InvalidID(t *testing.T) {
-	handler := NewAPIHandler(30*time.Second, 60*time.Second)
+	handler := NewAPIHandler(30*time.Second, 60*time.Second, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/mail/read?id=--inject", nil)
 	w := httptest.NewRecorder()
@@ -259,7 +259,7 @@ func TestHandler_MailRead_InvalidID(t *testing.T) {
 }
 
 func TestHandler_MailSend_InvalidRecipient(t *testing.T) {
-	handler := NewAPIHandler(30*time.Second, 60*time.Second)
+	handler := NewAPIHandler(30*time.Second, 60*time.Second, nil)
 
 	body := `{"to": "--flag", "subject": "test"}`
 	req := httptest.NewRequest(http.MethodPost, "/api/mail/send", bytes.NewBufferString(body))
@@ -273,7 +273,7 @@ func TestHandler_MailSend_InvalidRecipient(t *testing.T) {
 }
 
 func TestHandler_MailSend_ValidAgentPath(t *testing.T) {
-	handler := NewAPIHandler(30*time.Second, 60*time.Second)
+	handler := NewAPIHandler(30*time.Second, 60*time.Second, nil)
 
 	// rig/agent is a valid mail address — should NOT be rejected by validation.
 	// gt isn't available in test, so expect 500 (command failed), NOT 400 (validation).
@@ -298,7 +298,7 @@ func TestHandler_MailSend_ValidAgentPath(t *testing.T) {
 }
 
 func TestHandler_MailSend_OversizedSubject(t *testing.T) {
-	handler := NewAPIHandler(30*time.Second, 60*time.Second)
+	handler := NewAPIHandler(30*time.Second, 60*time.Second, nil)
 
 	payload := map[string]interface{}{
 		"to":      "alice",
@@ -316,7 +316,7 @@ func TestHandler_MailSend_OversizedSubject(t *testing.T) {
 }
 
 func TestHandler_IssueShow_InvalidID(t *testing.T) {
-	handler := NewAPIHandler(30*time.Second, 60*time.Second)
+	handler := NewAPIHandler(30*time.Second, 60*time.Second, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/issues/show?id=--help", nil)
 	w := httptest.NewRecorder()
@@ -328,7 +328,7 @@ func TestHandler_IssueShow_InvalidID(t *testing.T) {
 }
 
 func TestHandler_PRShow_InvalidNumber(t *testing.T) {
-	handler := NewAPIHandler(30*time.Second, 60*time.Second)
+	handler := NewAPIHandler(30*time.Second, 60*time.Second, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/pr/show?repo=owner/repo&number=abc", nil)
 	w := httptest.NewRecorder()
@@ -340,7 +340,7 @@ func TestHandler_PRShow_InvalidNumber(t *testing.T) {
 }
 
 func TestHandler_PRShow_InvalidURL(t *testing.T) {
-	handler := NewAPIHandler(30*time.Second, 60*time.Second)
+	handler := NewAPIHandler(30*time.Second, 60*time.Second, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/pr/show?url=--evil", nil)
 	w := httptest.NewRecorder()
@@ -352,7 +352,7 @@ func TestHandler_PRShow_InvalidURL(t *testing.T) {
 }
 
 func TestHandler_IssueShow_ExternalPrefixID(t *testing.T) {
-	handler := NewAPIHandler(30*time.Second, 60*time.Second)
+	handler := NewAPIHandler(30*time.Second, 60*time.Second, nil)
 
 	// external:prefix:id format should pass validation (unwrapped to raw ID).
 	// Expect 500 (bd not available), NOT 400 (validation failure).
@@ -375,7 +375,7 @@ func TestHandler_IssueShow_ExternalPrefixID(t *testing.T) {
 }
 
 func TestHandler_PRShow_URLIgnoresRepoNumber(t *testing.T) {
-	handler := NewAPIHandler(30*time.Second, 60*time.Second)
+	handler := NewAPIHandler(30*time.Second, 60*time.Second, nil)
 
 	// When url is provided, repo/number should be ignored (not validated).
 	// Expect 500 (gh not available), NOT 400 (validation failure).
@@ -483,7 +483,7 @@ func TestSetupHandler_CheckWorkspace_TraversalPath(t *testing.T) {
 }
 
 func TestHandler_IssueShow_MalformedExternalPrefix(t *testing.T) {
-	handler := NewAPIHandler(30*time.Second, 60*time.Second)
+	handler := NewAPIHandler(30*time.Second, 60*time.Second, nil)
 
 	// external:foo (only 2 parts) should get a specific error, not generic "Invalid issue ID".
 	req := httptest.NewRequest(http.MethodGet, "/api/issues/show?id=external:foo", nil)
@@ -504,7 +504,7 @@ func TestHandler_IssueShow_MalformedExternalPrefix(t *testing.T) {
 }
 
 func TestHandler_IssueShow_ExternalWithExtraColons(t *testing.T) {
-	handler := NewAPIHandler(30*time.Second, 60*time.Second)
+	handler := NewAPIHandler(30*time.Second, 60*time.Second, nil)
 
 	// SplitN(":", 3) puts "id:with:colons" in parts[2]. isValidID rejects colons.
 	req := httptest.NewRequest(http.MethodGet, "/api/issues/show?id=external:prefix:id:with:colons", nil)
@@ -525,7 +525,7 @@ func TestHandler_IssueShow_ExternalWithExtraColons(t *testing.T) {
 }
 
 func TestHandler_MailSend_NullByteSubject(t *testing.T) {
-	handler := NewAPIHandler(30*time.Second, 60*time.Second)
+	handler := NewAPIHandler(30*time.Second, 60*time.Second, nil)
 
 	body := `{"to": "alice", "subject": "test\u0000inject"}`
 	req := httptest.NewRequest(http.MethodPost, "/api/mail/send", bytes.NewBufferString(body))
@@ -539,7 +539,7 @@ func TestHandler_MailSend_NullByteSubject(t *testing.T) {
 }
 
 func TestHandler_IssueCreate_FlagTitle(t *testing.T) {
-	handler := NewAPIHandler(30*time.Second, 60*time.Second)
+	handler := NewAPIHandler(30*time.Second, 60*time.Second, nil)
 
 	// A title of "--help" should pass validation (no control chars, no newlines)
 	// and reach bd create. The -- sentinel ensures it's treated as positional.
@@ -556,7 +556,7 @@ func TestHandler_IssueCreate_FlagTitle(t *testing.T) {
 }
 
 func TestHandler_SessionPreview_MissingParam(t *testing.T) {
-	handler := NewAPIHandler(30*time.Second, 60*time.Second)
+	handler := NewAPIHandler(30*time.Second, 60*time.Second, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/session/preview", nil)
 	w := httptest.NewRecorder()
@@ -568,7 +568,7 @@ func TestHandler_SessionPreview_MissingParam(t *testing.T) {
 }
 
 func TestHandler_SessionPreview_InvalidPrefix(t *testing.T) {
-	handler := NewAPIHandler(30*time.Second, 60*time.Second)
+	handler := NewAPIHandler(30*time.Second, 60*time.Second, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/session/preview?session=evil-session", nil)
 	w := httptest.NewRecorder()
@@ -580,7 +580,7 @@ func TestHandler_SessionPreview_InvalidPrefix(t *testing.T) {
 }
 
 func TestHandler_SessionPreview_InvalidChars(t *testing.T) {
-	handler := NewAPIHandler(30*time.Second, 60*time.Second)
+	handler := NewAPIHandler(30*time.Second, 60*time.Second, nil)
 
 	// Session name with shell metacharacters should be rejected
 	req := httptest.NewRequest(http.MethodGet, "/api/session/preview?session=gt-evil;rm+-rf+/", nil)
@@ -593,7 +593,7 @@ func TestHandler_SessionPreview_InvalidChars(t *testing.T) {
 }
 
 func TestHandler_SessionPreview_ValidName(t *testing.T) {
-	handler := NewAPIHandler(30*time.Second, 60*time.Second)
+	handler := NewAPIHandler(30*time.Second, 60*time.Second, nil)
 
 	// A valid session name should pass validation and reach tmux capture-pane.
 	// tmux isn't available in test, so expect 500 (command failed), NOT 400 (validation).
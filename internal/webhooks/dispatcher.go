@@ -0,0 +1,253 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// PollInterval is how often the dispatcher reads new events and sends due
+// deliveries.
+const PollInterval = 2 * time.Second
+
+// requestTimeout bounds a single delivery attempt.
+const requestTimeout = 10 * time.Second
+
+// userAgent is sent with every delivery.
+const userAgent = "gastown-webhooks/1"
+
+// Dispatcher queues and sends webhook deliveries for a town.
+type Dispatcher struct {
+	townRoot string
+	client   *http.Client
+	logf     func(format string, args ...interface{})
+	now      func() time.Time
+
+	// ruleErrs is the last set of invalid-rule errors logged, so a bad
+	// rule is reported once rather than on every poll.
+	ruleErrs string
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewDispatcher creates a dispatcher. logf receives delivery failures and
+// configuration errors; it may be nil.
+func NewDispatcher(townRoot string, logf func(format string, args ...interface{})) *Dispatcher {
+	if logf == nil {
+		logf = func(string, ...interface{}) {}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Dispatcher{
+		townRoot: townRoot,
+		client:   &http.Client{Timeout: requestTimeout},
+		logf:     logf,
+		now:      time.Now,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Start polls in a goroutine until Stop is called.
+func (d *Dispatcher) Start() {
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		ticker := time.NewTicker(PollInterval)
+		defer ticker.Stop()
+		for {
+			if err := d.Poll(); err != nil {
+				d.logf("Webhooks: %v", err)
+			}
+			select {
+			case <-d.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the polling goroutine and waits for it to exit.
+func (d *Dispatcher) Stop() {
+	d.cancel()
+	d.wg.Wait()
+}
+
+// Poll queues deliveries for events appended since the last poll and sends
+// every delivery that is due. The first poll of a fresh queue starts at the
+// end of the event store, so existing history is not replayed.
+func (d *Dispatcher) Poll() error {
+	rules, errs := LoadRules(d.townRoot)
+	if msg := fmt.Sprint(errs); msg != d.ruleErrs {
+		d.ruleErrs = msg
+		for _, err := range errs {
+			d.logf("Webhooks: skipping rule: %v", err)
+		}
+	}
+
+	var due []Delivery
+	err := update(d.townRoot, func(q *Queue) error {
+		if err := d.enqueue(q, rules); err != nil {
+			return err
+		}
+		for _, dl := range q.due(d.now()) {
+			due = append(due, *dl)
+		}
+		return nil
+	})
+	if err != nil || len(due) == 0 {
+		return err
+	}
+
+	// Send without holding the queue lock; requests can be slow.
+	results := make([]Delivery, 0, len(due))
+	for _, dl := range due {
+		rule, ok := findRule(rules, dl.Webhook)
+		if !ok {
+			dl.Attempts++
+			dl.Status = StatusFailed
+			dl.LastError = "webhook no longer configured"
+			results = append(results, dl)
+			continue
+		}
+		d.attempt(&dl, rule)
+		results = append(results, dl)
+	}
+
+	return update(d.townRoot, func(q *Queue) error {
+		for _, res := range results {
+			if cur := q.Find(res.ID); cur != nil && cur.Status == StatusPending {
+				*cur = res
+			}
+		}
+		return nil
+	})
+}
+
+// enqueue reads new events from the store and adds a delivery for each
+// rule they match.
+func (d *Dispatcher) enqueue(q *Queue, rules []config.WebhookRule) error {
+	store := events.OpenStore(d.townRoot)
+	if q.Cursor == "" {
+		end, err := store.End()
+		if err != nil {
+			return fmt.Errorf("reading event store: %w", err)
+		}
+		q.Cursor = end.String()
+		return nil
+	}
+	from, err := events.ParseCursor(q.Cursor)
+	if err != nil {
+		return err
+	}
+	tail := store.Tail(from, events.Query{})
+	recs, err := tail.Next()
+	q.Cursor = tail.Cursor().String()
+	for _, rec := range recs {
+		for _, rule := range rules {
+			if !Matches(rule, rec.Event) {
+				continue
+			}
+			q.Deliveries = append(q.Deliveries, &Delivery{
+				ID:        newDeliveryID(),
+				Webhook:   rule.Name,
+				URL:       util.RedactSecretURL(rule.URL),
+				Event:     rec.Event,
+				Status:    StatusPending,
+				CreatedAt: d.now(),
+			})
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("reading event store: %w", err)
+	}
+	return nil
+}
+
+// attempt sends dl once and records the outcome on it.
+func (d *Dispatcher) attempt(dl *Delivery, rule config.WebhookRule) {
+	dl.Attempts++
+	status, err := d.send(dl, rule)
+	dl.LastStatus = status
+	if err == nil {
+		dl.Status = StatusDelivered
+		dl.DeliveredAt = d.now()
+		dl.LastError = ""
+		return
+	}
+	dl.LastError = err.Error()
+	if !retryable(status) || dl.Attempts >= MaxAttempts {
+		dl.Status = StatusFailed
+		d.logf("Webhooks: delivery %s to %s failed after %d attempt(s): %v", dl.ID, dl.Webhook, dl.Attempts, err)
+		return
+	}
+	dl.NextAttempt = d.now().Add(backoff(dl.Attempts))
+}
+
+// send POSTs the delivery. It returns the response status (0 when no
+// response was received) and an error for anything other than a 2xx.
+func (d *Dispatcher) send(dl *Delivery, rule config.WebhookRule) (int, error) {
+	body, err := encodePayload(dl)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodPost, rule.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, util.RedactURLError(rule.URL, err)
+	}
+	ts := d.now().Unix()
+	for k, v := range rule.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderEvent, dl.Event.Type)
+	req.Header.Set(HeaderDelivery, dl.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	if key := rule.SigningKey(); key != "" {
+		req.Header.Set(HeaderSignature, Sign(key, ts, body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		// Transport errors quote the full URL, which carries the secret for
+		// most webhook receivers; they end up in the queue file and logs.
+		return 0, util.RedactURLError(rule.URL, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// retryable reports whether a failed attempt with the given response status
+// is worth retrying. Client errors other than timeouts and rate limits mean
+// the receiver rejected the request and will keep rejecting it.
+func retryable(status int) bool {
+	if status >= 400 && status < 500 {
+		return status == http.StatusRequestTimeout || status == http.StatusTooManyRequests
+	}
+	return true
+}
+
+func findRule(rules []config.WebhookRule, name string) (config.WebhookRule, bool) {
+	for _, r := range rules {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return config.WebhookRule{}, false
+}
@@ -0,0 +1,183 @@
+package webhooks
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Delivery statuses.
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
+// Retry policy. A delivery is retried after 30s, 1m, 2m, ... capped at an
+// hour between attempts, and marked failed after MaxAttempts.
+const (
+	MaxAttempts    = 10
+	initialBackoff = 30 * time.Second
+	maxBackoff     = time.Hour
+)
+
+// historyRetention bounds how long delivered and failed deliveries are kept.
+const historyRetention = 7 * 24 * time.Hour
+
+// Delivery is one event queued for one webhook.
+type Delivery struct {
+	ID          string       `json:"id"`
+	Webhook     string       `json:"webhook"`
+	URL         string       `json:"url"`
+	Event       events.Event `json:"event"`
+	Status      string       `json:"status"`
+	Attempts    int          `json:"attempts"`
+	NextAttempt time.Time    `json:"next_attempt,omitempty"`
+	LastStatus  int          `json:"last_status,omitempty"` // HTTP status of the last attempt
+	LastError   string       `json:"last_error,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	DeliveredAt time.Time    `json:"delivered_at,omitempty"`
+}
+
+// Queue is the persisted delivery queue and the event store position the
+// dispatcher has read up to.
+type Queue struct {
+	Cursor     string      `json:"cursor,omitempty"`
+	Deliveries []*Delivery `json:"deliveries"`
+}
+
+// Dir returns the webhook state directory for a town.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "webhooks")
+}
+
+func queuePath(townRoot string) string {
+	return filepath.Join(Dir(townRoot), "deliveries.json")
+}
+
+// Load reads the town's delivery queue. A missing queue is empty.
+func Load(townRoot string) (*Queue, error) {
+	q := &Queue{}
+	data, err := os.ReadFile(queuePath(townRoot))
+	if os.IsNotExist(err) {
+		return q, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, q); err != nil {
+		return nil, fmt.Errorf("parsing webhook deliveries: %w", err)
+	}
+	return q, nil
+}
+
+// update applies fn to the town's queue under the queue lock and saves it.
+func update(townRoot string, fn func(*Queue) error) error {
+	path := queuePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking webhook deliveries: %w", err)
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	q, err := Load(townRoot)
+	if err != nil {
+		return err
+	}
+	if err := fn(q); err != nil {
+		return err
+	}
+	q.prune(time.Now())
+	return util.AtomicWriteJSON(path, q)
+}
+
+// Find returns the delivery with the given ID, or nil.
+func (q *Queue) Find(id string) *Delivery {
+	for _, d := range q.Deliveries {
+		if d.ID == id {
+			return d
+		}
+	}
+	return nil
+}
+
+// Sorted returns the deliveries newest first.
+func (q *Queue) Sorted() []*Delivery {
+	out := append([]*Delivery(nil), q.Deliveries...)
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].CreatedAt.After(out[j].CreatedAt)
+	})
+	return out
+}
+
+// due returns the pending deliveries whose next attempt is at or before now.
+func (q *Queue) due(now time.Time) []*Delivery {
+	var out []*Delivery
+	for _, d := range q.Deliveries {
+		if d.Status == StatusPending && !d.NextAttempt.After(now) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// prune drops delivered and failed deliveries older than historyRetention.
+func (q *Queue) prune(now time.Time) {
+	kept := q.Deliveries[:0]
+	for _, d := range q.Deliveries {
+		if d.Status != StatusPending && now.Sub(d.CreatedAt) > historyRetention {
+			continue
+		}
+		kept = append(kept, d)
+	}
+	q.Deliveries = kept
+}
+
+// Redeliver resets a delivery so the dispatcher sends it again on its next
+// poll, whatever its status.
+func Redeliver(townRoot, id string) (*Delivery, error) {
+	var out *Delivery
+	err := update(townRoot, func(q *Queue) error {
+		d := q.Find(id)
+		if d == nil {
+			return fmt.Errorf("delivery %s not found", id)
+		}
+		d.Status = StatusPending
+		d.Attempts = 0
+		d.NextAttempt = time.Time{}
+		d.LastError = ""
+		d.LastStatus = 0
+		d.DeliveredAt = time.Time{}
+		cp := *d
+		out = &cp
+		return nil
+	})
+	return out, err
+}
+
+// backoff returns the wait after the given number of failed attempts.
+func backoff(attempts int) time.Duration {
+	d := initialBackoff
+	for i := 1; i < attempts && d < maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, maxBackoff)
+}
+
+func newDeliveryID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return "wh-" + hex.EncodeToString(b)
+}
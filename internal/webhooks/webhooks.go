@@ -0,0 +1,95 @@
+// Package webhooks delivers town events to external URLs.
+//
+// Rules live in town settings (settings/config.json "webhooks"). The daemon
+// runs a Dispatcher that tails the event store, queues a delivery for each
+// event a rule matches, and POSTs it with an HMAC signature. Failed
+// deliveries are retried with exponential backoff. The queue and delivery
+// history are kept in ~/gt/.runtime/webhooks/deliveries.json and shown by
+// 'gt webhooks deliveries'.
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"path"
+	"slices"
+	"strconv"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Request headers sent with every delivery.
+const (
+	HeaderEvent     = "X-Gastown-Event"
+	HeaderDelivery  = "X-Gastown-Delivery"
+	HeaderTimestamp = "X-Gastown-Timestamp"
+	// HeaderSignature is "sha256=" followed by the hex HMAC-SHA256 of
+	// "<timestamp>.<body>" keyed with the webhook's secret. It is omitted
+	// when the webhook has no secret.
+	HeaderSignature = "X-Gastown-Signature"
+)
+
+// LoadRules returns the town's webhook rules. Invalid rules are returned
+// in errs and left out of rules.
+func LoadRules(townRoot string) (rules []config.WebhookRule, errs []error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, []error{err}
+	}
+	for _, r := range settings.Webhooks {
+		if err := r.Validate(); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		rules = append(rules, r)
+	}
+	return rules, errs
+}
+
+// Matches reports whether the rule should deliver ev.
+func Matches(rule config.WebhookRule, ev events.Event) bool {
+	if rule.Disabled {
+		return false
+	}
+	if len(rule.Events) > 0 && !slices.Contains(rule.Events, ev.Type) {
+		return false
+	}
+	if len(rule.Actors) == 0 {
+		return true
+	}
+	for _, pattern := range rule.Actors {
+		if ok, _ := path.Match(pattern, ev.Actor); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Payload is the JSON body POSTed for a delivery.
+type Payload struct {
+	Delivery string       `json:"delivery"`
+	Webhook  string       `json:"webhook"`
+	Event    events.Event `json:"event"`
+}
+
+// Sign returns the signature header value for body sent at timestamp.
+func Sign(key string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is valid for body sent at timestamp.
+// Receivers written in Go can use it directly.
+func Verify(key string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(key, timestamp, body)), []byte(signature))
+}
+
+func encodePayload(d *Delivery) (json.RawMessage, error) {
+	return json.Marshal(Payload{Delivery: d.ID, Webhook: d.Webhook, Event: d.Event})
+}
@@ -0,0 +1,275 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func townWithRules(t *testing.T, rules ...config.WebhookRule) string {
+	t.Helper()
+	town := t.TempDir()
+	settings := config.NewTownSettings()
+	settings.Webhooks = rules
+	if err := config.SaveTownSettings(config.TownSettingsPath(town), settings); err != nil {
+		t.Fatal(err)
+	}
+	return town
+}
+
+func appendEvents(t *testing.T, town string, evs ...events.Event) {
+	t.Helper()
+	for i := range evs {
+		evs[i].Timestamp = time.Now().UTC().Format(events.TimestampFormat)
+		evs[i].Source = "gt"
+		evs[i].Visibility = events.VisibilityFeed
+	}
+	if err := events.OpenStore(town).Append(evs...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	rule := config.WebhookRule{
+		Name:   "merges",
+		URL:    "https://example.com/hook",
+		Events: []string{events.TypeMerged, events.TypeMergeFailed},
+		Actors: []string{"gastown/*"},
+	}
+	tests := []struct {
+		ev   events.Event
+		want bool
+	}{
+		{events.Event{Type: events.TypeMerged, Actor: "gastown/refinery"}, true},
+		{events.Event{Type: events.TypeMergeFailed, Actor: "gastown/refinery"}, true},
+		{events.Event{Type: events.TypeMerged, Actor: "beads/refinery"}, false},
+		{events.Event{Type: events.TypeSling, Actor: "gastown/refinery"}, false},
+	}
+	for _, tt := range tests {
+		if got := Matches(rule, tt.ev); got != tt.want {
+			t.Errorf("Matches(%s by %s) = %v, want %v", tt.ev.Type, tt.ev.Actor, got, tt.want)
+		}
+	}
+	if !Matches(config.WebhookRule{Name: "all"}, events.Event{Type: "anything"}) {
+		t.Error("rule without filters should match every event")
+	}
+	rule.Disabled = true
+	if Matches(rule, tests[0].ev) {
+		t.Error("disabled rule should not match")
+	}
+}
+
+func TestRuleValidate(t *testing.T) {
+	valid := config.WebhookRule{Name: "ok", URL: "https://example.com/hook", Actors: []string{"*/witness"}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("valid rule: %v", err)
+	}
+	for _, r := range []config.WebhookRule{
+		{URL: "https://example.com"},
+		{Name: "scheme", URL: "ftp://example.com"},
+		{Name: "host", URL: "https:///path"},
+		{Name: "pattern", URL: "https://example.com", Actors: []string{"[bad"}},
+	} {
+		if err := r.Validate(); err == nil {
+			t.Errorf("rule %q should be invalid", r.Name)
+		}
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"event":{}}`)
+	sig := Sign("s3cret", 1700000000, body)
+	if len(sig) != len("sha256=")+64 {
+		t.Fatalf("signature = %q", sig)
+	}
+	if !Verify("s3cret", 1700000000, body, sig) {
+		t.Error("signature did not verify")
+	}
+	if Verify("s3cret", 1700000001, body, sig) || Verify("other", 1700000000, body, sig) {
+		t.Error("signature verified with the wrong timestamp or key")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	want := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute}
+	for i, w := range want {
+		if got := backoff(i + 1); got != w {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+	if got := backoff(MaxAttempts); got != maxBackoff {
+		t.Errorf("backoff(%d) = %v, want cap %v", MaxAttempts, got, maxBackoff)
+	}
+}
+
+func TestDispatcher_DeliversSigned(t *testing.T) {
+	var mu sync.Mutex
+	var got []*http.Request
+	var bodies [][]byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		got = append(got, r)
+		bodies = append(bodies, body)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	town := townWithRules(t, config.WebhookRule{
+		Name:    "ci",
+		URL:     srv.URL,
+		Events:  []string{events.TypeMerged},
+		Secret:  "s3cret",
+		Headers: map[string]string{"Authorization": "Bearer token"},
+	})
+	appendEvents(t, town, events.Event{Type: events.TypeMerged, Actor: "gastown/refinery"})
+
+	d := NewDispatcher(town, t.Logf)
+	// The first poll starts at the end of the store: history is not replayed.
+	if err := d.Poll(); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("existing events delivered on first poll: %d", len(got))
+	}
+
+	appendEvents(t, town,
+		events.Event{Type: events.TypeSling, Actor: "mayor"},
+		events.Event{Type: events.TypeMerged, Actor: "gastown/refinery"},
+	)
+	if err := d.Poll(); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("requests = %d, want 1", len(got))
+	}
+	r, body := got[0], bodies[0]
+	if r.Header.Get(HeaderEvent) != events.TypeMerged || r.Header.Get("Authorization") != "Bearer token" {
+		t.Errorf("headers = %v", r.Header)
+	}
+	ts, _ := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+	if !Verify("s3cret", ts, body, r.Header.Get(HeaderSignature)) {
+		t.Errorf("bad signature %q", r.Header.Get(HeaderSignature))
+	}
+	var p Payload
+	if err := json.Unmarshal(body, &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Webhook != "ci" || p.Delivery != r.Header.Get(HeaderDelivery) || p.Event.Actor != "gastown/refinery" {
+		t.Errorf("payload = %+v", p)
+	}
+
+	q, err := Load(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(q.Deliveries) != 1 || q.Deliveries[0].Status != StatusDelivered {
+		t.Errorf("deliveries = %+v", q.Deliveries)
+	}
+}
+
+func TestDispatcher_RetriesThenFails(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	town := townWithRules(t, config.WebhookRule{Name: "flaky", URL: srv.URL})
+	now := time.Now()
+	d := NewDispatcher(town, t.Logf)
+	d.now = func() time.Time { return now }
+	if err := d.Poll(); err != nil {
+		t.Fatal(err)
+	}
+	appendEvents(t, town, events.Event{Type: events.TypeDone, Actor: "gastown/polecats/toast"})
+	if err := d.Poll(); err != nil {
+		t.Fatal(err)
+	}
+
+	dl := mustSingleDelivery(t, town)
+	if dl.Status != StatusPending || dl.Attempts != 1 || dl.LastStatus != status {
+		t.Fatalf("after first failure: %+v", dl)
+	}
+	if want := now.Add(initialBackoff); !dl.NextAttempt.Equal(want) {
+		t.Errorf("next attempt = %v, want %v", dl.NextAttempt, want)
+	}
+
+	// Not due yet: no new attempt.
+	if err := d.Poll(); err != nil {
+		t.Fatal(err)
+	}
+	if dl := mustSingleDelivery(t, town); dl.Attempts != 1 {
+		t.Errorf("attempted before backoff elapsed: %d", dl.Attempts)
+	}
+
+	// A permanent client error fails the delivery without further retries.
+	status = http.StatusNotFound
+	now = now.Add(initialBackoff)
+	if err := d.Poll(); err != nil {
+		t.Fatal(err)
+	}
+	dl = mustSingleDelivery(t, town)
+	if dl.Status != StatusFailed || dl.Attempts != 2 {
+		t.Fatalf("after 404: %+v", dl)
+	}
+
+	// Redelivery sends it again.
+	status = http.StatusOK
+	if _, err := Redeliver(town, dl.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Poll(); err != nil {
+		t.Fatal(err)
+	}
+	if dl := mustSingleDelivery(t, town); dl.Status != StatusDelivered || dl.Attempts != 1 {
+		t.Errorf("after redeliver: %+v", dl)
+	}
+}
+
+func TestDispatcher_TransportErrorRedactsURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	hookURL := strings.Replace(srv.URL, "://", "://bot:pa55word@", 1) + "/hooks/T000/B000/s3cret-token?key=abc"
+	srv.Close() // refuse connections
+
+	town := townWithRules(t, config.WebhookRule{Name: "slack", URL: hookURL})
+	d := NewDispatcher(town, t.Logf)
+	if err := d.Poll(); err != nil {
+		t.Fatal(err)
+	}
+	appendEvents(t, town, events.Event{Type: events.TypeDone, Actor: "gastown/polecats/toast"})
+	if err := d.Poll(); err != nil {
+		t.Fatal(err)
+	}
+
+	dl := mustSingleDelivery(t, town)
+	if dl.LastError == "" || dl.Attempts != 1 {
+		t.Fatalf("want a failed attempt: %+v", dl)
+	}
+	for _, field := range []string{dl.LastError, dl.URL} {
+		if strings.Contains(field, "s3cret") || strings.Contains(field, "key=") || strings.Contains(field, "pa55word") {
+			t.Errorf("delivery leaks the webhook secret: %q", field)
+		}
+	}
+}
+
+func mustSingleDelivery(t *testing.T, town string) *Delivery {
+	t.Helper()
+	q, err := Load(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(q.Deliveries) != 1 {
+		t.Fatalf("deliveries = %d, want 1", len(q.Deliveries))
+	}
+	return q.Deliveries[0]
+}
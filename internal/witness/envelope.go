@@ -0,0 +1,306 @@
+package witness
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// envelopeTypes maps witness protocol types to their envelope type names.
+// The names match protocol.MessageType so both packages read the same wire
+// format (protocol imports witness, so the values are repeated here).
+var envelopeTypes = map[ProtocolType]string{
+	ProtoPolecatDone:       "POLECAT_DONE",
+	ProtoLifecycleShutdown: "LIFECYCLE_SHUTDOWN",
+	ProtoHelp:              "HELP",
+	ProtoMerged:            "MERGED",
+	ProtoMergeFailed:       "MERGE_FAILED",
+	ProtoMergeReady:        "MERGE_READY",
+	ProtoHandoff:           "HANDOFF",
+	ProtoSwarmStart:        "SWARM_START",
+}
+
+// EnvelopeType returns the envelope type name for a protocol type,
+// or "" for ProtoUnknown.
+func (p ProtocolType) EnvelopeType() string {
+	return envelopeTypes[p]
+}
+
+// ClassifyMail determines the protocol type of a message. A valid envelope
+// decides; otherwise the legacy subject patterns are used. A malformed
+// envelope also falls back to the subject, so the message still reaches its
+// handler, whose parser then reports the envelope error.
+func ClassifyMail(msg *mail.Message) ProtocolType {
+	if env, err := msg.Envelope(); err == nil {
+		for proto, name := range envelopeTypes {
+			if env.Type == name {
+				return proto
+			}
+		}
+		return ProtoUnknown
+	}
+	return ClassifyMessage(msg.Subject)
+}
+
+// decodeEnvelope decodes an envelope of the given type from body into v.
+// It reports false when the body has no envelope, meaning the caller should
+// use the legacy parser.
+func decodeEnvelope(body string, proto ProtocolType, v interface{}) (bool, error) {
+	env, err := mail.ExtractEnvelope(body)
+	if errors.Is(err, mail.ErrNoEnvelope) {
+		return false, nil
+	}
+	if err != nil {
+		return true, fmt.Errorf("%s: %w", proto.EnvelopeType(), err)
+	}
+	return true, env.DecodeBody(proto.EnvelopeType(), v)
+}
+
+func requirePolecat(proto ProtocolType, name string) error {
+	if name == "" {
+		return fmt.Errorf("invalid %s envelope: missing polecat", proto.EnvelopeType())
+	}
+	return nil
+}
+
+// newProtocolMessage builds a message with the legacy subject and body plus
+// a typed envelope, so old and new readers both understand it.
+func newProtocolMessage(from, to, subject, body string, proto ProtocolType, correlationID string, payload interface{}) (*mail.Message, error) {
+	msg := mail.NewMessage(from, to, subject, body)
+	env, err := mail.NewEnvelope(proto.EnvelopeType(), correlationID, payload)
+	if err != nil {
+		return nil, err
+	}
+	if err := msg.AttachEnvelope(env); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// LifecycleShutdownPayload contains parsed data from a LIFECYCLE:Shutdown message.
+type LifecycleShutdownPayload struct {
+	PolecatName string `json:"polecat"`
+	Reason      string `json:"reason,omitempty"`
+	RequestedBy string `json:"requested_by,omitempty"`
+	BeadID      string `json:"bead,omitempty"`
+	NewAssignee string `json:"new_assignee,omitempty"`
+}
+
+// ParseLifecycleShutdown extracts payload from a LIFECYCLE:Shutdown message.
+// Subject format: LIFECYCLE:Shutdown <polecat-name>
+// Body format:
+//
+//	Reason: <reason>
+//	RequestedBy: <agent>
+//	Bead: <bead-id>
+//	NewAssignee: <agent>
+func ParseLifecycleShutdown(subject, body string) (*LifecycleShutdownPayload, error) {
+	var env LifecycleShutdownPayload
+	if ok, err := decodeEnvelope(body, ProtoLifecycleShutdown, &env); ok {
+		if err != nil {
+			return nil, err
+		}
+		return &env, requirePolecat(ProtoLifecycleShutdown, env.PolecatName)
+	}
+
+	matches := PatternLifecycleShutdown.FindStringSubmatch(subject)
+	if len(matches) < 2 {
+		return nil, fmt.Errorf("invalid LIFECYCLE:Shutdown subject: %s", subject)
+	}
+	return &LifecycleShutdownPayload{
+		PolecatName: matches[1],
+		Reason:      bodyField(body, "Reason"),
+		RequestedBy: bodyField(body, "RequestedBy"),
+		BeadID:      bodyField(body, "Bead"),
+		NewAssignee: bodyField(body, "NewAssignee"),
+	}, nil
+}
+
+// HandoffPayload contains parsed data from a HANDOFF message.
+type HandoffPayload struct {
+	Topic string `json:"topic,omitempty"`
+	Notes string `json:"notes,omitempty"`
+}
+
+// ParseHandoff extracts payload from a HANDOFF message.
+// Subject format: 🤝 HANDOFF[: <topic>]
+// The legacy body is free-form handoff notes.
+func ParseHandoff(subject, body string) (*HandoffPayload, error) {
+	var env HandoffPayload
+	if ok, err := decodeEnvelope(body, ProtoHandoff, &env); ok {
+		if err != nil {
+			return nil, err
+		}
+		return &env, nil
+	}
+
+	loc := PatternHandoff.FindStringIndex(subject)
+	if loc == nil {
+		return nil, fmt.Errorf("invalid HANDOFF subject: %s", subject)
+	}
+	topic := strings.TrimSpace(subject[loc[1]:])
+	topic = strings.TrimSpace(strings.TrimPrefix(topic, ":"))
+	return &HandoffPayload{Topic: topic, Notes: strings.TrimSpace(body)}, nil
+}
+
+// bodyField returns the value of a "Key: value" line in body.
+func bodyField(body, key string) string {
+	prefix := key + ":"
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, prefix) {
+			return strings.TrimSpace(strings.TrimPrefix(line, prefix))
+		}
+	}
+	return ""
+}
+
+// AttachLegacyEnvelope adds a typed envelope to a protocol message that was
+// composed by hand, e.g. an agent's "gt mail send <rig>/witness -s 'HELP: ...'".
+// The payload is parsed from the legacy subject and body, which are kept as
+// written. Messages that are not witness protocol messages, or that already
+// carry an envelope, are left unchanged. MERGED and MERGE_FAILED bodies that
+// omit the rig take it from the "<rig>/witness" recipient.
+func AttachLegacyEnvelope(msg *mail.Message) error {
+	if _, err := msg.Envelope(); !errors.Is(err, mail.ErrNoEnvelope) {
+		return nil
+	}
+
+	var payload interface{}
+	var correlationID string
+	var err error
+	proto := ClassifyMessage(msg.Subject)
+	switch proto {
+	case ProtoPolecatDone:
+		var p *PolecatDonePayload
+		if p, err = ParsePolecatDone(msg.Subject, msg.Body); err == nil {
+			payload, correlationID = p, p.IssueID
+		}
+	case ProtoLifecycleShutdown:
+		var p *LifecycleShutdownPayload
+		if p, err = ParseLifecycleShutdown(msg.Subject, msg.Body); err == nil {
+			payload, correlationID = p, p.BeadID
+		}
+	case ProtoHelp:
+		var p *HelpPayload
+		if p, err = ParseHelp(msg.Subject, msg.Body); err == nil {
+			payload, correlationID = p, p.IssueID
+		}
+	case ProtoMerged:
+		var p *MergedPayload
+		if p, err = ParseMerged(msg.Subject, msg.Body); err == nil {
+			if p.Rig == "" {
+				p.Rig = recipientRig(msg.To)
+			}
+			payload, correlationID = p, p.IssueID
+		}
+	case ProtoMergeFailed:
+		var p *MergeFailedPayload
+		if p, err = ParseMergeFailed(msg.Subject, msg.Body); err == nil {
+			if p.Rig == "" {
+				p.Rig = recipientRig(msg.To)
+			}
+			payload, correlationID = p, p.IssueID
+		}
+	case ProtoMergeReady:
+		var p *MergeReadyPayload
+		if p, err = ParseMergeReady(msg.Subject, msg.Body); err == nil {
+			payload, correlationID = p, p.IssueID
+		}
+	case ProtoHandoff:
+		payload, err = ParseHandoff(msg.Subject, msg.Body)
+	case ProtoSwarmStart:
+		var p *SwarmStartPayload
+		if p, err = ParseSwarmStart(msg.Body); err == nil {
+			payload, correlationID = p, p.SwarmID
+		}
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	env, err := mail.NewEnvelope(proto.EnvelopeType(), correlationID, payload)
+	if err != nil {
+		return err
+	}
+	return msg.AttachEnvelope(env)
+}
+
+// recipientRig returns the rig of a "<rig>/<role>" address, or "".
+func recipientRig(addr string) string {
+	rig, _, ok := strings.Cut(addr, "/")
+	if !ok || rig == "mayor" || rig == "deacon" {
+		return ""
+	}
+	return rig
+}
+
+// NewPolecatDoneMessage encodes a POLECAT_DONE message.
+func NewPolecatDoneMessage(from, to string, p *PolecatDonePayload) (*mail.Message, error) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Exit: %s\n", p.Exit)
+	writeOptional(&sb, "Issue", p.IssueID)
+	writeOptional(&sb, "MR", p.MRID)
+	writeOptional(&sb, "Gate", p.Gate)
+	fmt.Fprintf(&sb, "Branch: %s\n", p.Branch)
+	writeOptional(&sb, "ConvoyID", p.ConvoyID)
+	if p.ConvoyOwned {
+		sb.WriteString("ConvoyOwned: true\n")
+	}
+	writeOptional(&sb, "MergeStrategy", p.MergeStrategy)
+	writeOptional(&sb, "Errors", p.Errors)
+	return newProtocolMessage(from, to, "POLECAT_DONE "+p.PolecatName, sb.String(), ProtoPolecatDone, p.IssueID, p)
+}
+
+// NewLifecycleShutdownMessage encodes a LIFECYCLE:Shutdown message.
+func NewLifecycleShutdownMessage(from, to string, p *LifecycleShutdownPayload) (*mail.Message, error) {
+	var sb strings.Builder
+	writeOptional(&sb, "Reason", p.Reason)
+	writeOptional(&sb, "RequestedBy", p.RequestedBy)
+	writeOptional(&sb, "Bead", p.BeadID)
+	writeOptional(&sb, "NewAssignee", p.NewAssignee)
+	msg, err := newProtocolMessage(from, to, "LIFECYCLE:Shutdown "+p.PolecatName, sb.String(), ProtoLifecycleShutdown, p.BeadID, p)
+	if err != nil {
+		return nil, err
+	}
+	msg.Type = mail.TypeTask
+	msg.Priority = mail.PriorityHigh
+	return msg, nil
+}
+
+// NewMergeReadyMessage encodes a MERGE_READY message.
+func NewMergeReadyMessage(from, to string, p *MergeReadyPayload) (*mail.Message, error) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Branch: %s\n", p.Branch)
+	writeOptional(&sb, "Issue", p.IssueID)
+	writeOptional(&sb, "MR", p.MRID)
+	fmt.Fprintf(&sb, "Polecat: %s\n", p.PolecatName)
+	fmt.Fprintf(&sb, "Rig: %s\n", p.Rig)
+	sb.WriteString("Verified: clean git state\n")
+	msg, err := newProtocolMessage(from, to, "MERGE_READY "+p.PolecatName, sb.String(), ProtoMergeReady, p.IssueID, p)
+	if err != nil {
+		return nil, err
+	}
+	msg.Type = mail.TypeTask
+	msg.Priority = mail.PriorityHigh
+	return msg, nil
+}
+
+// NewHandoffMessage encodes a HANDOFF message.
+func NewHandoffMessage(from, to string, p *HandoffPayload) (*mail.Message, error) {
+	subject := "🤝 HANDOFF"
+	if p.Topic != "" {
+		subject += ": " + p.Topic
+	}
+	return newProtocolMessage(from, to, subject, p.Notes, ProtoHandoff, "", p)
+}
+
+func writeOptional(sb *strings.Builder, key, value string) {
+	if value != "" {
+		fmt.Fprintf(sb, "%s: %s\n", key, value)
+	}
+}
@@ -0,0 +1,182 @@
+package witness
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+func TestWitnessEncoders_RoundTrip(t *testing.T) {
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	done, err := NewPolecatDoneMessage("gastown/nux", "gastown/witness", &PolecatDonePayload{
+		PolecatName: "nux", Exit: "PHASE_COMPLETE", IssueID: "gt-abc", Branch: "polecat/nux", Gate: "gt-gate1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	shutdown, err := NewLifecycleShutdownMessage("gt-sling", "gastown/witness", &LifecycleShutdownPayload{
+		PolecatName: "nux", Reason: "work_reassigned", BeadID: "gt-abc",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// HELP, MERGED, MERGE_FAILED and SWARM_START are composed by hand (or by
+	// the protocol package) and get their envelope from AttachLegacyEnvelope.
+	help := legacyMessage(t, "gastown/nux", "gastown/witness", "HELP: git push failing", "Problem: push rejected")
+	merged := legacyMessage(t, "gastown/refinery", "gastown/witness", "MERGED nux", "Branch: polecat/nux\nMerged-At: "+now.Format(time.RFC3339))
+	failed := legacyMessage(t, "gastown/refinery", "gastown/witness", "MERGE_FAILED nux", "Branch: polecat/nux\nRig: gastown\nFailure-Type: test\nError: FAIL")
+	ready, err := NewMergeReadyMessage("gastown/witness", "gastown/refinery", &MergeReadyPayload{PolecatName: "nux", Rig: "gastown", Branch: "polecat/nux", MRID: "gt-mr1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handoff, err := NewHandoffMessage("mayor/", "mayor/", &HandoffPayload{Topic: "Session cycling", Notes: "continue gt-abc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	swarm := legacyMessage(t, "mayor/", "gastown/witness", "SWARM_START", "SwarmID: sw-1\nBeads: gt-a, gt-b\nTotal: 2")
+
+	tests := []struct {
+		msg  *mail.Message
+		want ProtocolType
+	}{
+		{done, ProtoPolecatDone},
+		{shutdown, ProtoLifecycleShutdown},
+		{help, ProtoHelp},
+		{merged, ProtoMerged},
+		{failed, ProtoMergeFailed},
+		{ready, ProtoMergeReady},
+		{handoff, ProtoHandoff},
+		{swarm, ProtoSwarmStart},
+	}
+	for _, tt := range tests {
+		if got := ClassifyMail(tt.msg); got != tt.want {
+			t.Errorf("ClassifyMail(%q) = %s, want %s", tt.msg.Subject, got, tt.want)
+		}
+		// Legacy subjects are kept so old readers still classify the mail.
+		if got := ClassifyMessage(tt.msg.Subject); got != tt.want {
+			t.Errorf("ClassifyMessage(%q) = %s, want %s", tt.msg.Subject, got, tt.want)
+		}
+	}
+
+	if p, err := ParsePolecatDone(done.Subject, done.Body); err != nil || p.Gate != "gt-gate1" || p.Exit != "PHASE_COMPLETE" {
+		t.Errorf("ParsePolecatDone = %+v, %v", p, err)
+	}
+	if p, err := ParseLifecycleShutdown(shutdown.Subject, shutdown.Body); err != nil || p.PolecatName != "nux" || p.Reason != "work_reassigned" {
+		t.Errorf("ParseLifecycleShutdown = %+v, %v", p, err)
+	}
+	if p, err := ParseHelp(help.Subject, help.Body); err != nil || p.Topic != "git push failing" {
+		t.Errorf("ParseHelp = %+v, %v", p, err)
+	}
+	if p, err := ParseMerged(merged.Subject, merged.Body); err != nil || !p.MergedAt.Equal(now) || p.Rig != "gastown" {
+		t.Errorf("ParseMerged = %+v, %v", p, err)
+	}
+	if p, err := ParseMergeFailed(failed.Subject, failed.Body); err != nil || p.FailureType != "test" || p.Rig != "gastown" {
+		t.Errorf("ParseMergeFailed = %+v, %v", p, err)
+	}
+	if p, err := ParseMergeReady(ready.Subject, ready.Body); err != nil || p.MRID != "gt-mr1" || p.Rig != "gastown" {
+		t.Errorf("ParseMergeReady = %+v, %v", p, err)
+	}
+	if p, err := ParseHandoff(handoff.Subject, handoff.Body); err != nil || p.Notes != "continue gt-abc" {
+		t.Errorf("ParseHandoff = %+v, %v", p, err)
+	}
+	if p, err := ParseSwarmStart(swarm.Body); err != nil || p.Total != 2 || len(p.BeadIDs) != 2 {
+		t.Errorf("ParseSwarmStart = %+v, %v", p, err)
+	}
+}
+
+// legacyMessage builds a hand-written protocol message and attaches its
+// envelope the way gt mail send does.
+func legacyMessage(t *testing.T, from, to, subject, body string) *mail.Message {
+	t.Helper()
+	msg := mail.NewMessage(from, to, subject, body)
+	if err := AttachLegacyEnvelope(msg); err != nil {
+		t.Fatalf("AttachLegacyEnvelope(%q): %v", subject, err)
+	}
+	if _, err := msg.Envelope(); err != nil {
+		t.Fatalf("%q: no envelope attached: %v", subject, err)
+	}
+	if !strings.HasPrefix(msg.Body, body) {
+		t.Errorf("%q: legacy body not kept: %q", subject, msg.Body)
+	}
+	return msg
+}
+
+func TestAttachLegacyEnvelope_LeavesOtherMailAlone(t *testing.T) {
+	msg := mail.NewMessage("mayor/", "gastown/witness", "status?", "how is it going")
+	if err := AttachLegacyEnvelope(msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.Body != "how is it going" {
+		t.Errorf("body = %q, want unchanged", msg.Body)
+	}
+}
+
+func TestNewPolecatDoneMessage_ConvoyFields(t *testing.T) {
+	msg, err := NewPolecatDoneMessage("gastown/nux", "gastown/witness", &PolecatDonePayload{
+		PolecatName: "nux", Exit: "COMPLETED", Branch: "polecat/nux",
+		ConvoyID: "hq-cv-1", ConvoyOwned: true, MergeStrategy: "direct", Errors: "push: timeout",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Old readers see the same lines gt done always wrote.
+	legacy, err := ParsePolecatDone(msg.Subject, msg.Body[:strings.Index(msg.Body, mail.EnvelopeField)])
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := ParsePolecatDone(msg.Subject, msg.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, got := range []*PolecatDonePayload{legacy, p} {
+		if got.ConvoyID != "hq-cv-1" || !got.ConvoyOwned || got.MergeStrategy != "direct" || got.Errors != "push: timeout" {
+			t.Errorf("payload = %+v", got)
+		}
+	}
+}
+
+func TestParsePolecatDone_EnvelopeBeatsReformattedBody(t *testing.T) {
+	msg, err := NewPolecatDoneMessage("gastown/nux", "gastown/witness", &PolecatDonePayload{
+		PolecatName: "nux", Exit: "COMPLETED", MRID: "gt-mr1", Branch: "polecat/nux",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := msg.Body[strings.Index(msg.Body, mail.EnvelopeField):]
+	body := "**Exit** - completed!\nmr = gt-mr1\n\n" + env
+
+	p, err := ParsePolecatDone(msg.Subject, body)
+	if err != nil {
+		t.Fatalf("ParsePolecatDone: %v", err)
+	}
+	if p.Exit != "COMPLETED" || p.MRID != "gt-mr1" {
+		t.Errorf("payload = %+v", p)
+	}
+}
+
+func TestParse_BrokenEnvelopeIsAnError(t *testing.T) {
+	body := "Exit: COMPLETED\n" + mail.EnvelopeField + `: {"v":1,"type":"POLECAT_DONE","body":{"polecat":`
+	if _, err := ParsePolecatDone("POLECAT_DONE nux", body); err == nil {
+		t.Error("ParsePolecatDone succeeded on a truncated envelope")
+	}
+	msg := &mail.Message{Subject: "POLECAT_DONE nux", Body: body}
+	if got := ClassifyMail(msg); got != ProtoPolecatDone {
+		t.Errorf("ClassifyMail = %s, want subject fallback to polecat_done", got)
+	}
+}
+
+func TestParseLifecycleShutdown_Legacy(t *testing.T) {
+	p, err := ParseLifecycleShutdown("LIFECYCLE:Shutdown nux", "Reason: work_reassigned\nRequestedBy: mayor/\nBead: gt-abc\nNewAssignee: gastown/polecats/furiosa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.PolecatName != "nux" || p.RequestedBy != "mayor/" || p.NewAssignee != "gastown/polecats/furiosa" {
+		t.Errorf("payload = %+v", p)
+	}
+	if _, err := ParseLifecycleShutdown("LIFECYCLE:Restart nux", ""); err == nil {
+		t.Error("expected error for non-shutdown subject")
+	}
+}
@@ -1,7 +1,6 @@
 package witness
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -10,11 +9,13 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/connection"
+	"github.com/steveyegge/gastown/internal/costs"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/util"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -27,6 +28,12 @@ import (
 // tmux output (tool calls, status updates). 30 minutes of silence is abnormal.
 const HungSessionThresholdMinutes = 30
 
+// newBeadsStore returns the beads storage the handlers use for workDir.
+// Tests replace it to run the handlers against a *beads.MemoryStore.
+var newBeadsStore = func(workDir string) beads.Store {
+	return beads.New(workDir)
+}
+
 // initRegistryFromWorkDir initializes the session prefix registry from a work
 // directory. This ensures session.PrefixFor(rigName) returns the correct rig
 // prefix (e.g., "tr" for testrig) instead of the default "gt".
@@ -36,6 +43,15 @@ func initRegistryFromWorkDir(workDir string) {
 	}
 }
 
+// newSessionBackend returns the session backend of the town containing workDir.
+func newSessionBackend(workDir string) session.Backend {
+	townRoot, err := workspace.Find(workDir)
+	if err != nil || townRoot == "" {
+		townRoot = workDir
+	}
+	return session.NewBackend(townRoot)
+}
+
 // HandlerResult tracks the result of handling a protocol message.
 type HandlerResult struct {
 	MessageID    string
@@ -170,7 +186,7 @@ func isStalePolecatDone(workDir, rigName, polecatName string, msg *mail.Message)
 
 	initRegistryFromWorkDir(workDir)
 	sessionName := session.PolecatSessionName(session.PrefixFor(rigName), polecatName)
-	createdAt, err := session.SessionCreatedAt(sessionName)
+	createdAt, err := session.SessionCreatedAt(newSessionBackend(workDir), sessionName)
 	if err != nil {
 		// Session not found or tmux not running - can't determine staleness, allow message
 		return false, ""
@@ -188,13 +204,12 @@ func HandleLifecycleShutdown(workDir, rigName string, msg *mail.Message) *Handle
 		ProtocolType: ProtoLifecycleShutdown,
 	}
 
-	// Extract polecat name from subject
-	matches := PatternLifecycleShutdown.FindStringSubmatch(msg.Subject)
-	if len(matches) < 2 {
-		result.Error = fmt.Errorf("invalid LIFECYCLE:Shutdown subject: %s", msg.Subject)
+	payload, err := ParseLifecycleShutdown(msg.Subject, msg.Body)
+	if err != nil {
+		result.Error = fmt.Errorf("parsing LIFECYCLE:Shutdown: %w", err)
 		return result
 	}
-	polecatName := matches[1]
+	polecatName := payload.PolecatName
 
 	// Shutdown means no pending work - try to auto-nuke immediately
 	nukeResult := AutoNukeIfClean(workDir, rigName, polecatName)
@@ -435,33 +450,17 @@ func createCleanupWisp(workDir, polecatName, issueID, branch string) (string, er
 		description += fmt.Sprintf("\nBranch: %s", branch)
 	}
 
-	labels := strings.Join(CleanupWispLabels(polecatName, "pending"), ",")
-
-	output, err := util.ExecWithOutput(workDir, "bd", "create",
-		"--ephemeral",
-		"--json",
-		"--title", title,
-		"--description", description,
-		"--labels", labels,
-	)
+	created, err := newBeadsStore(workDir).Create(beads.CreateOptions{
+		Title:       title,
+		Description: description,
+		Priority:    -1,
+		Ephemeral:   true,
+		Labels:      CleanupWispLabels(polecatName, "pending"),
+	})
 	if err != nil {
 		return "", err
 	}
-
-	// Parse JSON output from bd create --json
-	var created struct {
-		ID string `json:"id"`
-	}
-	if err := json.Unmarshal([]byte(output), &created); err == nil && created.ID != "" {
-		return created.ID, nil
-	}
-
-	// Fallback: extract from "Created: <id>" format
-	if strings.HasPrefix(output, "Created:") {
-		return strings.TrimSpace(strings.TrimPrefix(output, "Created:")), nil
-	}
-
-	return "", fmt.Errorf("could not parse bead ID from bd create output: %q", output)
+	return created.ID, nil
 }
 
 // createSwarmWisp creates a wisp to track swarm (batch) work.
@@ -469,32 +468,26 @@ func createSwarmWisp(workDir string, payload *SwarmStartPayload) (string, error)
 	title := fmt.Sprintf("swarm:%s", payload.SwarmID)
 	description := fmt.Sprintf("Tracking batch: %s\nTotal: %d polecats", payload.SwarmID, payload.Total)
 
-	labels := strings.Join(SwarmWispLabels(payload.SwarmID, payload.Total, 0, payload.StartedAt), ",")
-
-	output, err := util.ExecWithOutput(workDir, "bd", "create",
-		"--ephemeral",
-		"--title", title,
-		"--description", description,
-		"--labels", labels,
-	)
+	created, err := newBeadsStore(workDir).Create(beads.CreateOptions{
+		Title:       title,
+		Description: description,
+		Priority:    -1,
+		Ephemeral:   true,
+		Labels:      SwarmWispLabels(payload.SwarmID, payload.Total, 0, payload.StartedAt),
+	})
 	if err != nil {
 		return "", err
 	}
-
-	if strings.HasPrefix(output, "Created:") {
-		return strings.TrimSpace(strings.TrimPrefix(output, "Created:")), nil
-	}
-
-	return output, nil
+	return created.ID, nil
 }
 
 // findCleanupWisp finds an existing cleanup wisp for a polecat.
 func findCleanupWisp(workDir, polecatName string) (string, error) {
-	output, err := util.ExecWithOutput(workDir, "bd", "list",
-		"--label", fmt.Sprintf("polecat:%s,state:merge-requested", polecatName),
-		"--status", "open",
-		"--json",
-	)
+	items, err := newBeadsStore(workDir).List(beads.ListOptions{
+		Label:    fmt.Sprintf("polecat:%s,state:merge-requested", polecatName),
+		Status:   "open",
+		Priority: -1,
+	})
 	if err != nil {
 		// Empty result is fine
 		if strings.Contains(err.Error(), "no issues found") {
@@ -502,29 +495,12 @@ func findCleanupWisp(workDir, polecatName string) (string, error) {
 		}
 		return "", err
 	}
-
-	// Parse JSON to get the wisp ID
-	if output == "" || output == "[]" || output == "null" {
-		return "", nil
-	}
-
-	var items []struct {
-		ID string `json:"id"`
-	}
-	if err := json.Unmarshal([]byte(output), &items); err != nil {
-		return "", fmt.Errorf("parsing cleanup wisp response: %w", err)
-	}
 	if len(items) > 0 {
 		return items[0].ID, nil
 	}
 	return "", nil
 }
 
-// agentBeadResponse is used to parse the bd show --json response for agent beads.
-type agentBeadResponse struct {
-	Description string `json:"description"`
-}
-
 // getCleanupStatus retrieves the cleanup_status from a polecat's agent bead.
 // Returns the status string: "clean", "has_uncommitted", "has_stash", "has_unpushed"
 // Returns empty string if agent bead doesn't exist or has no cleanup_status.
@@ -542,25 +518,15 @@ func getCleanupStatus(workDir, rigName, polecatName string) string {
 	prefix := beads.GetPrefixForRig(townRoot, rigName)
 	agentBeadID := beads.PolecatBeadIDWithPrefix(prefix, rigName, polecatName)
 
-	output, err := util.ExecWithOutput(workDir, "bd", "show", agentBeadID, "--json")
+	issue, err := newBeadsStore(workDir).Show(agentBeadID)
 	if err != nil {
 		// Agent bead doesn't exist or bd failed - return empty (unknown status)
 		return ""
 	}
 
-	if output == "" {
-		return ""
-	}
-
-	// Parse the JSON response — bd show --json returns an array
-	var issues []agentBeadResponse
-	if err := json.Unmarshal([]byte(output), &issues); err != nil || len(issues) == 0 {
-		return ""
-	}
-
 	// Parse cleanup_status from description
 	// Description format has "cleanup_status: <value>" line
-	for _, line := range strings.Split(issues[0].Description, "\n") {
+	for _, line := range strings.Split(issue.Description, "\n") {
 		line = strings.TrimSpace(line)
 		lower := strings.ToLower(line)
 		if strings.HasPrefix(lower, "cleanup_status:") {
@@ -578,23 +544,21 @@ func getCleanupStatus(workDir, rigName, polecatName string) string {
 // sendMergeReady sends a MERGE_READY notification to the Refinery.
 // This signals that a polecat's work is ready for merge queue processing.
 func sendMergeReady(router *mail.Router, rigName string, payload *PolecatDonePayload) (string, error) {
-	msg := mail.NewMessage(
+	msg, err := NewMergeReadyMessage(
 		fmt.Sprintf("%s/witness", rigName),
 		fmt.Sprintf("%s/refinery", rigName),
-		fmt.Sprintf("MERGE_READY %s", payload.PolecatName),
-		fmt.Sprintf(`Branch: %s
-Issue: %s
-MR: %s
-Polecat: %s
-Verified: clean git state`,
-			payload.Branch,
-			payload.IssueID,
-			payload.MRID,
-			payload.PolecatName,
-		),
+		&MergeReadyPayload{
+			PolecatName: payload.PolecatName,
+			Rig:         rigName,
+			Branch:      payload.Branch,
+			IssueID:     payload.IssueID,
+			MRID:        payload.MRID,
+			ReadyAt:     time.Now(),
+		},
 	)
-	msg.Priority = mail.PriorityHigh
-	msg.Type = mail.TypeTask
+	if err != nil {
+		return "", err
+	}
 
 	if err := router.Send(msg); err != nil {
 		return "", err
@@ -613,7 +577,7 @@ func nudgeRefinery(townRoot, rigName string) error {
 	sessionName := session.RefinerySessionName(session.PrefixFor(rigName))
 
 	// Check if refinery is running
-	t := tmux.NewTmux()
+	t := session.NewBackend(townRoot)
 	running, err := t.HasSession(sessionName)
 	if err != nil {
 		return fmt.Errorf("checking refinery session: %w", err)
@@ -717,39 +681,29 @@ DO NOT nuke without --force after recovery.`,
 
 // UpdateCleanupWispState updates a cleanup wisp's state label.
 func UpdateCleanupWispState(workDir, wispID, newState string) error {
+	store := newBeadsStore(workDir)
+
 	// Get current labels to preserve other labels
-	output, err := util.ExecWithOutput(workDir, "bd", "show", wispID, "--json")
+	wisp, err := store.Show(wispID)
 	if err != nil {
 		return fmt.Errorf("getting wisp: %w", err)
 	}
 
-	// Extract polecat name from existing labels via JSON parsing
-	polecatName := extractPolecatFromJSON(output)
-
+	polecatName := polecatFromLabels(wisp.Labels)
 	if polecatName == "" {
 		polecatName = "unknown"
 	}
 
-	// Update with new state — pass one --set-labels=<label> per label,
-	// matching the pattern used in agent_state.go and molecule_await_signal.go.
-	labels := CleanupWispLabels(polecatName, newState)
-	args := []string{"update", wispID}
-	for _, l := range labels {
-		args = append(args, "--set-labels="+l)
-	}
-	return util.ExecRun(workDir, "bd", args...)
+	// Replace the labels with the new state (bd update --set-labels).
+	return store.Update(wispID, beads.UpdateOptions{
+		SetLabels: CleanupWispLabels(polecatName, newState),
+	})
 }
 
-// extractPolecatFromJSON extracts the polecat name from bd show --json output.
-// Returns empty string if the output is malformed or no polecat label is found.
-func extractPolecatFromJSON(output string) string {
-	var items []struct {
-		Labels []string `json:"labels"`
-	}
-	if err := json.Unmarshal([]byte(output), &items); err != nil || len(items) == 0 {
-		return ""
-	}
-	for _, label := range items[0].Labels {
+// polecatFromLabels returns the polecat name from a polecat:<name> label,
+// or empty string if there is none.
+func polecatFromLabels(labels []string) string {
+	for _, label := range labels {
 		if name, ok := strings.CutPrefix(label, "polecat:"); ok {
 			return name
 		}
@@ -767,7 +721,7 @@ func NukePolecat(workDir, rigName, polecatName string) error {
 	// See: gt-g9ft5 - sessions were piling up because nuke wasn't killing them.
 	initRegistryFromWorkDir(workDir)
 	sessionName := session.PolecatSessionName(session.PrefixFor(rigName), polecatName)
-	t := tmux.NewTmux()
+	t := newSessionBackend(workDir)
 
 	// Check if session exists and kill it
 	if running, _ := t.HasSession(sessionName); running {
@@ -980,7 +934,7 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 		return result
 	}
 
-	t := tmux.NewTmux()
+	t := session.NewBackend(townRoot)
 
 	for _, entry := range entries {
 		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
@@ -993,6 +947,28 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 
 		detectedAt := time.Now()
 
+		prefix := beads.GetPrefixForRig(townRoot, rigName)
+		agentBeadID := beads.PolecatBeadIDWithPrefix(prefix, rigName, polecatName)
+
+		// Polecats placed on another machine are checked over their connection.
+		// The local tmux server knows nothing about them, so falling through to
+		// the local checks would misreport every remote polecat as dead.
+		conn, placement, err := polecat.RemotePolecatConnection(townRoot, rigName, polecatName)
+		if err != nil {
+			result.Errors = append(result.Errors,
+				fmt.Errorf("loading placement for %s: %w", polecatName, err))
+			continue
+		}
+		if placement != nil {
+			zombie, found, err := detectRemoteZombie(workDir, rigName, polecatName, agentBeadID, sessionName, conn, router)
+			if err != nil {
+				result.Errors = append(result.Errors, err)
+			} else if found {
+				result.Zombies = append(result.Zombies, zombie)
+			}
+			continue
+		}
+
 		sessionAlive, err := t.HasSession(sessionName)
 		if err != nil {
 			result.Errors = append(result.Errors,
@@ -1000,8 +976,6 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 			continue
 		}
 
-		prefix := beads.GetPrefixForRig(townRoot, rigName)
-		agentBeadID := beads.PolecatBeadIDWithPrefix(prefix, rigName, polecatName)
 		labels := getAgentBeadLabels(workDir, agentBeadID)
 		doneIntent := extractDoneIntent(labels)
 
@@ -1086,7 +1060,7 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 
 // detectZombieLiveSession checks a polecat with a live tmux session for zombie indicators:
 // stuck done-intent, dead agent process, or closed bead while still running.
-func detectZombieLiveSession(workDir, rigName, polecatName, agentBeadID, sessionName string, t *tmux.Tmux, doneIntent *DoneIntent, router *mail.Router) (ZombieResult, bool) {
+func detectZombieLiveSession(workDir, rigName, polecatName, agentBeadID, sessionName string, t session.Backend, doneIntent *DoneIntent, router *mail.Router) (ZombieResult, bool) {
 	// Check for done-intent stuck too long (polecat hung in gt done).
 	if doneIntent != nil && time.Since(doneIntent.Timestamp) > 60*time.Second {
 		_, stuckHookBead := getAgentBeadState(workDir, agentBeadID)
@@ -1142,7 +1116,13 @@ func detectZombieLiveSession(workDir, rigName, polecatName, agentBeadID, session
 
 // detectZombieDeadSession checks a polecat with a dead tmux session for zombie indicators:
 // stale done-intent, or active agent state / hooked bead with no session.
-func detectZombieDeadSession(workDir, rigName, polecatName, agentBeadID, sessionName string, t *tmux.Tmux, doneIntent *DoneIntent, detectedAt time.Time, router *mail.Router) (ZombieResult, bool) {
+func detectZombieDeadSession(workDir, rigName, polecatName, agentBeadID, sessionName string, t session.Backend, doneIntent *DoneIntent, detectedAt time.Time, router *mail.Router) (ZombieResult, bool) {
+	// A budget pause stops polecat sessions on purpose and keeps their
+	// worktree and hooked bead for when the pause lifts.
+	if budgetPaused(workDir, rigName, sessionName) {
+		return ZombieResult{}, false
+	}
+
 	// Done-intent: polecat was trying to exit.
 	if doneIntent != nil {
 		age := time.Since(doneIntent.Timestamp)
@@ -1187,6 +1167,88 @@ func detectZombieDeadSession(workDir, rigName, polecatName, agentBeadID, session
 	return zombie, true
 }
 
+// budgetPaused reports whether a budget breach has paused the polecat
+// session, per the daemon's budget state.
+func budgetPaused(workDir, rigName, sessionName string) bool {
+	townRoot, err := workspace.Find(workDir)
+	if err != nil || townRoot == "" {
+		return false
+	}
+	st, err := costs.LoadBudgetState(townRoot, time.Now())
+	return err == nil && st.PolecatPause(rigName, sessionName) != nil
+}
+
+// detectRemoteZombie checks a polecat whose session runs on another machine.
+// Only checks that can be answered from beads and the remote tmux server are
+// applied; agent-liveness and hung-session checks need local pane access.
+// An unreachable machine is reported as an error and never treated as a dead
+// session, so a network blip cannot get a working polecat nuked.
+func detectRemoteZombie(workDir, rigName, polecatName, agentBeadID, sessionName string, conn connection.Connection, router *mail.Router) (ZombieResult, bool, error) {
+	sessionAlive, err := conn.TmuxHasSession(sessionName)
+	if err != nil {
+		return ZombieResult{}, false, fmt.Errorf("checking session %s on %s: %w", sessionName, conn.Name(), err)
+	}
+
+	doneIntent := extractDoneIntent(getAgentBeadLabels(workDir, agentBeadID))
+
+	if sessionAlive {
+		_, hookBead := getAgentBeadState(workDir, agentBeadID)
+		zombie := ZombieResult{PolecatName: polecatName, HookBead: hookBead}
+		switch {
+		case doneIntent != nil && time.Since(doneIntent.Timestamp) > 60*time.Second:
+			zombie.AgentState = "stuck-in-done"
+			zombie.Action = fmt.Sprintf("killed-stuck-session (done-intent age=%v)", time.Since(doneIntent.Timestamp).Round(time.Second))
+		case hookBead != "" && getBeadStatus(workDir, hookBead) == "closed":
+			zombie.AgentState = "bead-closed-still-running"
+			zombie.Action = "nuke-bead-closed-polecat"
+		default:
+			return ZombieResult{}, false, nil
+		}
+		if err := NukePolecat(workDir, rigName, polecatName); err != nil {
+			zombie.Error = err
+			zombie.Action = fmt.Sprintf("nuke-failed (%s): %v", zombie.AgentState, err)
+		}
+		return zombie, true, nil
+	}
+
+	if doneIntent != nil && time.Since(doneIntent.Timestamp) < 30*time.Second {
+		return ZombieResult{}, false, nil // Recent — still working through gt done
+	}
+
+	agentState, hookBead := getAgentBeadState(workDir, agentBeadID)
+	if doneIntent == nil && !isZombieState(agentState, hookBead) {
+		return ZombieResult{}, false, nil
+	}
+
+	// TOCTOU guard: re-check the remote session before acting. Any failure to
+	// reach the machine aborts rather than assuming the session is gone.
+	if alive, err := conn.TmuxHasSession(sessionName); err != nil || alive {
+		if err != nil {
+			return ZombieResult{}, false, fmt.Errorf("re-checking session %s on %s: %w", sessionName, conn.Name(), err)
+		}
+		return ZombieResult{}, false, nil
+	}
+
+	zombie := ZombieResult{
+		PolecatName: polecatName,
+		AgentState:  agentState,
+		HookBead:    hookBead,
+	}
+	if doneIntent != nil {
+		zombie.AgentState = "done-intent-dead"
+		zombie.Action = fmt.Sprintf("auto-nuked (done-intent age=%v, type=%s)", time.Since(doneIntent.Timestamp).Round(time.Second), doneIntent.ExitType)
+		if err := NukePolecat(workDir, rigName, polecatName); err != nil {
+			zombie.Error = err
+			zombie.Action = fmt.Sprintf("nuke-failed (done-intent): %v", err)
+		}
+	} else {
+		cleanupStatus := getCleanupStatus(workDir, rigName, polecatName)
+		handleZombieCleanup(workDir, rigName, polecatName, hookBead, cleanupStatus, router, &zombie)
+	}
+	zombie.BeadRecovered = resetAbandonedBead(workDir, rigName, hookBead, polecatName, router)
+	return zombie, true, nil
+}
+
 // isZombieState returns true if the agent state or hook bead indicates a zombie.
 func isZombieState(agentState, hookBead string) bool {
 	if hookBead != "" {
@@ -1288,7 +1350,7 @@ func DetectStalledPolecats(workDir, rigName string) *DetectStalledPolecatsResult
 		return result // No polecats directory
 	}
 
-	t := tmux.NewTmux()
+	t := session.NewBackend(townRoot)
 
 	for _, entry := range entries {
 		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
@@ -1343,21 +1405,11 @@ func DetectStalledPolecats(workDir, rigName string) *DetectStalledPolecatsResult
 // getAgentBeadState reads agent_state and hook_bead from an agent bead.
 // Returns the agent_state string and hook_bead ID.
 func getAgentBeadState(workDir, agentBeadID string) (agentState, hookBead string) {
-	output, err := util.ExecWithOutput(workDir, "bd", "show", agentBeadID, "--json")
-	if err != nil || output == "" {
-		return "", ""
-	}
-
-	// Parse JSON response — bd show --json returns an array
-	var issues []struct {
-		AgentState string `json:"agent_state"`
-		HookBead   string `json:"hook_bead"`
-	}
-	if err := json.Unmarshal([]byte(output), &issues); err != nil || len(issues) == 0 {
+	issue, err := newBeadsStore(workDir).Show(agentBeadID)
+	if err != nil {
 		return "", ""
 	}
-
-	return issues[0].AgentState, issues[0].HookBead
+	return issue.AgentState, issue.HookBead
 }
 
 // getBeadStatus returns the status of a bead (e.g., "open", "closed", "hooked").
@@ -1366,17 +1418,11 @@ func getBeadStatus(workDir, beadID string) string {
 	if beadID == "" {
 		return ""
 	}
-	output, err := util.ExecWithOutput(workDir, "bd", "show", beadID, "--json")
-	if err != nil || output == "" {
-		return ""
-	}
-	var issues []struct {
-		Status string `json:"status"`
-	}
-	if err := json.Unmarshal([]byte(output), &issues); err != nil || len(issues) == 0 {
+	issue, err := newBeadsStore(workDir).Show(beadID)
+	if err != nil {
 		return ""
 	}
-	return issues[0].Status
+	return issue.Status
 }
 
 // resetAbandonedBead resets a dead polecat's hooked bead so it can be re-dispatched.
@@ -1395,7 +1441,8 @@ func resetAbandonedBead(workDir, rigName, hookBead, polecatName string, router *
 	}
 
 	// Reset bead status to open and clear assignee
-	if err := util.ExecRun(workDir, "bd", "update", hookBead, "--status=open", "--assignee="); err != nil {
+	openStatus, noAssignee := "open", ""
+	if err := newBeadsStore(workDir).Update(hookBead, beads.UpdateOptions{Status: &openStatus, Assignee: &noAssignee}); err != nil {
 		return false
 	}
 
@@ -1454,31 +1501,18 @@ func DetectOrphanedBeads(workDir, rigName string, router *mail.Router) *DetectOr
 
 	// Scan both in_progress and hooked beads — resetAbandonedBead handles both
 	// states, and orphaned beads can be stuck in either.
-	var beadList []struct {
-		ID       string `json:"id"`
-		Assignee string `json:"assignee"`
-	}
+	store := newBeadsStore(workDir)
+	var beadList []*beads.Issue
 	for _, status := range []string{"in_progress", "hooked"} {
-		output, err := util.ExecWithOutput(workDir, "bd", "list", "--status="+status, "--json", "--limit=0")
+		batch, err := store.List(beads.ListOptions{Status: status, Priority: -1})
 		if err != nil {
 			result.Errors = append(result.Errors, fmt.Errorf("listing %s beads: %w", status, err))
 			continue
 		}
-		if output == "" {
-			continue
-		}
-		var batch []struct {
-			ID       string `json:"id"`
-			Assignee string `json:"assignee"`
-		}
-		if err := json.Unmarshal([]byte(output), &batch); err != nil {
-			result.Errors = append(result.Errors, fmt.Errorf("parsing %s beads: %w", status, err))
-			continue
-		}
 		beadList = append(beadList, batch...)
 	}
 
-	t := tmux.NewTmux()
+	t := session.NewBackend(townRoot)
 
 	for _, bead := range beadList {
 		if bead.Assignee == "" {
@@ -1592,25 +1626,14 @@ func DetectOrphanedMolecules(workDir, rigName string, router *mail.Router) *Dete
 
 	// Step 1: List beads that could have attached molecules.
 	// Slung beads start as status=hooked; polecats may change them to in_progress.
-	type beadSummary struct {
-		ID       string `json:"id"`
-		Assignee string `json:"assignee"`
-	}
-	var allBeads []beadSummary
+	store := newBeadsStore(workDir)
+	var allBeads []*beads.Issue
 	for _, status := range []string{"hooked", "in_progress"} {
-		output, err := util.ExecWithOutput(workDir, "bd", "list", "--status="+status, "--json", "--limit=0")
+		items, err := store.List(beads.ListOptions{Status: status, Priority: -1})
 		if err != nil {
 			result.Errors = append(result.Errors, fmt.Errorf("listing %s beads: %w", status, err))
 			continue
 		}
-		if output == "" {
-			continue
-		}
-		var items []beadSummary
-		if err := json.Unmarshal([]byte(output), &items); err != nil {
-			result.Errors = append(result.Errors, fmt.Errorf("parsing %s beads: %w", status, err))
-			continue
-		}
 		allBeads = append(allBeads, items...)
 	}
 
@@ -1620,7 +1643,7 @@ func DetectOrphanedMolecules(workDir, rigName string, router *mail.Router) *Dete
 
 	// Step 2: Check each polecat-assigned bead
 	polecatPrefix := rigName + "/polecats/"
-	t := tmux.NewTmux()
+	t := session.NewBackend(townRoot)
 	polecatsDir := filepath.Join(townRoot, rigName, "polecats")
 
 	for _, b := range allBeads {
@@ -1705,19 +1728,12 @@ func DetectOrphanedMolecules(workDir, rigName string, router *mail.Router) *Dete
 
 // getAttachedMoleculeID reads a bead and returns its attached_molecule ID, if any.
 func getAttachedMoleculeID(workDir, beadID string) string {
-	output, err := util.ExecWithOutput(workDir, "bd", "show", beadID, "--json")
-	if err != nil || output == "" {
-		return ""
-	}
-
-	var issues []struct {
-		Description string `json:"description"`
-	}
-	if err := json.Unmarshal([]byte(output), &issues); err != nil || len(issues) == 0 {
+	issue, err := newBeadsStore(workDir).Show(beadID)
+	if err != nil {
 		return ""
 	}
 
-	fields := beads.ParseAttachmentFields(&beads.Issue{Description: issues[0].Description})
+	fields := beads.ParseAttachmentFields(issue)
 	if fields == nil {
 		return ""
 	}
@@ -1725,14 +1741,16 @@ func getAttachedMoleculeID(workDir, beadID string) string {
 }
 
 // closeMoleculeWithDescendants closes a molecule and all its descendant step
-// issues using the bd CLI. Returns the total number of issues closed.
+// issues. Returns the total number of issues closed.
 func closeMoleculeWithDescendants(workDir, moleculeID string) (int, error) {
+	store := newBeadsStore(workDir)
+
 	// Recursively close descendants first (bottom-up)
-	closed, descErr := closeDescendantsViaCLI(workDir, moleculeID)
+	closed, descErr := closeDescendants(store, moleculeID)
 
 	// Close the molecule itself
 	reason := "Orphaned mol-polecat-work — owning polecat no longer exists (issue #1381)"
-	if err := util.ExecRun(workDir, "bd", "close", moleculeID, "-r", reason); err != nil {
+	if err := store.CloseWithReason(reason, moleculeID); err != nil {
 		closeErr := fmt.Errorf("closing molecule %s: %w", moleculeID, err)
 		if descErr != nil {
 			return closed, fmt.Errorf("%w; also: %v", closeErr, descErr)
@@ -1744,25 +1762,14 @@ func closeMoleculeWithDescendants(workDir, moleculeID string) (int, error) {
 	return closed, descErr
 }
 
-// closeDescendantsViaCLI recursively closes descendant issues of a parent
-// using bd CLI commands. Returns count of issues closed and any error.
-func closeDescendantsViaCLI(workDir, parentID string) (int, error) {
+// closeDescendants recursively closes descendant issues of a parent.
+// Returns count of issues closed and any error.
+func closeDescendants(store beads.Store, parentID string) (int, error) {
 	// List children of this parent
-	output, err := util.ExecWithOutput(workDir, "bd", "list", "--parent="+parentID, "--json")
+	children, err := store.List(beads.ListOptions{Parent: parentID, Priority: -1})
 	if err != nil {
 		return 0, fmt.Errorf("listing children of %s: %w", parentID, err)
 	}
-	if output == "" {
-		return 0, nil
-	}
-
-	var children []struct {
-		ID     string `json:"id"`
-		Status string `json:"status"`
-	}
-	if err := json.Unmarshal([]byte(output), &children); err != nil {
-		return 0, fmt.Errorf("parsing children of %s: %w", parentID, err)
-	}
 
 	if len(children) == 0 {
 		return 0, nil
@@ -1772,7 +1779,7 @@ func closeDescendantsViaCLI(workDir, parentID string) (int, error) {
 	totalClosed := 0
 	var errs []error
 	for _, child := range children {
-		n, err := closeDescendantsViaCLI(workDir, child.ID)
+		n, err := closeDescendants(store, child.ID)
 		totalClosed += n
 		if err != nil {
 			errs = append(errs, err)
@@ -1789,9 +1796,7 @@ func closeDescendantsViaCLI(workDir, parentID string) (int, error) {
 
 	if len(idsToClose) > 0 {
 		reason := "Orphaned mol-polecat-work step — owning polecat no longer exists"
-		args := append([]string{"close"}, idsToClose...)
-		args = append(args, "-r", reason)
-		if err := util.ExecRun(workDir, "bd", args...); err != nil {
+		if err := store.CloseWithReason(reason, idsToClose...); err != nil {
 			errs = append(errs, fmt.Errorf("closing children of %s: %w", parentID, err))
 		} else {
 			totalClosed += len(idsToClose)
@@ -1836,31 +1841,23 @@ func extractDoneIntent(labels []string) *DoneIntent {
 
 // getAgentBeadLabels reads the labels from an agent bead.
 func getAgentBeadLabels(workDir, agentBeadID string) []string {
-	output, err := util.ExecWithOutput(workDir, "bd", "show", agentBeadID, "--json")
-	if err != nil || output == "" {
-		return nil
-	}
-
-	var issues []struct {
-		Labels []string `json:"labels"`
-	}
-	if err := json.Unmarshal([]byte(output), &issues); err != nil || len(issues) == 0 {
+	issue, err := newBeadsStore(workDir).Show(agentBeadID)
+	if err != nil {
 		return nil
 	}
-
-	return issues[0].Labels
+	return issue.Labels
 }
 
 // sessionRecreated checks whether a tmux session was (re)created after the
 // given timestamp. Returns true if the session exists and was created after
 // detectedAt, indicating a new session replaced the dead one (TOCTOU guard).
-func sessionRecreated(t *tmux.Tmux, sessionName string, detectedAt time.Time) bool {
+func sessionRecreated(t session.Backend, sessionName string, detectedAt time.Time) bool {
 	alive, err := t.HasSession(sessionName)
 	if err != nil || !alive {
 		return false // Still dead — not recreated
 	}
 	// Session exists now. Check if it was created after our detection.
-	createdAt, err := session.SessionCreatedAt(sessionName)
+	createdAt, err := session.SessionCreatedAt(t, sessionName)
 	if err != nil {
 		// Can't determine creation time — assume recreated to be safe.
 		// Better to skip a real zombie than kill a live session.
@@ -1873,21 +1870,12 @@ func sessionRecreated(t *tmux.Tmux, sessionName string, detectedAt time.Time) bo
 // regardless of state. Used to prevent duplicate escalation on repeated patrol
 // cycles for the same zombie.
 func findAnyCleanupWisp(workDir, polecatName string) string {
-	output, err := util.ExecWithOutput(workDir, "bd", "list",
-		"--label", fmt.Sprintf("cleanup,polecat:%s", polecatName),
-		"--status", "open",
-		"--json",
-	)
-	if err != nil {
-		return ""
-	}
-	if output == "" || output == "[]" || output == "null" {
-		return ""
-	}
-	var items []struct {
-		ID string `json:"id"`
-	}
-	if err := json.Unmarshal([]byte(output), &items); err != nil || len(items) == 0 {
+	items, err := newBeadsStore(workDir).List(beads.ListOptions{
+		Label:    fmt.Sprintf("cleanup,polecat:%s", polecatName),
+		Status:   "open",
+		Priority: -1,
+	})
+	if err != nil || len(items) == 0 {
 		return ""
 	}
 	return items[0].ID
@@ -9,6 +9,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/costs"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
@@ -247,7 +250,7 @@ func installFakeBd(t *testing.T) string {
 
 	if runtime.GOOS == "windows" {
 		// Windows: create a .bat file since shell scripts don't work
-		script := fmt.Sprintf("@echo off\r\necho %%* >> %q\r\nif \"%%1\"==\"list\" (\r\n  echo []\r\n) else if \"%%1\"==\"update\" (\r\n  exit /b 0\r\n) else if \"%%1\"==\"show\" (\r\n  echo [{\"labels\":[\"cleanup\",\"polecat:testpol\",\"state:pending\"]}]\r\n) else (\r\n  echo {}\r\n)\r\n", argsLog)
+		script := fmt.Sprintf("@echo off\r\necho %%* >> %q\r\nif \"%%1\"==\"--allow-stale\" shift\r\nif \"%%1\"==\"list\" (\r\n  echo []\r\n) else if \"%%1\"==\"update\" (\r\n  exit /b 0\r\n) else if \"%%1\"==\"show\" (\r\n  echo [{\"labels\":[\"cleanup\",\"polecat:testpol\",\"state:pending\"]}]\r\n) else (\r\n  echo {}\r\n)\r\n", argsLog)
 		bdPath := filepath.Join(binDir, "bd.bat")
 		if err := os.WriteFile(bdPath, []byte(script), 0o755); err != nil {
 			t.Fatalf("write fake bd.bat: %v", err)
@@ -256,6 +259,7 @@ func installFakeBd(t *testing.T) string {
 		// Unix: create a shell script
 		script := fmt.Sprintf(`#!/bin/sh
 echo "$@" >> %q
+[ "$1" = "--allow-stale" ] && shift
 case "$1" in
   list) echo "[]" ;;
   update) exit 0 ;;
@@ -520,36 +524,19 @@ func TestGetAgentBeadLabels_NoBdAvailable(t *testing.T) {
 	}
 }
 
-// --- extractPolecatFromJSON tests (issue #1228: panic-safe JSON parsing) ---
-
-func TestExtractPolecatFromJSON_ValidOutput(t *testing.T) {
-	input := `[{"labels":["cleanup","polecat:nux","state:pending"]}]`
-	got := extractPolecatFromJSON(input)
-	if got != "nux" {
-		t.Errorf("extractPolecatFromJSON() = %q, want %q", got, "nux")
-	}
-}
-
-func TestExtractPolecatFromJSON_InvalidInputs(t *testing.T) {
+func TestPolecatFromLabels(t *testing.T) {
 	tests := []struct {
-		name  string
-		input string
+		labels []string
+		want   string
 	}{
-		{"empty output", ""},
-		{"malformed JSON", "{not valid json"},
-		{"empty array", "[]"},
-		{"no polecat label", `[{"labels":["cleanup","state:pending"]}]`},
-		{"empty labels", `[{"labels":[]}]`},
-		{"truncated JSON", `[{"labels":["polecat:`},
+		{[]string{"cleanup", "polecat:nux", "state:pending"}, "nux"},
+		{[]string{"cleanup", "state:pending"}, ""},
+		{nil, ""},
 	}
-
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := extractPolecatFromJSON(tt.input)
-			if got != "" {
-				t.Errorf("extractPolecatFromJSON(%q) = %q, want empty", tt.input, got)
-			}
-		})
+		if got := polecatFromLabels(tt.labels); got != tt.want {
+			t.Errorf("polecatFromLabels(%v) = %q, want %q", tt.labels, got, tt.want)
+		}
 	}
 }
 
@@ -1133,6 +1120,7 @@ func TestDetectOrphanedMolecules_WithMockBd(t *testing.T) {
 	mockBd := filepath.Join(tmpDir, "bd")
 	mockScript := fmt.Sprintf(`#!/bin/sh
 echo "$@" >> %s
+[ "$1" = "--allow-stale" ] && shift
 case "$1" in
   list)
     case "$*" in
@@ -1249,3 +1237,204 @@ esac
 	}
 }
 
+// useMemoryStore points the handlers at an in-memory beads store.
+func useMemoryStore(t *testing.T) *beads.MemoryStore {
+	t.Helper()
+	store := beads.NewMemoryStore("gt")
+	orig := newBeadsStore
+	newBeadsStore = func(string) beads.Store { return store }
+	t.Cleanup(func() { newBeadsStore = orig })
+	return store
+}
+
+func TestCleanupWispLifecycle_MemoryStore(t *testing.T) {
+	store := useMemoryStore(t)
+
+	wispID, err := createCleanupWisp("", "nux", "gt-abc", "polecat/nux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wisp, err := store.Show(wispID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !wisp.Ephemeral || !beads.HasLabel(wisp, "state:pending") || !strings.Contains(wisp.Description, "Branch: polecat/nux") {
+		t.Errorf("wisp = %+v", wisp)
+	}
+	if got := findAnyCleanupWisp("", "nux"); got != wispID {
+		t.Errorf("findAnyCleanupWisp = %q, want %q", got, wispID)
+	}
+	if got, _ := findCleanupWisp("", "nux"); got != "" {
+		t.Errorf("findCleanupWisp matched a pending wisp: %q", got)
+	}
+
+	if err := UpdateCleanupWispState("", wispID, "merge-requested"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := findCleanupWisp("", "nux"); err != nil || got != wispID {
+		t.Errorf("findCleanupWisp = %q, %v; want %q", got, err, wispID)
+	}
+	wisp, _ = store.Show(wispID)
+	if beads.HasLabel(wisp, "state:pending") || !beads.HasLabel(wisp, "polecat:nux") {
+		t.Errorf("labels after update = %v", wisp.Labels)
+	}
+}
+
+func TestResetAbandonedBead_MemoryStore(t *testing.T) {
+	store := useMemoryStore(t)
+
+	hooked := "hooked"
+	assignee := "testrig/polecats/nux"
+	bead, err := store.Create(beads.CreateOptions{Title: "work", Priority: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Update(bead.ID, beads.UpdateOptions{Status: &hooked, Assignee: &assignee}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !resetAbandonedBead("", "testrig", bead.ID, "nux", nil) {
+		t.Fatal("resetAbandonedBead = false, want true")
+	}
+	got, _ := store.Show(bead.ID)
+	if got.Status != "open" || got.Assignee != "" {
+		t.Errorf("bead after reset: status=%q assignee=%q", got.Status, got.Assignee)
+	}
+	if resetAbandonedBead("", "testrig", bead.ID, "nux", nil) {
+		t.Error("an open bead should not be reset again")
+	}
+}
+
+func TestCloseMoleculeWithDescendants_MemoryStore(t *testing.T) {
+	store := useMemoryStore(t)
+
+	mol, _ := store.Create(beads.CreateOptions{Title: "mol-polecat-work", Priority: -1})
+	step, _ := store.Create(beads.CreateOptions{Title: "step", Parent: mol.ID, Priority: -1})
+	sub, _ := store.Create(beads.CreateOptions{Title: "substep", Parent: step.ID, Priority: -1})
+	done, _ := store.Create(beads.CreateOptions{Title: "done", Parent: mol.ID, Priority: -1})
+	if err := store.Close(done.ID); err != nil {
+		t.Fatal(err)
+	}
+	work, _ := store.Create(beads.CreateOptions{
+		Title:       "work",
+		Description: "attached_molecule: " + mol.ID,
+		Priority:    -1,
+	})
+
+	if got := getAttachedMoleculeID("", work.ID); got != mol.ID {
+		t.Fatalf("getAttachedMoleculeID = %q, want %q", got, mol.ID)
+	}
+	closed, err := closeMoleculeWithDescendants("", mol.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if closed != 3 {
+		t.Errorf("closed = %d, want 3 (substep, step, molecule)", closed)
+	}
+	for _, id := range []string{mol.ID, step.ID, sub.ID} {
+		if getBeadStatus("", id) != "closed" {
+			t.Errorf("%s not closed", id)
+		}
+	}
+}
+
+// installPrefixStrictBd installs a fake bd that, like a real bd with
+// BEADS_DIR pinned, only knows the issues of the database in BEADS_DIR:
+// hq-* in the town database, gt-* in the rig's. Every issue it knows is
+// hooked. Returns the rig directory and the log of accepted commands.
+func installPrefixStrictBd(t *testing.T) (rigDir, argsLog string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake bd is a shell script")
+	}
+	town := t.TempDir()
+	rigDir = filepath.Join(town, "testrig")
+	for _, dir := range []string{filepath.Join(town, "mayor"), filepath.Join(town, ".beads"), filepath.Join(rigDir, ".beads")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(town, "mayor", "town.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	routes := `{"prefix":"hq-","path":"."}` + "\n" + `{"prefix":"gt-","path":"testrig"}` + "\n"
+	if err := os.WriteFile(filepath.Join(town, ".beads", "routes.jsonl"), []byte(routes), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	binDir := t.TempDir()
+	argsLog = filepath.Join(binDir, "bd_args.log")
+	script := fmt.Sprintf(`#!/bin/sh
+[ "$1" = "--allow-stale" ] && shift
+cmd=$1 id=$2
+if [ "$BEADS_DIR" = %q ]; then want=hq-; else want=gt-; fi
+case "$id" in
+  "$want"*) ;;
+  *) echo "Error: no issue found matching \"$id\"" >&2; exit 1 ;;
+esac
+echo "$cmd $id" >> %q
+case "$cmd" in
+  show) echo "[{\"id\":\"$id\",\"status\":\"hooked\"}]" ;;
+  *) echo "{}" ;;
+esac
+`, filepath.Join(town, ".beads"), argsLog)
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return rigDir, argsLog
+}
+
+func TestResetAbandonedBead_RoutesWritesByPrefix(t *testing.T) {
+	rigDir, argsLog := installPrefixStrictBd(t)
+
+	for _, id := range []string{"hq-work-1", "gt-work-2"} {
+		if !resetAbandonedBead(rigDir, "testrig", id, "nux", nil) {
+			t.Errorf("resetAbandonedBead(%s) = false, want true", id)
+		}
+	}
+	data, _ := os.ReadFile(argsLog)
+	for _, want := range []string{"update hq-work-1", "update gt-work-2"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("bd log missing %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestCloseWithReason_RoutesByPrefix(t *testing.T) {
+	rigDir, argsLog := installPrefixStrictBd(t)
+
+	store := newBeadsStore(rigDir)
+	if err := store.CloseWithReason("done", "hq-mol-1", "gt-step-1"); err != nil {
+		t.Fatalf("CloseWithReason across databases: %v", err)
+	}
+	data, _ := os.ReadFile(argsLog)
+	for _, want := range []string{"close hq-mol-1", "close gt-step-1"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("bd log missing %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestDetectZombieDeadSession_SkipsBudgetPause(t *testing.T) {
+	rigDir, argsLog := installPrefixStrictBd(t)
+	town := filepath.Dir(rigDir)
+	sessionName := "gt-testrig-nux"
+	st := &costs.BudgetState{Day: time.Now().Format("2006-01-02"), Breaches: []costs.Breach{
+		{Key: "rig-10:testrig", Scope: config.BudgetScopeRig, Subject: "testrig", Rigs: []string{"testrig"},
+			Actions: []string{config.BudgetActionPausePolecats}},
+	}}
+	if err := costs.SaveBudgetState(town, st); err != nil {
+		t.Fatal(err)
+	}
+
+	// A stale done-intent would otherwise get the polecat nuked and its
+	// bead reset.
+	intent := &DoneIntent{ExitType: "COMPLETED", Timestamp: time.Now().Add(-time.Hour)}
+	if _, found := detectZombieDeadSession(rigDir, "testrig", "nux", "gt-testrig-polecat-nux", sessionName, nil, intent, time.Now(), nil); found {
+		t.Error("a budget-paused polecat was treated as a zombie")
+	}
+	if data, _ := os.ReadFile(argsLog); len(data) > 0 {
+		t.Errorf("paused polecat's beads were touched:\n%s", data)
+	}
+}
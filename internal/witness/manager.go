@@ -45,7 +45,7 @@ func NewManager(r *rig.Rig) *Manager {
 // ZFC: tmux session existence is the source of truth for session state,
 // but agent liveness determines if the session is actually functional.
 func (m *Manager) IsRunning() (bool, error) {
-	t := tmux.NewTmux()
+	t := m.backend()
 	status := t.CheckSessionHealth(m.SessionName(), 0)
 	return status == tmux.SessionHealthy, nil
 }
@@ -56,7 +56,7 @@ func (m *Manager) IsRunning() (bool, error) {
 // Returns the detailed ZombieStatus for callers that need to distinguish
 // between different failure modes.
 func (m *Manager) IsHealthy(maxInactivity time.Duration) tmux.ZombieStatus {
-	t := tmux.NewTmux()
+	t := m.backend()
 	return t.CheckSessionHealth(m.SessionName(), maxInactivity)
 }
 
@@ -68,7 +68,7 @@ func (m *Manager) SessionName() string {
 // Status returns information about the witness session.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Status() (*tmux.SessionInfo, error) {
-	t := tmux.NewTmux()
+	t := m.backend()
 	sessionID := m.SessionName()
 
 	running, err := t.HasSession(sessionID)
@@ -105,7 +105,7 @@ func (m *Manager) witnessDir() string {
 // envOverrides are KEY=VALUE pairs that override all other env var sources.
 // ZFC-compliant: no state file, tmux session is source of truth.
 func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []string) error {
-	t := tmux.NewTmux()
+	t := m.backend()
 	sessionID := m.SessionName()
 
 	if foreground {
@@ -236,6 +236,11 @@ func (m *Manager) townRoot() string {
 	return townRoot
 }
 
+// backend returns the session backend for the witness's town.
+func (m *Manager) backend() session.Backend {
+	return session.NewBackend(m.townRoot())
+}
+
 func roleConfigEnvVars(roleConfig *beads.RoleConfig, townRoot, rigName string) map[string]string {
 	if roleConfig == nil || len(roleConfig.EnvVars) == 0 {
 		return nil
@@ -269,7 +274,7 @@ func buildWitnessStartCommand(rigPath, rigName, townRoot, agentOverride string,
 // Stop stops the witness.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Stop() error {
-	t := tmux.NewTmux()
+	t := m.backend()
 	sessionID := m.SessionName()
 
 	// Check if tmux session exists
@@ -52,58 +52,69 @@ const (
 
 // PolecatDonePayload contains parsed data from a POLECAT_DONE message.
 type PolecatDonePayload struct {
-	PolecatName string
-	Exit        string // COMPLETED, ESCALATED, DEFERRED, PHASE_COMPLETE
-	IssueID     string
-	MRID        string
-	Branch      string
-	Gate        string // Gate ID when Exit is PHASE_COMPLETE
+	PolecatName string `json:"polecat"`
+	Exit        string `json:"exit_type"` // COMPLETED, ESCALATED, DEFERRED, PHASE_COMPLETE
+	IssueID     string `json:"issue,omitempty"`
+	MRID        string `json:"mr,omitempty"`
+	Branch      string `json:"branch"`
+	Gate        string `json:"gate,omitempty"` // Gate ID when Exit is PHASE_COMPLETE
+
+	// Convoy ownership, so the witness can skip merge flow registration.
+	ConvoyID      string `json:"convoy_id,omitempty"`
+	ConvoyOwned   bool   `json:"convoy_owned,omitempty"`
+	MergeStrategy string `json:"merge_strategy,omitempty"`
+
+	// Errors lists non-fatal errors hit by gt done, "; "-separated.
+	Errors string `json:"errors,omitempty"`
 }
 
 // HelpPayload contains parsed data from a HELP message.
 type HelpPayload struct {
-	Topic       string
-	Agent       string
-	IssueID     string
-	Problem     string
-	Tried       string
-	RequestedAt time.Time
+	Topic       string    `json:"topic"`
+	Agent       string    `json:"agent,omitempty"`
+	IssueID     string    `json:"issue,omitempty"`
+	Problem     string    `json:"problem,omitempty"`
+	Tried       string    `json:"tried,omitempty"`
+	RequestedAt time.Time `json:"requested_at"`
 }
 
 // MergedPayload contains parsed data from a MERGED message.
 type MergedPayload struct {
-	PolecatName string
-	Branch      string
-	IssueID     string
-	MergedAt    time.Time
+	PolecatName string    `json:"polecat"`
+	Rig         string    `json:"rig"`
+	Branch      string    `json:"branch"`
+	IssueID     string    `json:"issue,omitempty"`
+	MergedAt    time.Time `json:"merged_at"`
 }
 
 // MergeReadyPayload contains parsed data from a MERGE_READY message.
 // This is sent by Witness to Refinery when a polecat completes work with a pending MR.
 type MergeReadyPayload struct {
-	PolecatName string
-	Branch      string
-	IssueID     string
-	MRID        string
-	ReadyAt     time.Time
+	PolecatName string    `json:"polecat"`
+	Rig         string    `json:"rig"`
+	Branch      string    `json:"branch"`
+	IssueID     string    `json:"issue,omitempty"`
+	MRID        string    `json:"mr,omitempty"`
+	ReadyAt     time.Time `json:"timestamp"`
 }
 
 // MergeFailedPayload contains parsed data from a MERGE_FAILED message.
 type MergeFailedPayload struct {
-	PolecatName string
-	Branch      string
-	IssueID     string
-	FailureType string // "build", "test", "lint", etc.
-	Error       string
-	FailedAt    time.Time
+	PolecatName string    `json:"polecat"`
+	Rig         string    `json:"rig"`
+	Branch      string    `json:"branch"`
+	IssueID     string    `json:"issue,omitempty"`
+	FailureType string    `json:"failure_type"` // "build", "test", "lint", etc.
+	Error       string    `json:"error"`
+	FailedAt    time.Time `json:"failed_at"`
 }
 
 // SwarmStartPayload contains parsed data from a SWARM_START message.
 type SwarmStartPayload struct {
-	SwarmID   string
-	BeadIDs   []string
-	Total     int
-	StartedAt time.Time
+	SwarmID   string    `json:"swarm_id"`
+	BeadIDs   []string  `json:"beads"`
+	Total     int       `json:"total"`
+	StartedAt time.Time `json:"started_at"`
 }
 
 // ClassifyMessage determines the protocol type from a message subject.
@@ -131,6 +142,7 @@ func ClassifyMessage(subject string) ProtocolType {
 }
 
 // ParsePolecatDone extracts payload from a POLECAT_DONE message.
+// A Gt-Envelope line in the body takes precedence over the legacy format.
 // Subject format: POLECAT_DONE <polecat-name>
 // Body format:
 //
@@ -139,7 +151,19 @@ func ClassifyMessage(subject string) ProtocolType {
 //	MR: <mr-id>
 //	Gate: <gate-id>
 //	Branch: <branch>
+//	ConvoyID: <convoy-id>
+//	ConvoyOwned: true
+//	MergeStrategy: <strategy>
+//	Errors: <errors>
 func ParsePolecatDone(subject, body string) (*PolecatDonePayload, error) {
+	var env PolecatDonePayload
+	if ok, err := decodeEnvelope(body, ProtoPolecatDone, &env); ok {
+		if err != nil {
+			return nil, err
+		}
+		return &env, requirePolecat(ProtoPolecatDone, env.PolecatName)
+	}
+
 	matches := PatternPolecatDone.FindStringSubmatch(subject)
 	if len(matches) < 2 {
 		return nil, fmt.Errorf("invalid POLECAT_DONE subject: %s", subject)
@@ -162,6 +186,14 @@ func ParsePolecatDone(subject, body string) (*PolecatDonePayload, error) {
 			payload.Gate = strings.TrimSpace(strings.TrimPrefix(line, "Gate:"))
 		} else if strings.HasPrefix(line, "Branch:") {
 			payload.Branch = strings.TrimSpace(strings.TrimPrefix(line, "Branch:"))
+		} else if strings.HasPrefix(line, "ConvoyID:") {
+			payload.ConvoyID = strings.TrimSpace(strings.TrimPrefix(line, "ConvoyID:"))
+		} else if strings.HasPrefix(line, "ConvoyOwned:") {
+			payload.ConvoyOwned = strings.TrimSpace(strings.TrimPrefix(line, "ConvoyOwned:")) == "true"
+		} else if strings.HasPrefix(line, "MergeStrategy:") {
+			payload.MergeStrategy = strings.TrimSpace(strings.TrimPrefix(line, "MergeStrategy:"))
+		} else if strings.HasPrefix(line, "Errors:") {
+			payload.Errors = strings.TrimSpace(strings.TrimPrefix(line, "Errors:"))
 		}
 	}
 
@@ -177,6 +209,17 @@ func ParsePolecatDone(subject, body string) (*PolecatDonePayload, error) {
 //	Problem: <description>
 //	Tried: <what was attempted>
 func ParseHelp(subject, body string) (*HelpPayload, error) {
+	var env HelpPayload
+	if ok, err := decodeEnvelope(body, ProtoHelp, &env); ok {
+		if err != nil {
+			return nil, err
+		}
+		if env.Topic == "" {
+			return nil, fmt.Errorf("invalid HELP envelope: missing topic")
+		}
+		return &env, nil
+	}
+
 	matches := PatternHelp.FindStringSubmatch(subject)
 	if len(matches) < 2 {
 		return nil, fmt.Errorf("invalid HELP subject: %s", subject)
@@ -210,8 +253,17 @@ func ParseHelp(subject, body string) (*HelpPayload, error) {
 //
 //	Branch: <branch>
 //	Issue: <issue-id>
+//	Rig: <rig>
 //	Merged-At: <timestamp>
 func ParseMerged(subject, body string) (*MergedPayload, error) {
+	var env MergedPayload
+	if ok, err := decodeEnvelope(body, ProtoMerged, &env); ok {
+		if err != nil {
+			return nil, err
+		}
+		return &env, requirePolecat(ProtoMerged, env.PolecatName)
+	}
+
 	matches := PatternMerged.FindStringSubmatch(subject)
 	if len(matches) < 2 {
 		return nil, fmt.Errorf("invalid MERGED subject: %s", subject)
@@ -228,6 +280,8 @@ func ParseMerged(subject, body string) (*MergedPayload, error) {
 			payload.Branch = strings.TrimSpace(strings.TrimPrefix(line, "Branch:"))
 		} else if strings.HasPrefix(line, "Issue:") {
 			payload.IssueID = strings.TrimSpace(strings.TrimPrefix(line, "Issue:"))
+		} else if strings.HasPrefix(line, "Rig:") {
+			payload.Rig = strings.TrimSpace(strings.TrimPrefix(line, "Rig:"))
 		} else if strings.HasPrefix(line, "Merged-At:") {
 			ts := strings.TrimSpace(strings.TrimPrefix(line, "Merged-At:"))
 			if t, err := time.Parse(time.RFC3339, ts); err == nil {
@@ -245,9 +299,18 @@ func ParseMerged(subject, body string) (*MergedPayload, error) {
 //
 //	Branch: <branch>
 //	Issue: <issue-id>
+//	Rig: <rig>
 //	FailureType: <type>
 //	Error: <error-message>
 func ParseMergeFailed(subject, body string) (*MergeFailedPayload, error) {
+	var env MergeFailedPayload
+	if ok, err := decodeEnvelope(body, ProtoMergeFailed, &env); ok {
+		if err != nil {
+			return nil, err
+		}
+		return &env, requirePolecat(ProtoMergeFailed, env.PolecatName)
+	}
+
 	matches := PatternMergeFailed.FindStringSubmatch(subject)
 	if len(matches) < 2 {
 		return nil, fmt.Errorf("invalid MERGE_FAILED subject: %s", subject)
@@ -266,8 +329,10 @@ func ParseMergeFailed(subject, body string) (*MergeFailedPayload, error) {
 			payload.Branch = strings.TrimSpace(strings.TrimPrefix(line, "Branch:"))
 		case strings.HasPrefix(line, "Issue:"):
 			payload.IssueID = strings.TrimSpace(strings.TrimPrefix(line, "Issue:"))
-		case strings.HasPrefix(line, "FailureType:"):
-			payload.FailureType = strings.TrimSpace(strings.TrimPrefix(line, "FailureType:"))
+		case strings.HasPrefix(line, "Rig:"):
+			payload.Rig = strings.TrimSpace(strings.TrimPrefix(line, "Rig:"))
+		case strings.HasPrefix(line, "FailureType:"), strings.HasPrefix(line, "Failure-Type:"):
+			payload.FailureType = strings.TrimSpace(line[strings.Index(line, ":")+1:])
 		case strings.HasPrefix(line, "Error:"):
 			payload.Error = strings.TrimSpace(strings.TrimPrefix(line, "Error:"))
 		}
@@ -285,6 +350,14 @@ func ParseMergeFailed(subject, body string) (*MergeFailedPayload, error) {
 //	MR: <mr-id>
 //	Verified: clean git state
 func ParseMergeReady(subject, body string) (*MergeReadyPayload, error) {
+	var env MergeReadyPayload
+	if ok, err := decodeEnvelope(body, ProtoMergeReady, &env); ok {
+		if err != nil {
+			return nil, err
+		}
+		return &env, requirePolecat(ProtoMergeReady, env.PolecatName)
+	}
+
 	matches := PatternMergeReady.FindStringSubmatch(subject)
 	if len(matches) < 2 {
 		return nil, fmt.Errorf("invalid MERGE_READY subject: %s", subject)
@@ -305,6 +378,8 @@ func ParseMergeReady(subject, body string) (*MergeReadyPayload, error) {
 			payload.IssueID = strings.TrimSpace(strings.TrimPrefix(line, "Issue:"))
 		case strings.HasPrefix(line, "MR:"):
 			payload.MRID = strings.TrimSpace(strings.TrimPrefix(line, "MR:"))
+		case strings.HasPrefix(line, "Rig:"):
+			payload.Rig = strings.TrimSpace(strings.TrimPrefix(line, "Rig:"))
 		}
 	}
 
@@ -319,6 +394,14 @@ func ParseMergeReady(subject, body string) (*MergeReadyPayload, error) {
 //	Beads: <bead-a>, <bead-b>, ...
 //	Total: <count>
 func ParseSwarmStart(body string) (*SwarmStartPayload, error) {
+	var env SwarmStartPayload
+	if ok, err := decodeEnvelope(body, ProtoSwarmStart, &env); ok {
+		if err != nil {
+			return nil, err
+		}
+		return &env, nil
+	}
+
 	payload := &SwarmStartPayload{
 		StartedAt: time.Now(),
 	}